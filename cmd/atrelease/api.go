@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/actions"
+)
+
+// API command flags
+var apiDryRun bool
+
+// apiCmd represents the api command
+var apiCmd = &cobra.Command{
+	Use:   "api [directory]",
+	Short: "Update the Go API baseline in api/",
+	Long: `Regenerate the Go API baseline in api/, one file per package listing
+its exported identifiers, e.g.
+
+  pkg github.com/org/mod/pkg/x, func Parse(string) (*Doc, error)
+
+Commit api/ with the code, so reviews show every API change. The
+api-baseline check in validate fails when api/ is out of date, and warns
+when the API removed or changed anything since the latest tag without a
+breaking changelog entry.
+
+Examples:
+  atrelease api              # Create or update api/
+  atrelease api --dry-run    # Show the API changes without writing
+  atrelease api -v           # List the changed lines`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAPI,
+}
+
+func init() {
+	apiCmd.Flags().BoolVar(&apiDryRun, "dry-run", false, "Show what would be done without making changes")
+
+	rootCmd.AddCommand(apiCmd)
+}
+
+func runAPI(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
+		os.Exit(1)
+	}
+
+	fmt.Println("=== API Baseline ===")
+	fmt.Println()
+
+	result := actions.Run(&actions.APIAction{}, dir, actions.Options{
+		DryRun:  apiDryRun,
+		Verbose: cfgVerbose,
+	})
+
+	if result.Output != "" {
+		fmt.Println(result.Output)
+	}
+
+	if !result.Success {
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", result.Error)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// Backport command flags
+var (
+	backportTo      string
+	backportBranch  string
+	backportNoCheck bool
+	backportNoPR    bool
+	backportDryRun  bool
+)
+
+// backportCmd represents the backport command
+var backportCmd = &cobra.Command{
+	Use:   "backport <commit>... --to <branch>",
+	Short: "Cherry-pick commits onto a release branch and open a PR",
+	Long: `Backport one or more commits onto a release branch.
+
+Steps:
+  1. Create a backport branch from the target branch on the remote
+  2. Cherry-pick each commit (with -x), stopping on conflicts
+  3. Run validation checks via releasekit
+  4. Push the branch and open a pull request against the target (requires gh)
+
+On a conflict, the cherry-pick is aborted and the backport branch removed,
+and the conflicting files are listed. However the backport ends, the
+branch it started from is checked out again.
+
+Examples:
+  atrelease backport abc1234 --to release-1.x
+  atrelease backport abc1234 def5678 --to release-1.x --no-pr
+  atrelease backport abc1234 --to release-1.x --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runBackport,
+}
+
+func init() {
+	backportCmd.Flags().StringVar(&backportTo, "to", "", "Target release branch (required)")
+	backportCmd.Flags().StringVar(&backportBranch, "branch", "", "Backport branch name (default: backport/<commit>-to-<target>)")
+	backportCmd.Flags().BoolVar(&backportNoCheck, "no-check", false, "Skip validation checks")
+	backportCmd.Flags().BoolVar(&backportNoPR, "no-pr", false, "Push the branch without opening a PR")
+	backportCmd.Flags().BoolVar(&backportDryRun, "dry-run", false, "Show what would be done without making changes")
+	_ = backportCmd.MarkFlagRequired("to")
+
+	rootCmd.AddCommand(backportCmd)
+}
+
+func runBackport(cmd *cobra.Command, args []string) {
+	dir := "."
+	g := git.New(dir)
+
+	// restoreBranch checks out the branch the backport started from. fail
+	// calls it too, since os.Exit skips deferred calls.
+	restoreBranch := func() {}
+	fail := func(format string, a ...any) {
+		restoreBranch()
+		fmt.Fprintf(os.Stderr, "Error: "+format+"\n", a...)
+		os.Exit(1)
+	}
+
+	dirty, err := g.IsDirty()
+	if err != nil {
+		fail("%v", err)
+	}
+	if dirty {
+		fail("working directory has uncommitted changes")
+	}
+
+	commits := make([]string, len(args))
+	for i, ref := range args {
+		if commits[i], err = g.ResolveCommit(ref); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	fmt.Println("=== Backport ===")
+	fmt.Println()
+
+	// Prefer the remote branch so the backport starts from the published state
+	if err := g.Fetch(); err != nil && cfgVerbose {
+		fmt.Printf("Warning: fetch failed: %v\n", err)
+	}
+	base := g.Remote + "/" + backportTo
+	if !g.RefExists(base) {
+		base = backportTo
+		if !g.RefExists(base) {
+			fail("target branch %s not found", backportTo)
+		}
+	}
+
+	branch := backportBranch
+	if branch == "" {
+		branch = fmt.Sprintf("backport/%s-to-%s", shortSHA(commits[0]), strings.ReplaceAll(backportTo, "/", "-"))
+	}
+
+	subjects := make([]string, len(commits))
+	for i, c := range commits {
+		subjects[i], _ = g.CommitSubject(c)
+	}
+
+	if backportDryRun {
+		fmt.Printf("[Dry run] Would create %s from %s\n", branch, base)
+		for i, c := range commits {
+			fmt.Printf("[Dry run] Would cherry-pick %s %s\n", shortSHA(c), subjects[i])
+		}
+		if !backportNoCheck {
+			fmt.Println("[Dry run] Would run validation checks")
+		}
+		fmt.Printf("[Dry run] Would push %s", branch)
+		if !backportNoPR {
+			fmt.Printf(" and open a PR against %s", backportTo)
+		}
+		fmt.Println()
+		return
+	}
+
+	original, err := g.CurrentBranch()
+	if err != nil {
+		fail("%v", err)
+	}
+
+	if err := g.CreateBranch(branch, base); err != nil {
+		fail("%v", err)
+	}
+	restoreBranch = func() { _ = g.Checkout(original) }
+	defer restoreBranch()
+	fmt.Printf("Created %s from %s\n", branch, base)
+
+	for i, c := range commits {
+		if err := g.CherryPick(c); err != nil {
+			files, _ := g.ConflictedFiles()
+			_ = g.AbortCherryPick()
+			restoreBranch()
+			_ = g.DeleteBranch(branch)
+			if len(files) > 0 {
+				fmt.Fprintf(os.Stderr, "Conflicts cherry-picking %s onto %s:\n", shortSHA(c), backportTo)
+				for _, f := range files {
+					fmt.Fprintf(os.Stderr, "  %s\n", f)
+				}
+				fail("backport aborted; resolve manually with: git checkout -b %s %s && git cherry-pick -x %s", branch, base, c)
+			}
+			fail("%v", err)
+		}
+		fmt.Printf("Cherry-picked %s %s\n", shortSHA(c), subjects[i])
+	}
+
+	if !backportNoCheck {
+		if !checks.ReleasekitAvailable() {
+			fmt.Println("Warning: releasekit not found; skipping validation checks")
+		} else {
+			fmt.Println()
+			fmt.Println("Running checks via releasekit...")
+			cfg, err := config.Load(dir)
+			if err != nil {
+				fail("%v", err)
+			}
+			cfg.Verbose = cfg.Verbose || cfgVerbose
+			opts, err := checks.ResolveOptions(cfg, nil, checks.OptionFlags{})
+			if err != nil {
+				fail("%v", err)
+			}
+			checks.SetCommandPolicy(checks.ConfigCommandPolicy(cfg))
+			restore := proc.SetEnv(opts.Env)
+			results, err := checks.RunReleasekit(dir, opts)
+			restore()
+			if err != nil {
+				fail("running releasekit: %v", err)
+			}
+			_, failed, _, _ := checks.PrintResults(results, cfgVerbose)
+			if failed > 0 {
+				fail("%d checks failed on %s; fix and push manually", failed, branch)
+			}
+		}
+	}
+
+	if err := g.PushWithUpstream(); err != nil {
+		fail("%v", err)
+	}
+	fmt.Printf("\nPushed %s\n", branch)
+
+	if backportNoPR {
+		return
+	}
+
+	title := fmt.Sprintf("[%s] %s", backportTo, subjects[0])
+	if len(commits) > 1 {
+		title = fmt.Sprintf("[%s] Backport %d commits", backportTo, len(commits))
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Backport to `%s`:\n\n", backportTo)
+	for i, c := range commits {
+		fmt.Fprintf(&body, "- %s %s\n", c, subjects[i])
+	}
+	url, err := g.CreatePR(backportTo, branch, title, body.String())
+	if err != nil {
+		fail("%v", err)
+	}
+	fmt.Printf("Opened PR: %s\n", url)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -11,8 +11,9 @@ import (
 
 // Changelog command flags
 var (
-	changelogSince  string
-	changelogDryRun bool
+	changelogSince   string
+	changelogDryRun  bool
+	changelogVersion string
 )
 
 // changelogCmd represents the changelog command
@@ -24,13 +25,18 @@ var changelogCmd = &cobra.Command{
 This command parses git commits since the specified tag (or latest tag)
 and can regenerate CHANGELOG.md from CHANGELOG.json.
 
+Repositories without CHANGELOG.json that keep CHANGELOG.md in Keep a
+Changelog format are supported directly: the [Unreleased] changes are
+listed and, with --version, promoted to a release heading.
+
 Requires schangelog to be installed:
   go install github.com/grokify/schangelog/cmd/schangelog@latest
 
 Examples:
   atrelease changelog                    # Parse commits since latest tag
   atrelease changelog --since=v0.2.0     # Parse commits since v0.2.0
-  atrelease changelog --dry-run          # Show what would be done`,
+  atrelease changelog --dry-run          # Show what would be done
+  atrelease changelog --version=v1.2.0   # Promote [Unreleased] (Keep a Changelog)`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runChangelog,
 }
@@ -38,6 +44,7 @@ Examples:
 func init() {
 	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Parse commits since this tag (default: latest tag)")
 	changelogCmd.Flags().BoolVar(&changelogDryRun, "dry-run", false, "Show what would be done without making changes")
+	changelogCmd.Flags().StringVar(&changelogVersion, "version", "", "Release version to promote [Unreleased] to (Keep a Changelog CHANGELOG.md only)")
 
 	rootCmd.AddCommand(changelogCmd)
 }
@@ -62,10 +69,11 @@ func runChangelog(cmd *cobra.Command, args []string) {
 	opts := actions.Options{
 		Since:   changelogSince,
 		DryRun:  changelogDryRun,
+		Version: changelogVersion,
 		Verbose: cfgVerbose,
 	}
 
-	result := action.Run(dir, opts)
+	result := actions.Run(action, dir, opts)
 
 	if result.Output != "" {
 		fmt.Println(result.Output)
@@ -3,40 +3,62 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/plexusone/agent-team-release/pkg/checks"
 	"github.com/plexusone/agent-team-release/pkg/config"
 	"github.com/plexusone/agent-team-release/pkg/detect"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/runlog"
 	"github.com/plexusone/assistantkit/requirements"
 )
 
 // Check command flags
 var (
-	noTest     bool
-	noLint     bool
-	noFormat   bool
-	coverage   bool
-	goNoGoMode bool
+	noTest         bool
+	noLint         bool
+	noFormat       bool
+	coverage       bool
+	coverageDiff   bool
+	goNoGoMode     bool
+	checkStash     bool
+	checkRecurse   bool
+	checkStrict    bool
+	checkContainer bool
+	checkLocal     bool
+	assertClean    bool
 )
 
 // checkCmd represents the check command
 var checkCmd = &cobra.Command{
-	Use:   "check [directory]",
+	Use:   "check [directory]...",
 	Short: "Run validation checks",
 	Long: `Run validation checks for all detected languages in the repository.
 
 Checks include build, test, lint, and format verification for each
 detected language. Results are summarized with pass/fail status.
 
+Several directories can be checked in one run, for example the projects of a
+meta-repo. Each is checked with its own .releaseagent.yaml, and a combined
+summary grouped by directory follows. With --recursive, every directory
+under the arguments where a language is detected is checked independently.
+
 Examples:
   atrelease check              # Check current directory
   atrelease check /path/to/repo
+  atrelease check svc/api svc/web   # Check several projects
+  atrelease check --recursive       # Check every detected project
   atrelease check --verbose    # Show detailed output
-  atrelease check --no-test    # Skip tests`,
-	Args: cobra.MaximumNArgs(1),
-	Run:  runCheck,
+  atrelease check --no-test    # Skip tests
+  atrelease check --coverage-diff  # Coverage ratchet vs. merge base
+  atrelease check --stash      # Check only staged changes
+  atrelease check --strict     # Fail on warnings too
+  atrelease check --container  # Run checks in configured Docker images
+  atrelease check --local      # Run remote.checks on this machine
+  atrelease check --assert-clean  # Fail if checks modify the working tree`,
+	Run: runCheck,
 }
 
 func init() {
@@ -44,33 +66,29 @@ func init() {
 	checkCmd.Flags().BoolVar(&noLint, "no-lint", false, "Skip linting")
 	checkCmd.Flags().BoolVar(&noFormat, "no-format", false, "Skip format checks")
 	checkCmd.Flags().BoolVar(&coverage, "coverage", false, "Show coverage (Go only)")
+	checkCmd.Flags().BoolVar(&coverageDiff, "coverage-diff", false, "Compare coverage of changed Go packages against the merge base")
 	checkCmd.Flags().BoolVar(&goNoGoMode, "go-no-go", false, "Display NASA-style Go/No-Go validation report")
+	checkCmd.Flags().BoolVar(&checkStash, "stash", false, "Stash unstaged and untracked changes while checks run and restore them afterwards")
+	checkCmd.Flags().BoolVarP(&checkRecurse, "recursive", "r", false, "Check each directory where a language is detected independently")
+	checkCmd.Flags().BoolVar(&checkStrict, "strict", false, "Treat warnings as failures")
+	checkCmd.Flags().BoolVar(&checkContainer, "container", false, "Run each language's checks in its configured container image")
+	checkCmd.Flags().BoolVar(&checkLocal, "local", false, "Run the heavy checks in remote.checks on this machine")
+	checkCmd.Flags().BoolVar(&assertClean, "assert-clean", false, "Fail if the checks leave the working tree modified")
 
 	rootCmd.AddCommand(checkCmd)
 }
 
 func runCheck(cmd *cobra.Command, args []string) {
-	// Get directory to check
-	dir := "."
-	if len(args) > 0 {
-		dir = args[0]
-	}
-
-	// Make sure directory exists
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
-		os.Exit(1)
-	}
-
-	// Load configuration
-	cfg, err := config.Load(dir)
+	dirs, err := resolveDirs(args, checkRecurse)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Override config with flags
-	if cfgVerbose {
-		cfg.Verbose = true
+	// With --json, progress goes to stderr so stdout carries only the report
+	stdout := os.Stdout
+	if cfgJSON {
+		os.Stdout = os.Stderr
 	}
 
 	// Check if releasekit is available, prompt for installation if not
@@ -82,75 +100,381 @@ func runCheck(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	flags := checkOptionFlags(cmd)
+	var summaries []checkSummary
+	for i, t := range dirs {
+		if i > 0 {
+			fmt.Println()
+		}
+		cfg := loadConfig(t)
+		checks.SetReportConfig(cfg.Report)
+		title := "Pre-push Checks"
+		if len(dirs) > 1 {
+			title += ": " + t.Path
+		}
+
+		start := time.Now()
+		summary := checkSummary{Dir: t.Path}
+		run := func() int {
+			// Checks are read-only: the tree is compared before and after
+			g := git.New(t.Path)
+			before, snapErr := g.Snapshot()
+			summary = checkDir(t.Path, title, &cfg, flags)
+			if snapErr != nil {
+				if assertClean {
+					fmt.Fprintf(os.Stderr, "Warning: cannot verify the working tree is unchanged: %v\n", snapErr)
+				}
+				return summary.Code
+			}
+			verifyClean(g, before, &summary)
+			return summary.Code
+		}
+		if checkStash || cfg.Stash {
+			summary.Code = withStashedChanges(t.Path, run)
+		} else {
+			run()
+		}
+		summary.Success = summary.Code == 0
+		summaries = append(summaries, summary)
+		if cfg.History {
+			if err := recordRun(summary, time.Since(start)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: recording run history: %v\n", err)
+			}
+		}
+	}
+
+	if cfgJSON {
+		os.Stdout = stdout
+		report := newCheckReport(summaries)
+		if err := writeStructured(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !report.Success {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(summaries) == 1 {
+		os.Exit(summaries[0].Code)
+	}
+	os.Exit(printCheckSummaries(summaries))
+}
+
+// verifyClean compares the working tree with before and reports files the
+// checks modified. With --assert-clean, the run fails.
+func verifyClean(g *git.Git, before *git.Snapshot, summary *checkSummary) {
+	after, err := g.Snapshot()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot verify the working tree is unchanged: %v\n", err)
+		return
+	}
+	summary.Modified = before.Changed(after)
+	if len(summary.Modified) == 0 {
+		return
+	}
+
+	level := "Warning"
+	if assertClean {
+		level = "Error"
+		summary.Code = 1
+	}
+	fmt.Fprintf(os.Stderr, "%s: checks modified the working tree:\n", level)
+	for _, file := range summary.Modified {
+		fmt.Fprintf(os.Stderr, "  %s\n", file)
+	}
+}
+
+// checkCounts are result counts, as printed in summaries.
+type checkCounts struct {
+	Passed   int `json:"passed" toon:"passed"`
+	Failed   int `json:"failed" toon:"failed"`
+	Skipped  int `json:"skipped" toon:"skipped"`
+	Warnings int `json:"warnings" toon:"warnings"`
+}
+
+// format formats the counts. It isn't String, which the TOON encoder would
+// use in place of the fields.
+func (c checkCounts) format() string {
+	return checks.FormatCounts(c.Passed, c.Failed, c.Skipped, c.Warnings)
+}
+
+func (c *checkCounts) add(o checkCounts) {
+	c.Passed += o.Passed
+	c.Failed += o.Failed
+	c.Skipped += o.Skipped
+	c.Warnings += o.Warnings
+}
+
+// checkSummary is the outcome of checking one directory.
+type checkSummary struct {
+	Dir     string       `json:"dir" toon:"dir"`
+	Success bool         `json:"success" toon:"success"`
+	Error   string       `json:"error,omitempty" toon:"error,omitempty"`
+	Counts  checkCounts  `json:"summary" toon:"summary"`
+	Groups  []checkGroup `json:"groups" toon:"groups"`
+
+	Detections   []detect.Detection `json:"detections,omitempty" toon:"detections,omitempty"`
+	DetectCached bool               `json:"detect_cached,omitempty" toon:"detect_cached,omitempty"` // Detections came from the cache
+	Modified     []string           `json:"modified,omitempty" toon:"modified,omitempty"`           // Files the checks changed in the working tree
+	Code         int                `json:"-" toon:"-"`                                             // Exit code: 0 if the checks passed
+}
+
+// checkGroup is the results for one detection path and language.
+type checkGroup struct {
+	Path     string        `json:"path" toon:"path"`
+	Language string        `json:"language,omitempty" toon:"language,omitempty"`
+	Counts   checkCounts   `json:"summary" toon:"summary"`
+	Results  []checkResult `json:"results" toon:"results"`
+}
+
+// checkResult is a single check in the structured report.
+type checkResult struct {
+	ID          string `json:"id" toon:"id"`
+	Name        string `json:"name" toon:"name"`
+	Status      string `json:"status" toon:"status"` // "passed", "failed", "skipped", or "warning"
+	Output      string `json:"output,omitempty" toon:"output,omitempty"`
+	Error       string `json:"error,omitempty" toon:"error,omitempty"`
+	Reason      string `json:"reason,omitempty" toon:"reason,omitempty"`
+	Remediation string `json:"remediation,omitempty" toon:"remediation,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty" toon:"duration_ms,omitempty"`
+
+	Tests    *checks.TestSummary `json:"tests,omitempty" toon:"tests,omitempty"` // Checks that ran go test -json
+	Findings []checks.Finding    `json:"findings,omitempty" toon:"findings,omitempty"`
+}
+
+// checkReport is the structured output of check with --json.
+type checkReport struct {
+	Success     bool           `json:"success" toon:"success"`
+	Counts      checkCounts    `json:"summary" toon:"summary"`
+	Directories []checkSummary `json:"directories" toon:"directories"`
+}
+
+func newCheckReport(summaries []checkSummary) checkReport {
+	report := checkReport{Success: true, Directories: summaries}
+	for _, s := range summaries {
+		report.Success = report.Success && s.Success
+		report.Counts.add(s.Counts)
+	}
+	return report
+}
+
+// newCheckGroups converts grouped results for the structured report.
+func newCheckGroups(groups []checks.ResultGroup) []checkGroup {
+	out := make([]checkGroup, 0, len(groups))
+	for _, g := range groups {
+		cg := checkGroup{
+			Path:     g.Path,
+			Language: g.Language,
+			Counts:   checkCounts{g.Passed, g.Failed, g.Skipped, g.Warnings},
+		}
+		for _, r := range g.Results {
+			cr := checkResult{
+				ID:          checks.ResultID(r),
+				Name:        r.Name,
+				Status:      string(r.Severity()),
+				Output:      r.Output,
+				Reason:      r.Reason,
+				Remediation: r.ShownRemediation(),
+				DurationMs:  r.Duration.Milliseconds(),
+				Tests:       r.Tests,
+				Findings:    r.Findings,
+			}
+			if r.Error != nil {
+				cr.Error = r.Error.Error()
+			}
+			cg.Results = append(cg.Results, cr)
+		}
+		out = append(out, cg)
+	}
+	return out
+}
+
+// checkOptionFlags returns the check options set by flags of cmd, which
+// take precedence over the environment and config. --no-test=false turns
+// tests back on when ATRELEASE_TEST=0 turned them off.
+func checkOptionFlags(cmd *cobra.Command) checks.OptionFlags {
+	var flags checks.OptionFlags
+	set := func(name string, v bool) *bool {
+		if !cmd.Flags().Changed(name) {
+			return nil
+		}
+		return &v
+	}
+	flags.Test = set("no-test", !noTest)
+	flags.Lint = set("no-lint", !noLint)
+	flags.Format = set("no-format", !noFormat)
+	flags.Coverage = set("coverage", coverage)
+	return flags
+}
+
+// checkDir runs the checks in dir, prints the report under title, and
+// returns the grouped results and exit code. flags override the check
+// options in cfg.
+func checkDir(dir, title string, cfg *config.Config, flags checks.OptionFlags) checkSummary {
+	summary := checkSummary{Dir: dir, Code: 1}
+
 	// Detect languages
-	fmt.Println("=== Pre-push Checks ===")
+	fmt.Printf("=== %s ===\n", title)
 	fmt.Println()
 	fmt.Println("Detecting languages...")
 
-	detections, err := detect.Detect(dir)
+	detections, cached, err := detect.DetectCached(dir, detectOptions(*cfg))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error detecting languages: %v\n", err)
-		os.Exit(1)
+		summary.Error = fmt.Sprintf("detecting languages: %v", err)
+		return summary
 	}
 
 	if len(detections) == 0 {
 		fmt.Println("No supported languages detected.")
-		os.Exit(0)
+		summary.Code = 0
+		return summary
 	}
+	summary.DetectCached = cached
 
-	// Print detected languages
+	// Print detected languages, with toolchain versions when verbose
+	if cfg.Verbose || cfgJSON {
+		detect.ResolveToolchains(detections)
+	}
+	summary.Detections = detections
 	for _, d := range detections {
-		fmt.Printf("  Found: %s in %s\n", d.Language, d.Path)
+		if cfg.Verbose {
+			fmt.Printf("  Found: %s in %s (%s)\n", d.Language, d.Path, toolchainLabel(d))
+		} else {
+			fmt.Printf("  Found: %s in %s\n", d.Language, d.Path)
+		}
 	}
 	fmt.Println()
 
-	// Build options from flags and config
-	opts := checks.Options{
-		Test:    !noTest,
-		Lint:    !noLint,
-		Format:  !noFormat,
-		Coverage: coverage,
-		Verbose: cfg.Verbose,
+	engine, err := checks.NewEngine(*cfg, flags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		summary.Error = err.Error()
+		return summary
+	}
+	engine.Config.Strict = cfg.Strict || checkStrict
+	engine.Container = checkContainer
+	engine.Local = checkLocal
+	engine.ChangedCode = true
+	engine.CoverageDiff = coverageDiff
+	engine.Log = func(format string, args ...any) {
+		fmt.Printf(format+"\n", args...)
 	}
+	engine.Heartbeat = true
 
-	// Run releasekit validate (auto-detects languages)
-	fmt.Println("Running checks via releasekit...")
-	allResults, err := checks.RunReleasekit(dir, opts)
+	allResults, err := engine.Run(dir, detections)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error running releasekit: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		summary.Error = err.Error()
+		return summary
 	}
 	fmt.Println()
 
+	summary.Groups = newCheckGroups(checks.GroupResults(allResults))
+	for _, g := range summary.Groups {
+		summary.Counts.add(g.Counts)
+	}
+	if cfgJSON {
+		if summary.Counts.Failed == 0 {
+			summary.Code = 0
+		}
+		return summary
+	}
+
 	// Print summary
 	if goNoGoMode {
 		// NASA-style Go/No-Go report
 		allGo := checks.PrintGoNoGoReport(allResults, cfg.Verbose)
 		if !allGo {
-			os.Exit(1)
+			return summary
 		}
 	} else {
-		// Standard report
+		// Standard report, grouped by detection path and language
 		fmt.Println("=== Summary ===")
-		passed, failed, skipped, warnings := checks.PrintResults(allResults, cfg.Verbose)
+		checks.PrintGroupedResults(allResults, cfg.Verbose)
 		fmt.Println()
-		if warnings > 0 {
-			fmt.Printf("Passed: %d, Failed: %d, Skipped: %d, Warnings: %d\n", passed, failed, skipped, warnings)
-		} else {
-			fmt.Printf("Passed: %d, Failed: %d, Skipped: %d\n", passed, failed, skipped)
-		}
+		fmt.Println(summary.Counts.format())
 
-		if failed > 0 {
+		if summary.Counts.Failed > 0 {
 			fmt.Println()
 			fmt.Println("Pre-push checks failed!")
-			os.Exit(1)
+			return summary
 		}
 
 		fmt.Println()
-		if warnings > 0 {
+		if summary.Counts.Warnings > 0 {
 			fmt.Println("Pre-push checks passed with warnings.")
 		} else {
 			fmt.Println("All pre-push checks passed!")
 		}
 	}
+	summary.Code = 0
+	return summary
+}
+
+// recordRun appends a check run to the history of its directory.
+func recordRun(s checkSummary, elapsed time.Duration) error {
+	run := runlog.Run{
+		Time:         time.Now().UTC(),
+		Command:      "check",
+		DurationMs:   elapsed.Milliseconds(),
+		Success:      s.Success,
+		DetectCached: s.DetectCached,
+	}
+	for _, g := range s.Groups {
+		for _, r := range g.Results {
+			run.Checks = append(run.Checks, runlog.Check{
+				ID:         r.ID,
+				Name:       r.Name,
+				Path:       g.Path,
+				Status:     r.Status,
+				DurationMs: r.DurationMs,
+			})
+		}
+	}
+	return runlog.Append(s.Dir, run)
+}
+
+// toolchainLabel describes the resolved toolchain of a detection for
+// verbose output.
+func toolchainLabel(d detect.Detection) string {
+	if d.Toolchain == "" {
+		return "toolchain not found"
+	}
+	return fmt.Sprintf("%s %s", d.Language, d.Toolchain)
+}
+
+// printCheckSummaries prints the combined summary after checking several
+// directories and returns the exit code.
+func printCheckSummaries(summaries []checkSummary) int {
+	width := 0
+	for _, s := range summaries {
+		width = max(width, len(s.Dir))
+	}
+
+	fmt.Println()
+	fmt.Println("=== Combined Summary ===")
+	failedDirs := 0
+	var total checkCounts
+	for _, s := range summaries {
+		icon := "✓"
+		if s.Code != 0 {
+			icon = "✗"
+			failedDirs++
+		}
+		fmt.Printf("%s %-*s  %s\n", icon, width, s.Dir, s.Counts.format())
+		total.add(s.Counts)
+	}
+	fmt.Println()
+	fmt.Printf("Total: %s\n", total.format())
+
+	fmt.Println()
+	if failedDirs > 0 {
+		fmt.Printf("Pre-push checks failed in %d of %d directories!\n", failedDirs, len(summaries))
+		return 1
+	}
+	fmt.Printf("All pre-push checks passed in %d directories!\n", len(summaries))
+	return 0
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate shell completion scripts for atrelease.
+
+Bash:
+  source <(atrelease completion bash)
+  # Persist (Linux):
+  atrelease completion bash > /etc/bash_completion.d/atrelease
+
+Zsh:
+  atrelease completion zsh > "${fpath[1]}/_atrelease"
+
+Fish:
+  atrelease completion fish > ~/.config/fish/completions/atrelease.fish
+
+PowerShell:
+  atrelease completion powershell | Out-String | Invoke-Expression
+
+Completions include subcommands, flags, directories, and dynamic values such
+as git tags for --since and suggested next versions for release.`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+		return fmt.Errorf("unsupported shell: %s", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// registerCompletions wires dynamic completions for flags and arguments. It
+// is called from Execute, after every command's init has defined its flags.
+func registerCompletions() {
+	// Fixed-value flags
+	registerFlagValues(rootCmd, "format", "toon", "json")
+	registerFlagValues(validateCmd, "format", "default", "team")
+	for _, c := range []*cobra.Command{installKiroCmd, installClaudeCmd, installGeminiCmd} {
+		registerFlagValues(c, "scope", "user", "project")
+	}
+
+	// Directory arguments
+	for _, c := range []*cobra.Command{checkCmd, changelogCmd, readmeCmd, roadmapCmd, validateCmd} {
+		c.ValidArgsFunction = completeDirectory
+	}
+
+	// Dynamic values from git
+	_ = changelogCmd.RegisterFlagCompletionFunc("since", completeTags)
+	_ = validateCmd.RegisterFlagCompletionFunc("version", completeNextVersions)
+	_ = readmeCmd.RegisterFlagCompletionFunc("version", completeNextVersions)
+	releaseCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeNextVersions(cmd, args, toComplete)
+	}
+}
+
+// registerFlagValues registers a fixed set of completion values for a flag.
+func registerFlagValues(cmd *cobra.Command, flag string, values ...string) {
+	_ = cmd.RegisterFlagCompletionFunc(flag, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func completeDirectory(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// completeTags completes existing git tags, newest first.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	tags, err := git.New(".").AllTags()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return tags, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
+
+// completeNextVersions suggests patch, minor, and major bumps of the latest tag.
+func completeNextVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	latest, err := git.New(".").LatestTag()
+	if err != nil {
+		return []string{"v0.1.0", "v1.0.0"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	var versions []string
+	for _, part := range []semver.Part{semver.Patch, semver.Minor, semver.Major} {
+		next, err := semver.Bump(latest, part)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		versions = append(versions, next+"\t"+part.String())
+	}
+	return versions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveKeepOrder
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/detect"
+)
+
+var detectOutput string
+
+// detectCmd represents the detect command
+var detectCmd = &cobra.Command{
+	Use:   "detect [directory]",
+	Short: "Detect languages and workspaces without running checks",
+	Long: `Run only the language detection phase and print what was found: each
+language, the directory it was detected in, the indicator files, and the
+workspace (go.work, package.json workspaces, pnpm-workspace.yaml, or Cargo
+workspace) it belongs to. With --verbose or structured output, the
+toolchain version each language resolves to in its directory (go, node,
+cargo, python, swift) is included too, which helps debug checks that pass
+on one machine and fail on another.
+
+Use --output json for other tools and AI agents that want to reuse the
+detection logic.
+
+Examples:
+  atrelease detect                  # Detect in current directory
+  atrelease detect --output json    # Machine-readable detections
+  atrelease detect --json           # TOON output`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runDetect,
+}
+
+func init() {
+	detectCmd.Flags().StringVarP(&detectOutput, "output", "o", "text", "Output format: text, json, or toon")
+	rootCmd.AddCommand(detectCmd)
+}
+
+// detectReport is the structured form of the detect command output.
+type detectReport struct {
+	Dir        string             `json:"dir" toon:"dir"`
+	Detections []detect.Detection `json:"detections" toon:"detections"`
+	Workspaces []detect.Workspace `json:"workspaces,omitempty" toon:"workspaces,omitempty"`
+}
+
+func runDetect(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+	}
+
+	detections, workspaces, err := detect.Scan(dir, detectOptions(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error detecting languages: %v\n", err)
+		os.Exit(1)
+	}
+	if cfgVerbose || cfgJSON || detectOutput != "text" {
+		detect.ResolveToolchains(detections)
+	}
+	report := detectReport{Dir: dir, Detections: detections, Workspaces: workspaces}
+	if report.Detections == nil {
+		report.Detections = []detect.Detection{}
+	}
+
+	switch {
+	case detectOutput == "json":
+		err = writeFormatted(report, OutputFormatJSON)
+	case detectOutput == "toon":
+		err = writeFormatted(report, OutputFormatTOON)
+	case detectOutput != "text":
+		err = fmt.Errorf("unknown output format %q: expected text, json, or toon", detectOutput)
+	case cfgJSON:
+		err = writeStructured(report)
+	default:
+		printDetectReport(report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func printDetectReport(r detectReport) {
+	if len(r.Detections) == 0 {
+		fmt.Println("No supported languages detected.")
+		return
+	}
+
+	width := len("PATH")
+	for _, d := range r.Detections {
+		width = max(width, len(d.Path))
+	}
+	if cfgVerbose {
+		fmt.Printf("%-12s %-*s %-12s %-12s %s\n", "LANGUAGE", width, "PATH", "WORKSPACE", "TOOLCHAIN", "FILES")
+	} else {
+		fmt.Printf("%-12s %-*s %-12s %s\n", "LANGUAGE", width, "PATH", "WORKSPACE", "FILES")
+	}
+	for _, d := range r.Detections {
+		workspace := d.Workspace
+		if workspace == "" {
+			workspace = "-"
+		}
+		files := strings.Join(d.Files, ", ")
+		if cfgVerbose {
+			toolchain := d.Toolchain
+			if toolchain == "" {
+				toolchain = "not found"
+			}
+			fmt.Printf("%-12s %-*s %-12s %-12s %s\n", d.Language, width, d.Path, workspace, toolchain, files)
+		} else {
+			fmt.Printf("%-12s %-*s %-12s %s\n", d.Language, width, d.Path, workspace, files)
+		}
+	}
+
+	if len(r.Workspaces) > 0 {
+		fmt.Println()
+		fmt.Println("Workspaces:")
+		for _, w := range r.Workspaces {
+			fmt.Printf("  %-6s %s (%s)\n", w.Kind, w.File, strings.Join(w.Members, ", "))
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/detect"
+)
+
+// targetDir is a directory given to check or validate, or a detection root
+// found under one with --recursive.
+type targetDir struct {
+	Path string // Directory to check
+	Root string // Argument it was found under, whose config applies when Path has none
+}
+
+// resolveDirs returns the directories for the positional args (default
+// "."). With recursive, each argument is expanded into the directories where
+// a language is detected, so each project is checked independently.
+func resolveDirs(args []string, recursive bool) ([]targetDir, error) {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	seen := make(map[string]bool)
+	var dirs []targetDir
+	add := func(path, root string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			dirs = append(dirs, targetDir{Path: path, Root: root})
+		}
+	}
+
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("directory %s does not exist", arg)
+		}
+		if !recursive {
+			add(arg, arg)
+			continue
+		}
+
+		cfg, err := config.Load(arg)
+		if err != nil {
+			return nil, err
+		}
+		roots, err := detect.Roots(arg, detectOptions(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("detecting projects in %s: %w", arg, err)
+		}
+		if len(roots) == 0 {
+			add(arg, arg)
+		}
+		for _, root := range roots {
+			add(root, arg)
+		}
+	}
+	return dirs, nil
+}
+
+// detectOptions returns the detection options configured in cfg.
+func detectOptions(cfg config.Config) detect.Options {
+	opts := detect.Options{
+		Exclude:  cfg.Detect.Exclude,
+		MaxDepth: cfg.Detect.MaxDepth,
+	}
+	if cfg.Detect.Cache {
+		opts.CacheDir = detect.DefaultCacheDir()
+	}
+	return opts
+}
+
+// loadConfig loads the configuration for a target directory, falling back to
+// the nearest config up to the argument it was found under, and applies the
+// global flags.
+func loadConfig(t targetDir) config.Config {
+	cfg, err := config.LoadNearest(t.Path, t.Root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+	}
+	if cfgVerbose {
+		cfg.Verbose = true
+	}
+	return cfg
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/docs"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// Docs command flags
+var (
+	docsOwner   string
+	docsVersion string
+	docsForce   bool
+	docsDryRun  bool
+)
+
+// docsCmd represents the docs command
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Manage project documents",
+	Long: `Manage the project documents checked by validate: PRD.md, TRD.md,
+and release notes.`,
+}
+
+// docsInitCmd represents the docs init command
+var docsInitCmd = &cobra.Command{
+	Use:   "init [prd|trd|release-notes]... [--version <version>]",
+	Short: "Scaffold PRD, TRD, and release notes templates",
+	Long: `Scaffold document templates with front-matter that validate checks.
+
+Creates PRD.md and TRD.md in the project root, and release notes for
+--version at docs/releases/<version>.md (or RELEASE_NOTES_<version>.md when
+there is no docs/ directory). With no arguments, creates every document that
+does not exist yet; release notes are created only when --version is set.
+
+Each document starts with front-matter:
+
+  ---
+  title: <project> Product Requirements
+  owner: <owner>
+  status: draft
+  version: <version>
+  ---
+
+validate warns when owner, status, or version is missing, when status is not
+one of draft, review, approved, or final, when the version differs from the
+release, and when release notes are still draft.
+
+The owner defaults to git config user.name. Existing files are left alone
+unless --force is set.
+
+Examples:
+  atrelease docs init                          # PRD.md and TRD.md
+  atrelease docs init --version v1.2.0         # Also release notes
+  atrelease docs init release-notes --version v1.2.0
+  atrelease docs init prd --owner "Jane Doe" --dry-run`,
+	ValidArgs: docs.Kinds,
+	Args:      cobra.OnlyValidArgs,
+	Run:       runDocsInit,
+}
+
+func init() {
+	docsInitCmd.Flags().StringVar(&docsOwner, "owner", "", "Document owner (default: git config user.name)")
+	docsInitCmd.Flags().StringVar(&docsVersion, "version", "", "Target version for front-matter and release notes")
+	docsInitCmd.Flags().BoolVar(&docsForce, "force", false, "Overwrite existing documents")
+	docsInitCmd.Flags().BoolVar(&docsDryRun, "dry-run", false, "Show what would be created without writing files")
+
+	docsCmd.AddCommand(docsInitCmd)
+	rootCmd.AddCommand(docsCmd)
+}
+
+func runDocsInit(cmd *cobra.Command, args []string) {
+	dir := "."
+
+	kinds := args
+	explicit := len(kinds) > 0
+	if !explicit {
+		kinds = []string{docs.KindPRD, docs.KindTRD}
+		if docsVersion != "" {
+			kinds = append(kinds, docs.KindReleaseNotes)
+		}
+	}
+
+	if docsVersion != "" {
+		if !semver.IsValid(docsVersion) {
+			fmt.Fprintf(os.Stderr, "Error: invalid version %s (expected vMAJOR.MINOR.PATCH)\n", docsVersion)
+			os.Exit(1)
+		}
+	}
+
+	owner := docsOwner
+	if owner == "" {
+		owner, _ = git.New(dir).UserName()
+	}
+	data := docs.TemplateData{
+		Project: filepath.Base(mustAbs(dir)),
+		Owner:   owner,
+		Version: docsVersion,
+		Date:    time.Now().Format("2006-01-02"),
+	}
+
+	fmt.Println("=== Docs ===")
+	fmt.Println()
+
+	failed := false
+	for _, kind := range kinds {
+		path, err := docs.Path(dir, kind, docsVersion)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v (use --version)\n", kind, err)
+			failed = true
+			continue
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, path)); err == nil && !docsForce {
+			fmt.Printf("  = %s (exists; use --force to overwrite)\n", path)
+			continue
+		}
+
+		content, err := docs.Render(kind, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", kind, err)
+			failed = true
+			continue
+		}
+
+		fmt.Printf("  + %s\n", path)
+		if docsDryRun {
+			continue
+		}
+		target := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+			continue
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: writing %s: %v\n", path, err)
+			failed = true
+		}
+	}
+
+	if docsDryRun {
+		fmt.Println()
+		fmt.Println("Dry run: no changes made.")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
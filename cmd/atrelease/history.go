@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+var historyLimit int
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history [directory]",
+	Short: "List past releases",
+	Long: `List past releases from git tags and CHANGELOG.json.
+
+For each release, shows the version, release date, number of highlights,
+number of breaking changes, and days since the previous release. Releases
+present only as tags or only in CHANGELOG.json are included.
+
+Examples:
+  atrelease history                      # All releases, newest first
+  atrelease history --limit 5            # Last five releases
+  atrelease history --json --format json # JSON for dashboards`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runHistory,
+}
+
+func init() {
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 0, "Maximum number of releases to show (0 for all)")
+	rootCmd.AddCommand(historyCmd)
+}
+
+// historyEntry is a single release in the history output.
+type historyEntry struct {
+	Version           string `json:"version" toon:"version"`
+	Date              string `json:"date,omitempty" toon:"date,omitempty"`
+	Tagged            bool   `json:"tagged" toon:"tagged"`
+	Highlights        int    `json:"highlights" toon:"highlights"`
+	BreakingChanges   int    `json:"breaking_changes" toon:"breaking_changes"`
+	DaysSincePrevious *int   `json:"days_since_previous,omitempty" toon:"days_since_previous,omitempty"`
+}
+
+func runHistory(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
+		os.Exit(1)
+	}
+
+	entries, err := buildHistory(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if historyLimit > 0 && len(entries) > historyLimit {
+		entries = entries[:historyLimit]
+	}
+
+	if cfgJSON {
+		if err := writeStructured(entries); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No releases found.")
+		return
+	}
+
+	fmt.Printf("%-12s %-10s %10s %8s %6s\n", "VERSION", "DATE", "HIGHLIGHTS", "BREAKING", "DAYS")
+	for _, e := range entries {
+		date := e.Date
+		if date == "" {
+			date = "-"
+		}
+		days := "-"
+		if e.DaysSincePrevious != nil {
+			days = fmt.Sprintf("%d", *e.DaysSincePrevious)
+		}
+		version := e.Version
+		if !e.Tagged {
+			version += "*"
+		}
+		fmt.Printf("%-12s %-10s %10d %8d %6s\n", version, date, e.Highlights, e.BreakingChanges, days)
+	}
+	for _, e := range entries {
+		if !e.Tagged {
+			fmt.Println()
+			fmt.Println("* in CHANGELOG.json but not tagged")
+			break
+		}
+	}
+}
+
+// buildHistory merges git tags and CHANGELOG.json releases, newest first.
+func buildHistory(dir string) ([]historyEntry, error) {
+	byVersion := make(map[string]*historyEntry)
+	entry := func(version string) *historyEntry {
+		key := "v" + strings.TrimPrefix(version, "v")
+		if e, ok := byVersion[key]; ok {
+			return e
+		}
+		e := &historyEntry{Version: key}
+		byVersion[key] = e
+		return e
+	}
+
+	g := git.New(dir)
+	tags, _ := g.AllTags()
+	for _, tag := range tags {
+		if !semver.IsValid(tag) {
+			continue
+		}
+		e := entry(tag)
+		e.Tagged = true
+		if date, err := g.TagDate(tag); err == nil {
+			e.Date = date.Format(time.DateOnly)
+		}
+	}
+
+	cl, err := changelog.Load(filepath.Join(dir, changelog.DefaultFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if cl != nil {
+		for _, r := range cl.Releases {
+			e := entry(r.Version)
+			if r.Date != "" {
+				// The changelog date is the declared release date.
+				e.Date = r.Date
+			}
+			e.Highlights = len(r.Highlights)
+			e.BreakingChanges = r.BreakingCount()
+		}
+	}
+
+	entries := make([]historyEntry, 0, len(byVersion))
+	for _, e := range byVersion {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return semver.Compare(entries[i].Version, entries[j].Version) > 0
+	})
+
+	// Days since the previous (older) release
+	for i := 0; i < len(entries)-1; i++ {
+		cur, err1 := time.Parse(time.DateOnly, entries[i].Date)
+		prev, err2 := time.Parse(time.DateOnly, entries[i+1].Date)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		days := int(cur.Sub(prev).Hours() / 24)
+		entries[i].DaysSincePrevious = &days
+	}
+
+	return entries, nil
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/plugins/claude"
+	"github.com/plexusone/agent-team-release/plugins/gemini"
 	"github.com/plexusone/agent-team-release/plugins/kiro"
 	"github.com/spf13/cobra"
 )
@@ -19,8 +25,10 @@ const (
 )
 
 var (
-	installApply  bool
-	installPrefix string
+	installApply     bool
+	installPrefix    string
+	installUninstall bool
+	installScope     string
 )
 
 var installCmd = &cobra.Command{
@@ -30,6 +38,18 @@ var installCmd = &cobra.Command{
 
 Supported platforms:
   kiro    AWS Kiro CLI agents and steering files
+  claude  Claude Code subagents, slash commands, and skills
+  gemini  Gemini CLI extension
+
+With --scope project, files are installed under the current repository
+(e.g. .kiro/, .claude/, .gemini/) instead of the home directory, so per-repo
+agent configurations can be committed to version control.
+
+Each install records a manifest of installed files and checksums. Files from
+a previous install that are no longer produced (for example after changing
+--prefix) are removed on upgrade, and --uninstall removes everything listed in
+the manifest. Files modified since they were installed are left in place
+and stay listed in the manifest.
 
 By default, shows a plan of what would be installed. Use --apply to install.`,
 }
@@ -54,28 +74,75 @@ By default, shows a plan of what would be installed. Use --apply to install.`,
 	RunE: runInstallKiro,
 }
 
+var installClaudeCmd = &cobra.Command{
+	Use:   "claude",
+	Short: "Install Claude Code subagents, commands, and skills",
+	Long: `Install pre-built Claude Code configurations to ~/.claude/
+
+This installs:
+  - Subagents to ~/.claude/agents/
+  - Slash commands to ~/.claude/commands/<prefix>/
+  - Skills to ~/.claude/skills/
+
+Agents and skills are prefixed with the team name to avoid collisions when
+installing agents from multiple projects. Commands are placed in a directory
+named after the prefix, so they are invoked as /<prefix>:<command>.
+Default prefix: agent-team-release
+
+Example installed files:
+  ~/.claude/agents/agent-team-release_pm.md
+  ~/.claude/commands/agent-team-release/check.md
+  ~/.claude/skills/agent-team-release_version-analysis/SKILL.md
+
+By default, shows a plan of what would be installed. Use --apply to install.`,
+	RunE: runInstallClaude,
+}
+
+var installGeminiCmd = &cobra.Command{
+	Use:   "gemini",
+	Short: "Install the Gemini CLI extension",
+	Long: `Install the pre-built Gemini CLI extension to ~/.gemini/extensions/
+
+The extension is installed into a directory named after the prefix, and the
+extension name in gemini-extension.json is set to match.
+Default prefix: agent-team-release
+
+Example installed files:
+  ~/.gemini/extensions/agent-team-release/gemini-extension.json
+  ~/.gemini/extensions/agent-team-release/commands/check.toml
+
+By default, shows a plan of what would be installed. Use --apply to install.`,
+	RunE: runInstallGemini,
+}
+
 func init() {
-	installCmd.AddCommand(installKiroCmd)
-	installKiroCmd.Flags().BoolVar(&installApply, "apply", false, "Apply the installation (default: plan only)")
-	installKiroCmd.Flags().StringVar(&installPrefix, "prefix", DefaultInstallPrefix, "Prefix for installed files")
+	for _, c := range []*cobra.Command{installKiroCmd, installClaudeCmd, installGeminiCmd} {
+		installCmd.AddCommand(c)
+		c.Flags().BoolVar(&installApply, "apply", false, "Apply the installation (default: plan only)")
+		c.Flags().StringVar(&installPrefix, "prefix", DefaultInstallPrefix, "Prefix for installed files")
+		c.Flags().StringVar(&installScope, "scope", "user", "Install scope: user (home directory) or project (repository root)")
+		c.Flags().BoolVar(&installUninstall, "uninstall", false, "Remove previously installed files listed in the install manifest")
+	}
 	rootCmd.AddCommand(installCmd)
 }
 
 // FileAction represents an install action
 type FileAction struct {
-	Action string // "create", "update", "unchanged"
+	Action string // "create", "update", "unchanged", "remove", "modified"
 	Source string
 	Dest   string
 	Size   int64
+	Data   []byte // content to write, with any prefixing applied
+	SHA256 string // for "modified", the checksum the file was installed with
 }
 
 func runInstallKiro(cmd *cobra.Command, args []string) error {
-	homeDir, err := os.UserHomeDir()
+	rootDir, err := installRoot()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
-	kiroDir := filepath.Join(homeDir, ".kiro")
+	kiroDir := filepath.Join(rootDir, ".kiro")
 	agentsDir := filepath.Join(kiroDir, "agents")
 	steeringDir := filepath.Join(kiroDir, "steering")
 
@@ -95,6 +162,179 @@ func runInstallKiro(cmd *cobra.Command, args []string) error {
 	}
 	actions = append(actions, steeringActions...)
 
+	return runInstallPlan("Kiro Agent", kiroDir, actions)
+}
+
+func runInstallClaude(cmd *cobra.Command, args []string) error {
+	rootDir, err := installRoot()
+	if err != nil {
+		return err
+	}
+
+	claudeDir := filepath.Join(rootDir, ".claude")
+
+	var actions []FileAction
+
+	// Agents: prefixed filename and frontmatter name
+	err = fs.WalkDir(claude.AgentFiles, "agents", func(src string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(claude.AgentFiles, src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		dest := filepath.Join(claudeDir, "agents", prefixName(path.Base(src), installPrefix))
+		action, err := planFile(src, dest, prefixFrontmatterName(data, installPrefix))
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Commands: namespaced by a directory named after the prefix
+	err = fs.WalkDir(claude.CommandFiles, "commands", func(src string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(claude.CommandFiles, src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		dest := filepath.Join(claudeDir, "commands", installPrefix, path.Base(src))
+		action, err := planFile(src, dest, data)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Skills: prefixed skill directory and frontmatter name
+	err = fs.WalkDir(claude.SkillFiles, "skills", func(src string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(claude.SkillFiles, src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		skillName := path.Base(path.Dir(src))
+		dest := filepath.Join(claudeDir, "skills", prefixName(skillName, installPrefix), path.Base(src))
+		action, err := planFile(src, dest, prefixFrontmatterName(data, installPrefix))
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return runInstallPlan("Claude Code", claudeDir, actions)
+}
+
+func runInstallGemini(cmd *cobra.Command, args []string) error {
+	rootDir, err := installRoot()
+	if err != nil {
+		return err
+	}
+
+	name := installPrefix
+	if name == "" {
+		name = DefaultInstallPrefix
+	}
+	extensionsDir := filepath.Join(rootDir, ".gemini", "extensions")
+	extDir := filepath.Join(extensionsDir, name)
+
+	var actions []FileAction
+
+	err = fs.WalkDir(gemini.ExtensionFiles, ".", func(src string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(gemini.ExtensionFiles, src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", src, err)
+		}
+		if src == "gemini-extension.json" {
+			// The extension name must match the install directory
+			data, err = setJSONName(data, name)
+			if err != nil {
+				return fmt.Errorf("failed to set extension name in %s: %w", src, err)
+			}
+		}
+		action, err := planFile(src, filepath.Join(extDir, filepath.FromSlash(src)), data)
+		if err != nil {
+			return err
+		}
+		actions = append(actions, action)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return runInstallPlan("Gemini Extension", extensionsDir, actions)
+}
+
+// installRoot returns the directory platform directories are installed under:
+// the home directory for user scope, or the repository root for project scope.
+func installRoot() (string, error) {
+	switch installScope {
+	case "user", "":
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		return homeDir, nil
+	case "project":
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get working directory: %w", err)
+		}
+		if root, err := git.New(cwd).TopLevel(); err == nil {
+			return root, nil
+		}
+		// Not a git repository; install relative to the working directory
+		return cwd, nil
+	default:
+		return "", fmt.Errorf("invalid scope %q: must be user or project", installScope)
+	}
+}
+
+// runInstallPlan displays the plan and, with --apply, writes the files and
+// the install manifest under baseDir. Files recorded by a previous install
+// that are no longer produced are removed. With --uninstall, every file in
+// the manifest is removed instead. Files modified since they were installed
+// are kept and stay listed in the manifest.
+func runInstallPlan(platform, baseDir string, actions []FileAction) error {
+	manifestPath := filepath.Join(baseDir, installManifestName)
+	prev, err := loadInstallManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	title := platform + " Installation Plan:"
+	if installUninstall {
+		if prev == nil {
+			fmt.Println("No install manifest found; nothing to uninstall.")
+			return nil
+		}
+		title = platform + " Uninstall Plan:"
+		actions = planRemovals(baseDir, prev, nil)
+	} else {
+		actions = append(actions, planRemovals(baseDir, prev, actions)...)
+	}
+
 	if len(actions) == 0 {
 		fmt.Println("No files to install.")
 		return nil
@@ -102,13 +342,14 @@ func runInstallKiro(cmd *cobra.Command, args []string) error {
 
 	// Display plan
 	fmt.Println()
-	fmt.Println("Kiro Agent Installation Plan:")
+	fmt.Println(title)
 	fmt.Println()
 
-	var toCreate, toUpdate, unchanged int
+	var toCreate, toUpdate, unchanged, toRemove int
 	for _, action := range actions {
 		symbol := "+"
 		color := "\033[32m" // green
+		suffix := ""
 		switch action.Action {
 		case "update":
 			symbol = "~"
@@ -118,63 +359,109 @@ func runInstallKiro(cmd *cobra.Command, args []string) error {
 			symbol = " "
 			color = "\033[90m" // gray
 			unchanged++
+		case "remove":
+			symbol = "-"
+			color = "\033[31m" // red
+			toRemove++
+		case "modified":
+			symbol = "!"
+			color = "\033[33m" // yellow
+			suffix = " (modified since install, keeping)"
 		default:
 			toCreate++
 		}
 		reset := "\033[0m"
-		fmt.Printf("  %s%s %s%s\n", color, symbol, action.Dest, reset)
+		fmt.Printf("  %s%s %s%s%s\n", color, symbol, action.Dest, suffix, reset)
 	}
 
 	fmt.Println()
-	fmt.Printf("%d to create, %d to update, %d unchanged\n", toCreate, toUpdate, unchanged)
+	fmt.Printf("%d to create, %d to update, %d to remove, %d unchanged\n", toCreate, toUpdate, toRemove, unchanged)
 
 	if !installApply {
 		fmt.Println()
-		fmt.Println("Run with --apply to install.")
+		if installUninstall {
+			fmt.Println("Run with --apply to uninstall.")
+		} else {
+			fmt.Println("Run with --apply to install.")
+		}
 		return nil
 	}
 
 	// Apply installation
 	fmt.Println()
-	fmt.Println("Installing...")
-
-	// Create directories
-	if err := os.MkdirAll(agentsDir, 0755); err != nil {
-		return fmt.Errorf("failed to create agents directory: %w", err)
-	}
-	if err := os.MkdirAll(steeringDir, 0755); err != nil {
-		return fmt.Errorf("failed to create steering directory: %w", err)
+	if installUninstall {
+		fmt.Println("Uninstalling...")
+	} else {
+		fmt.Println("Installing...")
 	}
 
-	// Copy files
-	installed := 0
+	installed, removed, kept := 0, 0, 0
+	manifest := &installManifest{
+		Version: version,
+		Prefix:  installPrefix,
+	}
 	for _, action := range actions {
-		if action.Action == "unchanged" {
+		sum := checksum(action.Data)
+		switch action.Action {
+		case "remove":
+			if err := os.Remove(action.Dest); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove %s: %w", action.Dest, err)
+			}
+			removeEmptyDirs(filepath.Dir(action.Dest), baseDir)
+			removed++
 			continue
-		}
-
-		var data []byte
-		var readErr error
-		if strings.HasSuffix(action.Source, ".json") {
-			data, readErr = kiro.AgentFiles.ReadFile(action.Source)
-			if readErr == nil && installPrefix != "" {
-				// Prefix the "name" field inside the agent JSON
-				data, readErr = prefixAgentName(data, installPrefix)
+		case "modified":
+			// Kept files stay in the manifest with their installed
+			// checksum, so they are removed once restored
+			sum = action.SHA256
+			kept++
+		case "unchanged":
+		default:
+			if err := os.MkdirAll(filepath.Dir(action.Dest), 0755); err != nil {
+				return fmt.Errorf("failed to create directory for %s: %w", action.Dest, err)
 			}
-		} else {
-			data, readErr = kiro.SteeringFiles.ReadFile(action.Source)
+			if err := os.WriteFile(action.Dest, action.Data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", action.Dest, err)
+			}
+			installed++
 		}
-		if readErr != nil {
-			return fmt.Errorf("failed to read %s: %w", action.Source, readErr)
+		rel, err := filepath.Rel(baseDir, action.Dest)
+		if err != nil {
+			return fmt.Errorf("failed to record %s: %w", action.Dest, err)
 		}
+		manifest.Files = append(manifest.Files, manifestFile{
+			Path:   filepath.ToSlash(rel),
+			SHA256: sum,
+		})
+	}
 
-		if err := os.WriteFile(action.Dest, data, 0644); err != nil {
-			return fmt.Errorf("failed to write %s: %w", action.Dest, err)
+	if installUninstall {
+		if len(manifest.Files) > 0 {
+			if err := saveInstallManifest(manifestPath, manifest); err != nil {
+				return err
+			}
+		} else if err := os.Remove(manifestPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove install manifest: %w", err)
+		}
+		fmt.Printf("\nRemoved %d files from %s", removed, baseDir)
+		if kept > 0 {
+			fmt.Printf(", kept %d modified files", kept)
 		}
-		installed++
+		fmt.Println()
+		return nil
 	}
 
-	fmt.Printf("\nInstalled %d files to %s\n", installed, kiroDir)
+	if err := saveInstallManifest(manifestPath, manifest); err != nil {
+		return err
+	}
+	fmt.Printf("\nInstalled %d files to %s", installed, baseDir)
+	if removed > 0 {
+		fmt.Printf(", removed %d stale files", removed)
+	}
+	if kept > 0 {
+		fmt.Printf(", kept %d modified stale files", kept)
+	}
+	fmt.Println()
 	return nil
 }
 
@@ -192,12 +479,7 @@ func planEmbeddedFiles(fsys fs.FS, srcDir, destDir, ext, prefix string) ([]FileA
 			return nil
 		}
 
-		filename := filepath.Base(path)
-		// Add prefix to filename if provided
-		if prefix != "" {
-			filename = prefix + "_" + filename
-		}
-		destPath := filepath.Join(destDir, filename)
+		destPath := filepath.Join(destDir, prefixName(filepath.Base(path), prefix))
 
 		// Read source file
 		srcData, err := fs.ReadFile(fsys, path)
@@ -213,24 +495,10 @@ func planEmbeddedFiles(fsys fs.FS, srcDir, destDir, ext, prefix string) ([]FileA
 			}
 		}
 
-		action := FileAction{
-			Source: path,
-			Dest:   destPath,
-			Size:   int64(len(srcData)),
-		}
-
-		// Check if destination exists
-		destData, err := os.ReadFile(destPath)
-		if os.IsNotExist(err) {
-			action.Action = "create"
-		} else if err != nil {
-			return fmt.Errorf("failed to read %s: %w", destPath, err)
-		} else if string(srcData) == string(destData) {
-			action.Action = "unchanged"
-		} else {
-			action.Action = "update"
+		action, err := planFile(path, destPath, srcData)
+		if err != nil {
+			return err
 		}
-
 		actions = append(actions, action)
 		return nil
 	})
@@ -238,6 +506,38 @@ func planEmbeddedFiles(fsys fs.FS, srcDir, destDir, ext, prefix string) ([]FileA
 	return actions, err
 }
 
+// planFile compares data against the destination to determine the action.
+func planFile(src, dest string, data []byte) (FileAction, error) {
+	action := FileAction{
+		Source: src,
+		Dest:   dest,
+		Size:   int64(len(data)),
+		Data:   data,
+	}
+
+	// Check if destination exists
+	destData, err := os.ReadFile(dest)
+	if os.IsNotExist(err) {
+		action.Action = "create"
+	} else if err != nil {
+		return action, fmt.Errorf("failed to read %s: %w", dest, err)
+	} else if string(data) == string(destData) {
+		action.Action = "unchanged"
+	} else {
+		action.Action = "update"
+	}
+
+	return action, nil
+}
+
+// prefixName returns name with the install prefix prepended, if any.
+func prefixName(name, prefix string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
 // prefixAgentName modifies the "name" field in a Kiro agent JSON to include the prefix.
 func prefixAgentName(data []byte, prefix string) ([]byte, error) {
 	var agent map[string]interface{}
@@ -251,3 +551,133 @@ func prefixAgentName(data []byte, prefix string) ([]byte, error) {
 
 	return json.MarshalIndent(agent, "", "  ")
 }
+
+// setJSONName sets the top-level "name" field in a JSON manifest.
+func setJSONName(data []byte, name string) ([]byte, error) {
+	var manifest map[string]interface{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	manifest["name"] = name
+	return json.MarshalIndent(manifest, "", "  ")
+}
+
+// prefixFrontmatterName prefixes the "name:" field in a markdown file's YAML
+// frontmatter. Files without frontmatter or a name field are returned as-is.
+func prefixFrontmatterName(data []byte, prefix string) []byte {
+	if prefix == "" {
+		return data
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return data
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			break
+		}
+		if name, ok := strings.CutPrefix(lines[i], "name:"); ok {
+			lines[i] = "name: " + prefix + "_" + strings.TrimSpace(name)
+			break
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// installManifestName is the manifest file written to the install base
+// directory. It is keyed by the compiled-in team name rather than --prefix so
+// that a prefix change can still find and clean up the previous install.
+const installManifestName = "." + DefaultInstallPrefix + "-manifest.json"
+
+// installManifest records the files written by an install.
+type installManifest struct {
+	Version string         `json:"version"`
+	Prefix  string         `json:"prefix"`
+	Files   []manifestFile `json:"files"`
+}
+
+// manifestFile is an installed file, relative to the install base directory.
+type manifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// loadInstallManifest reads the manifest at path. It returns nil if no
+// manifest exists.
+func loadInstallManifest(path string) (*installManifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install manifest: %w", err)
+	}
+	var m installManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse install manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+func saveInstallManifest(path string, m *installManifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode install manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for install manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write install manifest: %w", err)
+	}
+	return nil
+}
+
+// planRemovals returns remove actions for files in the previous manifest
+// that are not part of the current install. Files whose contents no longer
+// match the recorded checksum are marked "modified" and kept.
+func planRemovals(baseDir string, prev *installManifest, current []FileAction) []FileAction {
+	if prev == nil {
+		return nil
+	}
+
+	keep := make(map[string]bool, len(current))
+	for _, action := range current {
+		keep[action.Dest] = true
+	}
+
+	var actions []FileAction
+	for _, f := range prev.Files {
+		dest := filepath.Join(baseDir, filepath.FromSlash(f.Path))
+		if keep[dest] {
+			continue
+		}
+		data, err := os.ReadFile(dest)
+		if err != nil {
+			// Already gone or unreadable; nothing to remove.
+			continue
+		}
+		action := FileAction{Action: "remove", Dest: dest, Size: int64(len(data))}
+		if checksum(data) != f.SHA256 {
+			action.Action = "modified"
+			action.SHA256 = f.SHA256
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// removeEmptyDirs removes dir and its empty parents, stopping at stopAt.
+func removeEmptyDirs(dir, stopAt string) {
+	for dir != stopAt && strings.HasPrefix(dir, stopAt+string(filepath.Separator)) {
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
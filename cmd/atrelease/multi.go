@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+var (
+	multiRepos string
+	multiJobs  int
+)
+
+// multiCmd represents the multi command
+var multiCmd = &cobra.Command{
+	Use:   "multi",
+	Short: "Run checks across several local repositories",
+	Long: `Run check in each repository listed in a repos file, several at a time,
+and print a matrix of check results across the repositories. Use it to see
+at a glance which services in a fleet of clones or worktrees are ready to
+push.
+
+Each repository is checked by a separate atrelease process with its own
+.releaseagent.yaml, so the runs don't interfere with each other.
+
+The repos file lists the repositories, relative to the file:
+
+  repos:
+    - path: ../svc-api
+    - path: ../svc-billing
+      name: billing
+
+Examples:
+  atrelease multi --repos repos.yaml          # Check all repositories
+  atrelease multi --repos repos.yaml -j 8     # Eight at a time
+  atrelease multi --repos repos.yaml --json   # TOON output`,
+	Args: cobra.NoArgs,
+	Run:  runMulti,
+}
+
+func init() {
+	multiCmd.Flags().StringVar(&multiRepos, "repos", "", "YAML file listing the repositories to check (required)")
+	multiCmd.Flags().IntVarP(&multiJobs, "jobs", "j", 4, "Number of repositories to check at a time")
+	_ = multiCmd.MarkFlagRequired("repos")
+	rootCmd.AddCommand(multiCmd)
+}
+
+// multiRepo is a repository listed in the repos file.
+type multiRepo struct {
+	Path string `yaml:"path"`
+	Name string `yaml:"name"` // Defaults to the directory name
+}
+
+// multiResult is the outcome of checking one repository.
+type multiResult struct {
+	Name       string            `json:"name" toon:"name"`
+	Path       string            `json:"path" toon:"path"`
+	Success    bool              `json:"success" toon:"success"`
+	Error      string            `json:"error,omitempty" toon:"error,omitempty"`
+	Counts     checkCounts       `json:"summary" toon:"summary"`
+	Checks     map[string]string `json:"checks,omitempty" toon:"checks,omitempty"` // Check ID to status
+	DurationMs int64             `json:"duration_ms" toon:"duration_ms"`
+}
+
+// multiReport is the structured output of multi with --json.
+type multiReport struct {
+	Success bool          `json:"success" toon:"success"`
+	Counts  checkCounts   `json:"summary" toon:"summary"`
+	Checks  []string      `json:"checks" toon:"checks"` // Check IDs across all repositories
+	Repos   []multiResult `json:"repos" toon:"repos"`
+}
+
+func runMulti(cmd *cobra.Command, args []string) {
+	repos, err := loadMultiRepos(multiRepos)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	defer proc.SetContext(ctx)()
+
+	if !cfgJSON {
+		fmt.Printf("Checking %d repositories (%d at a time)...\n", len(repos), max(multiJobs, 1))
+	}
+
+	results := make([]multiResult, len(repos))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	sem := make(chan struct{}, max(multiJobs, 1))
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = checkRepo(exe, repo)
+			if !cfgJSON {
+				mu.Lock()
+				fmt.Printf("  %s %s (%s)\n", multiIcon(results[i]), results[i].Name, time.Duration(results[i].DurationMs)*time.Millisecond)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := newMultiReport(results)
+	if cfgJSON {
+		if err := writeStructured(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		printMultiReport(report)
+	}
+	if !report.Success {
+		os.Exit(1)
+	}
+}
+
+// loadMultiRepos reads a repos file. Relative repository paths are
+// resolved against the file's directory.
+func loadMultiRepos(path string) ([]multiRepo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading repos file: %w", err)
+	}
+	var file struct {
+		Repos []multiRepo `yaml:"repos"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(file.Repos) == 0 {
+		return nil, fmt.Errorf("no repositories listed in %s", path)
+	}
+
+	base := filepath.Dir(path)
+	seen := make(map[string]bool)
+	for i, r := range file.Repos {
+		if r.Path == "" {
+			return nil, fmt.Errorf("%s: repository %d has no path", path, i+1)
+		}
+		if !filepath.IsAbs(r.Path) {
+			file.Repos[i].Path = filepath.Join(base, r.Path)
+		}
+		if r.Name == "" {
+			file.Repos[i].Name = filepath.Base(file.Repos[i].Path)
+		}
+		if seen[file.Repos[i].Name] {
+			return nil, fmt.Errorf("%s: duplicate repository name %q; set name to tell them apart", path, file.Repos[i].Name)
+		}
+		seen[file.Repos[i].Name] = true
+	}
+	return file.Repos, nil
+}
+
+// checkRepo runs check in repo with a separate atrelease process and
+// collects the status of each check.
+func checkRepo(exe string, repo multiRepo) multiResult {
+	result := multiResult{Name: repo.Name, Path: repo.Path}
+	start := time.Now()
+
+	if info, err := os.Stat(repo.Path); err != nil || !info.IsDir() {
+		result.Error = "not a directory"
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := proc.Command(exe, "check", "--json", "--format", "json", repo.Path)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	var report checkReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		result.Error = lastLine(stderr.String())
+		if result.Error == "" && runErr != nil {
+			result.Error = runErr.Error()
+		}
+		if result.Error == "" {
+			result.Error = fmt.Sprintf("reading check output: %v", err)
+		}
+		result.DurationMs = time.Since(start).Milliseconds()
+		return result
+	}
+
+	result.Success = report.Success
+	result.Counts = report.Counts
+	result.Checks = make(map[string]string)
+	for _, d := range report.Directories {
+		if d.Error != "" {
+			result.Error = d.Error
+		}
+		for _, g := range d.Groups {
+			for _, r := range g.Results {
+				result.Checks[r.ID] = worseStatus(result.Checks[r.ID], r.Status)
+			}
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+// statusRank orders check statuses from best to worst.
+var statusRank = map[string]int{
+	string(checks.SeveritySkipped): 1,
+	string(checks.SeverityPassed):  2,
+	string(checks.SeverityWarning): 3,
+	string(checks.SeverityFailed):  4,
+}
+
+// worseStatus returns the worse of two statuses of the same check, which
+// differ in monorepos where the check runs in several directories.
+func worseStatus(a, b string) string {
+	if statusRank[b] > statusRank[a] {
+		return b
+	}
+	return a
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func newMultiReport(results []multiResult) multiReport {
+	report := multiReport{Success: true, Repos: results}
+	for _, r := range results {
+		report.Success = report.Success && r.Success
+		report.Counts.add(r.Counts)
+		for id := range r.Checks {
+			if !slices.Contains(report.Checks, id) {
+				report.Checks = append(report.Checks, id)
+			}
+		}
+	}
+	slices.Sort(report.Checks)
+	return report
+}
+
+func multiIcon(r multiResult) string {
+	if r.Success {
+		return "✓"
+	}
+	return "✗"
+}
+
+// statusIcons are the matrix cells for each check status.
+var statusIcons = map[string]string{
+	string(checks.SeverityPassed):  "✓",
+	string(checks.SeverityFailed):  "✗",
+	string(checks.SeverityWarning): "⚠",
+	string(checks.SeveritySkipped): "⊘",
+}
+
+// printMultiReport prints a matrix with a row per check and a column per
+// repository, followed by the totals.
+func printMultiReport(report multiReport) {
+	width := 0
+	for _, id := range report.Checks {
+		width = max(width, len(id))
+	}
+
+	fmt.Println()
+	fmt.Println("=== Multi-Repo Summary ===")
+	if len(report.Checks) > 0 {
+		fmt.Printf("%-*s", width, "")
+		for _, r := range report.Repos {
+			fmt.Printf("  %s", r.Name)
+		}
+		fmt.Println()
+		for _, id := range report.Checks {
+			fmt.Printf("%-*s", width, id)
+			for _, r := range report.Repos {
+				cell := "-"
+				if status, ok := r.Checks[id]; ok {
+					cell = statusIcons[status]
+				}
+				// Center the cell under the repository name
+				pad := len([]rune(r.Name)) - 1
+				fmt.Printf("  %*s%s%*s", pad/2, "", cell, pad-pad/2, "")
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+
+	nameWidth := 0
+	for _, r := range report.Repos {
+		nameWidth = max(nameWidth, len(r.Name))
+	}
+	failed := 0
+	for _, r := range report.Repos {
+		detail := r.Counts.format()
+		if r.Error != "" {
+			detail = "error: " + r.Error
+		}
+		if !r.Success {
+			failed++
+		}
+		fmt.Printf("%s %-*s  %s\n", multiIcon(r), nameWidth, r.Name, detail)
+	}
+	fmt.Println()
+	fmt.Printf("Total: %s\n", report.Counts.format())
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("Pre-push checks failed in %d of %d repositories!\n", failed, len(report.Repos))
+		return
+	}
+	fmt.Printf("All pre-push checks passed in %d repositories!\n", len(report.Repos))
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/interactive"
+	"github.com/plexusone/agent-team-release/pkg/output"
+	"github.com/plexusone/agent-team-release/pkg/roadmap"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// Plan command flags
+var (
+	planIssues      []int
+	planMilestone   bool
+	planDryRun      bool
+	planIssuesLimit int
+)
+
+// planCmd represents the plan command
+var planCmd = &cobra.Command{
+	Use:   "plan <version>",
+	Short: "Plan the next release in ROADMAP.json",
+	Long: `Plan the next release by adding open GitHub issues to ROADMAP.json.
+
+Creates the phase for the version in ROADMAP.json if it does not exist, and
+adds or updates a planned roadmap item for each selected issue. Issues are
+selected with --issue, or interactively from the open issue list with -i.
+
+With --milestone, also creates a GitHub milestone named after the version
+and assigns the selected issues to it.
+
+Requires gh for issue and milestone access.
+
+Examples:
+  atrelease plan v0.9.0 -i               # Pick issues interactively
+  atrelease plan v0.9.0 --issue 12,15    # Plan specific issues
+  atrelease plan v0.9.0 -i --milestone   # Also create a GitHub milestone
+  atrelease plan v0.9.0 -i --dry-run     # Preview changes`,
+	Args: cobra.ExactArgs(1),
+	Run:  runPlan,
+}
+
+func init() {
+	planCmd.Flags().IntSliceVar(&planIssues, "issue", nil, "Issue numbers to plan (comma-separated)")
+	planCmd.Flags().BoolVar(&planMilestone, "milestone", false, "Create a GitHub milestone and assign the selected issues")
+	planCmd.Flags().BoolVar(&planDryRun, "dry-run", false, "Show what would be done without making changes")
+	planCmd.Flags().IntVar(&planIssuesLimit, "issues-limit", 100, "Maximum number of open issues to consider")
+	rootCmd.AddCommand(planCmd)
+}
+
+func runPlan(cmd *cobra.Command, args []string) {
+	version := args[0]
+	major, minor, _, err := semver.Split(version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	dir := "."
+	g := git.New(dir)
+	roadmapPath := filepath.Join(dir, roadmap.DefaultFile)
+
+	r, err := roadmap.Load(roadmapPath)
+	if os.IsNotExist(err) {
+		r = roadmap.New(filepath.Base(mustAbs(dir)))
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	selected, err := selectPlanIssues(g)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("=== Plan %s ===\n", version)
+	fmt.Println()
+
+	// Ensure a phase exists for the minor version
+	phaseID := fmt.Sprintf("v%d.%d", major, minor)
+	if r.Phase(phaseID) == nil {
+		order := 0
+		for _, p := range r.Phases {
+			// Keep catch-all phases (e.g. "future" at 99) last
+			if p.Order < 99 && p.Order > order {
+				order = p.Order
+			}
+		}
+		r.Phases = append(r.Phases, roadmap.Phase{
+			ID:     phaseID,
+			Name:   version,
+			Status: roadmap.StatusPlanned,
+			Order:  order + 1,
+		})
+		fmt.Printf("  + phase %s\n", phaseID)
+	}
+
+	for _, issue := range selected {
+		itemType := "Added"
+		if issue.HasLabel("bug") {
+			itemType = "Fixed"
+		}
+		added := r.UpsertItem(roadmap.Item{
+			ID:          fmt.Sprintf("issue-%d", issue.Number),
+			Title:       issue.Title,
+			Description: issue.URL,
+			Status:      roadmap.StatusPlanned,
+			Version:     strings.TrimPrefix(version, "v"),
+			Phase:       phaseID,
+			Type:        itemType,
+		})
+		symbol := "+"
+		if !added {
+			symbol = "~"
+		}
+		fmt.Printf("  %s #%d %s\n", symbol, issue.Number, issue.Title)
+	}
+
+	if planMilestone {
+		fmt.Printf("  + milestone %s (%d issues)\n", version, len(selected))
+	}
+
+	if planDryRun {
+		fmt.Println()
+		fmt.Println("Dry run: no changes made.")
+		return
+	}
+
+	if err := r.Save(roadmapPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: writing %s: %v\n", roadmapPath, err)
+		os.Exit(1)
+	}
+
+	if planMilestone {
+		created, err := g.EnsureMilestone(version, fmt.Sprintf("Release %s", version))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !created {
+			fmt.Printf("Milestone %s already exists\n", version)
+		}
+		for _, issue := range selected {
+			if err := g.SetIssueMilestone(issue.Number, version); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Updated %s. Run 'atrelease roadmap' to regenerate ROADMAP.md.\n", roadmap.DefaultFile)
+}
+
+// selectPlanIssues returns the issues named by --issue, or prompts for a
+// selection from open issues in interactive mode.
+func selectPlanIssues(g *git.Git) ([]git.Issue, error) {
+	if len(planIssues) == 0 && !cfgInteractive {
+		return nil, nil
+	}
+
+	open, err := g.ListOpenIssues(planIssuesLimit)
+	if err != nil {
+		return nil, err
+	}
+	byNumber := make(map[int]git.Issue, len(open))
+	for _, issue := range open {
+		byNumber[issue.Number] = issue
+	}
+
+	if len(planIssues) > 0 {
+		var selected []git.Issue
+		for _, n := range planIssues {
+			issue, ok := byNumber[n]
+			if !ok {
+				return nil, fmt.Errorf("issue #%d is not an open issue", n)
+			}
+			selected = append(selected, issue)
+		}
+		return selected, nil
+	}
+
+	if len(open) == 0 {
+		fmt.Println("No open issues found.")
+		return nil, nil
+	}
+
+	var prompter interactive.Prompter = interactive.NewCLIPrompter()
+	if cfgJSON {
+		p := interactive.DefaultJSONPrompter()
+		p.SetCorrelationID(output.NewCorrelationID())
+		prompter = p
+	}
+
+	q := interactive.Question{
+		ID:   "plan-issues",
+		Text: "Select issues to plan for this release:",
+		Type: interactive.QuestionTypeMultiChoice,
+	}
+	for _, issue := range open {
+		desc := ""
+		if len(issue.Labels) > 0 {
+			desc = strings.Join(issue.Labels, ", ")
+		}
+		q.Options = append(q.Options, interactive.Option{
+			ID:          strconv.Itoa(issue.Number),
+			Label:       fmt.Sprintf("#%d %s", issue.Number, issue.Title),
+			Description: desc,
+		})
+	}
+
+	answer, err := prompter.Ask(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []git.Issue
+	for _, id := range answer.Selected {
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+		selected = append(selected, byNumber[n])
+	}
+	return selected, nil
+}
+
+func mustAbs(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+	return abs
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/artifacts"
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/detect"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/assistantkit/requirements"
+)
+
+var (
+	policyOutput string
+	policyKey    string
+	policyNoSign bool
+)
+
+// policyCmd represents the policy command
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Evaluate the repository against its check policy",
+	Long: `Commands for the check policy of a repository, including the shared policy
+bundle it extends.`,
+}
+
+// policyReportCmd represents the policy report command
+var policyReportCmd = &cobra.Command{
+	Use:   "report [directory]",
+	Short: "Write a signed attestation of which policy controls passed",
+	Long: `Run the checks and security checks under the resolved policy, evaluate
+the compliance controls, and write the outcome as an in-toto statement for
+compliance tooling, signed with cosign.
+
+Controls:
+  checks            All checks passed
+  tests             Tests ran and passed
+  vulnerabilities   Vulnerability scan found no known vulnerabilities
+  license           LICENSE file present
+
+Every control is reported; those listed in policy.controls (default: all)
+must pass. The command exits with status 1 if a required control fails.
+
+Examples:
+  atrelease policy report                      # Write and sign policy-report.json
+  atrelease policy report -o out/policy.json   # Choose the output file
+  atrelease policy report --key cosign.key     # Sign with a key
+  atrelease policy report --no-sign            # Unsigned, for local use`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runPolicyReport,
+}
+
+func init() {
+	policyReportCmd.Flags().StringVarP(&policyOutput, "output", "o", "policy-report.json", "Attestation file, relative to the directory")
+	policyReportCmd.Flags().StringVar(&policyKey, "key", "", "cosign signing key (default: artifacts.cosign_key; empty signs keylessly)")
+	policyReportCmd.Flags().BoolVar(&policyNoSign, "no-sign", false, "Write the attestation without signing it")
+	policyCmd.AddCommand(policyReportCmd)
+	rootCmd.AddCommand(policyCmd)
+}
+
+// PolicyPredicateType identifies the predicate of policy report attestations.
+const PolicyPredicateType = "https://github.com/plexusone/agent-team-release/policy-report@v1"
+
+// policyStatement is an in-toto v1 statement about a repository commit.
+type policyStatement struct {
+	Type          string          `json:"_type" toon:"_type"`
+	Subject       []policySubject `json:"subject" toon:"subject"`
+	PredicateType string          `json:"predicateType" toon:"predicateType"`
+	Predicate     policyPredicate `json:"predicate" toon:"predicate"`
+}
+
+type policySubject struct {
+	Name   string            `json:"name" toon:"name"`
+	Digest map[string]string `json:"digest" toon:"digest"`
+}
+
+// policyPredicate is the outcome of evaluating the policy controls.
+type policyPredicate struct {
+	Repository  string           `json:"repository,omitempty" toon:"repository,omitempty"`
+	Commit      string           `json:"commit,omitempty" toon:"commit,omitempty"`
+	Dirty       bool             `json:"dirty" toon:"dirty"` // Uncommitted changes were checked
+	Policy      policyBundle     `json:"policy" toon:"policy"`
+	Tool        string           `json:"tool" toon:"tool"`
+	EvaluatedAt string           `json:"evaluatedAt" toon:"evaluatedAt"`
+	Passed      bool             `json:"passed" toon:"passed"` // Every required control passed
+	Controls    []checks.Control `json:"controls" toon:"controls"`
+}
+
+// policyBundle identifies the policy the controls were evaluated under.
+type policyBundle struct {
+	Extends  string   `json:"extends,omitempty" toon:"extends,omitempty"`
+	Digest   string   `json:"digest,omitempty" toon:"digest,omitempty"` // SHA-256 of the bundle's config file
+	Required []string `json:"required" toon:"required"`
+}
+
+func runPolicyReport(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	// A compliance report must not fall back to defaults on a bad policy
+	cfg, err := config.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: loading policy: %v\n", err)
+		os.Exit(1)
+	}
+	checks.SetCommandPolicy(checks.ConfigCommandPolicy(cfg))
+
+	key := policyKey
+	if key == "" {
+		key = cfg.Artifacts.CosignKey
+	}
+	if !policyNoSign && !artifacts.CosignAvailable() {
+		fmt.Fprintln(os.Stderr, "Error: cosign is required to sign the report; install it or use --no-sign")
+		os.Exit(1)
+	}
+
+	// With --json, progress goes to stderr so stdout carries only the report
+	stdout := os.Stdout
+	if cfgJSON {
+		os.Stdout = os.Stderr
+	}
+
+	reqResult := requirements.EnsureRequirements([]string{"releasekit"}, requirements.NewCLIPrompter())
+	if !reqResult.AllSatisfied() {
+		fmt.Fprint(os.Stderr, requirements.FormatMissingError(reqResult))
+		os.Exit(1)
+	}
+
+	statement := policyStatement{
+		Type:          "https://in-toto.io/Statement/v1",
+		PredicateType: PolicyPredicateType,
+		Predicate: policyPredicate{
+			Tool:        "atrelease " + version,
+			EvaluatedAt: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+	statement.Predicate.Policy = newPolicyBundle(cfg, dir)
+
+	g := git.New(dir)
+	statement.Predicate.Repository, _ = g.RepositoryURL()
+	statement.Predicate.Commit, _ = g.CurrentCommit()
+	statement.Predicate.Dirty, _ = g.IsDirty()
+	name := statement.Predicate.Repository
+	if name == "" {
+		abs, _ := filepath.Abs(dir)
+		name = filepath.Base(abs)
+	}
+	subject := policySubject{Name: name, Digest: map[string]string{}}
+	if statement.Predicate.Commit != "" {
+		subject.Digest["gitCommit"] = statement.Predicate.Commit
+	}
+	statement.Subject = []policySubject{subject}
+
+	fmt.Println("▶ Running checks...")
+	detections, err := detect.DetectWith(dir, detectOptions(cfg))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error detecting languages: %v\n", err)
+	}
+	engine, err := checks.NewEngine(cfg, checks.OptionFlags{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	engine.Log = func(format string, args ...any) {
+		fmt.Printf("  "+format+"\n", args...)
+	}
+	engine.Heartbeat = true
+	results, err := engine.Run(dir, detections)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("▶ Running security checks...")
+	secChecker := &checks.SecurityChecker{}
+	security := secChecker.Check(dir, checks.SecurityOptions{
+		Verbose:      cfg.Verbose,
+		Reproducible: cfg.Security.Reproducible,
+		Packages:     reproduciblePackages(cfg),
+	})
+
+	controls := checks.EvaluateControls(results, security, cfg.Policy.Controls)
+	statement.Predicate.Controls = controls
+	statement.Predicate.Passed = checks.ControlsPassed(controls)
+
+	out := filepath.Join(dir, policyOutput)
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(out, append(data, '\n'), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+	bundle := ""
+	if !policyNoSign {
+		bundle, err = artifacts.SignBlob(out, key)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfgJSON {
+		os.Stdout = stdout
+		if err := writeStructured(statement); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		printPolicyReport(statement.Predicate, out, bundle)
+	}
+	if !statement.Predicate.Passed {
+		os.Exit(1)
+	}
+}
+
+// newPolicyBundle describes the policy in cfg, loaded from dir. The
+// bundle digest is left empty if it can't be resolved.
+func newPolicyBundle(cfg config.Config, dir string) policyBundle {
+	p := policyBundle{Extends: cfg.Extends, Required: cfg.Policy.Controls}
+	if len(p.Required) == 0 {
+		p.Required = checks.DefaultControls
+	}
+	if cfg.Extends == "" {
+		return p
+	}
+	if path, err := config.ResolvePolicy(cfg.Extends, dir); err == nil {
+		if data, err := os.ReadFile(path); err == nil {
+			sum := sha256.Sum256(data)
+			p.Digest = "sha256:" + hex.EncodeToString(sum[:])
+		}
+	}
+	return p
+}
+
+// controlIcons are the report icons for each control status.
+var controlIcons = map[string]string{
+	checks.ControlPassed:       "✓",
+	checks.ControlFailed:       "✗",
+	checks.ControlNotEvaluated: "⊘",
+}
+
+func printPolicyReport(p policyPredicate, out, bundle string) {
+	fmt.Println()
+	fmt.Println("=== Policy Report ===")
+	if p.Policy.Extends != "" {
+		fmt.Printf("Policy: %s", p.Policy.Extends)
+		if p.Policy.Digest != "" {
+			fmt.Printf(" (%s)", shortDigest(p.Policy.Digest))
+		}
+		fmt.Println()
+	}
+	if p.Commit != "" {
+		fmt.Printf("Commit: %s", p.Commit[:min(len(p.Commit), 12)])
+		if p.Dirty {
+			fmt.Print(" (uncommitted changes)")
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	width := 0
+	for _, c := range p.Controls {
+		width = max(width, len(c.ID))
+	}
+	var failed []string
+	for _, c := range p.Controls {
+		note := ""
+		if !c.Required {
+			note = " (optional)"
+		} else if c.Status != checks.ControlPassed {
+			failed = append(failed, c.ID)
+		}
+		fmt.Printf("%s %-*s  %s%s\n", controlIcons[c.Status], width, c.ID, c.Description, note)
+		if c.Status != checks.ControlPassed {
+			for _, e := range c.Evidence {
+				fmt.Printf("    %s\n", firstLine(e))
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Wrote %s\n", out)
+	if bundle != "" {
+		fmt.Printf("Signed %s\n", bundle)
+	}
+	fmt.Println()
+	if len(failed) > 0 {
+		fmt.Printf("Required policy controls failed: %s\n", strings.Join(failed, ", "))
+		return
+	}
+	fmt.Println("All required policy controls passed!")
+}
+
+// shortDigest abbreviates "sha256:<hex>" to 12 hex digits.
+func shortDigest(d string) string {
+	algo, hex, ok := strings.Cut(d, ":")
+	if !ok || len(hex) <= 12 {
+		return d
+	}
+	return algo + ":" + hex[:12]
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
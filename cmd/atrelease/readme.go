@@ -55,7 +55,10 @@ func runReadme(cmd *cobra.Command, args []string) {
 	}
 
 	// Load configuration
-	cfg, _ := config.Load(dir)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		exitError(err)
+	}
 
 	fmt.Println("=== README ===")
 	fmt.Println()
@@ -68,7 +71,7 @@ func runReadme(cmd *cobra.Command, args []string) {
 		Config:  &cfg,
 	}
 
-	result := action.Run(dir, opts)
+	result := actions.Run(action, dir, opts)
 
 	if result.Output != "" {
 		fmt.Println(result.Output)
@@ -4,18 +4,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/toon-format/toon-go"
 
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/interactive"
 	"github.com/plexusone/agent-team-release/pkg/workflow"
 )
 
 // Release command flags
 var (
-	releaseDryRun     bool
-	releaseSkipChecks bool
-	releaseSkipCI     bool
+	releaseDryRun       bool
+	releaseSkipChecks   bool
+	releaseSkipCI       bool
+	releasePR           bool
+	releaseAutoMerge    bool
+	releaseForce        bool
+	releaseMergeTimeout time.Duration
 )
 
 // releaseCmd represents the release command
@@ -26,7 +33,7 @@ var releaseCmd = &cobra.Command{
 
 The release workflow includes:
   1. Validate version format and check it doesn't exist
-  2. Ensure working directory is clean
+  2. Ensure working directory is clean and the branch is not behind its remote
   3. Run validation checks (build, test, lint, format)
   4. Generate/update changelog
   5. Update roadmap
@@ -34,12 +41,29 @@ The release workflow includes:
   7. Push to remote
   8. Wait for CI to pass
   9. Create and push release tag
+ 10. Cross-compile release archives into dist/ (only when build.main is
+     configured)
+ 11. Checksum built artifacts, attest provenance, and upload them to the
+     GitHub release (only when there are archives or artifacts.paths)
+
+With --pr (or release.pull_request in .releaseagent.yaml), nothing is pushed
+to the current branch. After step 5 the workflow instead:
+  6. Creates a release/<version> branch
+  7. Creates the release commit and pushes the branch
+  8. Opens a PR with the validation report as its description
+  9. Enables auto-merge, or adds the PR to the merge queue (--auto-merge)
+ 10. Waits for the PR checks, and the merge queue checks when queued
+ 11. Waits for the PR to be merged (--merge-timeout)
+ 12. Tags the merge commit and pushes the tag, then builds and publishes
+     artifacts
 
 Examples:
   atrelease release v0.3.0
   atrelease release v0.3.0 --dry-run     # Preview without changes
   atrelease release v0.3.0 --skip-ci     # Don't wait for CI
-  atrelease release v0.3.0 --skip-checks # Skip validation`,
+  atrelease release v0.3.0 --skip-checks # Skip validation
+  atrelease release v0.3.0 --pr          # Release through a pull request
+  atrelease release v0.3.0 --auto-merge  # PR merged by auto-merge or the merge queue`,
 	Args: cobra.ExactArgs(1),
 	Run:  runRelease,
 }
@@ -48,6 +72,10 @@ func init() {
 	releaseCmd.Flags().BoolVar(&releaseDryRun, "dry-run", false, "Preview what would be done without making changes")
 	releaseCmd.Flags().BoolVar(&releaseSkipChecks, "skip-checks", false, "Skip validation checks (dangerous)")
 	releaseCmd.Flags().BoolVar(&releaseSkipCI, "skip-ci", false, "Don't wait for CI to pass before tagging")
+	releaseCmd.Flags().BoolVar(&releasePR, "pr", false, "Open a release PR from release/<version> and tag its merge commit")
+	releaseCmd.Flags().BoolVar(&releaseAutoMerge, "auto-merge", false, "Enable auto-merge or the merge queue on the release PR (implies --pr)")
+	releaseCmd.Flags().BoolVar(&releaseForce, "force", false, "Release during a freeze window, recording the override in .atrelease/audit.jsonl")
+	releaseCmd.Flags().DurationVar(&releaseMergeTimeout, "merge-timeout", workflow.DefaultMergeTimeout, "How long to wait for the release PR to be merged")
 
 	rootCmd.AddCommand(releaseCmd)
 }
@@ -68,6 +96,17 @@ func runRelease(cmd *cobra.Command, args []string) {
 	ctx := workflow.NewContext(dir, version)
 	ctx.SkipChecks = releaseSkipChecks
 	ctx.SkipCI = releaseSkipCI
+	ctx.Offline = cfgOffline
+	ctx.Force = releaseForce
+	ctx.MergeTimeout = releaseMergeTimeout
+	if cfgInteractive {
+		ctx.Prompter = interactive.NewCLIPrompter()
+		if cfgJSON {
+			p := interactive.DefaultJSONPrompter()
+			p.SetCorrelationID(ctx.CorrelationID)
+			ctx.Prompter = p
+		}
+	}
 
 	// Create runner
 	runner := workflow.NewRunner()
@@ -78,6 +117,14 @@ func runRelease(cmd *cobra.Command, args []string) {
 
 	// Create and run the release workflow
 	wf := workflow.ReleaseWorkflow(version)
+	cfg, err := config.Load(dir)
+	if err != nil {
+		exitError(err)
+	}
+	ctx.AutoMerge = releaseAutoMerge || cfg.Release.AutoMerge
+	if releasePR || ctx.AutoMerge || cfg.Release.PullRequest {
+		wf = workflow.ReleasePRWorkflow(version)
+	}
 	result := runner.Run(wf, ctx)
 
 	// Print output
@@ -111,6 +158,10 @@ func runRelease(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if result.Cancelled {
+		// Conventional exit code for termination by SIGINT
+		os.Exit(130)
+	}
 	if !result.Success {
 		os.Exit(1)
 	}
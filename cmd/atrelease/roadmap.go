@@ -7,11 +7,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/plexusone/agent-team-release/pkg/actions"
+	"github.com/plexusone/agent-team-release/pkg/config"
 )
 
 // Roadmap command flags
 var (
-	roadmapDryRun bool
+	roadmapDryRun     bool
+	roadmapSyncIssues bool
 )
 
 // roadmapCmd represents the roadmap command
@@ -23,18 +25,26 @@ var roadmapCmd = &cobra.Command{
 This command validates ROADMAP.json and regenerates ROADMAP.md
 with deterministic formatting.
 
+With --sync-issues (or roadmap.sync_issues in .releaseagent.yaml), first
+syncs ROADMAP.json with GitHub issues: outstanding items without an issue
+get one (labeled "roadmap" by default), and items whose issue has closed
+are marked completed. Items created by 'atrelease plan' are linked by
+their issue-N ID. Requires gh.
+
 Requires sroadmap to be installed:
   go install github.com/grokify/sroadmap/cmd/sroadmap@latest
 
 Examples:
   atrelease roadmap              # Regenerate ROADMAP.md
-  atrelease roadmap --dry-run    # Show stats without generating`,
+  atrelease roadmap --dry-run    # Show stats without generating
+  atrelease roadmap --sync-issues --dry-run  # Preview issue sync`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runRoadmap,
 }
 
 func init() {
 	roadmapCmd.Flags().BoolVar(&roadmapDryRun, "dry-run", false, "Show what would be done without making changes")
+	roadmapCmd.Flags().BoolVar(&roadmapSyncIssues, "sync-issues", false, "Sync ROADMAP.json items with GitHub issues")
 
 	rootCmd.AddCommand(roadmapCmd)
 }
@@ -52,16 +62,26 @@ func runRoadmap(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Load configuration
+	cfg, err := config.Load(dir)
+	if err != nil {
+		exitError(err)
+	}
+
 	fmt.Println("=== Roadmap ===")
 	fmt.Println()
 
-	action := &actions.RoadmapAction{}
+	action := &actions.RoadmapAction{
+		SyncIssues: roadmapSyncIssues || cfg.Roadmap.SyncIssues,
+		IssueLabel: cfg.Roadmap.IssueLabel,
+	}
 	opts := actions.Options{
 		DryRun:  roadmapDryRun,
 		Verbose: cfgVerbose,
+		Config:  &cfg,
 	}
 
-	result := action.Run(dir, opts)
+	result := actions.Run(action, dir, opts)
 
 	if result.Output != "" {
 		fmt.Println(result.Output)
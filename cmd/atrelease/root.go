@@ -1,9 +1,18 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/toon-format/toon-go"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/output"
 )
 
 // Version information (set via ldflags)
@@ -29,6 +38,8 @@ var (
 	cfgInteractive bool
 	cfgJSON        bool   // Enable structured output (TOON by default)
 	cfgFormat      string // Output format: "toon" or "json"
+	cfgTrace       bool   // Log every git/gh command to stderr
+	cfgOffline     bool   // Skip network-dependent steps and checks
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -49,24 +60,74 @@ language detection and monorepo support.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
+	registerCompletions()
 	err := rootCmd.Execute()
 	if err != nil {
+		if cfgJSON {
+			_ = writeStructured(output.NewErrorMessage(err, true, ""))
+		}
 		os.Exit(1)
 	}
 }
 
+// exitError reports err and exits with status 1. With --json it writes a
+// fatal error message with the error's code (see output.CodeOf) to
+// stdout; otherwise it prints the error to stderr.
+func exitError(err error) {
+	if cfgJSON {
+		_ = writeStructured(output.NewErrorMessage(err, true, ""))
+	} else {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+	os.Exit(1)
+}
+
 func init() {
 	// Global flags available to all subcommands
 	rootCmd.PersistentFlags().BoolVarP(&cfgVerbose, "verbose", "v", false, "Show detailed output")
 	rootCmd.PersistentFlags().BoolVarP(&cfgInteractive, "interactive", "i", false, "Enable interactive mode")
 	rootCmd.PersistentFlags().BoolVar(&cfgJSON, "json", false, "Enable structured output for LLM integration (TOON format by default)")
 	rootCmd.PersistentFlags().StringVar(&cfgFormat, "format", "toon", "Output format when --json is enabled: toon (default) or json")
+	rootCmd.PersistentFlags().BoolVar(&cfgTrace, "trace", false, "Log every git and gh command with duration and exit code (or set "+git.TraceEnv+"=1)")
+	rootCmd.PersistentFlags().BoolVar(&cfgOffline, "offline", false, "Skip steps and checks that need the network, reporting them as skipped")
+
+	cobra.OnInitialize(initTrace, initOffline, initGitHub)
 
 	// Add subcommands
 	rootCmd.AddCommand(checkCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
+// initTrace enables git/gh command tracing from --trace or the environment.
+func initTrace() {
+	if env := os.Getenv(git.TraceEnv); !cfgTrace && (env == "" || env == "0" || env == "false") {
+		return
+	}
+	git.Tracer = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// initOffline stops checks and policy fetches from using the network when
+// --offline is set.
+func initOffline() {
+	checks.SetOffline(cfgOffline)
+	config.SetOffline(cfgOffline)
+}
+
+// initGitHub points git and gh at the GitHub Enterprise Server instance in
+// network.github_url or, failing that, GH_HOST.
+func initGitHub() {
+	cfg, err := config.Load(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+	}
+	switch {
+	case cfg.Network.GitHubURL != "":
+		git.BaseURL = cfg.Network.GitHubURL
+	case os.Getenv("GH_HOST") != "" && os.Getenv("GH_HOST") != git.DefaultHost:
+		git.BaseURL = "https://" + os.Getenv("GH_HOST")
+	}
+}
+
 // GetOutputFormat returns the configured output format.
 func GetOutputFormat() OutputFormat {
 	if cfgFormat == "json" {
@@ -74,3 +135,26 @@ func GetOutputFormat() OutputFormat {
 	}
 	return OutputFormatTOON
 }
+
+// writeStructured writes v to stdout as TOON or JSON based on --format.
+func writeStructured(v any) error {
+	return writeFormatted(v, GetOutputFormat())
+}
+
+// writeFormatted writes v to stdout in the given format.
+func writeFormatted(v any, format OutputFormat) error {
+	if format == OutputFormatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(v); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		return nil
+	}
+	data, err := toon.Marshal(v, toon.WithIndent(2))
+	if err != nil {
+		return fmt.Errorf("encoding TOON: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
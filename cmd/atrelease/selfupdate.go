@@ -0,0 +1,302 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+const (
+	// releaseRepo is the GitHub repository releases are published to.
+	releaseRepo = "plexusone/agent-team-release"
+	// releaseProject is the GoReleaser project name used in archive names.
+	releaseProject = "agent-team-release"
+)
+
+var selfUpdateForce bool
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update atrelease to the latest release",
+	Long: `Download the latest atrelease release from GitHub and replace the
+running binary.
+
+The archive checksum is verified against the release checksums.txt before
+the binary is replaced. Development builds are not updated unless --force
+is given.`,
+	RunE: runSelfUpdate,
+}
+
+func init() {
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Update even if already up to date or running a development build")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
+
+// githubRelease is the subset of the GitHub release API response we use.
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// releaseHTTPClient returns the client for GitHub release requests, using
+// the network settings of the configuration in the current directory.
+func releaseHTTPClient() (*http.Client, error) {
+	cfg, err := config.Load(".")
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Network.HTTPClient(60 * time.Second)
+}
+
+// fetchLatestRelease returns the latest published GitHub release.
+func fetchLatestRelease() (*githubRelease, error) {
+	url := "https://api.github.com/repos/" + releaseRepo + "/releases/latest"
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client, err := releaseHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to query latest release: %s", resp.Status)
+	}
+
+	var release githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse latest release: %w", err)
+	}
+	return &release, nil
+}
+
+func (r *githubRelease) assetURL(name string) string {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a.URL
+		}
+	}
+	return ""
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	if version == "dev" && !selfUpdateForce {
+		return fmt.Errorf("refusing to replace a development build; use --force to override")
+	}
+
+	release, err := fetchLatestRelease()
+	if err != nil {
+		return err
+	}
+	if !selfUpdateForce && !isNewerVersion(release.TagName, version) {
+		fmt.Printf("Already up to date (%s)\n", version)
+		return nil
+	}
+
+	archiveName := releaseArchiveName(release.TagName, runtime.GOOS, runtime.GOARCH)
+	archiveURL := release.assetURL(archiveName)
+	if archiveURL == "" {
+		return fmt.Errorf("release %s has no archive for %s/%s (%s)", release.TagName, runtime.GOOS, runtime.GOARCH, archiveName)
+	}
+	checksumsURL := release.assetURL("checksums.txt")
+	if checksumsURL == "" {
+		return fmt.Errorf("release %s has no checksums.txt", release.TagName)
+	}
+
+	fmt.Printf("Downloading %s...\n", archiveName)
+	archive, err := download(archiveURL)
+	if err != nil {
+		return err
+	}
+	checksums, err := download(checksumsURL)
+	if err != nil {
+		return err
+	}
+	if err := verifyChecksum(archive, archiveName, checksums); err != nil {
+		return err
+	}
+
+	binaryName := "atrelease"
+	if runtime.GOOS == "windows" {
+		binaryName += ".exe"
+	}
+	binary, err := extractBinary(archive, archiveName, binaryName)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate running binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to resolve running binary: %w", err)
+	}
+	if err := replaceBinary(exe, binary); err != nil {
+		return err
+	}
+
+	fmt.Printf("Updated %s: %s -> %s\n", exe, version, release.TagName)
+	return nil
+}
+
+// releaseArchiveName mirrors the archive name_template in .goreleaser.yaml.
+func releaseArchiveName(tag, goos, goarch string) string {
+	arch := goarch
+	if goarch == "amd64" {
+		arch = "x86_64"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s%s", releaseProject, strings.TrimPrefix(tag, "v"), goos, arch, ext)
+}
+
+func download(url string) ([]byte, error) {
+	client, err := releaseHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verifyChecksum checks data against the entry for name in a checksums.txt file.
+func verifyChecksum(data []byte, name string, checksums []byte) error {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == name {
+			sum := sha256.Sum256(data)
+			if hex.EncodeToString(sum[:]) != fields[0] {
+				return fmt.Errorf("checksum mismatch for %s", name)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no checksum found for %s", name)
+}
+
+// extractBinary returns the named file from a .tar.gz or .zip archive.
+func extractBinary(archive []byte, archiveName, binaryName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) != binaryName {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer func() { _ = rc.Close() }()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s not found in %s", binaryName, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", archiveName, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", archiveName, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binaryName {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", binaryName, archiveName)
+}
+
+// replaceBinary atomically replaces the file at exe with data.
+func replaceBinary(exe string, data []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", exe, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".atrelease-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write update: %w", err)
+	}
+	if err := os.Chmod(tmpName, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		// A running executable cannot be overwritten on Windows, but it can be renamed.
+		old := exe + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return fmt.Errorf("failed to move old binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmpName, exe); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return nil
+}
+
+// isNewerVersion reports whether latest is a higher semantic version than
+// current. A current version that isn't one, such as a development build,
+// is older than any release.
+func isNewerVersion(latest, current string) bool {
+	return semver.IsValid(latest) && semver.Compare(latest, current) > 0
+}
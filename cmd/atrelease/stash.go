@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// withStashedChanges stashes unstaged and untracked changes in dir, runs fn
+// against the staged state, and restores the stash afterwards, including
+// when fn fails or the run is interrupted. It returns fn's exit code.
+func withStashedChanges(dir string, fn func() int) int {
+	g := git.New(dir)
+	sha, err := g.StashUnstaged("atrelease: stashed while running checks")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if sha == "" {
+		return fn()
+	}
+	fmt.Println("Stashed unstaged changes; checking staged changes only.")
+	fmt.Println()
+
+	restore := func() bool {
+		if err := g.RestoreStash(sha); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+		fmt.Println("Restored stashed changes.")
+		return true
+	}
+
+	// Restore on Ctrl-C too; child processes receive the signal and exit
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-signals; ok {
+			fmt.Fprintln(os.Stderr)
+			restore()
+			os.Exit(130)
+		}
+	}()
+
+	code := fn()
+	signal.Stop(signals)
+	if !restore() && code == 0 {
+		code = 1
+	}
+	return code
+}
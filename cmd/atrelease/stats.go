@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/runlog"
+)
+
+var (
+	statsLimit int
+	statsLast  int
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [directory]",
+	Short: "Summarize local check run history",
+	Long: `Summarize the check runs recorded in .atrelease/history.jsonl: how many
+runs passed, the average run time, how often language detection was served
+from the cache, the slowest checks, and the flakiest checks (those that
+flip between passing and failing). Use it to decide what to optimize, skip,
+or offload to a remote runner.
+
+History is recorded locally by check and never sent anywhere. Disable it
+with history: false in .releaseagent.yaml.
+
+Examples:
+  atrelease stats              # Summarize all recorded runs
+  atrelease stats --last 20    # Only the 20 most recent runs
+  atrelease stats --json       # TOON output`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runStats,
+}
+
+func init() {
+	statsCmd.Flags().IntVarP(&statsLimit, "limit", "n", 5, "Number of slowest and flakiest checks to list")
+	statsCmd.Flags().IntVar(&statsLast, "last", 0, "Only summarize the most recent runs (0 for all)")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	runs, err := runlog.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+	if statsLast > 0 && len(runs) > statsLast {
+		runs = runs[len(runs)-statsLast:]
+	}
+	stats := runlog.Summarize(runs, statsLimit)
+
+	if cfgJSON {
+		if err := writeStructured(stats); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	printStats(stats)
+}
+
+func printStats(s runlog.Stats) {
+	if s.Runs == 0 {
+		fmt.Println("No runs recorded yet. Run atrelease check to start recording.")
+		return
+	}
+
+	fmt.Println("=== Run History ===")
+	fmt.Printf("Runs:             %d since %s\n", s.Runs, s.Since.Local().Format("2006-01-02"))
+	fmt.Printf("Passed:           %d (%.0f%%)\n", s.Passed, percent(s.Passed, s.Runs))
+	fmt.Printf("Average run time: %s\n", formatMs(s.AvgDurationMs))
+	fmt.Printf("Detection cached: %d (%.0f%%)\n", s.DetectCached, percent(s.DetectCached, s.Runs))
+
+	if len(s.Slowest) > 0 {
+		fmt.Println()
+		fmt.Println("Slowest checks (average):")
+		for _, c := range s.Slowest {
+			fmt.Printf("  %-10s %s\n", formatMs(c.AvgDurationMs), checkLabel(c))
+		}
+	}
+
+	fmt.Println()
+	if len(s.Flakiest) == 0 {
+		fmt.Println("No flaky checks.")
+		return
+	}
+	fmt.Println("Flakiest checks:")
+	for _, c := range s.Flakiest {
+		fmt.Printf("  %3.0f%%  %s (%d flips, %d failures in %d runs)\n",
+			c.Flakiness*100, checkLabel(c), c.Flips, c.Failures, c.Runs)
+	}
+}
+
+// checkLabel names a check with its detection path when it has one.
+func checkLabel(c runlog.CheckStats) string {
+	if c.Path == "" || c.Path == "." {
+		return c.Name
+	}
+	return fmt.Sprintf("%s [%s]", c.Name, c.Path)
+}
+
+func formatMs(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d >= time.Second {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.String()
+}
+
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/roadmap"
+)
+
+var statusNoCI bool
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [directory]",
+	Short: "Show a pre-release snapshot of the repository",
+	Long: `Show a one-screen pre-release situational report:
+
+  - Current branch, HEAD commit, and working tree state
+  - Commits since the last tag, grouped by conventional commit type
+  - CI status of HEAD (requires gh)
+  - Suggested next version based on the commits
+  - Outstanding ROADMAP.json items
+
+Examples:
+  atrelease status              # Status of current directory
+  atrelease status --no-ci      # Skip the CI query
+  atrelease status --json       # Structured output`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runStatus,
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusNoCI, "no-ci", false, "Don't query CI status")
+	rootCmd.AddCommand(statusCmd)
+}
+
+// statusReport is the structured form of the status command output.
+type statusReport struct {
+	Branch             string         `json:"branch" toon:"branch"`
+	Commit             string         `json:"commit" toon:"commit"`
+	Dirty              bool           `json:"dirty" toon:"dirty"`
+	LatestTag          string         `json:"latest_tag,omitempty" toon:"latest_tag,omitempty"`
+	CommitsSinceTag    int            `json:"commits_since_tag" toon:"commits_since_tag"`
+	CommitsByType      map[string]int `json:"commits_by_type,omitempty" toon:"commits_by_type,omitempty"`
+	BreakingChanges    int            `json:"breaking_changes" toon:"breaking_changes"`
+	SuggestedVersion   string         `json:"suggested_version,omitempty" toon:"suggested_version,omitempty"`
+	CIStatus           string         `json:"ci_status" toon:"ci_status"`
+	RoadmapOutstanding []roadmap.Item `json:"roadmap_outstanding,omitempty" toon:"roadmap_outstanding,omitempty"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
+		os.Exit(1)
+	}
+
+	report, err := buildStatusReport(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if cfgJSON {
+		if err := writeStructured(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	printStatusReport(report)
+}
+
+func buildStatusReport(dir string) (*statusReport, error) {
+	g := git.New(dir)
+	report := &statusReport{CIStatus: "unknown"}
+
+	var err error
+	if report.Branch, err = g.CurrentBranch(); err != nil {
+		return nil, fmt.Errorf("not a git repository: %w", err)
+	}
+	report.Commit, _ = g.ShortCommit()
+	report.Dirty, _ = g.IsDirty()
+
+	// A missing tag just means every commit is unreleased.
+	report.LatestTag, _ = g.LatestTag()
+
+	commits, err := g.CommitsSince(report.LatestTag)
+	if err != nil {
+		return nil, err
+	}
+	report.CommitsSinceTag = len(commits)
+	report.CommitsByType = make(map[string]int)
+	for _, c := range commits {
+		report.CommitsByType[c.Type]++
+		if c.Breaking {
+			report.BreakingChanges++
+		}
+	}
+	if len(commits) > 0 {
+		report.SuggestedVersion, _ = git.SuggestNextVersion(report.LatestTag, commits)
+	}
+
+	if cfgOffline {
+		report.CIStatus = "skipped (offline)"
+	} else if !statusNoCI {
+		if ci, err := g.GetCIStatus(""); err == nil {
+			report.CIStatus = ci.State
+		}
+	}
+
+	if r, err := roadmap.Load(filepath.Join(dir, roadmap.DefaultFile)); err == nil {
+		report.RoadmapOutstanding = r.Outstanding()
+	}
+
+	return report, nil
+}
+
+func printStatusReport(r *statusReport) {
+	state := "clean"
+	if r.Dirty {
+		state = "dirty"
+	}
+	fmt.Println("=== Release Status ===")
+	fmt.Println()
+	fmt.Printf("Branch:      %s @ %s (%s)\n", r.Branch, r.Commit, state)
+	if r.LatestTag != "" {
+		fmt.Printf("Latest tag:  %s\n", r.LatestTag)
+	} else {
+		fmt.Println("Latest tag:  (none)")
+	}
+	fmt.Printf("CI:          %s\n", r.CIStatus)
+	if r.SuggestedVersion != "" {
+		fmt.Printf("Next:        %s (suggested)\n", r.SuggestedVersion)
+	}
+
+	fmt.Println()
+	fmt.Printf("Commits since tag: %d", r.CommitsSinceTag)
+	if r.BreakingChanges > 0 {
+		fmt.Printf(" (%d breaking)", r.BreakingChanges)
+	}
+	fmt.Println()
+	types := make([]string, 0, len(r.CommitsByType))
+	for t := range r.CommitsByType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if r.CommitsByType[types[i]] != r.CommitsByType[types[j]] {
+			return r.CommitsByType[types[i]] > r.CommitsByType[types[j]]
+		}
+		return types[i] < types[j]
+	})
+	for _, t := range types {
+		fmt.Printf("  %-10s %d\n", t, r.CommitsByType[t])
+	}
+
+	if len(r.RoadmapOutstanding) > 0 {
+		fmt.Println()
+		fmt.Printf("Outstanding roadmap items: %d\n", len(r.RoadmapOutstanding))
+		for _, item := range r.RoadmapOutstanding {
+			version := ""
+			if item.Version != "" {
+				version = " (" + item.Version + ")"
+			}
+			fmt.Printf("  [%s] %s%s\n", item.Status, item.Title, version)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/actions"
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// Tag command flags
+var (
+	tagDryRun       bool
+	tagSign         bool
+	tagNoPush       bool
+	tagTemplateFile string
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag <version>",
+	Short: "Create and push a release tag",
+	Long: `Create and push an annotated release tag at HEAD.
+
+Use this for the tag-only path, when release commits are already pushed.
+Before creating the tag, verifies it does not already exist locally or on
+the remote.
+
+The annotation is rendered from a Go text/template. The template is taken
+from --template-file, then tag.template in .releaseagent.yaml, then the
+built-in default, which embeds the CHANGELOG.json highlights for the version.
+
+Template fields:
+  .Version      Tag being created
+  .PreviousTag  Latest existing tag
+  .Date         Current date (YYYY-MM-DD)
+  .Highlights   CHANGELOG.json highlights for the version
+  .Commits      Commits since .PreviousTag (.Hash, .Subject, .Type, .Breaking)
+
+Examples:
+  atrelease tag v1.2.0               # Create and push tag
+  atrelease tag v1.2.0 --sign        # Signed tag
+  atrelease tag v1.2.0 --dry-run     # Preview the annotation
+  atrelease tag v1.2.0 --no-push     # Local tag only`,
+	Args: cobra.ExactArgs(1),
+	Run:  runTag,
+}
+
+func init() {
+	tagCmd.Flags().BoolVar(&tagDryRun, "dry-run", false, "Show the annotation without creating the tag")
+	tagCmd.Flags().BoolVar(&tagSign, "sign", false, "Create a signed tag (default from tag.sign in config)")
+	tagCmd.Flags().BoolVar(&tagNoPush, "no-push", false, "Create the tag locally without pushing")
+	tagCmd.Flags().StringVar(&tagTemplateFile, "template-file", "", "File containing the annotation template")
+
+	rootCmd.AddCommand(tagCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) {
+	dir := "."
+
+	cfg, err := config.Load(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	action := &actions.TagAction{
+		Sign:     cfg.Tag.Sign,
+		Template: cfg.Tag.Template,
+		NoPush:   tagNoPush,
+	}
+	if cmd.Flags().Changed("sign") {
+		action.Sign = tagSign
+	}
+	if tagTemplateFile != "" {
+		data, err := os.ReadFile(tagTemplateFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: reading template: %v\n", err)
+			os.Exit(1)
+		}
+		action.Template = string(data)
+	}
+
+	fmt.Println("=== Tag ===")
+	fmt.Println()
+
+	result := actions.Run(action, dir, actions.Options{
+		DryRun:  tagDryRun,
+		Version: args[0],
+		Verbose: cfgVerbose,
+		Config:  &cfg,
+	})
+
+	if result.Output != "" {
+		fmt.Println(result.Output)
+	}
+
+	if !result.Success {
+		if result.Error != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", result.Error)
+		}
+		os.Exit(1)
+	}
+}
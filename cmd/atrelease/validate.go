@@ -7,32 +7,41 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 
 	multiagentspec "github.com/plexusone/multi-agent-spec/sdk/go"
 	"github.com/spf13/cobra"
 
+	"github.com/plexusone/agent-team-release/pkg/changelog"
 	"github.com/plexusone/agent-team-release/pkg/checks"
 	"github.com/plexusone/agent-team-release/pkg/config"
 	"github.com/plexusone/agent-team-release/pkg/detect"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 	"github.com/plexusone/agent-team-release/pkg/report"
+	"github.com/plexusone/agent-team-release/pkg/telemetry"
 	"github.com/plexusone/assistantkit/requirements"
 )
 
 // Validate command flags
 var (
-	validateVersion  string
-	validateSkipPM   bool
-	validateSkipQA   bool
-	validateSkipDocs bool
-	validateSkipSec  bool
-	validateFormat   string
+	validateVersion    string
+	validateSkipPM     bool
+	validateSkipQA     bool
+	validateSkipDocs   bool
+	validateSkipSec    bool
+	validateFormat     string
+	validateRecurse    bool
+	validateStrict     bool
+	validateCheckRun   bool
+	validateSequential bool
 )
 
 // validateCmd represents the validate command
 var validateCmd = &cobra.Command{
-	Use:   "validate [directory]",
+	Use:   "validate [directory]...",
 	Short: "Run comprehensive release validation",
 	Long: `Run comprehensive release validation across all areas of responsibility.
 
@@ -45,14 +54,22 @@ Validation Areas:
 
 The PM agent runs first and produces the version recommendation. Other agents depend on PM.
 
+Several directories can be validated in one run, for example the projects of
+a meta-repo. Each gets its own report, followed by a combined GO/NO-GO
+summary grouped by directory. With --recursive, every directory under the
+arguments where a language is detected is validated independently.
+
 Examples:
   atrelease validate                    # Validate current directory
+  atrelease validate svc/api svc/web    # Validate several projects
+  atrelease validate --recursive        # Validate every detected project
   atrelease validate --version v0.2.0   # Include version-specific checks
   atrelease validate --skip-qa          # Skip QA checks
   atrelease validate --format team      # Team status report format
+  atrelease validate --strict           # Warnings block the release
+  atrelease validate --check-run        # Publish the report as a GitHub check run
   atrelease validate -v                 # Verbose output`,
-	Args: cobra.MaximumNArgs(1),
-	Run:  runValidate,
+	Run: runValidate,
 }
 
 func init() {
@@ -62,33 +79,56 @@ func init() {
 	validateCmd.Flags().BoolVar(&validateSkipDocs, "skip-docs", false, "Skip documentation checks")
 	validateCmd.Flags().BoolVar(&validateSkipSec, "skip-security", false, "Skip security checks")
 	validateCmd.Flags().StringVar(&validateFormat, "format", "default", "Output format (default, team)")
+	validateCmd.Flags().BoolVarP(&validateRecurse, "recursive", "r", false, "Validate each directory where a language is detected independently")
+	validateCmd.Flags().BoolVar(&validateStrict, "strict", false, "Treat warnings as failures, making their areas NO-GO")
+	validateCmd.Flags().BoolVar(&validateCheckRun, "check-run", false, "Publish the report as a GitHub check run on the current commit")
+	validateCmd.Flags().BoolVar(&validateSequential, "sequential", false, "Run the validation areas one at a time instead of concurrently")
 
 	rootCmd.AddCommand(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) {
-	// Get directory to validate
-	dir := "."
-	if len(args) > 0 {
-		dir = args[0]
+	dirs, err := resolveDirs(args, validateRecurse)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Make sure directory exists
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", dir)
-		os.Exit(1)
+	reports := make([]*checks.ValidationReport, len(dirs))
+	for i, t := range dirs {
+		if i > 0 {
+			fmt.Println()
+		}
+		if len(dirs) > 1 {
+			fmt.Printf("=== Validating %s ===\n", t.Path)
+			fmt.Println()
+		}
+		reports[i] = validateDir(t)
+		if validateCheckRun {
+			publishCheckRun(t, reports[i], len(dirs) > 1)
+		}
 	}
 
-	// Load configuration
-	cfg, err := config.Load(dir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: error loading config: %v\n", err)
+	if len(dirs) > 1 {
+		printValidationSummaries(dirs, reports)
 	}
 
-	// Override config with flags
-	if cfgVerbose {
-		cfg.Verbose = true
+	// Exit with error if validation failed
+	for _, vr := range reports {
+		if !vr.IsGo() {
+			os.Exit(1)
+		}
 	}
+}
+
+// validateDir runs every validation area in a target directory and prints
+// its report.
+func validateDir(t targetDir) *checks.ValidationReport {
+	dir := t.Path
+	start := time.Now()
+	cfg := loadConfig(t)
+	checks.SetCommandPolicy(checks.ConfigCommandPolicy(cfg))
+	checks.SetReportConfig(cfg.Report)
 
 	// Create validation report
 	validationReport := &checks.ValidationReport{
@@ -96,89 +136,130 @@ func runValidate(cmd *cobra.Command, args []string) {
 	}
 
 	// Detect languages for QA checks
-	detections, err := detect.Detect(dir)
+	detections, err := detect.DetectWith(dir, detectOptions(cfg))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: error detecting languages: %v\n", err)
 	}
 
-	fmt.Println("╔══════════════════════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                       RELEASE VALIDATION STARTING                            ║")
-	fmt.Println("╚══════════════════════════════════════════════════════════════════════════════╝")
+	checks.PrintBanner("RELEASE VALIDATION STARTING")
 	fmt.Println()
 
-	// PM Area (runs first - other agents depend on PM)
-	if !validateSkipPM {
-		fmt.Println("▶ Running PM validation...")
-		pmChecker := &checks.PMChecker{}
-		pmResults := pmChecker.Check(dir, checks.PMOptions{
-			Version: validateVersion,
-			Verbose: cfg.Verbose,
-		})
-		pmStatus := checks.ComputeAreaStatus(pmResults)
-		validationReport.Areas = append(validationReport.Areas, checks.AreaResult{
-			Area:    checks.AreaPM,
-			Status:  pmStatus,
-			Results: pmResults,
-		})
+	// The PM, Documentation, and Release checks share one parse
+	cl := changelog.LoadShared(dir)
 
-		if pmStatus == checks.StatusNoGo {
-			fmt.Println("  ⚠ PM validation failed - other agents will still run but release is blocked")
+	areas := cfg.Validate.Areas
+	skip := func(area checks.ValidationArea, flag string, flagSet bool) string {
+		reason := checks.AreaSkipReason(area, cfg, flag, flagSet)
+		if reason != "" {
+			fmt.Printf("⊘ Skipping %s validation (%s)\n", area, reason)
 		}
+		return reason
 	}
 
-	// QA Area
-	if !validateSkipQA {
-		fmt.Println("▶ Running QA validation...")
-		qaResults := runQAChecks(dir, detections, &cfg)
-		validationReport.Areas = append(validationReport.Areas, checks.AreaResult{
-			Area:    checks.AreaQA,
-			Status:  checks.ComputeAreaStatus(qaResults),
-			Results: qaResults,
-		})
+	// Prompt for releasekit before the areas start, not while they print
+	var qaUnavailable []checks.Result
+	pmSkip := skip(checks.AreaPM, "--skip-pm", validateSkipPM)
+	qaSkip := skip(checks.AreaQA, "--skip-qa", validateSkipQA)
+	if qaSkip == "" {
+		qaUnavailable = ensureReleasekit()
 	}
 
-	// Documentation Area
-	if !validateSkipDocs {
-		fmt.Println("▶ Running Documentation validation...")
-		docChecker := &checks.DocChecker{}
-		docResults := docChecker.Check(dir, checks.DocOptions{
-			Version: validateVersion,
-			Verbose: cfg.Verbose,
-		})
-		validationReport.Areas = append(validationReport.Areas, checks.AreaResult{
-			Area:    checks.AreaDocumentation,
-			Status:  checks.ComputeAreaStatus(docResults),
-			Results: docResults,
-		})
+	// Areas run concurrently unless --sequential; QA needs PM's verdict on
+	// the version first, and the custom areas the areas they name
+	tasks := []checks.AreaTask{
+		{
+			Area: checks.AreaPM,
+			Skip: pmSkip,
+			Run: func() checks.AreaResult {
+				pmChecker := &checks.PMChecker{}
+				pmResults := pmChecker.Check(dir, checks.PMOptions{
+					Version:   validateVersion,
+					Changelog: cl,
+					Verbose:   cfg.Verbose,
+				})
+				return checks.NewAreaResult(checks.AreaPM, pmResults, areas[checks.AreaPM.Key()])
+			},
+		},
+		{
+			Area:      checks.AreaQA,
+			DependsOn: []checks.ValidationArea{checks.AreaPM},
+			Skip:      qaSkip,
+			Run: func() checks.AreaResult {
+				qaResults := qaUnavailable
+				if qaResults == nil {
+					qaResults = runQAChecks(dir, detections, &cfg)
+					if cfg.QABaseline.Enabled {
+						qaResults = append(qaResults, checks.CheckQABaseline(dir, validateVersion, qaResults, cfg.QABaseline))
+					}
+				}
+				return checks.NewAreaResult(checks.AreaQA, qaResults, areas[checks.AreaQA.Key()])
+			},
+		},
+		{
+			Area: checks.AreaDocumentation,
+			Skip: skip(checks.AreaDocumentation, "--skip-docs", validateSkipDocs),
+			Run: func() checks.AreaResult {
+				docChecker := &checks.DocChecker{}
+				docResults := docChecker.Check(dir, checks.DocOptions{
+					Version:        validateVersion,
+					MinDocCoverage: cfg.Docs.MinCoverage,
+					Changelog:      cl,
+					Verbose:        cfg.Verbose,
+				})
+				return checks.NewAreaResult(checks.AreaDocumentation, docResults, areas[checks.AreaDocumentation.Key()])
+			},
+		},
+		{
+			Area: checks.AreaRelease,
+			Skip: skip(checks.AreaRelease, "", false),
+			Run: func() checks.AreaResult {
+				releaseChecker := &checks.ReleaseChecker{}
+				releaseResults := releaseChecker.Check(dir, checks.ReleaseOptions{
+					Version:   validateVersion,
+					Freeze:    cfg.Release.Freeze,
+					Changelog: cl,
+					Verbose:   cfg.Verbose,
+				})
+				return checks.NewAreaResult(checks.AreaRelease, releaseResults, areas[checks.AreaRelease.Key()])
+			},
+		},
+		{
+			Area: checks.AreaSecurity,
+			Skip: skip(checks.AreaSecurity, "--skip-security", validateSkipSec),
+			Run: func() checks.AreaResult {
+				secChecker := &checks.SecurityChecker{}
+				secResults := secChecker.Check(dir, checks.SecurityOptions{
+					Verbose:      cfg.Verbose,
+					Reproducible: cfg.Security.Reproducible,
+					Packages:     reproduciblePackages(cfg),
+				})
+				return checks.NewAreaResult(checks.AreaSecurity, secResults, areas[checks.AreaSecurity.Key()])
+			},
+		},
 	}
 
-	// Release Management Area
-	fmt.Println("▶ Running Release Management validation...")
-	releaseChecker := &checks.ReleaseChecker{}
-	releaseResults := releaseChecker.Check(dir, checks.ReleaseOptions{
-		Version: validateVersion,
-		Verbose: cfg.Verbose,
-	})
-	validationReport.Areas = append(validationReport.Areas, checks.AreaResult{
-		Area:    checks.AreaRelease,
-		Status:  checks.ComputeAreaStatus(releaseResults),
-		Results: releaseResults,
-	})
-
-	// Security Area
-	if !validateSkipSec {
-		fmt.Println("▶ Running Security validation...")
-		secChecker := &checks.SecurityChecker{}
-		secResults := secChecker.Check(dir, checks.SecurityOptions{
-			Verbose: cfg.Verbose,
-		})
-		validationReport.Areas = append(validationReport.Areas, checks.AreaResult{
-			Area:    checks.AreaSecurity,
-			Status:  checks.ComputeAreaStatus(secResults),
-			Results: secResults,
+	// Registered and config-defined areas
+	for _, def := range checks.CustomAreas(cfg.Validate) {
+		tasks = append(tasks, checks.AreaTask{
+			Area:      def.Area,
+			DependsOn: def.DependsOn,
+			Skip:      skip(def.Area, "", false),
+			Run: func() checks.AreaResult {
+				return checks.RunArea(def, dir, checks.AreaOptions{
+					Version:   validateVersion,
+					Changelog: cl,
+					Verbose:   cfg.Verbose,
+				}, areas[def.Area.Key()])
+			},
 		})
 	}
 
+	validationReport.Areas = checks.RunAreas(tasks, !validateSequential, printAreaEvent)
+
+	if validateStrict || cfg.Strict {
+		validationReport.PromoteWarnings()
+	}
+
 	// Print comprehensive report
 	if validateFormat == "team" {
 		printTeamStatusReport(validationReport, dir)
@@ -186,9 +267,100 @@ func runValidate(cmd *cobra.Command, args []string) {
 		checks.PrintValidationReport(validationReport)
 	}
 
-	// Exit with error if validation failed
-	if !validationReport.IsGo() {
-		os.Exit(1)
+	if cfg.Telemetry.Enabled() {
+		exportTelemetry(t, cfg, validationReport, start)
+	}
+	return validationReport
+}
+
+// exportTelemetry sends a validation report to the OpenTelemetry collector
+// and Prometheus Pushgateway configured under telemetry. Failing to export
+// is reported but doesn't change the outcome.
+func exportTelemetry(t targetDir, cfg config.Config, vr *checks.ValidationReport, start time.Time) {
+	if checks.Offline() {
+		fmt.Println("Telemetry not exported: offline")
+		return
+	}
+	client, err := cfg.Network.HTTPClient(30 * time.Second)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: telemetry not exported: %v\n", err)
+		return
+	}
+
+	run := telemetry.Run{Dir: t.Path, Report: vr, Start: start, End: time.Now()}
+	g := git.New(t.Path)
+	if url, err := g.RepositoryURL(); err == nil {
+		run.Repository = strings.TrimPrefix(url, "https://")
+	} else if abs, err := filepath.Abs(t.Path); err == nil {
+		run.Repository = filepath.Base(abs)
+	}
+	run.Commit, _ = g.CurrentCommit()
+
+	if err := telemetry.Export(client, cfg.Telemetry, run); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: telemetry not exported: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported validation telemetry for %s\n", run.Repository)
+}
+
+// publishCheckRun publishes a validation report as a GitHub check run on
+// the current commit, so GO/NO-GO shows in the pull request's checks.
+// Failing to publish is reported but doesn't change the outcome.
+func publishCheckRun(t targetDir, vr *checks.ValidationReport, several bool) {
+	if checks.Offline() {
+		fmt.Println("Check run not published: offline")
+		return
+	}
+	g := git.New(t.Path)
+	sha, err := g.CurrentCommit()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: check run not published: %v\n", err)
+		return
+	}
+
+	name := "atrelease validate"
+	if several {
+		name += " (" + t.Path + ")"
+	}
+	url, err := g.CreateCheckRun(vr.CheckRun(name, sha))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: check run not published: %v\n", err)
+		return
+	}
+	fmt.Printf("Published check run: %s\n", url)
+}
+
+// printValidationSummaries prints the combined GO/NO-GO summary after
+// validating several directories, listing the areas that blocked each one.
+func printValidationSummaries(dirs []targetDir, reports []*checks.ValidationReport) {
+	width := 0
+	for _, t := range dirs {
+		width = max(width, len(t.Path))
+	}
+
+	fmt.Println()
+	fmt.Println("=== Validation Summary ===")
+	noGo := 0
+	for i, vr := range reports {
+		if vr.IsGo() {
+			fmt.Printf("%s %-*s  %s\n", checks.StatusGo.Icon(), width, dirs[i].Path, checks.StatusGo.Label())
+			continue
+		}
+		noGo++
+		var blocked []string
+		for _, area := range vr.Areas {
+			if area.Status == checks.StatusNoGo {
+				blocked = append(blocked, string(area.Area))
+			}
+		}
+		fmt.Printf("%s %-*s  %s (%s)\n", checks.StatusNoGo.Icon(), width, dirs[i].Path, checks.StatusNoGo.Label(), strings.Join(blocked, ", "))
+	}
+
+	fmt.Println()
+	if noGo > 0 {
+		fmt.Printf("Validation failed in %d of %d directories.\n", noGo, len(reports))
+	} else {
+		fmt.Printf("All %d directories are GO for release.\n", len(reports))
 	}
 }
 
@@ -235,7 +407,7 @@ func printTeamStatusReport(vr *checks.ValidationReport, dir string) {
 // getGitRemoteProject extracts the project path from git remote origin.
 func getGitRemoteProject(dir string) string {
 	// Try to get git remote URL using git command
-	cmd := exec.Command("git", "-C", dir, "remote", "get-url", "origin")
+	cmd := proc.Command("git", "-C", dir, "remote", "get-url", "origin")
 	output, err := cmd.Output()
 	if err != nil {
 		return ""
@@ -264,60 +436,72 @@ func getGitRemoteProject(dir string) string {
 	return url
 }
 
-// runQAChecks runs all QA checks for detected languages using releasekit.
-// It shells out to the releasekit CLI for language-specific validation.
-func runQAChecks(dir string, detections []detect.Detection, cfg *config.Config) []checks.Result {
-	var results []checks.Result
-
-	// Check if releasekit is available, prompt for installation if not
-	if !checks.ReleasekitAvailable() {
-		prompter := requirements.NewCLIPrompter()
-		reqResult := requirements.EnsureRequirements([]string{"releasekit"}, prompter)
-		if !reqResult.AllSatisfied() {
-			return []checks.Result{{
-				Name:    "QA: releasekit",
-				Skipped: true,
-				Reason:  "releasekit CLI not installed",
-			}}
-		}
+// ensureReleasekit prompts to install the releasekit CLI the QA checks
+// run if it is missing. It returns the QA results if it is still missing,
+// or nil if it is available.
+func ensureReleasekit() []checks.Result {
+	if checks.ReleasekitAvailable() {
+		return nil
 	}
-
-	// Determine which languages are enabled and build options
-	hasGo := detect.HasLanguage(detections, detect.Go) && cfg.IsLanguageEnabled("go")
-	hasTS := detect.HasLanguage(detections, detect.TypeScript) && cfg.IsLanguageEnabled("typescript")
-	hasJS := detect.HasLanguage(detections, detect.JavaScript) && cfg.IsLanguageEnabled("javascript")
-
-	if !hasGo && !hasTS && !hasJS {
-		return results // No supported languages detected
+	prompter := requirements.NewCLIPrompter()
+	reqResult := requirements.EnsureRequirements([]string{"releasekit"}, prompter)
+	if reqResult.AllSatisfied() {
+		return nil
 	}
+	return []checks.Result{{
+		Name:    "QA: releasekit",
+		Skipped: true,
+		Reason:  "releasekit CLI not installed",
+	}}
+}
 
-	// Build options from config (use Go config as primary, others are similar)
-	opts := checks.Options{
-		Test:    true,
-		Lint:    true,
-		Format:  true,
-		Verbose: cfg.Verbose,
+// printAreaEvent prints a validation area starting or finishing.
+func printAreaEvent(ev checks.AreaEvent) {
+	if !ev.Done {
+		fmt.Printf("▶ Running %s validation...\n", ev.Area)
+		return
 	}
-
-	if hasGo {
-		langCfg := cfg.GetLanguageConfig("go")
-		opts.Test = *langCfg.Test
-		opts.Lint = *langCfg.Lint
-		opts.Format = *langCfg.Format
-		opts.Coverage = langCfg.Coverage != nil && *langCfg.Coverage
+	fmt.Printf("%s %s validation finished: %s (%s)\n",
+		ev.Result.Status.Icon(), ev.Area, ev.Result.Status.Label(), ev.Duration.Round(100*time.Millisecond))
+	if ev.Area == checks.AreaPM && ev.Result.Status == checks.StatusNoGo {
+		fmt.Println("  ⚠ PM validation failed - other agents will still run but release is blocked")
 	}
+}
 
-	// Run releasekit validate on the directory
-	// releasekit auto-detects languages, so we just call it once
-	releasekitResults, err := checks.RunReleasekit(dir, opts)
+// runQAChecks runs all QA checks for detected languages using releasekit.
+// It shells out to the releasekit CLI for language-specific validation.
+func runQAChecks(dir string, detections []detect.Detection, cfg *config.Config) []checks.Result {
+	// Run the language checks with the settings in the config
+	engine, err := checks.NewEngine(*cfg, checks.OptionFlags{})
 	if err != nil {
 		return []checks.Result{{
 			Name:   "QA: releasekit",
 			Passed: false,
-			Output: fmt.Sprintf("releasekit failed: %v", err),
+			Output: err.Error(),
+		}}
+	}
+	engine.Config.Strict = cfg.Strict || validateStrict
+	engine.Log = func(format string, args ...any) {
+		fmt.Printf("  "+format+"\n", args...)
+	}
+	engine.Heartbeat = true
+	results, err := engine.Run(dir, detections)
+	if err != nil {
+		return []checks.Result{{
+			Name:   "QA: releasekit",
+			Passed: false,
+			Output: err.Error(),
 		}}
 	}
-
-	results = append(results, releasekitResults...)
 	return results
 }
+
+// reproduciblePackages returns the commands the reproducible build check
+// builds: the release build's main package, or every main package if the
+// release doesn't build one.
+func reproduciblePackages(cfg config.Config) []string {
+	if cfg.Build.Main == "" {
+		return nil
+	}
+	return []string{cfg.Build.Main}
+}
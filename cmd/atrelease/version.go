@@ -2,16 +2,25 @@ package main
 
 import (
 	"fmt"
+	"runtime/debug"
+	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/plexusone/agent-team-release/pkg/semver"
 )
 
+var versionCheck bool
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
-	Long:  `Display the version, commit hash, and build date of agent-team-release.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Display the version, commit hash, and build date of agent-team-release.
+
+Use --check to compare against the latest GitHub release. Run
+'atrelease self-update' to install a newer release.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fmt.Printf("agent-team-release %s\n", version)
 		if commit != "none" {
 			fmt.Printf("  commit: %s\n", commit)
@@ -19,5 +28,45 @@ var versionCmd = &cobra.Command{
 		if date != "unknown" {
 			fmt.Printf("  built:  %s\n", date)
 		}
+
+		if !versionCheck {
+			return nil
+		}
+		if cfgOffline {
+			fmt.Println("Skipping update check (offline)")
+			return nil
+		}
+
+		latest, err := fetchLatestRelease()
+		if err != nil {
+			return err
+		}
+		if isNewerVersion(latest.TagName, version) {
+			fmt.Printf("\nA newer release is available: %s\n", latest.TagName)
+			fmt.Println("Run 'atrelease self-update' to upgrade.")
+		} else {
+			fmt.Printf("\nUp to date (latest release: %s)\n", latest.TagName)
+		}
+		return nil
 	},
 }
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionCheck, "check", false, "Check for a newer release on GitHub")
+
+	// Builds without ldflags (e.g. go install ...@v1.2.3) still carry the
+	// module version. Local builds report a pseudo-version or "(devel)", which
+	// are left as "dev".
+	if version == "dev" {
+		if info, ok := debug.ReadBuildInfo(); ok && isReleaseVersion(info.Main.Version) {
+			version = info.Main.Version
+		}
+	}
+	rootCmd.Version = version
+	rootCmd.SetVersionTemplate("agent-team-release {{.Version}}\n")
+}
+
+// isReleaseVersion reports whether v is a plain vMAJOR.MINOR.PATCH tag.
+func isReleaseVersion(v string) bool {
+	return strings.HasPrefix(v, "v") && semver.IsRelease(v)
+}
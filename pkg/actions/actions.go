@@ -2,17 +2,64 @@
 package actions
 
 import (
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
 	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // Result represents the result of an action.
 type Result struct {
+	ID      string // Stable machine ID, e.g. "action.changelog"; set by Run
 	Name    string
 	Success bool
 	Output  string
 	Error   error
 	Skipped bool
 	Reason  string
+
+	Duration time.Duration // How long the action took; set by Run
+}
+
+// Severity returns the outcome of r on the same scale as check results.
+func (r Result) Severity() checks.Severity {
+	switch {
+	case r.Skipped:
+		return checks.SeveritySkipped
+	case r.Success:
+		return checks.SeverityPassed
+	default:
+		return checks.SeverityFailed
+	}
+}
+
+// CheckResult converts r to a check result, so action results can be
+// grouped, reported, and converted to team reports like checks.
+func (r Result) CheckResult() checks.Result {
+	return checks.Result{
+		ID:       r.ID,
+		Name:     r.Name,
+		Passed:   r.Success,
+		Output:   r.Output,
+		Error:    r.Error,
+		Skipped:  r.Skipped,
+		Reason:   r.Reason,
+		Duration: r.Duration,
+	}
+}
+
+// Run runs a non-interactively and records the ID and duration of its
+// result.
+func Run(a Action, dir string, opts Options) Result {
+	start := time.Now()
+	if opts.DryRun {
+		defer proc.SetDryRun(true)()
+	}
+	result := a.Run(dir, opts)
+	result.ID = "action." + a.Name()
+	result.Duration = time.Since(start)
+	return result
 }
 
 // Proposal represents a proposed change for user approval.
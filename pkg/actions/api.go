@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/apireport"
+)
+
+// APIAction regenerates the Go API baseline in api/, which the api-baseline
+// check compares with the source and with the last release.
+type APIAction struct{}
+
+// Name returns the action name.
+func (a *APIAction) Name() string {
+	return "api"
+}
+
+// Run executes the api action directly.
+func (a *APIAction) Run(dir string, opts Options) Result {
+	var output strings.Builder
+
+	if !fileExists(filepath.Join(dir, "go.mod")) {
+		return Result{
+			Name:    "api",
+			Success: false,
+			Error:   fmt.Errorf("go.mod not found"),
+			Output:  "The API baseline covers Go modules; go.mod not found in " + dir,
+		}
+	}
+
+	current, err := apireport.Generate(dir)
+	if err != nil {
+		return Result{Name: "api", Success: false, Error: err}
+	}
+	committed, err := apireport.Load(dir)
+	if err != nil {
+		return Result{Name: "api", Success: false, Error: err}
+	}
+
+	diff := apireport.Compare(committed, current)
+	if diff.Empty() && len(committed) == len(current) {
+		output.WriteString("No changes needed in api/\n")
+		return Result{Name: "api", Success: true, Output: output.String()}
+	}
+
+	fmt.Fprintf(&output, "API changes: %d line(s) added, %d removed\n", len(diff.Added), len(diff.Removed))
+	if opts.Verbose {
+		for _, l := range diff.Added {
+			fmt.Fprintf(&output, "  + %s\n", l)
+		}
+		for _, l := range diff.Removed {
+			fmt.Fprintf(&output, "  - %s\n", l)
+		}
+	}
+
+	if opts.DryRun {
+		output.WriteString("\n[Dry run] Would update api/\n")
+		return Result{Name: "api", Success: true, Output: output.String()}
+	}
+
+	changed, err := apireport.Write(dir, current)
+	if err != nil {
+		return Result{Name: "api", Success: false, Error: err, Output: output.String()}
+	}
+	output.WriteString("\nUpdated api/:\n")
+	for _, name := range changed {
+		fmt.Fprintf(&output, "  - %s\n", name)
+	}
+	return Result{Name: "api", Success: true, Output: output.String()}
+}
+
+// Propose generates proposals for interactive mode, one per changed file.
+func (a *APIAction) Propose(dir string, opts Options) ([]Proposal, error) {
+	current, err := apireport.Generate(dir)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := apireport.Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range current {
+		names[name] = true
+	}
+	for name := range committed {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var proposals []Proposal
+	for _, name := range sorted {
+		oldContent, newContent := "", ""
+		if lines, ok := committed[name]; ok {
+			oldContent = string(apireport.Format(lines))
+		}
+		if lines, ok := current[name]; ok {
+			newContent = string(apireport.Format(lines))
+		}
+		if oldContent == newContent {
+			continue
+		}
+		description := "Update API baseline " + name
+		if newContent == "" {
+			description = "Remove API baseline " + name
+		}
+		proposals = append(proposals, Proposal{
+			Description: description,
+			FilePath:    filepath.Join(apireport.Dir, name),
+			OldContent:  oldContent,
+			NewContent:  newContent,
+		})
+	}
+	if len(proposals) == 0 {
+		return nil, fmt.Errorf("no changes to propose")
+	}
+	return proposals, nil
+}
+
+// Apply applies approved proposals.
+func (a *APIAction) Apply(dir string, proposals []Proposal) Result {
+	var output strings.Builder
+	for _, p := range proposals {
+		path := filepath.Join(dir, p.FilePath)
+		var err error
+		if p.NewContent == "" {
+			err = os.Remove(path)
+		} else if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+			err = os.WriteFile(path, []byte(p.NewContent), 0644)
+		}
+		if err != nil {
+			return Result{Name: "api", Success: false, Error: err, Output: output.String()}
+		}
+		fmt.Fprintf(&output, "Updated %s\n", p.FilePath)
+	}
+	return Result{Name: "api", Success: true, Output: output.String()}
+}
@@ -7,6 +7,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // ChangelogAction generates and updates changelogs using schangelog.
@@ -19,6 +23,16 @@ func (a *ChangelogAction) Name() string {
 
 // Run executes the changelog action directly.
 func (a *ChangelogAction) Run(dir string, opts Options) Result {
+	// Repositories without CHANGELOG.json may keep a Keep a Changelog
+	// CHANGELOG.md by hand; schangelog isn't needed for those
+	if !fileExists(filepath.Join(dir, changelog.DefaultFile)) {
+		if data, err := os.ReadFile(filepath.Join(dir, changelog.MarkdownFile)); err == nil {
+			if cl, err := changelog.ParseMarkdown(data); err == nil {
+				return a.runMarkdown(dir, data, cl, opts)
+			}
+		}
+	}
+
 	// Check if schangelog is available
 	if !commandExists("schangelog") {
 		return Result{
@@ -116,6 +130,67 @@ func (a *ChangelogAction) Run(dir string, opts Options) Result {
 	}
 }
 
+// runMarkdown maintains a Keep a Changelog CHANGELOG.md: it lists the
+// [Unreleased] changes and, when opts.Version is set, promotes them to a
+// heading for that version.
+func (a *ChangelogAction) runMarkdown(dir string, data []byte, cl *changelog.Changelog, opts Options) Result {
+	var output strings.Builder
+	output.WriteString("Using Keep a Changelog CHANGELOG.md (no CHANGELOG.json)\n")
+
+	if cl.Unreleased == nil || cl.Unreleased.Count() == 0 {
+		output.WriteString("\nNo changes under [Unreleased]\n")
+		if opts.Version == "" {
+			return Result{Name: "changelog", Success: true, Output: output.String()}
+		}
+		return Result{
+			Name:    "changelog",
+			Success: false,
+			Error:   fmt.Errorf("no changes under [Unreleased] in CHANGELOG.md to release as %s", opts.Version),
+			Output:  output.String(),
+		}
+	}
+
+	u := cl.Unreleased
+	output.WriteString("\nUnreleased changes:\n")
+	for _, section := range []struct {
+		name    string
+		entries []changelog.Entry
+	}{
+		{"Breaking", u.Breaking}, {"Added", u.Added}, {"Changed", u.Changed}, {"Deprecated", u.Deprecated},
+		{"Removed", u.Removed}, {"Fixed", u.Fixed}, {"Security", u.Security},
+	} {
+		for _, e := range section.entries {
+			fmt.Fprintf(&output, "  [%s] %s\n", section.name, e.Description)
+		}
+	}
+
+	if opts.Version == "" {
+		output.WriteString("\nSpecify a version to promote [Unreleased] to a release.\n")
+		return Result{Name: "changelog", Success: true, Output: output.String()}
+	}
+
+	updated, err := changelog.PromoteUnreleased(data, opts.Version, time.Now().Format(time.DateOnly))
+	if err != nil {
+		return Result{Name: "changelog", Success: false, Error: err, Output: output.String()}
+	}
+
+	if opts.DryRun {
+		fmt.Fprintf(&output, "\n[Dry run] Would promote [Unreleased] to %s in CHANGELOG.md\n", opts.Version)
+		return Result{Name: "changelog", Success: true, Output: output.String()}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, changelog.MarkdownFile), updated, 0644); err != nil {
+		return Result{Name: "changelog", Success: false, Error: err, Output: output.String()}
+	}
+	fmt.Fprintf(&output, "\nPromoted [Unreleased] to %s in CHANGELOG.md\n", opts.Version)
+
+	return Result{
+		Name:    "changelog",
+		Success: true,
+		Output:  output.String(),
+	}
+}
+
 // Propose generates proposals for interactive mode.
 func (a *ChangelogAction) Propose(dir string, opts Options) ([]Proposal, error) {
 	// Check if schangelog is available
@@ -230,7 +305,7 @@ func fileExists(path string) bool {
 }
 
 func runCommand(name string, dir string, command string, args ...string) Result {
-	cmd := exec.Command(command, args...)
+	cmd := proc.Command(command, args...)
 	cmd.Dir = dir
 
 	var stdout, stderr bytes.Buffer
@@ -256,7 +331,7 @@ func runCommand(name string, dir string, command string, args ...string) Result
 }
 
 func getLatestTag(dir string) (string, error) {
-	cmd := exec.Command("git", "describe", "--tags", "--abbrev=0")
+	cmd := proc.Command("git", "describe", "--tags", "--abbrev=0")
 	cmd.Dir = dir
 
 	output, err := cmd.Output()
@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// DefaultCommitTemplate is the release commit message template used when
+// none is configured.
+const DefaultCommitTemplate = `chore(release): {{.Version}}`
+
+// DefaultPRTitleTemplate is the release pull request title template used
+// when none is configured.
+const DefaultPRTitleTemplate = `Release {{.Version}}`
+
+// MessageData is the data available to release commit, tag annotation, and
+// pull request title templates.
+type MessageData struct {
+	Version     string       // Version being released, e.g. "v1.2.0"
+	PreviousTag string       // Latest existing tag, if any
+	Date        string       // Current date (YYYY-MM-DD)
+	Highlights  []string     // CHANGELOG.json highlights for Version
+	Commits     []git.Commit // Commits since PreviousTag
+}
+
+// LoadMessageData gathers template data for version from the repository:
+// the latest tag, the commits since it, and the CHANGELOG.json highlights.
+// Missing tags or changelog entries leave the fields empty.
+func LoadMessageData(dir, version string) MessageData {
+	g := git.New(dir)
+	data := MessageData{
+		Version: version,
+		Date:    time.Now().Format(time.DateOnly),
+	}
+	data.PreviousTag, _ = g.LatestTag()
+	data.Commits, _ = g.CommitsSince(data.PreviousTag)
+	if cl, err := changelog.Load(filepath.Join(dir, changelog.DefaultFile)); err == nil {
+		for _, r := range cl.Releases {
+			if strings.TrimPrefix(r.Version, "v") == strings.TrimPrefix(version, "v") {
+				for _, h := range r.Highlights {
+					data.Highlights = append(data.Highlights, h.Description)
+				}
+				break
+			}
+		}
+	}
+	return data
+}
+
+// RenderCommitMessage renders a release commit message template. An empty
+// template uses DefaultCommitTemplate.
+func RenderCommitMessage(tmpl string, data MessageData) (string, error) {
+	return RenderMessage("commit", tmpl, DefaultCommitTemplate, data)
+}
+
+// RenderPRTitle renders a release pull request title template. An empty
+// template uses DefaultPRTitleTemplate. Titles are a single line, so only
+// the first line of the output is used.
+func RenderPRTitle(tmpl string, data MessageData) (string, error) {
+	title, err := RenderMessage("PR title", tmpl, DefaultPRTitleTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	title, _, _ = strings.Cut(title, "\n")
+	return strings.TrimSpace(title), nil
+}
+
+// RenderMessage renders a message template named name, falling back to def
+// when tmpl is empty. Leading and trailing whitespace is trimmed.
+func RenderMessage(name, tmpl, def string, data MessageData) (string, error) {
+	if tmpl == "" {
+		tmpl = def
+	}
+	t, err := template.New(name).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering %s template: %w", name, err)
+	}
+	message := strings.TrimSpace(buf.String())
+	if message == "" {
+		return "", fmt.Errorf("%s template rendered an empty message", name)
+	}
+	return message, nil
+}
@@ -1,13 +1,74 @@
 package actions
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"golang.org/x/mod/modfile"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
 )
 
+// semverPattern matches a version with an optional "v" prefix, prerelease,
+// and build metadata.
+const semverPattern = `v?\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`
+
+// shieldsSemverPattern matches a version in a shields.io static badge path,
+// where a literal "-" is written "--". The "v" prefix is captured so it can
+// be kept as written.
+const shieldsSemverPattern = `(v?)\d+\.\d+\.\d+(?:--[0-9A-Za-z.]+)*`
+
+// DefaultBadgePatterns are the version references rewritten by the README
+// action unless readme.disable_default_badges is set. Those naming a module,
+// package, or image only match this project's, so references to other
+// projects keep their versions.
+var DefaultBadgePatterns = []config.BadgePattern{
+	{
+		Name:    "go install version",
+		Pattern: `(go install [^@\s]+@)` + semverPattern,
+		Replace: "${1}{{version}}",
+	},
+	{
+		Name:    "version badge",
+		Pattern: `(version-)` + shieldsSemverPattern + `-`,
+		Replace: "${1}${2}{{shields}}-",
+	},
+	{
+		Name:    "shields.io release badge",
+		Pattern: `(img\.shields\.io/badge/release-)` + shieldsSemverPattern + `-`,
+		Replace: "${1}${2}{{shields}}-",
+	},
+	{
+		Name:    "pkg.go.dev link",
+		Pattern: `(pkg\.go\.dev/{{module}}(?:/[^\s)"'@]*)?@)` + semverPattern,
+		Replace: "${1}{{version}}",
+	},
+	{
+		Name:    "npm version badge",
+		Pattern: `(img\.shields\.io/badge/npm-)` + shieldsSemverPattern + `-`,
+		Replace: "${1}${2}{{shields}}-",
+	},
+	{
+		Name:    "npm install version",
+		Pattern: `((?:npm|pnpm|yarn) (?:install|i|add) (?:-[-\w]+ )*{{package}}@)` + semverPattern,
+		Replace: "${1}{{semver}}",
+	},
+	{
+		Name:    "Docker Hub tag",
+		Pattern: `(docker (?:pull|run)[^\n]*?\s(?:docker\.io/)?{{image}}:)(v?)\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?`,
+		Replace: "${1}${2}{{semver}}",
+	},
+	{
+		Name:    "Docker Hub tag link",
+		Pattern: `(hub\.docker\.com/r/{{image}}/tags[^\s)"']*[?&]name=)(v?)\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?`,
+		Replace: "${1}${2}{{semver}}",
+	},
+}
+
 // ReadmeAction updates README badges and version references.
 type ReadmeAction struct{}
 
@@ -47,23 +108,23 @@ func (a *ReadmeAction) Run(dir string, opts Options) Result {
 	if opts.Version != "" {
 		fmt.Fprintf(&output, "Updating version references to %s...\n", opts.Version)
 
-		// Update @latest or @vX.Y.Z in go install commands
-		goInstallRegex := regexp.MustCompile(`go install ([^@]+)@v?[\d.]+`)
-		if goInstallRegex.MatchString(newContent) {
-			newContent = goInstallRegex.ReplaceAllString(newContent, "go install $1@"+opts.Version)
-			changes = append(changes, "Updated go install version")
-		}
-
-		// Update version in badges (e.g., version-vX.Y.Z-blue)
-		versionBadgeRegex := regexp.MustCompile(`version-v[\d.]+-`)
-		if versionBadgeRegex.MatchString(newContent) {
-			newContent = versionBadgeRegex.ReplaceAllString(newContent, "version-"+opts.Version+"-")
-			changes = append(changes, "Updated version badge")
+		updated, updates, err := updateBadges(newContent, opts.Version, readmeProject(dir, opts.Config), badgePatterns(opts.Config))
+		if err != nil {
+			return Result{
+				Name:    "readme",
+				Success: false,
+				Error:   err,
+				Output:  output.String(),
+			}
 		}
+		newContent = updated
+		changes = append(changes, updates...)
 	}
 
 	// Update coverage badge if gocoverbadge is available
-	if commandExists("gocoverbadge") {
+	if commandExists("gocoverbadge") && opts.DryRun {
+		changes = append(changes, "Update coverage badge (gocoverbadge)")
+	} else if commandExists("gocoverbadge") {
 		output.WriteString("Updating coverage badge...\n")
 
 		// Run gocoverbadge to generate badge
@@ -156,16 +217,13 @@ func (a *ReadmeAction) Propose(dir string, opts Options) ([]Proposal, error) {
 
 	// Preview version changes
 	if opts.Version != "" {
-		goInstallRegex := regexp.MustCompile(`go install ([^@]+)@v?[\d.]+`)
-		if goInstallRegex.MatchString(newContent) {
-			newContent = goInstallRegex.ReplaceAllString(newContent, "go install $1@"+opts.Version)
-			fmt.Fprintf(&description, "\n  - Update go install version to %s", opts.Version)
+		updated, updates, err := updateBadges(newContent, opts.Version, readmeProject(dir, opts.Config), badgePatterns(opts.Config))
+		if err != nil {
+			return nil, err
 		}
-
-		versionBadgeRegex := regexp.MustCompile(`version-v[\d.]+-`)
-		if versionBadgeRegex.MatchString(newContent) {
-			newContent = versionBadgeRegex.ReplaceAllString(newContent, "version-"+opts.Version+"-")
-			fmt.Fprintf(&description, "\n  - Update version badge to %s", opts.Version)
+		newContent = updated
+		for _, u := range updates {
+			fmt.Fprintf(&description, "\n  - %s", u)
 		}
 	}
 
@@ -219,3 +277,95 @@ func (a *ReadmeAction) Apply(dir string, proposals []Proposal) Result {
 		Output:  "Updated README.md",
 	}
 }
+
+// badgePatterns returns the built-in badge patterns followed by those in
+// readme.badges, or only the configured ones if the defaults are disabled.
+func badgePatterns(cfg *config.Config) []config.BadgePattern {
+	if cfg == nil {
+		return DefaultBadgePatterns
+	}
+	var patterns []config.BadgePattern
+	if !cfg.Readme.DisableDefaultBadges {
+		patterns = append(patterns, DefaultBadgePatterns...)
+	}
+	return append(patterns, cfg.Readme.Badges...)
+}
+
+// badgeProject names the module, package, and image of the project whose
+// version references are rewritten, each "" if it has none.
+type badgeProject struct {
+	Module  string // Go module path, from go.mod
+	Package string // npm package name, from package.json
+	Image   string // Docker image, from readme.image
+}
+
+// readmeProject returns the project of the README in dir.
+func readmeProject(dir string, cfg *config.Config) badgeProject {
+	var project badgeProject
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		project.Module = modfile.ModulePath(data)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			project.Package = pkg.Name
+		}
+	}
+	if cfg != nil {
+		project.Image = cfg.Readme.Image
+	}
+	return project
+}
+
+// expand replaces the {{module}}, {{package}}, and {{image}} placeholders
+// of pattern with the project's names, quoted to match literally. It
+// reports false if pattern uses a name the project doesn't have.
+func (p badgeProject) expand(pattern string) (string, bool) {
+	for placeholder, name := range map[string]string{
+		"{{module}}":  p.Module,
+		"{{package}}": p.Package,
+		"{{image}}":   p.Image,
+	} {
+		if !strings.Contains(pattern, placeholder) {
+			continue
+		}
+		if name == "" {
+			return "", false
+		}
+		pattern = strings.ReplaceAll(pattern, placeholder, regexp.QuoteMeta(name))
+	}
+	return pattern, true
+}
+
+// updateBadges rewrites every version reference matched by patterns to
+// version, returning the new content and a description of each pattern that
+// changed something. Patterns naming a module, package, or image project
+// doesn't have are skipped.
+func updateBadges(content, version string, project badgeProject, patterns []config.BadgePattern) (string, []string, error) {
+	semver := strings.TrimPrefix(version, "v")
+	placeholders := strings.NewReplacer(
+		"{{version}}", "v"+semver,
+		"{{semver}}", semver,
+		"{{shields}}", strings.ReplaceAll(semver, "-", "--"),
+	)
+
+	var changes []string
+	for _, p := range patterns {
+		pattern, ok := project.expand(p.Pattern)
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return content, nil, fmt.Errorf("readme badge %q: invalid pattern: %w", p.Name, err)
+		}
+		updated := re.ReplaceAllString(content, placeholders.Replace(p.Replace))
+		if updated != content {
+			changes = append(changes, fmt.Sprintf("Updated %s to %s", p.Name, version))
+			content = updated
+		}
+	}
+	return content, changes, nil
+}
@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestUpdateBadges(t *testing.T) {
+	project := badgeProject{
+		Module:  "github.com/example/project",
+		Package: "@example/project",
+		Image:   "example/project",
+	}
+	for _, tt := range []struct {
+		name, content, want string
+		project             badgeProject
+	}{
+		{
+			name:    "pkg.go.dev link",
+			content: "https://pkg.go.dev/github.com/example/project@v1.0.0",
+			want:    "https://pkg.go.dev/github.com/example/project@v1.2.0-rc.1",
+		},
+		{
+			name:    "pkg.go.dev link to a package",
+			content: "https://pkg.go.dev/github.com/example/project/pkg/api@v1.0.0",
+			want:    "https://pkg.go.dev/github.com/example/project/pkg/api@v1.2.0-rc.1",
+		},
+		{
+			name:    "pkg.go.dev link to another module",
+			content: "https://pkg.go.dev/github.com/other/lib@v1.0.0 and https://pkg.go.dev/github.com/example/project-extras@v1.0.0",
+			want:    "https://pkg.go.dev/github.com/other/lib@v1.0.0 and https://pkg.go.dev/github.com/example/project-extras@v1.0.0",
+		},
+		{
+			name:    "npm install version",
+			content: "npm install @example/project@1.0.0\npnpm add -D @example/project@1.0.0",
+			want:    "npm install @example/project@1.2.0-rc.1\npnpm add -D @example/project@1.2.0-rc.1",
+		},
+		{
+			name:    "npm install of another package",
+			content: "npm install left-pad@1.0.0 @example/project-cli@1.0.0",
+			want:    "npm install left-pad@1.0.0 @example/project-cli@1.0.0",
+		},
+		{
+			name:    "Docker Hub tag",
+			content: "docker pull example/project:v1.0.0\ndocker run --rm docker.io/example/project:1.0.0",
+			want:    "docker pull example/project:v1.2.0-rc.1\ndocker run --rm docker.io/example/project:1.2.0-rc.1",
+		},
+		{
+			name:    "Docker Hub tag of another image",
+			content: "docker run --rm -v $PWD:/src golang:1.0.0 && docker pull other/project:1.0.0",
+			want:    "docker run --rm -v $PWD:/src golang:1.0.0 && docker pull other/project:1.0.0",
+		},
+		{
+			name:    "Docker Hub tag link",
+			content: "https://hub.docker.com/r/example/project/tags?name=1.0.0 https://hub.docker.com/r/other/project/tags?name=1.0.0",
+			want:    "https://hub.docker.com/r/example/project/tags?name=1.2.0-rc.1 https://hub.docker.com/r/other/project/tags?name=1.0.0",
+		},
+		{
+			name:    "without an image",
+			content: "docker pull example/project:1.0.0",
+			want:    "docker pull example/project:1.0.0",
+			project: badgeProject{Module: project.Module},
+		},
+		{
+			name:    "shields.io badge",
+			content: "https://img.shields.io/badge/version-v1.0.0-blue",
+			want:    "https://img.shields.io/badge/version-v1.2.0--rc.1-blue",
+		},
+	} {
+		p := project
+		if tt.project != (badgeProject{}) {
+			p = tt.project
+		}
+		got, _, err := updateBadges(tt.content, "v1.2.0-rc.1", p, DefaultBadgePatterns)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s:\n got %q\nwant %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadmeProject(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module github.com/example/project\n\ngo 1.24\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"name": "@example/project"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Readme: config.ReadmeConfig{Image: "example/project"}}
+
+	want := badgeProject{Module: "github.com/example/project", Package: "@example/project", Image: "example/project"}
+	if got := readmeProject(dir, cfg); got != want {
+		t.Errorf("readmeProject() = %+v, want %+v", got, want)
+	}
+}
@@ -4,11 +4,27 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/proc"
+	"github.com/plexusone/agent-team-release/pkg/roadmap"
 )
 
-// RoadmapAction generates and updates roadmaps using sroadmap.
-type RoadmapAction struct{}
+// DefaultRoadmapIssueLabel is the label applied to issues created for
+// roadmap items when none is configured.
+const DefaultRoadmapIssueLabel = "roadmap"
+
+// roadmapIssuesLimit bounds the issues fetched when syncing with GitHub.
+const roadmapIssuesLimit = 1000
+
+// RoadmapAction generates and updates roadmaps using sroadmap, optionally
+// syncing ROADMAP.json items with GitHub issues first.
+type RoadmapAction struct {
+	SyncIssues bool   // Create issues for items and complete items whose issues closed
+	IssueLabel string // Label for created issues (default "roadmap")
+}
 
 // Name returns the action name.
 func (a *RoadmapAction) Name() string {
@@ -22,7 +38,7 @@ func (a *RoadmapAction) Run(dir string, opts Options) Result {
 		return Result{
 			Name:    "roadmap",
 			Success: false,
-			Error:   fmt.Errorf("sroadmap not found in PATH"),
+			Error:   proc.NotFound("sroadmap"),
 			Output:  "Install sroadmap: go install github.com/grokify/sroadmap/cmd/sroadmap@latest",
 		}
 	}
@@ -53,6 +69,40 @@ func (a *RoadmapAction) Run(dir string, opts Options) Result {
 	}
 	output.WriteString("ROADMAP.json is valid\n")
 
+	// Sync with GitHub issues before generating so ROADMAP.md reflects it
+	if a.SyncIssues && opts.DryRun {
+		output.WriteString("\n[Dry run] Would sync ROADMAP.json with GitHub issues\n")
+	} else if a.SyncIssues {
+		output.WriteString("\nSyncing with GitHub issues...\n")
+		r, changes, err := a.planSync(dir)
+		if err != nil {
+			return Result{
+				Name:    "roadmap",
+				Success: false,
+				Error:   err,
+				Output:  output.String(),
+			}
+		}
+		switch {
+		case len(changes) == 0:
+			output.WriteString("ROADMAP.json and GitHub issues are in sync\n")
+		case opts.DryRun:
+			output.WriteString("[Dry run] Would make these changes:\n")
+			for _, c := range changes {
+				fmt.Fprintf(&output, "  - %s\n", c)
+			}
+		default:
+			if err := a.applySync(dir, r, changes, &output); err != nil {
+				return Result{
+					Name:    "roadmap",
+					Success: false,
+					Error:   err,
+					Output:  output.String(),
+				}
+			}
+		}
+	}
+
 	// If dry run, show stats and stop
 	if opts.DryRun {
 		output.WriteString("\nRoadmap statistics:\n")
@@ -90,7 +140,7 @@ func (a *RoadmapAction) Run(dir string, opts Options) Result {
 func (a *RoadmapAction) Propose(dir string, opts Options) ([]Proposal, error) {
 	// Check if sroadmap is available
 	if !commandExists("sroadmap") {
-		return nil, fmt.Errorf("sroadmap not found in PATH")
+		return nil, proc.NotFound("sroadmap")
 	}
 
 	// Check if ROADMAP.json exists
@@ -99,6 +149,26 @@ func (a *RoadmapAction) Propose(dir string, opts Options) ([]Proposal, error) {
 		return nil, fmt.Errorf("ROADMAP.json not found")
 	}
 
+	var proposals []Proposal
+	if a.SyncIssues {
+		_, changes, err := a.planSync(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range changes {
+			proposals = append(proposals, Proposal{
+				Description: c.String(),
+				FilePath:    roadmap.DefaultFile,
+				Metadata: map[string]string{
+					"sync":  c.Action,
+					"item":  c.ItemID,
+					"title": c.Title,
+					"issue": strconv.Itoa(c.Issue),
+				},
+			})
+		}
+	}
+
 	// Get stats to show what will be included
 	statsResult := runCommand("stats", dir, "sroadmap", "stats", "ROADMAP.json")
 
@@ -112,29 +182,138 @@ func (a *RoadmapAction) Propose(dir string, opts Options) ([]Proposal, error) {
 		}
 	}
 
-	return []Proposal{
-		{
-			Description: "Regenerate ROADMAP.md from ROADMAP.json",
-			FilePath:    "ROADMAP.md",
-			OldContent:  oldContent,
-			NewContent:  "[Will be generated by sroadmap]",
-			Metadata: map[string]string{
-				"stats": statsResult.Output,
-			},
+	return append(proposals, Proposal{
+		Description: "Regenerate ROADMAP.md from ROADMAP.json",
+		FilePath:    "ROADMAP.md",
+		OldContent:  oldContent,
+		NewContent:  "[Will be generated by sroadmap]",
+		Metadata: map[string]string{
+			"stats": statsResult.Output,
 		},
-	}, nil
+	}), nil
 }
 
-// Apply applies approved proposals.
+// Apply applies approved proposals. Approved issue sync changes are applied
+// to ROADMAP.json before ROADMAP.md is regenerated.
 func (a *RoadmapAction) Apply(dir string, proposals []Proposal) Result {
-	// Run the action to apply changes
-	return a.Run(dir, Options{DryRun: false})
+	if len(proposals) == 0 {
+		return Result{Name: "roadmap", Skipped: true, Reason: "no proposals approved"}
+	}
+
+	var changes []roadmap.SyncChange
+	for _, p := range proposals {
+		if action := p.Metadata["sync"]; action != "" {
+			issue, _ := strconv.Atoi(p.Metadata["issue"])
+			changes = append(changes, roadmap.SyncChange{
+				Action: action,
+				ItemID: p.Metadata["item"],
+				Title:  p.Metadata["title"],
+				Issue:  issue,
+			})
+		}
+	}
+
+	var output strings.Builder
+	if len(changes) > 0 {
+		r, err := roadmap.Load(filepath.Join(dir, roadmap.DefaultFile))
+		if err == nil {
+			err = a.applySync(dir, r, changes, &output)
+		}
+		if err != nil {
+			return Result{Name: "roadmap", Success: false, Error: err, Output: output.String()}
+		}
+	}
+
+	if err := a.Generate(dir); err != nil {
+		return Result{Name: "roadmap", Success: false, Error: err, Output: output.String()}
+	}
+	output.WriteString("Generated ROADMAP.md\n")
+	return Result{Name: "roadmap", Success: true, Output: output.String()}
+}
+
+// planSync loads ROADMAP.json and compares its items with the repository's
+// GitHub issues.
+func (a *RoadmapAction) planSync(dir string) (*roadmap.Roadmap, []roadmap.SyncChange, error) {
+	r, err := roadmap.Load(filepath.Join(dir, roadmap.DefaultFile))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues, err := git.New(dir).ListIssues("all", roadmapIssuesLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs := make([]roadmap.IssueRef, 0, len(issues))
+	for _, issue := range issues {
+		refs = append(refs, roadmap.IssueRef{Number: issue.Number, Title: issue.Title, Closed: issue.IsClosed()})
+	}
+	return r, r.PlanIssueSync(refs), nil
+}
+
+// applySync creates issues for SyncCreate changes, records all changes in
+// ROADMAP.json, and saves it. Progress made before an error is saved so a
+// retry does not open duplicate issues.
+func (a *RoadmapAction) applySync(dir string, r *roadmap.Roadmap, changes []roadmap.SyncChange, output *strings.Builder) error {
+	g := git.New(dir)
+	label := a.IssueLabel
+	if label == "" {
+		label = DefaultRoadmapIssueLabel
+	}
+
+	var syncErr error
+	labelReady := false
+	for _, c := range changes {
+		if c.Action == roadmap.SyncCreate {
+			item := r.Item(c.ItemID)
+			if item == nil {
+				syncErr = fmt.Errorf("roadmap item %s not found", c.ItemID)
+				break
+			}
+			if !labelReady {
+				if _, err := g.EnsureLabel(label, "0e8a16", "Tracked in "+roadmap.DefaultFile); err != nil {
+					syncErr = err
+					break
+				}
+				labelReady = true
+			}
+			issue, err := g.CreateIssue(c.Title, roadmapIssueBody(item), []string{label})
+			if err != nil {
+				syncErr = err
+				break
+			}
+			c.Issue = issue.Number
+		}
+		if err := r.ApplySync(c); err != nil {
+			syncErr = err
+			break
+		}
+		fmt.Fprintf(output, "  %s\n", c)
+	}
+
+	if err := r.Save(filepath.Join(dir, roadmap.DefaultFile)); err != nil {
+		return fmt.Errorf("writing %s: %w", roadmap.DefaultFile, err)
+	}
+	return syncErr
+}
+
+// roadmapIssueBody returns the body of an issue created for a roadmap item.
+func roadmapIssueBody(item *roadmap.Item) string {
+	var body strings.Builder
+	if item.Description != "" {
+		body.WriteString(item.Description + "\n\n")
+	}
+	fmt.Fprintf(&body, "Tracked in %s as `%s`", roadmap.DefaultFile, item.ID)
+	if item.Version != "" {
+		fmt.Fprintf(&body, " for %s", item.Version)
+	}
+	body.WriteString(".\n")
+	return body.String()
 }
 
 // Validate runs sroadmap validate on ROADMAP.json.
 func (a *RoadmapAction) Validate(dir string) error {
 	if !commandExists("sroadmap") {
-		return fmt.Errorf("sroadmap not found in PATH")
+		return proc.NotFound("sroadmap")
 	}
 
 	result := runCommand("validate", dir, "sroadmap", "validate", "ROADMAP.json")
@@ -148,7 +327,7 @@ func (a *RoadmapAction) Validate(dir string) error {
 // Generate runs sroadmap generate to create ROADMAP.md.
 func (a *RoadmapAction) Generate(dir string) error {
 	if !commandExists("sroadmap") {
-		return fmt.Errorf("sroadmap not found in PATH")
+		return proc.NotFound("sroadmap")
 	}
 
 	result := runCommand("generate", dir, "sroadmap", "generate", "-i", "ROADMAP.json", "-o", "ROADMAP.md")
@@ -162,7 +341,7 @@ func (a *RoadmapAction) Generate(dir string) error {
 // Stats returns roadmap statistics.
 func (a *RoadmapAction) Stats(dir string) (string, error) {
 	if !commandExists("sroadmap") {
-		return "", fmt.Errorf("sroadmap not found in PATH")
+		return "", proc.NotFound("sroadmap")
 	}
 
 	result := runCommand("stats", dir, "sroadmap", "stats", "ROADMAP.json")
@@ -0,0 +1,147 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// DefaultTagTemplate is the annotation template used when none is configured.
+const DefaultTagTemplate = `Release {{.Version}}
+{{- if .Highlights}}
+
+Highlights:
+{{- range .Highlights}}
+- {{.}}
+{{- end}}
+{{- end}}
+`
+
+// TagAction creates and pushes an annotated release tag at HEAD.
+type TagAction struct {
+	Sign     bool   // Create a signed tag
+	Template string // Annotation template; DefaultTagTemplate if empty
+	NoPush   bool   // Create the tag locally without pushing
+}
+
+// Name returns the action name.
+func (a *TagAction) Name() string {
+	return "tag"
+}
+
+// Run executes the tag action directly.
+func (a *TagAction) Run(dir string, opts Options) Result {
+	var output strings.Builder
+
+	message, err := a.prepare(dir, opts)
+	if err != nil {
+		return Result{Name: "tag", Success: false, Error: err}
+	}
+
+	fmt.Fprintf(&output, "Tag %s annotation:\n\n%s\n", opts.Version, indent(message))
+
+	if opts.DryRun {
+		fmt.Fprintf(&output, "\n[Dry run] Would create tag %s", opts.Version)
+		if a.Sign {
+			output.WriteString(" (signed)")
+		}
+		if !a.NoPush {
+			output.WriteString(" and push it")
+		}
+		output.WriteString("\n")
+		return Result{Name: "tag", Success: true, Output: output.String()}
+	}
+
+	g := git.New(dir)
+	if err := g.CreateTag(opts.Version, message, a.Sign); err != nil {
+		return Result{Name: "tag", Success: false, Error: err, Output: output.String()}
+	}
+	fmt.Fprintf(&output, "\nCreated tag: %s\n", opts.Version)
+
+	if !a.NoPush {
+		if err := g.PushTag(opts.Version); err != nil {
+			// Clean up the local tag so the command can be retried
+			_ = g.DeleteTag(opts.Version)
+			return Result{Name: "tag", Success: false, Error: err, Output: output.String()}
+		}
+		fmt.Fprintf(&output, "Pushed tag: %s\n", opts.Version)
+	}
+
+	return Result{Name: "tag", Success: true, Output: output.String()}
+}
+
+// Propose generates proposals for interactive mode.
+func (a *TagAction) Propose(dir string, opts Options) ([]Proposal, error) {
+	message, err := a.prepare(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+	return []Proposal{
+		{
+			Description: fmt.Sprintf("Create tag %s", opts.Version),
+			NewContent:  message,
+			Metadata: map[string]string{
+				"version": opts.Version,
+				"sign":    fmt.Sprintf("%t", a.Sign),
+				"push":    fmt.Sprintf("%t", !a.NoPush),
+			},
+		},
+	}, nil
+}
+
+// Apply applies approved proposals.
+func (a *TagAction) Apply(dir string, proposals []Proposal) Result {
+	if len(proposals) == 0 {
+		return Result{Name: "tag", Skipped: true, Reason: "no proposals approved"}
+	}
+	return a.Run(dir, Options{Version: proposals[0].Metadata["version"]})
+}
+
+// prepare validates the version against local and remote tags and renders
+// the annotation message.
+func (a *TagAction) prepare(dir string, opts Options) (string, error) {
+	if !strings.HasPrefix(opts.Version, "v") || !semver.IsValid(opts.Version) {
+		return "", fmt.Errorf("invalid version %q: expected vMAJOR.MINOR.PATCH", opts.Version)
+	}
+
+	g := git.New(dir)
+	tags, err := g.AllTags()
+	if err != nil {
+		return "", err
+	}
+	for _, t := range tags {
+		if t == opts.Version {
+			return "", fmt.Errorf("tag %s already exists locally", opts.Version)
+		}
+	}
+	if !a.NoPush {
+		exists, err := g.RemoteTagExists(opts.Version)
+		if err != nil {
+			return "", fmt.Errorf("failed to check remote tags: %w", err)
+		}
+		if exists {
+			return "", fmt.Errorf("tag %s already exists on %s", opts.Version, g.Remote)
+		}
+	}
+
+	data := LoadMessageData(dir, opts.Version)
+	return RenderTagMessage(a.Template, data)
+}
+
+// RenderTagMessage renders a tag annotation template. An empty template
+// uses DefaultTagTemplate.
+func RenderTagMessage(tmpl string, data MessageData) (string, error) {
+	return RenderMessage("tag", tmpl, DefaultTagTemplate, data)
+}
+
+func indent(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,382 @@
+// Package apireport generates and compares reports of a Go module's
+// exported API, one line per exported identifier in the format of Go's own
+// api/*.txt files, e.g.
+//
+//	pkg github.com/org/mod/pkg/x, func Parse(string) (*Doc, error)
+//	pkg github.com/org/mod/pkg/x, type Doc struct, Title string
+//
+// A report committed under api/ is a baseline: regenerating it shows every
+// change to the API in review, and comparing it with the baseline of the
+// last release shows whether the release removed or changed anything.
+package apireport
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Dir is the directory, relative to the module, holding the baseline.
+const Dir = "api"
+
+// Report is a module's exported API: the lines of each package's file in
+// Dir, keyed by file name, e.g. "pkg.checks.txt" for pkg/checks. Lines
+// are sorted.
+type Report map[string][]string
+
+// Lines returns the lines of every file in r, sorted.
+func (r Report) Lines() []string {
+	var lines []string
+	for _, l := range r {
+		lines = append(lines, l...)
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// FileName returns the name of the report file of the package in rel, a
+// slash-separated directory relative to the module, with "." for the
+// module root, whose file is named after the module's last path element.
+func FileName(module, rel string) string {
+	if rel == "." {
+		return path.Base(module) + ".txt"
+	}
+	return strings.ReplaceAll(rel, "/", ".") + ".txt"
+}
+
+// Generate returns the exported API of the Go module in dir. Test files,
+// main packages, and internal/, testdata/, and vendor/ directories are
+// skipped, since none of them are importable API.
+func Generate(dir string) (Report, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	module := modfile.ModulePath(data)
+	if module == "" {
+		return nil, fmt.Errorf("%s: no module path", filepath.Join(dir, "go.mod"))
+	}
+
+	report := make(Report)
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if p != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "internal" || name == "testdata" || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			// A nested module is not part of this one
+			if p != dir && fileExists(filepath.Join(p, "go.mod")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, p, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+		if file.Name.Name == "main" {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, filepath.Dir(p))
+		rel = filepath.ToSlash(rel)
+		importPath := module
+		if rel != "." {
+			importPath += "/" + rel
+		}
+		name := FileName(module, rel)
+		for _, decl := range file.Decls {
+			for _, f := range features(decl) {
+				report[name] = append(report[name], "pkg "+importPath+", "+f)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, lines := range report {
+		// Files for other platforms may declare the same API
+		slices.Sort(lines)
+		report[name] = slices.Compact(lines)
+	}
+	return report, nil
+}
+
+// features returns the API features declared by decl, such as
+// "func Parse(string) error" or "type Doc struct, Title string".
+func features(decl ast.Decl) []string {
+	var feats []string
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return []string{"func " + d.Name.Name + typeParams(d.Type.TypeParams) + signature(d.Type)}
+		}
+		recv := d.Recv.List[0].Type
+		if !ast.IsExported(baseType(recv)) {
+			return nil
+		}
+		return []string{fmt.Sprintf("method (%s) %s%s", types.ExprString(recv), d.Name.Name, signature(d.Type))}
+	case *ast.GenDecl:
+		var typ ast.Expr // type of the previous const, repeated by iota-style specs
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					feats = append(feats, typeFeatures(s)...)
+				}
+			case *ast.ValueSpec:
+				if d.Tok == token.VAR || s.Type != nil || len(s.Values) > 0 {
+					typ = s.Type
+				}
+				for _, n := range s.Names {
+					if !n.IsExported() {
+						continue
+					}
+					f := d.Tok.String() + " " + n.Name
+					if typ != nil {
+						f += " " + types.ExprString(typ)
+					}
+					feats = append(feats, f)
+				}
+			}
+		}
+	}
+	return feats
+}
+
+// typeFeatures returns the features of an exported type: the type itself,
+// and the exported fields of a struct or methods of an interface.
+func typeFeatures(s *ast.TypeSpec) []string {
+	head := "type " + s.Name.Name + typeParams(s.TypeParams)
+	if s.Assign.IsValid() {
+		return []string{head + " = " + types.ExprString(s.Type)}
+	}
+	switch t := s.Type.(type) {
+	case *ast.StructType:
+		feats := []string{head + " struct"}
+		for _, f := range t.Fields.List {
+			typ := types.ExprString(f.Type)
+			if len(f.Names) == 0 {
+				if ast.IsExported(baseType(f.Type)) {
+					feats = append(feats, head+" struct, embedded "+typ)
+				}
+				continue
+			}
+			for _, n := range f.Names {
+				if n.IsExported() {
+					feats = append(feats, head+" struct, "+n.Name+" "+typ)
+				}
+			}
+		}
+		return feats
+	case *ast.InterfaceType:
+		feats := []string{head + " interface"}
+		for _, f := range t.Methods.List {
+			if len(f.Names) == 0 {
+				feats = append(feats, head+" interface, embedded "+types.ExprString(f.Type))
+				continue
+			}
+			ft, ok := f.Type.(*ast.FuncType)
+			if !ok {
+				continue
+			}
+			for _, n := range f.Names {
+				// An unexported method is still part of the contract:
+				// nothing outside the package can implement the interface
+				feats = append(feats, head+" interface, "+n.Name+signature(ft))
+			}
+		}
+		return feats
+	}
+	return []string{head + " " + types.ExprString(s.Type)}
+}
+
+// signature formats the parameter and result types of a function, without
+// names, e.g. "(string, ...int) (bool, error)".
+func signature(ft *ast.FuncType) string {
+	sig := "(" + strings.Join(fieldTypes(ft.Params), ", ") + ")"
+	results := fieldTypes(ft.Results)
+	switch {
+	case len(results) == 1:
+		sig += " " + results[0]
+	case len(results) > 1:
+		sig += " (" + strings.Join(results, ", ") + ")"
+	}
+	return sig
+}
+
+// typeParams formats type parameters, e.g. "[K comparable, V any]", or ""
+// if there are none.
+func typeParams(list *ast.FieldList) string {
+	if list == nil || len(list.List) == 0 {
+		return ""
+	}
+	var params []string
+	for _, f := range list.List {
+		for _, n := range f.Names {
+			params = append(params, n.Name+" "+types.ExprString(f.Type))
+		}
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+// fieldTypes returns the type of each field in list, repeated for fields
+// declaring several names.
+func fieldTypes(list *ast.FieldList) []string {
+	if list == nil {
+		return nil
+	}
+	var ts []string
+	for _, f := range list.List {
+		n := max(len(f.Names), 1)
+		for range n {
+			ts = append(ts, types.ExprString(f.Type))
+		}
+	}
+	return ts
+}
+
+// baseType returns the name of a receiver or embedded type such as *T,
+// T[K], or pkg.T.
+func baseType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return baseType(t.X)
+	case *ast.IndexExpr:
+		return baseType(t.X)
+	case *ast.IndexListExpr:
+		return baseType(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// Load reads the baseline committed in dir's Dir. It returns an empty
+// report if there is none.
+func Load(dir string) (Report, error) {
+	report := make(Report)
+	files, err := filepath.Glob(filepath.Join(dir, Dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		report[filepath.Base(f)] = Parse(data)
+	}
+	return report, nil
+}
+
+// Parse returns the lines of a report file, ignoring blank lines.
+func Parse(data []byte) []string {
+	var lines []string
+	for _, l := range strings.Split(string(data), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// Format returns the contents of a report file.
+func Format(lines []string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// Write replaces the baseline in dir's Dir with r, removing files of
+// packages no longer in it. It returns the names of the files it wrote or
+// removed.
+func Write(dir string, r Report) ([]string, error) {
+	old, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, Dir), 0755); err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for name, lines := range r {
+		if slices.Equal(old[name], lines) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, Dir, name), Format(lines), 0644); err != nil {
+			return changed, err
+		}
+		changed = append(changed, name)
+	}
+	for name := range old {
+		if _, ok := r[name]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, Dir, name)); err != nil {
+			return changed, err
+		}
+		changed = append(changed, name)
+	}
+	slices.Sort(changed)
+	return changed, nil
+}
+
+// Diff is the difference between two reports.
+type Diff struct {
+	Added   []string // Lines only in the new report
+	Removed []string // Lines only in the old report: removed or changed API
+}
+
+// Empty reports whether the reports are the same.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0
+}
+
+// Compare returns the lines added and removed from old to new.
+func Compare(old, new Report) Diff {
+	var d Diff
+	oldLines, newLines := old.Lines(), new.Lines()
+	for _, l := range newLines {
+		if _, found := slices.BinarySearch(oldLines, l); !found {
+			d.Added = append(d.Added, l)
+		}
+	}
+	for _, l := range oldLines {
+		if _, found := slices.BinarySearch(newLines, l); !found {
+			d.Removed = append(d.Removed, l)
+		}
+	}
+	return d
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,156 @@
+package apireport
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/mod\n\ngo 1.22\n",
+		"mod.go": `package mod
+
+// Level is a log level.
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	hidden
+)
+
+var Default, other = New("x"), 1
+
+type Doc struct {
+	Title   string
+	body    string
+	Base
+	*Other
+}
+
+type Base struct{}
+type Other struct{}
+
+type Reader interface {
+	Read(p []byte) (n int, err error)
+	Close() error
+}
+
+type Pair[K comparable, V any] struct{ Key K }
+
+type Alias = Doc
+
+func New(name string, opts ...int) *Doc { return nil }
+func (d *Doc) Render(w, h int) (string, error) { return "", nil }
+func (d Doc) secret() {}
+func (p Pair[K, V]) Get() V { var v V; return v }
+func Map[T any](s []T) []T { return s }
+func helper() {}
+`,
+		"pkg/sub/sub.go":        "package sub\n\nfunc Run() error { return nil }\n",
+		"pkg/sub/sub_test.go":   "package sub\n\nfunc TestOnly() {}\n",
+		"internal/x/x.go":       "package x\n\nfunc Hidden() {}\n",
+		"cmd/tool/main.go":      "package main\n\nfunc Exported() {}\n",
+		"nested/go.mod":         "module example.com/nested\n",
+		"nested/nested.go":      "package nested\n\nfunc Other() {}\n",
+		"pkg/sub/sub_linux.go":  "package sub\n\nfunc Platform() {}\n",
+		"pkg/sub/sub_darwin.go": "package sub\n\nfunc Platform() {}\n",
+	})
+
+	r, err := Generate(dir)
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if len(r) != 2 {
+		t.Errorf("Generate() files = %v, want mod.txt and pkg.sub.txt", r)
+	}
+
+	p := "pkg example.com/mod, "
+	want := []string{
+		p + "const Debug Level",
+		p + "const Info Level",
+		p + "func Map[T any]([]T) []T",
+		p + "func New(string, ...int) *Doc",
+		p + "method (*Doc) Render(int, int) (string, error)",
+		p + "method (Pair[K, V]) Get() V",
+		p + "type Alias = Doc",
+		p + "type Base struct",
+		p + "type Doc struct",
+		p + "type Doc struct, Title string",
+		p + "type Doc struct, embedded *Other",
+		p + "type Doc struct, embedded Base",
+		p + "type Level int",
+		p + "type Other struct",
+		p + "type Pair[K comparable, V any] struct",
+		p + "type Pair[K comparable, V any] struct, Key K",
+		p + "type Reader interface",
+		p + "type Reader interface, Close() error",
+		p + "type Reader interface, Read([]byte) (int, error)",
+		p + "var Default",
+	}
+	if got := r["mod.txt"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("mod.txt =\n%q\nwant\n%q", got, want)
+	}
+	wantSub := []string{
+		"pkg example.com/mod/pkg/sub, func Platform()",
+		"pkg example.com/mod/pkg/sub, func Run() error",
+	}
+	if got := r["pkg.sub.txt"]; !reflect.DeepEqual(got, wantSub) {
+		t.Errorf("pkg.sub.txt = %q, want %q", got, wantSub)
+	}
+}
+
+func TestWriteLoadCompare(t *testing.T) {
+	dir := t.TempDir()
+	old := Report{
+		"mod.txt":     {"pkg m, func A()", "pkg m, func B()"},
+		"pkg.old.txt": {"pkg m/pkg/old, func C()"},
+	}
+	if _, err := Write(dir, old); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := Report{"mod.txt": {"pkg m, func A()", "pkg m, func B(int)"}}
+	changed, err := Write(dir, updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"mod.txt", "pkg.old.txt"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("Write() changed = %v, want %v", changed, want)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, updated) {
+		t.Errorf("Load() = %v, want %v", loaded, updated)
+	}
+
+	d := Compare(old, loaded)
+	if want := []string{"pkg m, func B(int)"}; !reflect.DeepEqual(d.Added, want) {
+		t.Errorf("Added = %q, want %q", d.Added, want)
+	}
+	if want := []string{"pkg m, func B()", "pkg m/pkg/old, func C()"}; !reflect.DeepEqual(d.Removed, want) {
+		t.Errorf("Removed = %q, want %q", d.Removed, want)
+	}
+	if !Compare(loaded, updated).Empty() {
+		t.Error("Compare() of equal reports is not empty")
+	}
+}
@@ -0,0 +1,216 @@
+// Package artifacts computes checksums for built release artifacts and
+// produces SLSA provenance for them.
+package artifacts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// DefaultChecksumsFile is the checksums file name written next to the
+// artifacts when none is configured.
+const DefaultChecksumsFile = "checksums.txt"
+
+// Checksum is the SHA256 digest of an artifact.
+type Checksum struct {
+	Path   string // Path to the artifact
+	SHA256 string // Hex-encoded digest
+}
+
+// Name returns the artifact file name as it appears in checksums.txt.
+func (c Checksum) Name() string {
+	return filepath.Base(c.Path)
+}
+
+// Collect returns the files in dir matching the glob patterns, sorted and
+// without duplicates. Patterns are relative to dir. Directories and existing
+// checksums and attestation files are ignored.
+func Collect(dir string, patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern %q: %w", pattern, err)
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil || info.IsDir() || seen[m] || isGenerated(m) {
+				continue
+			}
+			seen[m] = true
+			paths = append(paths, m)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// isGenerated reports whether path is a checksums or attestation file
+// written by a previous run.
+func isGenerated(path string) bool {
+	name := filepath.Base(path)
+	return name == DefaultChecksumsFile || strings.HasSuffix(name, ".intoto.jsonl")
+}
+
+// Checksums computes the SHA256 digest of each file.
+func Checksums(paths []string) ([]Checksum, error) {
+	sums := make([]Checksum, 0, len(paths))
+	for _, path := range paths {
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return nil, err
+		}
+		sums = append(sums, Checksum{Path: path, SHA256: sum})
+	}
+	return sums, nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// FormatChecksums returns the checksums in the format of sha256sum, which
+// "sha256sum -c" can verify from the artifacts' directory.
+func FormatChecksums(sums []Checksum) []byte {
+	var b strings.Builder
+	for _, c := range sums {
+		fmt.Fprintf(&b, "%s  %s\n", c.SHA256, c.Name())
+	}
+	return []byte(b.String())
+}
+
+// ProvenanceInfo describes the release a provenance predicate is for.
+type ProvenanceInfo struct {
+	Repository string    // Repository URL, e.g. "https://github.com/owner/repo"
+	Tag        string    // Release tag
+	Commit     string    // Commit SHA the tag points to
+	BuilderID  string    // URI identifying what produced the provenance
+	StartedOn  time.Time // When the release started
+}
+
+// BuildType identifies provenance produced by the release workflow.
+const BuildType = "https://github.com/plexusone/agent-team-release/release@v1"
+
+// Provenance returns a SLSA v1 provenance predicate for the artifacts,
+// suitable for "cosign attest-blob --type slsaprovenance1". The artifacts
+// are listed as byproducts with their digests; the attestation subject is
+// the checksums file that covers them.
+func Provenance(info ProvenanceInfo, sums []Checksum) ([]byte, error) {
+	type digest map[string]string
+	type resource struct {
+		URI    string `json:"uri,omitempty"`
+		Name   string `json:"name,omitempty"`
+		Digest digest `json:"digest"`
+	}
+
+	byproducts := make([]resource, 0, len(sums))
+	for _, c := range sums {
+		byproducts = append(byproducts, resource{Name: c.Name(), Digest: digest{"sha256": c.SHA256}})
+	}
+
+	predicate := map[string]any{
+		"buildDefinition": map[string]any{
+			"buildType": BuildType,
+			"externalParameters": map[string]string{
+				"repository": info.Repository,
+				"ref":        "refs/tags/" + info.Tag,
+			},
+			"resolvedDependencies": []resource{
+				{
+					URI:    "git+" + info.Repository + "@refs/tags/" + info.Tag,
+					Digest: digest{"gitCommit": info.Commit},
+				},
+			},
+		},
+		"runDetails": map[string]any{
+			"builder": map[string]string{"id": info.BuilderID},
+			"metadata": map[string]string{
+				"startedOn":  info.StartedOn.UTC().Format(time.RFC3339),
+				"finishedOn": time.Now().UTC().Format(time.RFC3339),
+			},
+			"byproducts": byproducts,
+		},
+	}
+	return json.MarshalIndent(predicate, "", "  ")
+}
+
+// CosignAvailable reports whether cosign is installed.
+func CosignAvailable() bool {
+	_, err := exec.LookPath("cosign")
+	return err == nil
+}
+
+// Attest signs the provenance predicate for blob with cosign and writes the
+// in-toto attestation to blob + ".intoto.jsonl", returning its path. With an
+// empty key, cosign signs keylessly, which needs an OIDC identity.
+func Attest(blob string, predicate []byte, key string) (string, error) {
+	f, err := os.CreateTemp("", "atrelease-provenance-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(predicate); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	out := blob + ".intoto.jsonl"
+	args := []string{"attest-blob", "--yes",
+		"--type", "slsaprovenance1",
+		"--predicate", f.Name(),
+		"--output-attestation", out,
+	}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	args = append(args, blob)
+
+	cmd := proc.Command("cosign", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("cosign attest-blob failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return out, nil
+}
+
+// SignBlob signs blob with cosign and writes the signature and certificate
+// bundle to blob + ".sigstore.json", returning its path. With an empty key,
+// cosign signs keylessly, which needs an OIDC identity.
+func SignBlob(blob, key string) (string, error) {
+	out := blob + ".sigstore.json"
+	args := []string{"sign-blob", "--yes", "--bundle", out}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	args = append(args, blob)
+
+	cmd := proc.Command("cosign", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("cosign sign-blob failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return out, nil
+}
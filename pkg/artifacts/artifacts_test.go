@@ -0,0 +1,116 @@
+package artifacts
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "dist", "app_linux.tar.gz"), "linux")
+	writeFile(t, filepath.Join(dir, "dist", "app_darwin.tar.gz"), "darwin")
+	writeFile(t, filepath.Join(dir, "dist", "checksums.txt"), "old")
+	writeFile(t, filepath.Join(dir, "dist", "checksums.txt.intoto.jsonl"), "old")
+	if err := os.Mkdir(filepath.Join(dir, "dist", "app_windows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := Collect(dir, []string{"dist/*", "dist/*.tar.gz"})
+	if err != nil {
+		t.Fatalf("Collect() error: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "dist", "app_darwin.tar.gz"),
+		filepath.Join(dir, "dist", "app_linux.tar.gz"),
+	}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("Collect() = %v, want %v", paths, want)
+	}
+
+	if _, err := Collect(dir, []string{"dist/["}); err == nil {
+		t.Error("Collect(invalid pattern) error = nil, want error")
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.tar.gz")
+	writeFile(t, path, "hello\n")
+
+	sums, err := Checksums([]string{path})
+	if err != nil {
+		t.Fatalf("Checksums() error: %v", err)
+	}
+	want := "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  app.tar.gz\n"
+	if got := string(FormatChecksums(sums)); got != want {
+		t.Errorf("FormatChecksums() = %q, want %q", got, want)
+	}
+
+	if _, err := Checksums([]string{filepath.Join(dir, "missing")}); err == nil {
+		t.Error("Checksums(missing) error = nil, want error")
+	}
+}
+
+func TestProvenance(t *testing.T) {
+	data, err := Provenance(ProvenanceInfo{
+		Repository: "https://github.com/o/r",
+		Tag:        "v1.2.0",
+		Commit:     "abc123",
+		BuilderID:  "https://github.com/plexusone/agent-team-release",
+		StartedOn:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}, []Checksum{{Path: "dist/app.tar.gz", SHA256: "deadbeef"}})
+	if err != nil {
+		t.Fatalf("Provenance() error: %v", err)
+	}
+
+	var p struct {
+		BuildDefinition struct {
+			BuildType            string `json:"buildType"`
+			ResolvedDependencies []struct {
+				URI    string            `json:"uri"`
+				Digest map[string]string `json:"digest"`
+			} `json:"resolvedDependencies"`
+		} `json:"buildDefinition"`
+		RunDetails struct {
+			Metadata struct {
+				StartedOn string `json:"startedOn"`
+			} `json:"metadata"`
+			Byproducts []struct {
+				Name   string            `json:"name"`
+				Digest map[string]string `json:"digest"`
+			} `json:"byproducts"`
+		} `json:"runDetails"`
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+
+	if p.BuildDefinition.BuildType != BuildType {
+		t.Errorf("buildType = %q", p.BuildDefinition.BuildType)
+	}
+	deps := p.BuildDefinition.ResolvedDependencies
+	if len(deps) != 1 || deps[0].URI != "git+https://github.com/o/r@refs/tags/v1.2.0" || deps[0].Digest["gitCommit"] != "abc123" {
+		t.Errorf("resolvedDependencies = %+v", deps)
+	}
+	if p.RunDetails.Metadata.StartedOn != "2026-01-02T03:04:05Z" {
+		t.Errorf("startedOn = %q", p.RunDetails.Metadata.StartedOn)
+	}
+	by := p.RunDetails.Byproducts
+	if len(by) != 1 || by[0].Name != "app.tar.gz" || by[0].Digest["sha256"] != "deadbeef" {
+		t.Errorf("byproducts = %+v", by)
+	}
+}
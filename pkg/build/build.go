@@ -0,0 +1,269 @@
+// Package build cross-compiles Go commands and packages them into versioned
+// release archives, for projects that don't use goreleaser.
+package build
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// DefaultOutputDir is where archives are written when none is configured.
+const DefaultOutputDir = "dist"
+
+// DefaultTargets are the platforms built when none are configured.
+var DefaultTargets = []string{
+	"linux/amd64", "linux/arm64",
+	"darwin/amd64", "darwin/arm64",
+	"windows/amd64",
+}
+
+// DefaultFiles are included in each archive when they exist.
+var DefaultFiles = []string{"README.md", "LICENSE", "CHANGELOG.md"}
+
+// Target is a GOOS/GOARCH pair.
+type Target struct {
+	GOOS   string
+	GOARCH string
+}
+
+// String returns the target as "goos/goarch".
+func (t Target) String() string {
+	return t.GOOS + "/" + t.GOARCH
+}
+
+// ParseTargets parses "goos/goarch" strings.
+func ParseTargets(specs []string) ([]Target, error) {
+	targets := make([]Target, 0, len(specs))
+	for _, spec := range specs {
+		goos, goarch, ok := strings.Cut(strings.TrimSpace(spec), "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return nil, fmt.Errorf("invalid target %q: expected goos/goarch", spec)
+		}
+		targets = append(targets, Target{GOOS: goos, GOARCH: goarch})
+	}
+	return targets, nil
+}
+
+// Options configures a build.
+type Options struct {
+	Main      string   // Package to build, e.g. "./cmd/tool"
+	Binary    string   // Binary name; defaults to the base name of Main
+	Project   string   // Archive name prefix; defaults to Binary
+	Version   string   // Release version, e.g. "v1.2.0"
+	Commit    string   // Commit SHA, available to LDFlags
+	LDFlags   string   // -ldflags template with .Version, .Commit, .Date
+	OutputDir string   // Archive directory, relative to the project; DefaultOutputDir if empty
+	Files     []string // Extra files to include in archives, relative to the project
+}
+
+func (o Options) binary() string {
+	if o.Binary != "" {
+		return o.Binary
+	}
+	return filepath.Base(filepath.Clean(o.Main))
+}
+
+func (o Options) project() string {
+	if o.Project != "" {
+		return o.Project
+	}
+	return o.binary()
+}
+
+func (o Options) outputDir() string {
+	if o.OutputDir != "" {
+		return o.OutputDir
+	}
+	return DefaultOutputDir
+}
+
+// ArchiveName returns the archive file name for a target, following the
+// goreleaser convention: <project>_<version>_<os>_<arch>.tar.gz, with amd64
+// as x86_64, 386 as i386, and .zip on Windows.
+func ArchiveName(project, version string, t Target) string {
+	arch := t.GOARCH
+	switch arch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if t.GOOS == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s_%s%s", project, strings.TrimPrefix(version, "v"), t.GOOS, arch, ext)
+}
+
+// ArchivePath returns where the archive for a target is written, relative to
+// the project directory.
+func ArchivePath(opts Options, t Target) string {
+	return filepath.Join(opts.outputDir(), ArchiveName(opts.project(), opts.Version, t))
+}
+
+// Build cross-compiles opts.Main for the target with CGO disabled and
+// writes the archive, returning its path relative to dir.
+func Build(dir string, opts Options, t Target) (string, error) {
+	if opts.Main == "" {
+		return "", fmt.Errorf("no main package configured")
+	}
+	ldflags, err := renderLDFlags(opts)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.MkdirTemp("", "atrelease-build-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	binary := opts.binary()
+	if t.GOOS == "windows" {
+		binary += ".exe"
+	}
+	args := []string{"build", "-trimpath", "-o", filepath.Join(tmp, binary)}
+	if ldflags != "" {
+		args = append(args, "-ldflags", ldflags)
+	}
+	args = append(args, opts.Main)
+
+	cmd := proc.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOOS="+t.GOOS, "GOARCH="+t.GOARCH, "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("go build %s failed: %w\n%s", t, err, strings.TrimSpace(string(out)))
+	}
+
+	files := []archiveFile{{Name: binary, Path: filepath.Join(tmp, binary), Mode: 0755}}
+	for _, f := range append(append([]string{}, DefaultFiles...), opts.Files...) {
+		path := filepath.Join(dir, f)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && !hasFile(files, filepath.Base(f)) {
+			files = append(files, archiveFile{Name: filepath.Base(f), Path: path, Mode: 0644})
+		}
+	}
+
+	rel := ArchivePath(opts, t)
+	out := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(out, ".zip") {
+		err = writeZip(out, files)
+	} else {
+		err = writeTarGz(out, files)
+	}
+	if err != nil {
+		return "", fmt.Errorf("writing %s: %w", rel, err)
+	}
+	return rel, nil
+}
+
+// renderLDFlags renders the -ldflags template.
+func renderLDFlags(opts Options) (string, error) {
+	if opts.LDFlags == "" {
+		return "", nil
+	}
+	tmpl, err := template.New("ldflags").Parse(opts.LDFlags)
+	if err != nil {
+		return "", fmt.Errorf("invalid ldflags template: %w", err)
+	}
+	var buf bytes.Buffer
+	data := struct{ Version, Commit, Date string }{
+		Version: strings.TrimPrefix(opts.Version, "v"),
+		Commit:  opts.Commit,
+		Date:    time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering ldflags template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+type archiveFile struct {
+	Name string
+	Path string
+	Mode int64
+}
+
+func hasFile(files []archiveFile, name string) bool {
+	for _, f := range files {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func writeTarGz(path string, files []archiveFile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, af := range files {
+		data, err := os.ReadFile(af.Path)
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{Name: af.Name, Mode: af.Mode, Size: int64(len(data)), ModTime: time.Now()}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeZip(path string, files []archiveFile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, af := range files {
+		hdr := &zip.FileHeader{Name: af.Name, Method: zip.Deflate, Modified: time.Now()}
+		hdr.SetMode(os.FileMode(af.Mode))
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(af.Path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(w, src)
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,115 @@
+package build
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := ParseTargets([]string{"linux/amd64", " windows/arm64 "})
+	if err != nil {
+		t.Fatalf("ParseTargets() error: %v", err)
+	}
+	if len(targets) != 2 || targets[0].String() != "linux/amd64" || targets[1] != (Target{GOOS: "windows", GOARCH: "arm64"}) {
+		t.Errorf("ParseTargets() = %v", targets)
+	}
+
+	for _, bad := range []string{"linux", "linux/", "/amd64", "linux/amd64/v2"} {
+		if _, err := ParseTargets([]string{bad}); err == nil {
+			t.Errorf("ParseTargets(%q) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		target Target
+		want   string
+	}{
+		{Target{"linux", "amd64"}, "tool_1.2.0_linux_x86_64.tar.gz"},
+		{Target{"linux", "386"}, "tool_1.2.0_linux_i386.tar.gz"},
+		{Target{"darwin", "arm64"}, "tool_1.2.0_darwin_arm64.tar.gz"},
+		{Target{"windows", "amd64"}, "tool_1.2.0_windows_x86_64.zip"},
+	}
+	for _, tt := range tests {
+		if got := ArchiveName("tool", "v1.2.0", tt.target); got != tt.want {
+			t.Errorf("ArchiveName(%s) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/tool\n\ngo 1.21\n",
+		"cmd/tool/main.go": "package main\n\nvar version = \"dev\"\n\nfunc main() { println(version) }\n",
+		"README.md":        "# Tool\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := Options{Main: "./cmd/tool", Version: "v1.2.0", LDFlags: "-s -w -X main.version={{.Version}}"}
+
+	rel, err := Build(dir, opts, Target{GOOS: "linux", GOARCH: "amd64"})
+	if err != nil {
+		t.Fatalf("Build(linux) error: %v", err)
+	}
+	if rel != filepath.Join("dist", "tool_1.2.0_linux_x86_64.tar.gz") {
+		t.Errorf("Build(linux) = %q", rel)
+	}
+	f, err := os.Open(filepath.Join(dir, rel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, hdr.Name)
+	}
+	if strings.Join(names, ",") != "tool,README.md" {
+		t.Errorf("tar entries = %v, want [tool README.md]", names)
+	}
+
+	rel, err = Build(dir, opts, Target{GOOS: "windows", GOARCH: "amd64"})
+	if err != nil {
+		t.Fatalf("Build(windows) error: %v", err)
+	}
+	zr, err := zip.OpenReader(filepath.Join(dir, rel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	if len(zr.File) != 2 || zr.File[0].Name != "tool.exe" {
+		t.Errorf("zip entries = %d, first %q", len(zr.File), zr.File[0].Name)
+	}
+
+	if _, err := Build(dir, Options{Main: "./cmd/missing"}, Target{GOOS: "linux", GOARCH: "amd64"}); err == nil {
+		t.Error("Build(missing package) error = nil, want error")
+	}
+}
@@ -0,0 +1,149 @@
+// Package changelog reads CHANGELOG.json files in the structured changelog IR
+// format used by schangelog.
+package changelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultFile is the conventional structured changelog file name.
+const DefaultFile = "CHANGELOG.json"
+
+// Changelog is a parsed CHANGELOG.json.
+type Changelog struct {
+	Project    string    `json:"project,omitempty"`
+	Releases   []Release `json:"releases"`
+	Unreleased *Release  `json:"unreleased,omitempty"` // Only set from Markdown
+}
+
+// Release is a single released version. Only the fields used by this tool
+// are decoded; other change categories are ignored.
+type Release struct {
+	Version    string  `json:"version"`
+	Date       string  `json:"date,omitempty"`
+	Yanked     bool    `json:"yanked,omitempty"`
+	Highlights []Entry `json:"highlights,omitempty"`
+	Breaking   []Entry `json:"breaking,omitempty"`
+	Added      []Entry `json:"added,omitempty"`
+	Changed    []Entry `json:"changed,omitempty"`
+	Deprecated []Entry `json:"deprecated,omitempty"`
+	Removed    []Entry `json:"removed,omitempty"`
+	Fixed      []Entry `json:"fixed,omitempty"`
+	Security   []Entry `json:"security,omitempty"`
+}
+
+// Entry is a single changelog line item.
+type Entry struct {
+	Description string `json:"description"`
+	Commit      string `json:"commit,omitempty"`
+	PR          Ref    `json:"pr,omitempty"`
+	Breaking    bool   `json:"breaking,omitempty"`
+}
+
+// Ref is a pull request or issue reference, written in CHANGELOG.json as a
+// number or as a string such as "#12" or a URL.
+type Ref string
+
+// UnmarshalJSON accepts a number or a string.
+func (r *Ref) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*r = Ref(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("reference must be a number or string, got %s", data)
+	}
+	*r = Ref(n.String())
+	return nil
+}
+
+// Number returns the number the reference ends with, such as 12 for "#12"
+// or ".../pull/12", or 0 if there is none.
+func (r Ref) Number() int {
+	s := string(r)
+	i := len(s)
+	for i > 0 && s[i-1] >= '0' && s[i-1] <= '9' {
+		i--
+	}
+	n, _ := strconv.Atoi(s[i:])
+	return n
+}
+
+// Load reads and parses a CHANGELOG.json file.
+func Load(path string) (*Changelog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Changelog
+	if err := Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Release returns the release for version, ignoring a "v" prefix, which
+// Keep a Changelog headings usually omit, or nil if there is none.
+func (c *Changelog) Release(version string) *Release {
+	for i, r := range c.Releases {
+		if strings.TrimPrefix(r.Version, "v") == strings.TrimPrefix(version, "v") {
+			return &c.Releases[i]
+		}
+	}
+	return nil
+}
+
+// Count returns the number of change entries in the release, excluding
+// highlights, which summarize other entries.
+func (r Release) Count() int {
+	return len(r.Breaking) + len(r.Added) + len(r.Changed) + len(r.Deprecated) +
+		len(r.Removed) + len(r.Fixed) + len(r.Security)
+}
+
+// BreakingCount returns the number of breaking changes in the release,
+// counting both the breaking category and highlights marked "BREAKING".
+func (r Release) BreakingCount() int {
+	n := len(r.Breaking)
+	for _, h := range r.Highlights {
+		if strings.Contains(h.Description, "BREAKING") {
+			n++
+		}
+	}
+	return n
+}
+
+// Entries returns the highlights and change entries of the release, in
+// that order.
+func (r Release) Entries() []Entry {
+	var entries []Entry
+	for _, es := range [][]Entry{r.Highlights, r.Breaking, r.Added, r.Changed, r.Deprecated, r.Removed, r.Fixed, r.Security} {
+		entries = append(entries, es...)
+	}
+	return entries
+}
+
+// Loaded is the changelog of a directory as loaded by LoadShared, so that
+// the checks of a validation run parse it once and agree on its errors.
+type Loaded struct {
+	Changelog *Changelog // nil if Err is set
+	Source    string     // File read: DefaultFile or MarkdownFile; empty if neither exists
+	Err       error
+}
+
+// LoadShared loads the changelog in dir as LoadDir does, recording the
+// error instead of returning it.
+func LoadShared(dir string) *Loaded {
+	c, source, err := LoadDir(dir)
+	return &Loaded{Changelog: c, Source: source, Err: err}
+}
+
+// Missing reports whether dir has no changelog at all.
+func (l *Loaded) Missing() bool {
+	return l.Err != nil && os.IsNotExist(l.Err)
+}
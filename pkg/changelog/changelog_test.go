@@ -0,0 +1,110 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testChangelog = `{
+  "irVersion": "1.0",
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v0.2.0",
+      "date": "2026-02-01",
+      "highlights": [
+        {"description": "**BREAKING:** Renamed module path"},
+        {"description": "New status command"}
+      ],
+      "breaking": [{"description": "Removed legacy flag", "commit": "abc1234"}],
+      "added": [{"description": "Status command"}]
+    },
+    {"version": "v0.1.0", "date": "2026-01-01"}
+  ]
+}`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(testChangelog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(c.Releases) != 2 {
+		t.Fatalf("len(Releases) = %d, want 2", len(c.Releases))
+	}
+
+	r := c.Releases[0]
+	if r.Version != "v0.2.0" || r.Date != "2026-02-01" {
+		t.Errorf("Releases[0] = %s %s, want v0.2.0 2026-02-01", r.Version, r.Date)
+	}
+	if len(r.Highlights) != 2 {
+		t.Errorf("len(Highlights) = %d, want 2", len(r.Highlights))
+	}
+	if got := r.BreakingCount(); got != 2 {
+		t.Errorf("BreakingCount() = %d, want 2", got)
+	}
+	if got := c.Releases[1].BreakingCount(); got != 0 {
+		t.Errorf("Releases[1].BreakingCount() = %d, want 0", got)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), DefaultFile)); !os.IsNotExist(err) {
+		t.Errorf("Load() error = %v, want not-exist error", err)
+	}
+}
+
+func TestChangelog_Release(t *testing.T) {
+	c := &Changelog{Releases: []Release{{Version: "v0.2.0"}, {Version: "0.1.0"}}}
+	if r := c.Release("0.2.0"); r == nil || r.Version != "v0.2.0" {
+		t.Errorf("Release(0.2.0) = %v, want v0.2.0", r)
+	}
+	if r := c.Release("v0.1.0"); r == nil || r.Version != "0.1.0" {
+		t.Errorf("Release(v0.1.0) = %v, want 0.1.0", r)
+	}
+	if r := c.Release("v0.3.0"); r != nil {
+		t.Errorf("Release(v0.3.0) = %v, want nil", r)
+	}
+}
+
+func TestLoadShared(t *testing.T) {
+	dir := t.TempDir()
+	if l := LoadShared(dir); !l.Missing() || l.Source != "" {
+		t.Errorf("empty dir: Missing() = %v, Source = %q", l.Missing(), l.Source)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, DefaultFile), []byte(`{"releases": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if l := LoadShared(dir); l.Err == nil || l.Missing() || l.Source != DefaultFile {
+		t.Errorf("invalid JSON: Err = %v, Source = %q", l.Err, l.Source)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, DefaultFile), []byte(testChangelog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	l := LoadShared(dir)
+	if l.Err != nil || l.Changelog == nil || len(l.Changelog.Releases) != 2 {
+		t.Fatalf("LoadShared() = %+v", l)
+	}
+	if got := len(l.Changelog.Releases[0].Entries()); got != 4 {
+		t.Errorf("len(Entries()) = %d, want 4", got)
+	}
+}
+
+func TestRef(t *testing.T) {
+	var e Entry
+	if err := Unmarshal([]byte(`{"description": "x", "pr": 12}`), &e); err != nil || e.PR.Number() != 12 {
+		t.Errorf("numeric pr: %q, %v", e.PR, err)
+	}
+	for ref, want := range map[Ref]int{"#34": 34, "https://github.com/o/r/pull/56": 56, "": 0, "abc": 0} {
+		if got := ref.Number(); got != want {
+			t.Errorf("Ref(%q).Number() = %d, want %d", ref, got, want)
+		}
+	}
+}
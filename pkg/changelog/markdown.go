@@ -0,0 +1,198 @@
+package changelog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// MarkdownFile is the conventional Keep a Changelog file name.
+const MarkdownFile = "CHANGELOG.md"
+
+var (
+	// ## [1.2.0] - 2026-01-02, ## 1.2.0 (2026-01-02), ## [Unreleased]
+	mdVersionHeading = regexp.MustCompile(`^##\s+\[?([vV]?\d[^\]\s]*|(?i:unreleased))\]?(?:\s*[-–(]\s*(\d{4}-\d{2}-\d{2})\)?)?(.*)$`)
+	mdCategory       = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	mdItem           = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	mdCommitLink     = regexp.MustCompile("\\s*\\(\\[`?([0-9a-f]{7,40})`?\\]\\([^)]*\\)\\)\\s*$")
+	mdCompareLink    = regexp.MustCompile(`^\[(?i:unreleased)\]:\s*(\S*/compare/)(\S+)\.\.\.HEAD\s*$`)
+)
+
+// mdCategories maps Keep a Changelog section names to Release fields.
+// Sections not listed here are ignored.
+var mdCategories = map[string]func(*Release) *[]Entry{
+	"highlights":       func(r *Release) *[]Entry { return &r.Highlights },
+	"breaking":         func(r *Release) *[]Entry { return &r.Breaking },
+	"breaking changes": func(r *Release) *[]Entry { return &r.Breaking },
+	"added":            func(r *Release) *[]Entry { return &r.Added },
+	"changed":          func(r *Release) *[]Entry { return &r.Changed },
+	"deprecated":       func(r *Release) *[]Entry { return &r.Deprecated },
+	"removed":          func(r *Release) *[]Entry { return &r.Removed },
+	"fixed":            func(r *Release) *[]Entry { return &r.Fixed },
+	"security":         func(r *Release) *[]Entry { return &r.Security },
+}
+
+// ErrNotKeepAChangelog is returned by ParseMarkdown when the document has no
+// Keep a Changelog version headings.
+var ErrNotKeepAChangelog = errors.New("not in Keep a Changelog format")
+
+// ParseMarkdown parses a Keep a Changelog (https://keepachangelog.com)
+// Markdown document. The [Unreleased] section is returned as Unreleased;
+// entries mentioning "BREAKING" are marked Breaking.
+func ParseMarkdown(data []byte) (*Changelog, error) {
+	c := &Changelog{}
+	var release *Release
+	var entries *[]Entry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if m := mdVersionHeading.FindStringSubmatch(line); m != nil {
+			entries = nil
+			if strings.EqualFold(m[1], "unreleased") {
+				c.Unreleased = &Release{}
+				release = c.Unreleased
+				continue
+			}
+			c.Releases = append(c.Releases, Release{
+				Version: m[1],
+				Date:    m[2],
+				Yanked:  strings.Contains(strings.ToUpper(m[3]), "YANKED"),
+			})
+			release = &c.Releases[len(c.Releases)-1]
+			continue
+		}
+		if release == nil {
+			continue
+		}
+
+		if m := mdCategory.FindStringSubmatch(line); m != nil {
+			entries = nil
+			if field, ok := mdCategories[strings.ToLower(m[1])]; ok {
+				entries = field(release)
+			}
+			continue
+		}
+		if entries == nil || trimmed == "" {
+			continue
+		}
+
+		if m := mdItem.FindStringSubmatch(line); m != nil {
+			*entries = append(*entries, newMarkdownEntry(m[1]))
+			continue
+		}
+		// Indented continuation of the previous item
+		if n := len(*entries); n > 0 && line != trimmed {
+			e := &(*entries)[n-1]
+			*e = newMarkdownEntry(e.Description + " " + trimmed)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if c.Unreleased == nil && len(c.Releases) == 0 {
+		return nil, ErrNotKeepAChangelog
+	}
+	return c, nil
+}
+
+func newMarkdownEntry(text string) Entry {
+	e := Entry{Description: strings.TrimSpace(text)}
+	if m := mdCommitLink.FindStringSubmatchIndex(e.Description); m != nil {
+		e.Commit = e.Description[m[2]:m[3]]
+		e.Description = e.Description[:m[0]]
+	}
+	e.Breaking = strings.Contains(e.Description, "BREAKING")
+	return e
+}
+
+// LoadDir loads the changelog in dir: CHANGELOG.json if present, otherwise a
+// Keep a Changelog CHANGELOG.md. It returns the name of the file read.
+func LoadDir(dir string) (*Changelog, string, error) {
+	c, err := Load(filepath.Join(dir, DefaultFile))
+	if err == nil || !os.IsNotExist(err) {
+		return c, DefaultFile, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, MarkdownFile))
+	if err != nil {
+		return nil, "", err
+	}
+	c, err = ParseMarkdown(data)
+	if err != nil {
+		return nil, MarkdownFile, fmt.Errorf("parsing %s: %w", MarkdownFile, err)
+	}
+	return c, MarkdownFile, nil
+}
+
+// ReadJSON returns CHANGELOG.json from dir, or a Keep a Changelog
+// CHANGELOG.md converted to the same JSON structure, along with the name of
+// the file read. CHANGELOG.json is returned verbatim so decoding errors can
+// be reported against it.
+func ReadJSON(dir string) ([]byte, string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, DefaultFile))
+	if err == nil || !os.IsNotExist(err) {
+		return data, DefaultFile, err
+	}
+
+	c, source, err := LoadDir(dir)
+	if err != nil {
+		return nil, source, err
+	}
+	data, err = json.Marshal(c)
+	return data, source, err
+}
+
+// PromoteUnreleased turns the [Unreleased] section of a Keep a Changelog
+// document into a release heading for version, leaving an empty
+// [Unreleased] section above it. A "[Unreleased]: .../compare/X...HEAD"
+// link is updated to compare from the new version, with a link added for it.
+func PromoteUnreleased(data []byte, version, date string) ([]byte, error) {
+	c, err := ParseMarkdown(data)
+	if err != nil {
+		return nil, err
+	}
+	if c.Unreleased == nil {
+		return nil, errors.New("no [Unreleased] section")
+	}
+	if c.Unreleased.Count() == 0 {
+		return nil, errors.New("no changes under [Unreleased]")
+	}
+
+	// Follow the existing headings' "v" prefix convention
+	heading := strings.TrimPrefix(version, "v")
+	if len(c.Releases) > 0 && strings.HasPrefix(c.Releases[0].Version, "v") {
+		heading = "v" + heading
+	}
+	for _, r := range c.Releases {
+		if strings.TrimPrefix(r.Version, "v") == strings.TrimPrefix(version, "v") {
+			return nil, fmt.Errorf("version %s already exists", r.Version)
+		}
+	}
+
+	lines := strings.Split(string(data), "\n")
+	out := make([]string, 0, len(lines)+4)
+	for _, line := range lines {
+		if m := mdVersionHeading.FindStringSubmatch(line); m != nil && strings.EqualFold(m[1], "unreleased") {
+			out = append(out, line, "", fmt.Sprintf("## [%s] - %s", heading, date))
+			continue
+		}
+		if m := mdCompareLink.FindStringSubmatch(line); m != nil {
+			out = append(out,
+				fmt.Sprintf("[Unreleased]: %s%s...HEAD", m[1], heading),
+				fmt.Sprintf("[%s]: %s%s...%s", heading, m[1], m[2], heading))
+			continue
+		}
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
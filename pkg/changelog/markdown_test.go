@@ -0,0 +1,164 @@
+package changelog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMarkdown = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+
+- Status command
+- Config option that spans
+  two lines
+
+## [1.1.0] - 2026-02-01
+
+### Changed
+
+- **BREAKING:** Renamed the --out flag ([` + "`abc1234`" + `](https://example.com/commit/abc1234))
+- Faster startup
+
+### Fixed
+
+* Crash on empty input
+
+### Infrastructure
+
+- Not a Keep a Changelog category
+
+## [1.0.0] - 2026-01-01 [YANKED]
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/acme/tool/compare/1.1.0...HEAD
+[1.1.0]: https://github.com/acme/tool/compare/1.0.0...1.1.0
+`
+
+func TestParseMarkdown(t *testing.T) {
+	c, err := ParseMarkdown([]byte(testMarkdown))
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error: %v", err)
+	}
+
+	if c.Unreleased == nil {
+		t.Fatal("Unreleased = nil")
+	}
+	if len(c.Unreleased.Added) != 2 {
+		t.Fatalf("len(Unreleased.Added) = %d, want 2", len(c.Unreleased.Added))
+	}
+	if got := c.Unreleased.Added[1].Description; got != "Config option that spans two lines" {
+		t.Errorf("continuation line: got %q", got)
+	}
+
+	if len(c.Releases) != 2 {
+		t.Fatalf("len(Releases) = %d, want 2", len(c.Releases))
+	}
+	r := c.Releases[0]
+	if r.Version != "1.1.0" || r.Date != "2026-02-01" || r.Yanked {
+		t.Errorf("Releases[0] = %+v", r)
+	}
+	if len(r.Changed) != 2 || len(r.Fixed) != 1 {
+		t.Fatalf("Releases[0] changed=%d fixed=%d, want 2 and 1", len(r.Changed), len(r.Fixed))
+	}
+	breaking := r.Changed[0]
+	if !breaking.Breaking || breaking.Commit != "abc1234" || strings.Contains(breaking.Description, "example.com") {
+		t.Errorf("Changed[0] = %+v", breaking)
+	}
+	if r.Changed[1].Breaking {
+		t.Error("Changed[1] should not be breaking")
+	}
+	if r.Count() != 3 {
+		t.Errorf("Count() = %d, want 3 (unknown categories ignored)", r.Count())
+	}
+	if !c.Releases[1].Yanked {
+		t.Error("Releases[1] should be yanked")
+	}
+}
+
+func TestParseMarkdown_NotKeepAChangelog(t *testing.T) {
+	_, err := ParseMarkdown([]byte("# Changelog\n\n## Recent work\n\n- Stuff\n"))
+	if !errors.Is(err, ErrNotKeepAChangelog) {
+		t.Errorf("ParseMarkdown() error = %v, want ErrNotKeepAChangelog", err)
+	}
+}
+
+func TestReadJSON_MarkdownFallback(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, MarkdownFile), []byte(testMarkdown), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, source, err := ReadJSON(dir)
+	if err != nil {
+		t.Fatalf("ReadJSON() error: %v", err)
+	}
+	if source != MarkdownFile {
+		t.Errorf("source = %q, want %q", source, MarkdownFile)
+	}
+	var decoded struct {
+		Releases []struct {
+			Version string `json:"version"`
+			Changed []struct {
+				Breaking bool `json:"breaking"`
+			} `json:"changed"`
+		} `json:"releases"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Releases) != 2 || !decoded.Releases[0].Changed[0].Breaking {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	// CHANGELOG.json takes precedence
+	if err := os.WriteFile(filepath.Join(dir, DefaultFile), []byte(testChangelog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, source, _ := ReadJSON(dir); source != DefaultFile {
+		t.Errorf("source = %q, want %q", source, DefaultFile)
+	}
+}
+
+func TestPromoteUnreleased(t *testing.T) {
+	out, err := PromoteUnreleased([]byte(testMarkdown), "v1.2.0", "2026-03-01")
+	if err != nil {
+		t.Fatalf("PromoteUnreleased() error: %v", err)
+	}
+	got := string(out)
+
+	for _, want := range []string{
+		"## [Unreleased]\n\n## [1.2.0] - 2026-03-01\n\n### Added\n\n- Status command",
+		"[Unreleased]: https://github.com/acme/tool/compare/1.2.0...HEAD\n[1.2.0]: https://github.com/acme/tool/compare/1.1.0...1.2.0\n[1.1.0]:",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+
+	c, err := ParseMarkdown(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Unreleased.Count() != 0 || c.Releases[0].Version != "1.2.0" || len(c.Releases[0].Added) != 2 {
+		t.Errorf("after promotion: unreleased=%d releases[0]=%+v", c.Unreleased.Count(), c.Releases[0])
+	}
+
+	if _, err := PromoteUnreleased(out, "1.3.0", "2026-04-01"); err == nil {
+		t.Error("expected error with no unreleased changes")
+	}
+	if _, err := PromoteUnreleased([]byte(testMarkdown), "1.1.0", "2026-04-01"); err == nil {
+		t.Error("expected error for existing version")
+	}
+}
@@ -0,0 +1,136 @@
+package changelog
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/plexusone/agent-team-release/pkg/jsonschema"
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// Schema is the JSON Schema for CHANGELOG.json.
+//
+//go:embed changelog.schema.json
+var Schema []byte
+
+// Violation is a single schema or consistency error in a changelog.
+type Violation struct {
+	Path    string // Location in the document, e.g. "releases[2].highlights[0].description"
+	Message string
+}
+
+// String returns the violation as "path: message".
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Validate checks a CHANGELOG.json document against Schema and for
+// consistency the schema cannot express: release versions must be unique
+// and ordered newest first. It returns an error only if data is not valid
+// JSON.
+func Validate(data []byte) ([]Violation, error) {
+	doc, err := jsonschema.Decode(data)
+	if err != nil {
+		return nil, syntaxError(data, err)
+	}
+
+	schema, err := jsonschema.Compile(Schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid changelog schema: %w", err)
+	}
+	var violations []Violation
+	for _, v := range schema.Validate(doc) {
+		violations = append(violations, Violation(v))
+	}
+
+	var c Changelog
+	if len(violations) == 0 && json.Unmarshal(data, &c) == nil {
+		violations = append(violations, checkReleaseOrder(c.Releases)...)
+	}
+	return violations, nil
+}
+
+// Unmarshal decodes a CHANGELOG.json document into out, reporting the line
+// and column of syntax errors and the field of type errors.
+func Unmarshal(data []byte, out any) error {
+	if err := json.Unmarshal(data, out); err != nil {
+		return syntaxError(data, err)
+	}
+	return nil
+}
+
+// syntaxError adds the position to JSON decoding errors.
+func syntaxError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := position(data, syntaxErr.Offset)
+		return fmt.Errorf("line %d, column %d: %w", line, col, err)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		line, col := position(data, typeErr.Offset)
+		return fmt.Errorf("line %d, column %d: %s: expected %s, got %s",
+			line, col, fieldPath(typeErr.Field), typeErr.Type, typeErr.Value)
+	}
+	return err
+}
+
+// fieldPath converts a dotted encoding/json field such as "releases.0.date"
+// to the path style used by Violation, "releases[0].date".
+func fieldPath(field string) string {
+	var path string
+	for _, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			path += "[" + part + "]"
+		} else {
+			path = joinPath(path, part)
+		}
+	}
+	return path
+}
+
+func position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:])
+	return line, col
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// checkReleaseOrder reports duplicate versions and releases that are not
+// ordered newest first by semantic version.
+func checkReleaseOrder(releases []Release) []Violation {
+	var violations []Violation
+	seen := make(map[string]int)
+	for i, r := range releases {
+		path := fmt.Sprintf("releases[%d].version", i)
+		key := strings.TrimPrefix(r.Version, "v")
+		if j, ok := seen[key]; ok {
+			violations = append(violations, Violation{Path: path, Message: fmt.Sprintf("duplicate version %s (also releases[%d])", r.Version, j)})
+			continue
+		}
+		seen[key] = i
+		if i > 0 && semver.Compare(releases[i-1].Version, r.Version) < 0 {
+			violations = append(violations, Violation{
+				Path:    path,
+				Message: fmt.Sprintf("%s is newer than the preceding %s; releases must be ordered newest first", r.Version, releases[i-1].Version),
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,116 @@
+package changelog
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidate_Valid(t *testing.T) {
+	violations, err := Validate([]byte(testChangelog))
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Validate() = %v, want no violations", violations)
+	}
+}
+
+func TestValidate_RepoChangelog(t *testing.T) {
+	data, err := os.ReadFile("../../" + DefaultFile)
+	if err != nil {
+		t.Skip("repository CHANGELOG.json not found")
+	}
+	violations, err := Validate(data)
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	for _, v := range violations {
+		t.Errorf("violation: %s", v)
+	}
+}
+
+func TestValidate_Violations(t *testing.T) {
+	data := `{
+  "project": "demo",
+  "releases": [
+    {
+      "version": "v1.0",
+      "date": "2026/01/01",
+      "highlights": [{"description": ""}, {"commit": "abc1234"}],
+      "features": []
+    },
+    {"date": "2026-01-01", "fixed": "nope"}
+  ]
+}`
+	violations, err := Validate([]byte(data))
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+
+	want := []string{
+		`releases[0].date: "2026/01/01" does not match`,
+		`releases[0].features: unknown field "features"`,
+		`releases[0].highlights[0].description: must not be empty`,
+		`releases[0].highlights[1]: missing required field "description"`,
+		`releases[0].version: "v1.0" does not match`,
+		`releases[1]: missing required field "version"`,
+		`releases[1].fixed: expected array, got string`,
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("got %d violations, want %d:\n%v", len(violations), len(want), violations)
+	}
+	for i, w := range want {
+		if got := violations[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("violation %d = %q, want prefix %q", i, got, w)
+		}
+	}
+}
+
+func TestValidate_ReleaseOrder(t *testing.T) {
+	data := `{"releases": [
+  {"version": "v0.2.0"},
+  {"version": "v0.10.0"},
+  {"version": "v0.2.0"},
+  {"version": "v0.1.0"}
+]}`
+	violations, err := Validate([]byte(data))
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	want := []string{
+		"releases[1].version: v0.10.0 is newer than the preceding v0.2.0",
+		"releases[2].version: duplicate version v0.2.0 (also releases[0])",
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("got %v, want %d violations", violations, len(want))
+	}
+	for i, w := range want {
+		if got := violations[i].String(); !strings.HasPrefix(got, w) {
+			t.Errorf("violation %d = %q, want prefix %q", i, got, w)
+		}
+	}
+}
+
+func TestValidate_SyntaxError(t *testing.T) {
+	_, err := Validate([]byte("{\n  \"releases\": [\n    {\"version\": \"v1.0.0\",}\n  ]\n}"))
+	if err == nil {
+		t.Fatal("expected syntax error")
+	}
+	if !strings.HasPrefix(err.Error(), "line 3, column 26:") {
+		t.Errorf("error = %q, want line 3, column 26", err)
+	}
+}
+
+func TestUnmarshal_TypeError(t *testing.T) {
+	var c Changelog
+	err := Unmarshal([]byte(`{"releases": [{"version": 1}]}`), &c)
+	if err == nil {
+		t.Fatal("expected type error")
+	}
+	// Older Go versions omit the slice index from the field
+	if !strings.Contains(err.Error(), "releases[0].version: expected string, got number") &&
+		!strings.Contains(err.Error(), "releases.version: expected string, got number") {
+		t.Errorf("error = %q", err)
+	}
+}
@@ -0,0 +1,180 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// usesLine matches the action or reusable workflow a workflow step or job
+// uses, e.g. "- uses: owner/action@v4".
+var usesLine = regexp.MustCompile(`^\s*(?:-\s*)?uses:\s*['"]?([^'"\s#]+)`)
+
+// commitSHA matches a full commit SHA.
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// firstPartyOwners are the GitHub organizations whose actions may be
+// referenced by tag.
+var firstPartyOwners = map[string]bool{"actions": true, "github": true}
+
+// resolveActionRef returns the commit SHA that ref (a tag or branch) of a
+// GitHub repository, e.g. "owner/action", points to. Tests replace it.
+var resolveActionRef = func(repo, ref string) (string, error) {
+	cmd := proc.Command("git", "ls-remote", "https://github.com/"+repo,
+		"refs/tags/"+ref+"^{}", "refs/tags/"+ref, "refs/heads/"+ref)
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	refs := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		if sha, name, ok := strings.Cut(line, "\t"); ok {
+			refs[name] = sha
+		}
+	}
+	// A peeled annotated tag is the commit, not the tag object
+	for _, name := range []string{"refs/tags/" + ref + "^{}", "refs/tags/" + ref, "refs/heads/" + ref} {
+		if sha := refs[name]; sha != "" {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("%s has no tag or branch %s", repo, ref)
+}
+
+// ActionRef is a third-party action referenced by a GitHub Actions
+// workflow.
+type ActionRef struct {
+	File   string // Workflow file, relative to the checked directory
+	Line   int    // 1-based line of the uses: key
+	Action string // Action or reusable workflow, e.g. "owner/repo/path"
+	Ref    string // Tag, branch, or commit SHA after "@"
+}
+
+// Repo returns the GitHub repository of the action, e.g. "owner/repo".
+func (a ActionRef) Repo() string {
+	parts := strings.SplitN(a.Action, "/", 3)
+	if len(parts) < 2 {
+		return a.Action
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// Pinned reports whether the action is referenced by a commit SHA.
+func (a ActionRef) Pinned() bool {
+	return commitSHA.MatchString(a.Ref)
+}
+
+// WorkflowActions returns the third-party actions used by the workflows in
+// .github/workflows of dir. Local actions, Docker images, and actions
+// owned by GitHub are left out.
+func WorkflowActions(dir string) ([]ActionRef, error) {
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, ".github", "workflows", pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	var refs []ActionRef
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return nil, err
+		}
+		for i, line := range strings.Split(string(data), "\n") {
+			m := usesLine.FindStringSubmatch(line)
+			if m == nil || strings.HasPrefix(m[1], "./") || strings.HasPrefix(m[1], "docker://") {
+				continue
+			}
+			action, ref, ok := strings.Cut(m[1], "@")
+			if !ok {
+				continue
+			}
+			owner, _, _ := strings.Cut(action, "/")
+			if firstPartyOwners[owner] {
+				continue
+			}
+			refs = append(refs, ActionRef{File: filepath.ToSlash(rel), Line: i + 1, Action: action, Ref: ref})
+		}
+	}
+	return refs, nil
+}
+
+// CheckPinnedActions warns when GitHub Actions workflows in dir reference
+// third-party actions by a mutable tag or branch instead of a commit SHA,
+// and proposes the pinned reference for each.
+func CheckPinnedActions(dir string) Result {
+	name := "Security: pinned actions"
+	if !FileExists(filepath.Join(dir, ".github", "workflows")) {
+		return Result{Name: name, Skipped: true, Reason: "no GitHub Actions workflows"}
+	}
+
+	refs, err := WorkflowActions(dir)
+	if err != nil {
+		return Result{Name: name, Error: err}
+	}
+
+	var findings, fixes []string
+	resolved := make(map[string]string)
+	denied := !CommandAllowed("git") || offline
+	for _, a := range refs {
+		if a.Pinned() {
+			continue
+		}
+		findings = append(findings, fmt.Sprintf("%s:%d: %s@%s is not pinned to a commit SHA", a.File, a.Line, a.Action, a.Ref))
+		if denied {
+			continue
+		}
+		key := a.Repo() + "@" + a.Ref
+		sha, ok := resolved[key]
+		if !ok {
+			sha, _ = resolveActionRef(a.Repo(), a.Ref)
+			resolved[key] = sha
+		}
+		if sha != "" {
+			fixes = append(fixes, fmt.Sprintf("  %s@%s -> uses: %s@%s # %s", a.Action, a.Ref, a.Action, sha, a.Ref))
+		}
+	}
+
+	if len(findings) == 0 {
+		return Result{Name: name, Passed: true, Output: fmt.Sprintf("%d third-party actions pinned", len(refs))}
+	}
+	output := fmt.Sprintf("%d third-party action references use mutable tags:\n%s", len(findings), strings.Join(findings, "\n"))
+	if len(fixes) > 0 {
+		output += "\nProposed pins:\n" + strings.Join(uniqueStrings(fixes), "\n")
+	} else if offline {
+		output += "\n(offline: tags not resolved to commit SHAs)"
+	}
+	return Result{
+		Name:        name,
+		Warning:     true,
+		Output:      output,
+		Remediation: "pin each action to a commit SHA, keeping the tag in a comment, e.g. the proposed pins",
+	}
+}
+
+// uniqueStrings returns ss without repeats, keeping the first of each.
+func uniqueStrings(ss []string) []string {
+	seen := make(map[string]bool, len(ss))
+	out := ss[:0:0]
+	for _, s := range ss {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testWorkflow = `name: ci
+on: push
+jobs:
+  test:
+    uses: acme/workflows/.github/workflows/go.yml@main
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - uses: ./.github/actions/setup
+      - uses: docker://alpine:3
+      - uses: "golangci/golangci-lint-action@v6"
+      - name: pinned
+        uses: goreleaser/goreleaser-action@9c156ee8a17a598857849441385a2041ef570552 # v6
+      # - uses: commented/out@v1
+`
+
+func TestWorkflowActions(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, ".github", "workflows", "ci.yml"), testWorkflow)
+
+	refs, err := WorkflowActions(dir)
+	if err != nil {
+		t.Fatalf("WorkflowActions failed: %v", err)
+	}
+	want := []ActionRef{
+		{File: ".github/workflows/ci.yml", Line: 5, Action: "acme/workflows/.github/workflows/go.yml", Ref: "main"},
+		{File: ".github/workflows/ci.yml", Line: 12, Action: "golangci/golangci-lint-action", Ref: "v6"},
+		{File: ".github/workflows/ci.yml", Line: 14, Action: "goreleaser/goreleaser-action", Ref: "9c156ee8a17a598857849441385a2041ef570552"},
+	}
+	if fmt.Sprint(refs) != fmt.Sprint(want) {
+		t.Errorf("WorkflowActions() = %+v\nwant %+v", refs, want)
+	}
+	if refs[0].Repo() != "acme/workflows" {
+		t.Errorf("Repo() = %q, want acme/workflows", refs[0].Repo())
+	}
+	if refs[1].Pinned() || !refs[2].Pinned() {
+		t.Error("Pinned() should be true only for the commit SHA")
+	}
+}
+
+func TestCheckPinnedActions(t *testing.T) {
+	if r := CheckPinnedActions(t.TempDir()); !r.Skipped {
+		t.Errorf("expected skip without workflows, got %+v", r)
+	}
+
+	defer func(orig func(string, string) (string, error)) { resolveActionRef = orig }(resolveActionRef)
+	resolveActionRef = func(repo, ref string) (string, error) {
+		if repo == "acme/workflows" {
+			return "", fmt.Errorf("not found")
+		}
+		return strings.Repeat("a", 40), nil
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, ".github", "workflows", "ci.yml"), testWorkflow)
+	r := CheckPinnedActions(dir)
+	if r.Passed || !r.Warning {
+		t.Fatalf("expected a warning for unpinned actions, got %+v", r)
+	}
+	for _, want := range []string{
+		".github/workflows/ci.yml:5: acme/workflows/.github/workflows/go.yml@main is not pinned",
+		".github/workflows/ci.yml:12: golangci/golangci-lint-action@v6 is not pinned",
+		"uses: golangci/golangci-lint-action@" + strings.Repeat("a", 40) + " # v6",
+	} {
+		if !strings.Contains(r.Output, want) {
+			t.Errorf("output missing %q:\n%s", want, r.Output)
+		}
+	}
+	if strings.Contains(r.Output, "goreleaser-action@v6") {
+		t.Errorf("pinned action reported:\n%s", r.Output)
+	}
+
+	writeTestFile(t, filepath.Join(dir, ".github", "workflows", "ci.yml"), "steps:\n  - uses: actions/checkout@v4\n")
+	if r := CheckPinnedActions(dir); !r.Passed {
+		t.Errorf("expected first-party actions to pass, got %+v", r)
+	}
+}
@@ -0,0 +1,127 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/apireport"
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// maxAPILinesShown limits the API changes listed in output.
+const maxAPILinesShown = 10
+
+// releasedAPI returns the API baseline committed at the latest tag, with
+// the tag, or a nil report if the tag has none. Tests replace it.
+var releasedAPI = func(dir string) (apireport.Report, string, error) {
+	g := git.New(dir)
+	tag, err := g.LatestTag()
+	if err != nil {
+		return nil, "", err
+	}
+	files, err := g.ListFiles(tag, apireport.Dir)
+	if err != nil {
+		return nil, "", err
+	}
+	var report apireport.Report
+	for _, f := range files {
+		if path.Ext(f) != ".txt" {
+			continue
+		}
+		// "./" makes the path relative to dir rather than the top level
+		data, err := g.ShowFile(tag, "./"+f)
+		if err != nil {
+			return nil, "", err
+		}
+		if report == nil {
+			report = make(apireport.Report)
+		}
+		report[path.Base(f)] = apireport.Parse(data)
+	}
+	return report, tag, nil
+}
+
+// apiLines formats API report lines for output, limited to
+// maxAPILinesShown.
+func apiLines(prefix string, lines []string) []string {
+	var out []string
+	for i, l := range lines {
+		if i == maxAPILinesShown {
+			out = append(out, fmt.Sprintf("  ... (%d more)", len(lines)-i))
+			break
+		}
+		out = append(out, "  "+prefix+" "+l)
+	}
+	return out
+}
+
+// checkAPIBaseline validates the Go API baseline committed in api/: it must
+// match the API regenerated from the source, and the changelog must flag a
+// breaking change if the API removed or changed anything since the latest
+// tag's baseline. Without api/, the check is skipped.
+func (c *PMChecker) checkAPIBaseline(dir string, cl *changelog.Loaded, version string) Result {
+	name := "PM: api-baseline"
+
+	if !FileExists(filepath.Join(dir, "go.mod")) {
+		return Result{Name: name, Skipped: true, Reason: "No go.mod found"}
+	}
+	if info, err := os.Stat(filepath.Join(dir, apireport.Dir)); err != nil || !info.IsDir() {
+		return Result{Name: name, Skipped: true, Reason: "No API baseline in api/ (create one with atrelease api)"}
+	}
+
+	committed, err := apireport.Load(dir)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	current, err := apireport.Generate(dir)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+
+	if diff := apireport.Compare(committed, current); !diff.Empty() {
+		lines := []string{fmt.Sprintf("api/ is out of date: %d line(s) added, %d removed", len(diff.Added), len(diff.Removed))}
+		lines = append(lines, apiLines("+", diff.Added)...)
+		lines = append(lines, apiLines("-", diff.Removed)...)
+		return Result{Name: name, Passed: false, Output: strings.Join(lines, "\n"), Remediation: "regenerate the baseline: atrelease api"}
+	}
+
+	summary := fmt.Sprintf("api/ matches the source (%d exported identifiers)", len(current.Lines()))
+	released, tag, err := releasedAPI(dir)
+	if err != nil || released == nil {
+		return Result{Name: name, Passed: true, Output: summary}
+	}
+
+	diff := apireport.Compare(released, current)
+	breaking := 0
+	if cl.Err == nil {
+		if release := cl.Changelog.Release(version); release != nil {
+			breaking = breakingEntries(release)
+		}
+	}
+	if len(diff.Removed) > 0 && breaking == 0 {
+		lines := []string{fmt.Sprintf("%s; %d API line(s) removed or changed since %s, but the changelog has no breaking changes for %s:",
+			summary, len(diff.Removed), tag, version)}
+		lines = append(lines, apiLines("-", diff.Removed)...)
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      strings.Join(lines, "\n"),
+			Remediation: fmt.Sprintf("add a breaking change for %s to the changelog, or restore the removed API", version),
+		}
+	}
+
+	switch {
+	case len(diff.Removed) > 0:
+		summary += fmt.Sprintf(", %d API line(s) removed or changed since %s, documented as breaking", len(diff.Removed), tag)
+	case breaking > 0:
+		summary += fmt.Sprintf(", compatible with %s; the breaking changes are outside the Go API", tag)
+	default:
+		summary += fmt.Sprintf(", compatible with %s (%d line(s) added)", tag, len(diff.Added))
+	}
+	return Result{Name: name, Passed: true, Output: summary}
+}
@@ -0,0 +1,69 @@
+package checks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/apireport"
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+)
+
+func TestPMChecker_APIBaseline(t *testing.T) {
+	orig := releasedAPI
+	t.Cleanup(func() { releasedAPI = orig })
+	releasedAPI = func(string) (apireport.Report, string, error) { return nil, "", errors.New("no tags") }
+
+	c := &PMChecker{}
+	dir := t.TempDir()
+	cl := loadedChangelog(changelog.Release{Version: "v1.1.0"})
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/mod\n")
+	write("mod.go", "package mod\n\nfunc Parse(s string) error { return nil }\n")
+
+	if r := c.checkAPIBaseline(dir, cl, "v1.1.0"); !r.Skipped {
+		t.Errorf("no baseline: %+v", r)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, apireport.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	r := c.checkAPIBaseline(dir, cl, "v1.1.0")
+	if r.Passed || !strings.Contains(r.Output, "+ pkg example.com/mod, func Parse(string) error") {
+		t.Errorf("stale baseline: %+v", r)
+	}
+
+	current, err := apireport.Generate(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := apireport.Write(dir, current); err != nil {
+		t.Fatal(err)
+	}
+	if r := c.checkAPIBaseline(dir, cl, "v1.1.0"); !r.Passed || r.Output != "api/ matches the source (1 exported identifiers)" {
+		t.Errorf("no tag: %+v", r)
+	}
+
+	releasedAPI = func(string) (apireport.Report, string, error) {
+		return apireport.Report{"mod.txt": {"pkg example.com/mod, func Parse(string) (*Doc, error)"}}, "v1.0.0", nil
+	}
+	r = c.checkAPIBaseline(dir, cl, "v1.1.0")
+	if r.Passed || !r.Warning || !strings.Contains(r.Reason, "1 API line(s) removed or changed since v1.0.0") {
+		t.Errorf("undocumented break: %+v", r)
+	}
+
+	cl = loadedChangelog(changelog.Release{
+		Version: "v1.1.0",
+		Changed: []changelog.Entry{{Description: "Parse returns only an error", Breaking: true}},
+	})
+	if r := c.checkAPIBaseline(dir, cl, "v1.1.0"); !r.Passed || !strings.Contains(r.Output, "documented as breaking") {
+		t.Errorf("documented break: %+v", r)
+	}
+}
@@ -4,7 +4,15 @@
 
 package checks
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
 
 // ValidationArea represents a department/area of responsibility in the release process.
 type ValidationArea string
@@ -13,7 +21,7 @@ const (
 	// AreaPM represents Product Management validation.
 	// Ensures the release scope, versioning, and product decisions are appropriate.
 	// Checks: version-recommendation, release-scope, changelog-quality,
-	// breaking-changes, roadmap-alignment, deprecation-notices.
+	// breaking-changes, api-baseline, roadmap-alignment, deprecation-notices.
 	AreaPM ValidationArea = "PM"
 
 	// AreaQA represents Quality Assurance validation.
@@ -37,11 +45,19 @@ const (
 	AreaSecurity ValidationArea = "Security"
 )
 
+// Key returns the area's key in the validate config, e.g. "pm".
+func (a ValidationArea) Key() string {
+	return config.AreaKey(string(a))
+}
+
 // AreaResult represents the validation result for an area.
 type AreaResult struct {
-	Area    ValidationArea
-	Status  AreaStatus
-	Results []Result
+	Area      ValidationArea
+	Status    AreaStatus
+	Results   []Result
+	DependsOn []ValidationArea // Areas it ran after; team reports place areas beyond the defaults after them, or after PM
+	Started   time.Time        // When RunAreas started it; zero if it was skipped
+	Duration  time.Duration    // How long it ran
 }
 
 // AreaStatus represents the Go/No-Go status for an area.
@@ -86,6 +102,65 @@ func (r *ValidationReport) IsGo() bool {
 	return true
 }
 
+// PromoteWarnings turns every warning in the report into a failure and
+// recomputes the area statuses, so areas with warnings are NO-GO.
+func (r *ValidationReport) PromoteWarnings() {
+	for i := range r.Areas {
+		area := &r.Areas[i]
+		area.Results = PromoteWarnings(area.Results)
+		area.Status = ComputeAreaStatus(area.Results)
+	}
+}
+
+// NewAreaResult returns the result of an area from its check results,
+// reporting the checks cfg skips as skipped with their reasons.
+func NewAreaResult(area ValidationArea, results []Result, cfg config.AreaConfig) AreaResult {
+	for _, s := range cfg.Skip {
+		reason := fmt.Sprintf("skipped by config (validate.areas.%s.skip)", area.Key())
+		if s.Reason != "" {
+			reason += ": " + s.Reason
+		}
+		SkipByID(results, []string{s.ID}, reason)
+	}
+	return AreaResult{
+		Area:    area,
+		Status:  ComputeAreaStatus(results),
+		Results: results,
+	}
+}
+
+// SkippedArea returns the result of an area that didn't run, with a single
+// skipped result recording why.
+func SkippedArea(area ValidationArea, reason string) AreaResult {
+	return AreaResult{
+		Area:   area,
+		Status: StatusSkip,
+		Results: []Result{{
+			Name:    string(area) + ": area",
+			Skipped: true,
+			Reason:  reason,
+		}},
+	}
+}
+
+// AreaSkipReason returns why an area doesn't run: flag, e.g. "--skip-pm",
+// if it was given, or the area's config disables it. It returns "" if the
+// area runs.
+func AreaSkipReason(area ValidationArea, cfg config.Config, flag string, flagSet bool) string {
+	if flagSet {
+		return "skipped by " + flag
+	}
+	ac := cfg.Validate.Areas[area.Key()]
+	if ac.IsEnabled() {
+		return ""
+	}
+	reason := fmt.Sprintf("disabled by config (validate.areas.%s)", area.Key())
+	if ac.Reason != "" {
+		reason += ": " + ac.Reason
+	}
+	return reason
+}
+
 // ComputeAreaStatus computes the status for an area based on its results.
 func ComputeAreaStatus(results []Result) AreaStatus {
 	hasNoGo := false
@@ -93,18 +168,14 @@ func ComputeAreaStatus(results []Result) AreaStatus {
 	allSkipped := true
 
 	for _, r := range results {
-		if !r.Skipped {
-			allSkipped = false
-		}
-		if r.Skipped {
-			continue
-		}
-		if r.Warning && !r.Passed {
+		switch r.Severity() {
+		case SeverityFailed:
+			hasNoGo = true
+		case SeverityWarning:
 			hasWarn = true
-			continue
 		}
-		if !r.Passed {
-			hasNoGo = true
+		if !r.Skipped {
+			allSkipped = false
 		}
 	}
 
@@ -120,63 +191,94 @@ func ComputeAreaStatus(results []Result) AreaStatus {
 	return StatusGo
 }
 
-// PrintValidationReport prints a comprehensive Go/No-Go report organized by area.
+// reportMinWidth is the narrowest a validation report box gets.
+const reportMinWidth = 80
+
+// reportDetailIndent is the number of columns before a check's details in
+// the validation report.
+const reportDetailIndent = 10
+
+// PrintValidationReport prints a comprehensive Go/No-Go report organized by
+// area, in the labels and final message set by SetReportConfig. The box
+// fits the longest line if the terminal has room; names and details that
+// don't fit are wrapped.
 func PrintValidationReport(report *ValidationReport) {
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════════════════════╗")
+	writeValidationReport(os.Stdout, report)
+}
+
+func writeValidationReport(w io.Writer, report *ValidationReport) {
+	title := "RELEASE VALIDATION"
 	if report.Version != "" {
-		fmt.Printf("║             RELEASE VALIDATION: %-42s ║\n", report.Version)
-	} else {
-		fmt.Println("║                        RELEASE VALIDATION                                    ║")
+		title += ": " + report.Version
 	}
-	fmt.Println("╠══════════════════════════════════════════════════════════════════════════════╣")
-	fmt.Println("║  Assumes: Engineering ✅ SIGNED OFF | Product ✅ SIGNED OFF                  ║")
-	fmt.Println("╠══════════════════════════════════════════════════════════════════════════════╣")
 
-	for _, area := range report.Areas {
-		// Area header
-		// Box is 78 chars. Emoji displays as 2 but counts as 1, so reduce padding by 1
-		icon := area.Status.Icon()
-		fmt.Printf("║ %s %-8s %-62s ║\n", icon, area.Status, string(area.Area))
-		fmt.Println("║ ────────────────────────────────────────────────────────────────────────── ║")
+	// " 🟢 NO-GO    QA" and "   🟢 NO-GO  Go: tests"
+	areaLabels, checkLabels := labelWidth(8), labelWidth(6)
+	areaIndent, checkIndent := 4+areaLabels+1, 6+checkLabels+1
+	verdict := strings.Split(verdictMessage(report.IsGo(), report.Version, report.blockedAreas()), "\n")
 
-		// Individual checks
+	content := displayWidth(title) + 1
+	for _, l := range verdict {
+		content = max(content, displayWidth(l)+1)
+	}
+	for _, area := range report.Areas {
+		content = max(content, areaIndent+displayWidth(string(area.Area)))
 		for _, r := range area.Results {
-			checkIcon := IconGo
-			checkStatus := "GO"
-
-			if r.Skipped {
-				checkIcon = IconSkipped
-				checkStatus = "SKIP"
-			} else if r.Warning && !r.Passed {
-				checkIcon = IconWarning
-				checkStatus = "WARN"
-			} else if !r.Passed {
-				checkIcon = IconNoGo
-				checkStatus = "NO-GO"
+			content = max(content, checkIndent+displayWidth(r.Name))
+			for _, l := range strings.Split(reportDetails(r), "\n") {
+				content = max(content, reportDetailIndent+displayWidth(l))
 			}
+		}
+	}
+	b := box{w: w, width: boxWidth(reportMinWidth, content)}
+
+	fmt.Fprintln(w)
+	b.top()
+	b.center(title)
+	b.divider()
+	b.line("  Assumes: Engineering ✅ SIGNED OFF | Product ✅ SIGNED OFF")
+	b.divider()
+
+	detailPrefix := strings.Repeat(" ", reportDetailIndent)
+	for _, area := range report.Areas {
+		b.wrapped(" "+area.Status.Icon()+" "+padRight(area.Status.Label(), areaLabels)+" ", areaIndent, string(area.Area))
+		b.line(" " + strings.Repeat("─", b.inner()-2))
 
-			// Truncate name if too long
-			name := r.Name
-			if len(name) > 52 {
-				name = name[:49] + "..."
+		for _, r := range area.Results {
+			status := r.Severity().Status()
+			b.wrapped("   "+status.Icon()+" "+padRight(status.Label(), checkLabels)+" ", checkIndent, r.Name)
+			if r.Skipped && r.Reason != "" {
+				b.wrapped(detailPrefix, reportDetailIndent, r.Reason)
+			}
+			if fix := r.ShownRemediation(); fix != "" {
+				b.wrapped(detailPrefix+"Fix: ", reportDetailIndent+5, fix)
 			}
-			// Emoji displays as 2 but counts as 1, so reduce padding by 1
-			fmt.Printf("║   %s %-6s %-58s ║\n", checkIcon, checkStatus, name)
 		}
-		fmt.Println("║                                                                              ║")
+		b.line("")
 	}
 
-	fmt.Println("╠══════════════════════════════════════════════════════════════════════════════╣")
-
-	// Final verdict
+	b.divider()
+	for _, l := range verdict {
+		b.center(l)
+	}
 	if report.IsGo() {
-		fmt.Println("║                         🚀 ALL SYSTEMS GO 🚀                               ║")
-		fmt.Println("║                      RELEASE VALIDATION: APPROVED                          ║")
+		b.center("RELEASE VALIDATION: APPROVED")
 	} else {
-		fmt.Println("║                        🛑 NO-GO FOR RELEASE 🛑                              ║")
-		fmt.Println("║                    RELEASE VALIDATION: NOT APPROVED                        ║")
+		b.center("RELEASE VALIDATION: NOT APPROVED")
+	}
+	b.bottom()
+	fmt.Fprintln(w)
+}
+
+// reportDetails returns the lines the validation report prints under a
+// check.
+func reportDetails(r Result) string {
+	var details []string
+	if r.Skipped && r.Reason != "" {
+		details = append(details, r.Reason)
+	}
+	if fix := r.ShownRemediation(); fix != "" {
+		details = append(details, "Fix: "+fix)
 	}
-	fmt.Println("╚══════════════════════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+	return strings.Join(details, "\n")
 }
@@ -0,0 +1,330 @@
+package checks
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// Backend runs the commands of heavy checks, such as the full test suite
+// or the race detector, either locally or on a remote runner, so pre-push
+// latency stays acceptable on large repositories.
+type Backend interface {
+	// Name describes the backend for progress output, e.g. "ssh runner".
+	Name() string
+
+	// Output runs args in dir, a slash-separated path relative to root,
+	// and returns its standard output. A command that runs but exits
+	// non-zero returns an *ExitError along with any output.
+	Output(root, dir string, args []string) ([]byte, error)
+}
+
+// ExitError reports a command that ran but exited with a non-zero code.
+type ExitError struct {
+	Code   int
+	Stderr []byte
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// LocalBackend runs commands on this machine.
+type LocalBackend struct{}
+
+// Name implements Backend.
+func (LocalBackend) Name() string { return "local" }
+
+// Output implements Backend.
+func (LocalBackend) Output(root, dir string, args []string) ([]byte, error) {
+	cmd := proc.Command(args[0], args[1:]...)
+	cmd.Dir = filepath.Join(root, filepath.FromSlash(dir))
+	output, err := runOutput(cmd, strings.Join(args, " "), false)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output, &ExitError{Code: exitErr.ExitCode(), Stderr: exitErr.Stderr}
+	}
+	return output, err
+}
+
+// SSHBackend syncs the working tree to a directory on a remote host with
+// rsync and runs commands there over ssh. The host needs rsync and the
+// tools the checks run.
+type SSHBackend struct {
+	Host string // ssh destination, e.g. "ci@runner"
+	Dir  string // Directory on the host the tree is synced to
+}
+
+// Name implements Backend.
+func (b SSHBackend) Name() string { return "ssh " + b.Host }
+
+// Output implements Backend.
+func (b SSHBackend) Output(root, dir string, args []string) ([]byte, error) {
+	for _, command := range []string{"ssh", "rsync"} {
+		if !CommandAllowed(command) {
+			return nil, fmt.Errorf("%s is not allowed by the tools policy", command)
+		}
+	}
+
+	// Sync the working tree, including uncommitted changes, but not .git
+	sync := proc.Command("rsync", "-az", "--delete", "--exclude", ".git/", "--exclude", "node_modules/",
+		strings.TrimSuffix(root, "/")+"/", b.Host+":"+strings.TrimSuffix(b.Dir, "/")+"/")
+	if out, err := sync.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("rsync to %s failed: %w\n%s", b.Host, err, strings.TrimSpace(string(out)))
+	}
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	script := fmt.Sprintf("cd %s && %s", shellQuote(path.Join(b.Dir, dir)), strings.Join(quoted, " "))
+	cmd := proc.Command("ssh", b.Host, script)
+	output, err := runOutput(cmd, strings.Join(args, " "), false)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// ssh exits 255 for its own errors
+		if exitErr.ExitCode() == 255 {
+			return nil, fmt.Errorf("ssh %s failed: %s", b.Host, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return output, &ExitError{Code: exitErr.ExitCode(), Stderr: exitErr.Stderr}
+	}
+	return output, err
+}
+
+// withEnv returns args run with env added to the environment, through
+// env(1) so it works on every backend.
+func withEnv(env, args []string) []string {
+	if len(env) == 0 {
+		return args
+	}
+	return slices.Concat([]string{"env"}, env, args)
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// HTTPBackend sends the working tree and command to an HTTP agent. Each
+// run is a multipart POST to URL with a "request" field holding JSON
+// {"dir": ..., "args": [...]} and an "archive" field holding the tree as a
+// gzipped tarball (without .git and node_modules). The agent replies with
+// JSON {"exit_code": n, "stdout": ..., "stderr": ...}.
+type HTTPBackend struct {
+	URL    string
+	Token  string // Sent as a bearer token when set
+	Client *http.Client
+}
+
+// HTTPRequest is the command an HTTPBackend asks the agent to run.
+type HTTPRequest struct {
+	Dir  string   `json:"dir"`
+	Args []string `json:"args"`
+}
+
+// HTTPResponse is the agent's reply to an HTTPBackend run.
+type HTTPResponse struct {
+	ExitCode int    `json:"exit_code"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+}
+
+// Name implements Backend.
+func (b HTTPBackend) Name() string { return b.URL }
+
+// Output implements Backend.
+func (b HTTPBackend) Output(root, dir string, args []string) ([]byte, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	request, err := json.Marshal(HTTPRequest{Dir: dir, Args: args})
+	if err != nil {
+		return nil, err
+	}
+	if err := form.WriteField("request", string(request)); err != nil {
+		return nil, err
+	}
+	archive, err := form.CreateFormFile("archive", "tree.tar.gz")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeArchive(archive, root); err != nil {
+		return nil, fmt.Errorf("archiving %s: %w", root, err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, b.URL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if b.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.Token)
+	}
+	client := b.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("%s returned %s: %s", b.URL, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	var result HTTPResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid response from %s: %w", b.URL, err)
+	}
+	if result.ExitCode != 0 {
+		return []byte(result.Stdout), &ExitError{Code: result.ExitCode, Stderr: []byte(result.Stderr)}
+	}
+	return []byte(result.Stdout), nil
+}
+
+// writeArchive writes the regular files and directories under root to w
+// as a gzipped tarball, skipping .git and node_modules.
+func writeArchive(w io.Writer, root string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if d.IsDir() && (d.Name() == ".git" || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// RunReleasekitOn runs `releasekit validate` on dir, a path relative to
+// root, with backend b. Results without a detection path are attributed
+// to dir, and relative paths are taken as relative to it.
+func RunReleasekitOn(b Backend, root, dir string, opts Options) ([]Result, error) {
+	if r, ok := disallowed("releasekit validate", "releasekit"); ok {
+		return []Result{r}, nil
+	}
+
+	args := append([]string{"releasekit"}, releasekitArgs(opts)...)
+	output, err := b.Output(root, dir, withEnv(opts.Env, append(args, ".")))
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		// releasekit exits with code 2 for NO-GO
+		if exitErr.Code != 2 {
+			return nil, fmt.Errorf("releasekit failed on %s: %w\nstderr: %s", b.Name(), err, string(exitErr.Stderr))
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	results, err := parseReleasekitOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	hostDir := filepath.Join(root, filepath.FromSlash(dir))
+	for i := range results {
+		switch p := results[i].Path; {
+		case p == "" || p == ".":
+			results[i].Path = hostDir
+		case !path.IsAbs(p):
+			results[i].Path = filepath.Join(hostDir, filepath.FromSlash(p))
+		}
+	}
+	return results, nil
+}
+
+// CheckGoRace runs the Go tests in dir, a path relative to root, with the
+// race detector on backend b, adding env to the environment.
+func CheckGoRace(b Backend, root, dir string, env []string) Result {
+	name := "Go: race detector"
+	hostDir := filepath.Join(root, filepath.FromSlash(dir))
+	if r, ok := disallowed(name, "go"); ok {
+		r.Path = hostDir
+		return r
+	}
+
+	start := time.Now()
+	output, err := b.Output(root, dir, withEnv(env, []string{"go", "test", "-json", "-race", "./..."}))
+	parser := NewGoTestParser()
+	parser.Parse(output)
+	text, summary := parser.Output()
+	result := Result{
+		Name:     name,
+		Path:     hostDir,
+		Passed:   err == nil,
+		Output:   text,
+		Duration: time.Since(start),
+		Tests:    summary,
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		result.Output = strings.TrimSpace(result.Output + "\n" + string(exitErr.Stderr))
+	} else if err != nil {
+		result.Error = err
+	}
+	return result
+}
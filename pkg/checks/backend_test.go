@@ -0,0 +1,180 @@
+package checks
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+const agentResultJSON = `{"schema":"x","agent_id":"qa","step_id":"qa","inputs":{},"outputs":{},"checks":[],"status":"NO-GO","executed_at":"2025-01-01T00:00:00Z","tasks":[
+{"id":"Go: tests","status":"NO-GO","detail":"boom","metadata":{"path":"svc/api"}},
+{"id":"Go: build","status":"GO"}]}`
+
+// fakeBackend replies to every command with canned output.
+type fakeBackend struct {
+	output []byte
+	err    error
+	args   []string
+	dir    string
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Output(root, dir string, args []string) ([]byte, error) {
+	b.dir, b.args = dir, args
+	return b.output, b.err
+}
+
+func TestLocalBackend_ExitError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	output, err := LocalBackend{}.Output(dir, "sub", []string{"sh", "-c", "pwd; echo oops >&2; exit 3"})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 || string(exitErr.Stderr) != "oops\n" {
+		t.Fatalf("expected exit code 3, got %v", err)
+	}
+	if filepath.Base(string(output[:len(output)-1])) != "sub" {
+		t.Errorf("expected command to run in sub, got %q", output)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"./...":         "./...",
+		"":              "''",
+		"a b":           "'a b'",
+		"it's":          `'it'\''s'`,
+		"GOFLAGS=-race": "GOFLAGS=-race",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestHTTPBackend(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "go.mod"), "module example")
+	writeTestFile(t, filepath.Join(root, "svc", "main.go"), "package main")
+	writeTestFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main")
+
+	var files []string
+	var request HTTPRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("request")), &request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("archive")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			files = append(files, hdr.Name)
+		}
+		_ = json.NewEncoder(w).Encode(HTTPResponse{ExitCode: 1, Stdout: "FAIL", Stderr: "race detected"})
+	}))
+	defer server.Close()
+
+	b := HTTPBackend{URL: server.URL, Token: "secret"}
+	output, err := b.Output(root, "svc", []string{"go", "test", "-race", "./..."})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 1 || string(output) != "FAIL" {
+		t.Fatalf("expected exit code 1 with output, got %q, %v", output, err)
+	}
+	if request.Dir != "svc" || !slices.Equal(request.Args, []string{"go", "test", "-race", "./..."}) {
+		t.Errorf("unexpected request: %+v", request)
+	}
+	slices.Sort(files)
+	if want := []string{"go.mod", "svc/", "svc/main.go"}; !slices.Equal(files, want) {
+		t.Errorf("archive files = %v, want %v", files, want)
+	}
+
+	if _, err := (HTTPBackend{URL: server.URL}).Output(root, ".", []string{"true"}); err == nil || errors.As(err, &exitErr) {
+		t.Errorf("expected an HTTP error without a token, got %v", err)
+	}
+}
+
+func TestRunReleasekitOn(t *testing.T) {
+	b := &fakeBackend{output: []byte(agentResultJSON), err: &ExitError{Code: 2}}
+	results, err := RunReleasekitOn(b, "repo", ".", Options{Test: true})
+	if err != nil {
+		t.Fatalf("RunReleasekitOn failed: %v", err)
+	}
+	if b.args[0] != "releasekit" || b.args[len(b.args)-1] != "." || !slices.Contains(b.args, "--no-lint") {
+		t.Errorf("unexpected command: %v", b.args)
+	}
+	if len(results) != 2 || results[0].Passed || !results[1].Passed {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[0].Path != filepath.Join("repo", "svc", "api") || results[1].Path != "repo" {
+		t.Errorf("paths = %q, %q", results[0].Path, results[1].Path)
+	}
+
+	b.err = &ExitError{Code: 1, Stderr: []byte("crashed")}
+	if _, err := RunReleasekitOn(b, "repo", ".", Options{}); err == nil {
+		t.Error("expected an error for exit code 1")
+	}
+}
+
+func TestCheckGoRace(t *testing.T) {
+	events := `{"Action":"output","Package":"m/api","Test":"TestServe","Output":"WARNING: DATA RACE\n"}
+{"Action":"fail","Package":"m/api","Test":"TestServe","Elapsed":0.5}
+{"Action":"output","Package":"m/api","Output":"FAIL\tm/api\t0.6s\n"}
+{"Action":"fail","Package":"m/api","Elapsed":0.6}
+`
+	b := &fakeBackend{output: []byte(events), err: &ExitError{Code: 1, Stderr: []byte("FAIL")}}
+	r := CheckGoRace(b, "repo", "svc/api", nil)
+	want := "0 passed, 1 failed, 0 skipped in 1 packages\nFailed: m/api.TestServe\nSlowest: m/api.TestServe (500ms)\n\n" +
+		"WARNING: DATA RACE\nFAIL\tm/api\t0.6s\nFAIL"
+	if r.Passed || r.Error != nil || r.Path != filepath.Join("repo", "svc", "api") || r.Output != want {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if r.Tests == nil || r.Tests.Failed != 1 {
+		t.Errorf("tests = %+v", r.Tests)
+	}
+	if b.dir != "svc/api" {
+		t.Errorf("ran in %q, want svc/api", b.dir)
+	}
+
+	b = &fakeBackend{}
+	CheckGoRace(b, "repo", ".", []string{"GOFLAGS=-tags=integration"})
+	if want := []string{"env", "GOFLAGS=-tags=integration", "go", "test", "-json", "-race", "./..."}; !slices.Equal(b.args, want) {
+		t.Errorf("ran %q, want %q", b.args, want)
+	}
+
+	b = &fakeBackend{err: errors.New("connection refused")}
+	if r := CheckGoRace(b, "repo", ".", nil); r.Passed || r.Error == nil {
+		t.Errorf("expected a backend error, got %+v", r)
+	}
+}
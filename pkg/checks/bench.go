@@ -0,0 +1,248 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// BenchOptions configures the benchmark regression check.
+type BenchOptions struct {
+	Packages  []string // Package patterns to benchmark, e.g. "./pkg/..."
+	Pattern   string   // -bench regexp
+	Count     int      // Runs per benchmark; more runs give more reliable results
+	Threshold float64  // Minimum slowdown in percent to report
+	Alpha     float64  // Significance level for the Mann-Whitney U test
+	Fail      bool     // Fail instead of warn on regressions
+}
+
+// BenchDelta is the comparison of one benchmark between base and head.
+type BenchDelta struct {
+	Name        string
+	Base        float64 // Median ns/op on the base commit
+	Head        float64 // Median ns/op on HEAD
+	Change      float64 // Percent change of the median (positive is slower)
+	P           float64 // Mann-Whitney U p-value
+	Significant bool    // P is below the significance level
+}
+
+var benchLinePattern = regexp.MustCompile(`^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns/op`)
+
+// ParseBenchOutput parses `go test -bench` output into ns/op samples keyed
+// by benchmark name (without the GOMAXPROCS suffix). Benchmarks in
+// different packages are distinguished by the preceding "pkg:" line.
+func ParseBenchOutput(output string) map[string][]float64 {
+	samples := make(map[string][]float64)
+	pkg := ""
+	for _, line := range strings.Split(output, "\n") {
+		if p, ok := strings.CutPrefix(line, "pkg: "); ok {
+			pkg = strings.TrimSpace(p)
+			continue
+		}
+		m := benchLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		name := m[1]
+		if pkg != "" {
+			name = pkg + "." + name
+		}
+		samples[name] = append(samples[name], v)
+	}
+	return samples
+}
+
+// RunBenchmarks runs the configured benchmarks in dir and returns the
+// ns/op samples.
+func RunBenchmarks(dir string, opts BenchOptions) (map[string][]float64, error) {
+	args := []string{"test", "-run", "^$", "-bench", opts.Pattern, "-count", strconv.Itoa(opts.Count)}
+	args = append(args, opts.Packages...)
+
+	cmd := proc.Command("go", args...)
+	cmd.Dir = dir
+	output, err := runOutput(cmd, "go test -bench", true)
+	if err != nil {
+		return nil, fmt.Errorf("go test -bench failed: %s", strings.TrimSpace(string(output)))
+	}
+	return ParseBenchOutput(string(output)), nil
+}
+
+// CompareBenchmarks compares benchmarks present in both base and head,
+// sorted by name, in the style of benchstat: medians are compared and a
+// Mann-Whitney U test decides whether the difference is significant.
+func CompareBenchmarks(base, head map[string][]float64, alpha float64) []BenchDelta {
+	var deltas []BenchDelta
+	for name, h := range head {
+		b, ok := base[name]
+		if !ok || len(b) == 0 || len(h) == 0 {
+			continue
+		}
+		d := BenchDelta{
+			Name: name,
+			Base: median(b),
+			Head: median(h),
+			P:    mannWhitneyP(b, h),
+		}
+		if d.Base > 0 {
+			d.Change = (d.Head - d.Base) / d.Base * 100
+		}
+		d.Significant = d.P < alpha
+		deltas = append(deltas, d)
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Name < deltas[j].Name
+	})
+	return deltas
+}
+
+// CheckBenchmarks runs benchmarks on HEAD and on the base commit (in a
+// temporary worktree) and reports significant regressions.
+func CheckBenchmarks(dir, base string, opts BenchOptions) Result {
+	name := "Go: benchmark regressions"
+	if r, ok := disallowed(name, "go", "git"); ok {
+		return r
+	}
+
+	head, err := RunBenchmarks(dir, opts)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	if len(head) == 0 {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No benchmarks matched",
+		}
+	}
+
+	var baseSamples map[string][]float64
+	err = inWorktree(dir, base, func(wt string) error {
+		var err error
+		baseSamples, err = RunBenchmarks(wt, opts)
+		return err
+	})
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+
+	var lines, regressions []string
+	for _, d := range CompareBenchmarks(baseSamples, head, opts.Alpha) {
+		line := fmt.Sprintf("%s: %s -> %s (%+.1f%%, p=%.3f)", d.Name, formatNsOp(d.Base), formatNsOp(d.Head), d.Change, d.P)
+		if !d.Significant {
+			line = fmt.Sprintf("%s: %s -> %s (~, p=%.3f)", d.Name, formatNsOp(d.Base), formatNsOp(d.Head), d.P)
+		}
+		lines = append(lines, line)
+		if d.Significant && d.Change > opts.Threshold {
+			regressions = append(regressions, line)
+		}
+	}
+
+	if len(regressions) > 0 {
+		return Result{
+			Name:    name,
+			Warning: !opts.Fail,
+			Passed:  false,
+			Output: fmt.Sprintf("Benchmarks slower by more than %.0f%%:\n%s",
+				opts.Threshold, strings.Join(regressions, "\n")),
+			Remediation: "profile the slower benchmarks against the base, e.g. with go test -bench <name> -cpuprofile",
+		}
+	}
+
+	if len(lines) == 0 {
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: "No benchmarks in common with the base commit",
+		}
+	}
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: strings.Join(lines, "\n"),
+	}
+}
+
+func formatNsOp(v float64) string {
+	switch {
+	case v >= 1e9:
+		return fmt.Sprintf("%.2fs", v/1e9)
+	case v >= 1e6:
+		return fmt.Sprintf("%.2fms", v/1e6)
+	case v >= 1e3:
+		return fmt.Sprintf("%.2fµs", v/1e3)
+	default:
+		return fmt.Sprintf("%.2fns", v)
+	}
+}
+
+func median(values []float64) float64 {
+	s := append([]float64(nil), values...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// mannWhitneyP returns the two-sided p-value of the Mann-Whitney U test
+// using the normal approximation with tie correction.
+func mannWhitneyP(a, b []float64) float64 {
+	n1, n2 := float64(len(a)), float64(len(b))
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type sample struct {
+		v     float64
+		fromA bool
+	}
+	all := make([]sample, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, sample{v, true})
+	}
+	for _, v := range b {
+		all = append(all, sample{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// Average ranks over ties
+	var rankA, tieSum float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankA += rank
+			}
+		}
+		t := float64(j - i)
+		tieSum += t*t*t - t
+		i = j
+	}
+
+	u := rankA - n1*(n1+1)/2
+	n := n1 + n2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - tieSum/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Erfc(z / math.Sqrt2)
+}
@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseBenchOutput(t *testing.T) {
+	output := `goos: linux
+goarch: amd64
+pkg: example.com/m/parser
+cpu: Some CPU
+BenchmarkParse-8   	  100000	     1200 ns/op	     320 B/op	       4 allocs/op
+BenchmarkParse-8   	  100000	     1250.5 ns/op	     320 B/op	       4 allocs/op
+BenchmarkParse/large-8	     100	  2000000 ns/op
+PASS
+ok  	example.com/m/parser	3.2s
+pkg: example.com/m/lexer
+BenchmarkLex	 5000000	      250 ns/op
+`
+	got := ParseBenchOutput(output)
+	if s := got["example.com/m/parser.BenchmarkParse"]; len(s) != 2 || s[0] != 1200 || s[1] != 1250.5 {
+		t.Errorf("unexpected Parse samples %v", s)
+	}
+	if s := got["example.com/m/parser.BenchmarkParse/large"]; len(s) != 1 || s[0] != 2000000 {
+		t.Errorf("unexpected Parse/large samples %v", s)
+	}
+	if s := got["example.com/m/lexer.BenchmarkLex"]; len(s) != 1 || s[0] != 250 {
+		t.Errorf("unexpected Lex samples %v", s)
+	}
+}
+
+func TestCompareBenchmarks(t *testing.T) {
+	base := map[string][]float64{
+		"BenchmarkSlow":   {100, 101, 99, 100, 102},
+		"BenchmarkNoisy":  {100, 130, 90, 120, 95},
+		"BenchmarkRemove": {10, 10, 10},
+	}
+	head := map[string][]float64{
+		"BenchmarkSlow":  {120, 121, 119, 122, 118},
+		"BenchmarkNoisy": {105, 125, 92, 118, 99},
+		"BenchmarkNew":   {5, 5, 5},
+	}
+
+	deltas := CompareBenchmarks(base, head, 0.05)
+	if len(deltas) != 2 {
+		t.Fatalf("expected 2 deltas, got %+v", deltas)
+	}
+
+	noisy, slow := deltas[0], deltas[1]
+	if noisy.Name != "BenchmarkNoisy" || noisy.Significant {
+		t.Errorf("expected noisy benchmark to be insignificant, got %+v", noisy)
+	}
+	if slow.Name != "BenchmarkSlow" || !slow.Significant {
+		t.Errorf("expected slow benchmark to be significant, got %+v", slow)
+	}
+	if math.Abs(slow.Change-20) > 0.01 {
+		t.Errorf("expected +20%% change, got %.2f", slow.Change)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if m := median([]float64{3, 1, 2}); m != 2 {
+		t.Errorf("median odd = %v, want 2", m)
+	}
+	if m := median([]float64{4, 1, 3, 2}); m != 2.5 {
+		t.Errorf("median even = %v, want 2.5", m)
+	}
+}
+
+func TestMannWhitneyP(t *testing.T) {
+	// Identical samples are not significant
+	if p := mannWhitneyP([]float64{1, 1, 1}, []float64{1, 1, 1}); p < 0.99 {
+		t.Errorf("expected p=1 for identical samples, got %v", p)
+	}
+	// Fully separated samples of 5 are significant at 0.05
+	if p := mannWhitneyP([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}); p >= 0.05 {
+		t.Errorf("expected p<0.05 for separated samples, got %v", p)
+	}
+}
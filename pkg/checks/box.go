@@ -0,0 +1,190 @@
+package checks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/term"
+	"golang.org/x/text/width"
+)
+
+// maxBoxWidth caps report boxes when the terminal width is unknown, e.g.
+// when output goes to a file or CI log.
+const maxBoxWidth = 100
+
+// terminalWidth returns the width of the terminal on stdout, or $COLUMNS
+// when stdout isn't a terminal, or 0 if neither is known. Tests replace
+// it.
+var terminalWidth = func() int {
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+// boxWidth returns the width, borders included, of a box whose widest line
+// is content columns: wide enough for it and a margin if there is room, at
+// least minWidth, and no wider than the terminal.
+func boxWidth(minWidth, content int) int {
+	available := terminalWidth()
+	if available <= 0 {
+		available = maxBoxWidth
+	}
+	w := max(minWidth, content+3) // Borders and the right margin
+	return max(min(w, available), 20)
+}
+
+// ansiEscape matches ANSI CSI escape sequences such as colors.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+// displayWidth returns the number of terminal columns s takes: escape
+// sequences and combining marks take none, and wide runes such as CJK and
+// emoji take two.
+func displayWidth(s string) int {
+	s = ansiEscape.ReplaceAllString(s, "")
+	n := 0
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '\uFE0F':
+			// Emoji presentation widens a narrow symbol such as ⚠
+			if i > 0 && runeWidth(runes[i-1]) == 1 {
+				n++
+			}
+		default:
+			n += runeWidth(r)
+		}
+	}
+	return n
+}
+
+// runeWidth returns the number of terminal columns r takes.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.IsControl(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// wrapText splits s into lines of at most w columns, breaking at spaces
+// and splitting words longer than a line. Each line of s is wrapped on
+// its own.
+func wrapText(s string, w int) []string {
+	w = max(w, 1)
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for displayWidth(word) > w {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				head, tail := splitWidth(word, w)
+				lines = append(lines, head)
+				word = tail
+			}
+			switch {
+			case line == "":
+				line = word
+			case displayWidth(line)+1+displayWidth(word) <= w:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		if line != "" || len(lines) == 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitWidth splits s after at most w columns, keeping at least one rune
+// in the head.
+func splitWidth(s string, w int) (head, tail string) {
+	n := 0
+	for i, r := range s {
+		n += runeWidth(r)
+		if n > w && i > 0 {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+// padRight pads s with spaces to w columns.
+func padRight(s string, w int) string {
+	return s + strings.Repeat(" ", max(w-displayWidth(s), 0))
+}
+
+// box draws a report box of a fixed width with double-line borders.
+type box struct {
+	w     io.Writer
+	width int // Including the borders
+}
+
+// inner returns the number of columns between the borders.
+func (b box) inner() int {
+	return b.width - 2
+}
+
+func (b box) rule(left, right string) {
+	fmt.Fprintln(b.w, left+strings.Repeat("═", b.inner())+right)
+}
+
+func (b box) top()     { b.rule("╔", "╗") }
+func (b box) divider() { b.rule("╠", "╣") }
+func (b box) bottom()  { b.rule("╚", "╝") }
+
+// line prints s padded to the box width, or cut if it is too wide.
+func (b box) line(s string) {
+	if pad := b.inner() - displayWidth(s); pad >= 0 {
+		fmt.Fprintln(b.w, "║"+s+strings.Repeat(" ", pad)+"║")
+		return
+	}
+	head, _ := splitWidth(s, b.inner())
+	b.line(head)
+}
+
+// center prints s centered in the box.
+func (b box) center(s string) {
+	left := max(b.inner()-displayWidth(s), 0) / 2
+	b.line(strings.Repeat(" ", left) + s)
+}
+
+// wrapped prints s wrapped to the box after prefix, indenting the lines
+// after the first by indent columns.
+func (b box) wrapped(prefix string, indent int, s string) {
+	lines := wrapText(s, b.inner()-1-max(displayWidth(prefix), indent))
+	for i, l := range lines {
+		if i == 0 {
+			b.line(prefix + l)
+		} else {
+			b.line(strings.Repeat(" ", indent) + l)
+		}
+	}
+}
+
+// PrintBanner prints title centered in a box the width of a validation
+// report, or wider if the title needs it.
+func PrintBanner(title string) {
+	b := box{w: os.Stdout, width: boxWidth(reportMinWidth, displayWidth(title)+1)}
+	b.top()
+	b.center(title)
+	b.bottom()
+}
@@ -0,0 +1,126 @@
+package checks
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"GO", 2},
+		{"🟢 GO", 5},
+		{"✅ SIGNED OFF", 13},
+		{"⚠️ warn", 7},
+		{"日本語", 6},
+		{"\x1b[31mred\x1b[0m", 3},
+		{"e\u0301", 1},
+	}
+	for _, tt := range tests {
+		if got := displayWidth(tt.s); got != tt.want {
+			t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		s    string
+		w    int
+		want []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"one two three", 7, []string{"one two", "three"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"first\nsecond line", 6, []string{"first", "second", "line"}},
+		{"", 5, []string{""}},
+	}
+	for _, tt := range tests {
+		if got := wrapText(tt.s, tt.w); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapText(%q, %d) = %q, want %q", tt.s, tt.w, got, tt.want)
+		}
+	}
+}
+
+func TestBoxWidth(t *testing.T) {
+	defer func(f func() int) { terminalWidth = f }(terminalWidth)
+
+	terminalWidth = func() int { return 0 }
+	if got := boxWidth(80, 40); got != 80 {
+		t.Errorf("short content: width %d, want 80", got)
+	}
+	if got := boxWidth(80, 90); got != 93 {
+		t.Errorf("long content: width %d, want 93", got)
+	}
+	if got := boxWidth(80, 200); got != maxBoxWidth {
+		t.Errorf("unknown terminal: width %d, want %d", got, maxBoxWidth)
+	}
+
+	terminalWidth = func() int { return 60 }
+	if got := boxWidth(80, 40); got != 60 {
+		t.Errorf("narrow terminal: width %d, want 60", got)
+	}
+}
+
+// checkBoxLines fails unless every line of a box has the same width.
+func checkBoxLines(t *testing.T, out string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	want := displayWidth(lines[0])
+	for _, l := range lines {
+		if got := displayWidth(l); got != want {
+			t.Errorf("line %q is %d columns, want %d:\n%s", l, got, want, out)
+		}
+	}
+}
+
+func TestWriteValidationReport(t *testing.T) {
+	defer func(f func() int) { terminalWidth = f }(terminalWidth)
+	terminalWidth = func() int { return 80 }
+
+	results := []Result{
+		{Name: "QA: " + strings.Repeat("very long check name ", 5), Passed: true},
+		{Name: "QA: 日本語のチェック", Skipped: true, Reason: "not installed"},
+		{Name: "QA: tests", Error: errors.New("exit status 1"), Remediation: strings.Repeat("run the tests and fix them ", 4)},
+	}
+	report := &ValidationReport{
+		Version: "v1.2.0",
+		Areas:   []AreaResult{{Area: AreaQA, Status: ComputeAreaStatus(results), Results: results}},
+	}
+
+	var buf bytes.Buffer
+	writeValidationReport(&buf, report)
+	out := buf.String()
+	checkBoxLines(t, out)
+	for _, want := range []string{"RELEASE VALIDATION: v1.2.0", "very long check name", "Fix: run the tests", "NOT APPROVED"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	if displayWidth(strings.SplitN(strings.TrimSpace(out), "\n", 2)[0]) != 80 {
+		t.Errorf("report wider than the terminal:\n%s", out)
+	}
+}
+
+func TestWriteGoNoGoReport(t *testing.T) {
+	defer func(f func() int) { terminalWidth = f }(terminalWidth)
+	terminalWidth = func() int { return 0 }
+
+	results := []Result{
+		{Name: "Go: build", Passed: true},
+		{Name: "Go: tests in github.com/example/a-project-with-a-very-long-name", Output: "--- FAIL: TestX\nmore"},
+	}
+	var buf bytes.Buffer
+	if writeGoNoGoReport(&buf, results, true) {
+		t.Error("writeGoNoGoReport() = true with a failure")
+	}
+	out := buf.String()
+	checkBoxLines(t, out)
+	if !strings.Contains(out, "└─ --- FAIL: TestX") || strings.Contains(out, "more") {
+		t.Errorf("detail should be the first line of the output:\n%s", out)
+	}
+}
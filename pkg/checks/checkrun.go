@@ -0,0 +1,175 @@
+package checks
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// resultDetail returns why a result didn't pass, or its output if it did.
+func resultDetail(r Result) string {
+	switch {
+	case r.Reason != "":
+		return r.Reason
+	case r.Error != nil && r.Output != "":
+		return r.Error.Error() + "\n" + r.Output
+	case r.Error != nil:
+		return r.Error.Error()
+	}
+	return r.Output
+}
+
+// markdownCell escapes text for a single Markdown table cell, keeping its
+// first line.
+func markdownCell(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// blockedAreas returns the areas that are NO-GO.
+func (r *ValidationReport) blockedAreas() []string {
+	var blocked []string
+	for _, area := range r.Areas {
+		if area.Status == StatusNoGo {
+			blocked = append(blocked, string(area.Area))
+		}
+	}
+	return blocked
+}
+
+// Verdict returns the one-line outcome of the report, e.g. "GO for
+// release v1.2.0" or "NO-GO: PM, QA", in the configured labels.
+func (r *ValidationReport) Verdict() string {
+	if !r.IsGo() {
+		return StatusNoGo.Label() + ": " + strings.Join(r.blockedAreas(), ", ")
+	}
+	if r.Version != "" {
+		return StatusGo.Label() + " for release " + r.Version
+	}
+	return StatusGo.Label() + " for release"
+}
+
+// Markdown renders the report as Markdown: the verdict, a status table of
+// the areas, and a table of each area's checks, with the remediation of
+// each failure and warning.
+func (r *ValidationReport) Markdown() string {
+	var b strings.Builder
+	icon := IconGo
+	if !r.IsGo() {
+		icon = IconNoGo
+	}
+	fmt.Fprintf(&b, "## %s %s\n\n", icon, r.Verdict())
+
+	b.WriteString("| Area | Status |\n")
+	b.WriteString("|------|--------|\n")
+	for _, area := range r.Areas {
+		fmt.Fprintf(&b, "| %s | %s %s |\n", area.Area, area.Status.Icon(), area.Status.Label())
+	}
+
+	for _, area := range r.Areas {
+		fmt.Fprintf(&b, "\n### %s\n\n", area.Area)
+		b.WriteString("| Check | Status | Details |\n")
+		b.WriteString("|-------|--------|---------|\n")
+		for _, res := range area.Results {
+			status := res.Severity().Status()
+			detail := ""
+			if res.Severity() != SeverityPassed {
+				detail = markdownCell(resultDetail(res))
+			}
+			if fix := res.ShownRemediation(); fix != "" {
+				detail += "<br>**Fix:** " + markdownCell(fix)
+			}
+			fmt.Fprintf(&b, "| %s | %s %s | %s |\n", markdownCell(res.Name), status.Icon(), status.Label(), detail)
+		}
+	}
+	return b.String()
+}
+
+// CheckRun converts the report to a GitHub check run named name on the
+// commit sha, with the Markdown report as its summary and an annotation
+// for each failure and warning, ending with its remediation if it has one.
+// Results with a detection path are annotated on it; the rest on the
+// repository root. Lint results with findings get an annotation at each
+// finding instead.
+func (r *ValidationReport) CheckRun(name, sha string) git.CheckRun {
+	run := git.CheckRun{
+		Name:       name,
+		HeadSHA:    sha,
+		Conclusion: "success",
+		Title:      r.Verdict(),
+		Summary:    r.Markdown(),
+	}
+	if !r.IsGo() {
+		run.Conclusion = "failure"
+	}
+
+	for _, area := range r.Areas {
+		for _, res := range area.Results {
+			level := ""
+			switch res.Severity() {
+			case SeverityFailed:
+				level = "failure"
+			case SeverityWarning:
+				level = "warning"
+			default:
+				continue
+			}
+			if len(res.Findings) > 0 {
+				run.Annotations = append(run.Annotations, findingAnnotations(res, level)...)
+				continue
+			}
+			path := res.Path
+			if path == "" {
+				path = "."
+			}
+			message := resultDetail(res)
+			if message == "" {
+				message = string(res.Severity())
+			}
+			if res.Remediation != "" {
+				message += "\n\nFix: " + res.Remediation
+			}
+			run.Annotations = append(run.Annotations, git.CheckRunAnnotation{
+				Path:      path,
+				StartLine: 1,
+				EndLine:   1,
+				Level:     level,
+				Title:     res.Name,
+				Message:   message,
+			})
+		}
+	}
+	return run
+}
+
+// findingAnnotations returns an annotation at each finding of res, at
+// most at level: findings of a warning result are warnings.
+func findingAnnotations(res Result, level string) []git.CheckRunAnnotation {
+	annotations := make([]git.CheckRunAnnotation, 0, len(res.Findings))
+	for _, f := range res.Findings {
+		l := level
+		if f.Severity == FindingWarning {
+			l = "warning"
+		}
+		title := res.Name
+		if f.Rule != "" {
+			title += ": " + f.Rule
+		}
+		message := f.Message
+		if res.Remediation != "" {
+			message += "\n\nFix: " + res.Remediation
+		}
+		annotations = append(annotations, git.CheckRunAnnotation{
+			Path:      path.Join(filepath.ToSlash(res.Path), f.File),
+			StartLine: f.Line,
+			EndLine:   f.Line,
+			Level:     l,
+			Title:     title,
+			Message:   message,
+		})
+	}
+	return annotations
+}
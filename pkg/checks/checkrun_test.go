@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testValidationReport() *ValidationReport {
+	pm := []Result{
+		{Name: "PM: release-scope", Passed: false, Warning: true, Reason: "Version v1.2.0 not found | in CHANGELOG.json"},
+		{Name: "PM: changelog-quality", Passed: true, Output: "3 highlights present", Remediation: "add highlights"},
+	}
+	qa := []Result{
+		{Name: "Go: tests", Passed: false, Error: errors.New("exit status 1"), Output: "--- FAIL: TestX", Path: "svc/api", Remediation: "fix the failing tests: go test ./..."},
+		{Name: "Go: lint", Skipped: true, Reason: "golangci-lint not installed"},
+	}
+	return &ValidationReport{
+		Version: "v1.2.0",
+		Areas: []AreaResult{
+			{Area: AreaPM, Status: ComputeAreaStatus(pm), Results: pm},
+			{Area: AreaQA, Status: ComputeAreaStatus(qa), Results: qa},
+		},
+	}
+}
+
+func TestValidationReport_Markdown(t *testing.T) {
+	md := testValidationReport().Markdown()
+	for _, want := range []string{
+		"## 🔴 NO-GO: QA\n",
+		"| PM | 🟡 WARN |\n",
+		"### QA\n",
+		"| PM: release-scope | 🟡 WARN | Version v1.2.0 not found \\| in CHANGELOG.json |\n",
+		"| PM: changelog-quality | 🟢 GO |  |\n",
+		"| Go: tests | 🔴 NO-GO | exit status 1<br>**Fix:** fix the failing tests: go test ./... |\n",
+		"| Go: lint | ⚪ SKIP | golangci-lint not installed |\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestValidationReport_CheckRun(t *testing.T) {
+	vr := testValidationReport()
+	run := vr.CheckRun("atrelease validate", "abc123")
+	if run.Conclusion != "failure" || run.Title != "NO-GO: QA" || run.HeadSHA != "abc123" {
+		t.Errorf("CheckRun() = %+v", run)
+	}
+	if len(run.Annotations) != 2 {
+		t.Fatalf("annotations = %+v, want a warning and a failure", run.Annotations)
+	}
+	if a := run.Annotations[0]; a.Level != "warning" || a.Path != "." || a.Title != "PM: release-scope" {
+		t.Errorf("warning annotation = %+v", a)
+	}
+	if a := run.Annotations[1]; a.Level != "failure" || a.Path != "svc/api" || a.Message != "exit status 1\n--- FAIL: TestX\n\nFix: fix the failing tests: go test ./..." {
+		t.Errorf("failure annotation = %+v", a)
+	}
+
+	vr.Areas = vr.Areas[:1]
+	if run := vr.CheckRun("atrelease validate", "abc123"); run.Conclusion != "success" || run.Title != "GO for release v1.2.0" {
+		t.Errorf("GO report: %+v", run)
+	}
+}
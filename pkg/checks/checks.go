@@ -3,13 +3,18 @@ package checks
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // Result represents the result of a check.
 type Result struct {
+	ID      string // Stable machine ID, e.g. "go.test"; see ResultID
 	Name    string
 	Passed  bool
 	Output  string
@@ -17,6 +22,71 @@ type Result struct {
 	Skipped bool
 	Reason  string
 	Warning bool // Soft check: reported but doesn't fail the build
+
+	// Remediation is how to fix a failure or warning, e.g. "run go mod
+	// tidy"; reports show it with the check when it doesn't pass
+	Remediation string
+
+	Path     string // Detection path the check ran in; empty for the whole directory
+	Language string // Language checked; empty to use the "Language: " prefix of Name
+	Command  string // Command the check ran, if known; used to deduplicate results
+
+	Duration time.Duration // How long the check took, if known
+
+	// Tests summarizes the tests of a check that ran go test -json
+	Tests *TestSummary
+
+	// Findings are the issues of a failed or warning lint check, minus
+	// waived ones
+	Findings []Finding
+}
+
+// Severity is the outcome of a result as shown in reports.
+type Severity string
+
+const (
+	SeverityPassed  Severity = "passed"
+	SeverityFailed  Severity = "failed"
+	SeverityWarning Severity = "warning" // Soft failure that doesn't block
+	SeveritySkipped Severity = "skipped"
+)
+
+// Severity returns the outcome of r. A warning that passed counts as
+// passed.
+func (r Result) Severity() Severity {
+	switch {
+	case r.Skipped:
+		return SeveritySkipped
+	case r.Passed:
+		return SeverityPassed
+	case r.Warning:
+		return SeverityWarning
+	default:
+		return SeverityFailed
+	}
+}
+
+// ShownRemediation returns the remediation reports show for r: its
+// Remediation if it failed or warned, otherwise "".
+func (r Result) ShownRemediation() string {
+	if s := r.Severity(); s == SeverityFailed || s == SeverityWarning {
+		return r.Remediation
+	}
+	return ""
+}
+
+// Status returns the Go/No-Go status of a single result with severity s.
+func (s Severity) Status() AreaStatus {
+	switch s {
+	case SeverityPassed:
+		return StatusGo
+	case SeverityWarning:
+		return StatusWarn
+	case SeveritySkipped:
+		return StatusSkip
+	default:
+		return StatusNoGo
+	}
 }
 
 // Checker is the interface for language-specific checks.
@@ -32,6 +102,7 @@ type Options struct {
 	Format   bool
 	Coverage bool
 	Verbose  bool
+	Env      []string // Extra "KEY=value" environment for check commands
 
 	// Language-specific options
 	GoExcludeCoverage string // directories to exclude from coverage (e.g., "cmd")
@@ -49,19 +120,40 @@ func DefaultOptions() Options {
 	}
 }
 
-// RunCommand executes a command and returns the result.
+// RunCommand executes a command and returns the result. Commands not
+// allowed by the command policy are skipped.
 func RunCommand(name string, dir string, command string, args ...string) Result {
-	cmd := exec.Command(command, args...)
+	if r, ok := disallowed(name, command); ok {
+		return r
+	}
+
+	cmd := proc.Command(command, args...)
 	cmd.Dir = dir
 
-	output, err := cmd.CombinedOutput()
+	start := time.Now()
+	output, err := runOutput(cmd, name, true)
 
 	return Result{
-		Name:   name,
-		Passed: err == nil,
-		Output: strings.TrimSpace(string(output)),
-		Error:  err,
+		Name:     name,
+		Passed:   err == nil,
+		Output:   strings.TrimSpace(string(output)),
+		Error:    err,
+		Duration: time.Since(start),
+	}
+}
+
+// PromoteWarnings returns results with every warning turned into a
+// failure, for strict runs that hold soft checks to the same bar as the
+// rest.
+func PromoteWarnings(results []Result) []Result {
+	out := make([]Result, len(results))
+	for i, r := range results {
+		if r.Severity() == SeverityWarning {
+			r.Warning = false
+		}
+		out[i] = r
 	}
+	return out
 }
 
 // CommandExists checks if a command is available in PATH.
@@ -95,6 +187,9 @@ func PrintResults(results []Result, verbose bool) (passed int, failed int, skipp
 					fmt.Printf("  %s\n", line)
 				}
 			}
+			if fix := r.ShownRemediation(); fix != "" {
+				fmt.Printf("  Fix: %s\n", fix)
+			}
 			if r.Passed {
 				passed++
 			}
@@ -120,6 +215,9 @@ func PrintResults(results []Result, verbose bool) (passed int, failed int, skipp
 			if r.Error != nil && r.Output == "" {
 				fmt.Printf("  Error: %v\n", r.Error)
 			}
+			if fix := r.ShownRemediation(); fix != "" {
+				fmt.Printf("  Fix: %s\n", fix)
+			}
 		}
 	}
 
@@ -135,7 +233,7 @@ func FileExists(path string) bool {
 // ValidationStatus represents a Go/No-Go status for a check.
 type ValidationStatus struct {
 	Name   string
-	Status string // "GO" or "NO-GO"
+	Status string // Status label, e.g. "GO" or "NO-GO"
 	Icon   string // UTF-8 icon
 	Detail string // Optional detail message
 }
@@ -148,72 +246,83 @@ const (
 	IconWarning = "🟡" // Yellow circle for warning
 )
 
-// PrintGoNoGoReport prints results in NASA-style Go/No-Go format.
-// Returns true if all required checks pass (GO), false otherwise (NO-GO).
+// PrintGoNoGoReport prints results in NASA-style Go/No-Go format, or in
+// the labels and final message set by SetReportConfig. Returns true if all required checks pass (GO), false otherwise (NO-GO).
 func PrintGoNoGoReport(results []Result, verbose bool) bool {
-	fmt.Println()
-	fmt.Println("╔══════════════════════════════════════════════════════════════╗")
-	fmt.Println("║              RELEASE VALIDATION - GO/NO-GO                   ║")
-	fmt.Println("╠══════════════════════════════════════════════════════════════╣")
+	return writeGoNoGoReport(os.Stdout, results, verbose)
+}
 
+// The narrowest the Go/No-Go report box gets, and the columns before a
+// check's detail in verbose reports.
+const (
+	goNoGoMinWidth     = 64
+	goNoGoDetailIndent = 13 // "          └─ "
+)
+
+func writeGoNoGoReport(w io.Writer, results []Result, verbose bool) bool {
 	allGo := true
 	var statuses []ValidationStatus
+	var failed []string
+	labels := labelWidth(6)
+	nameIndent := 4 + labels + 1 // " 🟢 NO-GO  "
+	content := 0
 
 	for _, r := range results {
 		var status ValidationStatus
 		status.Name = r.Name
 
-		if r.Skipped {
-			status.Status = "SKIP"
-			status.Icon = IconSkipped
+		severity := r.Severity()
+		areaStatus := severity.Status()
+		status.Status = areaStatus.Label()
+		status.Icon = areaStatus.Icon()
+		switch severity {
+		case SeveritySkipped:
 			status.Detail = r.Reason
-		} else if r.Warning && !r.Passed {
-			status.Status = "WARN"
-			status.Icon = IconWarning
+		case SeverityWarning:
 			status.Detail = "Warning (non-blocking)"
-		} else if r.Passed {
-			status.Status = "GO"
-			status.Icon = IconGo
-		} else {
-			status.Status = "NO-GO"
-			status.Icon = IconNoGo
+		case SeverityFailed:
 			allGo = false
-			if r.Output != "" {
-				// Truncate long output for summary
-				lines := strings.Split(r.Output, "\n")
-				if len(lines) > 0 {
-					status.Detail = lines[0]
-					if len(status.Detail) > 40 {
-						status.Detail = status.Detail[:40] + "..."
-					}
-				}
-			}
+			failed = append(failed, r.Name)
+			// The first line of the output summarizes it
+			status.Detail, _, _ = strings.Cut(r.Output, "\n")
 		}
 
+		content = max(content, nameIndent+displayWidth(status.Name))
+		if verbose {
+			for _, l := range strings.Split(status.Detail, "\n") {
+				content = max(content, goNoGoDetailIndent+displayWidth(l))
+			}
+		}
 		statuses = append(statuses, status)
 	}
 
-	// Print each status
+	verdict := strings.Split(verdictMessage(allGo, "", failed), "\n")
+	for _, l := range verdict {
+		content = max(content, displayWidth(l)+1)
+	}
+
+	b := box{w: w, width: boxWidth(goNoGoMinWidth, content)}
+	fmt.Fprintln(w)
+	b.top()
+	b.center("RELEASE VALIDATION - GO/NO-GO")
+	b.divider()
+
+	// Format: Icon STATUS Name
 	for _, s := range statuses {
-		// Format: Icon STATUS Name
-		line := fmt.Sprintf("║ %s %-6s %-50s ║", s.Icon, s.Status, s.Name)
-		fmt.Println(line)
+		b.wrapped(" "+s.Icon+" "+padRight(s.Status, labels)+" ", nameIndent, s.Name)
 		if s.Detail != "" && verbose {
-			detailLine := fmt.Sprintf("║          └─ %-49s ║", s.Detail)
-			fmt.Println(detailLine)
+			b.wrapped("          └─ ", goNoGoDetailIndent, s.Detail)
 		}
 	}
 
-	fmt.Println("╠══════════════════════════════════════════════════════════════╣")
+	b.divider()
 
 	// Final verdict
-	if allGo {
-		fmt.Println("║                    🚀 ALL SYSTEMS GO 🚀                      ║")
-	} else {
-		fmt.Println("║                  🛑 NO-GO FOR RELEASE 🛑                     ║")
+	for _, l := range verdict {
+		b.center(l)
 	}
-	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+	b.bottom()
+	fmt.Fprintln(w)
 
 	return allGo
 }
@@ -224,12 +333,12 @@ func PrintCompactGoNoGo(results []Result) bool {
 	var goCount, noGoCount, warnCount, skipCount int
 
 	for _, r := range results {
-		switch {
-		case r.Skipped:
+		switch r.Severity() {
+		case SeveritySkipped:
 			skipCount++
-		case r.Warning && !r.Passed:
+		case SeverityWarning:
 			warnCount++
-		case r.Passed:
+		case SeverityPassed:
 			goCount++
 		default:
 			noGoCount++
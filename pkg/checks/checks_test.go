@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
 )
 
 func TestDefaultOptions(t *testing.T) {
@@ -139,3 +141,89 @@ func TestPrintResults_Warnings(t *testing.T) {
 		t.Errorf("expected 1 warning, got %d", warnings)
 	}
 }
+
+func TestPromoteWarnings(t *testing.T) {
+	results := []Result{
+		{Name: "build", Passed: true},
+		{Name: "coverage", Warning: true},
+		{Name: "roadmap", Warning: true, Skipped: true},
+	}
+
+	promoted := PromoteWarnings(results)
+	if promoted[1].Warning || promoted[1].Passed {
+		t.Errorf("expected warning to become a failure, got %+v", promoted[1])
+	}
+	if !promoted[2].Skipped {
+		t.Errorf("expected skipped result to stay skipped, got %+v", promoted[2])
+	}
+	if !results[1].Warning {
+		t.Error("expected PromoteWarnings not to modify its input")
+	}
+
+	report := &ValidationReport{Areas: []AreaResult{{Area: AreaQA, Status: StatusWarn, Results: results}}}
+	report.PromoteWarnings()
+	if report.Areas[0].Status != StatusNoGo || report.IsGo() {
+		t.Errorf("expected strict report to be NO-GO, got %s", report.Areas[0].Status)
+	}
+}
+
+func TestResult_Severity(t *testing.T) {
+	tests := []struct {
+		result Result
+		want   Severity
+		status AreaStatus
+	}{
+		{Result{Passed: true}, SeverityPassed, StatusGo},
+		{Result{Passed: true, Warning: true}, SeverityPassed, StatusGo},
+		{Result{Warning: true}, SeverityWarning, StatusWarn},
+		{Result{}, SeverityFailed, StatusNoGo},
+		{Result{Skipped: true, Warning: true}, SeveritySkipped, StatusSkip},
+	}
+	for _, tt := range tests {
+		got := tt.result.Severity()
+		if got != tt.want || got.Status() != tt.status {
+			t.Errorf("%+v: severity %s (%s), want %s (%s)", tt.result, got, got.Status(), tt.want, tt.status)
+		}
+	}
+}
+
+func TestAreaConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Validate.Areas = map[string]config.AreaConfig{
+		"pm": {Enabled: config.BoolPtr(false), Reason: "library without a roadmap"},
+		"documentation": {Skip: []config.CheckSkip{
+			{ID: "docs.prd", Reason: "no PRDs for libraries"},
+			{ID: "docs.trd"},
+		}},
+	}
+
+	if got := AreaSkipReason(AreaPM, cfg, "--skip-pm", false); got != "disabled by config (validate.areas.pm): library without a roadmap" {
+		t.Errorf("AreaSkipReason(pm) = %q", got)
+	}
+	if got := AreaSkipReason(AreaQA, cfg, "--skip-qa", true); got != "skipped by --skip-qa" {
+		t.Errorf("AreaSkipReason(qa, flag) = %q", got)
+	}
+	if got := AreaSkipReason(AreaDocumentation, cfg, "--skip-docs", false); got != "" {
+		t.Errorf("AreaSkipReason(documentation) = %q, want enabled", got)
+	}
+
+	if a := SkippedArea(AreaPM, "disabled"); a.Status != StatusSkip || len(a.Results) != 1 || a.Results[0].Reason != "disabled" {
+		t.Errorf("SkippedArea() = %+v", a)
+	}
+
+	results := []Result{
+		{Name: "Docs: PRD", Passed: false},
+		{Name: "Docs: TRD", Passed: false, Warning: true},
+		{Name: "Docs: README", Passed: true},
+	}
+	a := NewAreaResult(AreaDocumentation, results, cfg.Validate.Areas["documentation"])
+	if a.Status != StatusGo {
+		t.Errorf("NewAreaResult() status = %s, want GO with failures skipped", a.Status)
+	}
+	if r := a.Results[0]; !r.Skipped || r.Reason != "skipped by config (validate.areas.documentation.skip): no PRDs for libraries" {
+		t.Errorf("PRD result = %+v", r)
+	}
+	if r := a.Results[1]; !r.Skipped || r.Reason != "skipped by config (validate.areas.documentation.skip)" {
+		t.Errorf("TRD result = %+v", r)
+	}
+}
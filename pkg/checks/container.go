@@ -0,0 +1,154 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// Paths inside the container where the repository and cache are mounted.
+const (
+	containerWorkDir  = "/work"
+	containerCacheDir = "/cache"
+)
+
+// containerEnv points language caches and build output at the writable
+// cache mount, since the repository is mounted read-only.
+var containerEnv = []string{
+	"HOME=" + containerCacheDir + "/home",
+	"XDG_CACHE_HOME=" + containerCacheDir,
+	"GOCACHE=" + containerCacheDir + "/go-build",
+	"GOMODCACHE=" + containerCacheDir + "/go-mod",
+	"GOFLAGS=-mod=readonly",
+	"npm_config_cache=" + containerCacheDir + "/npm",
+	"CARGO_TARGET_DIR=" + containerCacheDir + "/cargo-target",
+	"PYTHONDONTWRITEBYTECODE=1",
+}
+
+// ContainerOptions configures running checks in containers.
+type ContainerOptions struct {
+	Runtime  string            // Container CLI, "docker" (default) or "podman"
+	Image    string            // Image for languages without their own
+	Images   map[string]string // Image per language, e.g. "go": "golang:1.23"
+	CacheDir string            // Host directory mounted writable at /cache; empty for a temporary one
+}
+
+// ImageFor returns the image to check lang in, or "" if none is configured.
+func (o ContainerOptions) ImageFor(lang string) string {
+	if image := o.Images[lang]; image != "" {
+		return image
+	}
+	return o.Image
+}
+
+func (o ContainerOptions) runtime() string {
+	if o.Runtime == "" {
+		return "docker"
+	}
+	return o.Runtime
+}
+
+// DefaultContainerCacheDir returns the user cache directory mounted into
+// check containers, or "" if it cannot be determined.
+func DefaultContainerCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "atrelease", "container")
+}
+
+// ContainerArgs returns the container CLI arguments that run `releasekit
+// validate` on dir in image. root is mounted read-only at /work and dir
+// must be inside it; cacheDir is mounted writable at /cache.
+func ContainerArgs(root, dir, image, cacheDir string, opts Options) ([]string, error) {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is not inside %s", dir, root)
+	}
+
+	args := []string{"run", "--rm",
+		"-v", root + ":" + containerWorkDir + ":ro",
+		"-v", cacheDir + ":" + containerCacheDir,
+		"-w", containerWorkDir,
+	}
+	// Run as the host user so cache files stay writable outside the container
+	if uid, gid := os.Getuid(), os.Getgid(); uid >= 0 {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", uid, gid))
+	}
+	for _, env := range slices.Concat(containerEnv, opts.Env) {
+		args = append(args, "-e", env)
+	}
+	args = append(args, image, "releasekit")
+	args = append(args, releasekitArgs(opts)...)
+	return append(args, path.Join(containerWorkDir, filepath.ToSlash(rel))), nil
+}
+
+// RunReleasekitInContainer runs `releasekit validate` on dir inside image,
+// with root mounted read-only. The image must provide releasekit and the
+// language toolchains. Detection paths reported inside the container are
+// mapped back to host paths, and results without one are attributed to
+// dir. If the command policy doesn't allow the container runtime, a single
+// skipped result is returned.
+func RunReleasekitInContainer(root, dir, image string, opts Options, copts ContainerOptions) ([]Result, error) {
+	name := "Container: " + image
+	if r, ok := disallowed(name, copts.runtime()); ok {
+		r.Path = dir
+		return []Result{r}, nil
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheDir := copts.CacheDir
+	if cacheDir == "" {
+		tmp, err := os.MkdirTemp("", "atrelease-container-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmp)
+		cacheDir = tmp
+	}
+	if err := os.MkdirAll(filepath.Join(cacheDir, "home"), 0755); err != nil {
+		return nil, err
+	}
+
+	args, err := ContainerArgs(absRoot, absDir, image, cacheDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	results, err := runReleasekitCommand(proc.Command(copts.runtime(), args...))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", image, err)
+	}
+
+	for i := range results {
+		results[i].Path = hostPath(results[i].Path, root, dir)
+	}
+	return results, nil
+}
+
+// hostPath maps a detection path reported inside the container to the
+// matching path under root, defaulting to dir.
+func hostPath(p, root, dir string) string {
+	switch {
+	case p == "":
+		return dir
+	case p == containerWorkDir:
+		return root
+	case strings.HasPrefix(p, containerWorkDir+"/"):
+		return filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(p, containerWorkDir+"/")))
+	}
+	return p
+}
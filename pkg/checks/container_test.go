@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestContainerOptions_ImageFor(t *testing.T) {
+	opts := ContainerOptions{Image: "ghcr.io/acme/ci:latest", Images: map[string]string{"go": "golang:1.23"}}
+	if got := opts.ImageFor("go"); got != "golang:1.23" {
+		t.Errorf("ImageFor(go) = %q", got)
+	}
+	if got := opts.ImageFor("rust"); got != "ghcr.io/acme/ci:latest" {
+		t.Errorf("ImageFor(rust) = %q", got)
+	}
+	if got := (ContainerOptions{}).ImageFor("go"); got != "" {
+		t.Errorf("ImageFor without images = %q, want empty", got)
+	}
+}
+
+func TestContainerArgs(t *testing.T) {
+	root := filepath.FromSlash("/src/repo")
+	args, err := ContainerArgs(root, filepath.Join(root, "svc", "api"), "golang:1.23", "/tmp/cache", Options{Test: true, Env: []string{"GOFLAGS=-short"}})
+	if err != nil {
+		t.Fatalf("ContainerArgs failed: %v", err)
+	}
+	joined := strings.Join(args, " ")
+	for _, want := range []string{
+		"run --rm -v " + root + ":/work:ro -v /tmp/cache:/cache -w /work",
+		"golang:1.23 releasekit validate --format json --no-lint /work/svc/api",
+		"-e GOCACHE=/cache/go-build",
+		"-e GOFLAGS=-short",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("ContainerArgs() = %q, missing %q", joined, want)
+		}
+	}
+	if !strings.HasSuffix(joined, "/work/svc/api") || slices.Contains(args, "--coverage") {
+		t.Errorf("unexpected arguments: %q", joined)
+	}
+
+	if _, err := ContainerArgs(root, filepath.FromSlash("/src/other"), "golang:1.23", "/tmp/cache", Options{}); err == nil {
+		t.Error("expected an error for a directory outside the root")
+	}
+}
+
+func TestHostPath(t *testing.T) {
+	tests := []struct{ path, want string }{
+		{"", "svc"},
+		{"/work", "."},
+		{"/work/svc/api", filepath.Join("svc", "api")},
+		{"web", "web"},
+	}
+	for _, tt := range tests {
+		if got := hostPath(tt.path, ".", "svc"); got != tt.want {
+			t.Errorf("hostPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRunReleasekitInContainer_Disallowed(t *testing.T) {
+	SetCommandPolicy(CommandPolicy{Deny: []string{"docker"}})
+	defer SetCommandPolicy(CommandPolicy{})
+
+	results, err := RunReleasekitInContainer(".", ".", "golang:1.23", DefaultOptions(), ContainerOptions{})
+	if err != nil || len(results) != 1 || !results[0].Skipped {
+		t.Errorf("expected a skipped result, got %+v (%v)", results, err)
+	}
+}
@@ -0,0 +1,154 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Controls evaluated by a policy report.
+const (
+	ControlChecks          = "checks"          // every check passed
+	ControlTests           = "tests"           // tests ran and passed
+	ControlVulnerabilities = "vulnerabilities" // the vulnerability scan is clean
+	ControlLicense         = "license"         // a LICENSE file is present
+)
+
+// DefaultControls are the controls required when the policy names none.
+var DefaultControls = []string{ControlChecks, ControlTests, ControlVulnerabilities, ControlLicense}
+
+// Control statuses.
+const (
+	ControlPassed       = "passed"
+	ControlFailed       = "failed"
+	ControlNotEvaluated = "not_evaluated"
+)
+
+// Control is the outcome of one compliance control.
+type Control struct {
+	ID          string   `json:"id" toon:"id"`
+	Description string   `json:"description" toon:"description"`
+	Required    bool     `json:"required" toon:"required"`
+	Status      string   `json:"status" toon:"status"` // "passed", "failed", or "not_evaluated"
+	Evidence    []string `json:"evidence,omitempty" toon:"evidence,omitempty"`
+}
+
+// controlDescriptions describes the known controls.
+var controlDescriptions = map[string]string{
+	ControlChecks:          "All checks passed",
+	ControlTests:           "Tests ran and passed",
+	ControlVulnerabilities: "Vulnerability scan found no known vulnerabilities",
+	ControlLicense:         "LICENSE file present",
+}
+
+// EvaluateControls evaluates every known control, and any unknown required
+// one, against the results of the checks and the security checks. required
+// names the controls that must pass; empty requires DefaultControls.
+func EvaluateControls(results, security []Result, required []string) []Control {
+	if len(required) == 0 {
+		required = DefaultControls
+	}
+
+	controls := []Control{
+		checksControl(results),
+		testsControl(results),
+		securityControl(ControlVulnerabilities, "Security: vulnerability scan", security),
+		securityControl(ControlLicense, "Security: LICENSE file", security),
+	}
+	for _, id := range required {
+		if _, ok := controlDescriptions[id]; !ok {
+			controls = append(controls, Control{
+				ID:          id,
+				Description: "Unknown control",
+				Status:      ControlNotEvaluated,
+			})
+		}
+	}
+	for i := range controls {
+		controls[i].Required = MatchID(controls[i].ID, required)
+	}
+	return controls
+}
+
+// ControlsPassed reports whether every required control passed.
+func ControlsPassed(controls []Control) bool {
+	for _, c := range controls {
+		if c.Required && c.Status != ControlPassed {
+			return false
+		}
+	}
+	return true
+}
+
+func checksControl(results []Result) Control {
+	c := Control{ID: ControlChecks, Description: controlDescriptions[ControlChecks], Status: ControlPassed}
+	for _, r := range results {
+		if r.Severity() == SeverityFailed {
+			c.Status = ControlFailed
+			c.Evidence = append(c.Evidence, "failed: "+resultLabel(r))
+		}
+	}
+	if c.Status == ControlPassed {
+		counts := make(map[Severity]int)
+		for _, r := range results {
+			counts[r.Severity()]++
+		}
+		c.Evidence = []string{FormatCounts(counts[SeverityPassed], 0, counts[SeveritySkipped], counts[SeverityWarning])}
+	}
+	return c
+}
+
+func testsControl(results []Result) Control {
+	c := Control{ID: ControlTests, Description: controlDescriptions[ControlTests]}
+	ran := 0
+	for _, r := range results {
+		if !strings.HasSuffix(ResultID(r), ".test") {
+			continue
+		}
+		switch r.Severity() {
+		case SeveritySkipped:
+			continue
+		case SeverityFailed:
+			c.Status = ControlFailed
+			c.Evidence = append(c.Evidence, "failed: "+resultLabel(r))
+		default:
+			c.Evidence = append(c.Evidence, "passed: "+resultLabel(r))
+		}
+		ran++
+	}
+	switch {
+	case ran == 0:
+		c.Status = ControlFailed
+		c.Evidence = []string{"no tests ran"}
+	case c.Status == "":
+		c.Status = ControlPassed
+	}
+	return c
+}
+
+func securityControl(id, name string, results []Result) Control {
+	c := Control{ID: id, Description: controlDescriptions[id], Status: ControlNotEvaluated}
+	for _, r := range results {
+		if r.Name != name {
+			continue
+		}
+		switch r.Severity() {
+		case SeveritySkipped:
+			c.Evidence = []string{r.Reason}
+		case SeverityFailed:
+			c.Status = ControlFailed
+			c.Evidence = []string{r.Output}
+		default:
+			c.Status = ControlPassed
+			c.Evidence = []string{r.Output}
+		}
+	}
+	return c
+}
+
+// resultLabel names a result with its path, e.g. "go.test [svc/api]".
+func resultLabel(r Result) string {
+	if r.Path == "" || r.Path == "." {
+		return ResultID(r)
+	}
+	return fmt.Sprintf("%s [%s]", ResultID(r), r.Path)
+}
@@ -0,0 +1,58 @@
+package checks
+
+import "testing"
+
+func TestEvaluateControls(t *testing.T) {
+	results := []Result{
+		{Name: "Go: build", Path: "svc", Passed: true},
+		{Name: "Go: tests", Path: "svc", Passed: true},
+		{Name: "Go: lint", Path: "svc", Passed: false},
+	}
+	security := []Result{
+		{Name: "Security: LICENSE file", Passed: true, Output: "LICENSE"},
+		{Name: "Security: vulnerability scan", Skipped: true, Reason: "govulncheck not installed"},
+	}
+
+	controls := EvaluateControls(results, security, []string{ControlTests, ControlLicense, "sbom"})
+	want := map[string]struct {
+		status   string
+		required bool
+	}{
+		ControlChecks:          {ControlFailed, false},
+		ControlTests:           {ControlPassed, true},
+		ControlVulnerabilities: {ControlNotEvaluated, false},
+		ControlLicense:         {ControlPassed, true},
+		"sbom":                 {ControlNotEvaluated, true},
+	}
+	if len(controls) != len(want) {
+		t.Fatalf("got %d controls, want %d: %+v", len(controls), len(want), controls)
+	}
+	for _, c := range controls {
+		w := want[c.ID]
+		if c.Status != w.status || c.Required != w.required {
+			t.Errorf("%s: status %s, required %v; want %s, %v", c.ID, c.Status, c.Required, w.status, w.required)
+		}
+	}
+	if ControlsPassed(controls) {
+		t.Error("ControlsPassed = true with an unknown required control")
+	}
+	if !ControlsPassed(controls[:4]) {
+		t.Error("ControlsPassed = false with only optional controls failing")
+	}
+}
+
+func TestEvaluateControls_NoTests(t *testing.T) {
+	results := []Result{
+		{Name: "Go: build", Passed: true},
+		{Name: "Go: tests", Skipped: true, Reason: "disabled"},
+	}
+	controls := EvaluateControls(results, nil, nil)
+	for _, c := range controls {
+		if !c.Required {
+			t.Errorf("%s not required by default", c.ID)
+		}
+		if c.ID == ControlTests && c.Status != ControlFailed {
+			t.Errorf("tests control %s with no tests run, want failed", c.Status)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// CoverageDiffOptions configures the coverage delta check.
+type CoverageDiffOptions struct {
+	MaxDrop  float64 // Allowed drop in percentage points per package
+	Fail     bool    // Fail instead of warn when a package drops more than MaxDrop
+	CacheDir string  // Directory for per-commit coverage results; empty disables caching
+}
+
+// CoverageDelta is the coverage change of a single package.
+type CoverageDelta struct {
+	Package string
+	Base    float64
+	Head    float64
+	New     bool // Package has no coverage on the base commit
+}
+
+// Drop returns the decrease in coverage, in percentage points.
+func (d CoverageDelta) Drop() float64 {
+	if d.New {
+		return 0
+	}
+	return d.Base - d.Head
+}
+
+var goCoverLinePattern = regexp.MustCompile(`^(?:ok\s+|\s*)(\S+)\s.*coverage: ([\d.]+)% of statements`)
+
+// ParseGoCoverOutput parses `go test -cover` output into coverage
+// percentages keyed by import path.
+func ParseGoCoverOutput(output string) map[string]float64 {
+	coverage := make(map[string]float64)
+	for _, line := range strings.Split(output, "\n") {
+		m := goCoverLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if pct, err := strconv.ParseFloat(m[2], 64); err == nil {
+			coverage[m[1]] = pct
+		}
+	}
+	return coverage
+}
+
+// GoCoverage runs `go test -cover` for the given package directories
+// (relative to dir) and returns coverage keyed by package directory.
+// Packages that fail to build or test are omitted.
+func GoCoverage(dir string, pkgs []string) (map[string]float64, error) {
+	var patterns []string
+	for _, pkg := range pkgs {
+		if FileExists(filepath.Join(dir, pkg)) {
+			patterns = append(patterns, "./"+filepath.ToSlash(pkg))
+		}
+	}
+	coverage := make(map[string]float64)
+	if len(patterns) == 0 {
+		return coverage, nil
+	}
+
+	// Map import paths back to package directories
+	list := proc.Command("go", append([]string{"list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}"}, patterns...)...)
+	list.Dir = dir
+	listOutput, err := list.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %w", err)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+	dirs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(listOutput)), "\n") {
+		importPath, pkgDir, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		if rel, err := filepath.Rel(absDir, pkgDir); err == nil {
+			dirs[importPath] = filepath.ToSlash(rel)
+		}
+	}
+
+	cmd := proc.Command("go", append([]string{"test", "-cover"}, patterns...)...)
+	cmd.Dir = dir
+	// Test failures are reported by the test check; keep what coverage we got
+	output, err := runOutput(cmd, "go test -cover", true)
+	byImport := ParseGoCoverOutput(string(output))
+	if len(byImport) == 0 && err != nil {
+		return nil, fmt.Errorf("go test -cover failed: %s", strings.TrimSpace(string(output)))
+	}
+	for importPath, pct := range byImport {
+		if d, ok := dirs[importPath]; ok {
+			coverage[d] = pct
+		}
+	}
+	return coverage, nil
+}
+
+// CompareCoverage returns the coverage change for each package covered on
+// head, sorted by package.
+func CompareCoverage(base, head map[string]float64) []CoverageDelta {
+	deltas := make([]CoverageDelta, 0, len(head))
+	for pkg, h := range head {
+		b, ok := base[pkg]
+		deltas = append(deltas, CoverageDelta{Package: pkg, Base: b, Head: h, New: !ok})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Package < deltas[j].Package
+	})
+	return deltas
+}
+
+// CheckCoverageDiff compares coverage of the changed Go packages between
+// the merge base and HEAD. Coverage for each commit is cached in
+// opts.CacheDir; HEAD results are only cached for a clean working tree.
+func CheckCoverageDiff(dir, base string, pkgs []string, opts CoverageDiffOptions) Result {
+	name := "Go: coverage delta"
+
+	if len(pkgs) == 0 {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No changed Go packages",
+		}
+	}
+	if r, ok := disallowed(name, "go", "git"); ok {
+		return r
+	}
+
+	g := git.New(dir)
+
+	headSHA, err := g.CurrentCommit()
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	if dirty, _ := g.IsDirty(); dirty {
+		headSHA = ""
+	}
+	head, err := cachedCoverage(opts.CacheDir, headSHA, pkgs, func(missing []string) (map[string]float64, error) {
+		return GoCoverage(dir, missing)
+	})
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+
+	baseSHA, err := g.ResolveCommit(base)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	baseCov, err := cachedCoverage(opts.CacheDir, baseSHA, pkgs, func(missing []string) (map[string]float64, error) {
+		var coverage map[string]float64
+		err := inWorktree(dir, baseSHA, func(wt string) error {
+			var err error
+			coverage, err = GoCoverage(wt, missing)
+			return err
+		})
+		return coverage, err
+	})
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+
+	var lines, dropped []string
+	for _, d := range CompareCoverage(baseCov, head) {
+		if d.New {
+			lines = append(lines, fmt.Sprintf("%s: %.1f%% (new)", d.Package, d.Head))
+			continue
+		}
+		line := fmt.Sprintf("%s: %.1f%% -> %.1f%% (%+.1f)", d.Package, d.Base, d.Head, d.Head-d.Base)
+		lines = append(lines, line)
+		if d.Drop() > opts.MaxDrop {
+			dropped = append(dropped, line)
+		}
+	}
+
+	if len(dropped) > 0 {
+		return Result{
+			Name:    name,
+			Warning: !opts.Fail,
+			Passed:  false,
+			Output: fmt.Sprintf("Coverage dropped more than %.1f points:\n%s",
+				opts.MaxDrop, strings.Join(dropped, "\n")),
+			Remediation: "add tests for the new code in the packages listed",
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: strings.Join(lines, "\n"),
+	}
+}
+
+// cachedCoverage returns coverage for pkgs at commit sha, computing and
+// caching only the packages not already cached. An empty sha or cacheDir
+// disables the cache.
+func cachedCoverage(cacheDir, sha string, pkgs []string, compute func([]string) (map[string]float64, error)) (map[string]float64, error) {
+	if cacheDir == "" || sha == "" {
+		return compute(pkgs)
+	}
+
+	path := filepath.Join(cacheDir, sha+".json")
+	entry := coverageCacheEntry{Coverage: map[string]float64{}}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &entry)
+	}
+	if entry.Coverage == nil {
+		entry.Coverage = map[string]float64{}
+	}
+
+	done := make(map[string]bool, len(entry.Packages))
+	for _, p := range entry.Packages {
+		done[p] = true
+	}
+	var missing []string
+	for _, p := range pkgs {
+		if !done[p] {
+			missing = append(missing, p)
+		}
+	}
+
+	if len(missing) > 0 {
+		coverage, err := compute(missing)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range coverage {
+			entry.Coverage[k] = v
+		}
+		entry.Packages = append(entry.Packages, missing...)
+		if err := os.MkdirAll(cacheDir, 0755); err == nil {
+			if data, err := json.Marshal(entry); err == nil {
+				_ = os.WriteFile(path, data, 0600)
+			}
+		}
+	}
+
+	result := make(map[string]float64)
+	for _, p := range pkgs {
+		if pct, ok := entry.Coverage[filepath.ToSlash(p)]; ok {
+			result[filepath.ToSlash(p)] = pct
+		}
+	}
+	return result, nil
+}
+
+// inWorktree checks out ref into a temporary git worktree of the repository
+// at dir and calls fn with its path, removing the worktree afterwards.
+func inWorktree(dir, ref string, fn func(wt string) error) error {
+	tmp, err := os.MkdirTemp("", "atrelease-worktree-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	// git worktree add requires a path that doesn't exist yet
+	wt := filepath.Join(tmp, "tree")
+	g := git.New(dir)
+	if err := g.AddWorktree(wt, ref); err != nil {
+		return err
+	}
+	defer func() { _ = g.RemoveWorktree(wt) }()
+
+	return fn(wt)
+}
+
+// coverageCacheEntry is the on-disk cache for one commit.
+type coverageCacheEntry struct {
+	Packages []string           `json:"packages"` // Package directories already measured
+	Coverage map[string]float64 `json:"coverage"` // Coverage keyed by package directory
+}
+
+// DefaultCoverageCacheDir returns the user cache directory for coverage
+// results, or "" if it cannot be determined.
+func DefaultCoverageCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "atrelease", "coverage")
+}
@@ -0,0 +1,112 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseGoCoverOutput(t *testing.T) {
+	output := "ok  \texample.com/m/pkg/a\t0.012s\tcoverage: 75.0% of statements\n" +
+		"ok  \texample.com/m/pkg/b\t(cached)\tcoverage: 50.5% of statements\n" +
+		"\texample.com/m/pkg/c\t\tcoverage: 0.0% of statements\n" +
+		"?   \texample.com/m/pkg/d\t[no test files]\n" +
+		"FAIL\texample.com/m/pkg/e\t0.010s\n"
+
+	got := ParseGoCoverOutput(output)
+	want := map[string]float64{
+		"example.com/m/pkg/a": 75.0,
+		"example.com/m/pkg/b": 50.5,
+		"example.com/m/pkg/c": 0.0,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGoCoverOutput() = %v, want %v", got, want)
+	}
+}
+
+func TestCompareCoverage(t *testing.T) {
+	base := map[string]float64{"pkg/a": 80, "pkg/b": 50}
+	head := map[string]float64{"pkg/a": 70, "pkg/b": 55, "pkg/c": 10}
+
+	deltas := CompareCoverage(base, head)
+	if len(deltas) != 3 {
+		t.Fatalf("expected 3 deltas, got %d", len(deltas))
+	}
+	if deltas[0].Package != "pkg/a" || deltas[0].Drop() != 10 {
+		t.Errorf("expected pkg/a to drop 10, got %+v", deltas[0])
+	}
+	if deltas[1].Drop() != -5 {
+		t.Errorf("expected pkg/b to rise 5, got %+v", deltas[1])
+	}
+	if !deltas[2].New || deltas[2].Drop() != 0 {
+		t.Errorf("expected pkg/c to be new, got %+v", deltas[2])
+	}
+}
+
+func TestCachedCoverage(t *testing.T) {
+	cacheDir := t.TempDir()
+	var computed [][]string
+	compute := func(pkgs []string) (map[string]float64, error) {
+		computed = append(computed, pkgs)
+		cov := make(map[string]float64)
+		for _, p := range pkgs {
+			if p != "pkg/untested" {
+				cov[p] = 42
+			}
+		}
+		return cov, nil
+	}
+
+	got, err := cachedCoverage(cacheDir, "abc123", []string{"pkg/a", "pkg/untested"}, compute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, map[string]float64{"pkg/a": 42}) {
+		t.Errorf("unexpected coverage %v", got)
+	}
+	if !FileExists(filepath.Join(cacheDir, "abc123.json")) {
+		t.Error("expected cache file to be written")
+	}
+
+	// Second call computes only the uncached package
+	got, err = cachedCoverage(cacheDir, "abc123", []string{"pkg/a", "pkg/b"}, compute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(computed) != 2 || !reflect.DeepEqual(computed[1], []string{"pkg/b"}) {
+		t.Errorf("expected only pkg/b to be computed, got %v", computed)
+	}
+	if !reflect.DeepEqual(got, map[string]float64{"pkg/a": 42, "pkg/b": 42}) {
+		t.Errorf("unexpected coverage %v", got)
+	}
+}
+
+func TestGoCoverage(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":            "module example.com/m\n\ngo 1.21\n",
+		"calc/calc.go":      "package calc\n\nfunc Add(a, b int) int { return a + b }\n\nfunc Sub(a, b int) int { return a - b }\n",
+		"calc/calc_test.go": "package calc\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fatal(\"bad\")\n\t}\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := GoCoverage(tmpDir, []string{"calc", "missing"})
+	if err != nil {
+		t.Fatalf("GoCoverage: %v", err)
+	}
+	if got["calc"] != 50 {
+		t.Errorf("expected calc coverage 50%%, got %v", got)
+	}
+}
@@ -0,0 +1,243 @@
+package checks
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// isDeprecated reports whether a doc comment has a paragraph starting with
+// "Deprecated:", the convention go doc and linters recognize.
+func isDeprecated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, p := range strings.Split(doc.Text(), "\n\n") {
+		if strings.HasPrefix(strings.TrimSpace(p), "Deprecated:") {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecatedIdentifiers returns the exported identifiers that the Go source
+// src marks deprecated, qualified by package: "pkg.Func", "pkg.Type",
+// "pkg.Type.Method", or "pkg.Type.Field". A deprecated const, var, or type
+// group deprecates every identifier in it. Main and generated files have
+// none, since they are not public API.
+func deprecatedIdentifiers(filename string, src []byte) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if file.Name.Name == "main" || ast.IsGenerated(file) {
+		return nil, nil
+	}
+
+	var ids []string
+	add := func(name string) {
+		ids = append(ids, file.Name.Name+"."+name)
+	}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() || !isDeprecated(d.Doc) {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				add(d.Name.Name)
+			} else if recv := receiverType(d.Recv.List[0].Type); ast.IsExported(recv) {
+				add(recv + "." + d.Name.Name)
+			}
+		case *ast.GenDecl:
+			group := isDeprecated(d.Doc)
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if !s.Name.IsExported() {
+						continue
+					}
+					if group || isDeprecated(s.Doc) {
+						add(s.Name.Name)
+					}
+					for _, f := range typeFields(s.Type) {
+						if isDeprecated(f.Doc) {
+							for _, n := range f.Names {
+								if n.IsExported() {
+									add(s.Name.Name + "." + n.Name)
+								}
+							}
+						}
+					}
+				case *ast.ValueSpec:
+					if !group && !isDeprecated(s.Doc) {
+						continue
+					}
+					for _, n := range s.Names {
+						if n.IsExported() {
+							add(n.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+// typeFields returns the fields of a struct type or methods of an
+// interface type.
+func typeFields(expr ast.Expr) []*ast.Field {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return t.Fields.List
+	case *ast.InterfaceType:
+		return t.Methods.List
+	}
+	return nil
+}
+
+// publicGoFile reports whether path, relative to a module, is a Go file
+// that may declare public API: not a test, and not under internal/,
+// testdata/, vendor/, or a directory starting with "." or "_".
+func publicGoFile(path string) bool {
+	path = filepath.ToSlash(path)
+	if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+		return false
+	}
+	dirs := strings.Split(path, "/")
+	for _, d := range dirs[:len(dirs)-1] {
+		if strings.HasPrefix(d, ".") || strings.HasPrefix(d, "_") ||
+			d == "internal" || d == "testdata" || d == "vendor" || d == "node_modules" {
+			return false
+		}
+	}
+	return true
+}
+
+// deprecationsSince returns the Go identifiers under dir deprecated since
+// the latest tag, with the tag. Tests replace it.
+var deprecationsSince = func(dir string) ([]string, string, error) {
+	g := git.New(dir)
+	tag, err := g.LatestTag()
+	if err != nil {
+		return nil, "", err
+	}
+	top, err := g.TopLevel()
+	if err != nil {
+		return nil, "", err
+	}
+	files, err := g.ChangedFiles(tag)
+	if err != nil {
+		return nil, "", err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+
+	var added []string
+	for _, f := range files {
+		rel, err := filepath.Rel(abs, filepath.Join(top, f))
+		if err != nil || strings.HasPrefix(rel, "..") || !publicGoFile(rel) {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(top, f))
+		if err != nil {
+			continue
+		}
+		now, err := deprecatedIdentifiers(f, src)
+		if err != nil || len(now) == 0 {
+			continue
+		}
+		// A file that didn't exist at the tag deprecates nothing there
+		var before []string
+		if old, err := g.ShowFile(tag, f); err == nil {
+			before, _ = deprecatedIdentifiers(f, old)
+		}
+		for _, id := range now {
+			if !slices.Contains(before, id) && !slices.Contains(added, id) {
+				added = append(added, id)
+			}
+		}
+	}
+	return added, tag, nil
+}
+
+// codeIdentifier matches a code span naming an exported Go identifier,
+// such as `Func`, `pkg.Type.Method`, or `Func()`.
+var codeIdentifier = regexp.MustCompile("`((?:[A-Za-z_]\\w*\\.)*[A-Z]\\w*)(?:\\(\\))?`")
+
+// lastComponent returns the final identifier of a dotted name.
+func lastComponent(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// mentions reports whether text names the identifier id, by its final
+// component, e.g. "Method" for "pkg.Type.Method".
+func mentions(text, id string) bool {
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(lastComponent(id)) + `\b`).MatchString(text)
+}
+
+// compareDeprecations cross-references a release's deprecation entries
+// with the identifiers deprecated in code since the last release. It
+// returns the identifiers no entry mentions, and the Go identifiers that
+// entries name in code spans without a matching deprecation in code.
+// Entries naming no Go identifier, e.g. a CLI flag, can't be checked.
+func compareDeprecations(entries []changelog.Entry, deprecated []string) (undocumented, unmatched []string) {
+	for _, id := range deprecated {
+		if !slices.ContainsFunc(entries, func(e changelog.Entry) bool { return mentions(e.Description, id) }) {
+			undocumented = append(undocumented, id)
+		}
+	}
+	for _, e := range entries {
+		if slices.ContainsFunc(deprecated, func(id string) bool { return mentions(e.Description, id) }) {
+			continue
+		}
+		for _, m := range codeIdentifier.FindAllStringSubmatch(e.Description, -1) {
+			if !slices.Contains(unmatched, m[1]) {
+				unmatched = append(unmatched, m[1])
+			}
+		}
+	}
+	return undocumented, unmatched
+}
+
+// checkDeprecationsAgainstCode compares the release's deprecation entries
+// with the Go identifiers deprecated since the latest tag. It returns a
+// summary and whether it found discrepancies, or "" if there is no tag to
+// compare with.
+func checkDeprecationsAgainstCode(dir string, entries []changelog.Entry) (string, bool) {
+	deprecated, tag, err := deprecationsSince(dir)
+	if err != nil {
+		return "", false
+	}
+
+	undocumented, unmatched := compareDeprecations(entries, deprecated)
+	if len(undocumented) == 0 && len(unmatched) == 0 {
+		if len(deprecated) == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("matching the %d identifier(s) deprecated since %s", len(deprecated), tag), false
+	}
+	var parts []string
+	if len(undocumented) > 0 {
+		parts = append(parts, fmt.Sprintf("deprecated since %s but not in the changelog: %s", tag, strings.Join(undocumented, ", ")))
+	}
+	if len(unmatched) > 0 {
+		parts = append(parts, fmt.Sprintf("no // Deprecated: comment added since %s for %s", tag, strings.Join(unmatched, ", ")))
+	}
+	return strings.Join(parts, "; "), true
+}
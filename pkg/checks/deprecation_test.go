@@ -0,0 +1,122 @@
+package checks
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+)
+
+func TestDeprecatedIdentifiers(t *testing.T) {
+	src := `package api
+
+// Old does it the old way.
+//
+// Deprecated: Use New.
+func Old() {}
+
+// New does it.
+func New() {}
+
+// Client talks to the server.
+type Client struct {
+	// Timeout is ignored.
+	//
+	// Deprecated: Use Options.Timeout.
+	Timeout int
+	Retries int
+}
+
+// Fetch fetches.
+//
+// Deprecated: Use Get.
+func (c *Client) Fetch() {}
+
+// Deprecated: These modes are no longer supported.
+const (
+	ModeA = iota
+	ModeB
+)
+
+// Deprecated: Unexported variables aren't API.
+var legacy = 1
+
+// Deprecated: Unexported methods aren't API.
+func (c *Client) retry() {}
+`
+	got, err := deprecatedIdentifiers("api.go", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"api.Old", "api.Client.Timeout", "api.Client.Fetch", "api.ModeA", "api.ModeB"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deprecatedIdentifiers() = %q, want %q", got, want)
+	}
+
+	got, err = deprecatedIdentifiers("main.go", []byte("package main\n\n// Deprecated: x\nfunc Old() {}\n"))
+	if err != nil || got != nil {
+		t.Errorf("main package: %q, %v", got, err)
+	}
+}
+
+func TestPublicGoFile(t *testing.T) {
+	tests := map[string]bool{
+		"api.go":                true,
+		"pkg/checks/pm.go":      true,
+		"pkg/checks/pm_test.go": false,
+		"internal/x/x.go":       false,
+		"testdata/a.go":         false,
+		".github/a.go":          false,
+		"README.md":             false,
+	}
+	for path, want := range tests {
+		if got := publicGoFile(path); got != want {
+			t.Errorf("publicGoFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestCompareDeprecations(t *testing.T) {
+	entries := []changelog.Entry{
+		{Description: "`api.Old` in favor of `api.New`"},
+		{Description: "`Client.Retry()`; retries are automatic"},
+		{Description: "The `--legacy` flag"},
+	}
+	undocumented, unmatched := compareDeprecations(entries, []string{"api.Old", "api.Client.Fetch"})
+	if want := []string{"api.Client.Fetch"}; !reflect.DeepEqual(undocumented, want) {
+		t.Errorf("undocumented = %q, want %q", undocumented, want)
+	}
+	if want := []string{"Client.Retry"}; !reflect.DeepEqual(unmatched, want) {
+		t.Errorf("unmatched = %q, want %q", unmatched, want)
+	}
+}
+
+func TestPMChecker_DeprecationNotices(t *testing.T) {
+	orig := deprecationsSince
+	t.Cleanup(func() { deprecationsSince = orig })
+	deprecationsSince = func(string) ([]string, string, error) {
+		return []string{"api.Old"}, "v1.0.0", nil
+	}
+
+	c := &PMChecker{}
+	r := c.checkDeprecationNotices(t.TempDir(), loadedChangelog(changelog.Release{
+		Version:    "v1.1.0",
+		Deprecated: []changelog.Entry{{Description: "`Old`, use `New`"}},
+	}), "v1.1.0")
+	if !r.Passed || r.Output != "1 deprecation notices, matching the 1 identifier(s) deprecated since v1.0.0" {
+		t.Errorf("documented: %+v", r)
+	}
+
+	r = c.checkDeprecationNotices(t.TempDir(), loadedChangelog(changelog.Release{Version: "v1.1.0"}), "v1.1.0")
+	if r.Passed || !r.Warning || !strings.Contains(r.Reason, "not in the changelog: api.Old") {
+		t.Errorf("undocumented: %+v", r)
+	}
+
+	deprecationsSince = func(string) ([]string, string, error) { return nil, "", errors.New("no tags") }
+	r = c.checkDeprecationNotices(t.TempDir(), loadedChangelog(changelog.Release{Version: "v1.1.0"}), "v1.1.0")
+	if !r.Passed || r.Output != "No deprecations" {
+		t.Errorf("no tag: %+v", r)
+	}
+}
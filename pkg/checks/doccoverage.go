@@ -0,0 +1,208 @@
+package checks
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// maxUndocumentedShown limits the undocumented identifiers listed in output.
+const maxUndocumentedShown = 10
+
+// DocCoverage is the doc comment coverage of a Go module's exported API.
+type DocCoverage struct {
+	Total        int      // Exported identifiers
+	Documented   int      // Exported identifiers with a doc comment
+	Undocumented []string // "path/file.go:12: func Name", in file order
+}
+
+// Percent returns the documented share of exported identifiers, or 100 if
+// there are none.
+func (d DocCoverage) Percent() float64 {
+	if d.Total == 0 {
+		return 100
+	}
+	return float64(d.Documented) / float64(d.Total) * 100
+}
+
+// GoDocCoverage scans the Go packages under dir for exported identifiers
+// without doc comments: top-level funcs, types, consts, and vars, and
+// methods on exported types. A doc comment on a const, var, or type group
+// documents every identifier in it, and godoc lists undocumented constants
+// of a documented type under that type, so they count as documented. Test
+// files, generated files, main packages, and internal/, testdata/, and
+// vendor/ directories are skipped, since none of them are public API.
+func GoDocCoverage(dir string) (DocCoverage, error) {
+	var cov DocCoverage
+	var pending []exportedDecl
+	documentedTypes := make(map[string]bool) // "pkgdir.Type"
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "internal" || name == "testdata" || name == "vendor" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		if file.Name.Name == "main" || ast.IsGenerated(file) {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		pkgDir := filepath.Dir(rel)
+		for _, decl := range file.Decls {
+			for _, id := range exportedDecls(decl) {
+				id.where = fmt.Sprintf("%s:%d", filepath.ToSlash(rel), fset.Position(id.pos).Line)
+				if id.typeName != "" {
+					id.typeName = pkgDir + "." + id.typeName
+				}
+				if strings.HasPrefix(id.name, "type ") && id.documented {
+					documentedTypes[pkgDir+"."+strings.TrimPrefix(id.name, "type ")] = true
+				}
+				pending = append(pending, id)
+			}
+		}
+		return nil
+	})
+
+	for _, id := range pending {
+		cov.Total++
+		if id.documented || (id.typeName != "" && documentedTypes[id.typeName]) {
+			cov.Documented++
+			continue
+		}
+		cov.Undocumented = append(cov.Undocumented, id.where+": "+id.name)
+	}
+	return cov, err
+}
+
+type exportedDecl struct {
+	name       string
+	pos        token.Pos
+	where      string // "path/file.go:12"
+	typeName   string // declared type of a constant, if a local named type
+	documented bool
+}
+
+// exportedDecls returns the exported identifiers declared by decl.
+func exportedDecls(decl ast.Decl) []exportedDecl {
+	var ids []exportedDecl
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		name := "func " + d.Name.Name
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			recv := receiverType(d.Recv.List[0].Type)
+			if !ast.IsExported(recv) {
+				return nil
+			}
+			name = fmt.Sprintf("method %s.%s", recv, d.Name.Name)
+		}
+		ids = append(ids, exportedDecl{name: name, pos: d.Pos(), documented: d.Doc != nil})
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return nil
+		}
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					ids = append(ids, exportedDecl{name: "type " + s.Name.Name, pos: s.Pos(), documented: d.Doc != nil || s.Doc != nil})
+				}
+			case *ast.ValueSpec:
+				var typeName string
+				if ident, ok := s.Type.(*ast.Ident); ok && d.Tok == token.CONST {
+					typeName = ident.Name
+				}
+				for _, n := range s.Names {
+					if n.IsExported() {
+						ids = append(ids, exportedDecl{
+							name:       d.Tok.String() + " " + n.Name,
+							pos:        n.Pos(),
+							typeName:   typeName,
+							documented: d.Doc != nil || s.Doc != nil || s.Comment != nil,
+						})
+					}
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// receiverType returns the type name of a method receiver such as *T or
+// T[K, V].
+func receiverType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverType(t.X)
+	case *ast.IndexExpr:
+		return receiverType(t.X)
+	case *ast.IndexListExpr:
+		return receiverType(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// checkGoDocCoverage reports the doc comment coverage of exported Go
+// identifiers. It fails only when minimum is set and coverage is below it.
+func (c *DocChecker) checkGoDocCoverage(dir string, minimum float64) Result {
+	name := "Docs: Go API doc coverage"
+
+	if !FileExists(filepath.Join(dir, "go.mod")) {
+		return Result{Name: name, Skipped: true, Reason: "No go.mod found"}
+	}
+
+	cov, err := GoDocCoverage(dir)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	if cov.Total == 0 {
+		return Result{Name: name, Skipped: true, Reason: "No exported Go identifiers"}
+	}
+
+	summary := fmt.Sprintf("%.1f%% documented (%d/%d exported identifiers)", cov.Percent(), cov.Documented, cov.Total)
+	if minimum <= 0 || cov.Percent() >= minimum {
+		if minimum > 0 {
+			summary += fmt.Sprintf(", minimum %.1f%%", minimum)
+		}
+		return Result{Name: name, Passed: true, Output: summary}
+	}
+
+	lines := []string{summary + fmt.Sprintf(", below minimum %.1f%%. Undocumented:", minimum)}
+	for i, id := range cov.Undocumented {
+		if i == maxUndocumentedShown {
+			lines = append(lines, fmt.Sprintf("  ... (%d more)", len(cov.Undocumented)-i))
+			break
+		}
+		lines = append(lines, "  "+id)
+	}
+	return Result{
+		Name:        name,
+		Passed:      false,
+		Output:      strings.Join(lines, "\n"),
+		Remediation: "add doc comments to the undocumented identifiers",
+	}
+}
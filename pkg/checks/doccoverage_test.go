@@ -0,0 +1,88 @@
+package checks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const docCoverageSource = `package demo
+
+// Documented is documented.
+func Documented() {}
+
+func Undocumented() {}
+
+func unexported() {}
+
+// Kind is a documented type.
+type Kind string
+
+// Kinds of things; documented by the type.
+const (
+	KindA Kind = "a"
+	KindB Kind = "b"
+)
+
+const (
+	Loose = 1
+	Inline = 2 // Inline has a trailing comment.
+)
+
+type Bare struct{}
+
+func (Bare) Method() {}
+
+func (b *Bare) unexported() {}
+
+// Grouped vars share the group comment.
+var (
+	VarA, VarB = 1, 2
+)
+`
+
+func TestGoDocCoverage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "demo.go"), docCoverageSource)
+	writeTestFile(t, filepath.Join(dir, "demo_test.go"), "package demo\n\nfunc Helper() {}\n")
+	writeTestFile(t, filepath.Join(dir, "gen.go"), "// Code generated by tool. DO NOT EDIT.\n\npackage demo\n\nfunc Generated() {}\n")
+	writeTestFile(t, filepath.Join(dir, "internal", "x", "x.go"), "package x\n\nfunc Hidden() {}\n")
+	writeTestFile(t, filepath.Join(dir, "cmd", "tool", "main.go"), "package main\n\nfunc Run() {}\n")
+
+	cov, err := GoDocCoverage(dir)
+	if err != nil {
+		t.Fatalf("GoDocCoverage() error: %v", err)
+	}
+
+	want := []string{
+		"demo.go:6: func Undocumented",
+		"demo.go:20: const Loose",
+		"demo.go:24: type Bare",
+		"demo.go:26: method Bare.Method",
+	}
+	if strings.Join(cov.Undocumented, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Undocumented =\n%s\nwant\n%s", strings.Join(cov.Undocumented, "\n"), strings.Join(want, "\n"))
+	}
+	// Documented, Kind, KindA, KindB, Inline, VarA, VarB
+	if cov.Total != 11 || cov.Documented != 7 {
+		t.Errorf("Total = %d, Documented = %d, want 11 and 7", cov.Total, cov.Documented)
+	}
+}
+
+func TestCheckGoDocCoverage(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/demo\n")
+	writeTestFile(t, filepath.Join(dir, "demo.go"), docCoverageSource)
+	c := &DocChecker{}
+
+	if r := c.checkGoDocCoverage(dir, 0); !r.Passed || !strings.HasPrefix(r.Output, "63.6% documented") {
+		t.Errorf("no minimum: got %+v, want pass with coverage", r)
+	}
+	if r := c.checkGoDocCoverage(dir, 60); !r.Passed {
+		t.Errorf("minimum 60: got %+v, want pass", r)
+	}
+	r := c.checkGoDocCoverage(dir, 80)
+	if r.Passed || !strings.Contains(r.Output, "below minimum 80.0%") || !strings.Contains(r.Output, "func Undocumented") {
+		t.Errorf("minimum 80: got %+v, want failure listing identifiers", r)
+	}
+}
@@ -0,0 +1,255 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// Documentation site generators detected by DetectDocSite.
+const (
+	DocSiteMkDocs     = "mkdocs"
+	DocSiteDocusaurus = "docusaurus"
+)
+
+// DocSite is a documentation site found in a project.
+type DocSite struct {
+	Kind   string // DocSiteMkDocs or DocSiteDocusaurus
+	Dir    string // Directory containing the site configuration
+	Config string // Path to mkdocs.yml or docusaurus.config.*
+}
+
+var (
+	docusaurusConfigs = []string{"docusaurus.config.js", "docusaurus.config.ts", "docusaurus.config.mjs", "docusaurus.config.cjs"}
+
+	// mike (https://github.com/jimporter/mike) is the usual MkDocs versioning tool
+	mkdocsMikeProvider = regexp.MustCompile(`(?m)^\s*provider:\s*["']?mike["']?\s*$`)
+)
+
+// DetectDocSite returns the MkDocs or Docusaurus site in dir, looking in dir
+// itself and in docs/ and website/. It returns nil if the project has no
+// docs/ directory or no site configuration.
+func DetectDocSite(dir string) *DocSite {
+	if !FileExists(filepath.Join(dir, "docs")) {
+		return nil
+	}
+	for _, sub := range []string{".", "docs", "website"} {
+		siteDir := filepath.Join(dir, sub)
+		for _, name := range []string{"mkdocs.yml", "mkdocs.yaml"} {
+			if path := filepath.Join(siteDir, name); FileExists(path) {
+				return &DocSite{Kind: DocSiteMkDocs, Dir: siteDir, Config: path}
+			}
+		}
+		for _, name := range docusaurusConfigs {
+			if path := filepath.Join(siteDir, name); FileExists(path) {
+				return &DocSite{Kind: DocSiteDocusaurus, Dir: siteDir, Config: path}
+			}
+		}
+	}
+	return nil
+}
+
+// checkDocsBuild builds the documentation site into a temporary directory:
+// `mkdocs build --strict` for MkDocs, `docusaurus build` for Docusaurus.
+func (c *DocChecker) checkDocsBuild(dir string) Result {
+	name := "Docs: site build"
+
+	site := DetectDocSite(dir)
+	if site == nil {
+		return Result{Name: name, Skipped: true, Reason: "No MkDocs or Docusaurus site found"}
+	}
+
+	outDir, err := os.MkdirTemp("", "atrelease-docs-")
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	defer os.RemoveAll(outDir)
+
+	var result Result
+	switch site.Kind {
+	case DocSiteMkDocs:
+		if r, ok := disallowed(name, "mkdocs"); ok {
+			return r
+		}
+		if !CommandExists("mkdocs") {
+			return Result{Name: name, Skipped: true, Reason: "mkdocs not installed (pip install mkdocs)"}
+		}
+		result = RunCommand(name, site.Dir, "mkdocs", "build", "--strict",
+			"--config-file", site.Config, "--site-dir", outDir)
+	case DocSiteDocusaurus:
+		bin := filepath.Join(site.Dir, "node_modules", ".bin", "docusaurus")
+		if !FileExists(bin) {
+			return Result{Name: name, Skipped: true, Reason: "Docusaurus not installed (run npm install in " + relPath(dir, site.Dir) + ")"}
+		}
+		result = RunCommand(name, site.Dir, bin, "build", "--out-dir", outDir)
+	}
+
+	if result.Skipped {
+		return result
+	}
+	if !result.Passed {
+		result.Output = lastLines(result.Output, 20)
+		return result
+	}
+	return Result{Name: name, Passed: true, Output: fmt.Sprintf("%s site builds (%s)", site.Kind, relPath(dir, site.Config))}
+}
+
+// checkDocsVersion verifies that versioned docs include the target release.
+// Docusaurus sites are versioned when versions.json exists; the version must
+// be listed there and have a versioned_docs/version-<v> directory. MkDocs
+// sites are versioned when they use mike; the version must be listed in
+// versions.json on the gh-pages branch. Versions match with or without the
+// "v" prefix, and at minor granularity ("1.2" for v1.2.3).
+func (c *DocChecker) checkDocsVersion(dir, version string) Result {
+	name := "Docs: versioned docs"
+
+	site := DetectDocSite(dir)
+	if site == nil {
+		return Result{Name: name, Skipped: true, Reason: "No MkDocs or Docusaurus site found"}
+	}
+	if version == "" {
+		return Result{Name: name, Skipped: true, Reason: "No version specified for versioned docs check"}
+	}
+
+	switch site.Kind {
+	case DocSiteDocusaurus:
+		return c.checkDocusaurusVersion(dir, site, version)
+	default:
+		return c.checkMikeVersion(dir, site, version)
+	}
+}
+
+func (c *DocChecker) checkDocusaurusVersion(dir string, site *DocSite, version string) Result {
+	name := "Docs: versioned docs"
+
+	versionsPath := filepath.Join(site.Dir, "versions.json")
+	data, err := os.ReadFile(versionsPath)
+	if os.IsNotExist(err) {
+		return Result{Name: name, Skipped: true, Reason: "Docusaurus docs are not versioned (no versions.json)"}
+	}
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+
+	var versions []string
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return Result{Name: name, Passed: false, Output: fmt.Sprintf("%s is invalid: %v", relPath(dir, versionsPath), err)}
+	}
+
+	entry := matchDocsVersion(versions, version)
+	if entry == "" {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      fmt.Sprintf("%s has no entry for %s", relPath(dir, versionsPath), version),
+			Remediation: "snapshot the docs: npx docusaurus docs:version " + strings.TrimPrefix(version, "v"),
+		}
+	}
+
+	versionedDir := filepath.Join(site.Dir, "versioned_docs", "version-"+entry)
+	if !FileExists(versionedDir) {
+		return Result{Name: name, Passed: false, Output: fmt.Sprintf("%s is listed but %s is missing", entry, relPath(dir, versionedDir))}
+	}
+	return Result{Name: name, Passed: true, Output: fmt.Sprintf("Found docs version %s", entry)}
+}
+
+func (c *DocChecker) checkMikeVersion(dir string, site *DocSite, version string) Result {
+	name := "Docs: versioned docs"
+
+	config, err := os.ReadFile(site.Config)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	if !mkdocsMikeProvider.Match(config) {
+		return Result{Name: name, Skipped: true, Reason: "MkDocs docs are not versioned (no mike provider in " + filepath.Base(site.Config) + ")"}
+	}
+
+	if r, ok := disallowed(name, "git"); ok {
+		return r
+	}
+
+	g := git.New(dir)
+	var data []byte
+	for _, ref := range []string{"gh-pages", "origin/gh-pages"} {
+		if data, err = g.ShowFile(ref, "versions.json"); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return Result{
+			Name:    name,
+			Warning: true,
+			Passed:  false,
+			Output:  "versions.json not found on gh-pages; deploy docs with: mike deploy --push " + minorVersion(version),
+		}
+	}
+
+	var entries []struct {
+		Version string   `json:"version"`
+		Aliases []string `json:"aliases"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return Result{Name: name, Passed: false, Output: fmt.Sprintf("gh-pages:versions.json is invalid: %v", err)}
+	}
+	var versions []string
+	for _, e := range entries {
+		versions = append(versions, e.Version)
+	}
+
+	if entry := matchDocsVersion(versions, version); entry != "" {
+		return Result{Name: name, Passed: true, Output: fmt.Sprintf("Found docs version %s on gh-pages", entry)}
+	}
+	return Result{
+		Name:    name,
+		Warning: true,
+		Passed:  false,
+		Output:  fmt.Sprintf("gh-pages:versions.json has no entry for %s; deploy docs with: mike deploy --push %s", version, minorVersion(version)),
+	}
+}
+
+// matchDocsVersion returns the entry in versions matching version exactly
+// or at minor granularity, ignoring a "v" prefix, or "" if none does.
+func matchDocsVersion(versions []string, version string) string {
+	full := strings.TrimPrefix(version, "v")
+	minor := minorVersion(version)
+	for _, v := range versions {
+		if strings.TrimPrefix(v, "v") == full {
+			return v
+		}
+	}
+	for _, v := range versions {
+		if strings.TrimPrefix(v, "v") == minor {
+			return v
+		}
+	}
+	return ""
+}
+
+// minorVersion returns "1.2" for "v1.2.3".
+func minorVersion(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return parts[0]
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func relPath(base, path string) string {
+	if rel, err := filepath.Rel(base, path); err == nil {
+		return rel
+	}
+	return path
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
@@ -0,0 +1,101 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectDocSite(t *testing.T) {
+	dir := t.TempDir()
+	if site := DetectDocSite(dir); site != nil {
+		t.Errorf("DetectDocSite(empty) = %+v, want nil", site)
+	}
+
+	// mkdocs.yml without docs/ is not a site
+	writeTestFile(t, filepath.Join(dir, "mkdocs.yml"), "site_name: demo\n")
+	if site := DetectDocSite(dir); site != nil {
+		t.Errorf("DetectDocSite(no docs/) = %+v, want nil", site)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "docs", "index.md"), "# Demo\n")
+	site := DetectDocSite(dir)
+	if site == nil || site.Kind != DocSiteMkDocs || site.Dir != dir {
+		t.Errorf("DetectDocSite(mkdocs) = %+v", site)
+	}
+
+	dir = t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "docs", "intro.md"), "# Intro\n")
+	writeTestFile(t, filepath.Join(dir, "website", "docusaurus.config.ts"), "export default {};\n")
+	site = DetectDocSite(dir)
+	if site == nil || site.Kind != DocSiteDocusaurus || site.Dir != filepath.Join(dir, "website") {
+		t.Errorf("DetectDocSite(docusaurus) = %+v", site)
+	}
+}
+
+func TestMatchDocsVersion(t *testing.T) {
+	versions := []string{"2.0.0", "1.3", "v1.2.1"}
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"v2.0.0", "2.0.0"},
+		{"1.2.1", "v1.2.1"},
+		{"v1.3.4", "1.3"},
+		{"v1.4.0", ""},
+	}
+	for _, tt := range tests {
+		if got := matchDocsVersion(versions, tt.version); got != tt.want {
+			t.Errorf("matchDocsVersion(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDocsVersion_Docusaurus(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "docs", "intro.md"), "# Intro\n")
+	writeTestFile(t, filepath.Join(dir, "docusaurus.config.js"), "module.exports = {};\n")
+	c := &DocChecker{}
+
+	if r := c.checkDocsVersion(dir, "v1.1.0"); !r.Skipped {
+		t.Errorf("unversioned site: got %+v, want skipped", r)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "versions.json"), `["1.0.0"]`)
+	r := c.checkDocsVersion(dir, "v1.1.0")
+	if r.Passed || r.Skipped || !strings.Contains(r.Remediation, "docs:version 1.1.0") {
+		t.Errorf("missing version: got %+v", r)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "versions.json"), `["1.1.0", "1.0.0"]`)
+	if r := c.checkDocsVersion(dir, "v1.1.0"); r.Passed || !strings.Contains(r.Output, "version-1.1.0") {
+		t.Errorf("missing versioned_docs: got %+v", r)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "versioned_docs", "version-1.1.0", "intro.md"), "# Intro\n")
+	if r := c.checkDocsVersion(dir, "v1.1.0"); !r.Passed {
+		t.Errorf("versioned: got %+v, want passed", r)
+	}
+}
+
+func TestCheckDocsVersion_MkDocsUnversioned(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "docs", "index.md"), "# Demo\n")
+	writeTestFile(t, filepath.Join(dir, "mkdocs.yml"), "site_name: demo\n")
+
+	r := (&DocChecker{}).checkDocsVersion(dir, "v1.0.0")
+	if !r.Skipped || !strings.Contains(r.Reason, "mike") {
+		t.Errorf("got %+v, want skipped without mike", r)
+	}
+}
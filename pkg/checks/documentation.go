@@ -5,10 +5,14 @@
 package checks
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/docs"
 )
 
 // DocChecker implements documentation checks.
@@ -21,8 +25,10 @@ func (c *DocChecker) Name() string {
 
 // DocOptions configures documentation checks.
 type DocOptions struct {
-	Version string // Target release version (e.g., "v0.2.0")
-	Verbose bool
+	Version        string            // Target release version (e.g., "v0.2.0")
+	MinDocCoverage float64           // Minimum Go API doc coverage in percent (0 = report only)
+	Changelog      *changelog.Loaded // Changelog shared with other checks; nil loads it from dir
+	Verbose        bool
 }
 
 // Check runs documentation checks on the specified directory.
@@ -32,6 +38,10 @@ func (c *DocChecker) Check(dir string, opts DocOptions) []Result {
 	// Check README.md exists
 	results = append(results, c.checkReadme(dir))
 
+	// Check README Go examples build and referenced files exist
+	results = append(results, c.checkReadmeGoExamples(dir))
+	results = append(results, c.checkReadmeLinks(dir))
+
 	// Check PRD.md (if exists, verify it's not empty)
 	results = append(results, c.checkOptionalDoc(dir, "PRD.md", "Product Requirements Document"))
 
@@ -41,11 +51,22 @@ func (c *DocChecker) Check(dir string, opts DocOptions) []Result {
 	// Check for MkDocs site
 	results = append(results, c.checkMkDocs(dir))
 
+	// Build the docs site and check versioned docs include the release
+	results = append(results, c.checkDocsBuild(dir))
+	results = append(results, c.checkDocsVersion(dir, opts.Version))
+
+	// Check exported Go identifiers have doc comments
+	results = append(results, c.checkGoDocCoverage(dir, opts.MinDocCoverage))
+
 	// Check for release notes
 	results = append(results, c.checkReleaseNotes(dir, opts.Version))
 
 	// Check CHANGELOG.md exists
-	results = append(results, c.checkChangelog(dir))
+	cl := opts.Changelog
+	if cl == nil {
+		cl = changelog.LoadShared(dir)
+	}
+	results = append(results, c.checkChangelog(dir, cl))
 
 	return results
 }
@@ -56,9 +77,10 @@ func (c *DocChecker) checkReadme(dir string) Result {
 
 	if !FileExists(readmePath) {
 		return Result{
-			Name:   name,
-			Passed: false,
-			Output: "README.md not found - create one to document the project",
+			Name:        name,
+			Passed:      false,
+			Output:      "README.md not found - create one to document the project",
+			Remediation: "add a README.md describing the project, how to install it, and how to use it",
 		}
 	}
 
@@ -74,9 +96,10 @@ func (c *DocChecker) checkReadme(dir string) Result {
 
 	if info.Size() < 100 {
 		return Result{
-			Name:   name,
-			Passed: false,
-			Output: "README.md exists but appears too short (< 100 bytes)",
+			Name:        name,
+			Passed:      false,
+			Output:      "README.md exists but appears too short (< 100 bytes)",
+			Remediation: "describe the project, how to install it, and how to use it in README.md",
 		}
 	}
 
@@ -94,7 +117,8 @@ func (c *DocChecker) checkOptionalDoc(dir, filename, description string) Result
 		return Result{
 			Name:    name,
 			Skipped: true,
-			Reason:  fmt.Sprintf("%s not found (optional)", description),
+			Reason: fmt.Sprintf("%s not found (optional; create with: atrelease docs init %s)",
+				description, strings.ToLower(strings.TrimSuffix(filename, ".md"))),
 		}
 	}
 
@@ -114,6 +138,18 @@ func (c *DocChecker) checkOptionalDoc(dir, filename, description string) Result
 			Warning: true,
 			Passed:  false,
 			Output:  fmt.Sprintf("%s exists but appears too short", filename),
+			Remediation: fmt.Sprintf("fill in %s, or recreate it from the template: atrelease docs init %s",
+				filename, strings.ToLower(strings.TrimSuffix(filename, ".md"))),
+		}
+	}
+
+	if _, problems := frontMatterProblems(docPath, ""); len(problems) > 0 {
+		return Result{
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      fmt.Sprintf("%s front-matter: %s", filename, strings.Join(problems, "; ")),
+			Remediation: "fix the front-matter fields listed",
 		}
 	}
 
@@ -123,6 +159,29 @@ func (c *DocChecker) checkOptionalDoc(dir, filename, description string) Result
 	}
 }
 
+// frontMatterProblems reports missing or invalid front-matter fields in a
+// document, and a version that differs from version when one is given.
+// Documents without front-matter have no problems.
+func frontMatterProblems(path, version string) (*docs.FrontMatter, []string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	fm, _, err := docs.ParseFrontMatter(data)
+	if errors.Is(err, docs.ErrNoFrontMatter) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	problems := fm.Problems()
+	if version != "" && fm.Version != "" && strings.TrimPrefix(fm.Version, "v") != strings.TrimPrefix(version, "v") {
+		problems = append(problems, fmt.Sprintf("version %s does not match %s", fm.Version, version))
+	}
+	return fm, problems
+}
+
 func (c *DocChecker) checkMkDocs(dir string) Result {
 	name := "Docs: MkDocs site"
 
@@ -141,11 +200,19 @@ func (c *DocChecker) checkMkDocs(dir string) Result {
 	if !FileExists(mkdocsPath) {
 		mkdocsPath = filepath.Join(dir, "mkdocs.yml")
 		if !FileExists(mkdocsPath) {
+			if site := DetectDocSite(dir); site != nil && site.Kind == DocSiteDocusaurus {
+				return Result{
+					Name:   name,
+					Passed: true,
+					Output: "Docusaurus site found instead of MkDocs",
+				}
+			}
 			return Result{
-				Name:    name,
-				Warning: true,
-				Passed:  false,
-				Output:  "docs/ exists but mkdocs.yml not found",
+				Name:        name,
+				Warning:     true,
+				Passed:      false,
+				Output:      "docs/ exists but mkdocs.yml not found",
+				Remediation: "add a mkdocs.yml with the site name and nav of docs/",
 			}
 		}
 	}
@@ -171,23 +238,31 @@ func (c *DocChecker) checkReleaseNotes(dir string, version string) Result {
 	ver := strings.TrimPrefix(version, "v")
 	versionWithV := "v" + ver
 
-	// Check for docs/releases/vX.Y.Z.md first
-	docsPath := filepath.Join(dir, "docs", "releases", versionWithV+".md")
-	if FileExists(docsPath) {
-		return Result{
-			Name:   name,
-			Passed: true,
-			Output: fmt.Sprintf("Found: docs/releases/%s.md", versionWithV),
+	// Check for docs/releases/vX.Y.Z.md first, then ./RELEASE_NOTES_vX.Y.Z.md
+	for _, rel := range []string{
+		filepath.Join("docs", "releases", versionWithV+".md"),
+		fmt.Sprintf("RELEASE_NOTES_%s.md", versionWithV),
+	} {
+		if !FileExists(filepath.Join(dir, rel)) {
+			continue
+		}
+		fm, problems := frontMatterProblems(filepath.Join(dir, rel), versionWithV)
+		if fm != nil && fm.Status == docs.StatusDraft {
+			problems = append(problems, "status is still draft")
+		}
+		if len(problems) > 0 {
+			return Result{
+				Name:        name,
+				Warning:     true,
+				Passed:      false,
+				Output:      fmt.Sprintf("Found: %s, but front-matter: %s", filepath.ToSlash(rel), strings.Join(problems, "; ")),
+				Remediation: fmt.Sprintf("fix the front-matter of %s, and set its status to final when it is ready", filepath.ToSlash(rel)),
+			}
 		}
-	}
-
-	// Check for ./RELEASE_NOTES_vX.Y.Z.md
-	releaseNotesPath := filepath.Join(dir, fmt.Sprintf("RELEASE_NOTES_%s.md", versionWithV))
-	if FileExists(releaseNotesPath) {
 		return Result{
 			Name:   name,
 			Passed: true,
-			Output: fmt.Sprintf("Found: RELEASE_NOTES_%s.md", versionWithV),
+			Output: fmt.Sprintf("Found: %s", filepath.ToSlash(rel)),
 		}
 	}
 
@@ -201,31 +276,33 @@ func (c *DocChecker) checkReleaseNotes(dir string, version string) Result {
 	}
 
 	return Result{
-		Name:   name,
-		Passed: false,
-		Output: fmt.Sprintf("Release notes not found. Expected: %s", expectedPath),
+		Name:        name,
+		Passed:      false,
+		Output:      fmt.Sprintf("Release notes not found. Expected: %s", expectedPath),
+		Remediation: "create them: atrelease docs init release-notes --version " + versionWithV,
 	}
 }
 
-func (c *DocChecker) checkChangelog(dir string) Result {
+func (c *DocChecker) checkChangelog(dir string, cl *changelog.Loaded) Result {
 	name := "Docs: CHANGELOG.md"
-	changelogPath := filepath.Join(dir, "CHANGELOG.md")
+	changelogPath := filepath.Join(dir, changelog.MarkdownFile)
 
 	if !FileExists(changelogPath) {
 		// Check for CHANGELOG.json as alternative
-		changelogJSON := filepath.Join(dir, "CHANGELOG.json")
-		if FileExists(changelogJSON) {
+		if cl.Source == changelog.DefaultFile {
 			return Result{
-				Name:    name,
-				Warning: true,
-				Passed:  false,
-				Output:  "CHANGELOG.json exists but CHANGELOG.md not generated. Run: schangelog generate",
+				Name:        name,
+				Warning:     true,
+				Passed:      false,
+				Output:      "CHANGELOG.json exists but CHANGELOG.md not generated",
+				Remediation: "generate it: schangelog generate",
 			}
 		}
 		return Result{
-			Name:   name,
-			Passed: false,
-			Output: "CHANGELOG.md not found",
+			Name:        name,
+			Passed:      false,
+			Output:      "CHANGELOG.md not found",
+			Remediation: "create CHANGELOG.json with schangelog init, then generate CHANGELOG.md with schangelog generate",
 		}
 	}
 
@@ -0,0 +1,538 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/detect"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/proc"
+	"github.com/plexusone/agent-team-release/pkg/runlog"
+)
+
+// Engine runs the language checks for a directory. Commands detect the
+// languages and pick the options; the engine applies the configuration
+// (tools policy, containers, remote backends, check filters, and strict
+// mode) the same way for all of them, so new languages and options only
+// need to be wired here.
+type Engine struct {
+	Config  config.Config
+	Options Options
+
+	Container    bool // Run checks in containers even if container.enabled is off
+	Local        bool // Run heavy checks on this machine even if a remote backend is configured
+	ChangedCode  bool // Also check code changed since the upstream ref
+	CoverageDiff bool // Compare coverage of changed Go packages against the merge base
+
+	// Log reports progress, one line per call; nil discards it.
+	Log func(format string, args ...any)
+	// Heartbeat also reports commands still running to Log, streaming
+	// their output when verbose, so long checks don't look hung.
+	Heartbeat bool
+}
+
+// NewEngine returns an engine for cfg, with options resolved from cfg,
+// the environment, and flags by ResolveOptions.
+func NewEngine(cfg config.Config, flags OptionFlags) (*Engine, error) {
+	opts, err := ResolveOptions(cfg, nil, flags)
+	if err != nil {
+		return nil, err
+	}
+	return &Engine{Config: cfg, Options: opts}, nil
+}
+
+// GoEnv returns the environment for the go commands checks run, including
+// those releasekit runs, with the build and test flags of lc added to
+// GOFLAGS. go build and go vet ignore the test flags there. Flags may be
+// written as "-tags=integration" or "-tags integration"; their values
+// can't contain spaces.
+func GoEnv(lc config.LanguageConfig) []string {
+	keys := make([]string, 0, len(lc.Env))
+	for k := range lc.Env {
+		if k != "GOFLAGS" {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	env := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		env = append(env, k+"="+lc.Env[k])
+	}
+
+	goflags, set := lc.Env["GOFLAGS"]
+	if !set {
+		goflags = os.Getenv("GOFLAGS")
+	}
+	extra := slices.Concat(lc.BuildFlags, lc.TestFlags)
+	if !set && len(extra) == 0 {
+		return env
+	}
+	var flags []string
+	for _, f := range strings.Fields(goflags + " " + strings.Join(extra, " ")) {
+		// A value following its flag, as in "-tags integration"
+		if n := len(flags); n > 0 && !strings.HasPrefix(f, "-") && !strings.Contains(flags[n-1], "=") {
+			flags[n-1] += "=" + f
+			continue
+		}
+		flags = append(flags, f)
+	}
+	return append(env, "GOFLAGS="+strings.Join(flags, " "))
+}
+
+// ConfigCommandPolicy returns the external tool policy configured in cfg.
+func ConfigCommandPolicy(cfg config.Config) CommandPolicy {
+	return CommandPolicy{Allow: cfg.Tools.Allow, Deny: cfg.Tools.Deny}
+}
+
+// Run checks dir, in which detections were found, and returns the results
+// ordered by detection path and ID. Detections of languages disabled in
+// the config are ignored; if none remain, nothing is checked.
+func (e *Engine) Run(dir string, detections []detect.Detection) ([]Result, error) {
+	cfg := e.Config
+	SetCommandPolicy(ConfigCommandPolicy(cfg))
+	defer proc.SetEnv(e.Options.Env)()
+	if e.Heartbeat {
+		SetProgress(Progress{Log: e.Log, Verbose: e.Options.Verbose})
+		defer SetProgress(Progress{})
+	}
+
+	detections = slices.DeleteFunc(slices.Clone(detections), func(d detect.Detection) bool {
+		return !cfg.IsLanguageEnabled(string(d.Language)) || e.excluded(dir, string(d.Language), d.Path)
+	})
+	if len(detections) == 0 {
+		return nil, nil
+	}
+
+	// Tests offloaded to a remote runner are left out of the local run
+	backend, err := e.backend()
+	if err != nil {
+		return nil, err
+	}
+	_, local := backend.(LocalBackend)
+	localOpts := e.Options
+	if !local && slices.Contains(cfg.Remote.Checks, "tests") {
+		localOpts.Test = false
+	}
+
+	// Sharded Go tests replace releasekit's, which can only be turned off
+	// for every language at once
+	container := e.Container || cfg.Container.Enabled
+	shards := cfg.GetLanguageConfig("go").TestShards
+	sharded := localOpts.Test && shards > 1 && !container && onlyGo(detections)
+	if sharded {
+		localOpts.Test = false
+		localOpts.Coverage = false
+	}
+
+	var results []Result
+	if container {
+		e.log("Running checks in containers...")
+		results, err = e.containerResults(dir, detections, localOpts)
+	} else {
+		// releasekit auto-detects languages, so it is run once
+		e.log("Running checks via releasekit...")
+		results, err = RunReleasekit(dir, localOpts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("running releasekit: %w", err)
+	}
+
+	// Heavy checks, on the remote runner if one is configured
+	if e.Options.Test && len(cfg.Remote.Checks) > 0 {
+		heavy, err := e.heavyResults(dir, detections, backend)
+		if err != nil {
+			return nil, fmt.Errorf("running checks on %s: %w", backend.Name(), err)
+		}
+		results = append(results, heavy...)
+	}
+
+	// releasekit doesn't compare vendor/ with go.mod
+	goDetections := detect.GetByLanguage(detections, detect.Go)
+	if sharded {
+		for _, d := range goDetections {
+			e.log("Running Go tests in %s in %d shards...", d.Path, shards)
+			results = append(results, CheckGoTestsSharded(d.Path, shards, e.Options.Coverage, e.Options.GoExcludeCoverage)...)
+		}
+	}
+	for _, d := range goDetections {
+		if HasGoVendor(d.Path) {
+			results = append(results, CheckGoVendor(d.Path))
+		}
+	}
+	results = e.goReplaceResults(dir, goDetections, results)
+
+	if e.ChangedCode && (e.Options.Test || e.CoverageDiff) {
+		results = append(results, e.changedCodeResults(dir)...)
+	}
+
+	AssignIDs(results)
+	ApplyFindings(dir, results)
+	if len(cfg.Checks.Flaky) > 0 {
+		// Tests that ran in a container can't be rerun on their own
+		var rerun RerunFunc
+		if !container {
+			rerun = e.rerunTests(dir, backend)
+		}
+		ApplyFlaky(results, cfg.Checks.Flaky, cfg.Checks.FlakyRetries, rerun)
+	}
+	e.skipExcluded(dir, results)
+	SkipByID(results, cfg.Checks.Skip, "skipped by config (checks.skip)")
+	ApplyWaivers(dir, results, cfg.Checks.Waivers, time.Now())
+	SortResults(results)
+	if cfg.Strict {
+		results = PromoteWarnings(results)
+	}
+	e.saveLogs(dir, results)
+	return results, nil
+}
+
+// onlyGo reports whether all detections are Go projects.
+func onlyGo(detections []detect.Detection) bool {
+	for _, d := range detections {
+		if d.Language != detect.Go {
+			return false
+		}
+	}
+	return true
+}
+
+// saveLogs saves the full output of each result to the logs of dir and
+// truncates the output of results to the configured number of lines,
+// ending it with the path of the full log. Logs are kept per detection
+// path, since the results of each module of a monorepo share check IDs.
+func (e *Engine) saveLogs(dir string, results []Result) {
+	logs := make(map[string]string)
+	for _, r := range results {
+		if r.Output != "" {
+			logs[runlog.LogName(pathInDir(dir, r.Path), r.ID)] = r.Output
+		}
+	}
+	if err := runlog.WriteLogs(dir, logs); err != nil {
+		// Without a log to point to, output is shown in full
+		e.log("Warning: saving check logs: %v", err)
+		return
+	}
+
+	maxLines := e.Config.Checks.MaxLines
+	for i, r := range results {
+		if maxLines <= 0 || r.Output == "" {
+			continue
+		}
+		lines := strings.Split(r.Output, "\n")
+		if len(lines) <= maxLines {
+			continue
+		}
+		results[i].Output = strings.Join(lines[:maxLines], "\n") +
+			fmt.Sprintf("\n… %d more lines in %s", len(lines)-maxLines, runlog.LogPath(dir, pathInDir(dir, r.Path), r.ID))
+	}
+}
+
+// goReplaceResults replaces releasekit's replace directive results for
+// the Go detections with CheckGoReplaces, which honors
+// languages.go.allow_replace and tells local replaces from version pins.
+func (e *Engine) goReplaceResults(dir string, detections []detect.Detection, results []Result) []Result {
+	allow := e.Config.GetLanguageConfig("go").AllowReplace
+	checked := make(map[string]bool)
+	var replaces []Result
+	for _, d := range detections {
+		if !FileExists(filepath.Join(d.Path, "go.mod")) {
+			continue
+		}
+		checked[pathInDir(dir, d.Path)] = true
+		replaces = append(replaces, CheckGoReplaces(d.Path, allow))
+	}
+	id := ResultID(Result{Name: goReplaceName})
+	results = slices.DeleteFunc(results, func(r Result) bool {
+		return ResultID(r) == id && checked[pathInDir(dir, r.Path)]
+	})
+	return append(results, replaces...)
+}
+
+// skipExcluded marks the results in paths excluded for their language as
+// skipped. releasekit checks every module it finds, so results of excluded
+// modules are only dropped afterwards.
+func (e *Engine) skipExcluded(dir string, results []Result) {
+	for i, r := range results {
+		if r.Skipped {
+			continue
+		}
+		for _, lang := range strings.Split(ResultLanguage(r), "/") {
+			if e.excluded(dir, lang, r.Path) {
+				results[i].Skipped = true
+				results[i].Reason = fmt.Sprintf("excluded by config (languages.%s.exclude)", strings.ToLower(lang))
+				break
+			}
+		}
+	}
+}
+
+// excluded reports whether p, a detection or result path, is excluded for
+// lang in the config. Patterns are relative to dir.
+func (e *Engine) excluded(dir, lang, p string) bool {
+	patterns := e.Config.Languages[strings.ToLower(lang)].Exclude
+	return len(patterns) > 0 && detect.Excluded(patterns, pathInDir(dir, p))
+}
+
+// pathInDir returns p relative to dir. Detection paths, and result paths
+// mapped from containers and remote runners, include dir; releasekit
+// reports paths relative to it, and an empty path is dir itself.
+func pathInDir(dir, p string) string {
+	if p == "" {
+		return "."
+	}
+	if filepath.IsAbs(p) {
+		if absDir, err := filepath.Abs(dir); err == nil {
+			if rel, err := filepath.Rel(absDir, p); err == nil {
+				return rel
+			}
+		}
+		return p
+	}
+	if rel, err := filepath.Rel(dir, p); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return rel
+	}
+	return filepath.Clean(p)
+}
+
+func (e *Engine) log(format string, args ...any) {
+	if e.Log != nil {
+		e.Log(format, args...)
+	}
+}
+
+// backend returns the backend heavy checks run on: the configured remote
+// runner, or this machine when none is configured, Local is set, or
+// checks run offline.
+func (e *Engine) backend() (Backend, error) {
+	r := e.Config.Remote
+	if e.Local || offline {
+		return LocalBackend{}, nil
+	}
+	switch r.Backend {
+	case "":
+		return LocalBackend{}, nil
+	case "ssh":
+		if r.Host == "" || r.Dir == "" {
+			return nil, fmt.Errorf("remote.host and remote.dir are required for the ssh backend")
+		}
+		return SSHBackend{Host: r.Host, Dir: r.Dir}, nil
+	case "http":
+		if r.URL == "" {
+			return nil, fmt.Errorf("remote.url is required for the http backend")
+		}
+		client, err := e.Config.Network.HTTPClient(30 * time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		b := HTTPBackend{URL: r.URL, Client: client}
+		if r.TokenEnv != "" {
+			b.Token = os.Getenv(r.TokenEnv)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown remote backend %q: expected ssh or http", r.Backend)
+	}
+}
+
+// containerOptions returns the container settings in the config.
+func (e *Engine) containerOptions() ContainerOptions {
+	c := e.Config.Container
+	cacheDir := c.Cache
+	if cacheDir == "" {
+		cacheDir = DefaultContainerCacheDir()
+	}
+	return ContainerOptions{
+		Runtime:  c.Runtime,
+		Image:    c.Image,
+		Images:   c.Images,
+		CacheDir: cacheDir,
+	}
+}
+
+// containerResults runs releasekit in the container image of each
+// detection's language, once per image and detection path. Detections
+// without an image are reported as skipped.
+func (e *Engine) containerResults(dir string, detections []detect.Detection, opts Options) ([]Result, error) {
+	copts := e.containerOptions()
+	var results []Result
+	seen := make(map[[2]string]bool)
+	for _, d := range detections {
+		image := copts.ImageFor(string(d.Language))
+		if image == "" {
+			results = append(results, Result{
+				Name:     "Container: " + string(d.Language),
+				Path:     d.Path,
+				Language: string(d.Language),
+				Skipped:  true,
+				Reason:   fmt.Sprintf("no container image configured for %s (container.images.%s)", d.Language, d.Language),
+			})
+			continue
+		}
+		key := [2]string{image, d.Path}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		e.log("  %s in %s", image, d.Path)
+		r, err := RunReleasekitInContainer(dir, d.Path, image, opts, copts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r...)
+	}
+	return results, nil
+}
+
+// heavyResults runs the heavy checks named in remote.checks on backend.
+// Tests are only run here when the backend is remote; locally they are
+// part of the releasekit run.
+func (e *Engine) heavyResults(dir string, detections []detect.Detection, backend Backend) ([]Result, error) {
+	_, local := backend.(LocalBackend)
+	var results []Result
+	for _, name := range e.Config.Remote.Checks {
+		switch name {
+		case "tests":
+			if local {
+				continue
+			}
+			e.log("Running tests on %s...", backend.Name())
+			r, err := RunReleasekitOn(backend, dir, ".", Options{
+				Test:     true,
+				Coverage: e.Options.Coverage,
+				Verbose:  e.Options.Verbose,
+				Env:      e.Options.Env,
+			})
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, r...)
+		case "race":
+			for _, d := range detect.GetByLanguage(detections, detect.Go) {
+				rel, err := filepath.Rel(dir, d.Path)
+				if err != nil {
+					return nil, err
+				}
+				e.log("Running race detector in %s on %s...", d.Path, backend.Name())
+				results = append(results, CheckGoRace(backend, dir, filepath.ToSlash(rel), e.Options.Env))
+			}
+		default:
+			return nil, fmt.Errorf("unknown remote check %q: expected tests or race", name)
+		}
+	}
+	return results, nil
+}
+
+// rerunTests returns a RerunFunc running the failed tests of a Go or
+// JavaScript test check on backend, in the result's detection path.
+func (e *Engine) rerunTests(dir string, backend Backend) RerunFunc {
+	return func(r Result, tests []FailedTest) bool {
+		rel := filepath.ToSlash(pathInDir(dir, r.Path))
+		args := rerunArgs(ResultID(r), filepath.Join(dir, rel), tests)
+		if !CommandAllowed(args[0]) {
+			return false
+		}
+		e.log("Rerunning %d quarantined tests of %s in %s...", len(tests), r.Name, rel)
+		_, err := backend.Output(dir, rel, withEnv(e.Options.Env, args))
+		return err == nil
+	}
+}
+
+// rerunArgs returns the command running only tests of the test check with
+// the given ID, for the project in projectDir: go test -run for Go, and
+// the package's test script with -t, which Jest and Vitest take, for
+// JavaScript.
+func rerunArgs(id, projectDir string, tests []FailedTest) []string {
+	names := make([]string, 0, len(tests))
+	if strings.HasPrefix(id, "go.") {
+		var pkgs []string
+		for _, t := range tests {
+			names = append(names, regexp.QuoteMeta(t.Name))
+			if t.Package != "" && !slices.Contains(pkgs, t.Package) {
+				pkgs = append(pkgs, t.Package)
+			}
+		}
+		if len(pkgs) == 0 {
+			pkgs = []string{"./..."}
+		}
+		return slices.Concat([]string{"go", "test", "-count=1", "-run", "^(" + strings.Join(names, "|") + ")$"}, pkgs)
+	}
+
+	// Jest and Vitest match -t against the test's describe blocks and name
+	// joined with spaces
+	for _, t := range tests {
+		names = append(names, regexp.QuoteMeta(strings.ReplaceAll(t.Name, " > ", " ")))
+	}
+	pattern := strings.Join(names, "|")
+	pm := "npm"
+	if lockfiles := DetectLockfiles(projectDir); len(lockfiles) > 0 {
+		pm = Lockfiles[lockfiles[0]]
+	}
+	switch pm {
+	case "npm":
+		return []string{"npm", "test", "--", "-t", pattern}
+	case "bun":
+		return []string{"bun", "run", "test", "-t", pattern}
+	default:
+		return []string{pm, "test", "-t", pattern}
+	}
+}
+
+// changedCodeResults runs checks against the upstream ref: test presence
+// for changed code, the coverage ratchet with CoverageDiff, and benchmark
+// regressions when enabled. Without an upstream it returns nothing.
+func (e *Engine) changedCodeResults(dir string) []Result {
+	cfg := e.Config
+	g := git.New(dir)
+	upstream, err := g.UpstreamRef()
+	if err != nil {
+		if e.Options.Verbose {
+			e.log("Skipping changed-code checks: %v", err)
+		}
+		return nil
+	}
+	base, err := g.MergeBase(upstream, "HEAD")
+	if err != nil {
+		return nil
+	}
+	files, err := g.ChangedFiles(base)
+	if err != nil {
+		return nil
+	}
+	// Changed paths are relative to the repository root
+	root, err := g.TopLevel()
+	if err != nil {
+		return nil
+	}
+
+	var results []Result
+	if e.Options.Test {
+		results = append(results, CheckChangedTests(root, files)...)
+	}
+	if e.CoverageDiff {
+		e.log("Comparing coverage against %s...", upstream)
+		results = append(results, CheckCoverageDiff(root, base, ChangedGoPackages(files), CoverageDiffOptions{
+			MaxDrop:  cfg.CoverageDiff.MaxDrop,
+			Fail:     cfg.CoverageDiff.Fail,
+			CacheDir: DefaultCoverageCacheDir(),
+		}))
+	}
+	if e.Options.Test && cfg.Benchmarks.Enabled {
+		e.log("Comparing benchmarks against %s (this may take a while)...", upstream)
+		results = append(results, CheckBenchmarks(root, base, BenchOptions{
+			Packages:  cfg.Benchmarks.Packages,
+			Pattern:   cfg.Benchmarks.Pattern,
+			Count:     cfg.Benchmarks.Count,
+			Threshold: cfg.Benchmarks.Threshold,
+			Alpha:     0.05,
+			Fail:      cfg.Benchmarks.Fail,
+		}))
+	}
+	return results
+}
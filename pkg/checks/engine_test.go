@@ -0,0 +1,242 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/detect"
+)
+
+// fakeReleasekit puts a releasekit on PATH that prints agentResultJSON and
+// exits with code 2 (NO-GO).
+func fakeReleasekit(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake releasekit is a shell script")
+	}
+	bin := t.TempDir()
+	writeTestFile(t, filepath.Join(bin, "agent.json"), agentResultJSON)
+	script := "#!/bin/sh\ncat " + filepath.Join(bin, "agent.json") + "\nexit 2\n"
+	if err := os.WriteFile(filepath.Join(bin, "releasekit"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// newEngine returns an engine for cfg without flags.
+func newEngine(t *testing.T, cfg config.Config) *Engine {
+	t.Helper()
+	e, err := NewEngine(cfg, OptionFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestNewEngine_Options(t *testing.T) {
+	f, tr := false, true
+	cfg := config.DefaultConfig()
+	cfg.Languages = map[string]config.LanguageConfig{
+		"go": {Test: &f, Coverage: &tr},
+	}
+	opts := newEngine(t, cfg).Options
+	if opts.Test || !opts.Lint || !opts.Format || !opts.Coverage {
+		t.Errorf("options don't follow the go language config: %+v", opts)
+	}
+}
+
+func TestEngine_Run(t *testing.T) {
+	fakeReleasekit(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Checks.Skip = []string{"go.build"}
+	results, err := newEngine(t, cfg).Run(t.TempDir(), []detect.Detection{{Language: detect.Go, Path: "."}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	if got := strings.Join(ids, ","); got != "go.build,go.test" {
+		t.Fatalf("result IDs = %s, want go.build,go.test", got)
+	}
+	if !results[0].Skipped || !strings.Contains(results[0].Reason, "checks.skip") {
+		t.Errorf("expected go.build to be skipped by config, got %+v", results[0])
+	}
+	if results[1].Passed {
+		t.Errorf("expected go.test to fail, got %+v", results[1])
+	}
+}
+
+func TestEngine_RunDisabledLanguage(t *testing.T) {
+	f := false
+	cfg := config.DefaultConfig()
+	cfg.Languages = map[string]config.LanguageConfig{"go": {Enabled: &f}}
+
+	results, err := newEngine(t, cfg).Run(t.TempDir(), []detect.Detection{{Language: detect.Go, Path: "."}})
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected nothing to run for a disabled language, got %+v (%v)", results, err)
+	}
+}
+
+func TestEngine_RunBadBackend(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Remote.Backend = "ftp"
+
+	_, err := newEngine(t, cfg).Run(t.TempDir(), []detect.Detection{{Language: detect.Go, Path: "."}})
+	if err == nil || !strings.Contains(err.Error(), "unknown remote backend") {
+		t.Errorf("expected an unknown backend error, got %v", err)
+	}
+
+	e := newEngine(t, cfg)
+	e.Local = true
+	if _, err := e.backend(); err != nil {
+		t.Errorf("expected Local to ignore the remote backend, got %v", err)
+	}
+}
+
+func TestEngine_SaveLogs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Checks.MaxLines = 2
+	results := []Result{
+		{ID: "go.test", Output: "one\ntwo\nthree\nfour"},
+		{ID: "go.vet", Output: "short"},
+		{ID: "go.build", Passed: true},
+	}
+	newEngine(t, cfg).saveLogs(dir, results)
+
+	logPath := filepath.Join(dir, ".atrelease", "logs", "go.test.log")
+	want := "one\ntwo\n… 2 more lines in " + logPath
+	if results[0].Output != want {
+		t.Errorf("truncated output = %q, want %q", results[0].Output, want)
+	}
+	if results[1].Output != "short" {
+		t.Errorf("short output changed to %q", results[1].Output)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil || string(data) != "one\ntwo\nthree\nfour\n" {
+		t.Errorf("log = %q (%v), want the full output", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".atrelease", "logs", "go.build.log")); err == nil {
+		t.Error("expected no log for a check without output")
+	}
+
+	// The next run replaces the logs
+	newEngine(t, cfg).saveLogs(dir, []Result{{ID: "go.vet", Output: "again"}})
+	if _, err := os.Stat(logPath); err == nil {
+		t.Error("expected the previous run's logs to be removed")
+	}
+}
+
+func TestEngine_SaveLogs_Monorepo(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Checks.MaxLines = 1
+	results := []Result{
+		{ID: "go.test", Path: filepath.Join(dir, "api"), Output: "api one\napi two"},
+		{ID: "go.test", Path: filepath.Join(dir, "services", "web"), Output: "web one\nweb two"},
+	}
+	newEngine(t, cfg).saveLogs(dir, results)
+
+	for i, want := range []struct{ log, output string }{
+		{filepath.Join(dir, ".atrelease", "logs", "api", "go.test.log"), "api one\napi two\n"},
+		{filepath.Join(dir, ".atrelease", "logs", "services-web", "go.test.log"), "web one\nweb two\n"},
+	} {
+		if !strings.HasSuffix(results[i].Output, "1 more lines in "+want.log) {
+			t.Errorf("truncated output = %q, want it to point to %s", results[i].Output, want.log)
+		}
+		data, err := os.ReadFile(want.log)
+		if err != nil || string(data) != want.output {
+			t.Errorf("log %s = %q (%v), want %q", want.log, data, err, want.output)
+		}
+	}
+}
+
+func TestGoEnv(t *testing.T) {
+	t.Setenv("GOFLAGS", "-mod=mod")
+	tests := []struct {
+		name string
+		lc   config.LanguageConfig
+		want []string
+	}{
+		{"none", config.LanguageConfig{}, []string{}},
+		{
+			"flags added to GOFLAGS",
+			config.LanguageConfig{BuildFlags: []string{"-tags integration"}, TestFlags: []string{"-short", "-run", "Unit"}},
+			[]string{"GOFLAGS=-mod=mod -tags=integration -short -run=Unit"},
+		},
+		{
+			"configured GOFLAGS and env",
+			config.LanguageConfig{
+				TestFlags: []string{"-count=1"},
+				Env:       map[string]string{"GOFLAGS": "-race", "CGO_ENABLED": "1", "GOEXPERIMENT": "synctest"},
+			},
+			[]string{"CGO_ENABLED=1", "GOEXPERIMENT=synctest", "GOFLAGS=-race -count=1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GoEnv(tt.lc); !slices.Equal(got, tt.want) {
+				t.Errorf("GoEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_RunExclude(t *testing.T) {
+	fakeReleasekit(t)
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Languages = map[string]config.LanguageConfig{"go": {Exclude: []string{"svc/**"}}}
+	results, err := newEngine(t, cfg).Run(dir, []detect.Detection{
+		{Language: detect.Go, Path: dir},
+		{Language: detect.Go, Path: filepath.Join(dir, "svc", "api")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		excluded := r.Path == "svc/api"
+		if r.Skipped != excluded {
+			t.Errorf("%s in %q: skipped = %v, want %v", r.ID, r.Path, r.Skipped, excluded)
+		}
+		if excluded && !strings.Contains(r.Reason, "languages.go.exclude") {
+			t.Errorf("unexpected reason %q", r.Reason)
+		}
+	}
+
+	e := newEngine(t, cfg)
+	if !e.excluded(dir, "Go", filepath.Join(dir, "svc", "api")) || e.excluded(dir, "Go", "") {
+		t.Error("expected only svc/api to be excluded")
+	}
+}
+
+func TestEngine_GoReplaceResults(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), replaceGoMod)
+
+	cfg := config.DefaultConfig()
+	cfg.Languages = map[string]config.LanguageConfig{"go": {AllowReplace: []string{"../*", "./tools/*"}}}
+	results := newEngine(t, cfg).goReplaceResults(dir, []detect.Detection{{Language: detect.Go, Path: dir}}, []Result{
+		{Name: "Go: no local replace directives", Output: "local replace found"},
+		{Name: "Go: build", Passed: true},
+	})
+	if len(results) != 2 {
+		t.Fatalf("expected releasekit's result to be replaced, got %+v", results)
+	}
+	if r := results[1]; r.Name != "Go: no local replace directives" || !r.Passed {
+		t.Errorf("expected allowed replaces to pass, got %+v", r)
+	}
+}
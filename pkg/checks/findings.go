@@ -0,0 +1,260 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Finding severities.
+const (
+	FindingError   = "error"
+	FindingWarning = "warning"
+)
+
+// Finding is an issue a linter reported at a line of a file.
+type Finding struct {
+	File     string `json:"file" toon:"file"` // Relative to the detection path, with forward slashes
+	Line     int    `json:"line" toon:"line"`
+	Column   int    `json:"column,omitempty" toon:"column,omitempty"`
+	Rule     string `json:"rule,omitempty" toon:"rule,omitempty"` // e.g. "errcheck" or "no-unused-vars"
+	Severity string `json:"severity" toon:"severity"`             // FindingError or FindingWarning
+	Message  string `json:"message" toon:"message"`
+}
+
+// String returns the finding as a line of linter output, e.g.
+// "internal/db/conn.go:42:7: Error return value is not checked (errcheck)".
+func (f Finding) String() string {
+	pos := fmt.Sprintf("%s:%d", f.File, f.Line)
+	if f.Column > 0 {
+		pos += fmt.Sprintf(":%d", f.Column)
+	}
+	if f.Rule == "" {
+		return pos + ": " + f.Message
+	}
+	return fmt.Sprintf("%s: %s (%s)", pos, f.Message, f.Rule)
+}
+
+// lintChecks are the check names, the part of an ID after the language
+// prefix, whose output is parsed into findings.
+var lintChecks = map[string]bool{
+	"lint":          true,
+	"golangci_lint": true,
+	"eslint":        true,
+}
+
+// IsLintCheck reports whether id is a lint check, such as "go.golangci_lint"
+// or "ts.lint".
+func IsLintCheck(id string) bool {
+	_, check, ok := strings.Cut(id, ".")
+	if !ok {
+		check = id
+	}
+	return lintChecks[check]
+}
+
+// golangciReport is the output of golangci-lint run --out-format json.
+type golangciReport struct {
+	Issues []struct {
+		FromLinter string
+		Text       string
+		Severity   string
+		Pos        struct {
+			Filename string
+			Line     int
+			Column   int
+		}
+	}
+}
+
+// eslintFile is a file of the output of eslint --format json.
+type eslintFile struct {
+	FilePath string `json:"filePath"`
+	Messages []struct {
+		RuleID   string `json:"ruleId"`
+		Severity int    `json:"severity"` // 1 warning, 2 error
+		Message  string `json:"message"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+	} `json:"messages"`
+}
+
+var (
+	// textFinding matches a finding of golangci-lint's default text
+	// output or ESLint's unix format, such as
+	// "pkg/db/conn.go:42:7: Error return value is not checked (errcheck)".
+	textFinding = regexp.MustCompile(`^([^\s:]+\.[A-Za-z0-9]+):(\d+)(?::(\d+))?:\s+(.*?)(?:\s+\(([\w/@.-]+)\))?$`)
+
+	// stylishFinding matches a finding under a file name in ESLint's
+	// default stylish output, such as "  12:5  error  'x' is unused  no-unused-vars".
+	stylishFinding = regexp.MustCompile(`^\s+(\d+):(\d+)\s+(error|warning)\s+(.*?)(?:\s{2,}(\S+))?$`)
+)
+
+// ParseFindings parses linter output into findings. It reads the JSON
+// output of golangci-lint and ESLint, golangci-lint's text output, and
+// ESLint's stylish and unix output. Absolute file names are made relative
+// to root, the checked detection directory.
+//
+// JSON and stylish output can't be matched line by line, so for them it
+// also returns the output rewritten with a line per finding, in the format
+// of Finding.String, keeping any lines before the JSON; otherwise the
+// output is returned as is.
+func ParseFindings(output, root string) ([]Finding, string) {
+	if findings, rest, ok := parseJSONFindings(output, root); ok {
+		lines := []string{}
+		if rest != "" {
+			lines = append(lines, rest)
+		}
+		for _, f := range findings {
+			lines = append(lines, f.String())
+		}
+		return findings, strings.Join(lines, "\n")
+	}
+
+	var findings []Finding
+	var lines []string
+	stylish := false
+	file := ""
+	for _, line := range strings.Split(output, "\n") {
+		if m := stylishFinding.FindStringSubmatch(line); m != nil && file != "" {
+			f := Finding{File: file, Severity: m[3], Message: m[4], Rule: m[5]}
+			f.Line, _ = strconv.Atoi(m[1])
+			f.Column, _ = strconv.Atoi(m[2])
+			findings = append(findings, f)
+			lines = append(lines, f.String())
+			stylish = true
+			continue
+		}
+		if m := textFinding.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			f := Finding{File: relFinding(m[1], root), Severity: FindingError, Message: m[4], Rule: m[5]}
+			f.Line, _ = strconv.Atoi(m[2])
+			f.Column, _ = strconv.Atoi(m[3])
+			findings = append(findings, f)
+			lines = append(lines, line)
+			continue
+		}
+		// A stylish file header is a file name on its own line
+		file = ""
+		if trimmed := strings.TrimSpace(line); trimmed == line && trimmed != "" && !strings.Contains(trimmed, " ") && filepath.Ext(trimmed) != "" {
+			file = relFinding(trimmed, root)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if !stylish {
+		return findings, output
+	}
+	return findings, strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// parseJSONFindings parses golangci-lint or ESLint JSON output, which may
+// follow other output such as log lines, returning the findings and the
+// output before the JSON.
+func parseJSONFindings(output, root string) ([]Finding, string, bool) {
+	// The JSON starts at the first line starting with a brace or bracket
+	start, offset := -1, 0
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if strings.HasPrefix(line, "{") || strings.HasPrefix(line, "[") {
+			start = offset
+			break
+		}
+		offset += len(line)
+	}
+	if start < 0 {
+		return nil, "", false
+	}
+	rest := strings.TrimSpace(output[:start])
+	data := []byte(strings.TrimSpace(output[start:]))
+
+	if data[0] == '{' {
+		var report golangciReport
+		if json.Unmarshal(data, &report) != nil || report.Issues == nil {
+			return nil, "", false
+		}
+		findings := []Finding{}
+		for _, issue := range report.Issues {
+			severity := FindingError
+			if strings.EqualFold(issue.Severity, FindingWarning) {
+				severity = FindingWarning
+			}
+			findings = append(findings, Finding{
+				File:     relFinding(issue.Pos.Filename, root),
+				Line:     issue.Pos.Line,
+				Column:   issue.Pos.Column,
+				Rule:     issue.FromLinter,
+				Severity: severity,
+				Message:  issue.Text,
+			})
+		}
+		return findings, rest, true
+	}
+
+	var files []eslintFile
+	if json.Unmarshal(data, &files) != nil {
+		return nil, "", false
+	}
+	findings := []Finding{}
+	for _, file := range files {
+		for _, m := range file.Messages {
+			severity := FindingError
+			if m.Severity == 1 {
+				severity = FindingWarning
+			}
+			findings = append(findings, Finding{
+				File:     relFinding(file.FilePath, root),
+				Line:     m.Line,
+				Column:   m.Column,
+				Rule:     m.RuleID,
+				Severity: severity,
+				Message:  m.Message,
+			})
+		}
+	}
+	return findings, rest, true
+}
+
+// relFinding returns the file name of a finding relative to root, with
+// forward slashes.
+func relFinding(file, root string) string {
+	if filepath.IsAbs(file) && root != "" {
+		if rel, err := filepath.Rel(root, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		}
+	}
+	return filepath.ToSlash(file)
+}
+
+// ApplyFindings attaches the findings of the failed and warning lint
+// results of dir, replacing JSON and stylish linter output with a line per
+// finding so waivers can match them.
+func ApplyFindings(dir string, results []Result) {
+	for i, r := range results {
+		if s := r.Severity(); s != SeverityFailed && s != SeverityWarning || !IsLintCheck(ResultID(r)) {
+			continue
+		}
+		root, err := filepath.Abs(filepath.Join(dir, pathInDir(dir, r.Path)))
+		if err != nil {
+			root = ""
+		}
+		findings, output := ParseFindings(r.Output, root)
+		if len(findings) == 0 {
+			continue
+		}
+		results[i].Findings = findings
+		results[i].Output = output
+	}
+}
+
+// dropFinding removes the finding a line of output, reported at line n of
+// file, describes.
+func dropFinding(findings []Finding, file string, n int, line string) []Finding {
+	for i, f := range findings {
+		if f.File == file && f.Line == n && strings.Contains(line, f.Message) {
+			return append(findings[:i:i], findings[i+1:]...)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,102 @@
+package checks
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestParseFindings(t *testing.T) {
+	root := filepath.FromSlash("/src/web")
+	for _, tt := range []struct {
+		name, output string
+		want         []Finding
+		wantOutput   string
+	}{
+		{
+			name: "golangci-lint json",
+			output: `level=warning msg="[runner] deprecated linter"` + "\n" +
+				`{"Issues":[{"FromLinter":"errcheck","Text":"Error return value is not checked","Severity":"","Pos":{"Filename":"db/conn.go","Line":42,"Column":7}},` +
+				`{"FromLinter":"godot","Text":"Comment should end in a period","Severity":"warning","Pos":{"Filename":"db/doc.go","Line":1,"Column":1}}],"Report":{}}`,
+			want: []Finding{
+				{File: "db/conn.go", Line: 42, Column: 7, Rule: "errcheck", Severity: FindingError, Message: "Error return value is not checked"},
+				{File: "db/doc.go", Line: 1, Column: 1, Rule: "godot", Severity: FindingWarning, Message: "Comment should end in a period"},
+			},
+			wantOutput: `level=warning msg="[runner] deprecated linter"` + "\n" +
+				"db/conn.go:42:7: Error return value is not checked (errcheck)\ndb/doc.go:1:1: Comment should end in a period (godot)",
+		},
+		{
+			name: "eslint json",
+			output: `[{"filePath":"/src/web/src/app.ts","messages":[{"ruleId":"no-unused-vars","severity":2,"message":"'x' is unused","line":3,"column":7},` +
+				`{"ruleId":"eqeqeq","severity":1,"message":"Expected '==='","line":9,"column":12}]},{"filePath":"/src/web/src/ok.ts","messages":[]}]`,
+			want: []Finding{
+				{File: "src/app.ts", Line: 3, Column: 7, Rule: "no-unused-vars", Severity: FindingError, Message: "'x' is unused"},
+				{File: "src/app.ts", Line: 9, Column: 12, Rule: "eqeqeq", Severity: FindingWarning, Message: "Expected '==='"},
+			},
+			wantOutput: "src/app.ts:3:7: 'x' is unused (no-unused-vars)\nsrc/app.ts:9:12: Expected '===' (eqeqeq)",
+		},
+		{
+			name:   "eslint stylish",
+			output: "\n/src/web/src/app.ts\n  3:7  error  'x' is unused  no-unused-vars\n\n✖ 1 problem (1 error, 0 warnings)\n",
+			want: []Finding{
+				{File: "src/app.ts", Line: 3, Column: 7, Rule: "no-unused-vars", Severity: FindingError, Message: "'x' is unused"},
+			},
+			wantOutput: "src/app.ts:3:7: 'x' is unused (no-unused-vars)\n\n✖ 1 problem (1 error, 0 warnings)",
+		},
+		{
+			name:   "golangci-lint text",
+			output: "db/conn.go:42:7: Error return value is not checked (errcheck)\n\trows.Close()\n\t^\nmain.go:3: missing doc",
+			want: []Finding{
+				{File: "db/conn.go", Line: 42, Column: 7, Rule: "errcheck", Severity: FindingError, Message: "Error return value is not checked"},
+				{File: "main.go", Line: 3, Severity: FindingError, Message: "missing doc"},
+			},
+			wantOutput: "db/conn.go:42:7: Error return value is not checked (errcheck)\n\trows.Close()\n\t^\nmain.go:3: missing doc",
+		},
+	} {
+		got, output := ParseFindings(tt.output, root)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: findings = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if output != tt.wantOutput {
+			t.Errorf("%s: output = %q, want %q", tt.name, output, tt.wantOutput)
+		}
+	}
+
+	if got, output := ParseFindings("exit status 3", root); got != nil || output != "exit status 3" {
+		t.Errorf("no findings: %+v, %q", got, output)
+	}
+}
+
+func TestApplyFindings(t *testing.T) {
+	dir := t.TempDir()
+	lint := `{"Issues":[{"FromLinter":"errcheck","Text":"unchecked","Pos":{"Filename":"a.go","Line":1,"Column":1}},` +
+		`{"FromLinter":"unused","Text":"unused","Pos":{"Filename":"b.go","Line":2,"Column":1}}]}`
+	results := []Result{
+		{ID: "go.golangci_lint", Name: "Go: golangci-lint", Path: "svc", Output: lint, Error: errors.New("exit status 1")},
+		{ID: "go.test", Name: "Go: tests", Output: "a.go:1:1: x"},
+	}
+	ApplyFindings(dir, results)
+	if len(results[0].Findings) != 2 || results[0].Output != "a.go:1:1: unchecked (errcheck)\nb.go:2:1: unused (unused)" {
+		t.Errorf("lint = %+v", results[0])
+	}
+	if results[1].Findings != nil {
+		t.Errorf("tests got findings: %+v", results[1].Findings)
+	}
+
+	// Waived findings are dropped
+	ApplyWaivers(dir, results, []config.Waiver{{ID: "go.golangci_lint", Match: "errcheck"}}, time.Now())
+	if f := results[0].Findings; len(f) != 1 || f[0].File != "b.go" {
+		t.Errorf("findings after waivers = %+v", f)
+	}
+
+	vr := &ValidationReport{Areas: []AreaResult{{Area: AreaQA, Status: StatusNoGo, Results: results[:1]}}}
+	annotations := vr.CheckRun("atrelease validate", "abc123").Annotations
+	if len(annotations) != 1 || annotations[0].Path != "svc/b.go" || annotations[0].StartLine != 2 ||
+		annotations[0].Title != "Go: golangci-lint: unused" || annotations[0].Level != "failure" {
+		t.Errorf("annotations = %+v", annotations)
+	}
+}
@@ -0,0 +1,220 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// FailedTest is a failed test in the output of a test check.
+type FailedTest struct {
+	Name    string // e.g. "TestUpload" or "client > retries on 503"
+	Package string // Go package, if known
+}
+
+var (
+	// goFailLine matches a failed top-level Go test; subtests are indented
+	goFailLine = regexp.MustCompile(`^--- FAIL: (\S+)`)
+	// goFailPackage matches the summary of a failed Go package, e.g.
+	// "FAIL\texample.com/m/pkg\t0.01s" or "FAIL\texample.com/m/pkg [build failed]"
+	goFailPackage = regexp.MustCompile(`^FAIL\s+(\S+)(?:\s+(.*))?$`)
+	// vitestFailLine matches "FAIL  src/a.test.ts > suite > name"
+	vitestFailLine = regexp.MustCompile(`^\s*FAIL\s+\S+\s+>\s+(.+)$`)
+	// vitestSuiteFail matches a test file that failed to load, e.g.
+	// "FAIL  src/a.test.ts [ src/a.test.ts ]"
+	vitestSuiteFail = regexp.MustCompile(`^\s*FAIL\s+(\S+)\s+\[`)
+	// jestFailLine matches "● suite › name"
+	jestFailLine = regexp.MustCompile(`^\s*● (.+)$`)
+)
+
+// ParseTestFailures returns the failed tests in the output of the test
+// check with the given ID. Failures that aren't of a single test, such as
+// a package that doesn't build, are returned with a name no test pattern
+// is meant to match, e.g. "example.com/m/pkg [build failed]".
+func ParseTestFailures(id, output string) []FailedTest {
+	if strings.HasPrefix(id, "go.") {
+		return parseGoTestFailures(output)
+	}
+	return parseJSTestFailures(output)
+}
+
+func parseGoTestFailures(output string) []FailedTest {
+	var failures, pending []FailedTest
+	for _, line := range strings.Split(output, "\n") {
+		if m := goFailLine.FindStringSubmatch(line); m != nil {
+			pending = append(pending, FailedTest{Name: m[1]})
+			continue
+		}
+		m := goFailPackage.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		if len(pending) == 0 {
+			// The package failed without a failed test, e.g. it doesn't
+			// build or TestMain exited
+			failures = append(failures, FailedTest{Name: strings.TrimSpace(m[1] + " " + m[2]), Package: m[1]})
+			continue
+		}
+		for _, f := range pending {
+			f.Package = m[1]
+			failures = append(failures, f)
+		}
+		pending = nil
+	}
+	return append(failures, pending...)
+}
+
+func parseJSTestFailures(output string) []FailedTest {
+	var failures []FailedTest
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			failures = append(failures, FailedTest{Name: name})
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if m := vitestFailLine.FindStringSubmatch(line); m != nil {
+			add(strings.TrimSpace(m[1]))
+		} else if m := vitestSuiteFail.FindStringSubmatch(line); m != nil {
+			add(m[1] + " [failed to run]")
+		} else if m := jestFailLine.FindStringSubmatch(line); m != nil {
+			name := strings.TrimSpace(m[1])
+			switch {
+			case name == "Console":
+			case name == "Test suite failed to run":
+				add("[test suite failed to run]")
+			default:
+				add(strings.ReplaceAll(name, " › ", " > "))
+			}
+		}
+	}
+	return failures
+}
+
+// matchTestName reports whether name matches pattern, in which * matches
+// any text, including "/" and " > ".
+func matchTestName(pattern, name string) bool {
+	expr := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `.*`)
+	ok, _ := regexp.MatchString("^"+expr+"$", name)
+	return ok
+}
+
+// quarantineEntry returns the flaky test entry for a failed test of the
+// check with the given ID.
+func quarantineEntry(id string, f FailedTest, flaky []config.FlakyTest) (config.FlakyTest, bool) {
+	for _, q := range flaky {
+		if (q.ID == "" || MatchID(id, []string{q.ID})) && matchTestName(q.Test, f.Name) {
+			return q, true
+		}
+	}
+	return config.FlakyTest{}, false
+}
+
+// isTestCheck reports whether the check with the given ID runs a test
+// suite, e.g. go.test or ts.test.
+func isTestCheck(id string) bool {
+	return strings.HasSuffix(id, ".test")
+}
+
+// RerunFunc reruns the given failed tests of a test check result and
+// reports whether they all passed.
+type RerunFunc func(r Result, tests []FailedTest) bool
+
+// ApplyFlaky applies the quarantine list to failed test checks. If every
+// failed test of a check is quarantined, they are rerun with rerun up to
+// retries times: if they pass the check passes, noting the flaky tests,
+// and if they keep failing it warns, linking their tracking issues. A
+// check with any other failed test still fails, listing which failures
+// are new. A nil rerun doesn't rerun tests.
+func ApplyFlaky(results []Result, flaky []config.FlakyTest, retries int, rerun RerunFunc) {
+	if len(flaky) == 0 {
+		return
+	}
+	for i, r := range results {
+		id := ResultID(r)
+		if !isTestCheck(id) || r.Severity() != SeverityFailed {
+			continue
+		}
+		failures := ParseTestFailures(id, r.Output)
+		if len(failures) == 0 {
+			continue
+		}
+
+		var quarantined, unexpected []string
+		entries := make([]config.FlakyTest, 0, len(failures))
+		for _, f := range failures {
+			if q, ok := quarantineEntry(id, f, flaky); ok {
+				quarantined = append(quarantined, f.Name+flakyNote(q))
+				entries = append(entries, q)
+			} else {
+				unexpected = append(unexpected, f.Name)
+			}
+		}
+		if len(quarantined) == 0 {
+			continue
+		}
+
+		if len(unexpected) > 0 {
+			results[i].Output = fmt.Sprintf("Failed tests not in the quarantine list:\n%s\nQuarantined:\n%s\n\n%s",
+				strings.Join(unexpected, "\n"), strings.Join(quarantined, "\n"), r.Output)
+			continue
+		}
+
+		if rerun != nil {
+			for attempt := 1; attempt <= retries; attempt++ {
+				if rerun(r, failures) {
+					results[i].Passed = true
+					results[i].Error = nil
+					results[i].Output = fmt.Sprintf("Quarantined tests passed on retry %d of %d:\n%s\n\n%s",
+						attempt, retries, strings.Join(quarantined, "\n"), r.Output)
+					break
+				}
+			}
+			if results[i].Passed {
+				continue
+			}
+		}
+
+		results[i].Warning = true
+		summary := "Quarantined tests failed"
+		if rerun != nil && retries > 0 {
+			summary = fmt.Sprintf("Quarantined tests still failed after %d retries", retries)
+		}
+		results[i].Output = fmt.Sprintf("%s:\n%s\n\n%s", summary, strings.Join(quarantined, "\n"), r.Output)
+		results[i].Remediation = flakyRemediation(entries)
+	}
+}
+
+// flakyNote describes a quarantined test, e.g.
+// " (flaky: https://github.com/org/repo/issues/12)".
+func flakyNote(q config.FlakyTest) string {
+	var parts []string
+	if q.Issue != "" {
+		parts = append(parts, q.Issue)
+	}
+	if q.Reason != "" {
+		parts = append(parts, q.Reason)
+	}
+	if len(parts) == 0 {
+		return " (flaky)"
+	}
+	return " (flaky: " + strings.Join(parts, "; ") + ")"
+}
+
+// flakyRemediation points to the tracking issues of entries.
+func flakyRemediation(entries []config.FlakyTest) string {
+	var issues []string
+	for _, q := range entries {
+		if q.Issue != "" && !slices.Contains(issues, q.Issue) {
+			issues = append(issues, q.Issue)
+		}
+	}
+	if len(issues) == 0 {
+		return "fix the quarantined flaky tests"
+	}
+	return "fix the flaky tests tracked in " + strings.Join(issues, ", ")
+}
@@ -0,0 +1,117 @@
+package checks
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+const goTestOutput = `--- FAIL: TestUpload (0.20s)
+    upload_test.go:42: connection reset
+--- FAIL: TestRetry (0.01s)
+    --- FAIL: TestRetry/backoff (0.01s)
+        retry_test.go:9: timeout
+FAIL
+FAIL	example.com/m/client	0.215s
+FAIL	example.com/m/server [build failed]
+ok  	example.com/m/store	0.010s
+FAIL`
+
+func TestParseTestFailures(t *testing.T) {
+	got := ParseTestFailures("go.test", goTestOutput)
+	want := []FailedTest{
+		{Name: "TestUpload", Package: "example.com/m/client"},
+		{Name: "TestRetry", Package: "example.com/m/client"},
+		{Name: "example.com/m/server [build failed]", Package: "example.com/m/server"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("go: got %+v, want %+v", got, want)
+	}
+
+	vitest := " FAIL  src/client.test.ts > client > retries on 503\n" +
+		" FAIL  src/broken.test.ts [ src/broken.test.ts ]\n"
+	jest := "  ● client › retries on 503\n\n  ● Console\n\n  ● Test suite failed to run\n"
+	for output, want := range map[string][]string{
+		vitest: {"client > retries on 503", "src/broken.test.ts [failed to run]"},
+		jest:   {"client > retries on 503", "[test suite failed to run]"},
+	} {
+		var names []string
+		for _, f := range ParseTestFailures("ts.test", output) {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("ts: got %q, want %q", names, want)
+		}
+	}
+}
+
+func TestRerunArgs(t *testing.T) {
+	tests := []FailedTest{{Name: "TestUpload", Package: "example.com/m/client"}, {Name: "TestRetry", Package: "example.com/m/client"}}
+	got := strings.Join(rerunArgs("go.test", t.TempDir(), tests), " ")
+	if want := "go test -count=1 -run ^(TestUpload|TestRetry)$ example.com/m/client"; got != want {
+		t.Errorf("go: got %q, want %q", got, want)
+	}
+
+	got = strings.Join(rerunArgs("ts.test", t.TempDir(), []FailedTest{{Name: "client > retries (503)"}}), " ")
+	if want := `npm test -- -t client retries \(503\)`; got != want {
+		t.Errorf("ts: got %q, want %q", got, want)
+	}
+}
+
+func TestApplyFlaky(t *testing.T) {
+	flaky := []config.FlakyTest{
+		{ID: "go.test", Test: "TestUpload", Issue: "https://github.com/org/repo/issues/12"},
+		{Test: "TestRetry*", Reason: "timing"},
+	}
+	failed := func(output string) []Result {
+		return []Result{{Name: "Go: tests", Output: output, Error: errors.New("exit status 1")}}
+	}
+	quarantinedOnly := "--- FAIL: TestUpload (0.20s)\n--- FAIL: TestRetry (0.01s)\nFAIL\nFAIL\texample.com/m/client\t0.2s"
+
+	// Flaky tests that pass on a retry pass the check
+	results := failed(quarantinedOnly)
+	attempts := 0
+	ApplyFlaky(results, flaky, 2, func(r Result, tests []FailedTest) bool {
+		attempts++
+		if len(tests) != 2 {
+			t.Errorf("rerun %d tests, want 2", len(tests))
+		}
+		return attempts == 2
+	})
+	if r := results[0]; !r.Passed || r.Error != nil || !strings.HasPrefix(r.Output, "Quarantined tests passed on retry 2 of 2:\n") {
+		t.Errorf("passed on retry: %+v", r)
+	}
+
+	// Flaky tests that keep failing warn, linking their issue
+	results = failed(quarantinedOnly)
+	ApplyFlaky(results, flaky, 2, func(Result, []FailedTest) bool { return false })
+	r := results[0]
+	if r.Severity() != SeverityWarning || !strings.Contains(r.Output, "still failed after 2 retries") ||
+		!strings.Contains(r.Output, "TestUpload (flaky: https://github.com/org/repo/issues/12)") ||
+		!strings.Contains(r.Output, "TestRetry (flaky: timing)") ||
+		r.Remediation != "fix the flaky tests tracked in https://github.com/org/repo/issues/12" {
+		t.Errorf("still failing: %+v", r)
+	}
+
+	// Any other failure still fails, without rerunning
+	results = failed(goTestOutput)
+	ApplyFlaky(results, flaky, 2, func(Result, []FailedTest) bool {
+		t.Error("reran tests with a failure not in the quarantine list")
+		return true
+	})
+	r = results[0]
+	if r.Severity() != SeverityFailed ||
+		!strings.HasPrefix(r.Output, "Failed tests not in the quarantine list:\nexample.com/m/server [build failed]\nQuarantined:\n") {
+		t.Errorf("new failure: %+v", r)
+	}
+
+	// Other checks are left alone
+	results = []Result{{Name: "Go: lint", Output: quarantinedOnly}}
+	ApplyFlaky(results, flaky, 2, nil)
+	if results[0].Output != quarantinedOnly {
+		t.Errorf("lint result changed: %+v", results[0])
+	}
+}
@@ -0,0 +1,155 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// weekdays maps three-letter day names to time.Weekday.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ActiveFreeze returns the first of windows in effect at t, or nil when
+// releases aren't frozen.
+func ActiveFreeze(windows []config.FreezeWindow, t time.Time) (*config.FreezeWindow, error) {
+	for i := range windows {
+		active, err := freezeActive(windows[i], t)
+		if err != nil {
+			return nil, err
+		}
+		if active {
+			return &windows[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// FreezeLabel describes a freeze window for messages: its name, or its
+// range when it has none.
+func FreezeLabel(w config.FreezeWindow) string {
+	switch {
+	case w.Name != "":
+		return w.Name
+	case w.Weekly != "":
+		return w.Weekly
+	}
+	return w.Start + " to " + w.End
+}
+
+// freezeActive reports whether t falls in w.
+func freezeActive(w config.FreezeWindow, t time.Time) (bool, error) {
+	loc := time.Local
+	if w.Timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return false, fmt.Errorf("freeze window %q: %w", FreezeLabel(w), err)
+		}
+	}
+	t = t.In(loc)
+
+	if w.Weekly != "" {
+		start, end, err := parseWeeklyRange(w.Weekly)
+		if err != nil {
+			return false, fmt.Errorf("freeze window %q: %w", FreezeLabel(w), err)
+		}
+		now := int(t.Weekday())*24*60 + t.Hour()*60 + t.Minute()
+		if start <= end {
+			return now >= start && now < end, nil
+		}
+		// The range wraps around the end of the week
+		return now >= start || now < end, nil
+	}
+
+	if w.Start == "" || w.End == "" {
+		return false, fmt.Errorf("freeze window %q: set weekly, or start and end", FreezeLabel(w))
+	}
+	start, _, err := parseFreezeTime(w.Start, loc)
+	if err != nil {
+		return false, fmt.Errorf("freeze window %q: start: %w", FreezeLabel(w), err)
+	}
+	end, dateOnly, err := parseFreezeTime(w.End, loc)
+	if err != nil {
+		return false, fmt.Errorf("freeze window %q: end: %w", FreezeLabel(w), err)
+	}
+	if dateOnly {
+		end = end.AddDate(0, 0, 1)
+	}
+	return !t.Before(start) && t.Before(end), nil
+}
+
+// parseWeeklyRange parses "Fri 16:00-Mon 08:00" into minutes since Sunday
+// 00:00 of its start and end. An en dash may separate the two.
+func parseWeeklyRange(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(strings.ReplaceAll(s, "–", "-"), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("weekly range %q: expected \"Day HH:MM-Day HH:MM\"", s)
+	}
+	if start, err = parseWeekTime(from); err != nil {
+		return 0, 0, err
+	}
+	if end, err = parseWeekTime(to); err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+// parseWeekTime parses "Fri 16:00" into minutes since Sunday 00:00.
+func parseWeekTime(s string) (int, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("%q: expected \"Day HH:MM\"", strings.TrimSpace(s))
+	}
+	day, ok := weekdays[strings.ToLower(fields[0])[:min(3, len(fields[0]))]]
+	if !ok {
+		return 0, fmt.Errorf("%q: unknown day %q", strings.TrimSpace(s), fields[0])
+	}
+	clock, err := time.Parse("15:04", fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("%q: invalid time %q", strings.TrimSpace(s), fields[1])
+	}
+	return int(day)*24*60 + clock.Hour()*60 + clock.Minute(), nil
+}
+
+// parseFreezeTime parses "2006-01-02 15:04" or "2006-01-02" in loc,
+// reporting whether s was a date alone.
+func parseFreezeTime(s string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, loc); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q: expected YYYY-MM-DD or YYYY-MM-DD HH:MM", s)
+	}
+	return t, true, nil
+}
+
+func (c *ReleaseChecker) checkFreeze(windows []config.FreezeWindow) Result {
+	name := "Release: freeze window"
+
+	if len(windows) == 0 {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No freeze windows configured",
+		}
+	}
+
+	w, err := ActiveFreeze(windows, time.Now())
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	if w != nil {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      fmt.Sprintf("Releases are frozen (%s)", FreezeLabel(*w)),
+			Remediation: "release after the freeze, or override it with atrelease release --force",
+		}
+	}
+	return Result{Name: name, Passed: true, Output: "Not in a freeze window"}
+}
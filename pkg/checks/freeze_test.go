@@ -0,0 +1,75 @@
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestActiveFreeze_Weekly(t *testing.T) {
+	windows := []config.FreezeWindow{{Name: "weekend", Weekly: "Fri 16:00–Mon 08:00", Timezone: "UTC"}}
+
+	tests := []struct {
+		time string
+		want bool
+	}{
+		{"2025-06-13 15:59", false}, // Friday
+		{"2025-06-13 16:00", true},
+		{"2025-06-15 12:00", true}, // Sunday
+		{"2025-06-16 07:59", true}, // Monday
+		{"2025-06-16 08:00", false},
+		{"2025-06-18 12:00", false}, // Wednesday
+	}
+	for _, tt := range tests {
+		now, _ := time.Parse("2006-01-02 15:04", tt.time)
+		w, err := ActiveFreeze(windows, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := w != nil; got != tt.want {
+			t.Errorf("ActiveFreeze at %s = %v, want %v", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestActiveFreeze_DateRange(t *testing.T) {
+	windows := []config.FreezeWindow{{Start: "2025-12-20", End: "2026-01-02", Timezone: "UTC"}}
+
+	tests := []struct {
+		time string
+		want bool
+	}{
+		{"2025-12-19 23:59", false},
+		{"2025-12-20 00:00", true},
+		{"2026-01-02 23:59", true},
+		{"2026-01-03 00:00", false},
+	}
+	for _, tt := range tests {
+		now, _ := time.Parse("2006-01-02 15:04", tt.time)
+		w, err := ActiveFreeze(windows, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := w != nil; got != tt.want {
+			t.Errorf("ActiveFreeze at %s = %v, want %v", tt.time, got, tt.want)
+		}
+	}
+	if got := FreezeLabel(windows[0]); got != "2025-12-20 to 2026-01-02" {
+		t.Errorf("FreezeLabel = %q", got)
+	}
+}
+
+func TestActiveFreeze_Invalid(t *testing.T) {
+	for _, w := range []config.FreezeWindow{
+		{Weekly: "Friday afternoon"},
+		{Weekly: "Fri 16:00-Xyz 08:00"},
+		{Start: "2025-12-20"},
+		{Start: "20 Dec", End: "2026-01-02"},
+		{Weekly: "Fri 16:00-Mon 08:00", Timezone: "Nowhere/City"},
+	} {
+		if _, err := ActiveFreeze([]config.FreezeWindow{w}, time.Now()); err == nil {
+			t.Errorf("ActiveFreeze(%+v): expected an error", w)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// PluginGenerateScript is the script that regenerates plugins/ from specs/.
+const PluginGenerateScript = "scripts/generate-plugins.sh"
+
+// maxDiffLines limits the generator diff shown in check output.
+const maxDiffLines = 20
+
+func (c *ReleaseChecker) checkGeneratedPlugins(dir string) Result {
+	name := "Release: generated plugins up to date"
+
+	script := filepath.Join(dir, PluginGenerateScript)
+	if !FileExists(script) || !FileExists(filepath.Join(dir, "specs")) {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No plugin generator",
+		}
+	}
+
+	if r, ok := disallowed(name, "bash"); ok {
+		return r
+	}
+
+	result := RunCommand(name, dir, "bash", PluginGenerateScript, "--check")
+	if result.Passed {
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: "plugins/ matches specs/",
+		}
+	}
+
+	if strings.Contains(result.Output, "assistantkit not found") {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "assistantkit not installed",
+		}
+	}
+
+	lines := strings.Split(result.Output, "\n")
+	if len(lines) > maxDiffLines {
+		lines = append(lines[:maxDiffLines], fmt.Sprintf("... (%d more lines)", len(lines)-maxDiffLines))
+	}
+	return Result{
+		Name:        name,
+		Passed:      false,
+		Output:      "plugins/ is out of date with specs/\n" + strings.Join(lines, "\n"),
+		Remediation: "regenerate plugins/: ./" + PluginGenerateScript,
+	}
+}
@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeGenerateScript(t *testing.T, dir, body string) {
+	t.Helper()
+	for _, d := range []string{"scripts", "specs"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	script := "#!/usr/bin/env bash\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, PluginGenerateScript), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckGeneratedPlugins(t *testing.T) {
+	if !CommandExists("bash") {
+		t.Skip("bash not installed")
+	}
+	c := &ReleaseChecker{}
+
+	tests := []struct {
+		name        string
+		script      string
+		wantPassed  bool
+		wantSkipped bool
+		wantOutput  string
+	}{
+		{"up to date", `echo "plugins/ is up to date with specs/"`, true, false, ""},
+		{"stale", `echo "-old"; echo "+new"; exit 1`, false, false, "+new"},
+		{"no generator", `echo "Error: assistantkit not found. Install it"; exit 1`, false, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeGenerateScript(t, dir, tt.script)
+
+			result := c.checkGeneratedPlugins(dir)
+			if result.Passed != tt.wantPassed || result.Skipped != tt.wantSkipped {
+				t.Errorf("got %+v, want passed=%v skipped=%v", result, tt.wantPassed, tt.wantSkipped)
+			}
+			if !strings.Contains(result.Output, tt.wantOutput) {
+				t.Errorf("expected output to contain %q, got %q", tt.wantOutput, result.Output)
+			}
+		})
+	}
+}
+
+func TestCheckGeneratedPlugins_NoScript(t *testing.T) {
+	c := &ReleaseChecker{}
+	result := c.checkGeneratedPlugins(t.TempDir())
+	if !result.Skipped {
+		t.Errorf("expected skipped result, got %+v", result)
+	}
+}
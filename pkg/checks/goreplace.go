@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// goReplaceName is the name of the replace directive check, the same as
+// releasekit's so the result keeps its ID.
+const goReplaceName = "Go: no local replace directives"
+
+// GoReplace is a replace directive in go.mod.
+type GoReplace struct {
+	Module        string // Module path replaced
+	Version       string // Version replaced; empty for all versions
+	Target        string // Module path or local directory it is replaced with
+	TargetVersion string // Version of Target; empty for a local directory
+}
+
+// Local reports whether r replaces a module with a local directory.
+func (r GoReplace) Local() bool {
+	return r.TargetVersion == ""
+}
+
+// String formats r as written in go.mod.
+func (r GoReplace) String() string {
+	s := r.Module
+	if r.Version != "" {
+		s += " " + r.Version
+	}
+	s += " => " + r.Target
+	if r.TargetVersion != "" {
+		s += " " + r.TargetVersion
+	}
+	return s
+}
+
+// Allowed reports whether r's module or target matches one of patterns,
+// which may use path.Match wildcards, e.g. "github.com/acme/*" or "../*".
+func (r GoReplace) Allowed(patterns []string) bool {
+	for _, p := range patterns {
+		for _, s := range []string{r.Module, r.Target} {
+			if ok, _ := path.Match(p, s); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GoReplaces returns the replace directives in the go.mod of dir.
+func GoReplaces(dir string) ([]GoReplace, error) {
+	cmd := proc.Command("go", "mod", "edit", "-json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go mod edit -json: %w", err)
+	}
+	var mod struct {
+		Replace []struct {
+			Old, New struct{ Path, Version string }
+		}
+	}
+	if err := json.Unmarshal(output, &mod); err != nil {
+		return nil, fmt.Errorf("parsing go mod edit -json output: %w", err)
+	}
+	replaces := make([]GoReplace, 0, len(mod.Replace))
+	for _, r := range mod.Replace {
+		replaces = append(replaces, GoReplace{
+			Module:        r.Old.Path,
+			Version:       r.Old.Version,
+			Target:        r.New.Path,
+			TargetVersion: r.New.Version,
+		})
+	}
+	return replaces, nil
+}
+
+// CheckGoReplaces checks that the go.mod of dir has no local replace
+// directives other than those allowed, which break builds outside the
+// checkout. Version pins are listed but always pass.
+func CheckGoReplaces(dir string, allow []string) Result {
+	name := goReplaceName
+	if !FileExists(filepath.Join(dir, "go.mod")) {
+		return Result{Name: name, Path: dir, Skipped: true, Reason: "Not a Go project"}
+	}
+	if r, ok := disallowed(name, "go"); ok {
+		r.Path = dir
+		return r
+	}
+
+	start := time.Now()
+	replaces, err := GoReplaces(dir)
+	if err != nil {
+		return Result{Name: name, Path: dir, Error: err, Duration: time.Since(start)}
+	}
+
+	var lines []string
+	denied := 0
+	for _, r := range replaces {
+		switch {
+		case !r.Local():
+			lines = append(lines, "version pin: "+r.String())
+		case r.Allowed(allow):
+			lines = append(lines, "local (allowed): "+r.String())
+		default:
+			lines = append(lines, "local: "+r.String())
+			denied++
+		}
+	}
+	if denied > 0 {
+		lines = append([]string{fmt.Sprintf("local replace directives found (%d)", denied)}, lines...)
+	}
+	return Result{
+		Name:     name,
+		Path:     dir,
+		Passed:   denied == 0,
+		Output:   strings.Join(lines, "\n"),
+		Duration: time.Since(start),
+
+		Remediation: "remove the local replace directives from go.mod, or allow them in languages.go.allow_replace",
+	}
+}
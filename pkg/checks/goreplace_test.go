@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const replaceGoMod = `module example.com/app
+
+go 1.21
+
+replace example.com/shared => ../shared
+
+replace example.com/tool => ./tools/tool
+
+replace example.com/lib v1.0.0 => example.com/fork v1.2.0
+`
+
+func TestGoReplaces(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), replaceGoMod)
+
+	replaces, err := GoReplaces(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replaces) != 3 {
+		t.Fatalf("got %d replaces, want 3: %+v", len(replaces), replaces)
+	}
+	if !replaces[0].Local() || replaces[2].Local() {
+		t.Errorf("expected only path replaces to be local: %+v", replaces)
+	}
+	if got := replaces[2].String(); got != "example.com/lib v1.0.0 => example.com/fork v1.2.0" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestCheckGoReplaces(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), replaceGoMod)
+
+	r := CheckGoReplaces(dir, []string{"../*"})
+	if r.Passed {
+		t.Fatalf("expected the unallowed local replace to fail, got %+v", r)
+	}
+	for _, want := range []string{
+		"local replace directives found (1)",
+		"local (allowed): example.com/shared => ../shared",
+		"local: example.com/tool => ./tools/tool",
+		"version pin: example.com/lib v1.0.0 => example.com/fork v1.2.0",
+	} {
+		if !strings.Contains(r.Output, want) {
+			t.Errorf("output missing %q:\n%s", want, r.Output)
+		}
+	}
+
+	if r := CheckGoReplaces(dir, []string{"../*", "example.com/tool"}); !r.Passed {
+		t.Errorf("expected allowed local replaces to pass, got %+v", r)
+	}
+	if r := CheckGoReplaces(t.TempDir(), nil); !r.Skipped {
+		t.Errorf("expected a directory without go.mod to be skipped, got %+v", r)
+	}
+}
@@ -0,0 +1,236 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// slowestTests is the number of slowest tests a TestSummary lists.
+const slowestTests = 5
+
+// TestSummary summarizes a go test -json run: the test counts of each
+// package, the failed tests, and the slowest tests.
+type TestSummary struct {
+	Passed      int            `json:"passed" toon:"passed"`
+	Failed      int            `json:"failed" toon:"failed"`
+	Skipped     int            `json:"skipped" toon:"skipped"`
+	Packages    []PackageTests `json:"packages" toon:"packages"`
+	FailedTests []string       `json:"failed_tests,omitempty" toon:"failed_tests,omitempty"` // e.g. "example.com/m/pkg.TestParse/empty"
+	Slowest     []TestTiming   `json:"slowest,omitempty" toon:"slowest,omitempty"`           // Top-level tests, slowest first
+}
+
+// PackageTests are the test counts of a package.
+type PackageTests struct {
+	Package     string `json:"package" toon:"package"`
+	Passed      int    `json:"passed" toon:"passed"`
+	Failed      int    `json:"failed" toon:"failed"`
+	Skipped     int    `json:"skipped" toon:"skipped"`
+	ElapsedMs   int64  `json:"elapsed_ms" toon:"elapsed_ms"`
+	BuildFailed bool   `json:"build_failed,omitempty" toon:"build_failed,omitempty"`
+}
+
+// TestTiming is how long a test took.
+type TestTiming struct {
+	Test      string `json:"test" toon:"test"` // e.g. "example.com/m/pkg.TestParse"
+	ElapsedMs int64  `json:"elapsed_ms" toon:"elapsed_ms"`
+}
+
+// String returns the summary as report lines: the counts, then the failed
+// and slowest tests, e.g.
+//
+//	38 passed, 1 failed, 2 skipped in 6 packages
+//	Failed: example.com/m/pkg.TestParse
+//	Slowest: example.com/m/db.TestMigrate (4.2s), ...
+func (s TestSummary) String() string {
+	lines := []string{fmt.Sprintf("%d passed, %d failed, %d skipped in %d packages",
+		s.Passed, s.Failed, s.Skipped, len(s.Packages))}
+	var builds []string
+	for _, p := range s.Packages {
+		if p.BuildFailed {
+			builds = append(builds, p.Package)
+		}
+	}
+	if len(builds) > 0 {
+		lines = append(lines, "Build failed: "+strings.Join(builds, ", "))
+	}
+	if len(s.FailedTests) > 0 {
+		lines = append(lines, "Failed: "+strings.Join(s.FailedTests, ", "))
+	}
+	if len(s.Slowest) > 0 {
+		slowest := make([]string, len(s.Slowest))
+		for i, t := range s.Slowest {
+			slowest[i] = fmt.Sprintf("%s (%s)", t.Test, time.Duration(t.ElapsedMs)*time.Millisecond)
+		}
+		lines = append(lines, "Slowest: "+strings.Join(slowest, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// testEvent is an event of go test -json, as documented by go doc
+// test2json.
+type testEvent struct {
+	Action      string
+	Package     string
+	Test        string
+	Elapsed     float64 // Seconds
+	Output      string
+	FailedBuild string // Package whose build failed, on a package's fail event
+}
+
+// testOutput is a line of output of a go test -json run.
+type testOutput struct {
+	test string // Package and test of test output; "" for other output
+	line string
+}
+
+// GoTestParser collects go test -json output, possibly of several go
+// test processes, into a TestSummary and the text go test would have
+// printed without -json and -v.
+type GoTestParser struct {
+	packages map[string]*PackageTests
+	failed   map[string]bool // Failed tests, by package and name
+	timings  []TestTiming
+	outputs  []testOutput
+}
+
+// NewGoTestParser returns an empty parser.
+func NewGoTestParser() *GoTestParser {
+	return &GoTestParser{packages: make(map[string]*PackageTests), failed: make(map[string]bool)}
+}
+
+// Parse adds the output of a go test -json process. Lines that aren't
+// events, such as build errors printed by older Go versions, are kept as
+// text.
+func (p *GoTestParser) Parse(output []byte) {
+	sc := bufio.NewScanner(bytes.NewReader(output))
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		var ev testEvent
+		if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &ev) != nil || ev.Action == "" {
+			p.outputs = append(p.outputs, testOutput{line: line})
+			continue
+		}
+		p.event(ev)
+	}
+}
+
+func (p *GoTestParser) pkg(name string) *PackageTests {
+	pt, ok := p.packages[name]
+	if !ok {
+		pt = &PackageTests{Package: name}
+		p.packages[name] = pt
+	}
+	return pt
+}
+
+func (p *GoTestParser) event(ev testEvent) {
+	key := ev.Package
+	if ev.Test != "" {
+		key += "." + ev.Test
+	}
+	switch ev.Action {
+	case "build-output":
+		p.outputs = append(p.outputs, testOutput{line: strings.TrimSuffix(ev.Output, "\n")})
+	case "output":
+		// Without -v, go test doesn't print the progress of tests
+		out := strings.TrimSuffix(ev.Output, "\n")
+		if isTestProgress(out) || ev.Test == "" && out == "PASS" {
+			return
+		}
+		o := testOutput{line: out}
+		if ev.Test != "" {
+			o.test = key
+		}
+		p.outputs = append(p.outputs, o)
+	case "pass", "fail", "skip":
+		pt := p.pkg(ev.Package)
+		if ev.Test == "" {
+			pt.ElapsedMs = int64(ev.Elapsed * 1000)
+			pt.BuildFailed = pt.BuildFailed || ev.FailedBuild != ""
+			return
+		}
+		switch ev.Action {
+		case "pass":
+			pt.Passed++
+		case "fail":
+			pt.Failed++
+			p.failed[key] = true
+		case "skip":
+			pt.Skipped++
+		}
+		if !strings.Contains(ev.Test, "/") {
+			p.timings = append(p.timings, TestTiming{Test: key, ElapsedMs: int64(ev.Elapsed * 1000)})
+		}
+	}
+}
+
+// isTestProgress reports whether line is test progress go test prints
+// only with -v, such as "=== RUN   TestParse" or "--- PASS: TestParse".
+func isTestProgress(line string) bool {
+	trimmed := strings.TrimLeft(line, " ")
+	for _, prefix := range []string{"=== RUN", "=== PAUSE", "=== CONT", "=== NAME", "--- PASS:", "--- SKIP:"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary returns the summary of the runs parsed so far. Subtests are
+// counted as tests.
+func (p *GoTestParser) Summary() TestSummary {
+	var s TestSummary
+	for _, pt := range p.packages {
+		s.Passed += pt.Passed
+		s.Failed += pt.Failed
+		s.Skipped += pt.Skipped
+		s.Packages = append(s.Packages, *pt)
+	}
+	slices.SortFunc(s.Packages, func(a, b PackageTests) int { return strings.Compare(a.Package, b.Package) })
+	for key := range p.failed {
+		s.FailedTests = append(s.FailedTests, key)
+	}
+	slices.Sort(s.FailedTests)
+
+	timings := slices.Clone(p.timings)
+	slices.SortStableFunc(timings, func(a, b TestTiming) int {
+		if a.ElapsedMs != b.ElapsedMs {
+			return int(b.ElapsedMs - a.ElapsedMs)
+		}
+		return strings.Compare(a.Test, b.Test)
+	})
+	s.Slowest = timings[:min(len(timings), slowestTests)]
+	return s
+}
+
+// Text returns the output of the runs parsed so far as go test prints it
+// without -v: the output of packages and of failed tests.
+func (p *GoTestParser) Text() string {
+	var lines []string
+	for _, o := range p.outputs {
+		if o.test != "" && !p.failed[o.test] {
+			continue
+		}
+		lines = append(lines, o.line)
+	}
+	// Keep the indentation of subtest output on the first line
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Output returns the output of a result for the runs parsed so far, the
+// summary followed by Text, and the summary. Without any test events,
+// such as when go test couldn't start, it returns just the text and a nil
+// summary.
+func (p *GoTestParser) Output() (string, *TestSummary) {
+	if len(p.packages) == 0 {
+		return p.Text(), nil
+	}
+	s := p.Summary()
+	return strings.TrimRight(s.String()+"\n\n"+p.Text(), "\n"), &s
+}
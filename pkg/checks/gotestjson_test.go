@@ -0,0 +1,79 @@
+package checks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const goTestEvents = `{"Action":"start","Package":"m/a"}
+{"Action":"run","Package":"m/a","Test":"TestParse"}
+{"Action":"output","Package":"m/a","Test":"TestParse","Output":"=== RUN   TestParse\n"}
+{"Action":"output","Package":"m/a","Test":"TestParse","Output":"    a_test.go:5: checking\n"}
+{"Action":"output","Package":"m/a","Test":"TestParse","Output":"--- PASS: TestParse (1.50s)\n"}
+{"Action":"pass","Package":"m/a","Test":"TestParse","Elapsed":1.5}
+{"Action":"output","Package":"m/a","Test":"TestSplit/empty","Output":"    --- FAIL: TestSplit/empty (0.00s)\n"}
+{"Action":"output","Package":"m/a","Test":"TestSplit/empty","Output":"        a_test.go:12: got 1\n"}
+{"Action":"fail","Package":"m/a","Test":"TestSplit/empty","Elapsed":0}
+{"Action":"output","Package":"m/a","Test":"TestSplit","Output":"--- FAIL: TestSplit (0.20s)\n"}
+{"Action":"fail","Package":"m/a","Test":"TestSplit","Elapsed":0.2}
+{"Action":"output","Package":"m/a","Test":"TestSlow","Output":"--- SKIP: TestSlow (0.00s)\n"}
+{"Action":"skip","Package":"m/a","Test":"TestSlow","Elapsed":0}
+{"Action":"output","Package":"m/a","Output":"FAIL\n"}
+{"Action":"output","Package":"m/a","Output":"FAIL\tm/a\t1.8s\n"}
+{"Action":"fail","Package":"m/a","Elapsed":1.8}
+`
+
+const goTestEventsShard2 = `# m/b
+b/b.go:3:1: syntax error
+{"Action":"output","Package":"m/b","Output":"FAIL\tm/b [build failed]\n"}
+{"Action":"fail","Package":"m/b","Elapsed":0,"FailedBuild":"m/b"}
+{"Action":"pass","Package":"m/c","Test":"TestC","Elapsed":0.01}
+{"Action":"output","Package":"m/c","Output":"PASS\n"}
+{"Action":"output","Package":"m/c","Output":"ok  \tm/c\t0.02s\tcoverage: 50.0% of statements\n"}
+{"Action":"pass","Package":"m/c","Elapsed":0.02}
+`
+
+func TestGoTestParser(t *testing.T) {
+	p := NewGoTestParser()
+	p.Parse([]byte(goTestEvents))
+	p.Parse([]byte(goTestEventsShard2))
+
+	s := p.Summary()
+	if s.Passed != 2 || s.Failed != 2 || s.Skipped != 1 || len(s.Packages) != 3 {
+		t.Errorf("counts = %+v", s)
+	}
+	if a := s.Packages[0]; a.Package != "m/a" || a.Failed != 2 || a.ElapsedMs != 1800 || !s.Packages[1].BuildFailed {
+		t.Errorf("packages = %+v", s.Packages)
+	}
+	if want := []string{"m/a.TestSplit", "m/a.TestSplit/empty"}; !reflect.DeepEqual(s.FailedTests, want) {
+		t.Errorf("failed = %v, want %v", s.FailedTests, want)
+	}
+	if want := []TestTiming{{"m/a.TestParse", 1500}, {"m/a.TestSplit", 200}, {"m/c.TestC", 10}, {"m/a.TestSlow", 0}}; !reflect.DeepEqual(s.Slowest, want) {
+		t.Errorf("slowest = %v, want %v", s.Slowest, want)
+	}
+
+	// Progress, passed tests, and PASS lines are left out, as without -v
+	wantText := "    --- FAIL: TestSplit/empty (0.00s)\n        a_test.go:12: got 1\n--- FAIL: TestSplit (0.20s)\n" +
+		"FAIL\nFAIL\tm/a\t1.8s\n# m/b\nb/b.go:3:1: syntax error\nFAIL\tm/b [build failed]\n" +
+		"ok  \tm/c\t0.02s\tcoverage: 50.0% of statements"
+	if got := p.Text(); got != wantText {
+		t.Errorf("text = %q, want %q", got, wantText)
+	}
+
+	output, summary := p.Output()
+	if summary == nil || !strings.HasPrefix(output, "2 passed, 2 failed, 1 skipped in 3 packages\nBuild failed: m/b\nFailed: m/a.TestSplit, m/a.TestSplit/empty\n"+
+		"Slowest: m/a.TestParse (1.5s), m/a.TestSplit (200ms), m/c.TestC (10ms), m/a.TestSlow (0s)\n\n") {
+		t.Errorf("output = %q", output)
+	}
+	// Failures are still found in the text
+	if got := ParseTestFailures("go.test", output); len(got) != 2 || got[0].Name != "TestSplit" || got[1].Package != "m/b" {
+		t.Errorf("failures = %+v", got)
+	}
+
+	p = NewGoTestParser()
+	p.Parse([]byte("go: cannot find main module\n"))
+	if output, summary := p.Output(); summary != nil || output != "go: cannot find main module" {
+		t.Errorf("without events: %q, %+v", output, summary)
+	}
+}
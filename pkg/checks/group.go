@@ -0,0 +1,160 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ResultGroup holds the results for one detection path and language, with
+// subtotals counted the way PrintResults counts them.
+type ResultGroup struct {
+	Path     string
+	Language string
+	Results  []Result
+	Passed   int
+	Failed   int
+	Skipped  int
+	Warnings int
+}
+
+// Title returns the group heading, e.g. "services/api (Go)".
+func (g ResultGroup) Title() string {
+	if g.Language == "" {
+		return g.Path
+	}
+	return fmt.Sprintf("%s (%s)", g.Path, g.Language)
+}
+
+// ResultPath returns the detection path a result belongs to, or "." when
+// the check ran against the whole directory.
+func ResultPath(r Result) string {
+	if r.Path == "" {
+		return "."
+	}
+	return r.Path
+}
+
+// RelResultPath returns the detection path of a result relative to dir,
+// with forward slashes, or "." for dir itself.
+func RelResultPath(dir string, r Result) string {
+	return filepath.ToSlash(pathInDir(dir, ResultPath(r)))
+}
+
+// ResultLanguage returns the language of a result, falling back to the
+// "Language: " prefix of its name.
+func ResultLanguage(r Result) string {
+	if r.Language != "" {
+		return r.Language
+	}
+	if lang, _, ok := strings.Cut(r.Name, ": "); ok {
+		return lang
+	}
+	return ""
+}
+
+// DedupeResults merges results that ran the same command in the same
+// detection path, such as the npm scripts checked for both JavaScript and
+// TypeScript in one directory. The first result is kept and attributed to
+// every language, e.g. "TypeScript/JavaScript: lint". Results without a
+// Command are never merged.
+func DedupeResults(results []Result) []Result {
+	out := make([]Result, 0, len(results))
+	index := make(map[[2]string]int)
+	for _, r := range results {
+		// Merged results keep the ID of the first language
+		r.ID = ResultID(r)
+		if r.Command == "" {
+			out = append(out, r)
+			continue
+		}
+		key := [2]string{ResultPath(r), r.Command}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(out)
+			out = append(out, r)
+			continue
+		}
+		out[i] = mergeLanguage(out[i], ResultLanguage(r))
+	}
+	return out
+}
+
+// mergeLanguage attributes r to lang as well as its own language.
+func mergeLanguage(r Result, lang string) Result {
+	current := ResultLanguage(r)
+	if lang == "" || current == "" || slices.Contains(strings.Split(current, "/"), lang) {
+		return r
+	}
+	merged := current + "/" + lang
+	if rest, ok := strings.CutPrefix(r.Name, current+": "); ok {
+		r.Name = merged + ": " + rest
+	}
+	r.Language = merged
+	return r
+}
+
+// GroupResults groups results by detection path and language, keeping the
+// order in which each group first appears.
+func GroupResults(results []Result) []ResultGroup {
+	var groups []ResultGroup
+	index := make(map[[2]string]int)
+	for _, r := range results {
+		key := [2]string{ResultPath(r), ResultLanguage(r)}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ResultGroup{Path: key[0], Language: key[1]})
+		}
+		g := &groups[i]
+		g.Results = append(g.Results, r)
+		switch r.Severity() {
+		case SeveritySkipped:
+			g.Skipped++
+		case SeverityPassed:
+			g.Passed++
+		case SeverityWarning:
+			g.Warnings++
+		default:
+			g.Failed++
+		}
+	}
+	return groups
+}
+
+// PrintGroupedResults prints results grouped by detection path and
+// language, with a subtotal after each group. Results from a single group
+// are printed as a flat list, exactly as PrintResults does.
+// Returns counts: passed, failed, skipped, warnings
+func PrintGroupedResults(results []Result, verbose bool) (passed int, failed int, skipped int, warnings int) {
+	groups := GroupResults(results)
+	if len(groups) <= 1 {
+		return PrintResults(results, verbose)
+	}
+
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("── %s ──\n", g.Title())
+		p, f, s, w := PrintResults(g.Results, verbose)
+		fmt.Printf("  Subtotal: %s\n", FormatCounts(p, f, s, w))
+		passed += p
+		failed += f
+		skipped += s
+		warnings += w
+	}
+	return passed, failed, skipped, warnings
+}
+
+// FormatCounts formats result counts as "Passed: 1, Failed: 0, Skipped: 0",
+// adding warnings when there are any.
+func FormatCounts(passed, failed, skipped, warnings int) string {
+	s := fmt.Sprintf("Passed: %d, Failed: %d, Skipped: %d", passed, failed, skipped)
+	if warnings > 0 {
+		s += fmt.Sprintf(", Warnings: %d", warnings)
+	}
+	return s
+}
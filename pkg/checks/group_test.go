@@ -0,0 +1,148 @@
+package checks
+
+import (
+	"testing"
+	"time"
+
+	multiagentspec "github.com/plexusone/multi-agent-spec/sdk/go"
+)
+
+func TestGroupResults(t *testing.T) {
+	results := []Result{
+		{Name: "Go: build", Passed: true, Path: "svc/api"},
+		{Name: "Go: tests", Passed: false, Path: "svc/api"},
+		{Name: "TypeScript: lint", Warning: true, Path: "web"},
+		{Name: "Go: build", Passed: true, Path: "svc/web"},
+		{Name: "Go: tests", Skipped: true, Path: "svc/web"},
+		{Name: "Go: tests for changed packages", Passed: true},
+		{Name: "custom", Passed: true, Language: "Rust", Path: "svc/api"},
+	}
+
+	groups := GroupResults(results)
+	want := []struct {
+		title                             string
+		passed, failed, skipped, warnings int
+	}{
+		{"svc/api (Go)", 1, 1, 0, 0},
+		{"web (TypeScript)", 0, 0, 0, 1},
+		{"svc/web (Go)", 1, 0, 1, 0},
+		{". (Go)", 1, 0, 0, 0},
+		{"svc/api (Rust)", 1, 0, 0, 0},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("GroupResults() returned %d groups, want %d", len(groups), len(want))
+	}
+	for i, w := range want {
+		g := groups[i]
+		if g.Title() != w.title {
+			t.Errorf("group %d title = %q, want %q", i, g.Title(), w.title)
+		}
+		if g.Passed != w.passed || g.Failed != w.failed || g.Skipped != w.skipped || g.Warnings != w.warnings {
+			t.Errorf("group %s counts = %d/%d/%d/%d, want %d/%d/%d/%d", w.title,
+				g.Passed, g.Failed, g.Skipped, g.Warnings, w.passed, w.failed, w.skipped, w.warnings)
+		}
+	}
+}
+
+func TestResultLanguage(t *testing.T) {
+	tests := []struct {
+		result Result
+		want   string
+	}{
+		{Result{Name: "Go: build"}, "Go"},
+		{Result{Name: "Go: build", Language: "Golang"}, "Golang"},
+		{Result{Name: "custom check"}, ""},
+	}
+	for _, tt := range tests {
+		if got := ResultLanguage(tt.result); got != tt.want {
+			t.Errorf("ResultLanguage(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCounts(t *testing.T) {
+	if got := FormatCounts(3, 1, 2, 0); got != "Passed: 3, Failed: 1, Skipped: 2" {
+		t.Errorf("FormatCounts() = %q", got)
+	}
+	if got := FormatCounts(3, 0, 0, 1); got != "Passed: 3, Failed: 0, Skipped: 0, Warnings: 1" {
+		t.Errorf("FormatCounts() with warnings = %q", got)
+	}
+}
+
+func TestConvertTaskResults_Path(t *testing.T) {
+	results := convertTaskResults([]multiagentspec.TaskResult{
+		{ID: "Go: build", Status: multiagentspec.StatusGo, Metadata: map[string]interface{}{"path": "svc/api", "language": "Go"}},
+		{ID: "Go: lint", Status: multiagentspec.StatusGo},
+		{ID: "Go: gofmt", Status: multiagentspec.StatusNoGo},
+		{ID: "Go: tests", Status: multiagentspec.StatusNoGo, Metadata: map[string]interface{}{"remediation": "go test -run TestX"}},
+	})
+	if results[0].Path != "svc/api" || results[0].Language != "Go" {
+		t.Errorf("result with metadata: path %q, language %q", results[0].Path, results[0].Language)
+	}
+	if ResultPath(results[1]) != "." {
+		t.Errorf("ResultPath() without metadata = %q, want \".\"", ResultPath(results[1]))
+	}
+	if results[2].Remediation != "format the code: gofmt -w ." {
+		t.Errorf("default remediation = %q", results[2].Remediation)
+	}
+	if results[3].Remediation != "go test -run TestX" {
+		t.Errorf("remediation from metadata = %q", results[3].Remediation)
+	}
+}
+
+func TestToTaskResult(t *testing.T) {
+	task := ToTaskResult(Result{
+		Name:        "Go: tests",
+		Path:        "svc/api",
+		Output:      "--- FAIL: TestHandler\nmore",
+		Remediation: "fix the failing tests: go test ./...",
+		Duration:    2 * time.Second,
+	})
+	if task.ID != "go.test" || task.Status != multiagentspec.StatusNoGo || task.Severity != "high" {
+		t.Errorf("unexpected task: %+v", task)
+	}
+	if task.Detail != "--- FAIL: TestHandler" || task.DurationMs != 2000 || task.Metadata["path"] != "svc/api" ||
+		task.Metadata["remediation"] != "fix the failing tests: go test ./..." {
+		t.Errorf("unexpected detail, duration, or metadata: %+v", task)
+	}
+
+	skipped := ToTaskResult(Result{Name: "Go: lint", Skipped: true, Reason: "not configured", Remediation: "install golangci-lint"})
+	if skipped.Status != multiagentspec.StatusSkip || skipped.Severity != "" || skipped.Detail != "not configured" || skipped.Metadata != nil {
+		t.Errorf("unexpected skipped task: %+v", skipped)
+	}
+}
+
+func TestDedupeResults(t *testing.T) {
+	results := []Result{
+		{Name: "TypeScript: lint", Passed: true, Path: "web", Command: "npm run lint"},
+		{Name: "JavaScript: lint", Passed: true, Path: "web", Command: "npm run lint"},
+		{Name: "JavaScript: lint", Passed: true, Path: "docs", Command: "npm run lint"},
+		{Name: "TypeScript: test", Passed: false, Path: "web", Command: "npm test"},
+		{Name: "JavaScript: test", Passed: false, Path: "web", Command: "npm test"},
+		{Name: "Go: build", Passed: true, Path: "web"},
+		{Name: "Go: build", Passed: true, Path: "web"},
+	}
+
+	got := DedupeResults(results)
+	want := []struct{ name, language, path string }{
+		{"TypeScript/JavaScript: lint", "TypeScript/JavaScript", "web"},
+		{"JavaScript: lint", "", "docs"},
+		{"TypeScript/JavaScript: test", "TypeScript/JavaScript", "web"},
+		{"Go: build", "", "web"},
+		{"Go: build", "", "web"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DedupeResults() returned %d results, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || got[i].Language != w.language || got[i].Path != w.path {
+			t.Errorf("result %d = %q (%q) in %q, want %q (%q) in %q", i,
+				got[i].Name, got[i].Language, got[i].Path, w.name, w.language, w.path)
+		}
+	}
+
+	groups := GroupResults(got)
+	if len(groups) != 3 || groups[0].Title() != "web (TypeScript/JavaScript)" {
+		t.Errorf("unexpected groups after dedupe: %+v", groups)
+	}
+}
@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// idPrefixes are the ID prefixes for language names; other prefixes are
+// lowercased, e.g. "Release: git remote" becomes "release.git_remote".
+var idPrefixes = map[string]string{
+	"go":         "go",
+	"typescript": "ts",
+	"javascript": "js",
+	"python":     "py",
+	"rust":       "rust",
+	"swift":      "swift",
+}
+
+// idNames are the IDs for check names that differ from their slug.
+var idNames = map[string]string{
+	"tests":      "test",
+	"formatting": "format",
+}
+
+// ResultID returns the stable machine ID of a result, such as "go.test"
+// or "ts.lint". Unlike the display name it doesn't change with wording or
+// language merging, so it is what config filters, JSON output, and report
+// conversion use. Together with the detection path it identifies a result.
+func ResultID(r Result) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	lang := ResultLanguage(r)
+	name := strings.TrimPrefix(r.Name, lang+": ")
+
+	check := slug(name)
+	if alias, ok := idNames[check]; ok {
+		check = alias
+	}
+	if lang == "" {
+		return check
+	}
+	prefix := slug(lang)
+	if p, ok := idPrefixes[prefix]; ok {
+		prefix = p
+	}
+	return prefix + "." + check
+}
+
+// slug lowercases s and joins its words with underscores.
+func slug(s string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+		} else {
+			sep = true
+		}
+	}
+	return b.String()
+}
+
+// AssignIDs sets the ID of every result that doesn't have one.
+func AssignIDs(results []Result) {
+	for i := range results {
+		results[i].ID = ResultID(results[i])
+	}
+}
+
+// SortResults orders results by detection path and then ID, so output
+// doesn't depend on the order checks ran in.
+func SortResults(results []Result) {
+	sort.SliceStable(results, func(i, j int) bool {
+		pi, pj := ResultPath(results[i]), ResultPath(results[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return ResultID(results[i]) < ResultID(results[j])
+	})
+}
+
+// MatchID reports whether id matches any of patterns, which may use
+// path.Match wildcards, e.g. "go.*" or "*.lint".
+func MatchID(id string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipByID marks the results whose IDs match patterns as skipped, with
+// reason explaining why.
+func SkipByID(results []Result, patterns []string, reason string) {
+	for i := range results {
+		if MatchID(ResultID(results[i]), patterns) {
+			results[i].Skipped = true
+			results[i].Passed = false
+			results[i].Warning = false
+			results[i].Reason = reason
+		}
+	}
+}
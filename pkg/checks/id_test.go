@@ -0,0 +1,73 @@
+package checks
+
+import "testing"
+
+func TestResultID(t *testing.T) {
+	tests := []struct {
+		result Result
+		want   string
+	}{
+		{Result{Name: "Go: tests"}, "go.test"},
+		{Result{Name: "Go: golangci-lint"}, "go.golangci_lint"},
+		{Result{Name: "TypeScript: formatting"}, "ts.format"},
+		{Result{Name: "JavaScript: lint"}, "js.lint"},
+		{Result{Name: "Release: git remote"}, "release.git_remote"},
+		{Result{Name: "Python: tests", Language: "Python"}, "py.test"},
+		{Result{Name: "lint", Language: "Rust"}, "rust.lint"},
+		{Result{Name: "Go: tests", ID: "custom.id"}, "custom.id"},
+		{Result{Name: "Build"}, "build"},
+	}
+	for _, tt := range tests {
+		if got := ResultID(tt.result); got != tt.want {
+			t.Errorf("ResultID(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestDedupeResults_KeepsID(t *testing.T) {
+	results := DedupeResults([]Result{
+		{Name: "TypeScript: lint", Command: "npm run lint", Passed: true},
+		{Name: "JavaScript: lint", Command: "npm run lint", Passed: true},
+	})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].ID != "ts.lint" {
+		t.Errorf("merged ID = %q, want ts.lint", results[0].ID)
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	results := []Result{
+		{Name: "Go: tests", Path: "svc"},
+		{Name: "Go: lint", Path: "svc"},
+		{Name: "TypeScript: lint"},
+		{Name: "Go: build"},
+	}
+	SortResults(results)
+
+	want := []string{"go.build", "ts.lint", "go.lint", "go.test"}
+	for i, r := range results {
+		if got := ResultID(r); got != want[i] {
+			t.Errorf("results[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestSkipByID(t *testing.T) {
+	results := []Result{
+		{Name: "Go: tests", Passed: true},
+		{Name: "Go: lint", Passed: false},
+		{Name: "TypeScript: lint", Warning: true},
+	}
+	SkipByID(results, []string{"*.lint"}, "skipped by config")
+
+	if results[0].Skipped {
+		t.Error("go.test should not be skipped")
+	}
+	for _, r := range results[1:] {
+		if !r.Skipped || r.Passed || r.Warning || r.Reason != "skipped by config" {
+			t.Errorf("expected %s to be skipped, got %+v", r.Name, r)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package checks
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// vocabularyLabels are the status labels of each report vocabulary.
+var vocabularyLabels = map[string]map[AreaStatus]string{
+	"go_no_go":  {StatusGo: "GO", StatusNoGo: "NO-GO", StatusWarn: "WARN", StatusSkip: "SKIP"},
+	"pass_fail": {StatusGo: "PASS", StatusNoGo: "FAIL", StatusWarn: "WARN", StatusSkip: "SKIP"},
+}
+
+// vocabularyMessages are the final verdicts of each report vocabulary, if
+// the release is a go and if it isn't.
+var vocabularyMessages = map[string][2]string{
+	"go_no_go":  {"🚀 ALL SYSTEMS GO 🚀", "🛑 NO-GO FOR RELEASE 🛑"},
+	"pass_fail": {"✅ ALL CHECKS PASSED", "❌ RELEASE CHECKS FAILED"},
+}
+
+// reportConfig is the wording of the reports, guarded by reportMu.
+var (
+	reportMu     sync.RWMutex
+	reportConfig config.ReportConfig
+)
+
+// SetReportConfig sets the wording of all subsequent reports.
+func SetReportConfig(cfg config.ReportConfig) {
+	reportMu.Lock()
+	defer reportMu.Unlock()
+	reportConfig = cfg
+}
+
+func currentReportConfig() config.ReportConfig {
+	reportMu.RLock()
+	defer reportMu.RUnlock()
+	return reportConfig
+}
+
+// vocabulary returns the report vocabulary of cfg, defaulting to
+// go_no_go.
+func vocabulary(cfg config.ReportConfig) string {
+	if _, ok := vocabularyLabels[cfg.Vocabulary]; ok {
+		return cfg.Vocabulary
+	}
+	return "go_no_go"
+}
+
+// Label returns the label reports show for s: the one configured with
+// SetReportConfig, or its vocabulary's, e.g. "PASS" for StatusGo with the
+// pass_fail vocabulary.
+func (s AreaStatus) Label() string {
+	cfg := currentReportConfig()
+	custom := map[AreaStatus]string{
+		StatusGo:   cfg.Labels.Go,
+		StatusNoGo: cfg.Labels.NoGo,
+		StatusWarn: cfg.Labels.Warn,
+		StatusSkip: cfg.Labels.Skip,
+	}
+	if l := custom[s]; l != "" {
+		return l
+	}
+	if l, ok := vocabularyLabels[vocabulary(cfg)][s]; ok {
+		return l
+	}
+	return string(s)
+}
+
+// labelWidth returns the display width of the widest status label, or
+// minWidth if that is wider.
+func labelWidth(minWidth int) int {
+	w := minWidth
+	for _, s := range []AreaStatus{StatusGo, StatusNoGo, StatusWarn, StatusSkip} {
+		w = max(w, displayWidth(s.Label()))
+	}
+	return w
+}
+
+// verdictData is the data of report message templates.
+type verdictData struct {
+	Version  string   // Target version, if known
+	Status   string   // Label of the overall status
+	Blocking []string // Areas or checks that failed
+}
+
+// verdictMessage returns the final verdict of a report: the configured
+// message, or the vocabulary's if none is configured or it fails to
+// render. Each line of the result is a line of the report.
+func verdictMessage(isGo bool, version string, blocking []string) string {
+	cfg := currentReportConfig()
+	msg, status := cfg.Messages.NoGo, StatusNoGo
+	if isGo {
+		msg, status = cfg.Messages.Go, StatusGo
+	}
+	if msg != "" {
+		if tmpl, err := config.ParseReportMessage(msg); err == nil {
+			var b strings.Builder
+			data := verdictData{Version: version, Status: status.Label(), Blocking: blocking}
+			if tmpl.Execute(&b, data) == nil {
+				return strings.TrimSpace(b.String())
+			}
+		}
+	}
+
+	messages := vocabularyMessages[vocabulary(cfg)]
+	if isGo {
+		return messages[0]
+	}
+	return messages[1]
+}
@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestAreaStatus_Label(t *testing.T) {
+	defer SetReportConfig(config.ReportConfig{})
+
+	if got := StatusNoGo.Label(); got != "NO-GO" {
+		t.Errorf("default NO-GO label = %q", got)
+	}
+
+	SetReportConfig(config.ReportConfig{Vocabulary: "pass_fail", Labels: config.StatusLabels{Warn: "ADVISORY"}})
+	for s, want := range map[AreaStatus]string{StatusGo: "PASS", StatusNoGo: "FAIL", StatusWarn: "ADVISORY", StatusSkip: "SKIP"} {
+		if got := s.Label(); got != want {
+			t.Errorf("%s.Label() = %q, want %q", s, got, want)
+		}
+	}
+	if got := labelWidth(6); got != 8 {
+		t.Errorf("labelWidth(6) = %d, want 8", got)
+	}
+}
+
+func TestVerdictMessage(t *testing.T) {
+	defer SetReportConfig(config.ReportConfig{})
+
+	if got := verdictMessage(false, "v1.2.0", []string{"QA"}); got != "🛑 NO-GO FOR RELEASE 🛑" {
+		t.Errorf("default message = %q", got)
+	}
+
+	SetReportConfig(config.ReportConfig{
+		Vocabulary: "pass_fail",
+		Messages:   config.ReportMessages{NoGo: `Release {{.Version}} {{.Status}}: {{join .Blocking ", "}}`},
+	})
+	if got := verdictMessage(false, "v1.2.0", []string{"QA", "Security"}); got != "Release v1.2.0 FAIL: QA, Security" {
+		t.Errorf("templated message = %q", got)
+	}
+	if got := verdictMessage(true, "v1.2.0", nil); got != "✅ ALL CHECKS PASSED" {
+		t.Errorf("vocabulary message = %q", got)
+	}
+}
+
+func TestWriteValidationReport_Labels(t *testing.T) {
+	defer SetReportConfig(config.ReportConfig{})
+	defer func(f func() int) { terminalWidth = f }(terminalWidth)
+	terminalWidth = func() int { return 0 }
+
+	SetReportConfig(config.ReportConfig{
+		Labels:   config.StatusLabels{Go: "APPROVED BY QA", NoGo: "REJECTED"},
+		Messages: config.ReportMessages{NoGo: "Release {{.Version}} rejected\nBlocked by {{join .Blocking \", \"}}"},
+	})
+	testReport := testValidationReport()
+
+	var buf bytes.Buffer
+	writeValidationReport(&buf, testReport)
+	out := buf.String()
+	checkBoxLines(t, out)
+	for _, want := range []string{"REJECTED       QA", "Release v1.2.0 rejected", "Blocked by QA"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "NO-GO") {
+		t.Errorf("report still says NO-GO:\n%s", out)
+	}
+	if got := testReport.Verdict(); got != "REJECTED: QA" {
+		t.Errorf("Verdict() = %q", got)
+	}
+}
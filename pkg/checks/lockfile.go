@@ -0,0 +1,290 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lockfileCommands maps package managers to a command that fails when the
+// lockfile is out of sync with package.json without installing anything
+// or running install scripts.
+var lockfileCommands = map[string][]string{
+	"npm": {"npm", "ci", "--dry-run", "--ignore-scripts", "--no-audit", "--no-fund"},
+	"bun": {"bun", "install", "--frozen-lockfile", "--dry-run"},
+}
+
+// yarnBerryLockfileCommand checks the lockfile with yarn 2+, which
+// resolves and fails on lockfile changes without linking node_modules.
+var yarnBerryLockfileCommand = []string{"yarn", "install", "--immutable", "--mode=update-lockfile"}
+
+// scratchLockfileCheck is a frozen, offline install for package managers
+// that can't check their lockfile without installing. It runs on a scratch
+// copy of package.json and the lockfile, so nothing is installed into the
+// checked directory.
+type scratchLockfileCheck struct {
+	args     []string
+	outdated string // Output reporting that the lockfile is out of sync
+}
+
+// scratchLockfileChecks maps package managers to their scratch check.
+var scratchLockfileChecks = map[string]scratchLockfileCheck{
+	"pnpm": {
+		args:     []string{"pnpm", "install", "--frozen-lockfile", "--ignore-scripts", "--offline"},
+		outdated: "ERR_PNPM_OUTDATED_LOCKFILE",
+	},
+	"yarn": {
+		args:     []string{"yarn", "install", "--frozen-lockfile", "--ignore-scripts", "--offline"},
+		outdated: "Your lockfile needs to be updated",
+	},
+}
+
+// scratchLockfileConfigs are the package manager settings copied along
+// with package.json and the lockfile, e.g. for a private registry.
+var scratchLockfileConfigs = []string{".npmrc", ".yarnrc"}
+
+// isYarnBerry reports whether the installed yarn is yarn 2 or later.
+func isYarnBerry(dir string) bool {
+	v, err := toolVersion(dir, "yarn")
+	return err == nil && !strings.HasPrefix(v, "1.")
+}
+
+// hasNodeWorkspaces reports whether the package in dir is the root of a
+// pnpm, npm, or yarn workspace, whose members a scratch copy would miss.
+func hasNodeWorkspaces(dir string) bool {
+	if FileExists(filepath.Join(dir, "pnpm-workspace.yaml")) {
+		return true
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return false
+	}
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	return json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 && string(pkg.Workspaces) != "null"
+}
+
+// checkLockfileCopy runs check on a scratch copy of the package.json,
+// lockfile, and package manager settings of dir. checked is false if the
+// install failed without reporting whether the lockfile is in sync, such
+// as when packages are missing from the offline cache.
+func checkLockfileCopy(name, dir, lockfile string, check scratchLockfileCheck) (result Result, checked bool, err error) {
+	tmp, err := os.MkdirTemp("", "atrelease-lockfile-")
+	if err != nil {
+		return Result{}, false, err
+	}
+	defer os.RemoveAll(tmp)
+
+	for _, f := range append([]string{"package.json", lockfile}, scratchLockfileConfigs...) {
+		data, err := os.ReadFile(filepath.Join(dir, f))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Result{}, false, err
+		}
+		if err := os.WriteFile(filepath.Join(tmp, f), data, 0644); err != nil {
+			return Result{}, false, err
+		}
+	}
+
+	result = RunCommand(name, tmp, check.args[0], check.args[1:]...)
+	return result, result.Passed || strings.Contains(result.Output, check.outdated), nil
+}
+
+func (c *ReleaseChecker) checkGoModSync(dir string) Result {
+	name := "Release: Go module sync"
+
+	if !FileExists(filepath.Join(dir, "go.mod")) {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "Not a Go project",
+		}
+	}
+
+	if r, ok := disallowed(name, "go"); ok {
+		return r
+	}
+	if !CommandExists("go") {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "go not installed",
+		}
+	}
+
+	verify := RunCommand(name, dir, "go", "mod", "verify")
+	if !verify.Passed {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      "go mod verify failed:\n" + verify.Output,
+			Remediation: "clear the corrupted modules with go clean -modcache, then download them again",
+		}
+	}
+
+	// go mod tidy -diff (Go 1.23+) reports go.mod/go.sum drift without writing
+	tidy := RunCommand(name, dir, "go", "mod", "tidy", "-diff")
+	if !tidy.Passed && strings.Contains(tidy.Output, "flag provided but not defined") {
+		tidy = checkModTidyCopy(name, dir)
+	}
+	if !tidy.Passed {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      "go.mod/go.sum are not tidy\n" + tidy.Output,
+			Remediation: "run go mod tidy and commit go.mod and go.sum",
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: "go.mod and go.sum are in sync",
+	}
+}
+
+// checkModTidyCopy runs go mod tidy on a scratch copy of go.mod and go.sum,
+// through -modfile, and fails if the result differs from the originals.
+// Unlike tidying in place, it never touches the working tree. It is the
+// fallback for Go releases without go mod tidy -diff.
+func checkModTidyCopy(name, dir string) Result {
+	tmp, err := os.MkdirTemp("", "atrelease-tidy-")
+	if err != nil {
+		return Result{Name: name, Error: err}
+	}
+	defer os.RemoveAll(tmp)
+
+	files := []string{"go.mod", "go.sum"}
+	original := make(map[string][]byte)
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(dir, f))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Result{Name: name, Error: err}
+		}
+		original[f] = data
+		if err := os.WriteFile(filepath.Join(tmp, f), data, 0644); err != nil {
+			return Result{Name: name, Error: err}
+		}
+	}
+
+	// go.sum is read and written beside the -modfile go.mod
+	tidy := RunCommand(name, dir, "go", "mod", "tidy", "-modfile="+filepath.Join(tmp, "go.mod"))
+	if !tidy.Passed {
+		return tidy
+	}
+	var changed []string
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Join(tmp, f))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return Result{Name: name, Error: err}
+		}
+		if !bytes.Equal(data, original[f]) {
+			changed = append(changed, f)
+		}
+	}
+	if len(changed) > 0 {
+		return Result{Name: name, Output: "go mod tidy would change " + strings.Join(changed, " and ")}
+	}
+	return Result{Name: name, Passed: true}
+}
+
+func (c *ReleaseChecker) checkNodeLockfileSync(dir string) Result {
+	name := "Release: Node lockfile sync"
+
+	if !FileExists(filepath.Join(dir, "package.json")) {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "Not a Node project",
+		}
+	}
+
+	lockfiles := DetectLockfiles(dir)
+	if len(lockfiles) == 0 {
+		return Result{
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      "No lockfile found; CI installs will not be reproducible",
+			Remediation: "install the dependencies with your package manager and commit its lockfile",
+		}
+	}
+	if len(lockfiles) > 1 {
+		// Reported by the Node toolchain check
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  fmt.Sprintf("multiple lockfiles (%s)", strings.Join(lockfiles, ", ")),
+		}
+	}
+
+	lockfile := lockfiles[0]
+	manager := Lockfiles[lockfile]
+	if r, ok := disallowed(name, manager); ok {
+		return r
+	}
+	if !CommandExists(manager) {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  fmt.Sprintf("%s not installed", manager),
+		}
+	}
+
+	var result Result
+	check, scratch := scratchLockfileChecks[manager]
+	switch {
+	case manager == "yarn" && isYarnBerry(dir):
+		args := yarnBerryLockfileCommand
+		result = RunCommand(name, dir, args[0], args[1:]...)
+	case scratch:
+		if hasNodeWorkspaces(dir) {
+			return Result{
+				Name:    name,
+				Skipped: true,
+				Reason:  fmt.Sprintf("%s workspaces can't be checked without installing", manager),
+			}
+		}
+		var checked bool
+		var err error
+		result, checked, err = checkLockfileCopy(name, dir, lockfile, check)
+		if err != nil {
+			return Result{Name: name, Error: err}
+		}
+		if !checked {
+			reason := fmt.Sprintf("%s couldn't check %s offline", manager, lockfile)
+			if line, _, _ := strings.Cut(strings.TrimSpace(result.Output), "\n"); line != "" {
+				reason += ": " + line
+			}
+			return Result{Name: name, Skipped: true, Reason: reason}
+		}
+	default:
+		args := lockfileCommands[manager]
+		result = RunCommand(name, dir, args[0], args[1:]...)
+	}
+	if !result.Passed {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      fmt.Sprintf("%s is out of sync with package.json\n%s", lockfile, result.Output),
+			Remediation: fmt.Sprintf("run %s install and commit %s", manager, lockfile),
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("%s is in sync", lockfile),
+	}
+}
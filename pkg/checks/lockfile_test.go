@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheckGoModSync(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/m\n\ngo 1.21\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ReleaseChecker{}
+	result := c.checkGoModSync(tmpDir)
+	if !result.Passed {
+		t.Errorf("expected tidy module to pass, got %+v", result)
+	}
+}
+
+func TestCheckNodeLockfileSync_NoLockfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"name": "demo"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ReleaseChecker{}
+	result := c.checkNodeLockfileSync(tmpDir)
+	if result.Passed || !result.Warning {
+		t.Errorf("expected warning when no lockfile exists, got %+v", result)
+	}
+}
+
+func TestCheckNodeLockfileSync_NotNodeProject(t *testing.T) {
+	c := &ReleaseChecker{}
+	result := c.checkNodeLockfileSync(t.TempDir())
+	if !result.Skipped {
+		t.Errorf("expected skipped result, got %+v", result)
+	}
+}
+
+func TestCheckModTidyCopy(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	tmpDir := t.TempDir()
+	goMod := "module example.com/m\n\ngo 1.21\n\nrequire example.com/unused v1.0.0\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	result := checkModTidyCopy("Release: Go module sync", tmpDir)
+	if result.Passed || !strings.Contains(result.Output, "go.mod") {
+		t.Errorf("expected an untidy go.mod to fail, got %+v", result)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, "go.mod"))
+	if err != nil || string(data) != goMod {
+		t.Errorf("go.mod was modified: %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "go.sum")); err == nil {
+		t.Error("go.sum was created in the working tree")
+	}
+
+	tidy := "module example.com/m\n\ngo 1.21\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(tidy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if result := checkModTidyCopy("Release: Go module sync", tmpDir); !result.Passed {
+		t.Errorf("expected a tidy go.mod to pass, got %+v", result)
+	}
+}
+
+// fakePackageManager puts a package manager on PATH that reports version,
+// logs the directory and arguments of each install to the returned file,
+// creates node_modules where it runs, and then runs install, a shell
+// snippet deciding the outcome.
+func fakePackageManager(t *testing.T, manager, version, install string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake package manager is a shell script")
+	}
+	bin := t.TempDir()
+	log := filepath.Join(bin, "calls.log")
+	script := "#!/bin/sh\n" +
+		"if [ \"$1\" = --version ]; then echo " + version + "; exit 0; fi\n" +
+		"echo \"$PWD $*\" >> " + log + "\n" +
+		"mkdir -p node_modules\n" +
+		install + "\n"
+	if err := os.WriteFile(filepath.Join(bin, manager), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestCheckNodeLockfileSync_NoInstall(t *testing.T) {
+	for _, tt := range []struct {
+		name, manager, version, lockfile, install string
+		workspace                                 bool
+		want                                      Severity
+		wantArgs                                  string // "" when the manager must not run
+		inDir                                     bool   // whether it runs in the checked directory
+	}{
+		{
+			name: "pnpm in sync", manager: "pnpm", version: "9.1.0", lockfile: "pnpm-lock.yaml",
+			install: "exit 0", want: SeverityPassed,
+			wantArgs: "install --frozen-lockfile --ignore-scripts --offline",
+		},
+		{
+			name: "pnpm out of sync", manager: "pnpm", version: "9.1.0", lockfile: "pnpm-lock.yaml",
+			install:  "echo ' ERR_PNPM_OUTDATED_LOCKFILE  Cannot install with frozen-lockfile'; exit 1",
+			want:     SeverityFailed,
+			wantArgs: "install --frozen-lockfile --ignore-scripts --offline",
+		},
+		{
+			name: "pnpm store miss", manager: "pnpm", version: "9.1.0", lockfile: "pnpm-lock.yaml",
+			install:  "echo ' ERR_PNPM_NO_OFFLINE_TARBALL  A package is missing from the store'; exit 1",
+			want:     SeveritySkipped,
+			wantArgs: "install --frozen-lockfile --ignore-scripts --offline",
+		},
+		{
+			name: "pnpm workspace", manager: "pnpm", version: "9.1.0", lockfile: "pnpm-lock.yaml",
+			install: "exit 0", workspace: true, want: SeveritySkipped,
+		},
+		{
+			name: "yarn classic out of sync", manager: "yarn", version: "1.22.22", lockfile: "yarn.lock",
+			install:  "echo 'error Your lockfile needs to be updated, but yarn was run with `--frozen-lockfile`.'; exit 1",
+			want:     SeverityFailed,
+			wantArgs: "install --frozen-lockfile --ignore-scripts --offline",
+		},
+		{
+			name: "yarn berry", manager: "yarn", version: "4.1.0", lockfile: "yarn.lock",
+			install: "rmdir node_modules; exit 0", want: SeverityPassed,
+			wantArgs: "install --immutable --mode=update-lockfile", inDir: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			log := fakePackageManager(t, tt.manager, tt.version, tt.install)
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "package.json"), `{"name": "demo"}`)
+			writeTestFile(t, filepath.Join(dir, tt.lockfile), "lockfileVersion: '9.0'\n")
+			if tt.workspace {
+				writeTestFile(t, filepath.Join(dir, "pnpm-workspace.yaml"), "packages:\n  - packages/*\n")
+			}
+
+			c := &ReleaseChecker{}
+			result := c.checkNodeLockfileSync(dir)
+			if got := result.Severity(); got != tt.want {
+				t.Errorf("severity = %s, want %s: %+v", got, tt.want, result)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "node_modules")); err == nil {
+				t.Error("node_modules was installed into the checked directory")
+			}
+
+			data, _ := os.ReadFile(log)
+			calls := strings.TrimSpace(string(data))
+			if tt.wantArgs == "" {
+				if calls != "" {
+					t.Errorf("%s ran: %s", tt.manager, calls)
+				}
+				return
+			}
+			callDir, args, _ := strings.Cut(calls, " ")
+			if args != tt.wantArgs {
+				t.Errorf("args = %q, want %q", args, tt.wantArgs)
+			}
+			if realDir, _ := filepath.EvalSymlinks(dir); (callDir == realDir || callDir == dir) != tt.inDir {
+				t.Errorf("ran in %s, checked directory %s", callDir, dir)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// checkModuleMajor validates the go.mod module path's major version suffix
+// matches the version being released: none for v0 and v1, /vN for v2 and
+// up. A mismatched tag is published but can't be imported, which users
+// only find out when go get rejects it.
+func (c *ReleaseChecker) checkModuleMajor(dir, version string) Result {
+	name := "Release: module path major version"
+
+	if version == "" {
+		return Result{Name: name, Skipped: true, Reason: "No version specified"}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return Result{Name: name, Skipped: true, Reason: "No go.mod found"}
+	}
+	path := modfile.ModulePath(data)
+	major, _, _, err := semver.Split(version)
+	if path == "" || err != nil {
+		// Version available and go.mod sync report these
+		return Result{Name: name, Skipped: true, Reason: "No valid module path or version"}
+	}
+
+	if msg := moduleMajorMismatch(path, version, major); msg != "" {
+		return Result{Name: name, Passed: false, Output: msg}
+	}
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("Module path %s matches %s", path, version),
+	}
+}
+
+// moduleMajorMismatch returns why module path can't publish version, whose
+// major version is major, or "" if it can.
+func moduleMajorMismatch(path, version string, major int) string {
+	prefix, pathMajor, ok := module.SplitPathVersion(path)
+	if !ok {
+		return fmt.Sprintf("Module path %s has an invalid major version suffix", path)
+	}
+
+	// gopkg.in paths carry the major as .vN, with its own rules
+	if strings.HasPrefix(pathMajor, ".") {
+		if err := module.CheckPathMajor("v"+strings.TrimPrefix(version, "v"), pathMajor); err != nil {
+			return fmt.Sprintf("Module path %s can't publish %s: %v", path, version, err)
+		}
+		return ""
+	}
+
+	pathN := 0
+	if pathMajor != "" {
+		pathN, _ = strconv.Atoi(strings.TrimPrefix(pathMajor, "/v"))
+	}
+	switch {
+	case major >= 2 && pathN == major:
+		return ""
+	case major < 2 && pathN == 0:
+		return ""
+	case major >= 2:
+		return fmt.Sprintf("Releasing %s requires module path %s/v%d, but go.mod declares %s. "+
+			"Change the module line and the module's imports of its own packages", version, prefix, major, path)
+	default:
+		return fmt.Sprintf("Module path %s is for v%d releases, but %s is v%d. "+
+			"v0 and v1 releases use %s, without a major version suffix", path, pathN, version, major, prefix)
+	}
+}
@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+func TestModuleMajorMismatch(t *testing.T) {
+	tests := []struct {
+		path, version string
+		want          string // substring of the mismatch, or "" if none
+	}{
+		{"example.com/m", "v0.3.0", ""},
+		{"example.com/m", "v1.4.2", ""},
+		{"example.com/m/v2", "v2.0.0", ""},
+		{"example.com/m/v3", "v3.1.0-rc.1", ""},
+		{"example.com/m", "v2.0.0", "requires module path example.com/m/v2"},
+		{"example.com/m/v2", "v3.0.0", "requires module path example.com/m/v3"},
+		{"example.com/m/v2", "v1.9.0", "is for v2 releases"},
+		{"gopkg.in/yaml.v3", "v3.0.1", ""},
+		{"gopkg.in/yaml.v3", "v4.0.0", "can't publish"},
+	}
+	for _, tt := range tests {
+		major, _, _, err := semver.Split(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := moduleMajorMismatch(tt.path, tt.version, major)
+		if (tt.want == "") != (got == "") || !strings.Contains(got, tt.want) {
+			t.Errorf("moduleMajorMismatch(%q, %q) = %q, want %q", tt.path, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseChecker_ModuleMajor(t *testing.T) {
+	c := &ReleaseChecker{}
+	dir := t.TempDir()
+	if r := c.checkModuleMajor(dir, "v2.0.0"); !r.Skipped {
+		t.Errorf("no go.mod: %+v", r)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if r := c.checkModuleMajor(dir, "v2.0.0"); r.Passed {
+		t.Errorf("v2 without /v2: %+v", r)
+	}
+	if r := c.checkModuleMajor(dir, "1.2.0"); !r.Passed {
+		t.Errorf("v1: %+v", r)
+	}
+}
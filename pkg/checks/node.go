@@ -0,0 +1,314 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// PackageJSON holds the package.json fields used by Node checks.
+type PackageJSON struct {
+	Engines        map[string]string `json:"engines"`
+	PackageManager string            `json:"packageManager"`
+}
+
+// LoadPackageJSON reads package.json from dir.
+func LoadPackageJSON(dir string) (*PackageJSON, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	var pkg PackageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+	return &pkg, nil
+}
+
+// Lockfiles maps lockfile names to the package manager that writes them.
+var Lockfiles = map[string]string{
+	"package-lock.json": "npm",
+	"pnpm-lock.yaml":    "pnpm",
+	"yarn.lock":         "yarn",
+	"bun.lockb":         "bun",
+	"bun.lock":          "bun",
+}
+
+// DetectLockfiles returns the lockfiles present in dir, sorted by name.
+func DetectLockfiles(dir string) []string {
+	var found []string
+	for _, name := range []string{"bun.lock", "bun.lockb", "package-lock.json", "pnpm-lock.yaml", "yarn.lock"} {
+		if FileExists(filepath.Join(dir, name)) {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+var nodeComparatorPattern = regexp.MustCompile(`^(>=|<=|>|<|=|\^|~)?\s*v?([0-9xX*]+(?:\.[0-9xX*]+){0,2})`)
+
+// SatisfiesNodeRange reports whether version satisfies an npm semver range
+// such as ">=18", "^20.10.0", "18.x || >=20" or "16 - 20". Pre-release tags
+// are ignored.
+func SatisfiesNodeRange(version, rng string) bool {
+	v := goVersionParts(strings.TrimPrefix(strings.TrimSpace(version), "v"))
+	for _, alt := range strings.Split(rng, "||") {
+		if satisfiesNodeSet(v, strings.TrimSpace(alt)) {
+			return true
+		}
+	}
+	return false
+}
+
+// satisfiesNodeSet reports whether v satisfies every comparator in a
+// space-separated set.
+func satisfiesNodeSet(v [3]int, set string) bool {
+	if set == "" || set == "*" || set == "x" {
+		return true
+	}
+
+	// Hyphen range: "a - b" is ">=a <=b" (with partial b as an upper bound)
+	if lo, hi, ok := strings.Cut(set, " - "); ok {
+		loParts, _ := parseNodePartial(strings.TrimSpace(lo))
+		hiParts, hiN := parseNodePartial(strings.TrimSpace(hi))
+		if compareParts(v, loParts) < 0 {
+			return false
+		}
+		if hiN < 3 {
+			return compareParts(v, bumpPartial(hiParts, hiN)) < 0
+		}
+		return compareParts(v, hiParts) <= 0
+	}
+
+	fields := strings.Fields(set)
+	for i := 0; i < len(fields); i++ {
+		comp := fields[i]
+		// Allow a space between operator and version, e.g. ">= 18"
+		if strings.Trim(comp, "<>=^~") == "" && i+1 < len(fields) {
+			comp += fields[i+1]
+			i++
+		}
+		if !satisfiesNodeComparator(v, comp) {
+			return false
+		}
+	}
+	return true
+}
+
+func satisfiesNodeComparator(v [3]int, comp string) bool {
+	m := nodeComparatorPattern.FindStringSubmatch(comp)
+	if m == nil {
+		return false
+	}
+	op := m[1]
+	parts, n := parseNodePartial(m[2])
+
+	switch op {
+	case ">=":
+		return compareParts(v, parts) >= 0
+	case ">":
+		if n < 3 {
+			return compareParts(v, bumpPartial(parts, n)) >= 0
+		}
+		return compareParts(v, parts) > 0
+	case "<":
+		return compareParts(v, parts) < 0
+	case "<=":
+		if n < 3 {
+			return compareParts(v, bumpPartial(parts, n)) < 0
+		}
+		return compareParts(v, parts) <= 0
+	case "^":
+		if compareParts(v, parts) < 0 {
+			return false
+		}
+		// Upper bound bumps the left-most non-zero component
+		switch {
+		case parts[0] != 0 || n == 1:
+			return v[0] == parts[0]
+		case parts[1] != 0 || n == 2:
+			return v[0] == 0 && v[1] == parts[1]
+		default:
+			return v == parts
+		}
+	case "~":
+		if compareParts(v, parts) < 0 {
+			return false
+		}
+		if n == 1 {
+			return v[0] == parts[0]
+		}
+		return v[0] == parts[0] && v[1] == parts[1]
+	default:
+		// Exact or partial ("20", "20.x") version
+		for i := 0; i < n; i++ {
+			if v[i] != parts[i] {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// parseNodePartial parses a possibly partial version, returning the parts
+// and the number of specified (non-wildcard) components.
+func parseNodePartial(s string) ([3]int, int) {
+	var parts [3]int
+	n := 0
+	for i, p := range strings.SplitN(strings.TrimPrefix(s, "v"), ".", 3) {
+		if p == "x" || p == "X" || p == "*" {
+			break
+		}
+		val, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts[i] = val
+		n = i + 1
+	}
+	return parts, n
+}
+
+// bumpPartial returns the exclusive upper bound of a partial version,
+// e.g. 20 -> 21.0.0 and 20.1 -> 20.2.0.
+func bumpPartial(parts [3]int, n int) [3]int {
+	switch n {
+	case 0:
+		return [3]int{int(^uint(0) >> 1), 0, 0}
+	case 1:
+		return [3]int{parts[0] + 1, 0, 0}
+	default:
+		return [3]int{parts[0], parts[1] + 1, 0}
+	}
+}
+
+func compareParts(a, b [3]int) int {
+	for i := range a {
+		if a[i] < b[i] {
+			return -1
+		}
+		if a[i] > b[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// toolVersion runs "<command> --version" and returns the trimmed output
+// without a leading "v".
+func toolVersion(dir, command string) (string, error) {
+	cmd := proc.Command(command, "--version")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), "v"), nil
+}
+
+func (c *ReleaseChecker) checkNodeToolchain(dir string) Result {
+	name := "Release: Node toolchain"
+
+	if !FileExists(filepath.Join(dir, "package.json")) {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "Not a Node project",
+		}
+	}
+
+	pkg, err := LoadPackageJSON(dir)
+	if err != nil {
+		return Result{
+			Name:   name,
+			Passed: false,
+			Error:  err,
+		}
+	}
+
+	var problems []string
+
+	nvmrc := ""
+	if data, err := os.ReadFile(filepath.Join(dir, ".nvmrc")); err == nil {
+		nvmrc = strings.TrimSpace(string(data))
+	}
+
+	nodeRange := pkg.Engines["node"]
+	if nodeRange != "" || nvmrc != "" {
+		if r, ok := disallowed(name, "node"); ok {
+			return r
+		}
+		if !CommandExists("node") {
+			problems = append(problems, "node not found in PATH; install Node.js (see engines.node or .nvmrc)")
+		} else if installed, err := toolVersion(dir, "node"); err != nil {
+			problems = append(problems, fmt.Sprintf("could not determine Node version: %v", err))
+		} else {
+			if nodeRange != "" && !SatisfiesNodeRange(installed, nodeRange) {
+				problems = append(problems, fmt.Sprintf("node v%s does not satisfy engines.node %q; install a matching version (e.g. nvm install)", installed, nodeRange))
+			}
+			// Aliases such as "lts/*" or "node" can't be checked locally
+			if nvmrc != "" && !strings.Contains(nvmrc, "/") && nvmrc != "node" && !SatisfiesNodeRange(installed, nvmrc) {
+				problems = append(problems, fmt.Sprintf("node v%s does not match .nvmrc %s; run 'nvm use'", installed, nvmrc))
+			}
+		}
+	}
+
+	// Expected package manager: packageManager field, then lockfile
+	manager := ""
+	if pkg.PackageManager != "" {
+		manager, _, _ = strings.Cut(pkg.PackageManager, "@")
+	}
+	lockfiles := DetectLockfiles(dir)
+	lockManagers := make(map[string]bool)
+	for _, lf := range lockfiles {
+		lockManagers[Lockfiles[lf]] = true
+	}
+	if len(lockManagers) > 1 {
+		problems = append(problems, fmt.Sprintf("multiple lockfiles found (%s); keep only the one for your package manager", strings.Join(lockfiles, ", ")))
+	}
+	for _, lf := range lockfiles {
+		if manager != "" && Lockfiles[lf] != manager {
+			problems = append(problems, fmt.Sprintf("%s does not match packageManager %s; remove it and run '%s install'", lf, pkg.PackageManager, manager))
+		}
+	}
+	if manager == "" && len(lockfiles) == 1 {
+		manager = Lockfiles[lockfiles[0]]
+	}
+
+	if manager != "" {
+		if r, ok := disallowed(name, manager); ok {
+			return r
+		}
+		if !CommandExists(manager) {
+			problems = append(problems, fmt.Sprintf("%s not found in PATH; install it (e.g. 'corepack enable')", manager))
+		} else if rng := pkg.Engines[manager]; rng != "" {
+			if installed, err := toolVersion(dir, manager); err == nil && !SatisfiesNodeRange(installed, rng) {
+				problems = append(problems, fmt.Sprintf("%s %s does not satisfy engines.%s %q", manager, installed, manager, rng))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return Result{
+			Name:   name,
+			Passed: false,
+			Output: strings.Join(problems, "\n"),
+		}
+	}
+
+	output := "No engine constraints"
+	if nodeRange != "" {
+		output = fmt.Sprintf("node %s satisfied", nodeRange)
+	}
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: output,
+	}
+}
@@ -0,0 +1,78 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSatisfiesNodeRange(t *testing.T) {
+	tests := []struct {
+		version, rng string
+		want         bool
+	}{
+		{"20.11.0", ">=18", true},
+		{"16.20.0", ">=18", false},
+		{"v20.11.0", "^20.10.0", true},
+		{"21.0.0", "^20.10.0", false},
+		{"0.3.1", "^0.3.0", true},
+		{"0.4.0", "^0.3.0", false},
+		{"18.19.1", "~18.19.0", true},
+		{"18.20.0", "~18.19.0", false},
+		{"18.17.0", "18.x || >=20", true},
+		{"19.0.0", "18.x || >=20", false},
+		{"22.1.0", "18.x || >=20", true},
+		{"20.0.0", ">=18 <21", true},
+		{"21.0.0", ">=18 <21", false},
+		{"20.5.0", "16 - 20", true},
+		{"21.0.0", "16 - 20", false},
+		{"20.5.0", ">= 18", true},
+		{"20.5.0", "20", true},
+		{"20.5.0", "*", true},
+		{"22.0.0", "<=20", false},
+	}
+	for _, tt := range tests {
+		if got := SatisfiesNodeRange(tt.version, tt.rng); got != tt.want {
+			t.Errorf("SatisfiesNodeRange(%q, %q) = %v, want %v", tt.version, tt.rng, got, tt.want)
+		}
+	}
+}
+
+func TestDetectLockfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"yarn.lock", "package-lock.json"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := DetectLockfiles(tmpDir)
+	if len(got) != 2 || got[0] != "package-lock.json" || got[1] != "yarn.lock" {
+		t.Errorf("expected [package-lock.json yarn.lock], got %v", got)
+	}
+}
+
+func TestCheckNodeToolchain_LockfileMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkg := `{"name": "demo", "packageManager": "pnpm@9.0.0"}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "package-lock.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ReleaseChecker{}
+	result := c.checkNodeToolchain(tmpDir)
+	if result.Passed {
+		t.Errorf("expected failure for lockfile mismatch, got %+v", result)
+	}
+}
+
+func TestCheckNodeToolchain_NotNodeProject(t *testing.T) {
+	c := &ReleaseChecker{}
+	result := c.checkNodeToolchain(t.TempDir())
+	if !result.Skipped {
+		t.Errorf("expected skipped result, got %+v", result)
+	}
+}
@@ -0,0 +1,28 @@
+package checks
+
+// OfflineReason is the reason checks that need the network are skipped in
+// offline mode.
+const OfflineReason = "offline"
+
+// offline reports whether checks must not use the network.
+var offline bool
+
+// SetOffline sets whether subsequent checks may use the network. Offline,
+// checks that need it are skipped and heavy checks run on this machine
+// instead of a remote runner.
+func SetOffline(b bool) {
+	offline = b
+}
+
+// Offline reports whether checks run in offline mode.
+func Offline() bool {
+	return offline
+}
+
+// offlineSkip returns a skipped result for check name in offline mode.
+func offlineSkip(name string) (Result, bool) {
+	if !offline {
+		return Result{}, false
+	}
+	return Result{Name: name, Skipped: true, Reason: OfflineReason}, true
+}
@@ -0,0 +1,24 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecurityChecker_Offline(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	c := &SecurityChecker{}
+	for _, r := range []Result{c.checkGoVulncheck(dir), c.checkGoModAudit(dir)} {
+		if !r.Skipped || r.Reason != OfflineReason {
+			t.Errorf("%s = %+v, want skipped with reason %q", r.Name, r, OfflineReason)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// Environment variables that set check options, overriding the config.
+// Each takes a boolean such as "1", "true", "0", or "false".
+const (
+	EnvTest     = "ATRELEASE_TEST"
+	EnvLint     = "ATRELEASE_LINT"
+	EnvFormat   = "ATRELEASE_FORMAT"
+	EnvCoverage = "ATRELEASE_COVERAGE"
+	EnvVerbose  = "ATRELEASE_VERBOSE"
+)
+
+// OptionFlags are check options given on the command line. A nil field
+// leaves the option to the environment and config.
+type OptionFlags struct {
+	Test     *bool
+	Lint     *bool
+	Format   *bool
+	Coverage *bool
+	Verbose  *bool
+}
+
+// ResolveOptions returns the check options for cfg. Each option is taken
+// from the first of these that sets it:
+//
+//  1. flags
+//  2. the environment, read with getenv (os.Getenv if nil)
+//  3. the go language settings in cfg, which apply to every language
+//     since releasekit checks them all in one run, and cfg.Verbose
+//  4. DefaultOptions
+//
+// Commands resolve options only through here, so flags, environment, and
+// config mean the same thing for all of them.
+func ResolveOptions(cfg config.Config, getenv func(string) string, flags OptionFlags) (Options, error) {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	opts := DefaultOptions()
+	lc := cfg.GetLanguageConfig("go")
+	opts.Test = *lc.Test
+	opts.Lint = *lc.Lint
+	opts.Format = *lc.Format
+	opts.Coverage = *lc.Coverage
+	opts.Verbose = cfg.Verbose
+	if lc.ExcludeCoverage != "" {
+		opts.GoExcludeCoverage = lc.ExcludeCoverage
+	}
+	opts.Env = GoEnv(lc)
+
+	for _, o := range []struct {
+		env  string
+		flag *bool
+		opt  *bool
+	}{
+		{EnvTest, flags.Test, &opts.Test},
+		{EnvLint, flags.Lint, &opts.Lint},
+		{EnvFormat, flags.Format, &opts.Format},
+		{EnvCoverage, flags.Coverage, &opts.Coverage},
+		{EnvVerbose, flags.Verbose, &opts.Verbose},
+	} {
+		if v := getenv(o.env); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return Options{}, fmt.Errorf("%s=%s: not a boolean", o.env, v)
+			}
+			*o.opt = b
+		}
+		if o.flag != nil {
+			*o.opt = *o.flag
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,84 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestResolveOptions(t *testing.T) {
+	f, tr := false, true
+	cfg := config.DefaultConfig()
+	cfg.Languages = map[string]config.LanguageConfig{
+		"go": {Lint: &f, Coverage: &tr, ExcludeCoverage: "tools"},
+	}
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		flags OptionFlags
+		want  [4]bool // test, lint, format, coverage
+	}{
+		{"config", nil, OptionFlags{}, [4]bool{true, false, true, true}},
+		{"env over config", map[string]string{EnvLint: "1", EnvTest: "false"}, OptionFlags{}, [4]bool{false, true, true, true}},
+		{"flags over env", map[string]string{EnvTest: "false", EnvCoverage: "0"}, OptionFlags{Test: &tr, Format: &f}, [4]bool{true, false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env = tt.env
+			opts, err := ResolveOptions(cfg, getenv, tt.flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := [4]bool{opts.Test, opts.Lint, opts.Format, opts.Coverage}
+			if got != tt.want {
+				t.Errorf("test, lint, format, coverage = %v, want %v", got, tt.want)
+			}
+			if opts.GoExcludeCoverage != "tools" {
+				t.Errorf("GoExcludeCoverage = %q, want tools", opts.GoExcludeCoverage)
+			}
+		})
+	}
+}
+
+func TestResolveOptions_Defaults(t *testing.T) {
+	opts, err := ResolveOptions(config.DefaultConfig(), func(string) string { return "" }, OptionFlags{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultOptions()
+	if opts.Test != want.Test || opts.Lint != want.Lint || opts.Format != want.Format ||
+		opts.Coverage != want.Coverage || opts.Verbose != want.Verbose || opts.GoExcludeCoverage != want.GoExcludeCoverage {
+		t.Errorf("ResolveOptions() = %+v, want defaults %+v", opts, want)
+	}
+}
+
+func TestResolveOptions_Verbose(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Verbose = true
+	f := false
+	opts, err := ResolveOptions(cfg, func(string) string { return "" }, OptionFlags{})
+	if err != nil || !opts.Verbose {
+		t.Errorf("config verbose: got %v, %v", opts.Verbose, err)
+	}
+	opts, err = ResolveOptions(cfg, func(string) string { return "" }, OptionFlags{Verbose: &f})
+	if err != nil || opts.Verbose {
+		t.Errorf("flag verbose=false: got %v, %v", opts.Verbose, err)
+	}
+}
+
+func TestResolveOptions_InvalidEnv(t *testing.T) {
+	getenv := func(k string) string {
+		if k == EnvFormat {
+			return "sometimes"
+		}
+		return ""
+	}
+	_, err := ResolveOptions(config.DefaultConfig(), getenv, OptionFlags{})
+	if err == nil || !strings.Contains(err.Error(), EnvFormat) {
+		t.Errorf("error = %v, want one naming %s", err, EnvFormat)
+	}
+}
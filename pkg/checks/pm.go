@@ -5,12 +5,16 @@
 package checks
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/roadmap"
+	"github.com/plexusone/agent-team-release/pkg/semver"
 )
 
 // PMChecker validates product management concerns for a release.
@@ -18,31 +22,42 @@ type PMChecker struct{}
 
 // PMOptions contains options for PM validation.
 type PMOptions struct {
-	Version string // Target version (e.g., "v0.5.0")
-	Verbose bool
+	Version   string            // Target version (e.g., "v0.5.0")
+	Changelog *changelog.Loaded // Changelog shared with other checks; nil loads it from dir
+	Verbose   bool
 }
 
 // Check runs all PM validation checks.
 func (c *PMChecker) Check(dir string, opts PMOptions) []Result {
 	var results []Result
+	cl := opts.Changelog
+	if cl == nil {
+		cl = changelog.LoadShared(dir)
+	}
 
 	// 1. Version recommendation
 	results = append(results, c.checkVersionRecommendation(dir, opts.Version))
 
+	// CHANGELOG.json structure, which the changelog checks below rely on
+	results = append(results, c.checkChangelogSchema(dir))
+
 	// 2. Release scope
-	results = append(results, c.checkReleaseScope(dir, opts.Version))
+	results = append(results, c.checkReleaseScope(dir, cl, opts.Version))
 
 	// 3. Changelog quality
-	results = append(results, c.checkChangelogQuality(dir, opts.Version))
+	results = append(results, c.checkChangelogQuality(cl, opts.Version))
 
 	// 4. Breaking changes
-	results = append(results, c.checkBreakingChanges(dir, opts.Version))
+	results = append(results, c.checkBreakingChanges(cl, opts.Version))
+
+	// Exported Go API against its baseline, which breaking changes rely on
+	results = append(results, c.checkAPIBaseline(dir, cl, opts.Version))
 
 	// 5. Roadmap alignment
 	results = append(results, c.checkRoadmapAlignment(dir, opts.Version))
 
 	// 6. Deprecation notices
-	results = append(results, c.checkDeprecationNotices(dir, opts.Version))
+	results = append(results, c.checkDeprecationNotices(dir, cl, opts.Version))
 
 	return results
 }
@@ -53,32 +68,34 @@ func (c *PMChecker) checkVersionRecommendation(dir, version string) Result {
 
 	if version == "" {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "No version specified",
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      "No version specified",
+			Remediation: "pass the release version, e.g. --version v1.2.0",
 		}
 	}
 
 	// Validate semver format
-	semverRegex := regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(-[a-zA-Z0-9.-]+)?(\+[a-zA-Z0-9.-]+)?$`)
-	if !semverRegex.MatchString(version) {
+	major, minor, patch, err := semver.Split(version)
+	if err != nil {
 		return Result{
-			Name:   name,
-			Passed: false,
-			Reason: fmt.Sprintf("Version %s does not follow semver format", version),
+			Name:        name,
+			Passed:      false,
+			Reason:      fmt.Sprintf("Version %s does not follow semver format", version),
+			Remediation: "use a vMAJOR.MINOR.PATCH version, e.g. v1.2.0",
 		}
 	}
 
 	// Determine version type
 	versionType := "patch"
-	parts := semverRegex.FindStringSubmatch(version)
-	if len(parts) >= 3 {
-		if parts[1] != "0" && parts[2] == "0" && parts[3] == "0" {
-			versionType = "major"
-		} else if parts[2] != "0" && parts[3] == "0" {
-			versionType = "minor (feature release)"
-		}
+	if major != 0 && minor == 0 && patch == 0 {
+		versionType = "major"
+	} else if minor != 0 && patch == 0 {
+		versionType = "minor (feature release)"
+	}
+	if pre := semver.Prerelease(version); pre != "" {
+		versionType += ", prerelease " + pre
 	}
 
 	return Result{
@@ -88,202 +105,280 @@ func (c *PMChecker) checkVersionRecommendation(dir, version string) Result {
 	}
 }
 
-// checkReleaseScope validates the release scope matches expectations.
-func (c *PMChecker) checkReleaseScope(dir, version string) Result {
-	name := "PM: release-scope"
+// checkChangelogSchema validates CHANGELOG.json against changelog.Schema
+// and reports the path of each violation.
+func (c *PMChecker) checkChangelogSchema(dir string) Result {
+	name := "PM: changelog-schema"
 
-	// Check CHANGELOG.json for the version entry
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
+	data, err := os.ReadFile(filepath.Join(dir, changelog.DefaultFile))
 	if err != nil {
 		return Result{
 			Name:    name,
-			Passed:  false,
-			Warning: true,
+			Skipped: true,
 			Reason:  "CHANGELOG.json not found",
 		}
 	}
 
-	var changelog struct {
-		Releases []struct {
-			Version    string `json:"version"`
-			Highlights []struct {
-				Description string `json:"description"`
-			} `json:"highlights"`
-			Added   []interface{} `json:"added"`
-			Changed []interface{} `json:"changed"`
-			Fixed   []interface{} `json:"fixed"`
-		} `json:"releases"`
+	violations, err := changelog.Validate(data)
+	if err != nil {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      "CHANGELOG.json is not valid JSON: " + err.Error(),
+			Remediation: "fix the JSON syntax of CHANGELOG.json",
+		}
+	}
+	if len(violations) > 0 {
+		lines := make([]string, len(violations))
+		for i, v := range violations {
+			lines[i] = v.String()
+		}
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      fmt.Sprintf("%d schema violation(s):\n%s", len(violations), strings.Join(lines, "\n")),
+			Remediation: "fix CHANGELOG.json at the paths listed",
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: "CHANGELOG.json matches the schema",
+	}
+}
+
+// changelogReadReason describes why the changelog couldn't be loaded.
+func changelogReadReason(err error) string {
+	if os.IsNotExist(err) {
+		return "CHANGELOG.json not found (and no Keep a Changelog CHANGELOG.md)"
+	}
+	return err.Error()
+}
+
+// changelogReadRemediation describes how to make the changelog loadable.
+func changelogReadRemediation(err error) string {
+	if os.IsNotExist(err) {
+		return "create CHANGELOG.json: schangelog init"
 	}
+	return "fix the changelog so it parses"
+}
+
+// checkReleaseScope validates the release scope matches expectations: the
+// changelog has an entry for the version, which documents the labeled pull
+// requests merged since the latest tag and no more.
+func (c *PMChecker) checkReleaseScope(dir string, cl *changelog.Loaded, version string) Result {
+	name := "PM: release-scope"
 
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	if cl.Err != nil {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "Failed to parse CHANGELOG.json",
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      changelogReadReason(cl.Err),
+			Remediation: changelogReadRemediation(cl.Err),
 		}
 	}
 
-	// Find the version entry
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			totalChanges := len(release.Added) + len(release.Changed) + len(release.Fixed)
+	if release := cl.Changelog.Release(version); release != nil {
+		documented := fmt.Sprintf("%d changes documented", release.Count())
+		scope, mismatch := checkScopeAgainstPRs(dir, version, release)
+		if mismatch {
 			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d changes documented", totalChanges),
+				Name:        name,
+				Passed:      false,
+				Warning:     true,
+				Reason:      documented + "; " + scope,
+				Remediation: "add entries for the missing pull requests, and remove those not merged for this release",
+			}
+		}
+		if scope != "" {
+			documented += ", " + scope
+		}
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: documented,
+		}
+	}
+
+	// Keep a Changelog: changes not yet promoted to a version heading
+	if u := cl.Changelog.Unreleased; u != nil {
+		if n := u.Count(); n > 0 {
+			return Result{
+				Name:    name,
+				Passed:  false,
+				Warning: true,
+				Reason: fmt.Sprintf("Version %s not found in %s; %d changes under [Unreleased] (the changelog action promotes them)",
+					version, cl.Source, n),
+				Remediation: "promote [Unreleased]: atrelease changelog --version " + version,
 			}
 		}
 	}
 
 	return Result{
-		Name:    name,
-		Passed:  false,
-		Warning: true,
-		Reason:  fmt.Sprintf("Version %s not found in CHANGELOG.json", version),
+		Name:        name,
+		Passed:      false,
+		Warning:     true,
+		Reason:      fmt.Sprintf("Version %s not found in %s", version, cl.Source),
+		Remediation: fmt.Sprintf("add %s to %s: atrelease changelog", version, cl.Source),
 	}
 }
 
 // checkChangelogQuality validates the changelog has highlights and proper descriptions.
-func (c *PMChecker) checkChangelogQuality(dir, version string) Result {
+func (c *PMChecker) checkChangelogQuality(cl *changelog.Loaded, version string) Result {
 	name := "PM: changelog-quality"
 
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
-	if err != nil {
+	if cl.Err != nil {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "CHANGELOG.json not found",
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      changelogReadReason(cl.Err),
+			Remediation: changelogReadRemediation(cl.Err),
 		}
 	}
 
-	var changelog struct {
-		Releases []struct {
-			Version    string `json:"version"`
-			Highlights []struct {
-				Description string `json:"description"`
-			} `json:"highlights"`
-		} `json:"releases"`
+	release := cl.Changelog.Release(version)
+	if release == nil {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      fmt.Sprintf("Version %s not found in %s", version, cl.Source),
+			Remediation: fmt.Sprintf("add %s to %s: atrelease changelog", version, cl.Source),
+		}
 	}
 
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	blank := 0
+	for _, e := range release.Entries() {
+		if strings.TrimSpace(e.Description) == "" {
+			blank++
+		}
+	}
+	if blank > 0 {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "Failed to parse CHANGELOG.json",
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      fmt.Sprintf("%d entries for %s have no description", blank, version),
+			Remediation: fmt.Sprintf("add descriptions to the %s entries in %s", version, cl.Source),
 		}
 	}
 
-	// Find the version entry
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			if len(release.Highlights) == 0 {
-				return Result{
-					Name:    name,
-					Passed:  false,
-					Warning: true,
-					Reason:  "No highlights for this release",
-				}
-			}
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d highlights present", len(release.Highlights)),
-			}
+	if len(release.Highlights) == 0 {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      "No highlights for this release",
+			Remediation: fmt.Sprintf("add highlights to %s %s", cl.Source, version),
 		}
 	}
-
 	return Result{
-		Name:    name,
-		Passed:  false,
-		Warning: true,
-		Reason:  fmt.Sprintf("Version %s not found in CHANGELOG.json", version),
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("%d highlights present", len(release.Highlights)),
 	}
 }
 
 // checkBreakingChanges validates breaking changes are properly documented.
-func (c *PMChecker) checkBreakingChanges(dir, version string) Result {
+func (c *PMChecker) checkBreakingChanges(cl *changelog.Loaded, version string) Result {
 	name := "PM: breaking-changes"
 
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
-	if err != nil {
+	if cl.Err != nil {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "CHANGELOG.json not found",
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      changelogReadReason(cl.Err),
+			Remediation: changelogReadRemediation(cl.Err),
 		}
 	}
 
-	var changelog struct {
-		Releases []struct {
-			Version string `json:"version"`
-			Changed []struct {
-				Description string `json:"description"`
-				Breaking    bool   `json:"breaking"`
-			} `json:"changed"`
-		} `json:"releases"`
-	}
-
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	release := cl.Changelog.Release(version)
+	if release == nil {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "Failed to parse CHANGELOG.json",
+			Name:   name,
+			Passed: true,
+			Output: "No breaking changes (version not in changelog)",
 		}
 	}
 
-	// Find the version entry and count breaking changes
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			breakingCount := 0
-			for _, change := range release.Changed {
-				if change.Breaking {
-					breakingCount++
-				}
-			}
-
-			if breakingCount == 0 {
-				return Result{
-					Name:   name,
-					Passed: true,
-					Output: "No breaking changes",
-				}
-			}
-
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d breaking changes documented", breakingCount),
-			}
+	breakingCount := breakingEntries(release)
+	if breakingCount == 0 {
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: "No breaking changes",
 		}
 	}
 
 	return Result{
 		Name:   name,
 		Passed: true,
-		Output: "No breaking changes (version not in changelog)",
+		Output: fmt.Sprintf("%d breaking changes documented", breakingCount),
 	}
 }
 
-// checkRoadmapAlignment validates the release aligns with roadmap items.
+// breakingEntries returns the number of breaking changes in a release: its
+// breaking category, plus changes elsewhere flagged breaking.
+func breakingEntries(release *changelog.Release) int {
+	n := len(release.Breaking)
+	for _, e := range slices.Concat(release.Added, release.Changed, release.Deprecated, release.Removed, release.Fixed, release.Security) {
+		if e.Breaking {
+			n++
+		}
+	}
+	return n
+}
+
+// checkRoadmapAlignment validates the release aligns with roadmap items in
+// ROADMAP.json, or in ROADMAP.md if there is no ROADMAP.json.
 func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 	name := "PM: roadmap-alignment"
 
+	r, err := roadmap.Load(filepath.Join(dir, roadmap.DefaultFile))
+	if os.IsNotExist(err) {
+		return c.checkRoadmapMarkdown(dir, version)
+	}
+	if err != nil {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      err.Error(),
+			Remediation: "fix ROADMAP.json so it parses",
+		}
+	}
+
+	items := r.ItemsFor(version)
+	completed := 0
+	var pending []string
+	for _, item := range items {
+		if item.Status == roadmap.StatusCompleted {
+			completed++
+		} else {
+			pending = append(pending, item.Title)
+		}
+	}
+	return roadmapAlignment(name, version, completed, len(items), pending)
+}
+
+// checkRoadmapMarkdown counts the roadmap items for version in the
+// checkbox headings of ROADMAP.md.
+func (c *PMChecker) checkRoadmapMarkdown(dir, version string) Result {
+	name := "PM: roadmap-alignment"
+
 	roadmapPath := filepath.Join(dir, "ROADMAP.md")
 	data, err := os.ReadFile(roadmapPath)
 	if err != nil {
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  "ROADMAP.md not found",
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      "ROADMAP.json and ROADMAP.md not found",
+			Remediation: "add a ROADMAP.json with the release's items",
 		}
 	}
 
@@ -298,8 +393,12 @@ func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 
 	completed := len(completedPattern.FindAllString(content, -1))
 	pending := len(pendingPattern.FindAllString(content, -1))
-	total := completed + pending
+	return roadmapAlignment(name, version, completed, completed+pending, nil)
+}
 
+// roadmapAlignment reports completed of total roadmap items for version,
+// naming the pending ones if known.
+func roadmapAlignment(name, version string, completed, total int, pending []string) Result {
 	if total == 0 {
 		return Result{
 			Name:    name,
@@ -309,12 +408,17 @@ func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 		}
 	}
 
-	if pending > 0 {
+	if completed < total {
+		reason := fmt.Sprintf("%d/%d roadmap items completed (%d pending)", completed, total, total-completed)
+		if len(pending) > 0 {
+			reason += ": " + strings.Join(pending, ", ")
+		}
 		return Result{
-			Name:    name,
-			Passed:  false,
-			Warning: true,
-			Reason:  fmt.Sprintf("%d/%d roadmap items completed (%d pending)", completed, total, pending),
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      reason,
+			Remediation: "complete the pending items, or move them to a later version in the roadmap",
 		}
 	}
 
@@ -325,58 +429,53 @@ func (c *PMChecker) checkRoadmapAlignment(dir, version string) Result {
 	}
 }
 
-// checkDeprecationNotices validates deprecated features are properly documented.
-func (c *PMChecker) checkDeprecationNotices(dir, version string) Result {
+// checkDeprecationNotices validates deprecated features are properly
+// documented: the changelog's deprecation entries for the version match
+// the exported Go identifiers given a "Deprecated:" doc comment since the
+// latest tag.
+func (c *PMChecker) checkDeprecationNotices(dir string, cl *changelog.Loaded, version string) Result {
 	name := "PM: deprecation-notices"
 
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
-	data, err := os.ReadFile(changelogPath)
-	if err != nil {
+	if cl.Missing() {
 		return Result{
 			Name:   name,
 			Passed: true,
-			Output: "No deprecations (CHANGELOG.json not found)",
+			Output: "No deprecations (no changelog found)",
 		}
 	}
-
-	var changelog struct {
-		Releases []struct {
-			Version    string `json:"version"`
-			Deprecated []struct {
-				Description string `json:"description"`
-			} `json:"deprecated"`
-		} `json:"releases"`
-	}
-
-	if err := json.Unmarshal(data, &changelog); err != nil {
+	if cl.Err != nil {
 		return Result{
 			Name:   name,
 			Passed: true,
-			Output: "No deprecations (could not parse changelog)",
+			Output: "No deprecations (could not parse changelog: " + cl.Err.Error() + ")",
 		}
 	}
 
-	// Find the version entry
-	for _, release := range changelog.Releases {
-		if release.Version == version {
-			if len(release.Deprecated) == 0 {
-				return Result{
-					Name:   name,
-					Passed: true,
-					Output: "No deprecations",
-				}
-			}
-			return Result{
-				Name:   name,
-				Passed: true,
-				Output: fmt.Sprintf("%d deprecation notices", len(release.Deprecated)),
-			}
-		}
+	var entries []changelog.Entry
+	if release := cl.Changelog.Release(version); release != nil {
+		entries = release.Deprecated
+	}
+	summary := "No deprecations"
+	if len(entries) > 0 {
+		summary = fmt.Sprintf("%d deprecation notices", len(entries))
 	}
 
+	code, mismatch := checkDeprecationsAgainstCode(dir, entries)
+	if mismatch {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Warning:     true,
+			Reason:      summary + "; " + code,
+			Remediation: "add a Deprecated changelog entry for each identifier, and a // Deprecated: doc comment for each entry",
+		}
+	}
+	if code != "" {
+		summary += ", " + code
+	}
 	return Result{
 		Name:   name,
 		Passed: true,
-		Output: "No deprecations",
+		Output: summary,
 	}
 }
@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/roadmap"
+)
+
+func loadedChangelog(releases ...changelog.Release) *changelog.Loaded {
+	return &changelog.Loaded{
+		Changelog: &changelog.Changelog{Releases: releases},
+		Source:    changelog.DefaultFile,
+	}
+}
+
+func TestPMChecker_ChangelogQuality(t *testing.T) {
+	c := &PMChecker{}
+	highlights := []changelog.Entry{{Description: "New status command"}}
+
+	r := c.checkChangelogQuality(loadedChangelog(changelog.Release{Version: "v1.0.0", Highlights: highlights}), "1.0.0")
+	if !r.Passed {
+		t.Errorf("release with highlights: %+v", r)
+	}
+
+	r = c.checkChangelogQuality(loadedChangelog(changelog.Release{
+		Version:    "v1.0.0",
+		Highlights: highlights,
+		Fixed:      []changelog.Entry{{Description: " "}, {Description: "Crash on empty config"}},
+	}), "v1.0.0")
+	if r.Passed || !r.Warning || !strings.Contains(r.Reason, "1 entries") {
+		t.Errorf("blank description: %+v", r)
+	}
+
+	r = c.checkChangelogQuality(loadedChangelog(changelog.Release{Version: "v0.9.0"}), "v1.0.0")
+	if r.Passed || !strings.Contains(r.Reason, "not found in CHANGELOG.json") {
+		t.Errorf("missing version: %+v", r)
+	}
+}
+
+func TestPMChecker_BreakingChanges(t *testing.T) {
+	c := &PMChecker{}
+	r := c.checkBreakingChanges(loadedChangelog(changelog.Release{
+		Version:  "v2.0.0",
+		Breaking: []changelog.Entry{{Description: "Removed legacy flag"}},
+		Changed:  []changelog.Entry{{Description: "Renamed config key", Breaking: true}, {Description: "Faster checks"}},
+	}), "v2.0.0")
+	if r.Output != "2 breaking changes documented" {
+		t.Errorf("Output = %q, want 2 breaking changes", r.Output)
+	}
+}
+
+func TestPMChecker_SharedChangelogErrors(t *testing.T) {
+	c := &PMChecker{}
+	missing := changelog.LoadShared(t.TempDir())
+
+	if r := c.checkReleaseScope(t.TempDir(), missing, "v1.0.0"); r.Passed || !strings.Contains(r.Reason, "not found") {
+		t.Errorf("release scope: %+v", r)
+	}
+	if r := c.checkDeprecationNotices(t.TempDir(), missing, "v1.0.0"); !r.Passed || r.Output != "No deprecations (no changelog found)" {
+		t.Errorf("deprecations: %+v", r)
+	}
+}
+
+func TestPMChecker_RoadmapAlignment(t *testing.T) {
+	c := &PMChecker{}
+	dir := t.TempDir()
+
+	r := roadmap.New("demo")
+	r.Items = []roadmap.Item{
+		{ID: "a", Title: "Status command", Status: roadmap.StatusCompleted, Version: "1.1.0"},
+		{ID: "b", Title: "Offline mode", Status: roadmap.StatusInProgress, Version: "v1.1.0"},
+		{ID: "c", Title: "Plugins", Status: roadmap.StatusPlanned, Version: "1.2.0"},
+	}
+	if err := r.Save(filepath.Join(dir, roadmap.DefaultFile)); err != nil {
+		t.Fatal(err)
+	}
+	// ROADMAP.md formatting doesn't matter once ROADMAP.json exists
+	if err := os.WriteFile(filepath.Join(dir, "ROADMAP.md"), []byte("# Roadmap\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res := c.checkRoadmapAlignment(dir, "v1.1.0")
+	if res.Passed || res.Reason != "1/2 roadmap items completed (1 pending): Offline mode" {
+		t.Errorf("v1.1.0: %+v", res)
+	}
+	res = c.checkRoadmapAlignment(dir, "v1.3.0")
+	if !res.Passed || !res.Warning {
+		t.Errorf("v1.3.0: %+v", res)
+	}
+}
+
+func TestPMChecker_RoadmapAlignmentMarkdown(t *testing.T) {
+	c := &PMChecker{}
+	dir := t.TempDir()
+	md := "### [x] Status command\n\n**Version:** 1.1.0\n\n### [ ] Offline mode\n\n**Version:** 1.1.0\n"
+	if err := os.WriteFile(filepath.Join(dir, "ROADMAP.md"), []byte(md), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res := c.checkRoadmapAlignment(dir, "v1.1.0")
+	if res.Passed || res.Reason != "1/2 roadmap items completed (1 pending)" {
+		t.Errorf("markdown fallback: %+v", res)
+	}
+}
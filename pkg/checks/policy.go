@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// CommandPolicy restricts which external binaries checks may run. Checks
+// that need a binary the policy doesn't allow are skipped with a reason
+// instead of running it.
+type CommandPolicy struct {
+	Allow []string // Binaries checks may run; empty allows any binary not denied
+	Deny  []string // Binaries checks may never run, even if allowed
+}
+
+// Allows reports whether the policy permits running command, matched by
+// its base name.
+func (p CommandPolicy) Allows(command string) bool {
+	name := filepath.Base(command)
+	if slices.Contains(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || slices.Contains(p.Allow, name)
+}
+
+// commandPolicy is the policy applied by RunCommand and the checks that
+// run commands directly. Validation areas run concurrently, so it is
+// guarded by policyMu.
+var (
+	policyMu      sync.RWMutex
+	commandPolicy CommandPolicy
+)
+
+// SetCommandPolicy sets the policy for all subsequent checks.
+func SetCommandPolicy(p CommandPolicy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	commandPolicy = p
+}
+
+// CommandAllowed reports whether checks may run command under the current
+// policy.
+func CommandAllowed(command string) bool {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return commandPolicy.Allows(command)
+}
+
+// disallowed returns a skipped result for check name if any of commands is
+// not allowed, and whether one wasn't.
+func disallowed(name string, commands ...string) (Result, bool) {
+	for _, command := range commands {
+		if !CommandAllowed(command) {
+			return Result{
+				Name:    name,
+				Skipped: true,
+				Reason:  fmt.Sprintf("%s is not allowed by the tools policy", filepath.Base(command)),
+			}, true
+		}
+	}
+	return Result{}, false
+}
@@ -0,0 +1,45 @@
+package checks
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandPolicy_Allows(t *testing.T) {
+	tests := []struct {
+		policy  CommandPolicy
+		command string
+		want    bool
+	}{
+		{CommandPolicy{}, "go", true},
+		{CommandPolicy{Allow: []string{"go", "git"}}, "git", true},
+		{CommandPolicy{Allow: []string{"go", "git"}}, "npm", false},
+		{CommandPolicy{Allow: []string{"docusaurus"}}, "site/node_modules/.bin/docusaurus", true},
+		{CommandPolicy{Deny: []string{"grep"}}, "grep", false},
+		{CommandPolicy{Allow: []string{"grep"}, Deny: []string{"grep"}}, "grep", false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Allows(tt.command); got != tt.want {
+			t.Errorf("%+v.Allows(%q) = %v, want %v", tt.policy, tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestRunCommand_Disallowed(t *testing.T) {
+	SetCommandPolicy(CommandPolicy{Deny: []string{"echo"}})
+	defer SetCommandPolicy(CommandPolicy{})
+
+	result := RunCommand("echo", ".", "echo", "hello")
+	if !result.Skipped || result.Passed {
+		t.Fatalf("expected denied command to be skipped, got %+v", result)
+	}
+	if !strings.Contains(result.Reason, "echo is not allowed") {
+		t.Errorf("unexpected reason: %q", result.Reason)
+	}
+
+	SetCommandPolicy(CommandPolicy{Allow: []string{"go"}})
+	results, err := RunReleasekit(".", DefaultOptions())
+	if err != nil || len(results) != 1 || !results[0].Skipped {
+		t.Errorf("expected releasekit to be skipped, got %+v (%v)", results, err)
+	}
+}
@@ -0,0 +1,150 @@
+package checks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeat is how often a still-running command is reported.
+const DefaultHeartbeat = 15 * time.Second
+
+// Progress reports on commands while they run, so long checks such as
+// `go test ./...` don't look hung in hooks.
+type Progress struct {
+	Log       func(format string, args ...any) // Receives progress lines; nil disables progress
+	Verbose   bool                             // Also stream command output, line by line
+	Heartbeat time.Duration                    // Interval between heartbeats; 0 for DefaultHeartbeat
+}
+
+// progress is the progress reporting used by the checks that run
+// commands, guarded by progressMu since validation areas run concurrently.
+var (
+	progressMu sync.RWMutex
+	progress   Progress
+)
+
+// SetProgress sets progress reporting for all subsequent checks.
+func SetProgress(p Progress) {
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	progress = p
+}
+
+// progressLog serializes progress lines from the heartbeat and output
+// streaming goroutines.
+type progressLog struct {
+	mu  sync.Mutex
+	log func(format string, args ...any)
+}
+
+func (l *progressLog) printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.log(format, args...)
+}
+
+// lineWriter passes each complete line written to it to a progressLog.
+type lineWriter struct {
+	log     *progressLog
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+}
+
+// flush emits a final line without a trailing newline.
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line []byte) {
+	w.log.printf("    │ %s", strings.TrimRight(string(line), "\r"))
+}
+
+// runOutput runs cmd and returns its standard output, like cmd.Output,
+// with standard error in any *exec.ExitError. With combined, standard
+// error is interleaved into the returned output instead, like
+// cmd.CombinedOutput. While cmd runs, a heartbeat naming label is
+// reported, and with verbose progress its output is streamed.
+func runOutput(cmd *exec.Cmd, label string, combined bool) ([]byte, error) {
+	progressMu.RLock()
+	p := progress
+	progressMu.RUnlock()
+	if p.Log == nil {
+		if combined {
+			return cmd.CombinedOutput()
+		}
+		return cmd.Output()
+	}
+
+	log := &progressLog{log: p.Log}
+	var stdout, stderr bytes.Buffer
+	var streams []*lineWriter
+	tee := func(buf *bytes.Buffer) io.Writer {
+		if !p.Verbose {
+			return buf
+		}
+		w := &lineWriter{log: log}
+		streams = append(streams, w)
+		return io.MultiWriter(buf, w)
+	}
+	if combined {
+		cmd.Stdout = tee(&stdout)
+		cmd.Stderr = cmd.Stdout
+	} else {
+		cmd.Stdout = tee(&stdout)
+		cmd.Stderr = tee(&stderr)
+	}
+
+	interval := p.Heartbeat
+	if interval <= 0 {
+		interval = DefaultHeartbeat
+	}
+	start := time.Now()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				log.printf("  … %s still running (%s)", label, time.Since(start).Round(time.Second))
+			}
+		}
+	}()
+
+	err := cmd.Run()
+	close(done)
+	wg.Wait()
+	for _, w := range streams {
+		w.flush()
+	}
+
+	var exitErr *exec.ExitError
+	if !combined && errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	return stdout.Bytes(), err
+}
@@ -0,0 +1,103 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// captureProgress sets progress reporting to a log it returns, restoring
+// the default when the test ends.
+func captureProgress(t *testing.T, verbose bool) func() []string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("progress tests run shell commands")
+	}
+	var mu sync.Mutex
+	var lines []string
+	SetProgress(Progress{
+		Log: func(format string, args ...any) {
+			mu.Lock()
+			defer mu.Unlock()
+			lines = append(lines, fmt.Sprintf(format, args...))
+		},
+		Verbose:   verbose,
+		Heartbeat: 20 * time.Millisecond,
+	})
+	t.Cleanup(func() { SetProgress(Progress{}) })
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), lines...)
+	}
+}
+
+func TestRunCommand_Heartbeat(t *testing.T) {
+	logged := captureProgress(t, false)
+
+	r := RunCommand("Go: tests", t.TempDir(), "sh", "-c", "sleep 0.2; echo ok")
+	if !r.Passed || r.Output != "ok" {
+		t.Fatalf("result = %+v", r)
+	}
+
+	lines := logged()
+	if len(lines) == 0 {
+		t.Fatal("no heartbeat logged")
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "Go: tests still running") {
+			t.Errorf("unexpected progress line %q", line)
+		}
+	}
+}
+
+func TestRunOutput_Verbose(t *testing.T) {
+	logged := captureProgress(t, true)
+
+	cmd := exec.Command("sh", "-c", "echo one; echo two >&2; printf three; exit 3")
+	out, err := runOutput(cmd, "script", false)
+	if string(out) != "one\nthree" {
+		t.Errorf("output = %q", out)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("err = %v, want exit status 3", err)
+	}
+	if string(exitErr.Stderr) != "two\n" {
+		t.Errorf("stderr = %q", exitErr.Stderr)
+	}
+
+	var streamed []string
+	for _, line := range logged() {
+		if !strings.Contains(line, "still running") {
+			streamed = append(streamed, strings.TrimSpace(line))
+		}
+	}
+	for _, want := range []string{"│ one", "│ two", "│ three"} {
+		found := false
+		for _, line := range streamed {
+			found = found || line == want
+		}
+		if !found {
+			t.Errorf("%q not streamed in %q", want, streamed)
+		}
+	}
+}
+
+func TestRunOutput_NoProgress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("progress tests run shell commands")
+	}
+	out, err := runOutput(exec.Command("sh", "-c", "echo out; echo err >&2"), "script", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "out") || !strings.Contains(string(out), "err") {
+		t.Errorf("combined output = %q", out)
+	}
+}
@@ -0,0 +1,151 @@
+package checks
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/qametrics"
+)
+
+var (
+	jsTestCasePattern = regexp.MustCompile(`(?m)^\s*(?:it|test)(?:\.\w+)?\(`)
+	pyTestFuncPattern = regexp.MustCompile(`(?m)^\s*(?:async\s+)?def test_\w*\(`)
+	coveragePattern   = regexp.MustCompile(`(\d+(?:\.\d+)?)%`)
+)
+
+// QAMetrics returns the QA metrics of the project in dir for version: the
+// test functions and cases in its test files, and the coverage, lint
+// findings, and build time of the QA check results.
+func QAMetrics(dir, version string, results []Result) qametrics.Metrics {
+	m := qametrics.Metrics{
+		Version: version,
+		Tests:   countTests(dir),
+	}
+
+	var coverage float64
+	covered := 0
+	for _, r := range results {
+		id := ResultID(r)
+		switch {
+		case strings.HasSuffix(id, ".coverage"):
+			if c := coveragePattern.FindStringSubmatch(r.Output); c != nil {
+				pct, _ := strconv.ParseFloat(c[1], 64)
+				coverage += pct
+				covered++
+			}
+		case IsLintCheck(id) && r.Findings != nil:
+			m.LintIssues += len(r.Findings)
+		case IsLintCheck(id):
+			for _, line := range strings.Split(r.Output, "\n") {
+				if findingLine.MatchString(line) {
+					m.LintIssues++
+				}
+			}
+		case strings.HasSuffix(id, ".build"):
+			m.BuildMs += r.Duration.Milliseconds()
+		}
+	}
+	// Languages reporting coverage are weighted alike
+	if covered > 0 {
+		avg := coverage / float64(covered)
+		m.Coverage = &avg
+	}
+	return m
+}
+
+// countTests counts the Go test functions, JavaScript and TypeScript test
+// cases, and Python test functions under dir, skipping dependencies,
+// test data, and hidden directories.
+func countTests(dir string) int {
+	n := 0
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules" || name == "testdata") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		var pattern *regexp.Regexp
+		switch {
+		case strings.HasSuffix(name, "_test.go"):
+			pattern = goTestFuncPattern
+		case isJSTestFile(path):
+			pattern = jsTestCasePattern
+		case strings.HasSuffix(name, ".py") && (strings.HasPrefix(name, "test_") || strings.HasSuffix(name, "_test.py")):
+			pattern = pyTestFuncPattern
+		default:
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			n += len(pattern.FindAllIndex(data, -1))
+		}
+		return nil
+	})
+	return n
+}
+
+// isJSTestFile reports whether path is a JavaScript or TypeScript test,
+// e.g. foo.test.ts or __tests__/foo.ts.
+func isJSTestFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+	default:
+		return false
+	}
+	base := filepath.Base(path)
+	return strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		filepath.Base(filepath.Dir(path)) == "__tests__"
+}
+
+// CheckQABaseline compares the QA metrics of the release candidate with
+// those recorded for the previous release in .release-agent/metrics.json.
+// Regressions are warnings unless cfg.Fail is set.
+func CheckQABaseline(dir, version string, results []Result, cfg config.QABaselineConfig) Result {
+	name := "QA: metrics baseline"
+
+	history, err := qametrics.Load(dir)
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	prev, ok := history.Previous(version)
+	if !ok {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "No metrics recorded for a previous release",
+		}
+	}
+
+	cur := QAMetrics(dir, version, results)
+	regressions := qametrics.Compare(prev, cur, qametrics.Limits{
+		MaxCoverageDrop:  cfg.MaxCoverageDrop,
+		MaxBuildIncrease: cfg.MaxBuildIncrease,
+	})
+	if len(regressions) > 0 {
+		return Result{
+			Name:        name,
+			Warning:     !cfg.Fail,
+			Passed:      false,
+			Output:      fmt.Sprintf("Regressed since %s:\n%s", prev.Version, strings.Join(regressions, "\n")),
+			Remediation: "restore the removed tests, cover the new code, and fix the new lint findings",
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("No regressions since %s", prev.Version),
+	}
+}
@@ -0,0 +1,93 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/qametrics"
+)
+
+func writeQAProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"a_test.go":                    "package a\n\nfunc TestA(t *testing.T) {}\n\nfunc TestB(t *testing.T) {}\n",
+		"web/src/app.test.ts":          "describe('app', () => {\n  it('renders', () => {})\n  it.skip('later', () => {})\n})\n",
+		"web/node_modules/x/x.test.js": "test('dependency', () => {})\n",
+		"vendor/v/v_test.go":           "func TestVendored(t *testing.T) {}\n",
+		"py/test_util.py":              "def test_one():\n    pass\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestQAMetrics(t *testing.T) {
+	dir := writeQAProject(t)
+	results := []Result{
+		{Name: "Go: build", Passed: true, Duration: 2 * time.Second},
+		{Name: "TypeScript: build", Passed: true, Duration: time.Second},
+		{Name: "Go: coverage", Passed: true, Output: "coverage: 80.0% of statements"},
+		{Name: "TypeScript: coverage", Passed: true, Output: "70%"},
+		{Name: "Go: lint", Warning: true, Output: "a.go:3:1: exported A should have comment\nb.go:9: unused\n2 issues"},
+	}
+
+	m := QAMetrics(dir, "v1.1.0", results)
+	if m.Version != "v1.1.0" || m.Tests != 5 || m.LintIssues != 2 || m.BuildMs != 3000 {
+		t.Errorf("QAMetrics() = %+v", m)
+	}
+	if m.Coverage == nil || *m.Coverage != 75 {
+		t.Errorf("Coverage = %v, want 75", m.Coverage)
+	}
+}
+
+func TestCheckQABaseline(t *testing.T) {
+	dir := writeQAProject(t)
+	cfg := config.DefaultConfig().QABaseline
+
+	r := CheckQABaseline(dir, "v1.1.0", nil, cfg)
+	if !r.Skipped {
+		t.Fatalf("without a baseline: %+v, want skipped", r)
+	}
+
+	var h qametrics.History
+	h.Record(qametrics.Metrics{Version: "v1.0.0", Tests: 7, LintIssues: 0})
+	if err := qametrics.Save(dir, h); err != nil {
+		t.Fatal(err)
+	}
+
+	r = CheckQABaseline(dir, "v1.1.0", nil, cfg)
+	if r.Severity() != SeverityWarning || !strings.Contains(r.Output, "since v1.0.0") ||
+		!strings.Contains(r.Output, "tests: 7 -> 5 (-2)") || r.Remediation == "" {
+		t.Errorf("regressed: %+v", r)
+	}
+
+	cfg.Fail = true
+	if r = CheckQABaseline(dir, "v1.1.0", nil, cfg); r.Severity() != SeverityFailed {
+		t.Errorf("regressed with fail: %+v, want failed", r)
+	}
+
+	// The previous release of v1.0.0 is none
+	if r = CheckQABaseline(dir, "v1.0.0", nil, cfg); !r.Skipped {
+		t.Errorf("re-validating the only release: %+v, want skipped", r)
+	}
+
+	h.Record(qametrics.Metrics{Version: "v1.0.1", Tests: 5})
+	if err := qametrics.Save(dir, h); err != nil {
+		t.Fatal(err)
+	}
+	if r = CheckQABaseline(dir, "v1.1.0", nil, cfg); !r.Passed {
+		t.Errorf("no regression: %+v, want passed", r)
+	}
+}
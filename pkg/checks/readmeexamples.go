@@ -0,0 +1,278 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// ReadmeCodeBlock is a fenced code block in a Markdown document.
+type ReadmeCodeBlock struct {
+	Lang string // First word of the info string, lowercased
+	Line int    // Line number of the first line of code
+	Code string
+}
+
+// ReadmeLink is a relative link or image reference in a Markdown document.
+type ReadmeLink struct {
+	Target string // Path as written, without any #fragment or ?query
+	Line   int
+}
+
+var (
+	mdFence     = regexp.MustCompile("^\\s{0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	mdLinkRef   = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	mdLinkDef   = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*<?(\S+?)>?(?:\s+.*)?$`)
+	htmlSrcHref = regexp.MustCompile(`(?i)<(?:img|a|source|video)\b[^>]*?\s(?:src|href)\s*=\s*["']([^"']+)["']`)
+	inlineCode  = regexp.MustCompile("`[^`]*`")
+	goBuildErr  = regexp.MustCompile(`(?m)^\.?/?block(\d+)/main\.go:(\d+)(?::\d+)?: (.*)$`)
+)
+
+// ParseReadme returns the fenced code blocks and the relative links (to
+// files in the repository, not URLs or anchors) outside code in a Markdown
+// document.
+func ParseReadme(data []byte) ([]ReadmeCodeBlock, []ReadmeLink) {
+	var blocks []ReadmeCodeBlock
+	var links []ReadmeLink
+
+	var fence string
+	var block *ReadmeCodeBlock
+	var code strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		if block != nil {
+			trimmed := strings.TrimSpace(text)
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				block.Code = code.String()
+				blocks = append(blocks, *block)
+				block = nil
+				continue
+			}
+			code.WriteString(text + "\n")
+			continue
+		}
+
+		if m := mdFence.FindStringSubmatch(text); m != nil {
+			fence = m[1]
+			block = &ReadmeCodeBlock{Lang: strings.ToLower(m[2]), Line: line + 1}
+			code.Reset()
+			continue
+		}
+
+		text = inlineCode.ReplaceAllString(text, "")
+		var targets []string
+		for _, re := range []*regexp.Regexp{mdLinkRef, mdLinkDef, htmlSrcHref} {
+			for _, m := range re.FindAllStringSubmatch(text, -1) {
+				targets = append(targets, m[1])
+			}
+		}
+		for _, target := range targets {
+			if path, ok := localLinkPath(target); ok {
+				links = append(links, ReadmeLink{Target: path, Line: line})
+			}
+		}
+	}
+	return blocks, links
+}
+
+// localLinkPath returns the file path of a relative link, or false for
+// URLs, anchors, and other schemes.
+func localLinkPath(target string) (string, bool) {
+	if target == "" || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "//") {
+		return "", false
+	}
+	if u, err := url.Parse(target); err != nil || u.Scheme != "" {
+		return "", false
+	}
+	path, _, _ := strings.Cut(target, "#")
+	path, _, _ = strings.Cut(path, "?")
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	return path, path != ""
+}
+
+// checkReadmeLinks verifies that files and images referenced by relative
+// links in README.md exist.
+func (c *DocChecker) checkReadmeLinks(dir string) Result {
+	name := "Docs: README links"
+
+	data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		return Result{Name: name, Skipped: true, Reason: "README.md not found"}
+	}
+
+	_, links := ParseReadme(data)
+	if len(links) == 0 {
+		return Result{Name: name, Skipped: true, Reason: "No relative links in README.md"}
+	}
+
+	var missing []string
+	for _, l := range links {
+		path := filepath.Join(dir, filepath.FromSlash(strings.TrimPrefix(l.Target, "/")))
+		if !FileExists(path) {
+			missing = append(missing, fmt.Sprintf("README.md:%d: %s", l.Line, l.Target))
+		}
+	}
+	if len(missing) > 0 {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      fmt.Sprintf("%d of %d referenced files not found:\n  %s", len(missing), len(links), strings.Join(missing, "\n  ")),
+			Remediation: "fix the links in README.md, or add the files they reference",
+		}
+	}
+	return Result{Name: name, Passed: true, Output: fmt.Sprintf("%d referenced files found", len(links))}
+}
+
+// checkReadmeGoExamples builds the Go code blocks in README.md that are
+// complete files (start with a package clause). Each block becomes a
+// package in a temporary module; when dir is a Go module, a go.work file
+// lets the blocks import its packages.
+func (c *DocChecker) checkReadmeGoExamples(dir string) Result {
+	name := "Docs: README Go examples"
+
+	data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		return Result{Name: name, Skipped: true, Reason: "README.md not found"}
+	}
+
+	blocks, _ := ParseReadme(data)
+	var examples []ReadmeCodeBlock
+	fragments := 0
+	for _, b := range blocks {
+		if b.Lang != "go" && b.Lang != "golang" {
+			continue
+		}
+		if isGoFile(b.Code) {
+			examples = append(examples, b)
+		} else {
+			fragments++
+		}
+	}
+	if len(examples) == 0 {
+		reason := "No Go code blocks in README.md"
+		if fragments > 0 {
+			reason = fmt.Sprintf("No complete Go files in README.md (%d fragments without a package clause)", fragments)
+		}
+		return Result{Name: name, Skipped: true, Reason: reason}
+	}
+	if r, ok := disallowed(name, "go"); ok {
+		return r
+	}
+	if !CommandExists("go") {
+		return Result{Name: name, Skipped: true, Reason: "go not installed"}
+	}
+
+	tmp, err := os.MkdirTemp("", "atrelease-readme-")
+	if err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := writeReadmeModule(tmp, dir, examples); err != nil {
+		return Result{Name: name, Passed: false, Error: err}
+	}
+
+	cmd := proc.Command("go", "build", "-o", os.DevNull, "./...")
+	cmd.Dir = tmp
+	cmd.Env = append(os.Environ(), "GOWORK="+workFile(tmp, dir), "GOFLAGS=-mod=readonly")
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		summary := fmt.Sprintf("%d Go examples build", len(examples))
+		if fragments > 0 {
+			summary += fmt.Sprintf(" (%d fragments skipped)", fragments)
+		}
+		return Result{Name: name, Passed: true, Output: summary}
+	}
+
+	// Report errors against README.md lines
+	output := goBuildErr.ReplaceAllStringFunc(string(out), func(m string) string {
+		sub := goBuildErr.FindStringSubmatch(m)
+		i, _ := strconv.Atoi(sub[1])
+		l, _ := strconv.Atoi(sub[2])
+		return fmt.Sprintf("README.md:%d: %s", examples[i].Line+l-1, sub[3])
+	})
+	return Result{
+		Name:        name,
+		Passed:      false,
+		Output:      lastLines(strings.TrimSpace(output), 20),
+		Remediation: "update the README.md examples to the current API",
+	}
+}
+
+func isGoFile(code string) bool {
+	for _, line := range strings.Split(code, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+		return strings.HasPrefix(line, "package ")
+	}
+	return false
+}
+
+// writeReadmeModule writes each example to tmp/block<N>/main.go with a
+// go.mod, and a go.work using dir when it is a Go module.
+func writeReadmeModule(tmp, dir string, examples []ReadmeCodeBlock) error {
+	goVersion := ""
+	if d, err := ParseGoModDirectives(filepath.Join(dir, "go.mod")); err == nil {
+		goVersion = d.Go
+	}
+
+	gomod := "module atrelease.local/readme\n"
+	if goVersion != "" {
+		gomod += "\ngo " + goVersion + "\n"
+	}
+	if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte(gomod), 0644); err != nil {
+		return err
+	}
+
+	if FileExists(filepath.Join(dir, "go.mod")) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		work := "use (\n\t.\n\t" + strconv.Quote(abs) + "\n)\n"
+		if goVersion != "" {
+			work = "go " + goVersion + "\n\n" + work
+		}
+		if err := os.WriteFile(filepath.Join(tmp, "go.work"), []byte(work), 0644); err != nil {
+			return err
+		}
+	}
+
+	for i, b := range examples {
+		pkgDir := filepath.Join(tmp, fmt.Sprintf("block%d", i))
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(pkgDir, "main.go"), []byte(b.Code), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workFile returns the GOWORK value for the example module: its go.work if
+// one was written, otherwise "off".
+func workFile(tmp, dir string) string {
+	if FileExists(filepath.Join(dir, "go.mod")) {
+		return filepath.Join(tmp, "go.work")
+	}
+	return "off"
+}
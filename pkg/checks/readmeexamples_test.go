@@ -0,0 +1,94 @@
+package checks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testReadme = "# Demo\n" +
+	"\n" +
+	"![Screenshot](docs/screenshot.png \"Screen\") and [guide](docs/guide.md#setup).\n" +
+	"See [site](https://example.com), [top](#demo), `[code](not/a/link.md)`, and <img src=\"assets/logo.svg\">.\n" +
+	"\n" +
+	"```go\n" +
+	"package main\n" +
+	"\n" +
+	"func main() {}\n" +
+	"```\n" +
+	"\n" +
+	"~~~golang\n" +
+	"x := demo.New()\n" +
+	"~~~\n" +
+	"\n" +
+	"```bash\n" +
+	"cat [x](missing.md)\n" +
+	"```\n" +
+	"\n" +
+	"[ref]: examples/main.go\n"
+
+func TestParseReadme(t *testing.T) {
+	blocks, links := ParseReadme([]byte(testReadme))
+
+	if len(blocks) != 3 {
+		t.Fatalf("len(blocks) = %d, want 3", len(blocks))
+	}
+	if blocks[0].Lang != "go" || blocks[0].Line != 7 || blocks[0].Code != "package main\n\nfunc main() {}\n" {
+		t.Errorf("blocks[0] = %+v", blocks[0])
+	}
+	if blocks[1].Lang != "golang" || isGoFile(blocks[1].Code) {
+		t.Errorf("blocks[1] = %+v, want golang fragment", blocks[1])
+	}
+
+	var got []string
+	for _, l := range links {
+		got = append(got, l.Target)
+	}
+	want := []string{"docs/screenshot.png", "docs/guide.md", "assets/logo.svg", "examples/main.go"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("links = %v, want %v", got, want)
+	}
+}
+
+func TestCheckReadmeLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "README.md"), testReadme)
+	writeTestFile(t, filepath.Join(dir, "docs", "guide.md"), "# Guide\n")
+	writeTestFile(t, filepath.Join(dir, "assets", "logo.svg"), "<svg/>")
+	c := &DocChecker{}
+
+	r := c.checkReadmeLinks(dir)
+	if r.Passed || !strings.Contains(r.Output, "README.md:3: docs/screenshot.png") || !strings.Contains(r.Output, "README.md:20: examples/main.go") {
+		t.Errorf("got %+v, want missing screenshot and example", r)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "docs", "screenshot.png"), "png")
+	writeTestFile(t, filepath.Join(dir, "examples", "main.go"), "package main\n")
+	if r := c.checkReadmeLinks(dir); !r.Passed {
+		t.Errorf("got %+v, want pass", r)
+	}
+}
+
+func TestCheckReadmeGoExamples(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/demo\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "demo.go"), "package demo\n\n// Hello returns a greeting.\nfunc Hello() string { return \"hi\" }\n")
+	readme := "# Demo\n\n```go\npackage main\n\nimport \"example.com/demo\"\n\nfunc main() { println(demo.Hello()) }\n```\n"
+	writeTestFile(t, filepath.Join(dir, "README.md"), readme)
+	c := &DocChecker{}
+
+	if r := c.checkReadmeGoExamples(dir); !r.Passed {
+		t.Fatalf("got %+v, want pass", r)
+	}
+
+	readme += "\n```go\npackage main\n\nfunc main() {\n\tundefined()\n}\n```\n"
+	writeTestFile(t, filepath.Join(dir, "README.md"), readme)
+	r := c.checkReadmeGoExamples(dir)
+	if r.Passed || !strings.Contains(r.Output, "README.md:15: undefined: undefined") {
+		t.Errorf("got %+v, want build error at README.md:15", r)
+	}
+}
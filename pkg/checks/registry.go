@@ -0,0 +1,136 @@
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// AreaOptions configures the checks of a registered area.
+type AreaOptions struct {
+	Version   string            // Target version (e.g., "v0.5.0")
+	Changelog *changelog.Loaded // Changelog shared with other checks; nil loads it from dir
+	Verbose   bool
+}
+
+// AreaDefinition is a validation area beyond the built-in ones, such as
+// "Legal" or "Localization". Its results count toward the Go/No-Go
+// verdict like any other area's.
+type AreaDefinition struct {
+	Area ValidationArea
+	// DependsOn are the areas that must finish before this one runs;
+	// their teams also come before its team in team reports. The Release
+	// area always comes after it. Default: PM.
+	DependsOn []ValidationArea
+	// Check runs the area's checks in dir.
+	Check func(dir string, opts AreaOptions) []Result
+}
+
+// builtinAreas are the areas validate always runs, in order.
+var builtinAreas = []ValidationArea{AreaPM, AreaQA, AreaDocumentation, AreaRelease, AreaSecurity}
+
+var (
+	registryMu sync.Mutex
+	registry   []AreaDefinition
+)
+
+// RegisterArea adds an area to every validation run after the built-in
+// areas, typically from an init function. Its key becomes valid in the
+// validate config, so the area can be disabled or have checks skipped
+// there. It fails if the area has no name or Check, already exists, or
+// depends on the Release area or an area that isn't defined.
+func RegisterArea(def AreaDefinition) error {
+	if def.Area.Key() == "" {
+		return errors.New("register area: missing name")
+	}
+	if def.Check == nil {
+		return fmt.Errorf("register area %s: missing Check", def.Area)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	known := knownAreas(registry)
+	if slices.ContainsFunc(known, func(a ValidationArea) bool { return a.Key() == def.Area.Key() }) {
+		return fmt.Errorf("register area %s: already exists", def.Area)
+	}
+	for _, d := range def.DependsOn {
+		if d == AreaRelease || !slices.Contains(known, d) {
+			return fmt.Errorf("register area %s: can't depend on %s", def.Area, d)
+		}
+	}
+
+	def.DependsOn = slices.Clone(def.DependsOn)
+	registry = append(registry, def)
+	config.ValidateAreas = append(config.ValidateAreas, def.Area.Key())
+	return nil
+}
+
+// RegisteredAreas returns the areas added by RegisterArea, in the order
+// they were registered.
+func RegisteredAreas() []AreaDefinition {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return slices.Clone(registry)
+}
+
+// knownAreas returns the built-in areas followed by defs.
+func knownAreas(defs []AreaDefinition) []ValidationArea {
+	known := slices.Clone(builtinAreas)
+	for _, d := range defs {
+		known = append(known, d.Area)
+	}
+	return known
+}
+
+// CustomAreas returns the areas to run after the built-in ones: the
+// registered areas, then those defined in cfg's validate.custom, with
+// their dependencies defaulted. Config areas run their checks as commands
+// in the validated directory.
+func CustomAreas(cfg config.ValidateConfig) []AreaDefinition {
+	defs := RegisteredAreas()
+	for _, a := range cfg.Custom {
+		known := knownAreas(defs)
+		def := AreaDefinition{
+			Area:  ValidationArea(a.Name),
+			Check: commandChecks(a),
+		}
+		for _, key := range a.DependsOn {
+			if i := slices.IndexFunc(known, func(k ValidationArea) bool { return k.Key() == key }); i >= 0 {
+				def.DependsOn = append(def.DependsOn, known[i])
+			}
+		}
+		defs = append(defs, def)
+	}
+	for i := range defs {
+		if len(defs[i].DependsOn) == 0 {
+			defs[i].DependsOn = []ValidationArea{AreaPM}
+		}
+	}
+	return defs
+}
+
+// commandChecks returns the checks of a config-defined area, each passing
+// if its command exits with status 0.
+func commandChecks(a config.CustomArea) func(dir string, opts AreaOptions) []Result {
+	return func(dir string, _ AreaOptions) []Result {
+		var results []Result
+		for _, c := range a.Checks {
+			r := RunCommand(a.Name+": "+c.Name, dir, c.Command[0], c.Command[1:]...)
+			r.Warning = c.Warning
+			results = append(results, r)
+		}
+		return results
+	}
+}
+
+// RunArea runs a custom area's checks and returns its result, reporting
+// the checks cfg skips as skipped.
+func RunArea(def AreaDefinition, dir string, opts AreaOptions, cfg config.AreaConfig) AreaResult {
+	result := NewAreaResult(def.Area, def.Check(dir, opts), cfg)
+	result.DependsOn = def.DependsOn
+	return result
+}
@@ -0,0 +1,63 @@
+package checks
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestRegisterArea(t *testing.T) {
+	savedRegistry, savedKeys := registry, config.ValidateAreas
+	t.Cleanup(func() { registry, config.ValidateAreas = savedRegistry, savedKeys })
+	registry, config.ValidateAreas = nil, slices.Clone(config.ValidateAreas)
+
+	check := func(string, AreaOptions) []Result {
+		return []Result{{Name: "Legal: license headers", Passed: true}}
+	}
+	if err := RegisterArea(AreaDefinition{Area: "Legal", Check: check}); err != nil {
+		t.Fatalf("RegisterArea(Legal) error: %v", err)
+	}
+	for _, def := range []AreaDefinition{
+		{Area: "", Check: check},
+		{Area: "Localization"},
+		{Area: "legal", Check: check},
+		{Area: "QA", Check: check},
+		{Area: "Localization", Check: check, DependsOn: []ValidationArea{AreaRelease}},
+		{Area: "Localization", Check: check, DependsOn: []ValidationArea{"Marketing"}},
+	} {
+		if err := RegisterArea(def); err == nil {
+			t.Errorf("RegisterArea(%q, depends on %v) error = nil", def.Area, def.DependsOn)
+		}
+	}
+	if !slices.Contains(config.ValidateAreas, "legal") {
+		t.Errorf("config.ValidateAreas = %v, want legal", config.ValidateAreas)
+	}
+
+	cfg := config.ValidateConfig{Custom: []config.CustomArea{{
+		Name:      "Localization",
+		DependsOn: []string{"legal"},
+		Checks: []config.CustomCheck{
+			{Name: "pass", Command: []string{"true"}},
+			{Name: "soft", Command: []string{"false"}, Warning: true},
+		},
+	}}}
+	defs := CustomAreas(cfg)
+	if len(defs) != 2 || defs[0].Area != "Legal" || defs[1].Area != "Localization" ||
+		!slices.Equal(defs[1].DependsOn, []ValidationArea{"Legal"}) {
+		t.Fatalf("CustomAreas() = %+v", defs)
+	}
+
+	a := RunArea(defs[1], t.TempDir(), AreaOptions{}, config.AreaConfig{})
+	if a.Status != StatusWarn || len(a.Results) != 2 || a.Results[0].Name != "Localization: pass" {
+		t.Errorf("RunArea() = %+v, want WARN", a)
+	}
+	if !slices.Equal(a.DependsOn, []ValidationArea{"Legal"}) {
+		t.Errorf("RunArea().DependsOn = %v", a.DependsOn)
+	}
+
+	skip := config.AreaConfig{Skip: []config.CheckSkip{{ID: "localization.soft"}}}
+	if a := RunArea(defs[1], t.TempDir(), AreaOptions{}, skip); a.Status != StatusGo {
+		t.Errorf("RunArea() skipping localization.soft = %s, want GO", a.Status)
+	}
+}
@@ -6,9 +6,12 @@ package checks
 
 import (
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // ReleaseChecker implements release management checks.
@@ -21,8 +24,10 @@ func (c *ReleaseChecker) Name() string {
 
 // ReleaseOptions configures release checks.
 type ReleaseOptions struct {
-	Version string // Target release version (e.g., "v0.2.0")
-	Verbose bool
+	Version   string                // Target release version (e.g., "v0.2.0")
+	Freeze    []config.FreezeWindow // Windows during which releases are blocked
+	Changelog *changelog.Loaded     // Changelog shared with other checks; nil loads it from dir
+	Verbose   bool
 }
 
 // Check runs release management checks on the specified directory.
@@ -32,6 +37,12 @@ func (c *ReleaseChecker) Check(dir string, opts ReleaseOptions) []Result {
 	// Check version format and availability
 	results = append(results, c.checkVersionAvailable(dir, opts.Version))
 
+	// Check the go.mod module path's /vN suffix matches the version
+	results = append(results, c.checkModuleMajor(dir, opts.Version))
+
+	// Check releases aren't frozen
+	results = append(results, c.checkFreeze(opts.Freeze))
+
 	// Check git status (clean working directory for release)
 	results = append(results, c.checkGitStatus(dir))
 
@@ -39,11 +50,28 @@ func (c *ReleaseChecker) Check(dir string, opts ReleaseOptions) []Result {
 	results = append(results, c.checkGitRemote(dir))
 
 	// Check CHANGELOG.json exists and is valid
-	results = append(results, c.checkChangelogJSON(dir))
+	cl := opts.Changelog
+	if cl == nil {
+		cl = changelog.LoadShared(dir)
+	}
+	results = append(results, c.checkChangelogJSON(dir, cl))
 
 	// Check for CI configuration
 	results = append(results, c.checkCIConfig(dir))
 
+	// Check Go toolchain matches go.mod locally and in CI
+	results = append(results, c.checkGoToolchain(dir))
+
+	// Check Node engines, .nvmrc and lockfile match the local toolchain
+	results = append(results, c.checkNodeToolchain(dir))
+
+	// Check lockfiles are in sync so CI installs don't break
+	results = append(results, c.checkGoModSync(dir))
+	results = append(results, c.checkNodeLockfileSync(dir))
+
+	// Check generated plugin trees match their specs
+	results = append(results, c.checkGeneratedPlugins(dir))
+
 	return results
 }
 
@@ -63,8 +91,12 @@ func (c *ReleaseChecker) checkVersionAvailable(dir string, version string) Resul
 		version = "v" + version
 	}
 
+	if r, ok := disallowed(name, "git"); ok {
+		return r
+	}
+
 	// Check if tag already exists
-	cmd := exec.Command("git", "tag", "-l", version)
+	cmd := proc.Command("git", "tag", "-l", version)
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
@@ -77,9 +109,10 @@ func (c *ReleaseChecker) checkVersionAvailable(dir string, version string) Resul
 
 	if strings.TrimSpace(string(output)) != "" {
 		return Result{
-			Name:   name,
-			Passed: false,
-			Output: fmt.Sprintf("Tag %s already exists", version),
+			Name:        name,
+			Passed:      false,
+			Output:      fmt.Sprintf("Tag %s already exists", version),
+			Remediation: "release a version that isn't tagged yet",
 		}
 	}
 
@@ -92,8 +125,11 @@ func (c *ReleaseChecker) checkVersionAvailable(dir string, version string) Resul
 
 func (c *ReleaseChecker) checkGitStatus(dir string) Result {
 	name := "Release: git working directory"
+	if r, ok := disallowed(name, "git"); ok {
+		return r
+	}
 
-	cmd := exec.Command("git", "status", "--porcelain")
+	cmd := proc.Command("git", "status", "--porcelain")
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
@@ -112,10 +148,11 @@ func (c *ReleaseChecker) checkGitStatus(dir string) Result {
 			summary = status
 		}
 		return Result{
-			Name:    name,
-			Warning: true,
-			Passed:  false,
-			Output:  summary,
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      summary,
+			Remediation: "commit or stash the changes before releasing",
 		}
 	}
 
@@ -128,15 +165,19 @@ func (c *ReleaseChecker) checkGitStatus(dir string) Result {
 
 func (c *ReleaseChecker) checkGitRemote(dir string) Result {
 	name := "Release: git remote"
+	if r, ok := disallowed(name, "git"); ok {
+		return r
+	}
 
-	cmd := exec.Command("git", "remote", "get-url", "origin")
+	cmd := proc.Command("git", "remote", "get-url", "origin")
 	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
 		return Result{
-			Name:   name,
-			Passed: false,
-			Output: "No 'origin' remote configured",
+			Name:        name,
+			Passed:      false,
+			Output:      "No 'origin' remote configured",
+			Remediation: "add the remote: git remote add origin <url>",
 		}
 	}
 
@@ -148,26 +189,34 @@ func (c *ReleaseChecker) checkGitRemote(dir string) Result {
 	}
 }
 
-func (c *ReleaseChecker) checkChangelogJSON(dir string) Result {
+func (c *ReleaseChecker) checkChangelogJSON(dir string, cl *changelog.Loaded) Result {
 	name := "Release: CHANGELOG.json"
-	changelogPath := filepath.Join(dir, "CHANGELOG.json")
 
-	if !FileExists(changelogPath) {
+	if cl.Source != changelog.DefaultFile {
+		return Result{
+			Name:        name,
+			Passed:      false,
+			Output:      "CHANGELOG.json not found",
+			Remediation: "create it: schangelog init",
+		}
+	}
+	if cl.Err != nil {
 		return Result{
 			Name:   name,
 			Passed: false,
-			Output: "CHANGELOG.json not found. Run: schangelog init",
+			Output: cl.Err.Error(),
 		}
 	}
 
 	// Validate with schangelog if available
-	if CommandExists("schangelog") {
+	if CommandExists("schangelog") && CommandAllowed("schangelog") {
 		result := RunCommand("validate", dir, "schangelog", "validate", "CHANGELOG.json")
 		if !result.Passed {
 			return Result{
-				Name:   name,
-				Passed: false,
-				Output: "CHANGELOG.json validation failed",
+				Name:        name,
+				Passed:      false,
+				Output:      "CHANGELOG.json validation failed",
+				Remediation: "see the errors: schangelog validate CHANGELOG.json",
 			}
 		}
 	}
@@ -203,9 +252,10 @@ func (c *ReleaseChecker) checkCIConfig(dir string) Result {
 	}
 
 	return Result{
-		Name:    name,
-		Warning: true,
-		Passed:  false,
-		Output:  "No CI configuration found",
+		Name:        name,
+		Warning:     true,
+		Passed:      false,
+		Output:      "No CI configuration found",
+		Remediation: "add a workflow that builds and tests the project, e.g. .github/workflows/ci.yml",
 	}
 }
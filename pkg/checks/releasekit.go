@@ -3,13 +3,30 @@ package checks
 import (
 	"fmt"
 	"os/exec"
+	"strings"
+	"time"
 
 	multiagentspec "github.com/plexusone/multi-agent-spec/sdk/go"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // RunReleasekit executes `releasekit validate` and returns the results as checks.Result.
 // It shells out to the releasekit CLI and parses the AgentResult JSON output.
+// If the command policy doesn't allow releasekit, a single skipped result
+// is returned.
 func RunReleasekit(dir string, opts Options) ([]Result, error) {
+	if r, ok := disallowed("releasekit validate", "releasekit"); ok {
+		return []Result{r}, nil
+	}
+
+	cmd := proc.Command("releasekit", append(releasekitArgs(opts), dir)...)
+	return runReleasekitCommand(cmd)
+}
+
+// releasekitArgs returns the `releasekit validate` arguments for opts,
+// without the directory.
+func releasekitArgs(opts Options) []string {
 	args := []string{"validate", "--format", "json"}
 
 	if !opts.Lint {
@@ -24,11 +41,13 @@ func RunReleasekit(dir string, opts Options) ([]Result, error) {
 	if opts.Verbose {
 		args = append(args, "--verbose")
 	}
+	return args
+}
 
-	args = append(args, dir)
-
-	cmd := exec.Command("releasekit", args...)
-	output, err := cmd.Output()
+// runReleasekitCommand runs a releasekit validate command and converts its
+// AgentResult output.
+func runReleasekitCommand(cmd *exec.Cmd) ([]Result, error) {
+	output, err := runOutput(cmd, "releasekit validate", false)
 
 	// releasekit exits with code 2 for NO-GO, which is not an error for our purposes
 	if err != nil {
@@ -44,7 +63,11 @@ func RunReleasekit(dir string, opts Options) ([]Result, error) {
 		}
 	}
 
-	// Parse AgentResult
+	return parseReleasekitOutput(output)
+}
+
+// parseReleasekitOutput parses releasekit's AgentResult JSON output.
+func parseReleasekitOutput(output []byte) ([]Result, error) {
 	agentResult, err := multiagentspec.ParseAgentResult(output)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse releasekit output: %w\noutput: %s", err, string(output))
@@ -60,12 +83,23 @@ func convertTaskResults(tasks []multiagentspec.TaskResult) []Result {
 
 	for _, t := range tasks {
 		r := Result{
-			Name: t.ID,
+			Name:     t.ID,
+			Duration: time.Duration(t.DurationMs) * time.Millisecond,
+		}
+		// releasekit reports the detection root and language of each task
+		// in a monorepo
+		if t.Metadata != nil {
+			r.Path, _ = t.Metadata["path"].(string)
+			r.Language, _ = t.Metadata["language"].(string)
+			r.Command, _ = t.Metadata["command"].(string)
+			r.Remediation, _ = t.Metadata["remediation"].(string)
 		}
 
 		switch t.Status {
 		case multiagentspec.StatusGo:
 			r.Passed = true
+			// e.g. the coverage percentage, shown with --verbose
+			r.Output = t.Detail
 		case multiagentspec.StatusNoGo:
 			r.Passed = false
 			r.Output = t.Detail
@@ -88,10 +122,66 @@ func convertTaskResults(tasks []multiagentspec.TaskResult) []Result {
 			r.Reason = t.Detail
 		}
 
+		r.ID = ResultID(r)
+		if r.Remediation == "" {
+			r.Remediation = DefaultRemediation(r.ID)
+		}
 		results = append(results, r)
 	}
 
-	return results
+	return DedupeResults(results)
+}
+
+// taskStatuses maps result severities to task statuses.
+var taskStatuses = map[Severity]multiagentspec.Status{
+	SeverityPassed:  multiagentspec.StatusGo,
+	SeverityFailed:  multiagentspec.StatusNoGo,
+	SeverityWarning: multiagentspec.StatusWarn,
+	SeveritySkipped: multiagentspec.StatusSkip,
+}
+
+// ToTaskResult converts r to a multiagentspec.TaskResult for team reports.
+// The task ID is the result's stable ID, the detail is the first line of
+// its output or the skip reason, and failures and warnings are given a high
+// and low severity and their remediation as the "remediation" metadata.
+func ToTaskResult(r Result) multiagentspec.TaskResult {
+	severity := r.Severity()
+	task := multiagentspec.TaskResult{
+		ID:         ResultID(r),
+		Status:     taskStatuses[severity],
+		DurationMs: r.Duration.Milliseconds(),
+	}
+	switch severity {
+	case SeverityFailed:
+		task.Severity = "high"
+	case SeverityWarning:
+		task.Severity = "low"
+	}
+
+	if r.Output != "" {
+		task.Detail, _, _ = strings.Cut(r.Output, "\n")
+		if len(task.Detail) > 40 {
+			task.Detail = task.Detail[:37] + "..."
+		}
+	}
+	if task.Detail == "" {
+		task.Detail = r.Reason
+	}
+
+	remediation := r.ShownRemediation()
+	if r.Path != "" || r.Language != "" || remediation != "" {
+		task.Metadata = map[string]interface{}{}
+		if r.Path != "" {
+			task.Metadata["path"] = r.Path
+		}
+		if r.Language != "" {
+			task.Metadata["language"] = r.Language
+		}
+		if remediation != "" {
+			task.Metadata["remediation"] = remediation
+		}
+	}
+	return task
 }
 
 // ReleasekitAvailable checks if the releasekit CLI is installed and available.
@@ -103,6 +193,10 @@ func ReleasekitAvailable() bool {
 // RunReleasekitRaw executes releasekit and returns the raw AgentResult.
 // Use this when you want to work directly with multi-agent-spec types.
 func RunReleasekitRaw(dir string, opts Options) (*multiagentspec.AgentResult, error) {
+	if !CommandAllowed("releasekit") {
+		return nil, fmt.Errorf("releasekit is not allowed by the tools policy")
+	}
+
 	args := []string{"validate", "--format", "json"}
 
 	if !opts.Lint {
@@ -120,8 +214,8 @@ func RunReleasekitRaw(dir string, opts Options) (*multiagentspec.AgentResult, er
 
 	args = append(args, dir)
 
-	cmd := exec.Command("releasekit", args...)
-	output, err := cmd.Output()
+	cmd := proc.Command("releasekit", args...)
+	output, err := runOutput(cmd, "releasekit validate", false)
 
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -0,0 +1,41 @@
+package checks
+
+import "strings"
+
+// languageRemediations are the remediations of the language checks
+// releasekit runs, for results that don't bring their own: by ID for Go
+// checks, and by ID without the "ts." or "js." prefix for TypeScript and
+// JavaScript checks.
+var languageRemediations = map[string]string{
+	"go.build":                     "fix the compile errors: go build ./...",
+	"go.gofmt":                     "format the code: gofmt -w .",
+	"go.format":                    "format the code: gofmt -w .",
+	"go.golangci_lint":             "fix the findings, or waive them with //atrelease:ignore: golangci-lint run",
+	"go.lint":                      "fix the findings, or waive them with //atrelease:ignore: golangci-lint run",
+	"go.test":                      "fix the failing tests: go test ./...",
+	"go.mod_tidy":                  "run go mod tidy and commit go.mod and go.sum",
+	"go.error_handling_compliance": "handle or return the discarded errors",
+	"go.untracked_refs":            "commit the referenced files, or remove the references",
+	"eslint":                       "fix the findings: npx eslint .",
+	"lint":                         "fix the findings: npx eslint .",
+	"prettier":                     "format the code: npx prettier --write .",
+	"format":                       "format the code: npx prettier --write .",
+	"tsc_noemit":                   "fix the type errors: npx tsc --noEmit",
+	"npm_test":                     "fix the failing tests: npm test",
+	"test":                         "fix the failing tests: npm test",
+}
+
+// DefaultRemediation returns the remediation of the language check with
+// ID id, e.g. "go.gofmt" or "ts.lint", or "" if there is none.
+func DefaultRemediation(id string) string {
+	lang, check, ok := strings.Cut(id, ".")
+	switch {
+	case !ok:
+		return ""
+	case lang == "go":
+		return languageRemediations[id]
+	case lang == "ts" || lang == "js":
+		return languageRemediations[check]
+	}
+	return ""
+}
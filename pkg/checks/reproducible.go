@@ -0,0 +1,128 @@
+package checks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// CheckGoReproducible builds the Go commands in dir twice with -trimpath,
+// the second time with an empty build cache and temporary directory, and
+// warns if the binaries differ. packages are the commands to build, e.g.
+// "./cmd/tool"; empty builds every main package in the module.
+func CheckGoReproducible(dir string, packages []string) Result {
+	name := "Security: reproducible build"
+	if !FileExists(filepath.Join(dir, "go.mod")) {
+		return Result{Name: name, Skipped: true, Reason: "Not a Go project"}
+	}
+	if r, ok := disallowed(name, "go"); ok {
+		return r
+	}
+
+	start := time.Now()
+	if len(packages) == 0 {
+		cmd := proc.Command("go", "list", "-f", `{{if eq .Name "main"}}{{.ImportPath}}{{end}}`, "./...")
+		cmd.Dir = dir
+		output, err := cmd.Output()
+		if err != nil {
+			return Result{Name: name, Error: fmt.Errorf("listing main packages: %w", err), Duration: time.Since(start)}
+		}
+		packages = strings.Fields(string(output))
+	}
+	if len(packages) == 0 {
+		return Result{Name: name, Skipped: true, Reason: "no main packages"}
+	}
+
+	tmp, err := os.MkdirTemp("", "atrelease-reproducible-")
+	if err != nil {
+		return Result{Name: name, Error: err}
+	}
+	defer os.RemoveAll(tmp)
+
+	var builds [2]map[string]string
+	for i := range builds {
+		out := filepath.Join(tmp, fmt.Sprintf("build%d", i+1))
+		var env []string
+		if i == 1 {
+			// Rebuild everything from scratch rather than reusing cached objects
+			env = []string{"GOCACHE=" + filepath.Join(tmp, "cache"), "GOTMPDIR=" + filepath.Join(tmp, "work")}
+		}
+		if output, err := goBuildTo(dir, out, packages, env); err != nil {
+			return Result{
+				Name:     name,
+				Output:   "go build failed:\n" + strings.TrimSpace(string(output)),
+				Duration: time.Since(start),
+			}
+		}
+		if builds[i], err = hashTree(out); err != nil {
+			return Result{Name: name, Error: err, Duration: time.Since(start)}
+		}
+	}
+
+	var diffs []string
+	for bin, sum := range builds[0] {
+		if other := builds[1][bin]; other != sum {
+			diffs = append(diffs, fmt.Sprintf("  %s: %.12s != %.12s", bin, sum, other))
+		}
+	}
+	sort.Strings(diffs)
+	if len(diffs) > 0 {
+		return Result{
+			Name:     name,
+			Warning:  true,
+			Output:   fmt.Sprintf("%d of %d binaries differ between two builds:\n%s", len(diffs), len(builds[0]), strings.Join(diffs, "\n")),
+			Duration: time.Since(start),
+
+			Remediation: "stamp versions from the commit instead of the build time, and build with CGO_ENABLED=0 if possible",
+		}
+	}
+	return Result{
+		Name:     name,
+		Passed:   true,
+		Output:   fmt.Sprintf("%d binaries identical across two builds", len(builds[0])),
+		Duration: time.Since(start),
+	}
+}
+
+// goBuildTo builds packages of dir with -trimpath into the directory out.
+func goBuildTo(dir, out string, packages, env []string) ([]byte, error) {
+	for _, d := range append([]string{out}, env...) {
+		if _, path, ok := strings.Cut(d, "="); ok {
+			d = path
+		}
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, err
+		}
+	}
+	args := append([]string{"build", "-trimpath", "-o", out + string(filepath.Separator)}, packages...)
+	cmd := proc.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), env...)
+	return runOutput(cmd, "Security: reproducible build", true)
+}
+
+// hashTree returns the SHA-256 of each regular file under root, by
+// slash-separated relative path.
+func hashTree(root string) (map[string]string, error) {
+	files, err := treeFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[string]string, len(files))
+	for rel := range files {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		sums[rel] = hex.EncodeToString(sum[:])
+	}
+	return sums, nil
+}
@@ -0,0 +1,43 @@
+package checks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckGoReproducible(t *testing.T) {
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "cmd", "app", "main.go"), "package main\n\nfunc main() { println(\"app\") }\n")
+	writeTestFile(t, filepath.Join(dir, "lib", "lib.go"), "package lib\n")
+	t.Setenv("GOWORK", "off")
+
+	r := CheckGoReproducible(dir, nil)
+	if !r.Passed {
+		t.Fatalf("expected identical builds, got %+v", r)
+	}
+	if !strings.Contains(r.Output, "1 binaries identical") {
+		t.Errorf("Output = %q, want one binary built", r.Output)
+	}
+}
+
+func TestCheckGoReproducible_Skips(t *testing.T) {
+	if r := CheckGoReproducible(t.TempDir(), nil); !r.Skipped {
+		t.Errorf("expected skip without go.mod, got %+v", r)
+	}
+
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/lib\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "lib.go"), "package lib\n")
+	t.Setenv("GOWORK", "off")
+	if r := CheckGoReproducible(dir, nil); !r.Skipped || r.Reason != "no main packages" {
+		t.Errorf("expected skip without main packages, got %+v", r)
+	}
+}
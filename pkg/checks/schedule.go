@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// AreaTask is a validation area to run with RunAreas.
+type AreaTask struct {
+	Area ValidationArea
+	// DependsOn are the areas that must finish before this one starts.
+	// Only areas earlier in the task list count, so there are no cycles.
+	DependsOn []ValidationArea
+	// Skip is why the area doesn't run; Run isn't called if it is set.
+	Skip string
+	// Run runs the area's checks.
+	Run func() AreaResult
+}
+
+// AreaEvent reports an area starting, or finishing with Result.
+type AreaEvent struct {
+	Area     ValidationArea
+	Done     bool
+	Result   AreaResult    // Set when Done
+	Duration time.Duration // Set when Done
+}
+
+// RunAreas runs tasks and returns their results in the order of tasks.
+// With parallel, each area starts as soon as the areas it depends on have
+// finished, so independent areas run concurrently; otherwise they run one
+// at a time in order. An area runs even if one it depends on is NO-GO.
+// Skipped areas finish immediately, without events. events, if not nil,
+// receives each area's start and completion as they happen, one call at a
+// time.
+func RunAreas(tasks []AreaTask, parallel bool, events func(AreaEvent)) []AreaResult {
+	var mu sync.Mutex
+	emit := func(ev AreaEvent) {
+		if events == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		events(ev)
+	}
+
+	results := make([]AreaResult, len(tasks))
+	run := func(i int) {
+		t := tasks[i]
+		if t.Skip != "" {
+			results[i] = SkippedArea(t.Area, t.Skip)
+		} else {
+			emit(AreaEvent{Area: t.Area})
+			start := time.Now()
+			results[i] = t.Run()
+			results[i].Started, results[i].Duration = start, time.Since(start)
+			emit(AreaEvent{Area: t.Area, Done: true, Result: results[i], Duration: results[i].Duration})
+		}
+		results[i].DependsOn = t.DependsOn
+	}
+
+	if !parallel {
+		for i := range tasks {
+			run(i)
+		}
+		return results
+	}
+
+	done := make([]chan struct{}, len(tasks))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	var wg sync.WaitGroup
+	for i, t := range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			for j := range i {
+				if slices.Contains(t.DependsOn, tasks[j].Area) {
+					<-done[j]
+				}
+			}
+			run(i)
+		}()
+	}
+	wg.Wait()
+	return results
+}
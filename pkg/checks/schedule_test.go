@@ -0,0 +1,87 @@
+package checks
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestRunAreas(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	record := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, s)
+	}
+
+	// Docs and Security wait for each other, so they only finish if they
+	// run concurrently; QA must wait for PM
+	docsStarted, secStarted := make(chan struct{}), make(chan struct{})
+	area := func(a ValidationArea, run func()) AreaTask {
+		return AreaTask{Area: a, Run: func() AreaResult {
+			record("start " + string(a))
+			if run != nil {
+				run()
+			}
+			record("end " + string(a))
+			return NewAreaResult(a, []Result{{Name: string(a) + ": check", Passed: true}}, config.AreaConfig{})
+		}}
+	}
+	pm := area(AreaPM, func() { time.Sleep(20 * time.Millisecond) })
+	qa := area(AreaQA, nil)
+	qa.DependsOn = []ValidationArea{AreaPM}
+	docs := area(AreaDocumentation, func() { close(docsStarted); <-secStarted })
+	sec := area(AreaSecurity, func() { close(secStarted); <-docsStarted })
+	release := AreaTask{Area: AreaRelease, Skip: "skipped by config"}
+
+	var events []AreaEvent
+	results := RunAreas([]AreaTask{pm, qa, docs, release, sec}, true, func(ev AreaEvent) {
+		events = append(events, ev)
+	})
+
+	var areas []ValidationArea
+	for _, r := range results {
+		areas = append(areas, r.Area)
+	}
+	if !slices.Equal(areas, []ValidationArea{AreaPM, AreaQA, AreaDocumentation, AreaRelease, AreaSecurity}) {
+		t.Errorf("RunAreas() areas = %v, want task order", areas)
+	}
+	if slices.Index(order, "start QA") < slices.Index(order, "end PM") {
+		t.Errorf("QA started before PM finished: %v", order)
+	}
+	if results[3].Status != StatusSkip || results[3].Results[0].Reason != "skipped by config" {
+		t.Errorf("Release = %+v, want skipped", results[3])
+	}
+	if results[0].Started.IsZero() || results[0].Duration < 20*time.Millisecond || !results[3].Started.IsZero() {
+		t.Errorf("PM ran at %v for %v, Release at %v; want only PM timed", results[0].Started, results[0].Duration, results[3].Started)
+	}
+	if !slices.Equal(results[1].DependsOn, []ValidationArea{AreaPM}) {
+		t.Errorf("QA DependsOn = %v", results[1].DependsOn)
+	}
+	if len(events) != 8 {
+		t.Errorf("got %d events, want a start and a finish for each area that ran", len(events))
+	}
+	for _, ev := range events {
+		if ev.Done && ev.Result.Status != StatusGo {
+			t.Errorf("event %+v, want GO", ev)
+		}
+	}
+}
+
+func TestRunAreas_Sequential(t *testing.T) {
+	var order []ValidationArea
+	task := func(a ValidationArea) AreaTask {
+		return AreaTask{Area: a, Run: func() AreaResult {
+			order = append(order, a)
+			return AreaResult{Area: a, Status: StatusGo}
+		}}
+	}
+	RunAreas([]AreaTask{task(AreaPM), task(AreaQA), task(AreaSecurity)}, false, nil)
+	if !slices.Equal(order, []ValidationArea{AreaPM, AreaQA, AreaSecurity}) {
+		t.Errorf("sequential order = %v", order)
+	}
+}
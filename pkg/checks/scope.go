@@ -0,0 +1,168 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// scopePRLimit caps the merged pull requests fetched per query.
+const scopePRLimit = 200
+
+// scopeCategories maps pull request labels, lowercased and without a
+// "type:" or "kind/" prefix, to the change they document.
+var scopeCategories = map[string]string{
+	"breaking":        "breaking",
+	"breaking-change": "breaking",
+	"breaking change": "breaking",
+	"feature":         "feature",
+	"feat":            "feature",
+	"enhancement":     "feature",
+	"fix":             "fix",
+	"bug":             "fix",
+	"bugfix":          "fix",
+}
+
+// prCategory returns the change a pull request's labels say it makes, the
+// most significant if several apply, or "" if none do.
+func prCategory(pr git.MergedPR) string {
+	category := ""
+	for _, l := range pr.Labels {
+		l = strings.ToLower(l)
+		l = strings.TrimPrefix(strings.TrimPrefix(l, "type:"), "kind/")
+		c := scopeCategories[strings.TrimSpace(l)]
+		if c == "breaking" {
+			return c
+		}
+		if c == "feature" || category == "" {
+			category = c
+		}
+	}
+	return category
+}
+
+// mergedScopePRs returns the pull requests merged since the latest tag or
+// assigned to the version's milestone, with the tag. Tests replace it.
+var mergedScopePRs = func(dir, version string) ([]git.MergedPR, string, error) {
+	g := git.New(dir)
+	tag, err := g.LatestTag()
+	if err != nil {
+		return nil, "", err
+	}
+	date, err := g.TagDate(tag)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prs, err := g.ListMergedPRs("merged:>="+date.UTC().Format(time.RFC3339), scopePRLimit)
+	if err != nil {
+		return nil, "", err
+	}
+	milestone, err := g.ListMergedPRs(fmt.Sprintf("milestone:%q", version), scopePRLimit)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, pr := range milestone {
+		if !slices.ContainsFunc(prs, func(p git.MergedPR) bool { return p.Number == pr.Number }) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, tag, nil
+}
+
+// prMention matches "#12" in an entry description.
+var prMention = regexp.MustCompile(`#(\d+)\b`)
+
+// entryPRs returns the pull request numbers an entry references.
+func entryPRs(e changelog.Entry) []int {
+	var numbers []int
+	if n := e.PR.Number(); n > 0 {
+		numbers = append(numbers, n)
+	}
+	for _, m := range prMention.FindAllStringSubmatch(e.Description, -1) {
+		n, _ := strconv.Atoi(m[1])
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// documents reports whether a changelog entry documents a pull request, by
+// referencing its number or merge commit.
+func documents(e changelog.Entry, pr git.MergedPR) bool {
+	if slices.Contains(entryPRs(e), pr.Number) {
+		return true
+	}
+	return len(e.Commit) >= 7 && pr.MergeCommit != "" && strings.HasPrefix(pr.MergeCommit, e.Commit)
+}
+
+// compareScope cross-references a release's changelog entries with the
+// pull requests merged for it. It returns the labeled pull requests no
+// entry documents, and entries that go beyond the merged pull requests:
+// references to pull requests not merged for the release, and breaking
+// changes no pull request is labeled with.
+func compareScope(release *changelog.Release, prs []git.MergedPR) (missing, creep []string) {
+	entries := release.Entries()
+
+	merged := make(map[int]bool)
+	labeledBreaking := false
+	for _, pr := range prs {
+		merged[pr.Number] = true
+		category := prCategory(pr)
+		if category == "breaking" {
+			labeledBreaking = true
+		}
+		if category == "" || slices.ContainsFunc(entries, func(e changelog.Entry) bool { return documents(e, pr) }) {
+			continue
+		}
+		missing = append(missing, fmt.Sprintf("#%d %s (%s)", pr.Number, pr.Title, category))
+	}
+
+	seen := make(map[int]bool)
+	for _, e := range entries {
+		for _, n := range entryPRs(e) {
+			if !merged[n] && !seen[n] {
+				seen[n] = true
+				creep = append(creep, fmt.Sprintf("#%d was not merged for this release", n))
+			}
+		}
+	}
+
+	breaking := breakingEntries(release)
+	if breaking > 0 && len(prs) > 0 && !labeledBreaking {
+		creep = append(creep, fmt.Sprintf("%d breaking change(s) but no merged pull request is labeled breaking", breaking))
+	}
+	return missing, creep
+}
+
+// checkScopeAgainstPRs compares the release's changelog entries with the
+// pull requests merged since the latest tag. It returns a summary and
+// whether it found discrepancies, or "" if the pull requests can't be
+// listed, e.g. offline or without gh.
+func checkScopeAgainstPRs(dir, version string, release *changelog.Release) (string, bool) {
+	if Offline() || !CommandExists("gh") || !CommandAllowed("gh") {
+		return "", false
+	}
+	prs, tag, err := mergedScopePRs(dir, version)
+	if err != nil || len(prs) == 0 {
+		return "", false
+	}
+
+	missing, creep := compareScope(release, prs)
+	if len(missing) == 0 && len(creep) == 0 {
+		return fmt.Sprintf("matches the %d pull request(s) merged since %s", len(prs), tag), false
+	}
+	var parts []string
+	if len(missing) > 0 {
+		parts = append(parts, "missing entries for "+strings.Join(missing, ", "))
+	}
+	if len(creep) > 0 {
+		parts = append(parts, "beyond the merged pull requests: "+strings.Join(creep, "; "))
+	}
+	return strings.Join(parts, "; "), true
+}
@@ -0,0 +1,62 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/changelog"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+func TestPRCategory(t *testing.T) {
+	tests := []struct {
+		labels []string
+		want   string
+	}{
+		{[]string{"bug"}, "fix"},
+		{[]string{"Type: Feature"}, "feature"},
+		{[]string{"kind/bug", "enhancement"}, "feature"},
+		{[]string{"enhancement", "breaking-change"}, "breaking"},
+		{[]string{"documentation"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := prCategory(git.MergedPR{Labels: tt.labels}); got != tt.want {
+			t.Errorf("prCategory(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestCompareScope(t *testing.T) {
+	prs := []git.MergedPR{
+		{Number: 10, Title: "Add status command", Labels: []string{"feature"}, MergeCommit: "abc1234def"},
+		{Number: 11, Title: "Fix crash", Labels: []string{"bug"}},
+		{Number: 12, Title: "Bump deps", Labels: []string{"dependencies"}},
+		{Number: 13, Title: "Faster checks", Labels: []string{"enhancement"}},
+	}
+	release := &changelog.Release{
+		Version:  "v1.1.0",
+		Added:    []changelog.Entry{{Description: "Status command", Commit: "abc1234"}},
+		Fixed:    []changelog.Entry{{Description: "Crash on empty config (#11)"}, {Description: "Old bug", PR: "https://github.com/o/r/pull/7"}},
+		Breaking: []changelog.Entry{{Description: "Removed --legacy"}},
+	}
+
+	missing, creep := compareScope(release, prs)
+	if want := []string{"#13 Faster checks (feature)"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missing = %q, want %q", missing, want)
+	}
+	wantCreep := []string{
+		"#7 was not merged for this release",
+		"1 breaking change(s) but no merged pull request is labeled breaking",
+	}
+	if !reflect.DeepEqual(creep, wantCreep) {
+		t.Errorf("creep = %q, want %q", creep, wantCreep)
+	}
+
+	release.Fixed = release.Fixed[:1]
+	release.Changed = []changelog.Entry{{Description: "Faster checks", PR: "13"}}
+	prs[0].Labels = append(prs[0].Labels, "breaking")
+	if missing, creep := compareScope(release, prs); len(missing) != 0 || len(creep) != 0 {
+		t.Errorf("documented release: missing = %q, creep = %q", missing, creep)
+	}
+}
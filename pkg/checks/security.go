@@ -5,9 +5,10 @@
 package checks
 
 import (
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // SecurityChecker implements security and compliance checks.
@@ -21,6 +22,10 @@ func (c *SecurityChecker) Name() string {
 // SecurityOptions configures security checks.
 type SecurityOptions struct {
 	Verbose bool
+
+	// Reproducible builds the Go commands twice and compares the binaries
+	Reproducible bool
+	Packages     []string // commands to build; empty for every main package
 }
 
 // Check runs security checks on the specified directory.
@@ -39,6 +44,14 @@ func (c *SecurityChecker) Check(dir string, opts SecurityOptions) []Result {
 	// Check for secrets in code
 	results = append(results, c.checkNoSecrets(dir))
 
+	// Check that workflows pin third-party actions to commits
+	results = append(results, CheckPinnedActions(dir))
+
+	// Check that Go builds are reproducible (optional, builds twice)
+	if opts.Reproducible {
+		results = append(results, CheckGoReproducible(dir, opts.Packages))
+	}
+
 	return results
 }
 
@@ -63,9 +76,10 @@ func (c *SecurityChecker) checkLicense(dir string) Result {
 	}
 
 	return Result{
-		Name:   name,
-		Passed: false,
-		Output: "No LICENSE file found",
+		Name:        name,
+		Passed:      false,
+		Output:      "No LICENSE file found",
+		Remediation: "add a LICENSE file with the project's license, e.g. from https://choosealicense.com",
 	}
 }
 
@@ -81,6 +95,13 @@ func (c *SecurityChecker) checkGoVulncheck(dir string) Result {
 		}
 	}
 
+	if r, ok := offlineSkip(name); ok {
+		return r
+	}
+	if r, ok := disallowed(name, "govulncheck"); ok {
+		return r
+	}
+
 	// Check if govulncheck is available
 	if !CommandExists("govulncheck") {
 		return Result{
@@ -96,9 +117,10 @@ func (c *SecurityChecker) checkGoVulncheck(dir string) Result {
 		// Check if it's a real vulnerability or just an error
 		if strings.Contains(result.Output, "Vulnerability") {
 			return Result{
-				Name:   name,
-				Passed: false,
-				Output: "Vulnerabilities found - review and update dependencies",
+				Name:        name,
+				Passed:      false,
+				Output:      "Vulnerabilities found - review and update dependencies",
+				Remediation: "see the affected modules with govulncheck ./..., then upgrade them: go get <module>@<fixed version>",
 			}
 		}
 	}
@@ -122,30 +144,41 @@ func (c *SecurityChecker) checkGoModAudit(dir string) Result {
 		}
 	}
 
+	// Checking for retractions queries the module proxy
+	if r, ok := offlineSkip(name); ok {
+		return r
+	}
+
+	if r, ok := disallowed(name, "go"); ok {
+		return r
+	}
+
 	// Use go list to check for dependency issues
-	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd := proc.Command("go", "list", "-m", "-json", "all")
 	cmd.Dir = dir
 	_, err := cmd.Output()
 	if err != nil {
 		return Result{
-			Name:    name,
-			Warning: true,
-			Passed:  false,
-			Output:  "Failed to list dependencies",
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      "Failed to list dependencies",
+			Remediation: "fix go.mod so go list -m all succeeds, e.g. with go mod tidy",
 		}
 	}
 
 	// Check for retracted versions
-	cmd = exec.Command("go", "list", "-m", "-u", "-retracted", "all")
+	cmd = proc.Command("go", "list", "-m", "-u", "-retracted", "all")
 	cmd.Dir = dir
 	output, _ := cmd.Output()
 
 	if strings.Contains(string(output), "(retracted)") {
 		return Result{
-			Name:    name,
-			Warning: true,
-			Passed:  false,
-			Output:  "Some dependencies have retracted versions",
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      "Some dependencies have retracted versions",
+			Remediation: "see them with go list -m -u -retracted all, then upgrade them with go get",
 		}
 	}
 
@@ -171,9 +204,13 @@ func (c *SecurityChecker) checkNoSecrets(dir string) Result {
 		"private_key.*=.*\"",
 	}
 
+	if r, ok := disallowed(name, "grep"); ok {
+		return r
+	}
+
 	for _, pattern := range secretPatterns {
 		// Exclude this file (security.go) which contains the patterns as string literals
-		cmd := exec.Command("grep", "-r", "-i", "-l", "--include=*.go", "--exclude=security.go", pattern, ".")
+		cmd := proc.Command("grep", "-r", "-i", "-l", "--include=*.go", "--exclude=security.go", pattern, ".")
 		cmd.Dir = dir
 		output, err := cmd.Output()
 
@@ -181,10 +218,11 @@ func (c *SecurityChecker) checkNoSecrets(dir string) Result {
 		if err == nil && len(output) > 0 {
 			files := strings.TrimSpace(string(output))
 			return Result{
-				Name:    name,
-				Warning: true,
-				Passed:  false,
-				Output:  "Potential hardcoded secrets found in: " + files,
+				Name:        name,
+				Warning:     true,
+				Passed:      false,
+				Output:      "Potential hardcoded secrets found in: " + files,
+				Remediation: "move the secrets to the environment or a secret store, and rotate any that were committed",
 			}
 		}
 	}
@@ -0,0 +1,273 @@
+package checks
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+	"github.com/plexusone/agent-team-release/pkg/runlog"
+)
+
+// CoverProfileName is the merged coverage profile sharded Go tests save
+// in the run history directory of the module, for go tool cover.
+const CoverProfileName = "coverage.out"
+
+// goPackage is a package listed by go list.
+type goPackage struct {
+	ImportPath string
+	Dir        string // Relative to the module
+	TestFiles  int
+}
+
+// listGoPackages lists the packages of the Go module in dir.
+func listGoPackages(dir string) ([]goPackage, error) {
+	cmd := proc.Command("go", "list", "-f", "{{.ImportPath}}\t{{.Dir}}\t{{len .TestGoFiles}}\t{{len .XTestGoFiles}}", "./...")
+	cmd.Dir = dir
+	output, err := runOutput(cmd, "go list", false)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("go list: %w\n%s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+
+	var pkgs []goPackage
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		rel, err := filepath.Rel(dir, fields[1])
+		if err != nil {
+			rel = fields[1]
+		}
+		internal, _ := strconv.Atoi(fields[2])
+		external, _ := strconv.Atoi(fields[3])
+		pkgs = append(pkgs, goPackage{ImportPath: fields[0], Dir: filepath.ToSlash(rel), TestFiles: internal + external})
+	}
+	return pkgs, nil
+}
+
+// shardPackages splits pkgs into at most n shards of about equal weight,
+// each package weighing one plus its number of test files. Packages are
+// assigned heaviest first to the lightest shard, so the result only
+// depends on pkgs. Each shard is sorted by import path; empty shards are
+// dropped.
+func shardPackages(pkgs []goPackage, n int) [][]goPackage {
+	weight := func(p goPackage) int { return 1 + p.TestFiles }
+	sorted := slices.Clone(pkgs)
+	slices.SortStableFunc(sorted, func(a, b goPackage) int {
+		if d := weight(b) - weight(a); d != 0 {
+			return d
+		}
+		return strings.Compare(a.ImportPath, b.ImportPath)
+	})
+
+	shards := make([][]goPackage, max(n, 1))
+	loads := make([]int, len(shards))
+	for _, p := range sorted {
+		lightest := 0
+		for i := range loads {
+			if loads[i] < loads[lightest] {
+				lightest = i
+			}
+		}
+		shards[lightest] = append(shards[lightest], p)
+		loads[lightest] += weight(p)
+	}
+
+	out := shards[:0]
+	for _, s := range shards {
+		if len(s) == 0 {
+			continue
+		}
+		slices.SortFunc(s, func(a, b goPackage) int { return strings.Compare(a.ImportPath, b.ImportPath) })
+		out = append(out, s)
+	}
+	return out
+}
+
+// shardRun is the outcome of running one shard.
+type shardRun struct {
+	output  []byte
+	err     error
+	profile string // Coverage profile file, if coverage was measured
+}
+
+// CheckGoTestsSharded runs the tests of the Go module in dir split by
+// package across shards parallel go test -json processes, and merges
+// their output into one "Go: tests" result, summarized first. With
+// coverage, it also merges the shards' coverage profiles into a
+// "Go: coverage" result with the total coverage, excluding the packages
+// under the comma-separated directories of excludeCoverage, and saves the
+// merged profile as CoverProfileName in the module's run history directory.
+func CheckGoTestsSharded(dir string, shards int, coverage bool, excludeCoverage string) []Result {
+	name := "Go: tests"
+	if r, ok := disallowed(name, "go"); ok {
+		r.Path = dir
+		return []Result{r}
+	}
+
+	start := time.Now()
+	pkgs, err := listGoPackages(dir)
+	if err != nil {
+		return []Result{{Name: name, Path: dir, Passed: false, Error: err}}
+	}
+	if len(pkgs) == 0 {
+		return []Result{{Name: name, Path: dir, Skipped: true, Reason: "No Go packages"}}
+	}
+	split := shardPackages(pkgs, shards)
+
+	tmp, err := os.MkdirTemp("", "atrelease-shards-")
+	if err != nil {
+		return []Result{{Name: name, Path: dir, Passed: false, Error: err}}
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	runs := make([]shardRun, len(split))
+	var wg sync.WaitGroup
+	for i, shard := range split {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			args := []string{"test", "-json"}
+			if coverage {
+				runs[i].profile = filepath.Join(tmp, fmt.Sprintf("shard%d.out", i))
+				args = append(args, "-cover", "-coverprofile="+runs[i].profile)
+			}
+			for _, p := range shard {
+				args = append(args, p.ImportPath)
+			}
+			cmd := proc.Command("go", args...)
+			cmd.Dir = dir
+			runs[i].output, runs[i].err = runOutput(cmd, fmt.Sprintf("%s (shard %d of %d)", name, i+1, len(split)), true)
+		}()
+	}
+	wg.Wait()
+
+	passed := true
+	parser := NewGoTestParser()
+	for _, run := range runs {
+		if run.err != nil {
+			passed = false
+		}
+		parser.Parse(run.output)
+	}
+	output, summary := parser.Output()
+	results := []Result{{
+		Name:     name,
+		Path:     dir,
+		Passed:   passed,
+		Output:   fmt.Sprintf("%s\n(%d packages in %d shards)", output, len(pkgs), len(split)),
+		Duration: time.Since(start),
+		Tests:    summary,
+	}}
+	if !coverage {
+		return results
+	}
+
+	var profiles []string
+	for _, run := range runs {
+		profiles = append(profiles, run.profile)
+	}
+	excluded := coverageExcluded(pkgs, excludeCoverage)
+	merged, pct, err := mergeCoverProfiles(profiles, excluded)
+	cov := Result{Name: "Go: coverage", Path: dir, Passed: true}
+	switch {
+	case err != nil:
+		cov.Passed, cov.Error = false, err
+	case merged == nil:
+		cov.Skipped, cov.Reason = true, "No coverage profile"
+	default:
+		cov.Output = fmt.Sprintf("coverage: %.1f%% of statements", pct)
+		if err := runlog.WriteFile(dir, CoverProfileName, merged); err != nil {
+			cov.Output += fmt.Sprintf("\n(saving the merged profile: %v)", err)
+		}
+	}
+	return append(results, cov)
+}
+
+// coverageExcluded returns the import paths of the packages in or under
+// the comma-separated directories of exclude.
+func coverageExcluded(pkgs []goPackage, exclude string) map[string]bool {
+	excluded := make(map[string]bool)
+	for _, d := range strings.Split(exclude, ",") {
+		d = strings.Trim(strings.TrimSpace(d), "/")
+		if d == "" {
+			continue
+		}
+		for _, p := range pkgs {
+			if p.Dir == d || strings.HasPrefix(p.Dir, d+"/") {
+				excluded[p.ImportPath] = true
+			}
+		}
+	}
+	return excluded
+}
+
+// mergeCoverProfiles merges the coverage profiles of shards, which cover
+// disjoint packages, and returns the merged profile and the percentage of
+// statements covered outside the excluded packages. Missing profiles, of
+// shards that failed to build, are ignored; it returns a nil profile if
+// there are none.
+func mergeCoverProfiles(files []string, excluded map[string]bool) ([]byte, float64, error) {
+	var merged bytes.Buffer
+	var statements, covered int
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		for sc.Scan() {
+			line := sc.Text()
+			if strings.HasPrefix(line, "mode: ") {
+				if merged.Len() == 0 {
+					merged.WriteString(line + "\n")
+				}
+				continue
+			}
+			if line == "" {
+				continue
+			}
+			merged.WriteString(line + "\n")
+
+			// "example.com/m/pkg/file.go:10.2,12.3 2 1": statements and count
+			block, counts, _ := strings.Cut(line, " ")
+			src, _, _ := strings.Cut(block, ":")
+			fields := strings.Fields(counts)
+			if len(fields) != 2 || excluded[path.Dir(src)] {
+				continue
+			}
+			n, _ := strconv.Atoi(fields[0])
+			count, _ := strconv.Atoi(fields[1])
+			statements += n
+			if count > 0 {
+				covered += n
+			}
+		}
+	}
+	if merged.Len() == 0 {
+		return nil, 0, nil
+	}
+	pct := 0.0
+	if statements > 0 {
+		pct = float64(covered) / float64(statements) * 100
+	}
+	return merged.Bytes(), pct, nil
+}
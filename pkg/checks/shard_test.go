@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/runlog"
+)
+
+func TestShardPackages(t *testing.T) {
+	pkgs := []goPackage{
+		{ImportPath: "m/a", TestFiles: 5},
+		{ImportPath: "m/b", TestFiles: 1},
+		{ImportPath: "m/c", TestFiles: 2},
+		{ImportPath: "m/d"},
+		{ImportPath: "m/e", TestFiles: 1},
+	}
+	var got [][]string
+	for _, shard := range shardPackages(pkgs, 2) {
+		var paths []string
+		for _, p := range shard {
+			paths = append(paths, p.ImportPath)
+		}
+		got = append(got, paths)
+	}
+	// Weights a=6, c=3, b=2, e=2, d=1: c, b, and e fill the second shard
+	// up to 7, and d goes to the first
+	want := [][]string{{"m/a", "m/d"}, {"m/b", "m/c", "m/e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("shards = %v, want %v", got, want)
+	}
+
+	if n := len(shardPackages(pkgs[:1], 4)); n != 1 {
+		t.Errorf("got %d shards for one package, want 1", n)
+	}
+}
+
+func TestCheckGoTestsSharded(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/m\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "a", "a.go"), "package a\n\nfunc A(b bool) int {\n\tif b {\n\t\treturn 1\n\t}\n\treturn 0\n}\n")
+	writeTestFile(t, filepath.Join(dir, "a", "a_test.go"), "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {\n\tif A(true) != 1 {\n\t\tt.Fatal()\n\t}\n}\n")
+	writeTestFile(t, filepath.Join(dir, "b", "b_test.go"), "package b\n\nimport \"testing\"\n\nfunc TestB(t *testing.T) { t.Fatal(\"broken\") }\n")
+	writeTestFile(t, filepath.Join(dir, "cmd", "tool", "main.go"), "package main\n\nfunc main() {\n\tprintln(1)\n}\n")
+
+	results := CheckGoTestsSharded(dir, 2, true, "cmd")
+	if len(results) != 2 {
+		t.Fatalf("results = %+v", results)
+	}
+	tests, cov := results[0], results[1]
+	if tests.Passed || !strings.Contains(tests.Output, "--- FAIL: TestB") ||
+		!strings.HasSuffix(tests.Output, "(3 packages in 2 shards)") {
+		t.Errorf("tests = %+v", tests)
+	}
+	if got := ParseTestFailures("go.test", tests.Output); len(got) != 1 || got[0].Package != "example.com/m/b" {
+		t.Errorf("failures = %+v", got)
+	}
+	// a has 3 statements of which 2 are covered; cmd/tool is excluded
+	if !cov.Passed || cov.Output != "coverage: 66.7% of statements" {
+		t.Errorf("coverage = %+v", cov)
+	}
+	profile, err := os.ReadFile(filepath.Join(dir, runlog.Dir, CoverProfileName))
+	if err != nil || !strings.HasPrefix(string(profile), "mode: set\n") || strings.Count(string(profile), "mode:") != 1 {
+		t.Errorf("merged profile = %q, %v", profile, err)
+	}
+}
@@ -0,0 +1,169 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var goTestFuncPattern = regexp.MustCompile(`(?m)^func (Test|Example|Fuzz)\w*\(`)
+
+// CheckChangedTests is a soft check that warns when changed Go packages or
+// TypeScript/JavaScript modules have no tests. files are paths relative to
+// dir, typically from git.ChangedFiles.
+func CheckChangedTests(dir string, files []string) []Result {
+	var goPkgs, jsModules []string
+	seen := make(map[string]bool)
+
+	for _, f := range files {
+		switch {
+		case isGoSource(f):
+			pkg := filepath.Dir(f)
+			if !seen[pkg] {
+				seen[pkg] = true
+				goPkgs = append(goPkgs, pkg)
+			}
+		case isJSSource(f):
+			jsModules = append(jsModules, f)
+		}
+	}
+
+	var results []Result
+	if len(goPkgs) > 0 {
+		results = append(results, checkGoPackagesHaveTests(dir, goPkgs))
+	}
+	if len(jsModules) > 0 {
+		results = append(results, checkJSModulesHaveTests(dir, jsModules))
+	}
+	return results
+}
+
+// ChangedGoPackages returns the directories of Go packages with changed
+// non-test source files, excluding cmd/.
+func ChangedGoPackages(files []string) []string {
+	var pkgs []string
+	seen := make(map[string]bool)
+	for _, f := range files {
+		if !isGoSource(f) {
+			continue
+		}
+		pkg := filepath.Dir(f)
+		if seen[pkg] || pkg == "cmd" || strings.HasPrefix(pkg, "cmd/") {
+			continue
+		}
+		seen[pkg] = true
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
+
+func checkGoPackagesHaveTests(dir string, pkgs []string) Result {
+	name := "Go: tests for changed packages"
+
+	var missing []string
+	for _, pkg := range pkgs {
+		// Skip main packages under cmd/, which are rarely unit tested
+		if pkg == "cmd" || strings.HasPrefix(pkg, "cmd/") {
+			continue
+		}
+		if !FileExists(filepath.Join(dir, pkg)) {
+			continue
+		}
+		if !goPackageHasTests(filepath.Join(dir, pkg)) {
+			missing = append(missing, pkg)
+		}
+	}
+
+	return testPresenceResult(name, "package", missing, len(pkgs))
+}
+
+func goPackageHasTests(pkgDir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(pkgDir, "*_test.go"))
+	for _, m := range matches {
+		data, err := os.ReadFile(m)
+		if err == nil && goTestFuncPattern.Match(data) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkJSModulesHaveTests(dir string, modules []string) Result {
+	name := "TypeScript: tests for changed modules"
+
+	var missing []string
+	for _, m := range modules {
+		if !FileExists(filepath.Join(dir, m)) {
+			continue
+		}
+		if !jsModuleHasTests(dir, m) {
+			missing = append(missing, m)
+		}
+	}
+
+	return testPresenceResult(name, "module", missing, len(modules))
+}
+
+// jsModuleHasTests looks for foo.test.ts / foo.spec.ts next to the module
+// or in a sibling __tests__ directory.
+func jsModuleHasTests(dir, module string) bool {
+	ext := filepath.Ext(module)
+	base := strings.TrimSuffix(filepath.Base(module), ext)
+	moduleDir := filepath.Join(dir, filepath.Dir(module))
+
+	for _, d := range []string{moduleDir, filepath.Join(moduleDir, "__tests__")} {
+		for _, kind := range []string{".test", ".spec"} {
+			matches, _ := filepath.Glob(filepath.Join(d, base+kind+".*"))
+			if len(matches) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func testPresenceResult(name, kind string, missing []string, total int) Result {
+	if len(missing) == 0 {
+		return Result{
+			Name:   name,
+			Passed: true,
+			Output: fmt.Sprintf("%d changed %s(s) have tests", total, kind),
+		}
+	}
+
+	sort.Strings(missing)
+	return Result{
+		Name:        name,
+		Warning:     true,
+		Passed:      false,
+		Output:      fmt.Sprintf("No tests for changed %s(s):\n%s", kind, strings.Join(missing, "\n")),
+		Remediation: fmt.Sprintf("add tests for the changed %s(s)", kind),
+	}
+}
+
+func isGoSource(path string) bool {
+	return strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, "_test.go") &&
+		!strings.Contains(path, "vendor/") && !strings.Contains(path, "testdata/")
+}
+
+func isJSSource(path string) bool {
+	switch filepath.Ext(path) {
+	case ".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs":
+	default:
+		return false
+	}
+	base := filepath.Base(path)
+	if strings.HasSuffix(base, ".d.ts") || strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.Contains(base, ".config.") {
+		return false
+	}
+	for _, skip := range []string{"node_modules/", "dist/", "build/", "__tests__/"} {
+		if strings.Contains(path, skip) {
+			return false
+		}
+	}
+	return true
+}
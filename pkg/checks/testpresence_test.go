@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckChangedTests(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"pkg/tested/a.go":            "package tested\n",
+		"pkg/tested/a_test.go":       "package tested\n\nfunc TestA(t *testing.T) {}\n",
+		"pkg/untested/b.go":          "package untested\n",
+		"pkg/helpers/c.go":           "package helpers\n",
+		"pkg/helpers/c_test.go":      "package helpers\n\n// no test functions\n",
+		"cmd/tool/main.go":           "package main\n",
+		"src/util.ts":                "export const x = 1\n",
+		"src/__tests__/util.test.ts": "test('x', () => {})\n",
+		"src/other.ts":               "export const y = 2\n",
+		"src/types.d.ts":             "export type T = string\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changed := []string{
+		"pkg/tested/a.go",
+		"pkg/untested/b.go",
+		"pkg/helpers/c.go",
+		"cmd/tool/main.go",
+		"src/util.ts",
+		"src/other.ts",
+		"src/types.d.ts",
+		"README.md",
+	}
+	results := CheckChangedTests(tmpDir, changed)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	goResult := results[0]
+	if goResult.Passed || !goResult.Warning {
+		t.Errorf("expected Go warning, got %+v", goResult)
+	}
+	want := "No tests for changed package(s):\npkg/helpers\npkg/untested"
+	if goResult.Output != want {
+		t.Errorf("expected output %q, got %q", want, goResult.Output)
+	}
+
+	jsResult := results[1]
+	if jsResult.Passed || jsResult.Output != "No tests for changed module(s):\nsrc/other.ts" {
+		t.Errorf("unexpected TypeScript result %+v", jsResult)
+	}
+}
+
+func TestCheckChangedTests_NoSourceChanges(t *testing.T) {
+	results := CheckChangedTests(t.TempDir(), []string{"README.md", "pkg/a/a_test.go"})
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %+v", results)
+	}
+}
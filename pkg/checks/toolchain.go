@@ -0,0 +1,224 @@
+package checks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+var (
+	goDirectivePattern        = regexp.MustCompile(`^go\s+(\d+(?:\.\d+){1,2}\S*)\s*$`)
+	toolchainDirectivePattern = regexp.MustCompile(`^toolchain\s+go(\d+(?:\.\d+){1,2}\S*)\s*$`)
+	ciGoVersionPattern        = regexp.MustCompile(`^\s*-?\s*go-version:\s*(.+)$`)
+	goVersionNumberPattern    = regexp.MustCompile(`\d+\.\d+(?:\.(?:\d+|x))?`)
+)
+
+// GoModDirectives holds the Go version directives declared in go.mod.
+type GoModDirectives struct {
+	Go        string // "go" directive, e.g. "1.25.0"
+	Toolchain string // "toolchain" directive without the "go" prefix, e.g. "1.25.3"
+}
+
+// ParseGoModDirectives reads the go and toolchain directives from a go.mod file.
+func ParseGoModDirectives(path string) (GoModDirectives, error) {
+	var d GoModDirectives
+
+	f, err := os.Open(path)
+	if err != nil {
+		return d, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if m := goDirectivePattern.FindStringSubmatch(line); m != nil {
+			d.Go = m[1]
+		} else if m := toolchainDirectivePattern.FindStringSubmatch(line); m != nil {
+			d.Toolchain = m[1]
+		}
+	}
+	return d, scanner.Err()
+}
+
+// CIGoVersions returns the Go versions pinned via go-version in GitHub
+// Actions workflows, keyed by workflow file name. An "x" patch, as in
+// "1.24.x", is kept. Versions computed from expressions and go-version-file
+// entries are ignored.
+func CIGoVersions(dir string) (map[string][]string, error) {
+	versions := make(map[string][]string)
+
+	var files []string
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, ".github", "workflows", pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			m := ciGoVersionPattern.FindStringSubmatch(line)
+			if m == nil || strings.Contains(m[1], "${{") {
+				continue
+			}
+			for _, v := range goVersionNumberPattern.FindAllString(m[1], -1) {
+				versions[filepath.Base(file)] = append(versions[filepath.Base(file)], v)
+			}
+		}
+	}
+	return versions, nil
+}
+
+// CompareGoVersions compares two Go versions such as "1.25", "1.25.0" or
+// "go1.25.3". Missing components are treated as zero, an "x" component
+// matches any (so "1.24.x" equals "1.24.2"), and pre-release suffixes
+// (e.g. "rc1") are ignored. Returns -1, 0 or 1.
+func CompareGoVersions(a, b string) int {
+	pa, pb := goVersionParts(a), goVersionParts(b)
+	for i := range pa {
+		if pa[i] == anyGoVersion || pb[i] == anyGoVersion {
+			return 0
+		}
+		if pa[i] < pb[i] {
+			return -1
+		}
+		if pa[i] > pb[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// anyGoVersion is the part goVersionParts returns for an "x" component.
+const anyGoVersion = -1
+
+func goVersionParts(v string) [3]int {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "go")
+	for i, s := range strings.SplitN(v, ".", 3) {
+		if s == "x" {
+			parts[i] = anyGoVersion
+			continue
+		}
+		// Strip pre-release suffixes such as "0rc1"
+		end := 0
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		parts[i], _ = strconv.Atoi(s[:end])
+	}
+	return parts
+}
+
+// installedGoVersion returns the version of the go command in PATH,
+// without the "go" prefix.
+func installedGoVersion(dir string) (string, error) {
+	cmd := proc.Command("go", "env", "GOVERSION")
+	cmd.Dir = dir
+	// Report the local toolchain rather than one go.mod would switch to
+	cmd.Env = append(cmd.Environ(), "GOTOOLCHAIN=local")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), "go"), nil
+}
+
+func (c *ReleaseChecker) checkGoToolchain(dir string) Result {
+	name := "Release: Go toolchain"
+
+	gomod := filepath.Join(dir, "go.mod")
+	if !FileExists(gomod) {
+		return Result{
+			Name:    name,
+			Skipped: true,
+			Reason:  "Not a Go project",
+		}
+	}
+
+	directives, err := ParseGoModDirectives(gomod)
+	if err != nil {
+		return Result{
+			Name:   name,
+			Passed: false,
+			Error:  err,
+		}
+	}
+	if directives.Go == "" {
+		return Result{
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      "go.mod has no go directive",
+			Remediation: "add one: go mod edit -go=<minimum Go version>",
+		}
+	}
+
+	if r, ok := disallowed(name, "go"); ok {
+		return r
+	}
+
+	var problems []string
+
+	if !CommandExists("go") {
+		problems = append(problems, "go command not found in PATH")
+	} else if installed, err := installedGoVersion(dir); err != nil {
+		problems = append(problems, fmt.Sprintf("could not determine installed Go version: %v", err))
+	} else {
+		if CompareGoVersions(installed, directives.Go) < 0 {
+			problems = append(problems, fmt.Sprintf("installed go%s is older than go.mod go %s", installed, directives.Go))
+		}
+		if directives.Toolchain != "" && CompareGoVersions(installed, directives.Toolchain) < 0 {
+			problems = append(problems, fmt.Sprintf("installed go%s is older than go.mod toolchain go%s", installed, directives.Toolchain))
+		}
+	}
+
+	ciVersions, err := CIGoVersions(dir)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("could not read CI workflows: %v", err))
+	}
+	files := make([]string, 0, len(ciVersions))
+	for file := range ciVersions {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		for _, v := range ciVersions[file] {
+			if CompareGoVersions(v, directives.Go) < 0 {
+				problems = append(problems, fmt.Sprintf("%s uses Go %s, older than go.mod go %s", file, v, directives.Go))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return Result{
+			Name:        name,
+			Warning:     true,
+			Passed:      false,
+			Output:      strings.Join(problems, "\n"),
+			Remediation: fmt.Sprintf("use Go %s or later locally and in the CI workflows", directives.Go),
+		}
+	}
+
+	return Result{
+		Name:   name,
+		Passed: true,
+		Output: fmt.Sprintf("go %s satisfied", directives.Go),
+	}
+}
@@ -0,0 +1,138 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.25.0", "1.25", 0},
+		{"go1.25.3", "1.25.0", 1},
+		{"1.24", "1.25.0", -1},
+		{"1.26rc1", "1.25.9", 1},
+		{"1.25.0rc1", "1.25.0", 0},
+		{"1.24.x", "1.24.2", 0},
+		{"1.25.3", "1.25.x", 0},
+		{"1.24.x", "1.25.0", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareGoVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareGoVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseGoModDirectives(t *testing.T) {
+	tmpDir := t.TempDir()
+	gomod := filepath.Join(tmpDir, "go.mod")
+	content := "module example.com/m\n\ngo 1.24.2 // minimum\n\ntoolchain go1.25.1\n\nrequire golang.org/x/mod v0.20.0\n"
+	if err := os.WriteFile(gomod, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := ParseGoModDirectives(gomod)
+	if err != nil {
+		t.Fatalf("ParseGoModDirectives: %v", err)
+	}
+	if d.Go != "1.24.2" {
+		t.Errorf("expected go 1.24.2, got %q", d.Go)
+	}
+	if d.Toolchain != "1.25.1" {
+		t.Errorf("expected toolchain 1.25.1, got %q", d.Toolchain)
+	}
+}
+
+func TestCIGoVersions(t *testing.T) {
+	tmpDir := t.TempDir()
+	workflows := filepath.Join(tmpDir, ".github", "workflows")
+	if err := os.MkdirAll(workflows, 0755); err != nil {
+		t.Fatal(err)
+	}
+	ci := `jobs:
+  test:
+    strategy:
+      matrix:
+        go-version: ['1.23', '1.24.x']
+    steps:
+      - uses: actions/setup-go@v5
+        with:
+          go-version: ${{ matrix.go-version }}
+      - uses: actions/setup-go@v5
+        with:
+          go-version: "1.25"
+      - uses: actions/setup-go@v5
+        with:
+          go-version-file: go.mod
+`
+	if err := os.WriteFile(filepath.Join(workflows, "ci.yaml"), []byte(ci), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	versions, err := CIGoVersions(tmpDir)
+	if err != nil {
+		t.Fatalf("CIGoVersions: %v", err)
+	}
+	got := versions["ci.yaml"]
+	want := []string{"1.23", "1.24.x", "1.25"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+}
+
+func TestCheckGoToolchain_CIMismatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module m\n\ngo 1.21\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	workflows := filepath.Join(tmpDir, ".github", "workflows")
+	if err := os.MkdirAll(workflows, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workflows, "ci.yml"), []byte("go-version: '1.20'\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ReleaseChecker{}
+	result := c.checkGoToolchain(tmpDir)
+	if result.Passed || !result.Warning {
+		t.Errorf("expected warning for CI Go version mismatch, got %+v", result)
+	}
+}
+
+func TestCheckGoToolchain_CIPatchWildcard(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module m\n\ngo 1.21.5\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	workflows := filepath.Join(tmpDir, ".github", "workflows")
+	if err := os.MkdirAll(workflows, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(workflows, "ci.yml"), []byte("go-version: '1.21.x'\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &ReleaseChecker{}
+	if result := c.checkGoToolchain(tmpDir); !result.Passed {
+		t.Errorf("expected 1.21.x to satisfy go 1.21.5, got %+v", result)
+	}
+}
+
+func TestCheckGoToolchain_NotGoProject(t *testing.T) {
+	c := &ReleaseChecker{}
+	result := c.checkGoToolchain(t.TempDir())
+	if !result.Skipped {
+		t.Errorf("expected skipped result, got %+v", result)
+	}
+}
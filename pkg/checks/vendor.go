@@ -0,0 +1,158 @@
+package checks
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// maxVendorDiffs is how many differing files CheckGoVendor lists.
+const maxVendorDiffs = 20
+
+// HasGoVendor reports whether the Go module in dir vendors its
+// dependencies.
+func HasGoVendor(dir string) bool {
+	return FileExists(filepath.Join(dir, "vendor", "modules.txt"))
+}
+
+// CheckGoVendor checks that the committed vendor directory of the Go
+// module in dir matches what `go mod vendor` produces, catching
+// dependency changes that weren't re-vendored. The fresh copy is written
+// to a scratch directory, so the working tree isn't touched.
+func CheckGoVendor(dir string) Result {
+	name := "Go: vendor"
+	if !HasGoVendor(dir) {
+		return Result{Name: name, Path: dir, Skipped: true, Reason: "no vendor directory"}
+	}
+	if r, ok := disallowed(name, "go"); ok {
+		r.Path = dir
+		return r
+	}
+
+	tmp, err := os.MkdirTemp("", "atrelease-vendor-")
+	if err != nil {
+		return Result{Name: name, Path: dir, Error: err}
+	}
+	defer os.RemoveAll(tmp)
+	fresh := filepath.Join(tmp, "vendor")
+
+	start := time.Now()
+	cmd := proc.Command("go", "mod", "vendor", "-o", fresh)
+	cmd.Dir = dir
+	// Workspaces are vendored with go work vendor; check the module alone
+	cmd.Env = append(cmd.Environ(), "GOWORK=off")
+	output, err := runOutput(cmd, name, true)
+	if err != nil {
+		return Result{
+			Name:        name,
+			Path:        dir,
+			Output:      "go mod vendor failed:\n" + strings.TrimSpace(string(output)),
+			Remediation: "fix the go.mod errors above, then run go mod vendor",
+			Duration:    time.Since(start),
+		}
+	}
+
+	diffs, err := diffTrees(filepath.Join(dir, "vendor"), fresh)
+	if err != nil {
+		return Result{Name: name, Path: dir, Error: err, Duration: time.Since(start)}
+	}
+	if len(diffs) == 0 {
+		return Result{
+			Name:     name,
+			Path:     dir,
+			Passed:   true,
+			Output:   "vendor/ matches go mod vendor",
+			Duration: time.Since(start),
+		}
+	}
+
+	lines := []string{fmt.Sprintf("vendor/ is out of date with go.mod (%d files differ)", len(diffs))}
+	for i, d := range diffs {
+		if i == maxVendorDiffs {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(diffs)-i))
+			break
+		}
+		lines = append(lines, "  "+d)
+	}
+	return Result{
+		Name:        name,
+		Path:        dir,
+		Output:      strings.Join(lines, "\n"),
+		Duration:    time.Since(start),
+		Remediation: "run go mod vendor",
+	}
+}
+
+// diffTrees compares the files under have with those under want and
+// describes each difference, e.g. "modified: vendor/modules.txt", sorted
+// by path.
+func diffTrees(have, want string) ([]string, error) {
+	haveFiles, err := treeFiles(have)
+	if err != nil {
+		return nil, err
+	}
+	wantFiles, err := treeFiles(want)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for rel := range wantFiles {
+		if _, ok := haveFiles[rel]; !ok {
+			diffs = append(diffs, "missing: vendor/"+rel)
+			continue
+		}
+		a, err := os.ReadFile(filepath.Join(have, rel))
+		if err != nil {
+			return nil, err
+		}
+		b, err := os.ReadFile(filepath.Join(want, rel))
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(a, b) {
+			diffs = append(diffs, "modified: vendor/"+rel)
+		}
+	}
+	for rel := range haveFiles {
+		if _, ok := wantFiles[rel]; !ok {
+			diffs = append(diffs, "extra: vendor/"+rel)
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		_, pi, _ := strings.Cut(diffs[i], " ")
+		_, pj, _ := strings.Cut(diffs[j], " ")
+		return pi < pj
+	})
+	return diffs, nil
+}
+
+// treeFiles returns the slash-separated paths of the regular files under
+// root. A missing root has no files.
+func treeFiles(root string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && os.IsNotExist(err) {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+	return files, err
+}
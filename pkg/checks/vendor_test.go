@@ -0,0 +1,57 @@
+package checks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// vendoredModule writes a module that depends on a local module through a
+// replace directive, and vendors it.
+func vendoredModule(t *testing.T) string {
+	t.Helper()
+	if !CommandExists("go") {
+		t.Skip("go not installed")
+	}
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.21\n\nrequire example.com/dep v0.0.0\n\nreplace example.com/dep => ./dep\n")
+	writeTestFile(t, filepath.Join(dir, "main.go"), "package main\n\nimport \"example.com/dep\"\n\nfunc main() { dep.Hello() }\n")
+	writeTestFile(t, filepath.Join(dir, "dep", "go.mod"), "module example.com/dep\n\ngo 1.21\n")
+	writeTestFile(t, filepath.Join(dir, "dep", "dep.go"), "package dep\n\nfunc Hello() {}\n")
+
+	cmd := exec.Command("go", "mod", "vendor")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go mod vendor: %v\n%s", err, out)
+	}
+	return dir
+}
+
+func TestCheckGoVendor(t *testing.T) {
+	dir := vendoredModule(t)
+	if r := CheckGoVendor(dir); !r.Passed {
+		t.Fatalf("expected a fresh vendor directory to pass, got %+v", r)
+	}
+
+	// Change the dependency without re-vendoring
+	writeTestFile(t, filepath.Join(dir, "dep", "dep.go"), "package dep\n\nfunc Hello() { println() }\n")
+	writeTestFile(t, filepath.Join(dir, "vendor", "stale.txt"), "left over\n")
+	r := CheckGoVendor(dir)
+	if r.Passed || r.Skipped {
+		t.Fatalf("expected a stale vendor directory to fail, got %+v", r)
+	}
+	for _, want := range []string{"modified: vendor/example.com/dep/dep.go", "extra: vendor/stale.txt"} {
+		if !strings.Contains(r.Output, want) {
+			t.Errorf("output missing %q:\n%s", want, r.Output)
+		}
+	}
+}
+
+func TestCheckGoVendor_NoVendor(t *testing.T) {
+	if r := CheckGoVendor(t.TempDir()); !r.Skipped {
+		t.Errorf("expected a module without vendor/ to be skipped, got %+v", r)
+	}
+}
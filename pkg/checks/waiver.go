@@ -0,0 +1,171 @@
+package checks
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/detect"
+)
+
+// findingLine matches a finding in check output, such as
+// "internal/db/conn.go:42:7: SA1019: ...", capturing the file and line.
+var findingLine = regexp.MustCompile(`^\s*([^\s:]+\.[A-Za-z0-9]+):(\d+)(?::\d+)?:`)
+
+// ignoreComment matches an inline waiver, such as
+// "//atrelease:ignore go.golangci_lint reason=legacy", in any comment
+// syntax. It waives the findings of the check on its own line and the
+// line below.
+var ignoreComment = regexp.MustCompile(`atrelease:ignore\s+(\S+)(?:\s+reason=(.*))?`)
+
+// WaiverExpired reports whether w no longer applies on the day of now. A
+// waiver with an unparsable expiry date is treated as expired.
+func WaiverExpired(w config.Waiver, now time.Time) bool {
+	if w.Expires == "" {
+		return false
+	}
+	if _, err := time.Parse(time.DateOnly, w.Expires); err != nil {
+		return true
+	}
+	return now.Format(time.DateOnly) > w.Expires
+}
+
+// ApplyWaivers acknowledges findings in failed and warning results of
+// dir. A config waiver without a path or match waives the whole check.
+// Otherwise each "file:line:" finding in the output is waived by a config
+// waiver that matches it or by an atrelease:ignore comment at that line.
+// A result whose findings are all waived passes, listing them; expired
+// waivers no longer apply and are noted in the output.
+func ApplyWaivers(dir string, results []Result, waivers []config.Waiver, now time.Time) {
+	sources := make(map[string][]string)
+	for i, r := range results {
+		if s := r.Severity(); s != SeverityFailed && s != SeverityWarning {
+			continue
+		}
+		id := ResultID(r)
+		var active, expired []config.Waiver
+		for _, w := range waivers {
+			if !MatchID(id, []string{w.ID}) {
+				continue
+			}
+			if WaiverExpired(w, now) {
+				expired = append(expired, w)
+			} else {
+				active = append(active, w)
+			}
+		}
+
+		if w, ok := wholeCheckWaiver(active); ok {
+			results[i].Passed = true
+			results[i].Warning = false
+			results[i].Findings = nil
+			results[i].Output = strings.TrimSpace(fmt.Sprintf("Waived%s: %s\n%s", waiverUntil(w), w.Reason, r.Output))
+			continue
+		}
+
+		base := pathInDir(dir, r.Path)
+		var kept, waived []string
+		findings := 0
+		for _, line := range strings.Split(r.Output, "\n") {
+			m := findingLine.FindStringSubmatch(line)
+			if m == nil {
+				kept = append(kept, line)
+				continue
+			}
+			findings++
+			file := filepath.ToSlash(filepath.Join(base, m[1]))
+			n, _ := strconv.Atoi(m[2])
+			if reason, ok := findingWaived(id, line, file, n, active, filepath.Join(dir, filepath.FromSlash(file)), sources); ok {
+				waived = append(waived, fmt.Sprintf("%s (%s)", strings.TrimSpace(line), reason))
+				results[i].Findings = dropFinding(results[i].Findings, filepath.ToSlash(m[1]), n, line)
+				continue
+			}
+			kept = append(kept, line)
+		}
+
+		if len(waived) > 0 && len(waived) == findings {
+			results[i].Passed = true
+			results[i].Warning = false
+			results[i].Output = fmt.Sprintf("All %d findings waived:\n%s", len(waived), strings.Join(waived, "\n"))
+			continue
+		}
+		output := strings.Join(kept, "\n")
+		if len(waived) > 0 {
+			output += fmt.Sprintf("\n(%d findings waived)", len(waived))
+		}
+		for _, w := range expired {
+			output += fmt.Sprintf("\nWaiver for %s expired on %s: %s", w.ID, w.Expires, w.Reason)
+		}
+		results[i].Output = strings.TrimSpace(output)
+	}
+}
+
+// wholeCheckWaiver returns the first waiver without a path or match.
+func wholeCheckWaiver(waivers []config.Waiver) (config.Waiver, bool) {
+	for _, w := range waivers {
+		if w.Path == "" && w.Match == "" {
+			return w, true
+		}
+	}
+	return config.Waiver{}, false
+}
+
+// waiverUntil describes when w expires, e.g. " until 2026-12-31".
+func waiverUntil(w config.Waiver) string {
+	if w.Expires == "" {
+		return ""
+	}
+	return " until " + w.Expires
+}
+
+// findingWaived reports whether the finding on line, at line n of file (a
+// path relative to the checked directory, read from path), is waived for
+// check id, and why. sources caches the lines of files read.
+func findingWaived(id, line, file string, n int, waivers []config.Waiver, path string, sources map[string][]string) (string, bool) {
+	for _, w := range waivers {
+		if (w.Path == "" || detect.Excluded([]string{w.Path}, file)) && strings.Contains(line, w.Match) {
+			return "waived" + waiverUntil(w) + ": " + w.Reason, true
+		}
+	}
+
+	lines, ok := sources[path]
+	if !ok {
+		lines = readLines(path)
+		sources[path] = lines
+	}
+	for _, at := range []int{n, n - 1} {
+		if at < 1 || at > len(lines) {
+			continue
+		}
+		m := ignoreComment.FindStringSubmatch(lines[at-1])
+		if m != nil && MatchID(id, []string{m[1]}) {
+			if reason := strings.TrimSpace(m[2]); reason != "" {
+				return "ignored: " + reason, true
+			}
+			return "ignored", true
+		}
+	}
+	return "", false
+}
+
+// readLines returns the lines of a file, or nil if it can't be read.
+func readLines(path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
@@ -0,0 +1,70 @@
+package checks
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func TestWaiverExpired(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expires string
+		want    bool
+	}{
+		{"", false},
+		{"2026-10-16", false},
+		{"2026-12-31", false},
+		{"2026-10-15", true},
+		{"next year", true},
+	}
+	for _, tt := range tests {
+		if got := WaiverExpired(config.Waiver{Expires: tt.expires}, now); got != tt.want {
+			t.Errorf("WaiverExpired(%q) = %v, want %v", tt.expires, got, tt.want)
+		}
+	}
+}
+
+func TestApplyWaivers(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "svc", "db.go"), "package svc\n\n//atrelease:ignore go.golangci_lint reason=legacy driver\nvar x = old()\n")
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	waivers := []config.Waiver{
+		{ID: "go.golangci_lint", Path: "svc/gen/**", Reason: "generated"},
+		{ID: "go.vet", Reason: "tracked in #12", Expires: "2026-12-31"},
+		{ID: "go.test", Reason: "flaky", Expires: "2026-01-01"},
+	}
+	results := []Result{
+		{ID: "go.golangci_lint", Path: "svc", Output: "db.go:4:9: SA1019: old is deprecated\ngen/api.go:10:1: unused (unused)"},
+		{ID: "go.golangci_lint", Path: "web", Output: "main.go:3:1: errcheck"},
+		{ID: "go.vet", Output: "vet: something"},
+		{ID: "go.test", Output: "--- FAIL: TestX"},
+		{ID: "go.build", Passed: true},
+	}
+	ApplyWaivers(dir, results, waivers, now)
+
+	if r := results[0]; !r.Passed || !strings.Contains(r.Output, "All 2 findings waived") ||
+		!strings.Contains(r.Output, "ignored: legacy driver") || !strings.Contains(r.Output, "waived: generated") {
+		t.Errorf("expected every svc finding to be waived, got %+v", r)
+	}
+	if r := results[1]; r.Passed || r.Output != "main.go:3:1: errcheck" {
+		t.Errorf("expected the web finding to stay, got %+v", r)
+	}
+	if r := results[2]; !r.Passed || !strings.HasPrefix(r.Output, "Waived until 2026-12-31: tracked in #12") {
+		t.Errorf("expected go.vet to be waived, got %+v", r)
+	}
+	if r := results[3]; r.Passed || !strings.Contains(r.Output, "Waiver for go.test expired on 2026-01-01: flaky") {
+		t.Errorf("expected the expired waiver to fail with a note, got %+v", r)
+	}
+}
+
+func TestApplyWaivers_Partial(t *testing.T) {
+	results := []Result{{ID: "go.golangci_lint", Output: "a.go:1:1: x\nb.go:2:1: y"}}
+	ApplyWaivers(t.TempDir(), results, []config.Waiver{{ID: "go.*", Match: "x"}}, time.Now())
+	if r := results[0]; r.Passed || r.Output != "b.go:2:1: y\n(1 findings waived)" {
+		t.Errorf("expected one finding left, got %+v", r)
+	}
+}
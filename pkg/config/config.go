@@ -2,18 +2,400 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
-
-	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 )
 
+// ErrInvalid is returned by Load when the configuration file cannot be parsed.
+var ErrInvalid = errors.New("invalid configuration")
+
 // Config represents the .releaseagent.yaml configuration.
 type Config struct {
+	// Shared policy bundle this file overrides, e.g. github.com/org/policy@v1
+	Extends string `yaml:"extends"`
+
 	// Global settings
 	Verbose bool `yaml:"verbose"`
+	Stash   bool `yaml:"stash"`   // stash unstaged changes while checks run
+	Strict  bool `yaml:"strict"`  // treat warning results as failures
+	History bool `yaml:"history"` // record check runs in .atrelease/history.jsonl
 
 	// Language-specific settings
 	Languages map[string]LanguageConfig `yaml:"languages"`
+
+	// Language detection settings
+	Detect DetectConfig `yaml:"detect"`
+
+	// Tag settings
+	Tag TagConfig `yaml:"tag"`
+
+	// Release workflow settings
+	Release ReleaseConfig `yaml:"release"`
+
+	// Release binary build settings
+	Build BuildConfig `yaml:"build"`
+
+	// Release artifact checksum and provenance settings
+	Artifacts ArtifactsConfig `yaml:"artifacts"`
+
+	// Coverage delta settings for check --coverage-diff
+	CoverageDiff CoverageDiffConfig `yaml:"coverage_diff"`
+
+	// QA metrics recorded per release and compared by validate
+	QABaseline QABaselineConfig `yaml:"qa_baseline"`
+
+	// Benchmark regression settings
+	Benchmarks BenchmarkConfig `yaml:"benchmarks"`
+
+	// Roadmap action settings
+	Roadmap RoadmapConfig `yaml:"roadmap"`
+
+	// Documentation check settings
+	Docs DocsConfig `yaml:"docs"`
+
+	// Security check settings
+	Security SecurityConfig `yaml:"security"`
+
+	// README action settings
+	Readme ReadmeConfig `yaml:"readme"`
+
+	// Check result filters
+	Checks ChecksConfig `yaml:"checks"`
+
+	// External tool execution policy
+	Tools ToolsConfig `yaml:"tools"`
+
+	// Compliance controls for policy report
+	Policy PolicyConfig `yaml:"policy"`
+
+	// Container settings for check --container
+	Container ContainerConfig `yaml:"container"`
+
+	// Remote execution settings for heavy checks
+	Remote RemoteConfig `yaml:"remote"`
+
+	// Proxy, CA, and GitHub Enterprise settings for HTTP clients
+	Network NetworkConfig `yaml:"network"`
+
+	// Areas and checks run by validate
+	Validate ValidateConfig `yaml:"validate"`
+
+	// Wording of Go/No-Go reports
+	Report ReportConfig `yaml:"report"`
+
+	// Validation report export to OpenTelemetry and Prometheus
+	Telemetry TelemetryConfig `yaml:"telemetry"`
+}
+
+// ValidateAreas are the keys of the validation areas in ValidateConfig.
+// Areas registered with checks.RegisterArea are added to it; areas defined
+// in ValidateConfig.Custom are valid keys too.
+var ValidateAreas = []string{"pm", "qa", "documentation", "release", "security"}
+
+// ValidateConfig tailors the validation matrix of the validate command.
+type ValidateConfig struct {
+	Areas  map[string]AreaConfig `yaml:"areas"`  // keyed by area: pm, qa, documentation, release, security, or a custom area
+	Custom []CustomArea          `yaml:"custom"` // additional areas whose checks are external commands
+}
+
+// CustomArea is a validation area defined in config, such as "Legal",
+// whose checks run external commands.
+type CustomArea struct {
+	Name      string        `yaml:"name"`       // area name shown in reports, e.g. Legal
+	DependsOn []string      `yaml:"depends_on"` // keys of areas defined before it that run first; default pm
+	Checks    []CustomCheck `yaml:"checks"`     // commands run in the validated directory
+}
+
+// Key returns the area's key in Areas.
+func (a CustomArea) Key() string {
+	return AreaKey(a.Name)
+}
+
+// AreaKey returns the key in ValidateConfig.Areas of the area named name:
+// the name lowercased, with words joined by underscores, e.g. "pm" for
+// "PM" and "legal_review" for "Legal Review".
+func AreaKey(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "_")
+}
+
+// CustomCheck is a check of a custom area that passes if its command
+// exits with status 0.
+type CustomCheck struct {
+	Name    string   `yaml:"name"`    // check name, reported as "Area: name"
+	Command []string `yaml:"command"` // program and arguments, e.g. [./scripts/check-headers.sh, --all]
+	Warning bool     `yaml:"warning"` // a failure warns instead of blocking the release
+}
+
+// AreaConfig enables a validation area and skips some of its checks.
+type AreaConfig struct {
+	Enabled *bool       `yaml:"enabled"` // nil means enabled
+	Reason  string      `yaml:"reason"`  // why the area is disabled, shown in the report
+	Skip    []CheckSkip `yaml:"skip"`    // checks of the area to report as skipped
+}
+
+// IsEnabled reports whether the area runs.
+func (a AreaConfig) IsEnabled() bool {
+	return a.Enabled == nil || *a.Enabled
+}
+
+// ReportVocabularies are the values of ReportConfig.Vocabulary.
+var ReportVocabularies = []string{"go_no_go", "pass_fail"}
+
+// ReportConfig sets the wording of Go/No-Go reports, for organizations
+// whose release records can't use the NASA-style GO/NO-GO.
+type ReportConfig struct {
+	Vocabulary string         `yaml:"vocabulary"` // go_no_go (default) or pass_fail
+	Labels     StatusLabels   `yaml:"labels"`     // status labels overriding the vocabulary's
+	Messages   ReportMessages `yaml:"messages"`   // final verdicts overriding the vocabulary's
+}
+
+// StatusLabels are the labels reports show for each status. Empty labels
+// keep the vocabulary's.
+type StatusLabels struct {
+	Go   string `yaml:"go"`    // e.g. APPROVED
+	NoGo string `yaml:"no_go"` // e.g. REJECTED
+	Warn string `yaml:"warn"`
+	Skip string `yaml:"skip"`
+}
+
+// ReportMessages are the final verdicts of report boxes, as Go templates
+// with .Version, .Status, and .Blocking, the areas or checks that failed.
+type ReportMessages struct {
+	Go   string `yaml:"go"`    // e.g. "Release {{.Version}} approved"
+	NoGo string `yaml:"no_go"` // e.g. "Release blocked by {{join .Blocking \", \"}}"
+}
+
+// check reports an unknown vocabulary or a message that isn't a valid
+// template.
+func (r ReportConfig) check() error {
+	if r.Vocabulary != "" && !slices.Contains(ReportVocabularies, r.Vocabulary) {
+		return fmt.Errorf("unknown report.vocabulary %q (expected one of %s)",
+			r.Vocabulary, strings.Join(ReportVocabularies, ", "))
+	}
+	for name, msg := range map[string]string{"go": r.Messages.Go, "no_go": r.Messages.NoGo} {
+		if _, err := ParseReportMessage(msg); err != nil {
+			return fmt.Errorf("report.messages.%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// ParseReportMessage parses a message of ReportMessages. Besides the
+// standard template functions, messages can use join, as in strings.Join.
+func ParseReportMessage(msg string) (*template.Template, error) {
+	return template.New("message").Funcs(template.FuncMap{"join": strings.Join}).Parse(msg)
+}
+
+// CheckSkip skips the checks matching an ID, recording why.
+type CheckSkip struct {
+	ID     string `yaml:"id"`     // check ID; patterns like "pm.*" are allowed
+	Reason string `yaml:"reason"` // why the check doesn't apply, shown in the report
+}
+
+// RemoteConfig holds settings for offloading heavy checks to a remote
+// runner.
+type RemoteConfig struct {
+	Backend  string   `yaml:"backend"`   // ssh or http; empty runs heavy checks locally
+	Host     string   `yaml:"host"`      // ssh destination, e.g. ci@runner
+	Dir      string   `yaml:"dir"`       // directory on the ssh host the tree is synced to
+	URL      string   `yaml:"url"`       // HTTP agent endpoint
+	TokenEnv string   `yaml:"token_env"` // environment variable holding the HTTP agent token
+	Checks   []string `yaml:"checks"`    // heavy checks to offload: tests, race
+}
+
+// ContainerConfig holds settings for running checks in containers.
+type ContainerConfig struct {
+	Enabled bool              `yaml:"enabled"` // always run checks in containers (same as --container)
+	Runtime string            `yaml:"runtime"` // container CLI: docker (default) or podman
+	Image   string            `yaml:"image"`   // image for languages without their own
+	Images  map[string]string `yaml:"images"`  // image per language, e.g. go: golang:1.23
+	Cache   string            `yaml:"cache"`   // host cache directory mounted writable; default user cache
+}
+
+// ChecksConfig filters check results by their stable IDs, such as
+// "go.test" or "ts.lint", and limits the output shown for each.
+type ChecksConfig struct {
+	Skip     []string `yaml:"skip"`      // check IDs to skip; patterns like "go.*" are allowed
+	MaxLines int      `yaml:"max_lines"` // output lines shown per check; 0 shows all
+	Waivers  []Waiver `yaml:"waivers"`   // acknowledged findings
+
+	Flaky        []FlakyTest `yaml:"flaky"`         // known-flaky tests, rerun when they fail
+	FlakyRetries int         `yaml:"flaky_retries"` // reruns of failed flaky tests before they count as failed
+}
+
+// FlakyTest quarantines known-flaky tests: when they fail they are rerun,
+// and if they keep failing the test check warns instead of failing, as
+// long as no other test failed.
+type FlakyTest struct {
+	ID     string `yaml:"id"`     // test check ID, e.g. go.test; patterns are allowed; empty for any
+	Test   string `yaml:"test"`   // test name pattern, e.g. TestUpload* or "client > retries *"
+	Issue  string `yaml:"issue"`  // tracking issue URL, shown with the failures
+	Reason string `yaml:"reason"` // why the test is flaky
+}
+
+// check reports a flaky test without a name or a negative retry count.
+func (c ChecksConfig) check() error {
+	for i, f := range c.Flaky {
+		if f.Test == "" {
+			return fmt.Errorf("checks.flaky[%d]: test is required", i)
+		}
+	}
+	if c.FlakyRetries < 0 {
+		return fmt.Errorf("checks.flaky_retries must not be negative, got %d", c.FlakyRetries)
+	}
+	return nil
+}
+
+// Waiver acknowledges findings of a check, until it expires, without
+// disabling the check.
+type Waiver struct {
+	ID      string `yaml:"id"`      // check ID; patterns like "go.*" are allowed
+	Path    string `yaml:"path"`    // file pattern of the findings, e.g. internal/legacy/**; empty for any
+	Match   string `yaml:"match"`   // text the finding contains, e.g. SA1019; empty for any
+	Reason  string `yaml:"reason"`  // why the findings are acceptable
+	Expires string `yaml:"expires"` // last day the waiver applies, as YYYY-MM-DD; empty for never
+}
+
+// PolicyConfig holds settings for the compliance report of policy report.
+type PolicyConfig struct {
+	Controls []string `yaml:"controls"` // controls that must pass: checks, tests, vulnerabilities, license; empty for all
+}
+
+// ToolsConfig restricts which external binaries checks may run.
+type ToolsConfig struct {
+	Allow []string `yaml:"allow"` // binaries checks may run; empty allows any not denied
+	Deny  []string `yaml:"deny"`  // binaries checks may never run
+}
+
+// DetectConfig holds settings for language detection.
+type DetectConfig struct {
+	Exclude  []string `yaml:"exclude"`   // path patterns to skip, e.g. "third_party/**"
+	MaxDepth int      `yaml:"max_depth"` // directory levels to scan below the root; 0 for no limit
+	Cache    bool     `yaml:"cache"`     // reuse results until a directory or indicator file changes
+}
+
+// ReadmeConfig holds settings for the README action.
+type ReadmeConfig struct {
+	Badges               []BadgePattern `yaml:"badges"`                 // extra version patterns to rewrite
+	DisableDefaultBadges bool           `yaml:"disable_default_badges"` // use only Badges, not the built-in patterns
+	Image                string         `yaml:"image"`                  // Docker image whose tags are rewritten, e.g. "example/project"
+}
+
+// BadgePattern is a version reference in README.md to rewrite on release.
+// Replace may use regexp groups (${1}) and the placeholders {{version}}
+// ("v1.2.0"), {{semver}} ("1.2.0"), and {{shields}} ("1.2.0" with "-"
+// escaped as "--" for shields.io static badges). Pattern may use
+// {{module}}, {{package}}, and {{image}} for the project's Go module path,
+// npm package name, and Docker image.
+type BadgePattern struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+	Replace string `yaml:"replace"`
+}
+
+// DocsConfig holds settings for the documentation checks in validate.
+type DocsConfig struct {
+	MinCoverage float64 `yaml:"min_coverage"` // minimum Go API doc coverage in percent (0 = report only)
+}
+
+// SecurityConfig holds settings for the security checks in validate.
+type SecurityConfig struct {
+	Reproducible bool `yaml:"reproducible"` // build Go commands twice and warn if the binaries differ
+}
+
+// RoadmapConfig holds settings for the roadmap action.
+type RoadmapConfig struct {
+	SyncIssues bool   `yaml:"sync_issues"` // sync ROADMAP.json items with GitHub issues
+	IssueLabel string `yaml:"issue_label"` // label applied to issues created for roadmap items
+}
+
+// CoverageDiffConfig holds settings for the per-package coverage delta check.
+type CoverageDiffConfig struct {
+	MaxDrop float64 `yaml:"max_drop"` // allowed drop in percentage points per changed package
+	Fail    bool    `yaml:"fail"`     // fail instead of warn when coverage drops more than max_drop
+}
+
+// QABaselineConfig holds settings for the QA metrics baseline: the metrics
+// recorded in .release-agent/metrics.json on each release, and the check
+// comparing a release candidate with the previous release.
+type QABaselineConfig struct {
+	Enabled          bool    `yaml:"enabled"`            // record metrics on release and compare them in validate
+	MaxCoverageDrop  float64 `yaml:"max_coverage_drop"`  // allowed coverage drop in percentage points
+	MaxBuildIncrease float64 `yaml:"max_build_increase"` // allowed build time increase in percent
+	Fail             bool    `yaml:"fail"`               // fail instead of warn on a regression
+}
+
+// TagConfig holds settings for release tag creation.
+type TagConfig struct {
+	Sign     bool   `yaml:"sign"`     // create GPG/SSH-signed tags
+	Template string `yaml:"template"` // Go text/template for the tag annotation
+}
+
+// ReleaseConfig holds settings for the release workflow. Templates are Go
+// text/templates; the tag annotation uses TagConfig.Template.
+type ReleaseConfig struct {
+	PullRequest     bool   `yaml:"pull_request"`      // release through a PR instead of pushing (same as --pr)
+	AutoMerge       bool   `yaml:"auto_merge"`        // enable auto-merge / merge queue on the release PR (same as --auto-merge)
+	MergeMethod     string `yaml:"merge_method"`      // auto-merge method: merge, squash, or rebase
+	CommitTemplate  string `yaml:"commit_template"`   // release commit message
+	PRTitleTemplate string `yaml:"pr_title_template"` // release pull request title
+
+	Freeze    []FreezeWindow `yaml:"freeze"`    // times releases are blocked without --force
+	Approvals int            `yaml:"approvals"` // approvals by other maintainers required before tagging; 0 disables
+}
+
+// FreezeWindow is a period during which releases are blocked: either a
+// weekly recurring range such as "Fri 16:00-Mon 08:00", or a one-off range
+// of dates or times such as 2025-12-20 to 2026-01-02.
+type FreezeWindow struct {
+	Name     string `yaml:"name"`     // shown when the window blocks a release, e.g. "weekend"
+	Weekly   string `yaml:"weekly"`   // recurring range: "Fri 16:00-Mon 08:00"
+	Start    string `yaml:"start"`    // one-off start: YYYY-MM-DD or YYYY-MM-DD HH:MM
+	End      string `yaml:"end"`      // one-off end; a date alone includes the whole day
+	Timezone string `yaml:"timezone"` // IANA time zone, e.g. Europe/Berlin (default: local)
+}
+
+// BuildConfig holds settings for the release workflow step that
+// cross-compiles a Go command into versioned archives, for projects that
+// don't use goreleaser. The step is skipped when Main is empty.
+type BuildConfig struct {
+	Main    string   `yaml:"main"`    // package to build, e.g. "./cmd/tool"
+	Binary  string   `yaml:"binary"`  // binary name (default: base name of main)
+	Project string   `yaml:"project"` // archive name prefix (default: binary)
+	Targets []string `yaml:"targets"` // goos/goarch pairs (default: linux, darwin, windows on amd64/arm64)
+	LDFlags string   `yaml:"ldflags"` // -ldflags template with {{.Version}}, {{.Commit}}, {{.Date}}
+	Output  string   `yaml:"output"`  // archive directory (default: dist)
+	Files   []string `yaml:"files"`   // extra files to include besides README.md, LICENSE, CHANGELOG.md
+}
+
+// ArtifactsConfig holds settings for the release workflow step that
+// checksums built artifacts, attests their provenance, and uploads them to
+// the GitHub release. The step is skipped when Paths is empty and the build
+// step produced no archives.
+type ArtifactsConfig struct {
+	Paths         []string `yaml:"paths"`          // glob patterns for built artifacts (default: archives from the build step)
+	ChecksumsFile string   `yaml:"checksums_file"` // checksums file path (default: checksums.txt next to the artifacts)
+	Provenance    bool     `yaml:"provenance"`     // attest SLSA provenance with cosign when it is installed
+	CosignKey     string   `yaml:"cosign_key"`     // cosign signing key (empty = keyless)
+	Upload        bool     `yaml:"upload"`         // upload artifacts, checksums, and attestation to the GitHub release
+}
+
+// BenchmarkConfig holds settings for the benchmark regression check, which
+// is disabled by default because it runs benchmarks twice.
+type BenchmarkConfig struct {
+	Enabled   bool     `yaml:"enabled"`   // run benchmarks in check
+	Packages  []string `yaml:"packages"`  // package patterns to benchmark
+	Pattern   string   `yaml:"pattern"`   // -bench regexp
+	Count     int      `yaml:"count"`     // runs per benchmark
+	Threshold float64  `yaml:"threshold"` // minimum slowdown in percent to report
+	Fail      bool     `yaml:"fail"`      // fail instead of warn on regressions
 }
 
 // LanguageConfig holds settings for a specific language.
@@ -24,9 +406,15 @@ type LanguageConfig struct {
 	Lint     *bool    `yaml:"lint"`     // run linter
 	Format   *bool    `yaml:"format"`   // check formatting
 	Coverage *bool    `yaml:"coverage"` // show coverage
+	Exclude  []string `yaml:"exclude"`  // paths not checked, e.g. examples/** or tools
 
 	// Go-specific
-	ExcludeCoverage string `yaml:"exclude_coverage"` // directories to exclude from coverage
+	ExcludeCoverage string            `yaml:"exclude_coverage"` // directories to exclude from coverage
+	BuildFlags      []string          `yaml:"build_flags"`      // flags for every go command, e.g. -tags=integration
+	TestFlags       []string          `yaml:"test_flags"`       // flags for go test, e.g. -short or -run=Unit
+	Env             map[string]string `yaml:"env"`              // environment for go commands, e.g. CGO_ENABLED
+	AllowReplace    []string          `yaml:"allow_replace"`    // local replace targets or modules allowed in go.mod, e.g. ../shared
+	TestShards      int               `yaml:"test_shards"`      // parallel go test processes, splitting the packages; 0 or 1 for one
 }
 
 // DefaultConfig returns a configuration with sensible defaults.
@@ -34,25 +422,87 @@ func DefaultConfig() Config {
 	return Config{
 		Verbose:   false,
 		Languages: make(map[string]LanguageConfig),
+		CoverageDiff: CoverageDiffConfig{
+			MaxDrop: 1.0,
+		},
+		QABaseline: QABaselineConfig{
+			MaxCoverageDrop:  1.0,
+			MaxBuildIncrease: 25,
+		},
+		Benchmarks: BenchmarkConfig{
+			Packages:  []string{"./..."},
+			Pattern:   ".",
+			Count:     5,
+			Threshold: 5,
+		},
+		Roadmap: RoadmapConfig{
+			IssueLabel: "roadmap",
+		},
+		Artifacts: ArtifactsConfig{
+			Provenance: true,
+			Upload:     true,
+		},
+		Detect: DetectConfig{
+			Cache: true,
+		},
+		Checks: ChecksConfig{
+			MaxLines:     50,
+			FlakyRetries: 2,
+		},
+		History: true,
+	}
+}
+
+// FileNames are the configuration file names, in order of precedence.
+var FileNames = []string{".releaseagent.yaml", ".releaseagent.yml"}
+
+// Exists reports whether dir contains a configuration file.
+func Exists(dir string) bool {
+	for _, name := range FileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadNearest loads the configuration from dir or, if it has none, from the
+// closest parent directory up to and including root that does. Returns the
+// default config if none is found.
+func LoadNearest(dir, root string) (Config, error) {
+	dir, root = filepath.Clean(dir), filepath.Clean(root)
+	for d := dir; ; d = filepath.Dir(d) {
+		if Exists(d) {
+			return Load(d)
+		}
+		rel, err := filepath.Rel(root, d)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") || d == filepath.Dir(d) {
+			break
+		}
 	}
+	return Load(root)
 }
 
-// Load reads configuration from .releaseagent.yaml in the given directory.
-// Returns default config if file doesn't exist.
+// Load reads configuration from .releaseagent.yaml in the given directory,
+// on top of the policy bundle it extends, if any. Returns default config if
+// file doesn't exist. If the policy bundle is unavailable, it returns the
+// config without it and an ErrPolicy error.
 func Load(dir string) (Config, error) {
 	cfg := DefaultConfig()
 
 	// Try multiple config file names
-	configFiles := []string{
-		dir + "/.releaseagent.yaml",
-		dir + "/.releaseagent.yml",
+	var configFiles []string
+	for _, name := range FileNames {
+		configFiles = append(configFiles, dir+"/"+name)
 	}
 
 	var data []byte
+	var path string
 	var err error
 	for _, f := range configFiles {
 		data, err = os.ReadFile(f)
 		if err == nil {
+			path = f
 			break
 		}
 	}
@@ -62,11 +512,66 @@ func Load(dir string) (Config, error) {
 		return cfg, nil
 	}
 
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return cfg, err
+	// Without its policy, the file still applies, so callers that carry on
+	// after the error keep the repository's own settings
+	policyErr := decode(&cfg, path, data, map[string]bool{})
+	if policyErr != nil && !errors.Is(policyErr, ErrPolicy) {
+		return cfg, policyErr
 	}
 
-	return cfg, nil
+	if err := cfg.Validate.check(); err != nil {
+		return cfg, fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+	if err := cfg.Report.check(); err != nil {
+		return cfg, fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+	if err := cfg.Checks.check(); err != nil {
+		return cfg, fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+
+	// A relative CA bundle is relative to the repository, not the caller
+	if ca := cfg.Network.CABundle; ca != "" && !filepath.IsAbs(ca) {
+		cfg.Network.CABundle = filepath.Join(dir, ca)
+	}
+
+	return cfg, policyErr
+}
+
+// check reports custom areas without a name or checks, or depending on
+// an area that isn't defined before them, and areas in Areas that are
+// neither built in, registered, nor custom.
+func (v ValidateConfig) check() error {
+	known := slices.Clone(ValidateAreas)
+	for i, a := range v.Custom {
+		if a.Key() == "" {
+			return fmt.Errorf("validate.custom[%d]: missing name", i)
+		}
+		if slices.Contains(known, a.Key()) {
+			return fmt.Errorf("validate.custom[%d]: area %q already exists", i, a.Name)
+		}
+		for _, d := range a.DependsOn {
+			// The release area depends on every area, so it can't come first
+			if !slices.Contains(known, d) || d == "release" {
+				return fmt.Errorf("validate.custom[%d]: area %q can't depend on %q", i, a.Name, d)
+			}
+		}
+		if len(a.Checks) == 0 {
+			return fmt.Errorf("validate.custom[%d]: area %q has no checks", i, a.Name)
+		}
+		for j, c := range a.Checks {
+			if c.Name == "" || len(c.Command) == 0 {
+				return fmt.Errorf("validate.custom[%d].checks[%d]: name and command are required", i, j)
+			}
+		}
+		known = append(known, a.Key())
+	}
+	for area := range v.Areas {
+		if !slices.Contains(known, area) {
+			return fmt.Errorf("unknown validation area %q in validate.areas (expected one of %s)",
+				area, strings.Join(known, ", "))
+		}
+	}
+	return nil
 }
 
 // IsLanguageEnabled checks if a language is enabled in config.
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -57,6 +59,345 @@ languages:
 	}
 }
 
+func TestLoad_CoverageDiff(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.CoverageDiff.MaxDrop != 1.0 || cfg.CoverageDiff.Fail {
+		t.Errorf("unexpected default coverage_diff: %+v", cfg.CoverageDiff)
+	}
+
+	configContent := `
+coverage_diff:
+  fail: true
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.CoverageDiff.MaxDrop != 1.0 {
+		t.Errorf("expected max_drop default to be kept, got %v", cfg.CoverageDiff.MaxDrop)
+	}
+	if !cfg.CoverageDiff.Fail {
+		t.Error("expected coverage_diff.fail to be true")
+	}
+}
+
+func TestLoad_Benchmarks(t *testing.T) {
+	dir := t.TempDir()
+
+	configContent := `
+benchmarks:
+  enabled: true
+  packages: ["./pkg/parser"]
+  threshold: 10
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	b := cfg.Benchmarks
+	if !b.Enabled || len(b.Packages) != 1 || b.Packages[0] != "./pkg/parser" || b.Threshold != 10 {
+		t.Errorf("unexpected benchmarks config: %+v", b)
+	}
+	if b.Count != 5 || b.Pattern != "." {
+		t.Errorf("expected count and pattern defaults to be kept, got %+v", b)
+	}
+}
+
+func TestLoad_Roadmap(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("roadmap:\n  sync_issues: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Roadmap.SyncIssues {
+		t.Error("expected roadmap.sync_issues to be true")
+	}
+	if cfg.Roadmap.IssueLabel != "roadmap" {
+		t.Errorf("expected issue_label default to be kept, got %q", cfg.Roadmap.IssueLabel)
+	}
+}
+
+func TestLoad_Validate(t *testing.T) {
+	dir := t.TempDir()
+	content := `validate:
+  areas:
+    pm:
+      enabled: false
+      reason: library without a roadmap
+    documentation:
+      skip:
+        - id: docs.prd
+          reason: no PRDs for libraries
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if pm := cfg.Validate.Areas["pm"]; pm.IsEnabled() || pm.Reason != "library without a roadmap" {
+		t.Errorf("pm = %+v, want disabled with reason", pm)
+	}
+	docs := cfg.Validate.Areas["documentation"]
+	if !docs.IsEnabled() || len(docs.Skip) != 1 || docs.Skip[0].ID != "docs.prd" {
+		t.Errorf("documentation = %+v, want enabled skipping docs.prd", docs)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("validate:\n  areas:\n    docs: {enabled: false}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); !errors.Is(err, ErrInvalid) || !strings.Contains(err.Error(), `unknown validation area "docs"`) {
+		t.Errorf("Load() error = %v, want unknown area", err)
+	}
+}
+
+func TestLoad_ValidateCustom(t *testing.T) {
+	dir := t.TempDir()
+	content := `validate:
+  custom:
+    - name: Legal Review
+      checks:
+        - name: license headers
+          command: [./scripts/check-headers.sh, --all]
+    - name: Localization
+      depends_on: [legal_review]
+      checks:
+        - name: translations
+          command: [make, i18n-check]
+          warning: true
+  areas:
+    localization:
+      skip:
+        - id: localization.translations
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	custom := cfg.Validate.Custom
+	if len(custom) != 2 || custom[0].Key() != "legal_review" || custom[1].DependsOn[0] != "legal_review" ||
+		!custom[1].Checks[0].Warning || len(custom[0].Checks[0].Command) != 2 {
+		t.Errorf("custom = %+v", custom)
+	}
+
+	invalid := map[string]string{
+		"no checks":    "validate:\n  custom:\n    - name: Legal\n",
+		"duplicate":    "validate:\n  custom:\n    - name: QA\n      checks: [{name: x, command: [true]}]\n",
+		"no command":   "validate:\n  custom:\n    - name: Legal\n      checks: [{name: x}]\n",
+		"later dep":    "validate:\n  custom:\n    - name: Legal\n      depends_on: [l10n]\n      checks: [{name: x, command: [true]}]\n    - name: L10n\n      checks: [{name: x, command: [true]}]\n",
+		"release dep":  "validate:\n  custom:\n    - name: Legal\n      depends_on: [release]\n      checks: [{name: x, command: [true]}]\n",
+		"unknown area": "validate:\n  areas:\n    legal: {enabled: false}\n",
+	}
+	for name, content := range invalid {
+		if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Load() error = %v, want ErrInvalid", name, err)
+		}
+	}
+}
+
+func TestLoad_Report(t *testing.T) {
+	dir := t.TempDir()
+	content := `report:
+  vocabulary: pass_fail
+  labels:
+    no_go: REJECTED
+  messages:
+    go: "Release {{.Version}} approved"
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if r := cfg.Report; r.Vocabulary != "pass_fail" || r.Labels.NoGo != "REJECTED" || r.Messages.Go != "Release {{.Version}} approved" {
+		t.Errorf("report = %+v", r)
+	}
+
+	invalid := map[string]string{
+		"vocabulary": "report:\n  vocabulary: nasa\n",
+		"template":   "report:\n  messages:\n    no_go: \"Blocked by {{.Blocking\"\n",
+	}
+	for name, content := range invalid {
+		if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Load() error = %v, want ErrInvalid", name, err)
+		}
+	}
+}
+
+func TestLoad_Flaky(t *testing.T) {
+	dir := t.TempDir()
+	content := `checks:
+  flaky:
+    - id: go.test
+      test: TestUpload*
+      issue: https://github.com/org/repo/issues/12
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if c := cfg.Checks; len(c.Flaky) != 1 || c.Flaky[0].Test != "TestUpload*" || c.FlakyRetries != 2 {
+		t.Errorf("checks = %+v", c)
+	}
+
+	invalid := map[string]string{
+		"no test": "checks:\n  flaky:\n    - id: go.test\n",
+		"retries": "checks:\n  flaky_retries: -1\n",
+	}
+	for name, content := range invalid {
+		if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Load() error = %v, want ErrInvalid", name, err)
+		}
+	}
+}
+
+func TestAreaKey(t *testing.T) {
+	for name, want := range map[string]string{"PM": "pm", "Documentation": "documentation", "Legal Review": "legal_review", " i18n/L10n ": "i18n_l10n"} {
+		if got := AreaKey(name); got != want {
+			t.Errorf("AreaKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLoad_Release(t *testing.T) {
+	dir := t.TempDir()
+	configContent := `
+release:
+  pull_request: true
+  auto_merge: true
+  merge_method: squash
+  commit_template: "release: {{.Version}} ({{.Date}})"
+  pr_title_template: "chore: release {{.Version}}"
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Release.PullRequest || !cfg.Release.AutoMerge || cfg.Release.MergeMethod != "squash" || cfg.Release.CommitTemplate != "release: {{.Version}} ({{.Date}})" || cfg.Release.PRTitleTemplate != "chore: release {{.Version}}" {
+		t.Errorf("unexpected release config: %+v", cfg.Release)
+	}
+}
+
+func TestLoad_Build(t *testing.T) {
+	dir := t.TempDir()
+	configContent := `
+build:
+  main: ./cmd/tool
+  targets: [linux/amd64, darwin/arm64]
+  ldflags: "-s -w -X main.version={{.Version}}"
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	b := cfg.Build
+	if b.Main != "./cmd/tool" || len(b.Targets) != 2 || b.LDFlags != "-s -w -X main.version={{.Version}}" {
+		t.Errorf("unexpected build config: %+v", b)
+	}
+}
+
+func TestLoad_Artifacts(t *testing.T) {
+	dir := t.TempDir()
+	configContent := `
+artifacts:
+  paths: ["dist/*.tar.gz", "dist/*.zip"]
+  provenance: false
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	a := cfg.Artifacts
+	if len(a.Paths) != 2 || a.Provenance || !a.Upload {
+		t.Errorf("unexpected artifacts config: %+v", a)
+	}
+}
+
+func TestLoad_ReadmeBadges(t *testing.T) {
+	dir := t.TempDir()
+	configContent := `
+readme:
+  disable_default_badges: true
+  image: example/project
+  badges:
+    - name: Helm chart
+      pattern: '(version: )\d+\.\d+\.\d+'
+      replace: '${1}{{semver}}'
+`
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	r := cfg.Readme
+	if !r.DisableDefaultBadges || r.Image != "example/project" || len(r.Badges) != 1 || r.Badges[0].Name != "Helm chart" || r.Badges[0].Replace != "${1}{{semver}}" {
+		t.Errorf("unexpected readme config: %+v", r)
+	}
+}
+
+func TestLoad_InvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("verbose: [unclosed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(dir)
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("Load() error = %v, want ErrInvalid", err)
+	}
+}
+
 func TestLoad_AlternateNames(t *testing.T) {
 	names := []string{".releaseagent.yaml", ".releaseagent.yml"}
 
@@ -80,6 +421,46 @@ func TestLoad_AlternateNames(t *testing.T) {
 	}
 }
 
+func TestLoadNearest(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".releaseagent.yaml"), []byte("verbose: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadNearest(sub, root)
+	if err != nil {
+		t.Fatalf("LoadNearest failed: %v", err)
+	}
+	if !cfg.Verbose {
+		t.Error("expected root config to apply to sub directory")
+	}
+
+	if err := os.WriteFile(filepath.Join(sub, ".releaseagent.yml"), []byte("stash: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadNearest(sub, root)
+	if err != nil {
+		t.Fatalf("LoadNearest failed: %v", err)
+	}
+	if cfg.Verbose || !cfg.Stash {
+		t.Errorf("expected sub directory config, got verbose=%v stash=%v", cfg.Verbose, cfg.Stash)
+	}
+
+	// Relative paths
+	t.Chdir(root)
+	cfg, err = LoadNearest(filepath.Join("services", "web"), ".")
+	if err != nil {
+		t.Fatalf("LoadNearest failed: %v", err)
+	}
+	if !cfg.Verbose {
+		t.Error("expected root config to apply to relative sub directory")
+	}
+}
+
 func TestIsLanguageEnabled(t *testing.T) {
 	cfg := DefaultConfig()
 
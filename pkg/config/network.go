@@ -0,0 +1,119 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// NetworkConfig holds settings for the HTTP clients atrelease uses directly,
+// for networks that require a proxy or a private CA. Calls made through git
+// and gh use their own configuration.
+type NetworkConfig struct {
+	Proxy     string   `yaml:"proxy"`      // proxy URL for HTTP(S) requests (default: HTTPS_PROXY / HTTP_PROXY)
+	NoProxy   []string `yaml:"no_proxy"`   // hosts reached directly, e.g. .corp.example.com; added to NO_PROXY
+	CABundle  string   `yaml:"ca_bundle"`  // PEM file of CA certificates trusted besides the system roots
+	GitHubURL string   `yaml:"github_url"` // GitHub Enterprise base URL, e.g. https://github.example.com (default: github.com)
+}
+
+// DefaultGitHubURL is the GitHub base URL used when GitHubURL is empty.
+const DefaultGitHubURL = "https://github.com"
+
+// GitHubBaseURL returns the GitHub web URL without a trailing slash.
+func (n NetworkConfig) GitHubBaseURL() string {
+	if n.GitHubURL == "" {
+		return DefaultGitHubURL
+	}
+	return strings.TrimSuffix(n.GitHubURL, "/")
+}
+
+// GitHubAPIURL returns the REST API URL for GitHubBaseURL:
+// https://api.github.com for github.com and <base>/api/v3 for GitHub
+// Enterprise Server.
+func (n NetworkConfig) GitHubAPIURL() string {
+	base := n.GitHubBaseURL()
+	if base == DefaultGitHubURL {
+		return "https://api.github.com"
+	}
+	return base + "/api/v3"
+}
+
+// HTTPClient returns a client with the given timeout that uses the
+// configured proxy and CA bundle. Without either, it behaves like a client
+// with the default transport: HTTPS_PROXY, HTTP_PROXY, and NO_PROXY apply.
+func (n NetworkConfig) HTTPClient(timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = n.proxyFunc()
+
+	if n.CABundle != "" {
+		pem, err := os.ReadFile(n.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading network.ca_bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("network.ca_bundle %s contains no PEM certificates", n.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// proxyFunc returns the transport proxy function: no proxy for hosts
+// matching NoProxy or NO_PROXY, otherwise Proxy or the environment.
+func (n NetworkConfig) proxyFunc() func(*http.Request) (*url.URL, error) {
+	noProxy := append([]string(nil), n.NoProxy...)
+	for _, env := range []string{"NO_PROXY", "no_proxy"} {
+		noProxy = append(noProxy, strings.Split(os.Getenv(env), ",")...)
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(req.URL.Hostname(), noProxy) {
+			return nil, nil
+		}
+		if n.Proxy == "" {
+			return http.ProxyFromEnvironment(req)
+		}
+		u, err := url.Parse(n.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network.proxy: %w", err)
+		}
+		return u, nil
+	}
+}
+
+// matchNoProxy reports whether host matches a NO_PROXY style pattern: "*",
+// an IP address or CIDR range, a host name, or a domain with or without a
+// leading dot, which also matches its subdomains.
+func matchNoProxy(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		switch {
+		case p == "":
+			continue
+		case p == "*":
+			return true
+		case strings.Contains(p, "/"):
+			if _, cidr, err := net.ParseCIDR(p); err == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(p, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
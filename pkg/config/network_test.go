@@ -0,0 +1,89 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNetworkConfig_GitHubAPIURL(t *testing.T) {
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"", "https://api.github.com"},
+		{"https://github.com/", "https://api.github.com"},
+		{"https://github.example.com/", "https://github.example.com/api/v3"},
+	}
+	for _, tt := range tests {
+		if got := (NetworkConfig{GitHubURL: tt.base}).GitHubAPIURL(); got != tt.want {
+			t.Errorf("GitHubAPIURL(%q) = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkConfig_Proxy(t *testing.T) {
+	t.Setenv("NO_PROXY", "localhost")
+	t.Setenv("no_proxy", "")
+	n := NetworkConfig{Proxy: "http://proxy.corp:3128", NoProxy: []string{".internal.corp", "10.0.0.0/8"}}
+	client, err := n.HTTPClient(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := client.Transport.(*http.Transport).Proxy
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.github.com/repos", "http://proxy.corp:3128"},
+		{"https://git.internal.corp/api", ""},
+		{"https://internal.corp/api", ""},
+		{"http://10.1.2.3:8080/run", ""},
+		{"http://localhost:8080/run", ""},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+		u, err := proxy(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		if u != nil {
+			got = u.String()
+		}
+		if got != tt.want {
+			t.Errorf("proxy(%s) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkConfig_CABundle(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (NetworkConfig{CABundle: bundle}).HTTPClient(time.Second); err == nil {
+		t.Error("expected an error for a bundle without certificates")
+	}
+	if _, err := (NetworkConfig{CABundle: filepath.Join(dir, "missing.pem")}).HTTPClient(time.Second); err == nil {
+		t.Error("expected an error for a missing bundle")
+	}
+}
+
+func TestLoad_NetworkCABundleRelative(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("network:\n  ca_bundle: certs/ca.pem\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "certs", "ca.pem"); cfg.Network.CABundle != want {
+		t.Errorf("CABundle = %q, want %q", cfg.Network.CABundle, want)
+	}
+}
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// PolicyTTL is how long a fetched policy bundle is reused before it is
+// fetched again, so moving refs like v1 pick up changes. A stale copy is
+// still used when fetching fails.
+const PolicyTTL = 24 * time.Hour
+
+// ErrPolicy is returned by Load when the policy bundle a config file
+// extends can't be fetched or read. The returned config then has the file
+// applied on top of the defaults, without the policy.
+var ErrPolicy = errors.New("policy bundle unavailable")
+
+// offline makes fetchPolicy use only cached bundles.
+var offline bool
+
+// SetOffline sets whether policy bundles may be fetched. Offline, cached
+// bundles are used however old they are.
+func SetOffline(b bool) {
+	offline = b
+}
+
+// policyURL returns the git URL of a policy repository, e.g.
+// "github.com/org/policy". Tests replace it.
+var policyURL = func(repo string) string {
+	return "https://" + repo + ".git"
+}
+
+// decode applies the config file at path, containing data, to cfg after
+// the policy it extends. seen holds the files already applied, to detect
+// cycles. If the policy can't be resolved or read, the file is still
+// applied on its own and an ErrPolicy error is returned.
+func decode(cfg *Config, path string, data []byte, seen map[string]bool) error {
+	var head struct {
+		Extends string `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal(data, &head); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+
+	var policyErr error
+	if head.Extends != "" {
+		base, err := ResolvePolicy(head.Extends, filepath.Dir(path))
+		var baseData []byte
+		if err == nil {
+			if seen[base] {
+				return fmt.Errorf("%w: %s: extends cycle through %s", ErrInvalid, path, base)
+			}
+			seen[base] = true
+			baseData, err = os.ReadFile(base)
+		}
+		if err != nil {
+			policyErr = fmt.Errorf("%w: %s: extends %s: %v", ErrPolicy, path, head.Extends, err)
+		} else if err := decode(cfg, base, baseData, seen); err != nil {
+			if !errors.Is(err, ErrPolicy) {
+				return err
+			}
+			policyErr = err
+		}
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalid, path, err)
+	}
+	return policyErr
+}
+
+// ResolvePolicy returns the config file of the policy bundle ref, as
+// written in extends. A ref starting with "." or "/" is a config file, or
+// a directory containing one, relative to dir. Any other ref is a git
+// repository and version, with an optional directory inside it, such as
+// "github.com/org/policy@v1" or "github.com/org/policies/go@v2.3.0"; it
+// is fetched into the user cache directory.
+func ResolvePolicy(ref, dir string) (string, error) {
+	if strings.HasPrefix(ref, ".") || filepath.IsAbs(ref) {
+		path := ref
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return policyFile(path)
+	}
+
+	module, version, ok := strings.Cut(ref, "@")
+	if !ok || version == "" {
+		return "", fmt.Errorf("policy %s must pin a version, e.g. %s@v1", ref, ref)
+	}
+	parts := strings.Split(module, "/")
+	if len(parts) < 3 {
+		return "", fmt.Errorf("policy %s is not a repository path like github.com/org/policy", ref)
+	}
+	repo, sub := strings.Join(parts[:3], "/"), filepath.Join(parts[3:]...)
+
+	checkout, err := fetchPolicy(repo, version)
+	if err != nil {
+		return "", err
+	}
+	return policyFile(filepath.Join(checkout, sub))
+}
+
+// policyFile returns path if it is a file, or the config file in the
+// directory path.
+func policyFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return filepath.Abs(path)
+	}
+	for _, name := range FileNames {
+		if f := filepath.Join(path, name); fileExists(f) {
+			return filepath.Abs(f)
+		}
+	}
+	return "", fmt.Errorf("no %s in %s", FileNames[0], path)
+}
+
+// fetchPolicy returns a checkout of version of repo in the cache, cloning
+// it if it is missing or older than PolicyTTL.
+func fetchPolicy(repo, version string) (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	checkout := filepath.Join(cache, "atrelease", "policies", filepath.FromSlash(repo)+"@"+version)
+	info, statErr := os.Stat(checkout)
+	if statErr == nil && (offline || time.Since(info.ModTime()) < PolicyTTL) {
+		return checkout, nil
+	}
+	if offline {
+		return "", fmt.Errorf("policy %s@%s isn't cached; fetch it once online", repo, version)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(checkout), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(checkout), ".fetch-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	cmd := proc.Command("git", "clone", "--quiet", "--depth", "1", "--branch", version, policyURL(repo), tmp)
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if statErr == nil {
+			// Offline or unreachable: keep using the cached copy
+			return checkout, nil
+		}
+		return "", fmt.Errorf("fetching policy %s@%s: %s", repo, version, strings.TrimSpace(string(output)))
+	}
+	if err := os.RemoveAll(checkout); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, checkout); err != nil {
+		return "", err
+	}
+	return checkout, nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
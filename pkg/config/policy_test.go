@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testPolicy = `
+strict: true
+languages:
+  go:
+    lint: false
+checks:
+  skip: [go.bench]
+  waivers:
+    - id: go.golangci_lint
+      reason: org-wide
+`
+
+func TestLoad_ExtendsLocal(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "policy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "policy", ".releaseagent.yaml"), []byte(testPolicy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "repo")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	local := "extends: ../policy\nverbose: true\nchecks:\n  skip: [ts.*]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(local), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Strict || !cfg.Verbose {
+		t.Errorf("strict = %v, verbose = %v; want both from policy and local file", cfg.Strict, cfg.Verbose)
+	}
+	if lc := cfg.GetLanguageConfig("go"); *lc.Lint {
+		t.Error("expected lint disabled by the policy")
+	}
+	if len(cfg.Checks.Skip) != 1 || cfg.Checks.Skip[0] != "ts.*" {
+		t.Errorf("Checks.Skip = %v, want local [ts.*]", cfg.Checks.Skip)
+	}
+	if len(cfg.Checks.Waivers) != 1 || cfg.Checks.Waivers[0].Reason != "org-wide" {
+		t.Errorf("Checks.Waivers = %v, want the policy waiver", cfg.Checks.Waivers)
+	}
+	if cfg.Checks.MaxLines != 50 {
+		t.Errorf("Checks.MaxLines = %d, want default 50", cfg.Checks.MaxLines)
+	}
+}
+
+func TestLoad_ExtendsCycle(t *testing.T) {
+	root := t.TempDir()
+	for name, extends := range map[string]string{"a.yaml": "./b.yaml", "b.yaml": "./a.yaml"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte("extends: "+extends+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, ".releaseagent.yaml"), []byte("extends: ./a.yaml\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(root)
+	if !errors.Is(err, ErrInvalid) {
+		t.Errorf("Load() error = %v, want ErrInvalid for a cycle", err)
+	}
+}
+
+func TestResolvePolicy_Errors(t *testing.T) {
+	tests := []string{
+		"github.com/org/policy",    // no version
+		"github.com/policy@v1",     // not a repository path
+		"./missing",                // no such file
+		"github.com/org/policy@v1", // unreachable
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func(orig func(string) string) { policyURL = orig }(policyURL)
+	policyURL = func(repo string) string { return filepath.Join(t.TempDir(), "missing") }
+
+	for _, ref := range tests {
+		if _, err := ResolvePolicy(ref, t.TempDir()); err == nil {
+			t.Errorf("ResolvePolicy(%q) succeeded, want error", ref)
+		}
+	}
+}
+
+func TestResolvePolicy_Remote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "go"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "go", ".releaseagent.yaml"), []byte(testPolicy), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "policy"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+	defer func(orig func(string) string) { policyURL = orig }(policyURL)
+	policyURL = func(string) string { return repo }
+
+	path, err := ResolvePolicy("github.com/org/policy/go@v1", t.TempDir())
+	if err != nil {
+		t.Fatalf("ResolvePolicy failed: %v", err)
+	}
+	if filepath.Base(path) != ".releaseagent.yaml" || filepath.Base(filepath.Dir(path)) != "go" {
+		t.Errorf("ResolvePolicy() = %s, want go/.releaseagent.yaml in the checkout", path)
+	}
+
+	// A stale cached copy is used when the repository is unreachable
+	checkout := filepath.Join(cache, "atrelease", "policies", "github.com", "org", "policy@v1")
+	old := time.Now().Add(-2 * PolicyTTL)
+	if err := os.Chtimes(checkout, old, old); err != nil {
+		t.Fatal(err)
+	}
+	policyURL = func(string) string { return filepath.Join(repo, "missing") }
+	if _, err := ResolvePolicy("github.com/org/policy/go@v1", t.TempDir()); err != nil {
+		t.Errorf("ResolvePolicy with cache failed: %v", err)
+	}
+}
+
+func TestLoad_ExtendsUnreachable(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func(orig func(string) string) { policyURL = orig }(policyURL)
+	policyURL = func(string) string { return filepath.Join(t.TempDir(), "missing") }
+
+	dir := t.TempDir()
+	local := "extends: github.com/org/policy@v1\nverbose: true\n"
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(local), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if !errors.Is(err, ErrPolicy) {
+		t.Errorf("Load() error = %v, want ErrPolicy", err)
+	}
+	if !cfg.Verbose {
+		t.Error("expected the local file applied without its policy")
+	}
+}
@@ -0,0 +1,38 @@
+package config
+
+import "os"
+
+// TelemetryConfig exports each validation report to monitoring systems,
+// so release readiness can be graphed over time across repositories.
+type TelemetryConfig struct {
+	OTLP        OTLPConfig        `yaml:"otlp"`        // OpenTelemetry traces and metrics
+	Pushgateway PushgatewayConfig `yaml:"pushgateway"` // Prometheus metrics
+}
+
+// Enabled reports whether any exporter is configured.
+func (t TelemetryConfig) Enabled() bool {
+	return t.OTLP.Endpoint != "" || t.Pushgateway.URL != ""
+}
+
+// OTLPConfig sends traces and metrics to an OpenTelemetry collector over
+// OTLP/HTTP with JSON encoding.
+type OTLPConfig struct {
+	Endpoint string            `yaml:"endpoint"` // base URL, e.g. http://localhost:4318; /v1/traces and /v1/metrics are appended
+	Headers  map[string]string `yaml:"headers"`  // sent with each request, e.g. Authorization; ${VAR} is expanded from the environment
+}
+
+// ExpandedHeaders returns Headers with environment variables expanded, so
+// tokens can stay out of the config file.
+func (o OTLPConfig) ExpandedHeaders() map[string]string {
+	headers := make(map[string]string, len(o.Headers))
+	for k, v := range o.Headers {
+		headers[k] = os.ExpandEnv(v)
+	}
+	return headers
+}
+
+// PushgatewayConfig pushes Prometheus metrics to a Pushgateway.
+type PushgatewayConfig struct {
+	URL string `yaml:"url"` // e.g. http://pushgateway:9091
+	Job string `yaml:"job"` // job label of the pushed group (default: atrelease)
+}
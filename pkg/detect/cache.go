@@ -0,0 +1,108 @@
+package detect
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// cacheVersion is bumped when the cache format or detection rules change,
+// so stale entries are ignored.
+const cacheVersion = 1
+
+// DefaultCacheDir returns the user cache directory for detection results,
+// or "" if there is none.
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "atrelease", "detect")
+}
+
+// stamp identifies a version of a file or directory. A directory's
+// modification time changes whenever an entry is added, removed, or
+// renamed in it, so stamping every walked directory and indicator file
+// detects any change that could alter the results.
+type stamp struct {
+	ModTime int64 `json:"mtime"`
+	Size    int64 `json:"size"`
+}
+
+func stampOf(info os.FileInfo) stamp {
+	return stamp{ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+}
+
+// cacheEntry is the on-disk cache for one scanned directory.
+type cacheEntry struct {
+	Version    int              `json:"version"`
+	Stamps     map[string]stamp `json:"stamps"` // Walked directories and indicator files
+	Detections []Detection      `json:"detections"`
+	Workspaces []Workspace      `json:"workspaces"`
+}
+
+// valid reports whether none of the stamped paths have changed.
+func (e cacheEntry) valid() bool {
+	if e.Version != cacheVersion || len(e.Stamps) == 0 {
+		return false
+	}
+	for path, s := range e.Stamps {
+		info, err := os.Stat(path)
+		if err != nil || stampOf(info) != s {
+			return false
+		}
+	}
+	return true
+}
+
+// cachePath returns the cache file for scanning dir with opts. The key
+// includes dir as given, since detection paths are reported relative to it.
+func cachePath(dir string, opts Options) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	key, err := json.Marshal(struct {
+		Abs, Dir string
+		Opts     Options
+	}{abs, dir, opts})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(key)
+	return filepath.Join(opts.CacheDir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// loadCache returns the cached results for scanning dir with opts, if they
+// are still valid.
+func loadCache(dir string, opts Options) (cacheEntry, bool) {
+	var entry cacheEntry
+	path := cachePath(dir, opts)
+	if path == "" {
+		return entry, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &entry) != nil {
+		return entry, false
+	}
+	return entry, entry.valid()
+}
+
+// saveCache writes the results of scanning dir with opts. Failures are
+// ignored; the next run scans again.
+func saveCache(dir string, opts Options, entry cacheEntry) {
+	path := cachePath(dir, opts)
+	if path == "" {
+		return
+	}
+	entry.Version = cacheVersion
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(opts.CacheDir, 0755); err == nil {
+		_ = os.WriteFile(path, data, 0600)
+	}
+}
@@ -0,0 +1,112 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScan_Cache(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{CacheDir: t.TempDir()}
+	writeTree(t, dir, []string{"go.mod", "web/package.json", "tools/cli/README.md"})
+
+	first, _, err := Scan(dir, opts)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	entries, err := os.ReadDir(opts.CacheDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %v (%v)", entries, err)
+	}
+	if _, ok := loadCache(dir, opts); !ok {
+		t.Fatal("expected a valid cache entry after Scan")
+	}
+	second, _, err := Scan(dir, opts)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached Scan = %+v, want %+v", second, first)
+	}
+
+	// A new indicator file in an existing directory changes its mtime
+	writeTree(t, dir, []string{"tools/cli/go.mod"})
+	touch(t, filepath.Join(dir, "tools", "cli"))
+	if _, ok := loadCache(dir, opts); ok {
+		t.Error("expected new go.mod to invalidate the cache")
+	}
+	detections, _, err := Scan(dir, opts)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(detections) != len(first)+1 {
+		t.Errorf("expected %d detections after adding go.mod, got %+v", len(first)+1, detections)
+	}
+
+	// Editing an indicator file in place invalidates it too
+	manifest := filepath.Join(dir, "web", "package.json")
+	if err := os.WriteFile(manifest, []byte(`{"workspaces": ["pkgs/*"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	touch(t, manifest)
+	if _, ok := loadCache(dir, opts); ok {
+		t.Error("expected edited package.json to invalidate the cache")
+	}
+	_, workspaces, err := Scan(dir, opts)
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(workspaces) != 1 {
+		t.Errorf("expected 1 workspace after editing package.json, got %+v", workspaces)
+	}
+}
+
+func TestScan_CacheDisabled(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, []string{"go.mod"})
+
+	if _, _, err := Scan(dir, Options{}); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if _, ok := loadCache(dir, Options{}); ok {
+		t.Error("expected no cache entry without a CacheDir")
+	}
+}
+
+func TestCachePath_Options(t *testing.T) {
+	cacheDir := t.TempDir()
+	a := cachePath("repo", Options{CacheDir: cacheDir})
+	b := cachePath("repo", Options{CacheDir: cacheDir, MaxDepth: 2})
+	if a == "" || a == b {
+		t.Errorf("expected distinct cache paths for different options, got %q and %q", a, b)
+	}
+}
+
+// touch moves a path's modification time forward, so the change is seen
+// even on file systems with coarse timestamps.
+func touch(t *testing.T, path string) {
+	t.Helper()
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectCached(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{CacheDir: t.TempDir()}
+	writeTree(t, dir, []string{"go.mod"})
+
+	for i, want := range []bool{false, true} {
+		detections, cached, err := DetectCached(dir, opts)
+		if err != nil {
+			t.Fatalf("DetectCached failed: %v", err)
+		}
+		if cached != want || len(detections) != 1 {
+			t.Errorf("run %d: cached = %v with %d detections, want %v with 1", i, cached, len(detections), want)
+		}
+	}
+}
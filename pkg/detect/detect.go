@@ -2,8 +2,9 @@
 package detect
 
 import (
-	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 // Language represents a detected programming language.
@@ -20,92 +21,177 @@ const (
 
 // Detection holds information about a detected language.
 type Detection struct {
-	Language Language
-	Path     string   // Directory where detected
-	Files    []string // Indicator files found
+	Language  Language `json:"language" toon:"language"`
+	Path      string   `json:"path" toon:"path"`                               // Directory where detected
+	Files     []string `json:"files" toon:"files"`                             // Indicator files found
+	Workspace string   `json:"workspace,omitempty" toon:"workspace,omitempty"` // Path of the workspace it is a member of
+	Toolchain string   `json:"toolchain,omitempty" toon:"toolchain,omitempty"` // Toolchain version, set by ResolveToolchains
 }
 
-// Detect scans a directory and returns all detected languages.
-func Detect(dir string) ([]Detection, error) {
-	var detections []Detection
+// Options limits which directories are scanned.
+type Options struct {
+	// Exclude lists patterns of paths to skip, relative to the scanned
+	// directory. "**" matches any number of directories; patterns without a
+	// "/" match a name at any depth.
+	Exclude []string
+
+	// MaxDepth is how many directory levels below the scanned directory to
+	// descend; 0 for no limit.
+	MaxDepth int
 
-	// Walk the directory looking for language indicators
-	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
+	// CacheDir is where results are cached between runs, keyed by the
+	// scanned directory and options; empty disables caching. Cached results
+	// are used until a walked directory or indicator file changes.
+	CacheDir string `json:"-"`
+}
+
+// excluded reports whether the path rel, relative to the scanned
+// directory, matches an exclude pattern.
+func (o Options) excluded(rel string) bool {
+	return Excluded(o.Exclude, rel)
+}
+
+// Excluded reports whether the path rel matches one of patterns, written
+// like Options.Exclude.
+func Excluded(patterns []string, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/")
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if matchPath(strings.Split(pattern, "/"), strings.Split(rel, "/")) {
+			return true
 		}
+	}
+	return false
+}
 
-		// Skip hidden directories and common non-source directories
-		// Note: don't skip "." itself (current directory)
-		if d.IsDir() {
-			name := d.Name()
-			if name != "." && (name[0] == '.' || name == "node_modules" || name == "vendor" || name == "__pycache__") {
-				return filepath.SkipDir
+// matchPath matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments.
+func matchPath(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchPath(pattern[1:], path[i:]) {
+				return true
 			}
-			return nil
 		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+		return false
+	}
+	return matchPath(pattern[1:], path[1:])
+}
 
-		relDir := filepath.Dir(path)
-		if relDir == "." {
-			relDir = dir
+// Detect scans a directory and returns all detected languages.
+func Detect(dir string) ([]Detection, error) {
+	return DetectWith(dir, Options{})
+}
+
+// DetectWith scans a directory, skipping excluded paths and directories
+// deeper than opts.MaxDepth, and returns all detected languages.
+func DetectWith(dir string, opts Options) ([]Detection, error) {
+	detections, _, err := Scan(dir, opts)
+	return detections, err
+}
+
+// Scan scans a directory and returns all detected languages, with each
+// detection's workspace membership, and the workspaces declared in it.
+// Directories are read concurrently; results are in the order a serial
+// walk would produce.
+func Scan(dir string, opts Options) ([]Detection, []Workspace, error) {
+	detections, workspaces, _, err := scan(dir, opts)
+	return detections, workspaces, err
+}
+
+// DetectCached is DetectWith, also reporting whether the results came from
+// the cache in opts.CacheDir.
+func DetectCached(dir string, opts Options) ([]Detection, bool, error) {
+	detections, _, cached, err := scan(dir, opts)
+	return detections, cached, err
+}
+
+// scan implements Scan, reporting whether the results were cached.
+func scan(dir string, opts Options) ([]Detection, []Workspace, bool, error) {
+	if opts.CacheDir != "" {
+		if entry, ok := loadCache(dir, opts); ok {
+			return entry.Detections, entry.Workspaces, true, nil
+		}
+	}
+
+	indicators, stamps, err := walk(dir, opts)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	var detections []Detection
+	var workspaces []Workspace
+	type key struct {
+		lang Language
+		path string
+	}
+	index := make(map[key]int)
+	for _, ind := range indicators {
+		// Check for workspace declarations
+		if ind.workspace != nil {
+			workspaces = append(workspaces, *ind.workspace)
 		}
 
 		// Check for language indicators
-		switch d.Name() {
+		var lang Language
+		switch filepath.Base(ind.path) {
 		case "go.mod":
-			detections = appendIfNew(detections, Detection{
-				Language: Go,
-				Path:     relDir,
-				Files:    []string{path},
-			})
+			lang = Go
 		case "package.json":
 			// Check if it's TypeScript or JavaScript
-			lang := JavaScript
-			tsConfig := filepath.Join(relDir, "tsconfig.json")
-			if _, err := os.Stat(tsConfig); err == nil {
+			lang = JavaScript
+			if ind.tsConfig {
 				lang = TypeScript
 			}
-			detections = appendIfNew(detections, Detection{
-				Language: lang,
-				Path:     relDir,
-				Files:    []string{path},
-			})
 		case "Cargo.toml":
-			detections = appendIfNew(detections, Detection{
-				Language: Rust,
-				Path:     relDir,
-				Files:    []string{path},
-			})
+			lang = Rust
 		case "Package.swift":
-			detections = appendIfNew(detections, Detection{
-				Language: Swift,
-				Path:     relDir,
-				Files:    []string{path},
-			})
+			lang = Swift
 		case "pyproject.toml", "setup.py", "requirements.txt":
-			detections = appendIfNew(detections, Detection{
-				Language: Python,
-				Path:     relDir,
-				Files:    []string{path},
-			})
+			lang = Python
+		default:
+			continue
 		}
 
-		return nil
-	})
-
-	return detections, err
-}
+		// Merge indicator files found for the same language and path
+		k := key{lang, ind.dir}
+		if i, ok := index[k]; ok {
+			detections[i].Files = append(detections[i].Files, ind.path)
+			continue
+		}
+		index[k] = len(detections)
+		detections = append(detections, Detection{
+			Language: lang,
+			Path:     ind.dir,
+			Files:    []string{ind.path},
+		})
+	}
 
-// appendIfNew adds a detection if the path isn't already detected for that language.
-func appendIfNew(detections []Detection, d Detection) []Detection {
-	for i, existing := range detections {
-		if existing.Language == d.Language && existing.Path == d.Path {
-			// Merge files
-			detections[i].Files = append(existing.Files, d.Files...)
-			return detections
+	for i := range detections {
+		for _, w := range workspaces {
+			if w.Includes(detections[i]) {
+				detections[i].Workspace = w.Path
+				break
+			}
 		}
 	}
-	return append(detections, d)
+
+	if opts.CacheDir != "" {
+		saveCache(dir, opts, cacheEntry{Stamps: stamps, Detections: detections, Workspaces: workspaces})
+	}
+	return detections, workspaces, false, nil
 }
 
 // HasLanguage checks if a specific language was detected.
@@ -128,3 +214,24 @@ func GetByLanguage(detections []Detection, lang Language) []Detection {
 	}
 	return result
 }
+
+// Roots returns the distinct directories under dir where a language was
+// detected, sorted, so that each can be checked as an independent project.
+func Roots(dir string, opts Options) ([]string, error) {
+	detections, err := DetectWith(dir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var roots []string
+	for _, d := range detections {
+		path := filepath.Clean(d.Path)
+		if !seen[path] {
+			seen[path] = true
+			roots = append(roots, path)
+		}
+	}
+	sort.Strings(roots)
+	return roots, nil
+}
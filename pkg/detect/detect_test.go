@@ -3,6 +3,8 @@ package detect
 import (
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -199,3 +201,75 @@ func TestHasLanguage(t *testing.T) {
 		t.Error("expected HasLanguage to return false for Python")
 	}
 }
+
+func TestRoots(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"go.mod", "web/package.json", "svc/go.mod", "svc/requirements.txt", "node_modules/x/package.json"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	roots, err := Roots(dir, Options{})
+	if err != nil {
+		t.Fatalf("Roots failed: %v", err)
+	}
+	want := []string{dir, filepath.Join(dir, "svc"), filepath.Join(dir, "web")}
+	if len(roots) != len(want) {
+		t.Fatalf("Roots() = %v, want %v", roots, want)
+	}
+	for i := range want {
+		if roots[i] != want[i] {
+			t.Errorf("Roots()[%d] = %q, want %q", i, roots[i], want[i])
+		}
+	}
+}
+
+func TestDetectWith_Options(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"", "third_party/lib", "svc/api", "svc/api/testdata/mod", "a/b/c"} {
+		path := filepath.Join(dir, sub)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "go.mod"), []byte("module test"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths := func(opts Options) []string {
+		t.Helper()
+		detections, err := DetectWith(dir, opts)
+		if err != nil {
+			t.Fatalf("DetectWith failed: %v", err)
+		}
+		var rels []string
+		for _, d := range detections {
+			rel, _ := filepath.Rel(dir, d.Path)
+			rels = append(rels, filepath.ToSlash(rel))
+		}
+		sort.Strings(rels)
+		return rels
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"no options", Options{}, ".,a/b/c,svc/api,svc/api/testdata/mod,third_party/lib"},
+		{"anchored exclude", Options{Exclude: []string{"third_party/**"}}, ".,a/b/c,svc/api,svc/api/testdata/mod"},
+		{"name at any depth", Options{Exclude: []string{"testdata"}}, ".,a/b/c,svc/api,third_party/lib"},
+		{"double star", Options{Exclude: []string{"**/testdata/**", "a/*/c"}}, ".,svc/api,third_party/lib"},
+		{"max depth", Options{MaxDepth: 2}, ".,svc/api,third_party/lib"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(paths(tt.opts), ","); got != tt.want {
+			t.Errorf("%s: detected %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
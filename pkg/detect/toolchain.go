@@ -0,0 +1,69 @@
+package detect
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// toolchainCommands are the commands that report each language's toolchain
+// version, tried in order until one is installed.
+var toolchainCommands = map[Language][][]string{
+	Go:         {{"go", "env", "GOVERSION"}},
+	TypeScript: {{"node", "--version"}},
+	JavaScript: {{"node", "--version"}},
+	Rust:       {{"cargo", "--version"}},
+	Python:     {{"python3", "--version"}, {"python", "--version"}},
+	Swift:      {{"swift", "--version"}},
+}
+
+var toolchainVersionPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?[^\s)]*`)
+
+// ToolchainVersion returns the version of the toolchain for lang as resolved
+// in dir, e.g. "1.25.3" for go1.25.3. Running in dir honors go.mod toolchain
+// directives and version manager shims (asdf, nvm, pyenv) that read local
+// files.
+func ToolchainVersion(lang Language, dir string) (string, error) {
+	commands, ok := toolchainCommands[lang]
+	if !ok {
+		return "", fmt.Errorf("no toolchain command for %s", lang)
+	}
+
+	var lastErr error
+	for _, args := range commands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			lastErr = err
+			continue
+		}
+		cmd := proc.Command(args[0], args[1:]...)
+		cmd.Dir = dir
+		// Python 2 prints its version to stderr
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%s failed: %w", strings.Join(args, " "), err)
+		}
+		version := parseToolchainVersion(string(output))
+		if version == "" {
+			return "", fmt.Errorf("no version in %s output: %s", args[0], strings.TrimSpace(string(output)))
+		}
+		return version, nil
+	}
+	return "", lastErr
+}
+
+// parseToolchainVersion extracts the first version number from the output
+// of a version command, e.g. "1.75.0" from "cargo 1.75.0 (1d8b05cdd 2023-11-20)".
+func parseToolchainVersion(output string) string {
+	return toolchainVersionPattern.FindString(output)
+}
+
+// ResolveToolchains sets the Toolchain of each detection. Detections whose
+// toolchain isn't installed are left empty.
+func ResolveToolchains(detections []Detection) {
+	for i := range detections {
+		detections[i].Toolchain, _ = ToolchainVersion(detections[i].Language, detections[i].Path)
+	}
+}
@@ -0,0 +1,49 @@
+package detect
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestParseToolchainVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"go1.25.3\n", "1.25.3"},
+		{"go1.26rc1\n", "1.26rc1"},
+		{"v20.11.0\n", "20.11.0"},
+		{"cargo 1.75.0 (1d8b05cdd 2023-11-20)\n", "1.75.0"},
+		{"Python 3.12.1\n", "3.12.1"},
+		{"swift-driver version: 1.87.3 Apple Swift version 5.9.2 (swiftlang-5.9.2.2.56 clang-1500.1.0.2.5)\n", "1.87.3"},
+		{"command not found", ""},
+	}
+	for _, tt := range tests {
+		if got := parseToolchainVersion(tt.output); got != tt.want {
+			t.Errorf("parseToolchainVersion(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestToolchainVersion(t *testing.T) {
+	if _, err := ToolchainVersion(Language("cobol"), "."); err == nil {
+		t.Error("expected error for unknown language")
+	}
+
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not installed")
+	}
+	version, err := ToolchainVersion(Go, t.TempDir())
+	if err != nil {
+		t.Fatalf("ToolchainVersion(go) error: %v", err)
+	}
+	if parseToolchainVersion(version) != version {
+		t.Errorf("ToolchainVersion(go) = %q, want a bare version", version)
+	}
+
+	detections := []Detection{{Language: Go, Path: t.TempDir()}}
+	ResolveToolchains(detections)
+	if detections[0].Toolchain != version {
+		t.Errorf("ResolveToolchains() set %q, want %q", detections[0].Toolchain, version)
+	}
+}
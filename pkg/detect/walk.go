@@ -0,0 +1,219 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// indicatorFiles are the file names that identify a language or declare a
+// workspace.
+var indicatorFiles = map[string]bool{
+	"go.mod":              true,
+	"go.work":             true,
+	"package.json":        true,
+	"pnpm-workspace.yaml": true,
+	"Cargo.toml":          true,
+	"Package.swift":       true,
+	"pyproject.toml":      true,
+	"setup.py":            true,
+	"requirements.txt":    true,
+}
+
+// indicator is an indicator file found by the walk.
+type indicator struct {
+	path      string     // File path, joined with the scanned directory
+	dir       string     // Directory of the file, as reported in Detection.Path
+	tsConfig  bool       // A tsconfig.json sits next to the file
+	workspace *Workspace // Workspace declared by the file, if any
+	segments  []string   // Path segments relative to the root, for ordering
+}
+
+// walker walks a directory tree with a bounded pool of workers, each
+// reading one directory at a time from a shared queue.
+type walker struct {
+	root string
+	opts Options
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	queue      []walkDir
+	pending    int // Directories queued or being read
+	indicators []indicator
+	stamps     map[string]stamp // Walked directories and indicator files, when caching
+	err        error
+}
+
+type walkDir struct {
+	path  string
+	rel   string // Path relative to the root, slash-separated; "" for the root
+	depth int
+}
+
+// walk returns the indicator files under root in the order
+// filepath.WalkDir would visit them and, when opts.CacheDir is set, stamps
+// of every walked directory and indicator file.
+func walk(root string, opts Options) ([]indicator, map[string]stamp, error) {
+	w := &walker{root: root, opts: opts, queue: []walkDir{{path: root}}, pending: 1}
+	w.cond = sync.NewCond(&w.mu)
+	if opts.CacheDir != "" {
+		w.stamps = make(map[string]stamp)
+	}
+
+	var wg sync.WaitGroup
+	for range 2 * runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+	wg.Wait()
+
+	if w.err != nil {
+		return nil, nil, w.err
+	}
+	sort.Slice(w.indicators, func(i, j int) bool {
+		return lessSegments(w.indicators[i].segments, w.indicators[j].segments)
+	})
+	return w.indicators, w.stamps, nil
+}
+
+func (w *walker) work() {
+	for {
+		w.mu.Lock()
+		for len(w.queue) == 0 && w.pending > 0 {
+			w.cond.Wait()
+		}
+		if w.pending == 0 {
+			w.mu.Unlock()
+			return
+		}
+		d := w.queue[len(w.queue)-1]
+		w.queue = w.queue[:len(w.queue)-1]
+		skip := w.err != nil
+		w.mu.Unlock()
+
+		var subdirs []walkDir
+		var found []indicator
+		var stamps map[string]stamp
+		var err error
+		if !skip {
+			subdirs, found, stamps, err = w.readDir(d)
+		}
+
+		w.mu.Lock()
+		if err != nil && w.err == nil {
+			w.err = err
+		}
+		for path, s := range stamps {
+			w.stamps[path] = s
+		}
+		w.queue = append(w.queue, subdirs...)
+		w.pending += len(subdirs) - 1
+		w.indicators = append(w.indicators, found...)
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}
+}
+
+// readDir lists one directory, returning the subdirectories to walk, the
+// indicator files in it, and their stamps when caching. The sorted
+// directory listing answers the tsconfig.json probe for package.json
+// without another stat.
+func (w *walker) readDir(d walkDir) ([]walkDir, []indicator, map[string]stamp, error) {
+	var stamps map[string]stamp
+	if w.stamps != nil {
+		// Stamp before listing, so a change in between invalidates the cache
+		info, err := os.Stat(d.path)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		stamps = map[string]stamp{d.path: stampOf(info)}
+	}
+
+	entries, err := os.ReadDir(d.path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	relDir := filepath.Clean(d.path)
+	if relDir == "." {
+		relDir = w.root
+	}
+
+	var subdirs []walkDir
+	var found []indicator
+	for _, e := range entries {
+		name := e.Name()
+		rel := name
+		if d.rel != "" {
+			rel = d.rel + "/" + name
+		}
+
+		// Skip configured excludes and directories below the maximum depth
+		if len(w.opts.Exclude) > 0 && w.opts.excluded(rel) {
+			continue
+		}
+
+		// Skip hidden directories and common non-source directories
+		if e.IsDir() {
+			if name[0] == '.' || name == "node_modules" || name == "vendor" || name == "__pycache__" {
+				continue
+			}
+			if w.opts.MaxDepth > 0 && d.depth+1 > w.opts.MaxDepth {
+				continue
+			}
+			subdirs = append(subdirs, walkDir{path: filepath.Join(d.path, name), rel: rel, depth: d.depth + 1})
+			continue
+		}
+
+		if !indicatorFiles[name] {
+			continue
+		}
+		path := filepath.Join(d.path, name)
+		ind := indicator{
+			path:     path,
+			dir:      relDir,
+			tsConfig: name == "package.json" && hasEntry(entries, "tsconfig.json"),
+			segments: strings.Split(rel, "/"),
+		}
+		if stamps != nil {
+			if info, err := os.Stat(path); err == nil {
+				stamps[path] = stampOf(info)
+			}
+		}
+		switch name {
+		case "go.work", "pnpm-workspace.yaml", "package.json", "Cargo.toml":
+			if ws, ok := parseWorkspace(path); ok {
+				ws.Path = relDir
+				ind.workspace = &ws
+			}
+		}
+		found = append(found, ind)
+	}
+	return subdirs, found, stamps, nil
+}
+
+// hasEntry reports whether the sorted directory listing contains name.
+func hasEntry(entries []os.DirEntry, name string) bool {
+	_, ok := slices.BinarySearchFunc(entries, name, func(e os.DirEntry, name string) int {
+		return strings.Compare(e.Name(), name)
+	})
+	return ok
+}
+
+// lessSegments orders paths by comparing their segments in turn, which is
+// the order filepath.WalkDir visits files in.
+func lessSegments(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
@@ -0,0 +1,95 @@
+package detect
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTree(t testing.TB, dir string, files []string) {
+	t.Helper()
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWalk_Order(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, []string{
+		"go.mod", "a/go.mod", "a-b/package.json", "a/b/Cargo.toml", "b/setup.py",
+		"b/requirements.txt", "node_modules/x/package.json", ".git/go.mod",
+		"web/package.json", "web/tsconfig.json", "z/y/x/Package.swift",
+	})
+
+	// Reference order from a serial walk
+	var want []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != dir && (d.Name()[0] == '.' || d.Name() == "node_modules") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && indicatorFiles[d.Name()] {
+			want = append(want, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 5 {
+		indicators, _, err := walk(dir, Options{})
+		if err != nil {
+			t.Fatalf("walk failed: %v", err)
+		}
+		var got []string
+		for _, ind := range indicators {
+			got = append(got, ind.path)
+			if ind.tsConfig != (ind.dir == filepath.Join(dir, "web")) {
+				t.Errorf("%s: tsConfig = %v", ind.path, ind.tsConfig)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("walk order:\n got %v\nwant %v", got, want)
+		}
+	}
+}
+
+func TestScan_MissingDir(t *testing.T) {
+	if _, _, err := Scan(filepath.Join(t.TempDir(), "missing"), Options{}); err == nil {
+		t.Error("expected error for missing directory")
+	}
+}
+
+func BenchmarkScan(b *testing.B) {
+	dir := b.TempDir()
+	var files []string
+	for i := range 50 {
+		for j := range 20 {
+			files = append(files, fmt.Sprintf("pkg%d/mod%d/src/main.go", i, j))
+			if j%4 == 0 {
+				files = append(files, fmt.Sprintf("pkg%d/mod%d/package.json", i, j))
+			}
+		}
+		files = append(files, fmt.Sprintf("pkg%d/go.mod", i))
+	}
+	writeTree(b, dir, files)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, _, err := Scan(dir, Options{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package detect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkspaceKind identifies the tool that declares a workspace.
+type WorkspaceKind string
+
+const (
+	GoWorkspace    WorkspaceKind = "go"    // go.work
+	NPMWorkspace   WorkspaceKind = "npm"   // "workspaces" in package.json (npm, yarn)
+	PNPMWorkspace  WorkspaceKind = "pnpm"  // pnpm-workspace.yaml
+	CargoWorkspace WorkspaceKind = "cargo" // [workspace] in Cargo.toml
+)
+
+// Workspace is a multi-project workspace declared in a repository.
+type Workspace struct {
+	Kind    WorkspaceKind `json:"kind" toon:"kind"`
+	Path    string        `json:"path" toon:"path"`       // Directory of the workspace file
+	File    string        `json:"file" toon:"file"`       // Workspace file
+	Members []string      `json:"members" toon:"members"` // Member patterns relative to Path; "!" excludes
+}
+
+// Includes reports whether a detection belongs to the workspace: the
+// language matches the workspace kind and its path matches a member pattern.
+func (w Workspace) Includes(d Detection) bool {
+	switch w.Kind {
+	case GoWorkspace:
+		if d.Language != Go {
+			return false
+		}
+	case NPMWorkspace, PNPMWorkspace:
+		if d.Language != TypeScript && d.Language != JavaScript {
+			return false
+		}
+	case CargoWorkspace:
+		if d.Language != Rust {
+			return false
+		}
+	}
+
+	rel, err := filepath.Rel(w.Path, d.Path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	included := false
+	for _, m := range w.Members {
+		if pattern, ok := strings.CutPrefix(m, "!"); ok {
+			if matchMember(pattern, rel) {
+				return false
+			}
+		} else if matchMember(m, rel) {
+			included = true
+		}
+	}
+	return included
+}
+
+// matchMember matches a relative path against a workspace member pattern,
+// which may use * wildcards and a trailing /** for any depth.
+func matchMember(pattern, rel string) bool {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(filepath.ToSlash(pattern), "./"), "/")
+	if pattern == "" {
+		pattern = "."
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		return rel != prefix && strings.HasPrefix(rel, prefix+"/")
+	}
+	ok, _ := filepath.Match(pattern, rel)
+	return ok
+}
+
+// parseWorkspace reads the workspace declared by a go.work,
+// pnpm-workspace.yaml, package.json, or Cargo.toml file, if any.
+func parseWorkspace(path string) (Workspace, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Workspace{}, false
+	}
+
+	w := Workspace{Path: filepath.Dir(path), File: path}
+	switch filepath.Base(path) {
+	case "go.work":
+		w.Kind, w.Members = GoWorkspace, parseGoWork(data)
+		return w, true
+	case "pnpm-workspace.yaml":
+		var pnpm struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &pnpm) != nil {
+			return w, false
+		}
+		w.Kind, w.Members = PNPMWorkspace, pnpm.Packages
+		return w, true
+	case "package.json":
+		w.Kind, w.Members = NPMWorkspace, parsePackageWorkspaces(data)
+	case "Cargo.toml":
+		w.Kind, w.Members = CargoWorkspace, parseCargoWorkspace(data)
+	}
+	return w, len(w.Members) > 0
+}
+
+// parseGoWork returns the directories listed in go.work use directives.
+func parseGoWork(data []byte) []string {
+	var members []string
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			if line != "" {
+				members = append(members, strings.Trim(line, `"`))
+			}
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			members = append(members, strings.Trim(strings.TrimSpace(line[4:]), `"`))
+		}
+	}
+	return members
+}
+
+// parsePackageWorkspaces returns the "workspaces" patterns of a package.json,
+// either an array or an object with a "packages" array (yarn).
+func parsePackageWorkspaces(data []byte) []string {
+	var pkg struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &pkg) != nil || len(pkg.Workspaces) == 0 {
+		return nil
+	}
+	var members []string
+	if json.Unmarshal(pkg.Workspaces, &members) == nil {
+		return members
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(pkg.Workspaces, &yarn) == nil {
+		return yarn.Packages
+	}
+	return nil
+}
+
+var tomlString = regexp.MustCompile(`"([^"]*)"`)
+
+// parseCargoWorkspace returns the members of the [workspace] table of a
+// Cargo.toml, with excludes prefixed by "!".
+func parseCargoWorkspace(data []byte) []string {
+	var members []string
+	inWorkspace := false
+	key := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && key == "" {
+			inWorkspace = line == "[workspace]"
+			continue
+		}
+		if !inWorkspace {
+			continue
+		}
+
+		value := line
+		if key == "" {
+			name, v, ok := strings.Cut(line, "=")
+			name = strings.TrimSpace(name)
+			if !ok || (name != "members" && name != "exclude") {
+				continue
+			}
+			key, value = name, v
+		}
+		for _, m := range tomlString.FindAllStringSubmatch(value, -1) {
+			if key == "exclude" {
+				members = append(members, "!"+m[1])
+			} else {
+				members = append(members, m[1])
+			}
+		}
+		if strings.Contains(value, "]") {
+			key = ""
+		}
+	}
+	return members
+}
@@ -0,0 +1,117 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseGoWork(t *testing.T) {
+	data := []byte("go 1.22\n\nuse ./tools // single\n\nuse (\n\t./svc/a\n\t\"./svc/b\"\n)\n")
+	want := []string{"./tools", "./svc/a", "./svc/b"}
+	if got := parseGoWork(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseGoWork() = %v, want %v", got, want)
+	}
+}
+
+func TestParsePackageWorkspaces(t *testing.T) {
+	tests := []struct {
+		data string
+		want []string
+	}{
+		{`{"workspaces": ["packages/*", "apps/web"]}`, []string{"packages/*", "apps/web"}},
+		{`{"workspaces": {"packages": ["libs/*"]}}`, []string{"libs/*"}},
+		{`{"name": "app"}`, nil},
+	}
+	for _, tt := range tests {
+		if got := parsePackageWorkspaces([]byte(tt.data)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parsePackageWorkspaces(%s) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestParseCargoWorkspace(t *testing.T) {
+	data := []byte(`[package]
+name = "root"
+
+[workspace]
+members = [
+    "crates/*", # all crates
+    "tools/cli",
+]
+exclude = ["crates/legacy"]
+
+[dependencies]
+members = "not a workspace key"
+`)
+	want := []string{"crates/*", "tools/cli", "!crates/legacy"}
+	if got := parseCargoWorkspace(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCargoWorkspace() = %v, want %v", got, want)
+	}
+}
+
+func TestWorkspaceIncludes(t *testing.T) {
+	w := Workspace{Kind: CargoWorkspace, Path: "repo", Members: []string{"crates/*", "!crates/legacy", "tools/**"}}
+	tests := []struct {
+		d    Detection
+		want bool
+	}{
+		{Detection{Language: Rust, Path: "repo/crates/core"}, true},
+		{Detection{Language: Rust, Path: "repo/crates/legacy"}, false},
+		{Detection{Language: Rust, Path: "repo/crates/core/sub"}, false},
+		{Detection{Language: Rust, Path: "repo/tools/a/b"}, true},
+		{Detection{Language: Rust, Path: "repo"}, false},
+		{Detection{Language: Go, Path: "repo/crates/core"}, false},
+		{Detection{Language: Rust, Path: "other/crates/core"}, false},
+	}
+	for _, tt := range tests {
+		if got := w.Includes(tt.d); got != tt.want {
+			t.Errorf("Includes(%s %s) = %v, want %v", tt.d.Language, tt.d.Path, got, tt.want)
+		}
+	}
+}
+
+func TestScan_Workspaces(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.work":                "go 1.22\n\nuse ./svc/api\n",
+		"svc/api/go.mod":         "module api",
+		"tools/go.mod":           "module tools",
+		"pnpm-workspace.yaml":    "packages:\n  - 'web/*'\n",
+		"package.json":           "{}",
+		"web/app/package.json":   "{}",
+		"web/app/tsconfig.json":  "{}",
+		"crates/core/Cargo.toml": "[package]\nname = \"core\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	detections, workspaces, err := Scan(dir, Options{})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if len(workspaces) != 2 {
+		t.Errorf("expected 2 workspaces, got %+v", workspaces)
+	}
+
+	want := map[string]string{
+		filepath.Join(dir, "svc", "api"):     dir,
+		filepath.Join(dir, "tools"):          "",
+		filepath.Join(dir, "web", "app"):     dir,
+		dir:                                  "",
+		filepath.Join(dir, "crates", "core"): "",
+	}
+	for _, d := range detections {
+		if d.Workspace != want[d.Path] {
+			t.Errorf("%s in %s: workspace = %q, want %q", d.Language, d.Path, d.Workspace, want[d.Path])
+		}
+	}
+}
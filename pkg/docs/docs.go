@@ -0,0 +1,169 @@
+// Package docs scaffolds project documents (PRD, TRD, release notes) and
+// parses the YAML front-matter that identifies their owner, status, and
+// version.
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document kinds that can be scaffolded.
+const (
+	KindPRD          = "prd"
+	KindTRD          = "trd"
+	KindReleaseNotes = "release-notes"
+)
+
+// Kinds lists the document kinds in scaffolding order.
+var Kinds = []string{KindPRD, KindTRD, KindReleaseNotes}
+
+// Document statuses accepted in front-matter.
+const (
+	StatusDraft    = "draft"
+	StatusReview   = "review"
+	StatusApproved = "approved"
+	StatusFinal    = "final"
+)
+
+// Statuses lists the valid front-matter statuses.
+var Statuses = []string{StatusDraft, StatusReview, StatusApproved, StatusFinal}
+
+//go:embed templates/*.md
+var templates embed.FS
+
+var templateFiles = map[string]string{
+	KindPRD:          "templates/PRD.md",
+	KindTRD:          "templates/TRD.md",
+	KindReleaseNotes: "templates/RELEASE_NOTES.md",
+}
+
+// TemplateData is the data available to document templates.
+type TemplateData struct {
+	Project string // Project name
+	Owner   string // Document owner
+	Version string // Target version, e.g. "v1.2.0"
+	Date    string // Current date (YYYY-MM-DD)
+}
+
+// FrontMatter is the YAML block between "---" lines at the top of a
+// document.
+type FrontMatter struct {
+	Title   string `yaml:"title"`
+	Owner   string `yaml:"owner"`
+	Status  string `yaml:"status"`
+	Version string `yaml:"version"`
+}
+
+// ErrNoFrontMatter is returned by ParseFrontMatter when the document does not
+// start with a "---" line.
+var ErrNoFrontMatter = errors.New("no front-matter")
+
+// Path returns the path, relative to the project directory, where a document
+// of the given kind is scaffolded. Release notes go to
+// docs/releases/<version>.md when docs/ exists and RELEASE_NOTES_<version>.md
+// otherwise, matching where the documentation checks look for them.
+func Path(dir, kind, version string) (string, error) {
+	switch kind {
+	case KindPRD:
+		return "PRD.md", nil
+	case KindTRD:
+		return "TRD.md", nil
+	case KindReleaseNotes:
+		if version == "" {
+			return "", errors.New("release notes require a version")
+		}
+		version = "v" + strings.TrimPrefix(version, "v")
+		if info, err := os.Stat(filepath.Join(dir, "docs")); err == nil && info.IsDir() {
+			return filepath.Join("docs", "releases", version+".md"), nil
+		}
+		return fmt.Sprintf("RELEASE_NOTES_%s.md", version), nil
+	}
+	return "", fmt.Errorf("unknown document kind %q (expected %s)", kind, strings.Join(Kinds, ", "))
+}
+
+// Render returns the template for a document kind filled in with data.
+func Render(kind string, data TemplateData) ([]byte, error) {
+	name, ok := templateFiles[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown document kind %q (expected %s)", kind, strings.Join(Kinds, ", "))
+	}
+	if data.Version != "" {
+		data.Version = "v" + strings.TrimPrefix(data.Version, "v")
+	}
+	if data.Owner == "" {
+		data.Owner = "TBD"
+	}
+
+	tmpl, err := template.ParseFS(templates, name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseFrontMatter returns the front-matter of a Markdown document and the
+// body that follows it.
+func ParseFrontMatter(data []byte) (*FrontMatter, []byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	first, rest, _ := bytes.Cut(data, []byte("\n"))
+	if strings.TrimSpace(string(first)) != "---" {
+		return nil, data, ErrNoFrontMatter
+	}
+
+	var block []byte
+	for len(rest) > 0 {
+		line, next, _ := bytes.Cut(rest, []byte("\n"))
+		rest = next
+		if strings.TrimSpace(string(line)) == "---" {
+			var fm FrontMatter
+			if err := yaml.Unmarshal(block, &fm); err != nil {
+				return nil, data, fmt.Errorf("invalid front-matter: %w", err)
+			}
+			return &fm, rest, nil
+		}
+		block = append(block, line...)
+		block = append(block, '\n')
+	}
+	return nil, data, errors.New("unterminated front-matter")
+}
+
+// Problems returns the fields that are missing or invalid. Placeholder
+// owners ("TBD") count as missing.
+func (fm *FrontMatter) Problems() []string {
+	var problems []string
+	if fm.Owner == "" || strings.EqualFold(fm.Owner, "TBD") {
+		problems = append(problems, "owner is not set")
+	}
+	switch {
+	case fm.Status == "":
+		problems = append(problems, "status is not set")
+	case !isStatus(fm.Status):
+		problems = append(problems, fmt.Sprintf("status %q is not one of %s", fm.Status, strings.Join(Statuses, ", ")))
+	}
+	if fm.Version == "" {
+		problems = append(problems, "version is not set")
+	}
+	return problems
+}
+
+func isStatus(s string) bool {
+	for _, status := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
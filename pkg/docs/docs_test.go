@@ -0,0 +1,87 @@
+package docs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	for _, kind := range Kinds {
+		out, err := Render(kind, TemplateData{Project: "demo", Owner: "Jane", Version: "1.2.0", Date: "2026-01-02"})
+		if err != nil {
+			t.Fatalf("Render(%s) error: %v", kind, err)
+		}
+		fm, body, err := ParseFrontMatter(out)
+		if err != nil {
+			t.Fatalf("Render(%s) front-matter: %v", kind, err)
+		}
+		if fm.Owner != "Jane" || fm.Status != StatusDraft || fm.Version != "v1.2.0" {
+			t.Errorf("Render(%s) front-matter = %+v", kind, fm)
+		}
+		if problems := fm.Problems(); len(problems) != 0 {
+			t.Errorf("Render(%s) problems = %v", kind, problems)
+		}
+		if !strings.HasPrefix(string(body), "\n# demo") {
+			t.Errorf("Render(%s) body starts %q", kind, string(body)[:20])
+		}
+	}
+
+	out, err := Render(KindPRD, TemplateData{Project: "demo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fm, _, _ := ParseFrontMatter(out)
+	want := []string{"owner is not set", "version is not set"}
+	if got := fm.Problems(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Problems() = %v, want %v", got, want)
+	}
+
+	if _, err := Render("spec", TemplateData{}); err == nil {
+		t.Error("Render(unknown) error = nil, want error")
+	}
+}
+
+func TestParseFrontMatter(t *testing.T) {
+	if _, _, err := ParseFrontMatter([]byte("# Title\n")); !errors.Is(err, ErrNoFrontMatter) {
+		t.Errorf("no front-matter: error = %v, want ErrNoFrontMatter", err)
+	}
+	if _, _, err := ParseFrontMatter([]byte("---\nowner: x\n")); err == nil {
+		t.Error("unterminated: error = nil, want error")
+	}
+	if _, _, err := ParseFrontMatter([]byte("---\nowner: [x\n---\n")); err == nil {
+		t.Error("invalid YAML: error = nil, want error")
+	}
+
+	fm, body, err := ParseFrontMatter([]byte("\ufeff---\r\nowner: Jane\r\nstatus: shipped\r\nversion: v1.0.0\r\n---\r\nBody\n"))
+	if err != nil {
+		t.Fatalf("ParseFrontMatter() error: %v", err)
+	}
+	if string(body) != "Body\n" {
+		t.Errorf("body = %q", body)
+	}
+	if got := fm.Problems(); len(got) != 1 || !strings.Contains(got[0], `status "shipped"`) {
+		t.Errorf("Problems() = %v, want invalid status", got)
+	}
+}
+
+func TestPath(t *testing.T) {
+	dir := t.TempDir()
+	if got, _ := Path(dir, KindReleaseNotes, "1.2.0"); got != "RELEASE_NOTES_v1.2.0.md" {
+		t.Errorf("Path(no docs/) = %q", got)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Path(dir, KindReleaseNotes, "v1.2.0"); got != filepath.Join("docs", "releases", "v1.2.0.md") {
+		t.Errorf("Path(docs/) = %q", got)
+	}
+	if _, err := Path(dir, KindReleaseNotes, ""); err == nil {
+		t.Error("Path(no version) error = nil, want error")
+	}
+	if got, _ := Path(dir, KindTRD, ""); got != "TRD.md" {
+		t.Errorf("Path(trd) = %q", got)
+	}
+}
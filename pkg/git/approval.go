@@ -0,0 +1,112 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// CurrentUser returns the login of the GitHub user gh is authenticated as.
+func (g *Git) CurrentUser() (string, error) {
+	if !commandExists("gh") {
+		return "", proc.NotFound("gh CLI")
+	}
+	output, err := g.runGH("api", "user", "--jq", ".login")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// SwitchUser makes login the active gh account, e.g. after another
+// maintainer authenticated to approve a release.
+func (g *Git) SwitchUser(login string) error {
+	current, err := g.CurrentUser()
+	if err == nil && current == login {
+		return nil
+	}
+	_, err = g.runGH("auth", "switch", "--user", login)
+	return err
+}
+
+// Permission returns the permission of a GitHub user in the repository of
+// the remote: "admin", "maintain", "write", "triage", "read", or "none".
+func (g *Git) Permission(login string) (string, error) {
+	owner, repo, err := g.parseRemoteURL()
+	if err != nil {
+		return "", err
+	}
+	output, err := g.runGH("api", fmt.Sprintf("repos/%s/%s/collaborators/%s/permission", owner, repo, login), "--jq", ".permission")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// CanMaintain reports whether permission, as returned by Permission, lets
+// a user push to the repository.
+func CanMaintain(permission string) bool {
+	switch permission {
+	case "admin", "maintain", "write":
+		return true
+	}
+	return false
+}
+
+// PRApprovers returns the users whose latest review of a pull request,
+// given its number, URL, or head branch, approves it. The author's own
+// reviews don't count.
+func (g *Git) PRApprovers(ref string) ([]string, error) {
+	if !commandExists("gh") {
+		return nil, proc.NotFound("gh CLI")
+	}
+	output, err := g.runGH("pr", "view", ref, "--json", "author,reviews")
+	if err != nil {
+		return nil, err
+	}
+	return parseApprovers([]byte(output))
+}
+
+// parseApprovers reads the approvers from gh pr view --json author,reviews.
+// Reviews are in the order they were submitted, so a later "changes
+// requested" replaces an earlier approval. Comments don't change a
+// reviewer's verdict.
+func parseApprovers(data []byte) ([]string, error) {
+	var result struct {
+		Author struct {
+			Login string `json:"login"`
+		} `json:"author"`
+		Reviews []struct {
+			Author struct {
+				Login string `json:"login"`
+			} `json:"author"`
+			State string `json:"state"`
+		} `json:"reviews"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	var order []string
+	verdicts := make(map[string]string)
+	for _, r := range result.Reviews {
+		login := r.Author.Login
+		if login == "" || login == result.Author.Login || r.State == "COMMENTED" || r.State == "PENDING" {
+			continue
+		}
+		if _, ok := verdicts[login]; !ok {
+			order = append(order, login)
+		}
+		verdicts[login] = r.State
+	}
+
+	var approvers []string
+	for _, login := range order {
+		if verdicts[login] == "APPROVED" {
+			approvers = append(approvers, login)
+		}
+	}
+	return approvers, nil
+}
@@ -0,0 +1,36 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseApprovers(t *testing.T) {
+	data := `{
+  "author": {"login": "releaser"},
+  "reviews": [
+    {"author": {"login": "alice"}, "state": "APPROVED"},
+    {"author": {"login": "bob"}, "state": "APPROVED"},
+    {"author": {"login": "releaser"}, "state": "APPROVED"},
+    {"author": {"login": "bob"}, "state": "CHANGES_REQUESTED"},
+    {"author": {"login": "carol"}, "state": "CHANGES_REQUESTED"},
+    {"author": {"login": "carol"}, "state": "APPROVED"},
+    {"author": {"login": "alice"}, "state": "COMMENTED"}
+  ]
+}`
+	got, err := parseApprovers([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alice", "carol"}; !slices.Equal(got, want) {
+		t.Errorf("parseApprovers() = %v, want %v", got, want)
+	}
+}
+
+func TestCanMaintain(t *testing.T) {
+	for perm, want := range map[string]bool{"admin": true, "maintain": true, "write": true, "triage": false, "read": false, "none": false} {
+		if got := CanMaintain(perm); got != want {
+			t.Errorf("CanMaintain(%q) = %v, want %v", perm, got, want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateBranch creates a branch at startPoint and checks it out.
+func (g *Git) CreateBranch(name, startPoint string) error {
+	args := []string{"checkout", "-b", name}
+	if startPoint != "" {
+		args = append(args, startPoint)
+	}
+	if _, err := g.run(args...); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// Checkout checks out an existing branch or ref.
+func (g *Git) Checkout(ref string) error {
+	if _, err := g.run("checkout", ref); err != nil {
+		return fmt.Errorf("failed to checkout %s: %w", ref, err)
+	}
+	return nil
+}
+
+// DeleteBranch force-deletes a local branch.
+func (g *Git) DeleteBranch(name string) error {
+	_, err := g.run("branch", "-D", name)
+	return err
+}
+
+// RefExists reports whether ref resolves to a commit.
+func (g *Git) RefExists(ref string) bool {
+	_, err := g.run("rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	return err == nil
+}
+
+// ResolveCommit returns the full SHA of the commit ref points to.
+func (g *Git) ResolveCommit(ref string) (string, error) {
+	output, err := g.run("rev-parse", "--verify", ref+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("commit %s not found", ref)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// CommitSubject returns the subject line of a commit.
+func (g *Git) CommitSubject(ref string) (string, error) {
+	output, err := g.run("log", "-1", "--format=%s", ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// ShowFile returns the contents of path as of ref, e.g. a file on another
+// branch.
+func (g *Git) ShowFile(ref, path string) ([]byte, error) {
+	output, err := g.run("show", ref+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(output), nil
+}
+
+// ListFiles returns the files under path as of ref, relative to the
+// repository directory, or none if path doesn't exist at ref.
+func (g *Git) ListFiles(ref, path string) ([]string, error) {
+	output, err := g.run("ls-tree", "-r", "--name-only", ref, "--", path)
+	if err != nil {
+		return nil, err
+	}
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// CherryPick applies a commit onto the current branch, recording the
+// original commit in the message (-x).
+func (g *Git) CherryPick(commit string) error {
+	if _, err := g.run("cherry-pick", "-x", commit); err != nil {
+		return fmt.Errorf("failed to cherry-pick %s: %w", commit, err)
+	}
+	return nil
+}
+
+// AbortCherryPick aborts an in-progress cherry-pick.
+func (g *Git) AbortCherryPick() error {
+	_, err := g.run("cherry-pick", "--abort")
+	return err
+}
+
+// ConflictedFiles returns the paths with unresolved merge conflicts.
+func (g *Git) ConflictedFiles() ([]string, error) {
+	output, err := g.run("diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
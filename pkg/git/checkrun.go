@@ -0,0 +1,122 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// maxCheckRunAnnotations is the most annotations GitHub accepts per check
+// run request; more are sent in further updates.
+const maxCheckRunAnnotations = 50
+
+// maxCheckRunText is the most characters GitHub accepts in a check run's
+// summary, text, or annotation message.
+const maxCheckRunText = 65535
+
+// CheckRunAnnotation is a message attached to a check run, shown in the
+// pull request's checks UI.
+type CheckRunAnnotation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Level     string `json:"annotation_level"` // notice, warning, or failure
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message"`
+}
+
+// CheckRun is a completed GitHub check run to publish on a commit.
+type CheckRun struct {
+	Name        string
+	HeadSHA     string
+	Conclusion  string // success, failure, neutral, ...
+	Title       string
+	Summary     string // Markdown
+	Annotations []CheckRunAnnotation
+}
+
+// checkRunOutput is the output object of the check runs API.
+type checkRunOutput struct {
+	Title       string               `json:"title"`
+	Summary     string               `json:"summary"`
+	Annotations []CheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+// truncateText shortens s to GitHub's limit for check run text.
+func truncateText(s string) string {
+	if len(s) <= maxCheckRunText {
+		return s
+	}
+	const suffix = "\n\n… (truncated)"
+	return s[:maxCheckRunText-len(suffix)] + suffix
+}
+
+// CreateCheckRun publishes run on its commit using the gh CLI and returns
+// the check run's URL. GitHub only lets GitHub Apps create check runs, so
+// this needs an app token such as GITHUB_TOKEN in GitHub Actions, with the
+// checks: write permission.
+func (g *Git) CreateCheckRun(run CheckRun) (string, error) {
+	if !commandExists("gh") {
+		return "", proc.NotFound("gh CLI")
+	}
+	owner, repo, err := g.parseRemoteURL()
+	if err != nil {
+		return "", err
+	}
+
+	for i := range run.Annotations {
+		run.Annotations[i].Message = truncateText(run.Annotations[i].Message)
+	}
+	annotations := run.Annotations
+	first := annotations[:min(len(annotations), maxCheckRunAnnotations)]
+	output := checkRunOutput{Title: run.Title, Summary: truncateText(run.Summary), Annotations: first}
+
+	data, err := g.checkRunRequest("POST", fmt.Sprintf("repos/%s/%s/check-runs", owner, repo), map[string]any{
+		"name":       run.Name,
+		"head_sha":   run.HeadSHA,
+		"status":     "completed",
+		"conclusion": run.Conclusion,
+		"output":     output,
+	})
+	if err != nil {
+		return "", err
+	}
+	var created struct {
+		ID      int64  `json:"id"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal([]byte(data), &created); err != nil {
+		return "", fmt.Errorf("parsing check run: %w", err)
+	}
+
+	// Annotations beyond the first batch are appended by updating the run
+	for rest := annotations[len(first):]; len(rest) > 0; {
+		output.Annotations = rest[:min(len(rest), maxCheckRunAnnotations)]
+		rest = rest[len(output.Annotations):]
+		if _, err := g.checkRunRequest("PATCH", fmt.Sprintf("repos/%s/%s/check-runs/%d", owner, repo, created.ID), map[string]any{
+			"output": output,
+		}); err != nil {
+			return created.HTMLURL, err
+		}
+	}
+	return created.HTMLURL, nil
+}
+
+// checkRunRequest sends a JSON body to the check runs API with gh api.
+func (g *Git) checkRunRequest(method, endpoint string, body any) (string, error) {
+	f, err := os.CreateTemp("", "atrelease-check-run-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if err := json.NewEncoder(f).Encode(body); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return g.runGH("api", "--method", method, endpoint, "--input", f.Name())
+}
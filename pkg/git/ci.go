@@ -2,13 +2,23 @@ package git
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
-	"regexp"
 	"strings"
 	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
+// ErrCITimeout is returned by WaitForCI when CI does not finish in time.
+var ErrCITimeout = errors.New("CI timeout")
+
+// ErrMergeTimeout is returned by WaitForMerge when the pull request is not
+// merged in time.
+var ErrMergeTimeout = errors.New("merge timeout")
+
 // CIStatus represents the combined status of CI checks.
 type CIStatus struct {
 	State       string        // "success", "pending", "failure", "error"
@@ -60,7 +70,7 @@ type ghCheckRuns struct {
 // GetCIStatus retrieves the CI status for a commit.
 func (g *Git) GetCIStatus(ref string) (*CIStatus, error) {
 	if !commandExists("gh") {
-		return nil, fmt.Errorf("gh CLI not found in PATH")
+		return nil, proc.NotFound("gh CLI")
 	}
 
 	// Get repository info
@@ -140,7 +150,7 @@ func (g *Git) GetCIStatus(ref string) (*CIStatus, error) {
 // WaitForCI waits for CI to complete with a timeout.
 func (g *Git) WaitForCI(timeout time.Duration) error {
 	if !commandExists("gh") {
-		return fmt.Errorf("gh CLI not found in PATH")
+		return proc.NotFound("gh CLI")
 	}
 
 	ref, err := g.CurrentCommit()
@@ -165,10 +175,12 @@ func (g *Git) WaitForCI(timeout time.Duration) error {
 		}
 
 		// Still pending, wait and retry
-		time.Sleep(pollInterval)
+		if err := proc.Sleep(pollInterval); err != nil {
+			return err
+		}
 	}
 
-	return fmt.Errorf("CI timeout after %v", timeout)
+	return fmt.Errorf("%w after %v", ErrCITimeout, timeout)
 }
 
 // IsCIPassing checks if CI is currently passing (without waiting).
@@ -180,36 +192,27 @@ func (g *Git) IsCIPassing(ref string) (bool, error) {
 	return status.State == "success", nil
 }
 
-// parseRemoteURL extracts owner and repo from the remote URL.
-func (g *Git) parseRemoteURL() (owner string, repo string, err error) {
-	url, err := g.RemoteURL()
-	if err != nil {
-		return "", "", err
-	}
-
-	// Handle SSH format: git@github.com:owner/repo.git
-	sshRegex := regexp.MustCompile(`git@github\.com:([^/]+)/(.+?)(?:\.git)?$`)
-	if m := sshRegex.FindStringSubmatch(url); m != nil {
-		return m[1], m[2], nil
-	}
-
-	// Handle HTTPS format: https://github.com/owner/repo.git
-	httpsRegex := regexp.MustCompile(`https://github\.com/([^/]+)/(.+?)(?:\.git)?$`)
-	if m := httpsRegex.FindStringSubmatch(url); m != nil {
-		return m[1], m[2], nil
-	}
-
-	return "", "", fmt.Errorf("could not parse GitHub URL: %s", url)
-}
-
 // runGH executes a gh command and returns the output.
 func (g *Git) runGH(args ...string) (string, error) {
-	cmd := exec.Command("gh", args...)
+	args, env := g.ghHostArgs(args)
+	cmd := proc.Command("gh", args...)
 	cmd.Dir = g.Dir
+	if len(env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, env...)
+	}
 
+	start := time.Now()
 	output, err := cmd.Output()
+	traceCommand(g.Dir, "gh", args, start, err)
 	if err != nil {
-		return "", err
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %w: %s", commandString("gh", args), err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %w", commandString("gh", args), err)
 	}
 
 	return string(output), nil
@@ -251,7 +254,7 @@ func commandExists(command string) bool {
 // GetPRForBranch gets the PR number for the current branch.
 func (g *Git) GetPRForBranch() (int, error) {
 	if !commandExists("gh") {
-		return 0, fmt.Errorf("gh CLI not found in PATH")
+		return 0, proc.NotFound("gh CLI")
 	}
 
 	branch, err := g.CurrentBranch()
@@ -274,10 +277,283 @@ func (g *Git) GetPRForBranch() (int, error) {
 	return result.Number, nil
 }
 
+// CreatePR opens a pull request from head into base and returns its URL.
+func (g *Git) CreatePR(base, head, title, body string) (string, error) {
+	if !commandExists("gh") {
+		return "", proc.NotFound("gh CLI")
+	}
+
+	output, err := g.runGH("pr", "create", "--base", base, "--head", head, "--title", title, "--body", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PR: %w", err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// PullRequest is the state of a GitHub pull request.
+type PullRequest struct {
+	Number      int              // PR number
+	URL         string           // PR URL
+	State       string           // "OPEN", "CLOSED", or "MERGED"
+	MergeCommit string           // Merge commit SHA (only when merged)
+	AutoMerge   bool             // Auto-merge is enabled
+	MergeQueue  *MergeQueueEntry // Merge queue entry, if the PR is queued
+}
+
+// MergeQueueEntry is a pull request's position in a GitHub merge queue.
+type MergeQueueEntry struct {
+	State      string // "QUEUED", "AWAITING_CHECKS", "MERGEABLE", "UNMERGEABLE", or "LOCKED"
+	Position   int    // Position in the queue, starting at 1
+	HeadCommit string // Merge group commit the queue's checks run on
+}
+
+// IsMerged reports whether the pull request has been merged.
+func (pr *PullRequest) IsMerged() bool {
+	return pr.State == "MERGED"
+}
+
+// GetPR returns the state of a pull request given its number, URL, or head
+// branch.
+func (g *Git) GetPR(ref string) (*PullRequest, error) {
+	if !commandExists("gh") {
+		return nil, proc.NotFound("gh CLI")
+	}
+
+	output, err := g.runGH("pr", "view", ref, "--json", "number,url,state,mergeCommit,autoMergeRequest")
+	if err != nil {
+		return nil, err
+	}
+	pr, err := parsePullRequest([]byte(output))
+	if err != nil {
+		return nil, err
+	}
+
+	if pr.State == "OPEN" {
+		// Not every repository has a merge queue; treat lookup errors as
+		// "not queued".
+		pr.MergeQueue, _ = g.getMergeQueueEntry(pr.Number)
+	}
+	return pr, nil
+}
+
+// mergeQueueQuery fetches a pull request's merge queue entry, which gh pr
+// view does not expose.
+const mergeQueueQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      mergeQueueEntry { state position headCommit { oid } }
+    }
+  }
+}`
+
+// getMergeQueueEntry returns the merge queue entry of a pull request, or nil
+// if it is not queued.
+func (g *Git) getMergeQueueEntry(number int) (*MergeQueueEntry, error) {
+	owner, repo, err := g.parseRemoteURL()
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := g.runGH("api", "graphql",
+		"-f", "query="+mergeQueueQuery,
+		"-F", "owner="+owner,
+		"-F", "repo="+repo,
+		"-F", fmt.Sprintf("number=%d", number))
+	if err != nil {
+		return nil, err
+	}
+	return parseMergeQueueEntry([]byte(output))
+}
+
+// parseMergeQueueEntry parses the response to mergeQueueQuery.
+func parseMergeQueueEntry(data []byte) (*MergeQueueEntry, error) {
+	var result struct {
+		Data struct {
+			Repository struct {
+				PullRequest struct {
+					MergeQueueEntry *struct {
+						State      string `json:"state"`
+						Position   int    `json:"position"`
+						HeadCommit *struct {
+							OID string `json:"oid"`
+						} `json:"headCommit"`
+					} `json:"mergeQueueEntry"`
+				} `json:"pullRequest"`
+			} `json:"repository"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	e := result.Data.Repository.PullRequest.MergeQueueEntry
+	if e == nil {
+		return nil, nil
+	}
+	entry := &MergeQueueEntry{State: e.State, Position: e.Position}
+	if e.HeadCommit != nil {
+		entry.HeadCommit = e.HeadCommit.OID
+	}
+	return entry, nil
+}
+
+// EnableAutoMerge turns on auto-merge for a pull request using method
+// ("merge", "squash", or "rebase"; "merge" if empty). When the base branch
+// requires a merge queue, GitHub adds the pull request to the queue instead
+// and the queue's merge method applies.
+func (g *Git) EnableAutoMerge(ref, method string) error {
+	if !commandExists("gh") {
+		return proc.NotFound("gh CLI")
+	}
+
+	switch method {
+	case "":
+		method = "merge"
+	case "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("invalid merge method %q (expected merge, squash, or rebase)", method)
+	}
+
+	if _, err := g.runGH("pr", "merge", ref, "--auto", "--"+method); err != nil {
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
+	return nil
+}
+
+// WaitForPRCI waits for the checks of a pull request to pass. Once the pull
+// request is in a merge queue, the queue's merge group checks are followed
+// instead, and with auto-merge enabled it keeps waiting until the pull
+// request is queued or merged. It returns the pull request's last state.
+func (g *Git) WaitForPRCI(ref string, timeout time.Duration) (*PullRequest, error) {
+	deadline := time.Now().Add(timeout)
+	pollInterval := 10 * time.Second
+
+	queued := false
+	for time.Now().Before(deadline) {
+		pr, err := g.GetPR(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		// Queued PRs are checked on the merge group commit, once the queue
+		// has created it
+		var status *CIStatus
+		switch {
+		case pr.MergeQueue != nil:
+			if pr.MergeQueue.HeadCommit != "" {
+				status, err = g.GetCIStatus(pr.MergeQueue.HeadCommit)
+			}
+		case pr.State == "OPEN":
+			status, err = g.GetPRStatus(pr.Number)
+		}
+		if err != nil {
+			return pr, err
+		}
+
+		done, err := prCIState(pr, status, queued)
+		if err != nil || done {
+			return pr, err
+		}
+		queued = queued || pr.MergeQueue != nil
+
+		if err := proc.Sleep(pollInterval); err != nil {
+			return pr, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w after %v", ErrCITimeout, timeout)
+}
+
+// prCIState decides whether WaitForPRCI is done given the pull request, the
+// status of the checks it should follow (nil if none), and whether the pull
+// request was seen in the merge queue before.
+func prCIState(pr *PullRequest, status *CIStatus, wasQueued bool) (bool, error) {
+	switch {
+	case pr.IsMerged():
+		return true, nil
+	case pr.State == "CLOSED":
+		return true, fmt.Errorf("pull request #%d was closed without merging", pr.Number)
+	case pr.MergeQueue != nil && pr.MergeQueue.State == "UNMERGEABLE":
+		return true, fmt.Errorf("pull request #%d cannot be merged by the merge queue", pr.Number)
+	case pr.MergeQueue == nil && wasQueued:
+		return true, fmt.Errorf("pull request #%d was removed from the merge queue", pr.Number)
+	}
+
+	if status == nil {
+		return false, nil
+	}
+	switch status.State {
+	case "failure", "error":
+		if pr.MergeQueue != nil {
+			return true, fmt.Errorf("merge queue checks failed with state: %s", status.State)
+		}
+		return true, fmt.Errorf("CI failed with state: %s", status.State)
+	case "success":
+		// With auto-merge, passing PR checks are followed by the merge
+		// queue (or the merge itself), so keep waiting
+		return !pr.AutoMerge || pr.MergeQueue != nil, nil
+	}
+	return false, nil
+}
+
+// parsePullRequest parses the JSON output of gh pr view.
+func parsePullRequest(data []byte) (*PullRequest, error) {
+	var result struct {
+		Number      int    `json:"number"`
+		URL         string `json:"url"`
+		State       string `json:"state"`
+		MergeCommit *struct {
+			OID string `json:"oid"`
+		} `json:"mergeCommit"`
+		AutoMergeRequest *struct{} `json:"autoMergeRequest"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	pr := &PullRequest{
+		Number:    result.Number,
+		URL:       result.URL,
+		State:     strings.ToUpper(result.State),
+		AutoMerge: result.AutoMergeRequest != nil,
+	}
+	if result.MergeCommit != nil {
+		pr.MergeCommit = result.MergeCommit.OID
+	}
+	return pr, nil
+}
+
+// WaitForMerge polls a pull request until it is merged and returns its final
+// state. It fails if the pull request is closed without merging.
+func (g *Git) WaitForMerge(ref string, timeout time.Duration) (*PullRequest, error) {
+	deadline := time.Now().Add(timeout)
+	pollInterval := 30 * time.Second
+
+	for time.Now().Before(deadline) {
+		pr, err := g.GetPR(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case pr.IsMerged():
+			return pr, nil
+		case pr.State == "CLOSED":
+			return pr, fmt.Errorf("pull request #%d was closed without merging", pr.Number)
+		}
+
+		if err := proc.Sleep(pollInterval); err != nil {
+			return pr, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w after %v", ErrMergeTimeout, timeout)
+}
+
 // GetPRStatus gets the CI status for a PR.
 func (g *Git) GetPRStatus(prNumber int) (*CIStatus, error) {
 	if !commandExists("gh") {
-		return nil, fmt.Errorf("gh CLI not found in PATH")
+		return nil, proc.NotFound("gh CLI")
 	}
 
 	output, err := g.runGH("pr", "checks", fmt.Sprintf("%d", prNumber), "--json", "name,state,conclusion")
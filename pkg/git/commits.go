@@ -0,0 +1,104 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// Commit is a commit parsed according to the Conventional Commits format.
+type Commit struct {
+	Hash     string
+	Subject  string
+	Type     string // e.g. "feat", "fix"; "other" if not conventional
+	Scope    string
+	Breaking bool
+}
+
+// conventionalPattern matches "type(scope)!: description".
+var conventionalPattern = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?:\s`)
+
+// ParseCommit classifies a commit subject and body.
+func ParseCommit(hash, subject, body string) Commit {
+	c := Commit{Hash: hash, Subject: subject, Type: "other"}
+	if m := conventionalPattern.FindStringSubmatch(subject); m != nil {
+		c.Type = strings.ToLower(m[1])
+		c.Scope = m[2]
+		c.Breaking = m[3] == "!"
+	}
+	if strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:") {
+		c.Breaking = true
+	}
+	return c
+}
+
+// CommitsSince returns the commits after ref up to HEAD, newest first.
+// If ref is empty, all commits reachable from HEAD are returned.
+func (g *Git) CommitsSince(ref string) ([]Commit, error) {
+	if g.goGit {
+		return g.goGitCommitsSince(ref)
+	}
+	rangeArg := "HEAD"
+	if ref != "" {
+		rangeArg = ref + "..HEAD"
+	}
+	// Fields are separated by US (0x1f) and records by RS (0x1e).
+	output, err := g.run("log", "--format=%h%x1f%s%x1f%b%x1e", rangeArg)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		body := ""
+		if len(fields) == 3 {
+			body = fields[2]
+		}
+		commits = append(commits, ParseCommit(fields[0], fields[1], body))
+	}
+	return commits, nil
+}
+
+// SuggestNextVersion returns the next semantic version after latest based on
+// the commits: major for breaking changes, minor for features, patch otherwise.
+// Before 1.0.0, breaking changes bump the minor version. An empty latest is
+// treated as v0.0.0. The "v" prefix of latest is preserved, and a latest
+// prerelease is followed by its release when that bumps enough, as with
+// semver.Bump.
+func SuggestNextVersion(latest string, commits []Commit) (string, error) {
+	if latest == "" {
+		latest = "v0.0.0"
+	}
+	major, _, _, err := semver.Split(latest)
+	if err != nil {
+		return "", err
+	}
+
+	var breaking, feature bool
+	for _, c := range commits {
+		if c.Breaking {
+			breaking = true
+		}
+		if c.Type == "feat" {
+			feature = true
+		}
+	}
+
+	part := semver.Patch
+	switch {
+	case breaking && major > 0:
+		part = semver.Major
+	case breaking || feature:
+		part = semver.Minor
+	}
+	return semver.Bump(latest, part)
+}
@@ -0,0 +1,74 @@
+package git
+
+import "testing"
+
+func TestParseCommit(t *testing.T) {
+	tests := []struct {
+		subject      string
+		body         string
+		wantType     string
+		wantScope    string
+		wantBreaking bool
+	}{
+		{"feat: add status command", "", "feat", "", false},
+		{"fix(git): handle empty tags", "", "fix", "git", false},
+		{"feat(api)!: drop v1 endpoints", "", "feat", "api", true},
+		{"refactor: rework runner", "BREAKING CHANGE: Runner.Run signature changed", "refactor", "", true},
+		{"Update README", "", "other", "", false},
+		{"Merge branch 'main'", "", "other", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			c := ParseCommit("abc123", tt.subject, tt.body)
+			if c.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", c.Type, tt.wantType)
+			}
+			if c.Scope != tt.wantScope {
+				t.Errorf("Scope = %q, want %q", c.Scope, tt.wantScope)
+			}
+			if c.Breaking != tt.wantBreaking {
+				t.Errorf("Breaking = %v, want %v", c.Breaking, tt.wantBreaking)
+			}
+		})
+	}
+}
+
+func TestSuggestNextVersion(t *testing.T) {
+	fix := Commit{Type: "fix"}
+	feat := Commit{Type: "feat"}
+	breaking := Commit{Type: "feat", Breaking: true}
+
+	tests := []struct {
+		name    string
+		latest  string
+		commits []Commit
+		want    string
+	}{
+		{"patch", "v1.2.3", []Commit{fix}, "v1.2.4"},
+		{"minor", "v1.2.3", []Commit{fix, feat}, "v1.3.0"},
+		{"major", "v1.2.3", []Commit{breaking}, "v2.0.0"},
+		{"breaking before 1.0", "v0.4.1", []Commit{breaking}, "v0.5.0"},
+		{"no prefix", "1.2.3", []Commit{fix}, "1.2.4"},
+		{"prerelease", "v1.2.3-rc.1", []Commit{fix}, "v1.2.3"},
+		{"prerelease minor", "v1.3.0-rc.1", []Commit{feat}, "v1.3.0"},
+		{"prerelease past minor", "v1.2.3-rc.1", []Commit{feat}, "v1.3.0"},
+		{"no tags", "", []Commit{feat}, "v0.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SuggestNextVersion(tt.latest, tt.commits)
+			if err != nil {
+				t.Fatalf("SuggestNextVersion() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SuggestNextVersion(%q) = %q, want %q", tt.latest, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SuggestNextVersion("not-a-version", nil); err == nil {
+		t.Error("SuggestNextVersion(invalid) error = nil, want error")
+	}
+}
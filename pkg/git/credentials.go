@@ -0,0 +1,156 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// Credential describes what the GitHub credential used by gh may do in
+// the repository. It is what GitHub API calls use, not necessarily what
+// git pushes with; see CheckPush for that.
+type Credential struct {
+	Repository string   // owner/repo probed
+	Push       bool     // The credential can push to the repository (contents:write)
+	Scopes     []string // OAuth scopes of a classic token
+	Classic    bool     // The token reports OAuth scopes; fine-grained and app tokens don't
+}
+
+// CheckPush verifies that git can authenticate to the remote to push the
+// current branch, with whatever credential git itself uses, such as an SSH
+// key or a credential helper. It runs git push --dry-run, which negotiates
+// with the remote but sends nothing, without prompting for credentials. A
+// push the remote would reject as non-fast-forward still passes; only
+// failing to connect or authenticate is an error.
+func (g *Git) CheckPush() error {
+	branch, err := g.CurrentBranch()
+	if err != nil {
+		return err
+	}
+	args := []string{"push", "--dry-run", "--porcelain", g.Remote, "HEAD:refs/heads/" + branch}
+	cmd := proc.Command("git", args...)
+	cmd.Dir = g.Dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	traceCommand(g.Dir, "git", args, start, err)
+	// With --porcelain, "To <url>" is printed once the remote accepted the
+	// connection, before any ref is rejected
+	if err != nil && !strings.HasPrefix(stdout.String(), "To ") {
+		return fmt.Errorf("%s: %w: %s", commandString("git", args), err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// IsGitHub reports whether the remote is a repository on github.com or
+// the GitHub Enterprise Server host of BaseURL.
+func (g *Git) IsGitHub() bool {
+	_, _, err := g.parseRemoteURL()
+	return err == nil
+}
+
+// GHAuthenticated reports whether gh is logged in to the remote's host.
+func (g *Git) GHAuthenticated() bool {
+	if !commandExists("gh") {
+		return false
+	}
+	_, err := g.runGH("auth", "status", "--hostname", g.Host())
+	return err == nil
+}
+
+// Credential probes the GitHub API with gh's credential to find out what
+// it may do in the repository of the remote.
+func (g *Git) Credential() (*Credential, error) {
+	if !commandExists("gh") {
+		return nil, proc.NotFound("gh CLI")
+	}
+	owner, repo, err := g.parseRemoteURL()
+	if err != nil {
+		return nil, err
+	}
+	output, err := g.runGH("api", "--include", fmt.Sprintf("repos/%s/%s", owner, repo))
+	if err != nil {
+		return nil, err
+	}
+	c, err := parseCredential(output)
+	if err != nil {
+		return nil, err
+	}
+	c.Repository = owner + "/" + repo
+	return c, nil
+}
+
+// parseCredential reads the response headers and repository body of
+// "gh api --include repos/owner/repo".
+func parseCredential(output string) (*Credential, error) {
+	c := &Credential{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	var body strings.Builder
+	inBody := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if inBody {
+			body.WriteString(line)
+			continue
+		}
+		if line == "" {
+			inBody = true
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(name), "X-OAuth-Scopes") {
+			c.Classic = true
+			for _, s := range strings.Split(value, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					c.Scopes = append(c.Scopes, s)
+				}
+			}
+		}
+	}
+
+	var repo struct {
+		Permissions struct {
+			Push bool `json:"push"`
+		} `json:"permissions"`
+	}
+	if err := json.Unmarshal([]byte(body.String()), &repo); err != nil {
+		return nil, fmt.Errorf("parsing repository response: %w", err)
+	}
+	c.Push = repo.Permissions.Push
+	return c, nil
+}
+
+// HasScope reports whether a classic token has scope.
+func (c *Credential) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Missing describes what the credential lacks to push commits, workflow
+// changes, and tags: pushing needs contents:write, and a classic token
+// needs the workflow scope. Fine-grained and app tokens don't report
+// their workflow permission, so it can't be verified before pushing.
+func (c *Credential) Missing() []string {
+	var missing []string
+	if !c.Push {
+		missing = append(missing, fmt.Sprintf("contents:write: the credential can't push to %s", c.Repository))
+	}
+	if c.Classic && !c.HasScope("workflow") {
+		missing = append(missing, fmt.Sprintf("workflow: the token has scopes [%s]; run 'gh auth refresh -s workflow'", strings.Join(c.Scopes, ", ")))
+	}
+	return missing
+}
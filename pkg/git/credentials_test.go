@@ -0,0 +1,49 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCredential(t *testing.T) {
+	output := "HTTP/2.0 200 OK\r\nContent-Type: application/json\r\nX-Oauth-Scopes: repo, read:org\r\n\r\n" +
+		`{"full_name":"acme/app","permissions":{"admin":false,"push":true,"pull":true}}`
+
+	c, err := parseCredential(output)
+	if err != nil {
+		t.Fatalf("parseCredential failed: %v", err)
+	}
+	if !c.Classic || !c.Push {
+		t.Errorf("Classic = %v, Push = %v; want both", c.Classic, c.Push)
+	}
+	if !c.HasScope("repo") || c.HasScope("workflow") {
+		t.Errorf("Scopes = %v", c.Scopes)
+	}
+
+	c.Repository = "acme/app"
+	missing := c.Missing()
+	if len(missing) != 1 || !strings.HasPrefix(missing[0], "workflow:") {
+		t.Errorf("Missing() = %v, want workflow", missing)
+	}
+}
+
+func TestParseCredential_FineGrained(t *testing.T) {
+	output := "HTTP/2.0 200 OK\nContent-Type: application/json\n\n" + `{"permissions":{"push":false,"pull":true}}`
+
+	c, err := parseCredential(output)
+	if err != nil {
+		t.Fatalf("parseCredential failed: %v", err)
+	}
+	if c.Classic {
+		t.Error("expected a token without scopes header to be fine-grained")
+	}
+	c.Repository = "acme/app"
+	missing := c.Missing()
+	if len(missing) != 1 || !strings.Contains(missing[0], "can't push to acme/app") {
+		t.Errorf("Missing() = %v, want contents:write only", missing)
+	}
+
+	if _, err := parseCredential("HTTP/2.0 200 OK\n\nnot json"); err == nil {
+		t.Error("expected an error for an unparsable body")
+	}
+}
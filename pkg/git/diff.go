@@ -0,0 +1,57 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UpstreamRef returns the ref to compare the current branch against: the
+// branch's upstream if set, otherwise the remote's default branch.
+func (g *Git) UpstreamRef() (string, error) {
+	if output, err := g.run("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		return strings.TrimSpace(output), nil
+	}
+	ref, err := g.DefaultBranchRef()
+	if err != nil {
+		return "", fmt.Errorf("no upstream branch configured and %w", err)
+	}
+	return ref, nil
+}
+
+// DefaultBranchRef returns the remote-tracking ref of the remote's default
+// branch, e.g. "origin/main".
+func (g *Git) DefaultBranchRef() (string, error) {
+	if output, err := g.run("symbolic-ref", "--short", "refs/remotes/"+g.Remote+"/HEAD"); err == nil {
+		return strings.TrimSpace(output), nil
+	}
+	for _, branch := range []string{"main", "master"} {
+		ref := g.Remote + "/" + branch
+		if g.RefExists(ref) {
+			return ref, nil
+		}
+	}
+	return "", fmt.Errorf("%s default branch not found", g.Remote)
+}
+
+// MergeBase returns the best common ancestor of two refs.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	output, err := g.run("merge-base", a, b)
+	if err != nil {
+		return "", fmt.Errorf("no merge base between %s and %s: %w", a, b, err)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// ChangedFiles returns files added, copied, modified or renamed on HEAD
+// since it diverged from base.
+func (g *Git) ChangedFiles(base string) ([]string, error) {
+	output, err := g.run("diff", "--name-only", "--diff-filter=ACMR", base+"...HEAD")
+	if err != nil {
+		return nil, err
+	}
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
@@ -0,0 +1,74 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeBase(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+
+	run("commit", "--allow-empty", "-m", "base")
+	base := run("rev-parse", "HEAD")
+	main := run("rev-parse", "--abbrev-ref", "HEAD")
+	run("checkout", "-b", "feature")
+	run("commit", "--allow-empty", "-m", "feature change")
+	run("checkout", main)
+	run("commit", "--allow-empty", "-m", "main change")
+
+	g := New(tmpDir)
+	got, err := g.MergeBase(main, "feature")
+	if err != nil {
+		t.Fatalf("MergeBase() error: %v", err)
+	}
+	if got != base {
+		t.Errorf("MergeBase() = %s, want %s", got, base)
+	}
+
+	if _, err := g.MergeBase(main, "missing"); err == nil {
+		t.Error("MergeBase() with a missing ref: want error")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("kept.txt", "kept\n")
+	write("modified.txt", "base\n")
+	write("deleted.txt", "base\n")
+	run("add", "-A")
+	run("commit", "-m", "base")
+	run("branch", "base")
+
+	g := New(tmpDir)
+	changed, err := g.ChangedFiles("base")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error: %v", err)
+	}
+	if changed != nil {
+		t.Errorf("ChangedFiles() without changes = %v, want none", changed)
+	}
+
+	// Deleted files aren't reported
+	write("modified.txt", "changed\n")
+	write("added.txt", "new\n")
+	run("rm", "-q", "deleted.txt")
+	run("add", "-A")
+	run("commit", "-m", "change")
+
+	changed, err = g.ChangedFiles("base")
+	if err != nil {
+		t.Fatalf("ChangedFiles() error: %v", err)
+	}
+	if want := []string{"added.txt", "modified.txt"}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("ChangedFiles() = %v, want %v", changed, want)
+	}
+}
@@ -3,16 +3,28 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+	"github.com/plexusone/agent-team-release/pkg/semver"
 )
 
 // Git provides git operations for a repository.
 type Git struct {
 	Dir    string // Repository directory
 	Remote string // Remote name (default: origin)
+
+	// goGit serves read-only operations with go-git instead of the git
+	// binary. Set when git is not installed.
+	goGit bool
+
+	// host caches the GitHub host of the remote; see Host.
+	host string
 }
 
 // New creates a new Git instance for the given directory.
@@ -20,6 +32,7 @@ func New(dir string) *Git {
 	return &Git{
 		Dir:    dir,
 		Remote: "origin",
+		goGit:  !commandExists("git"),
 	}
 }
 
@@ -38,6 +51,9 @@ type Status struct {
 
 // LatestTag returns the most recent tag reachable from HEAD.
 func (g *Git) LatestTag() (string, error) {
+	if g.goGit {
+		return g.goGitLatestTag()
+	}
 	output, err := g.run("describe", "--tags", "--abbrev=0")
 	if err != nil {
 		return "", fmt.Errorf("no tags found: %w", err)
@@ -45,8 +61,12 @@ func (g *Git) LatestTag() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
-// AllTags returns all tags in the repository, sorted by version.
+// AllTags returns all tags in the repository, newest semantic version
+// first, followed by other tags in git's version order.
 func (g *Git) AllTags() ([]string, error) {
+	if g.goGit {
+		return g.goGitAllTags()
+	}
 	output, err := g.run("tag", "--sort=-version:refname")
 	if err != nil {
 		return nil, err
@@ -54,11 +74,30 @@ func (g *Git) AllTags() ([]string, error) {
 	if output == "" {
 		return nil, nil
 	}
-	return strings.Split(strings.TrimSpace(output), "\n"), nil
+	tags := strings.Split(strings.TrimSpace(output), "\n")
+	semver.SortNewestFirst(tags)
+	return tags, nil
+}
+
+// TagDate returns the commit date of the commit a tag points to.
+func (g *Git) TagDate(tag string) (time.Time, error) {
+	if g.goGit {
+		return g.goGitTagDate(tag)
+	}
+	output, err := g.run("log", "-1", "--format=%cI", tag+"^{commit}")
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(output))
 }
 
 // CreateTag creates a new tag at HEAD.
 func (g *Git) CreateTag(tag string, message string, sign bool) error {
+	return g.CreateTagAt(tag, "", message, sign)
+}
+
+// CreateTagAt creates a new tag at ref, or at HEAD if ref is empty.
+func (g *Git) CreateTagAt(tag, ref, message string, sign bool) error {
 	args := []string{"tag"}
 	if sign {
 		args = append(args, "-s")
@@ -67,6 +106,9 @@ func (g *Git) CreateTag(tag string, message string, sign bool) error {
 		args = append(args, "-m", message)
 	}
 	args = append(args, tag)
+	if ref != "" {
+		args = append(args, ref)
+	}
 
 	_, err := g.run(args...)
 	if err != nil {
@@ -75,6 +117,15 @@ func (g *Git) CreateTag(tag string, message string, sign bool) error {
 	return nil
 }
 
+// RemoteTagExists reports whether the tag exists on the remote.
+func (g *Git) RemoteTagExists(tag string) (bool, error) {
+	output, err := g.run("ls-remote", "--tags", g.Remote, "refs/tags/"+tag)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(output) != "", nil
+}
+
 // DeleteTag deletes a local tag.
 func (g *Git) DeleteTag(tag string) error {
 	_, err := g.run("tag", "-d", tag)
@@ -153,6 +204,10 @@ func (g *Git) Commit(message string, sign bool) error {
 
 // Status returns the current git status.
 func (g *Git) Status() (*Status, error) {
+	if g.goGit {
+		return g.goGitStatus()
+	}
+
 	status := &Status{}
 
 	// Get branch name
@@ -237,6 +292,9 @@ func (s *Status) parseBranchLine(line string) {
 
 // IsDirty returns true if there are uncommitted changes.
 func (g *Git) IsDirty() (bool, error) {
+	if g.goGit {
+		return g.goGitIsDirty()
+	}
 	output, err := g.run("status", "--porcelain")
 	if err != nil {
 		return false, err
@@ -246,6 +304,9 @@ func (g *Git) IsDirty() (bool, error) {
 
 // CurrentBranch returns the current branch name.
 func (g *Git) CurrentBranch() (string, error) {
+	if g.goGit {
+		return g.goGitCurrentBranch()
+	}
 	output, err := g.run("rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return "", err
@@ -255,6 +316,9 @@ func (g *Git) CurrentBranch() (string, error) {
 
 // CurrentCommit returns the current commit SHA.
 func (g *Git) CurrentCommit() (string, error) {
+	if g.goGit {
+		return g.goGitCurrentCommit()
+	}
 	output, err := g.run("rev-parse", "HEAD")
 	if err != nil {
 		return "", err
@@ -264,6 +328,13 @@ func (g *Git) CurrentCommit() (string, error) {
 
 // ShortCommit returns the short form of the current commit SHA.
 func (g *Git) ShortCommit() (string, error) {
+	if g.goGit {
+		sha, err := g.goGitCurrentCommit()
+		if err != nil {
+			return "", err
+		}
+		return sha[:7], nil
+	}
 	output, err := g.run("rev-parse", "--short", "HEAD")
 	if err != nil {
 		return "", err
@@ -271,8 +342,23 @@ func (g *Git) ShortCommit() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// TopLevel returns the absolute path of the repository's working tree root.
+func (g *Git) TopLevel() (string, error) {
+	if g.goGit {
+		return g.goGitTopLevel()
+	}
+	output, err := g.run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
 // RemoteURL returns the URL of the remote.
 func (g *Git) RemoteURL() (string, error) {
+	if g.goGit {
+		return g.goGitRemoteURL()
+	}
 	output, err := g.run("remote", "get-url", g.Remote)
 	if err != nil {
 		return "", err
@@ -280,12 +366,22 @@ func (g *Git) RemoteURL() (string, error) {
 	return strings.TrimSpace(output), nil
 }
 
+// UserName returns the configured user.name.
+func (g *Git) UserName() (string, error) {
+	output, err := g.run("config", "user.name")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
 	if err != nil {
 		// Exit code 1 means not an ancestor, which is not an error
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
 			return false, nil
 		}
 		return false, err
@@ -299,6 +395,12 @@ func (g *Git) Fetch() error {
 	return err
 }
 
+// PullRebase pulls the upstream branch, rebasing local commits onto it.
+func (g *Git) PullRebase() error {
+	_, err := g.run("pull", "--rebase")
+	return err
+}
+
 // FetchTags fetches tags from the remote.
 func (g *Git) FetchTags() error {
 	_, err := g.run("fetch", "--tags", g.Remote)
@@ -320,20 +422,22 @@ func (g *Git) Log(from, to string, format string) (string, error) {
 
 // run executes a git command and returns the output.
 func (g *Git) run(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+	cmd := proc.Command("git", args...)
 	cmd.Dir = g.Dir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
+	start := time.Now()
 	err := cmd.Run()
+	traceCommand(g.Dir, "git", args, start, err)
 	if err != nil {
 		errMsg := stderr.String()
 		if errMsg == "" {
 			errMsg = stdout.String()
 		}
-		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(errMsg))
+		return "", fmt.Errorf("%s: %w: %s", commandString("git", args), err, strings.TrimSpace(errMsg))
 	}
 
 	return stdout.String(), nil
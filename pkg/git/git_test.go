@@ -4,7 +4,10 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -146,39 +149,151 @@ func TestCalculateOverallState(t *testing.T) {
 	}
 }
 
-// Integration tests that require a real git repo
-func TestGitIntegration(t *testing.T) {
-	// Skip if git is not available
-	if _, err := exec.LookPath("git"); err != nil {
-		t.Skip("git not found in PATH")
+func TestParseMergedPRs(t *testing.T) {
+	data := `[{"number":12,"title":"Add status","labels":[{"name":"feature"}],"mergeCommit":{"oid":"abc123"}},{"number":13,"title":"Docs","labels":[],"mergeCommit":null}]`
+	prs, err := parseMergedPRs([]byte(data))
+	if err != nil {
+		t.Fatalf("parseMergedPRs() error: %v", err)
+	}
+	want := []MergedPR{
+		{Number: 12, Title: "Add status", Labels: []string{"feature"}, MergeCommit: "abc123"},
+		{Number: 13, Title: "Docs"},
+	}
+	if !reflect.DeepEqual(prs, want) {
+		t.Errorf("parseMergedPRs() = %+v, want %+v", prs, want)
+	}
+}
+
+func TestParsePullRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		want   PullRequest
+		merged bool
+	}{
+		{
+			name: "open",
+			json: `{"number":12,"url":"https://github.com/o/r/pull/12","state":"OPEN","mergeCommit":null}`,
+			want: PullRequest{Number: 12, URL: "https://github.com/o/r/pull/12", State: "OPEN"},
+		},
+		{
+			name: "auto-merge",
+			json: `{"number":12,"url":"https://github.com/o/r/pull/12","state":"OPEN","autoMergeRequest":{"mergeMethod":"SQUASH"}}`,
+			want: PullRequest{Number: 12, URL: "https://github.com/o/r/pull/12", State: "OPEN", AutoMerge: true},
+		},
+		{
+			name:   "merged",
+			json:   `{"number":12,"url":"https://github.com/o/r/pull/12","state":"MERGED","mergeCommit":{"oid":"abc123"}}`,
+			want:   PullRequest{Number: 12, URL: "https://github.com/o/r/pull/12", State: "MERGED", MergeCommit: "abc123"},
+			merged: true,
+		},
 	}
 
-	// Create a temporary directory for testing
-	tmpDir, err := os.MkdirTemp("", "git-test-*")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePullRequest([]byte(tt.json))
+			if err != nil {
+				t.Fatalf("parsePullRequest() error: %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("parsePullRequest() = %+v, want %+v", *got, tt.want)
+			}
+			if got.IsMerged() != tt.merged {
+				t.Errorf("IsMerged() = %v, want %v", got.IsMerged(), tt.merged)
+			}
+		})
+	}
+}
+
+func TestParseMergeQueueEntry(t *testing.T) {
+	entry, err := parseMergeQueueEntry([]byte(`{"data":{"repository":{"pullRequest":{"mergeQueueEntry":{"state":"AWAITING_CHECKS","position":2,"headCommit":{"oid":"def456"}}}}}}`))
 	if err != nil {
-		t.Fatalf("Failed to create temp dir: %v", err)
+		t.Fatalf("parseMergeQueueEntry() error: %v", err)
+	}
+	if entry == nil || *entry != (MergeQueueEntry{State: "AWAITING_CHECKS", Position: 2, HeadCommit: "def456"}) {
+		t.Errorf("parseMergeQueueEntry() = %+v", entry)
 	}
-	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-	// Initialize a git repo
-	cmd := exec.Command("git", "init")
-	cmd.Dir = tmpDir
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to init git repo: %v", err)
+	entry, err = parseMergeQueueEntry([]byte(`{"data":{"repository":{"pullRequest":{"mergeQueueEntry":null}}}}`))
+	if err != nil || entry != nil {
+		t.Errorf("parseMergeQueueEntry(not queued) = %+v, %v; want nil, nil", entry, err)
 	}
+}
+
+func TestPRCIState(t *testing.T) {
+	open := &PullRequest{Number: 1, State: "OPEN"}
+	auto := &PullRequest{Number: 1, State: "OPEN", AutoMerge: true}
+	queued := &PullRequest{Number: 1, State: "OPEN", AutoMerge: true, MergeQueue: &MergeQueueEntry{State: "AWAITING_CHECKS", HeadCommit: "abc"}}
+	unmergeable := &PullRequest{Number: 1, State: "OPEN", MergeQueue: &MergeQueueEntry{State: "UNMERGEABLE"}}
 
-	// Configure git user for the test repo
-	cmd = exec.Command("git", "config", "user.email", "test@example.com")
-	cmd.Dir = tmpDir
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to set git email: %v", err)
+	tests := []struct {
+		name      string
+		pr        *PullRequest
+		status    string // "" for no status
+		wasQueued bool
+		wantDone  bool
+		wantErr   string
+	}{
+		{name: "pending", pr: open, status: "pending"},
+		{name: "checks pass", pr: open, status: "success", wantDone: true},
+		{name: "checks fail", pr: open, status: "failure", wantDone: true, wantErr: "CI failed"},
+		{name: "auto-merge waits for queue", pr: auto, status: "success"},
+		{name: "queue checks pending", pr: queued, status: "pending"},
+		{name: "queue checks pass", pr: queued, status: "success", wantDone: true},
+		{name: "queue checks fail", pr: queued, status: "failure", wantDone: true, wantErr: "merge queue checks failed"},
+		{name: "queued without merge group", pr: &PullRequest{State: "OPEN", MergeQueue: &MergeQueueEntry{State: "QUEUED"}}},
+		{name: "unmergeable", pr: unmergeable, wantDone: true, wantErr: "cannot be merged"},
+		{name: "removed from queue", pr: auto, status: "success", wasQueued: true, wantDone: true, wantErr: "removed from the merge queue"},
+		{name: "merged", pr: &PullRequest{State: "MERGED"}, wasQueued: true, wantDone: true},
+		{name: "closed", pr: &PullRequest{State: "CLOSED"}, wantDone: true, wantErr: "closed without merging"},
 	}
 
-	cmd = exec.Command("git", "config", "user.name", "Test User")
-	cmd.Dir = tmpDir
-	if err := cmd.Run(); err != nil {
-		t.Fatalf("Failed to set git name: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status *CIStatus
+			if tt.status != "" {
+				status = &CIStatus{State: tt.status}
+			}
+			done, err := prCIState(tt.pr, status, tt.wasQueued)
+			if done != tt.wantDone {
+				t.Errorf("done = %v, want %v", done, tt.wantDone)
+			}
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newTestRepo initializes a git repository with a committer identity in a
+// temporary directory, skipping the test if git isn't installed. run runs git
+// in the repository and returns its trimmed output, failing the test on error.
+func newTestRepo(t *testing.T) (dir string, run func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+
+	dir = t.TempDir()
+	run = func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
 	}
+	run("init")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "Test User")
+	return dir, run
+}
+
+// Integration tests that require a real git repo
+func TestGitIntegration(t *testing.T) {
+	tmpDir, _ := newTestRepo(t)
 
 	// Create a test file
 	testFile := filepath.Join(tmpDir, "test.txt")
@@ -210,6 +325,22 @@ func TestGitIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("TopLevel", func(t *testing.T) {
+		sub := filepath.Join(tmpDir, "sub")
+		if err := os.MkdirAll(sub, 0755); err != nil {
+			t.Fatalf("Failed to create subdir: %v", err)
+		}
+		top, err := New(sub).TopLevel()
+		if err != nil {
+			t.Fatalf("TopLevel() error: %v", err)
+		}
+		want, _ := filepath.EvalSymlinks(tmpDir)
+		got, _ := filepath.EvalSymlinks(top)
+		if got != want {
+			t.Errorf("TopLevel() = %s, want %s", got, want)
+		}
+	})
+
 	t.Run("CurrentBranch", func(t *testing.T) {
 		branch, err := g.CurrentBranch()
 		if err != nil {
@@ -266,6 +397,29 @@ func TestGitIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("ListFiles", func(t *testing.T) {
+		files, err := g.ListFiles("v0.1.0", ".")
+		if err != nil {
+			t.Fatalf("ListFiles() error: %v", err)
+		}
+		if len(files) != 1 || files[0] != "test.txt" {
+			t.Errorf("ListFiles() = %v, want [test.txt]", files)
+		}
+		if files, err := g.ListFiles("v0.1.0", "missing"); err != nil || files != nil {
+			t.Errorf("ListFiles(missing) = %v, %v, want none", files, err)
+		}
+	})
+
+	t.Run("TagDate", func(t *testing.T) {
+		date, err := g.TagDate("v0.1.0")
+		if err != nil {
+			t.Fatalf("TagDate() error: %v", err)
+		}
+		if time.Since(date) > time.Hour {
+			t.Errorf("TagDate() = %v, want a recent time", date)
+		}
+	})
+
 	t.Run("Status", func(t *testing.T) {
 		status, err := g.Status()
 		if err != nil {
@@ -276,3 +430,67 @@ func TestGitIntegration(t *testing.T) {
 		}
 	})
 }
+
+func TestCherryPickConflict(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("base\n")
+	run("add", "-A")
+	run("commit", "-m", "base")
+
+	g := New(tmpDir)
+	main, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.CreateBranch("release-1.x", ""); err != nil {
+		t.Fatalf("CreateBranch() error: %v", err)
+	}
+	write("release\n")
+	run("commit", "-am", "release change")
+
+	if err := g.Checkout(main); err != nil {
+		t.Fatalf("Checkout() error: %v", err)
+	}
+	write("main\n")
+	run("commit", "-am", "fix: main change")
+	fix, err := g.CurrentCommit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := g.CommitSubject(fix)
+	if err != nil || subject != "fix: main change" {
+		t.Errorf("CommitSubject() = %q, %v, want %q", subject, err, "fix: main change")
+	}
+	if !g.RefExists("release-1.x") || g.RefExists("release-9.x") {
+		t.Error("RefExists() returned wrong result")
+	}
+
+	if err := g.CreateBranch("backport", "release-1.x"); err != nil {
+		t.Fatalf("CreateBranch() error: %v", err)
+	}
+	if err := g.CherryPick(fix); err == nil {
+		t.Fatal("CherryPick() error = nil, want conflict")
+	}
+	files, err := g.ConflictedFiles()
+	if err != nil {
+		t.Fatalf("ConflictedFiles() error: %v", err)
+	}
+	if len(files) != 1 || files[0] != "file.txt" {
+		t.Errorf("ConflictedFiles() = %v, want [file.txt]", files)
+	}
+	if err := g.AbortCherryPick(); err != nil {
+		t.Fatalf("AbortCherryPick() error: %v", err)
+	}
+	if dirty, _ := g.IsDirty(); dirty {
+		t.Error("IsDirty() = true after abort, want false")
+	}
+}
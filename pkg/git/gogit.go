@@ -0,0 +1,355 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// This file implements read-only operations with go-git, used when no git
+// binary is available (e.g. minimal containers). Operations that write to
+// the repository or talk to remotes always use the git binary.
+
+func (g *Git) openRepo() (*gogit.Repository, error) {
+	repo, err := gogit.PlainOpenWithOptions(g.Dir, &gogit.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+	return repo, nil
+}
+
+// tagCommits returns a map of commit hash to the tags pointing at it,
+// peeling annotated tags.
+func tagCommits(repo *gogit.Repository) (map[plumbing.Hash][]string, error) {
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	commits := make(map[plumbing.Hash][]string)
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				// Tags of non-commit objects are ignored
+				return nil
+			}
+			hash = commit.Hash
+		}
+		commits[hash] = append(commits[hash], ref.Name().Short())
+		return nil
+	})
+	return commits, err
+}
+
+func (g *Git) goGitAllTags() ([]string, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return nil, err
+	}
+	iter, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		tags = append(tags, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Match git tag --sort=-version:refname, as AllTags does
+	sort.Slice(tags, func(i, j int) bool {
+		return versionRefLess(tags[j], tags[i])
+	})
+	semver.SortNewestFirst(tags)
+	return tags, nil
+}
+
+func (g *Git) goGitLatestTag() (string, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return "", err
+	}
+	tagged, err := tagCommits(repo)
+	if err != nil {
+		return "", err
+	}
+	head, err := headCommit(repo)
+	if err != nil {
+		return "", err
+	}
+
+	// Breadth-first from HEAD finds the nearest tagged ancestor, as git
+	// describe does for linear history.
+	var latest string
+	err = object.NewCommitIterBSF(head, nil, nil).ForEach(func(c *object.Commit) error {
+		if tags, ok := tagged[c.Hash]; ok {
+			sort.Slice(tags, func(i, j int) bool { return versionRefLess(tags[j], tags[i]) })
+			latest = tags[0]
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("no tags found: %w", err)
+	}
+	if latest == "" {
+		return "", errors.New("no tags found")
+	}
+	return latest, nil
+}
+
+func (g *Git) goGitTagDate(tag string) (time.Time, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return time.Time{}, err
+	}
+	commit, err := resolveCommit(repo, tag)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return commit.Committer.When, nil
+}
+
+func (g *Git) goGitCurrentBranch() (string, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	if head.Name().IsBranch() {
+		return head.Name().Short(), nil
+	}
+	return "HEAD", nil
+}
+
+func (g *Git) goGitCurrentCommit() (string, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Hash().String(), nil
+}
+
+func (g *Git) goGitTopLevel() (string, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return "", err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	return wt.Filesystem.Root(), nil
+}
+
+func (g *Git) goGitRemoteURL() (string, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return "", err
+	}
+	remote, err := repo.Remote(g.Remote)
+	if err != nil {
+		return "", err
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", fmt.Errorf("remote %s has no URL", g.Remote)
+	}
+	return urls[0], nil
+}
+
+func (g *Git) goGitStatus() (*Status, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return nil, err
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	files, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{}
+	status.Branch, err = g.goGitCurrentBranch()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fs := files[path]
+		switch {
+		case fs.Staging == gogit.Untracked:
+			status.Untracked = append(status.Untracked, path)
+		case fs.Staging != gogit.Unmodified:
+			status.Staged = append(status.Staged, path)
+		case fs.Worktree != gogit.Unmodified:
+			status.Modified = append(status.Modified, path)
+		}
+	}
+	status.IsClean = len(status.Staged) == 0 && len(status.Modified) == 0
+
+	// Tracking branch and ahead/behind counts
+	if branch, err := repo.Branch(status.Branch); err == nil && branch.Remote != "" && branch.Merge != "" {
+		status.HasRemote = true
+		status.RemoteBranch = branch.Remote + "/" + branch.Merge.Short()
+		local, err1 := headCommit(repo)
+		remote, err2 := resolveCommit(repo, status.RemoteBranch)
+		if err1 == nil && err2 == nil {
+			status.Ahead, _ = countExclusive(local, remote)
+			status.Behind, _ = countExclusive(remote, local)
+		}
+	}
+
+	return status, nil
+}
+
+func (g *Git) goGitIsDirty() (bool, error) {
+	status, err := g.goGitStatus()
+	if err != nil {
+		return false, err
+	}
+	// Like git status --porcelain, untracked files count as changes
+	return !status.IsClean || len(status.Untracked) > 0, nil
+}
+
+func (g *Git) goGitCommitsSince(ref string) ([]Commit, error) {
+	repo, err := g.openRepo()
+	if err != nil {
+		return nil, err
+	}
+	head, err := headCommit(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var exclude map[plumbing.Hash]bool
+	if ref != "" {
+		base, err := resolveCommit(repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		exclude, err = ancestors(base)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var commits []Commit
+	err = object.NewCommitIterCTime(head, exclude, nil).ForEach(func(c *object.Commit) error {
+		subject, body, _ := strings.Cut(c.Message, "\n")
+		commits = append(commits, ParseCommit(c.Hash.String()[:7], strings.TrimSpace(subject), strings.TrimSpace(body)))
+		return nil
+	})
+	return commits, err
+}
+
+func headCommit(repo *gogit.Repository) (*object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	return repo.CommitObject(head.Hash())
+}
+
+func resolveCommit(repo *gogit.Repository, rev string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("commit %s not found: %w", rev, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err == nil {
+		return commit, nil
+	}
+	// Annotated tags resolve to the tag object
+	tag, tagErr := repo.TagObject(*hash)
+	if tagErr != nil {
+		return nil, err
+	}
+	return tag.Commit()
+}
+
+// ancestors returns the set of commits reachable from c, including c.
+func ancestors(c *object.Commit) (map[plumbing.Hash]bool, error) {
+	seen := make(map[plumbing.Hash]bool)
+	err := object.NewCommitPreorderIter(c, nil, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	return seen, err
+}
+
+// countExclusive counts the commits reachable from a but not from b.
+func countExclusive(a, b *object.Commit) (int, error) {
+	exclude, err := ancestors(b)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	err = object.NewCommitPreorderIter(a, exclude, nil).ForEach(func(c *object.Commit) error {
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// versionRefLess orders refs like git's version:refname sort: numeric
+// runs are compared numerically, everything else lexically.
+func versionRefLess(a, b string) bool {
+	for a != "" && b != "" {
+		na, ra := leadingNumber(a)
+		nb, rb := leadingNumber(b)
+		if na != "" && nb != "" {
+			ia, _ := strconv.Atoi(na)
+			ib, _ := strconv.Atoi(nb)
+			if ia != ib {
+				return ia < ib
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+func leadingNumber(s string) (string, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,91 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVersionRefLess(t *testing.T) {
+	tags := []string{"v0.10.0", "v0.2.0", "v1.0.0", "v0.9.1", "v0.9.0"}
+	want := []string{"v0.2.0", "v0.9.0", "v0.9.1", "v0.10.0", "v1.0.0"}
+	for i := 0; i < len(tags); i++ {
+		for j := i + 1; j < len(tags); j++ {
+			if versionRefLess(tags[j], tags[i]) {
+				tags[i], tags[j] = tags[j], tags[i]
+			}
+		}
+	}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("sorted = %v, want %v", tags, want)
+	}
+}
+
+// TestGoGitMatchesExec checks that the go-git read-only backend returns the
+// same results as the git binary.
+func TestGoGitMatchesExec(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("a.txt", "a\n")
+	run("add", "-A")
+	run("commit", "-m", "feat: initial")
+	run("tag", "v0.9.0")
+	write("a.txt", "b\n")
+	run("commit", "-am", "fix: change a")
+	run("tag", "-a", "v0.10.0", "-m", "Release v0.10.0")
+	write("b.txt", "b\n")
+	run("add", "-A")
+	run("commit", "-m", "feat!: add b\n\nBREAKING CHANGE: new file")
+
+	// Working tree changes of each kind
+	write("a.txt", "modified\n")
+	write("staged.txt", "staged\n")
+	run("add", "staged.txt")
+	write("untracked.txt", "untracked\n")
+
+	exe := &Git{Dir: tmpDir, Remote: "origin"}
+	native := &Git{Dir: tmpDir, Remote: "origin", goGit: true}
+
+	compare := func(name string, f func(g *Git) (any, error)) {
+		t.Helper()
+		want, err := f(exe)
+		if err != nil {
+			t.Fatalf("%s (exec) error: %v", name, err)
+		}
+		got, err := f(native)
+		if err != nil {
+			t.Fatalf("%s (go-git) error: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: go-git = %#v, exec = %#v", name, got, want)
+		}
+	}
+
+	compare("AllTags", func(g *Git) (any, error) { return g.AllTags() })
+	compare("LatestTag", func(g *Git) (any, error) { return g.LatestTag() })
+	compare("CurrentBranch", func(g *Git) (any, error) { return g.CurrentBranch() })
+	compare("CurrentCommit", func(g *Git) (any, error) { return g.CurrentCommit() })
+	compare("ShortCommit", func(g *Git) (any, error) { return g.ShortCommit() })
+	compare("IsDirty", func(g *Git) (any, error) { return g.IsDirty() })
+	compare("Status", func(g *Git) (any, error) { return g.Status() })
+	compare("CommitsSince", func(g *Git) (any, error) { return g.CommitsSince("v0.9.0") })
+	compare("CommitsSince all", func(g *Git) (any, error) { return g.CommitsSince("") })
+	compare("TagDate", func(g *Git) (any, error) {
+		d, err := g.TagDate("v0.10.0")
+		return d.Unix(), err
+	})
+	compare("TopLevel", func(g *Git) (any, error) {
+		dir, err := g.TopLevel()
+		if err != nil {
+			return nil, err
+		}
+		return filepath.EvalSymlinks(dir)
+	})
+}
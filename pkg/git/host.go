@@ -0,0 +1,110 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// DefaultHost is the GitHub host used when a remote doesn't name another.
+const DefaultHost = "github.com"
+
+// BaseURL is the base URL of the GitHub Enterprise Server instance
+// repositories are hosted on, e.g. "https://github.example.com". Remotes
+// on its host are recognized as GitHub repositories and gh is pointed at
+// it. Empty recognizes github.com only.
+var BaseURL string
+
+var (
+	// git@host:owner/repo.git
+	scpRemoteRegex = regexp.MustCompile(`^[^@/]+@([^:/]+):([^/]+)/(.+?)(?:\.git)?/?$`)
+	// https://host/owner/repo.git, ssh://git@host:22/owner/repo.git
+	urlRemoteRegex = regexp.MustCompile(`^(?:https?|ssh|git)://(?:[^@/]+@)?([^:/]+)(?::\d+)?/([^/]+)/(.+?)(?:\.git)?/?$`)
+)
+
+// enterpriseHost returns the host of BaseURL, or "" when it isn't set.
+func enterpriseHost() string {
+	if BaseURL == "" {
+		return ""
+	}
+	u, err := url.Parse(BaseURL)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(BaseURL, "/")
+	}
+	return u.Hostname()
+}
+
+// parseRemote extracts the GitHub host, owner, and repository from a remote
+// URL in scp-like ssh, ssh://, or https:// form. The host must be
+// github.com or the host of BaseURL.
+func parseRemote(remote string) (host, owner, repo string, err error) {
+	m := scpRemoteRegex.FindStringSubmatch(remote)
+	if m == nil {
+		m = urlRemoteRegex.FindStringSubmatch(remote)
+	}
+	if m == nil {
+		return "", "", "", fmt.Errorf("could not parse GitHub URL: %s", remote)
+	}
+	host = strings.ToLower(m[1])
+	if host != DefaultHost && host != enterpriseHost() {
+		return "", "", "", fmt.Errorf("remote %s isn't on %s; set network.github_url for GitHub Enterprise Server", remote, DefaultHost)
+	}
+	return host, m[2], m[3], nil
+}
+
+// parseRemoteURL extracts owner and repo from the remote URL.
+func (g *Git) parseRemoteURL() (owner string, repo string, err error) {
+	_, owner, repo, err = g.parseRemoteHost()
+	return owner, repo, err
+}
+
+// parseRemoteHost extracts the GitHub host, owner, and repo from the remote
+// URL.
+func (g *Git) parseRemoteHost() (host, owner, repo string, err error) {
+	remote, err := g.RemoteURL()
+	if err != nil {
+		return "", "", "", err
+	}
+	return parseRemote(remote)
+}
+
+// Host returns the GitHub host of the remote, e.g. "github.com" or a GitHub
+// Enterprise Server host.
+func (g *Git) Host() string {
+	if g.host == "" {
+		g.host = DefaultHost
+		if host, _, _, err := g.parseRemoteHost(); err == nil {
+			g.host = host
+		}
+	}
+	return g.host
+}
+
+// APIURL returns the REST API base URL for the remote's host:
+// https://api.github.com for github.com and https://<host>/api/v3 for
+// GitHub Enterprise Server.
+func (g *Git) APIURL() string {
+	host := g.Host()
+	if host == DefaultHost {
+		return "https://api.github.com"
+	}
+	if h := enterpriseHost(); h == host && strings.Contains(BaseURL, "://") {
+		return strings.TrimSuffix(BaseURL, "/") + "/api/v3"
+	}
+	return "https://" + host + "/api/v3"
+}
+
+// ghHostArgs returns args and extra environment that point gh at the
+// remote's host when it isn't github.com: GH_HOST for commands that resolve
+// the repository, and --hostname for gh api.
+func (g *Git) ghHostArgs(args []string) ([]string, []string) {
+	host := g.Host()
+	if host == DefaultHost {
+		return args, nil
+	}
+	if len(args) > 0 && args[0] == "api" {
+		args = append([]string{"api", "--hostname", host}, args[1:]...)
+	}
+	return args, []string{"GH_HOST=" + host}
+}
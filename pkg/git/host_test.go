@@ -0,0 +1,58 @@
+package git
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseRemote(t *testing.T) {
+	BaseURL = "https://github.example.com"
+	t.Cleanup(func() { BaseURL = "" })
+
+	tests := []struct {
+		remote string
+		host   string
+		owner  string
+		repo   string
+	}{
+		{"git@github.com:owner/repo.git", "github.com", "owner", "repo"},
+		{"https://github.com/owner/repo", "github.com", "owner", "repo"},
+		{"https://github.example.com/team/tool.git", "github.example.com", "team", "tool"},
+		{"git@github.example.com:team/tool.git", "github.example.com", "team", "tool"},
+		{"ssh://git@github.example.com:2222/team/tool.git", "github.example.com", "team", "tool"},
+	}
+	for _, tt := range tests {
+		host, owner, repo, err := parseRemote(tt.remote)
+		if err != nil {
+			t.Errorf("parseRemote(%q) error: %v", tt.remote, err)
+			continue
+		}
+		if host != tt.host || owner != tt.owner || repo != tt.repo {
+			t.Errorf("parseRemote(%q) = %s, %s, %s; want %s, %s, %s", tt.remote, host, owner, repo, tt.host, tt.owner, tt.repo)
+		}
+	}
+
+	if _, _, _, err := parseRemote("git@gitlab.com:owner/repo.git"); err == nil {
+		t.Error("expected an error for a remote on another host")
+	}
+}
+
+func TestGHHostArgs(t *testing.T) {
+	g := &Git{host: DefaultHost}
+	args, env := g.ghHostArgs([]string{"api", "repos/o/r"})
+	if !slices.Equal(args, []string{"api", "repos/o/r"}) || env != nil {
+		t.Errorf("github.com: args = %v, env = %v", args, env)
+	}
+
+	g = &Git{host: "github.example.com"}
+	args, env = g.ghHostArgs([]string{"api", "repos/o/r"})
+	if want := []string{"api", "--hostname", "github.example.com", "repos/o/r"}; !slices.Equal(args, want) {
+		t.Errorf("args = %v, want %v", args, want)
+	}
+	if !slices.Equal(env, []string{"GH_HOST=github.example.com"}) {
+		t.Errorf("env = %v", env)
+	}
+	if got := g.APIURL(); got != "https://github.example.com/api/v3" {
+		t.Errorf("APIURL() = %q", got)
+	}
+}
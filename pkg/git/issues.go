@@ -0,0 +1,237 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// Issue is a GitHub issue.
+type Issue struct {
+	Number int      `json:"number"`
+	Title  string   `json:"title"`
+	URL    string   `json:"url"`
+	State  string   `json:"state"` // OPEN or CLOSED
+	Labels []string `json:"labels"`
+}
+
+// IsClosed reports whether the issue is closed.
+func (i Issue) IsClosed() bool {
+	return strings.EqualFold(i.State, "closed")
+}
+
+// HasLabel reports whether the issue has the named label.
+func (i Issue) HasLabel(name string) bool {
+	for _, l := range i.Labels {
+		if l == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ListOpenIssues returns up to limit open issues using the gh CLI.
+func (g *Git) ListOpenIssues(limit int) ([]Issue, error) {
+	return g.ListIssues("open", limit)
+}
+
+// ListIssues returns up to limit issues in the given state ("open",
+// "closed", or "all") using the gh CLI.
+func (g *Git) ListIssues(state string, limit int) ([]Issue, error) {
+	if !commandExists("gh") {
+		return nil, proc.NotFound("gh CLI")
+	}
+
+	output, err := g.runGH("issue", "list", "--state", state,
+		"--limit", strconv.Itoa(limit), "--json", "number,title,url,state,labels")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	var raw []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		URL    string `json:"url"`
+		State  string `json:"state"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	if err := json.Unmarshal([]byte(output), &raw); err != nil {
+		return nil, err
+	}
+
+	issues := make([]Issue, 0, len(raw))
+	for _, r := range raw {
+		issue := Issue{Number: r.Number, Title: r.Title, URL: r.URL, State: r.State}
+		for _, l := range r.Labels {
+			issue.Labels = append(issue.Labels, l.Name)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// MergedPR is a merged GitHub pull request.
+type MergedPR struct {
+	Number      int
+	Title       string
+	Labels      []string
+	MergeCommit string // Merge commit SHA
+}
+
+// ListMergedPRs returns up to limit merged pull requests matching a GitHub
+// search query, such as "merged:>=2026-01-02T15:04:05Z" or
+// `milestone:"v1.2.0"`, using the gh CLI.
+func (g *Git) ListMergedPRs(search string, limit int) ([]MergedPR, error) {
+	if !commandExists("gh") {
+		return nil, proc.NotFound("gh CLI")
+	}
+
+	output, err := g.runGH("pr", "list", "--state", "merged", "--search", search,
+		"--limit", strconv.Itoa(limit), "--json", "number,title,labels,mergeCommit")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+	return parseMergedPRs([]byte(output))
+}
+
+// parseMergedPRs parses the JSON output of gh pr list.
+func parseMergedPRs(data []byte) ([]MergedPR, error) {
+	var raw []struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Labels []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		MergeCommit *struct {
+			OID string `json:"oid"`
+		} `json:"mergeCommit"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	prs := make([]MergedPR, 0, len(raw))
+	for _, r := range raw {
+		pr := MergedPR{Number: r.Number, Title: r.Title}
+		for _, l := range r.Labels {
+			pr.Labels = append(pr.Labels, l.Name)
+		}
+		if r.MergeCommit != nil {
+			pr.MergeCommit = r.MergeCommit.OID
+		}
+		prs = append(prs, pr)
+	}
+	return prs, nil
+}
+
+// CreateIssue opens a GitHub issue with the given labels and returns it.
+func (g *Git) CreateIssue(title, body string, labels []string) (Issue, error) {
+	if !commandExists("gh") {
+		return Issue{}, proc.NotFound("gh CLI")
+	}
+
+	args := []string{"issue", "create", "--title", title, "--body", body}
+	for _, l := range labels {
+		args = append(args, "--label", l)
+	}
+	output, err := g.runGH(args...)
+	if err != nil {
+		return Issue{}, fmt.Errorf("failed to create issue %q: %w", title, err)
+	}
+
+	// gh prints the new issue URL, e.g. https://github.com/o/r/issues/42
+	url := strings.TrimSpace(output)
+	number, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return Issue{}, fmt.Errorf("unexpected gh issue create output: %s", url)
+	}
+	return Issue{Number: number, Title: title, URL: url, State: "OPEN", Labels: labels}, nil
+}
+
+// EnsureLabel creates a GitHub label with the given name if one does not
+// already exist. It reports whether a label was created.
+func (g *Git) EnsureLabel(name, color, description string) (bool, error) {
+	if !commandExists("gh") {
+		return false, proc.NotFound("gh CLI")
+	}
+
+	output, err := g.runGH("label", "list", "--search", name, "--json", "name")
+	if err != nil {
+		return false, fmt.Errorf("failed to list labels: %w", err)
+	}
+	var labels []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(output), &labels); err != nil {
+		return false, err
+	}
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			return false, nil
+		}
+	}
+
+	args := []string{"label", "create", name, "--color", color}
+	if description != "" {
+		args = append(args, "--description", description)
+	}
+	if _, err := g.runGH(args...); err != nil {
+		return false, fmt.Errorf("failed to create label %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// EnsureMilestone creates a GitHub milestone with the given title if one
+// does not already exist. It reports whether a milestone was created.
+func (g *Git) EnsureMilestone(title, description string) (bool, error) {
+	if !commandExists("gh") {
+		return false, proc.NotFound("gh CLI")
+	}
+
+	owner, repo, err := g.parseRemoteURL()
+	if err != nil {
+		return false, err
+	}
+	endpoint := fmt.Sprintf("repos/%s/%s/milestones", owner, repo)
+
+	output, err := g.runGH("api", endpoint+"?state=all&per_page=100")
+	if err != nil {
+		return false, fmt.Errorf("failed to list milestones: %w", err)
+	}
+	var milestones []struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal([]byte(output), &milestones); err != nil {
+		return false, err
+	}
+	for _, m := range milestones {
+		if m.Title == title {
+			return false, nil
+		}
+	}
+
+	args := []string{"api", endpoint, "-f", "title=" + title}
+	if description != "" {
+		args = append(args, "-f", "description="+description)
+	}
+	if _, err := g.runGH(args...); err != nil {
+		return false, fmt.Errorf("failed to create milestone %s: %w", title, err)
+	}
+	return true, nil
+}
+
+// SetIssueMilestone assigns an issue to the milestone with the given title.
+func (g *Git) SetIssueMilestone(number int, milestone string) error {
+	if !commandExists("gh") {
+		return proc.NotFound("gh CLI")
+	}
+	if _, err := g.runGH("issue", "edit", strconv.Itoa(number), "--milestone", milestone); err != nil {
+		return fmt.Errorf("failed to set milestone on #%d: %w", number, err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// UploadReleaseAssets uploads files to the GitHub release for tag, replacing
+// assets with the same name. If the release does not exist yet, it is
+// created from the pushed tag with generated notes.
+func (g *Git) UploadReleaseAssets(tag string, paths ...string) error {
+	if !commandExists("gh") {
+		return proc.NotFound("gh CLI")
+	}
+
+	if _, err := g.runGH("release", "view", tag, "--json", "tagName"); err != nil {
+		args := append([]string{"release", "create", tag, "--verify-tag", "--title", tag, "--generate-notes"}, paths...)
+		if _, err := g.runGH(args...); err != nil {
+			return fmt.Errorf("failed to create release %s: %w", tag, err)
+		}
+		return nil
+	}
+
+	args := append([]string{"release", "upload", tag, "--clobber"}, paths...)
+	if _, err := g.runGH(args...); err != nil {
+		return fmt.Errorf("failed to upload release assets: %w", err)
+	}
+	return nil
+}
+
+// RepositoryURL returns the https URL of the GitHub repository for the
+// remote, e.g. "https://github.com/owner/repo".
+func (g *Git) RepositoryURL() (string, error) {
+	host, owner, repo, err := g.parseRemoteHost()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("https://%s/%s/%s", host, owner, repo), nil
+}
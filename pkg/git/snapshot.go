@@ -0,0 +1,100 @@
+package git
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Snapshot fingerprints the working tree: the status of every changed or
+// untracked file, and its contents. Files that match HEAD are covered by
+// their clean status, so two equal snapshots mean a byte-identical tree.
+type Snapshot struct {
+	Files map[string]string // Repository-relative path to status and content hash
+}
+
+// Snapshot takes a snapshot of the working tree. Ignored files are left
+// out.
+func (g *Git) Snapshot() (*Snapshot, error) {
+	if g.goGit {
+		return nil, fmt.Errorf("snapshots require the git binary")
+	}
+	root, err := g.TopLevel()
+	if err != nil {
+		return nil, err
+	}
+	output, err := g.run("status", "--porcelain", "-z", "--untracked-files=all")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{Files: make(map[string]string)}
+	entries := strings.Split(output, "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		xy, file := entry[:2], entry[3:]
+		// Renames and copies are followed by their source path
+		if xy[0] == 'R' || xy[0] == 'C' {
+			i++
+		}
+		hash, err := hashFile(filepath.Join(root, filepath.FromSlash(file)))
+		if err != nil {
+			return nil, err
+		}
+		s.Files[file] = xy + " " + hash
+	}
+	return s, nil
+}
+
+// Changed returns the paths whose status or contents differ between s and
+// after, sorted.
+func (s *Snapshot) Changed(after *Snapshot) []string {
+	var changed []string
+	for file, state := range after.Files {
+		if s.Files[file] != state {
+			changed = append(changed, file)
+		}
+	}
+	for file := range s.Files {
+		if _, ok := after.Files[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// hashFile returns the SHA-256 of a file's contents, "deleted" for a
+// missing file, or the target of a symlink.
+func hashFile(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "deleted", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		return "-> " + target, err
+	case info.IsDir():
+		// A submodule or nested repository
+		return "dir", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
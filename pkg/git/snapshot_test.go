@@ -0,0 +1,59 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("clean.txt", "clean\n")
+	write("dirty.txt", "committed\n")
+	write(".gitignore", "*.log\n")
+	run("add", "-A")
+	run("commit", "-m", "base")
+	write("dirty.txt", "edited\n")
+	write("new/untracked.txt", "new\n")
+
+	g := New(tmpDir)
+	before, err := g.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error: %v", err)
+	}
+	same, err := g.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed := before.Changed(same); len(changed) != 0 {
+		t.Errorf("unchanged tree reported changes: %v", changed)
+	}
+
+	// Edit an already dirty file, touch a clean one, and write ignored output
+	write("dirty.txt", "edited again\n")
+	write("clean.txt", "changed\n")
+	write("build.log", "ignored\n")
+	if err := os.Remove(filepath.Join(tmpDir, "new", "untracked.txt")); err != nil {
+		t.Fatal(err)
+	}
+	after, err := g.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"clean.txt", "dirty.txt", "new/untracked.txt"}
+	if changed := before.Changed(after); !slices.Equal(changed, want) {
+		t.Errorf("Changed() = %v, want %v", changed, want)
+	}
+}
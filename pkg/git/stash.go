@@ -0,0 +1,54 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StashUnstaged stashes unstaged and untracked changes, leaving staged
+// changes in the index and working tree so checks run against what would
+// be committed. It returns the stash commit to pass to RestoreStash, or ""
+// if there was nothing to stash.
+func (g *Git) StashUnstaged(message string) (string, error) {
+	output, err := g.run("status", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+	unstaged := false
+	for _, line := range strings.Split(output, "\n") {
+		if len(line) >= 2 && line[1] != ' ' {
+			unstaged = true
+			break
+		}
+	}
+	if !unstaged {
+		return "", nil
+	}
+
+	if _, err := g.run("stash", "push", "--keep-index", "--include-untracked", "-m", message); err != nil {
+		return "", fmt.Errorf("failed to stash changes: %w", err)
+	}
+	sha, err := g.run("rev-parse", "--verify", "refs/stash")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(sha), nil
+}
+
+// RestoreStash restores a stash created by StashUnstaged, including its
+// staged changes. The working tree is reset to HEAD first so the stash
+// applies cleanly even where staged and unstaged changes overlap; the stash
+// holds everything that was there before.
+func (g *Git) RestoreStash(sha string) error {
+	top, err := g.run("rev-parse", "--verify", "refs/stash")
+	if err != nil || strings.TrimSpace(top) != sha {
+		return fmt.Errorf("stash %s is no longer the latest stash; restore it with 'git stash apply --index %s'", sha[:7], sha)
+	}
+	if _, err := g.run("reset", "--hard", "--quiet"); err != nil {
+		return err
+	}
+	if _, err := g.run("stash", "pop", "--index", "--quiet"); err != nil {
+		return fmt.Errorf("failed to restore stashed changes; run 'git stash pop --index': %w", err)
+	}
+	return nil
+}
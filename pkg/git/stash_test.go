@@ -0,0 +1,79 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStashUnstaged(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(tmpDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	write("file.txt", "a\nb\nc\n")
+	run("add", "-A")
+	run("commit", "-m", "base")
+
+	g := New(tmpDir)
+
+	// Only staged changes: nothing to stash
+	write("file.txt", "A\nb\nc\n")
+	run("add", "file.txt")
+	sha, err := g.StashUnstaged("test")
+	if err != nil {
+		t.Fatalf("StashUnstaged() error: %v", err)
+	}
+	if sha != "" {
+		t.Fatalf("StashUnstaged() = %q with only staged changes, want empty", sha)
+	}
+
+	// Unstaged change overlapping the staged one, plus an untracked file
+	write("file.txt", "AA\nb\nc\n")
+	write("new.txt", "new\n")
+	sha, err = g.StashUnstaged("test")
+	if err != nil {
+		t.Fatalf("StashUnstaged() error: %v", err)
+	}
+	if sha == "" {
+		t.Fatal("StashUnstaged() stashed nothing")
+	}
+	if got := read("file.txt"); got != "A\nb\nc\n" {
+		t.Errorf("working tree after stash = %q, want staged content", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "new.txt")); !os.IsNotExist(err) {
+		t.Error("untracked file should be stashed")
+	}
+
+	// A check that leaves a modified file behind must not block restoring
+	write("file.txt", "modified by a check\n")
+
+	if err := g.RestoreStash(sha); err != nil {
+		t.Fatalf("RestoreStash() error: %v", err)
+	}
+	if got := read("file.txt"); got != "AA\nb\nc\n" {
+		t.Errorf("working tree after restore = %q", got)
+	}
+	if got := read("new.txt"); got != "new\n" {
+		t.Errorf("untracked file after restore = %q", got)
+	}
+	if staged := run("diff", "--cached"); !strings.Contains(staged, "+A\n") {
+		t.Errorf("staged changes not restored:\n%s", staged)
+	}
+	if stashes := run("stash", "list"); stashes != "" {
+		t.Errorf("stash should be dropped, got %q", stashes)
+	}
+}
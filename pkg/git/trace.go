@@ -0,0 +1,65 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TraceEnv is the environment variable that enables command tracing.
+const TraceEnv = "ATRELEASE_TRACE"
+
+// Tracer, when set, receives a debug record for every git and gh command
+// run by this package, with its arguments, duration, and exit code.
+var Tracer *slog.Logger
+
+// maxTraceArg is the length above which arguments such as PR bodies are
+// shortened in traces and errors.
+const maxTraceArg = 80
+
+// traceCommand logs a finished command to Tracer.
+func traceCommand(dir, name string, args []string, start time.Time, err error) {
+	if Tracer == nil {
+		return
+	}
+	Tracer.LogAttrs(context.Background(), slog.LevelDebug, "exec",
+		slog.String("cmd", commandString(name, args)),
+		slog.String("dir", dir),
+		slog.Duration("duration", time.Since(start)),
+		slog.Int("exit_code", exitCode(err)),
+	)
+}
+
+// commandString formats a command line for traces and error messages,
+// quoting arguments with spaces and shortening long ones.
+func commandString(name string, args []string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, name)
+	for _, arg := range args {
+		if len(arg) > maxTraceArg {
+			arg = arg[:maxTraceArg] + "..."
+		}
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// exitCode returns the exit code for a command error: 0 on success and -1
+// if the command could not be started.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,53 @@
+package git
+
+import (
+	"bytes"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCommandString(t *testing.T) {
+	long := strings.Repeat("x", maxTraceArg+10)
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"push", "origin", "main"}, "git push origin main"},
+		{[]string{"commit", "-m", "fix: a bug"}, `git commit -m "fix: a bug"`},
+		{[]string{"tag", ""}, `git tag ""`},
+		{[]string{"log", long}, "git log " + strings.Repeat("x", maxTraceArg) + "..."},
+	}
+	for _, tt := range tests {
+		if got := commandString("git", tt.args); got != tt.want {
+			t.Errorf("commandString(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestTraceAndWrappedErrors(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	var buf bytes.Buffer
+	Tracer = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	defer func() { Tracer = nil }()
+
+	g := New(t.TempDir())
+	_, err := g.run("rev-parse", "--verify", "no-such-ref")
+	if err == nil {
+		t.Fatal("expected error outside a repository")
+	}
+	if !strings.Contains(err.Error(), "git rev-parse --verify no-such-ref:") {
+		t.Errorf("error should include the failing command, got %q", err)
+	}
+
+	trace := buf.String()
+	for _, want := range []string{`cmd="git rev-parse --verify no-such-ref"`, "duration=", "exit_code=128"} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+}
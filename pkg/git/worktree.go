@@ -0,0 +1,17 @@
+package git
+
+import "fmt"
+
+// AddWorktree checks out ref into a new detached worktree at path.
+func (g *Git) AddWorktree(path, ref string) error {
+	if _, err := g.run("worktree", "add", "--detach", path, ref); err != nil {
+		return fmt.Errorf("failed to create worktree for %s: %w", ref, err)
+	}
+	return nil
+}
+
+// RemoveWorktree removes a worktree created by AddWorktree.
+func (g *Git) RemoveWorktree(path string) error {
+	_, err := g.run("worktree", "remove", "--force", path)
+	return err
+}
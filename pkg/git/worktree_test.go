@@ -0,0 +1,51 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorktree(t *testing.T) {
+	tmpDir, run := newTestRepo(t)
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("base\n")
+	run("add", "-A")
+	run("commit", "-m", "base")
+	run("tag", "v1.0.0")
+	write("main\n")
+	run("commit", "-am", "main change")
+
+	g := New(tmpDir)
+	wt := filepath.Join(t.TempDir(), "wt")
+	if err := g.AddWorktree(wt, "v1.0.0"); err != nil {
+		t.Fatalf("AddWorktree() error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(wt, "file.txt")); err != nil || string(data) != "base\n" {
+		t.Errorf("worktree file.txt = %q, %v, want %q", data, err, "base\n")
+	}
+
+	// The worktree is removed even with changes, leaving the repo as it was
+	if err := os.WriteFile(filepath.Join(wt, "file.txt"), []byte("edited\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RemoveWorktree(wt); err != nil {
+		t.Fatalf("RemoveWorktree() error: %v", err)
+	}
+	if _, err := os.Stat(wt); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists after RemoveWorktree(): %v", err)
+	}
+	if dirty, _ := g.IsDirty(); dirty {
+		t.Error("IsDirty() = true after RemoveWorktree(), want false")
+	}
+
+	if err := g.AddWorktree(filepath.Join(t.TempDir(), "missing"), "v9.9.9"); err == nil {
+		t.Error("AddWorktree() with a missing ref: want error")
+	}
+}
@@ -6,15 +6,17 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/plexusone/agent-team-release/pkg/actions"
 )
 
 // JSONPrompter implements Prompter with JSON input/output for Claude Code integration.
 type JSONPrompter struct {
-	writer  io.Writer
-	reader  *bufio.Reader
-	encoder *json.Encoder
+	writer        io.Writer
+	reader        *bufio.Reader
+	encoder       *json.Encoder
+	correlationID string
 }
 
 // NewJSONPrompter creates a new JSONPrompter.
@@ -33,10 +35,28 @@ func DefaultJSONPrompter() *JSONPrompter {
 	return NewJSONPrompter(os.Stdout, os.Stdin)
 }
 
+// SetCorrelationID sets the run-scoped correlation ID stamped on every message,
+// typically shared with the output writer for the same run.
+func (p *JSONPrompter) SetCorrelationID(id string) {
+	p.correlationID = id
+}
+
 // jsonMessage is the base JSON protocol message.
 type jsonMessage struct {
-	Type string `json:"type"`
-	ID   string `json:"id,omitempty"`
+	Type          string `json:"type"`
+	ID            string `json:"id,omitempty"`
+	Timestamp     string `json:"timestamp,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// newMessage returns a base message stamped with the current time and correlation ID.
+func (p *JSONPrompter) newMessage(msgType, id string) jsonMessage {
+	return jsonMessage{
+		Type:          msgType,
+		ID:            id,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		CorrelationID: p.correlationID,
+	}
 }
 
 // jsonQuestionMessage represents a question in JSON format.
@@ -94,17 +114,14 @@ func (p *JSONPrompter) Ask(q Question) (Answer, error) {
 
 	// Write question as JSON
 	msg := jsonQuestionMessage{
-		jsonMessage: jsonMessage{
-			Type: "question",
-			ID:   q.ID,
-		},
-		Question:   q.Text,
-		InputType:  q.Type.String(),
-		Options:    options,
-		Default:    q.Default,
-		Context:    q.Context,
-		Required:   true,
-		WaitingFor: "user_input",
+		jsonMessage: p.newMessage("question", q.ID),
+		Question:    q.Text,
+		InputType:   q.Type.String(),
+		Options:     options,
+		Default:     q.Default,
+		Context:     q.Context,
+		Required:    true,
+		WaitingFor:  "user_input",
 	}
 
 	if err := p.encoder.Encode(msg); err != nil {
@@ -137,9 +154,7 @@ func (p *JSONPrompter) Ask(q Question) (Answer, error) {
 // ShowProposal displays a proposed change for review via JSON.
 func (p *JSONPrompter) ShowProposal(proposal actions.Proposal) error {
 	msg := jsonProposalMessage{
-		jsonMessage: jsonMessage{
-			Type: "proposal",
-		},
+		jsonMessage: p.newMessage("proposal", ""),
 		Description: proposal.Description,
 		FilePath:    proposal.FilePath,
 		OldContent:  proposal.OldContent,
@@ -176,7 +191,7 @@ func (p *JSONPrompter) Confirm(message string) (bool, error) {
 // Info displays an informational message via JSON.
 func (p *JSONPrompter) Info(message string) {
 	msg := jsonInfoMessage{
-		jsonMessage: jsonMessage{Type: "info"},
+		jsonMessage: p.newMessage("info", ""),
 		Text:        message,
 	}
 	_ = p.encoder.Encode(msg)
@@ -185,7 +200,7 @@ func (p *JSONPrompter) Info(message string) {
 // Warn displays a warning message via JSON.
 func (p *JSONPrompter) Warn(message string) {
 	msg := jsonInfoMessage{
-		jsonMessage: jsonMessage{Type: "warning"},
+		jsonMessage: p.newMessage("warning", ""),
 		Text:        message,
 	}
 	_ = p.encoder.Encode(msg)
@@ -194,7 +209,7 @@ func (p *JSONPrompter) Warn(message string) {
 // Error displays an error message via JSON.
 func (p *JSONPrompter) Error(message string) {
 	msg := jsonInfoMessage{
-		jsonMessage: jsonMessage{Type: "error"},
+		jsonMessage: p.newMessage("error", ""),
 		Text:        message,
 	}
 	_ = p.encoder.Encode(msg)
@@ -0,0 +1,264 @@
+// Package jsonschema validates JSON documents against the subset of JSON
+// Schema the project's schemas use: $ref to $defs, type, required,
+// properties, additionalProperties, items, enum, pattern, minLength, and
+// maxLength. Other keywords, such as format, are ignored.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation is a single schema error in a document.
+type Violation struct {
+	Path    string // Location in the document, e.g. "releases[2].highlights[0].description"
+	Message string
+}
+
+// String returns the violation as "path: message".
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	root *node
+}
+
+// node is a schema or subschema.
+type node struct {
+	Ref                  string           `json:"$ref"`
+	Type                 typeList         `json:"type"`
+	Required             []string         `json:"required"`
+	Properties           map[string]*node `json:"properties"`
+	AdditionalProperties *additional      `json:"additionalProperties"`
+	Items                *node            `json:"items"`
+	Enum                 []string         `json:"enum"`
+	Pattern              string           `json:"pattern"`
+	MinLength            int              `json:"minLength"`
+	MaxLength            *int             `json:"maxLength"`
+	Defs                 map[string]*node `json:"$defs"`
+
+	pattern *regexp.Regexp
+}
+
+// typeList is a JSON Schema "type", which is a string or array of strings.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = typeList{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*t = multi
+	return nil
+}
+
+// additional is an "additionalProperties" keyword, which is a boolean or
+// the schema of the properties not listed in "properties".
+type additional struct {
+	allowed bool
+	schema  *node
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	return json.Unmarshal(data, &a.schema)
+}
+
+// Compile parses a JSON Schema document.
+func Compile(data []byte) (*Schema, error) {
+	var root node
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if err := root.compile(); err != nil {
+		return nil, err
+	}
+	return &Schema{root: &root}, nil
+}
+
+// compile compiles the patterns of n and its subschemas.
+func (n *node) compile() error {
+	if n == nil {
+		return nil
+	}
+	if n.Pattern != "" {
+		re, err := regexp.Compile(n.Pattern)
+		if err != nil {
+			return fmt.Errorf("pattern %q: %w", n.Pattern, err)
+		}
+		n.pattern = re
+	}
+	children := []*node{n.Items}
+	if n.AdditionalProperties != nil {
+		children = append(children, n.AdditionalProperties.schema)
+	}
+	for _, child := range n.Properties {
+		children = append(children, child)
+	}
+	for _, child := range n.Defs {
+		children = append(children, child)
+	}
+	for _, child := range children {
+		if err := child.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode decodes a JSON document for Validate, keeping numbers as
+// json.Number so integers can be told apart.
+func Decode(data []byte) (any, error) {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Validate returns the violations of doc, a document returned by Decode.
+func (s *Schema) Validate(doc any) []Violation {
+	v := &validator{defs: s.root.Defs}
+	v.validate(s.root, doc, "")
+	return v.violations
+}
+
+type validator struct {
+	defs       map[string]*node
+	violations []Violation
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	if path == "" {
+		path = "(root)"
+	}
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(s *node, value any, path string) {
+	if s.Ref != "" {
+		def, ok := v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			v.fail(path, "unresolved schema reference %s", s.Ref)
+			return
+		}
+		s = def
+	}
+
+	if len(s.Type) > 0 && !matchesType(s.Type, value) {
+		v.fail(path, "expected %s, got %s", strings.Join(s.Type, " or "), jsonType(value))
+		return
+	}
+
+	switch val := value.(type) {
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := val[key]; !ok {
+				v.fail(path, "missing required field %q", key)
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := joinPath(path, key)
+			if prop, ok := s.Properties[key]; ok {
+				v.validate(prop, val[key], child)
+			} else if a := s.AdditionalProperties; a != nil && !a.allowed {
+				v.fail(child, "unknown field %q", key)
+			} else if a != nil && a.schema != nil {
+				v.validate(a.schema, val[key], child)
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(val)
+		if n < s.MinLength {
+			if s.MinLength == 1 {
+				v.fail(path, "must not be empty")
+			} else {
+				v.fail(path, "must be at least %d characters", s.MinLength)
+			}
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			v.fail(path, "must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			v.fail(path, "%q does not match %s", val, s.Pattern)
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, val) {
+			v.fail(path, "%q is not one of %s", val, strings.Join(s.Enum, ", "))
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func matchesType(types []string, value any) bool {
+	actual := jsonType(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package jsonschema
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testSchema = `{
+  "type": "object",
+  "required": ["name", "version"],
+  "properties": {
+    "name": {"type": "string", "pattern": "^[a-z][a-z0-9-]*$", "maxLength": 10},
+    "version": {"$ref": "#/$defs/version"},
+    "tags": {"type": "array", "items": {"type": "string", "minLength": 1}},
+    "servers": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "object",
+        "required": ["command"],
+        "properties": {"command": {"type": "string"}},
+        "additionalProperties": false
+      }
+    }
+  },
+  "$defs": {
+    "version": {"type": "string", "enum": ["1.0.0", "2.0.0"]}
+  }
+}`
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Compile() error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name, doc string
+		want      []Violation
+	}{
+		{
+			name: "valid",
+			doc:  `{"name": "demo", "version": "1.0.0", "tags": ["a"], "servers": {"local": {"command": "demo"}}, "extra": 1}`,
+		},
+		{
+			name: "not an object",
+			doc:  `[]`,
+			want: []Violation{{"(root)", "expected object, got array"}},
+		},
+		{
+			name: "missing and invalid fields",
+			doc:  `{"name": "Demo-plugin-name", "tags": [""]}`,
+			want: []Violation{
+				{"(root)", `missing required field "version"`},
+				{"name", "must be at most 10 characters"},
+				{"name", `"Demo-plugin-name" does not match ^[a-z][a-z0-9-]*$`},
+				{"tags[0]", "must not be empty"},
+			},
+		},
+		{
+			name: "additional properties",
+			doc:  `{"name": "demo", "version": "3.0.0", "servers": {"local": {"cmd": "demo"}}}`,
+			want: []Violation{
+				{"servers.local", `missing required field "command"`},
+				{"servers.local.cmd", `unknown field "cmd"`},
+				{"version", `"3.0.0" is not one of 1.0.0, 2.0.0`},
+			},
+		},
+	} {
+		doc, err := Decode([]byte(tt.doc))
+		if err != nil {
+			t.Fatalf("%s: Decode() error: %v", tt.name, err)
+		}
+		if got := schema.Validate(doc); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Validate() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompile_InvalidPattern(t *testing.T) {
+	if _, err := Compile([]byte(`{"properties": {"name": {"pattern": "("}}}`)); err == nil {
+		t.Error("Compile() with an invalid pattern: want error")
+	}
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// ErrorCode is a stable, machine-readable error cause that agents can branch on.
+type ErrorCode string
+
+const (
+	// ErrCodeConfigInvalid indicates the configuration file could not be
+	// parsed, or the policy bundle it extends could not be fetched.
+	ErrCodeConfigInvalid ErrorCode = "CONFIG_INVALID"
+	// ErrCodeToolMissing indicates a required external tool is not installed.
+	ErrCodeToolMissing ErrorCode = "TOOL_MISSING"
+	// ErrCodeCheckFailed indicates one or more validation checks failed.
+	ErrCodeCheckFailed ErrorCode = "CHECK_FAILED"
+	// ErrCodeGitDirty indicates the working directory has uncommitted changes.
+	ErrCodeGitDirty ErrorCode = "GIT_DIRTY"
+	// ErrCodeGitDiverged indicates the branch is behind its remote or shares
+	// no history with the default branch.
+	ErrCodeGitDiverged ErrorCode = "GIT_DIVERGED"
+	// ErrCodeCITimeout indicates CI did not complete before the timeout.
+	ErrCodeCITimeout ErrorCode = "CI_TIMEOUT"
+	// ErrCodeAuthScope indicates the GitHub credential lacks a permission
+	// the release needs.
+	ErrCodeAuthScope ErrorCode = "AUTH_SCOPE"
+	// ErrCodeTagExists indicates the release tag already exists.
+	ErrCodeTagExists ErrorCode = "TAG_EXISTS"
+	// ErrCodeReleaseFrozen indicates a release was attempted during a freeze
+	// window without --force.
+	ErrCodeReleaseFrozen ErrorCode = "RELEASE_FROZEN"
+	// ErrCodeApprovalRequired indicates the release lacks the approvals
+	// release.approvals requires.
+	ErrCodeApprovalRequired ErrorCode = "APPROVAL_REQUIRED"
+	// ErrCodeCancelled indicates the operation was interrupted, e.g. by
+	// Ctrl-C, and its processes were killed.
+	ErrCodeCancelled ErrorCode = "CANCELLED"
+)
+
+// CodedError wraps an error with a stable ErrorCode.
+type CodedError struct {
+	Code ErrorCode
+	Err  error
+}
+
+// Error returns the wrapped error message.
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *CodedError) Unwrap() error {
+	return e.Err
+}
+
+// WithCode attaches an ErrorCode to err. It returns nil if err is nil.
+func WithCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CodedError{Code: code, Err: err}
+}
+
+// CodeOf returns the ErrorCode attached to err, or an empty code if none.
+// Well-known sentinel errors (missing executables, invalid configuration,
+// CI timeouts) are mapped to their codes even when not explicitly wrapped.
+func CodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var coded *CodedError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	switch {
+	case errors.Is(err, exec.ErrNotFound):
+		return ErrCodeToolMissing
+	case errors.Is(err, config.ErrInvalid), errors.Is(err, config.ErrPolicy):
+		return ErrCodeConfigInvalid
+	case errors.Is(err, git.ErrCITimeout):
+		return ErrCodeCITimeout
+	case errors.Is(err, context.Canceled):
+		return ErrCodeCancelled
+	}
+	return ""
+}
@@ -2,9 +2,13 @@
 package output
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/plexusone/agent-team-release/pkg/actions"
 	"github.com/plexusone/agent-team-release/pkg/interactive"
@@ -32,22 +36,25 @@ const (
 
 // Message is the base protocol message.
 type Message struct {
-	Type      MessageType `json:"type" toon:"type"`
-	ID        string      `json:"id,omitempty" toon:"id,omitempty"`
-	Timestamp string      `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	Type          MessageType `json:"type" toon:"type"`
+	ID            string      `json:"id,omitempty" toon:"id,omitempty"`
+	Timestamp     string      `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string      `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // QuestionMessage represents a question for user input.
 type QuestionMessage struct {
-	Type       string       `json:"type" toon:"type"`
-	ID         string       `json:"id,omitempty" toon:"id,omitempty"`
-	Question   string       `json:"question" toon:"question"`
-	InputType  string       `json:"input_type" toon:"input_type"` // single_choice, multi_choice, confirm, text
-	Options    []OptionJSON `json:"options,omitempty" toon:"options,omitempty"`
-	Default    string       `json:"default,omitempty" toon:"default,omitempty"`
-	Context    string       `json:"context,omitempty" toon:"context,omitempty"`
-	Required   bool         `json:"required" toon:"required"`
-	WaitingFor string       `json:"waiting_for" toon:"waiting_for"` // Always "user_input" for questions
+	Type          string       `json:"type" toon:"type"`
+	ID            string       `json:"id,omitempty" toon:"id,omitempty"`
+	Question      string       `json:"question" toon:"question"`
+	InputType     string       `json:"input_type" toon:"input_type"` // single_choice, multi_choice, confirm, text
+	Options       []OptionJSON `json:"options,omitempty" toon:"options,omitempty"`
+	Default       string       `json:"default,omitempty" toon:"default,omitempty"`
+	Context       string       `json:"context,omitempty" toon:"context,omitempty"`
+	Required      bool         `json:"required" toon:"required"`
+	WaitingFor    string       `json:"waiting_for" toon:"waiting_for"` // Always "user_input" for questions
+	Timestamp     string       `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string       `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // OptionJSON represents a choice option.
@@ -67,64 +74,118 @@ type AnswerMessage struct {
 
 // ProposalMessage represents a proposed change for review.
 type ProposalMessage struct {
-	Type        string            `json:"type" toon:"type"`
-	Description string            `json:"description" toon:"description"`
-	FilePath    string            `json:"file_path,omitempty" toon:"file_path,omitempty"`
-	OldContent  string            `json:"old_content,omitempty" toon:"old_content,omitempty"`
-	NewContent  string            `json:"new_content,omitempty" toon:"new_content,omitempty"`
-	Diff        string            `json:"diff,omitempty" toon:"diff,omitempty"`
-	Metadata    map[string]string `json:"metadata,omitempty" toon:"metadata,omitempty"`
-	WaitingFor  string            `json:"waiting_for" toon:"waiting_for"` // "user_approval"
-	Actions     []string          `json:"actions" toon:"actions"`         // ["apply", "skip", "abort"]
+	Type          string            `json:"type" toon:"type"`
+	Description   string            `json:"description" toon:"description"`
+	FilePath      string            `json:"file_path,omitempty" toon:"file_path,omitempty"`
+	OldContent    string            `json:"old_content,omitempty" toon:"old_content,omitempty"`
+	NewContent    string            `json:"new_content,omitempty" toon:"new_content,omitempty"`
+	Diff          string            `json:"diff,omitempty" toon:"diff,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty" toon:"metadata,omitempty"`
+	WaitingFor    string            `json:"waiting_for" toon:"waiting_for"` // "user_approval"
+	Actions       []string          `json:"actions" toon:"actions"`         // ["apply", "skip", "abort"]
+	Timestamp     string            `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // InfoMessage represents an informational message.
 type InfoMessage struct {
-	Type string `json:"type" toon:"type"`
-	Text string `json:"text" toon:"text"`
+	Type          string `json:"type" toon:"type"`
+	Text          string `json:"text" toon:"text"`
+	Timestamp     string `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // WarningMessage represents a warning message.
 type WarningMessage struct {
-	Type string `json:"type" toon:"type"`
-	Text string `json:"text" toon:"text"`
+	Type          string `json:"type" toon:"type"`
+	Text          string `json:"text" toon:"text"`
+	Timestamp     string `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // ErrorMessage represents an error message.
 type ErrorMessage struct {
-	Type  string `json:"type" toon:"type"`
-	Text  string `json:"text" toon:"text"`
-	Code  string `json:"code,omitempty" toon:"code,omitempty"`
-	Fatal bool   `json:"fatal" toon:"fatal"`
+	Type          string `json:"type" toon:"type"`
+	Text          string `json:"text" toon:"text"`
+	Code          string `json:"code,omitempty" toon:"code,omitempty"`
+	Fatal         bool   `json:"fatal" toon:"fatal"`
+	Timestamp     string `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
+}
+
+// NewErrorMessage converts err to an error message, with the code CodeOf
+// finds for it.
+func NewErrorMessage(err error, fatal bool, correlationID string) ErrorMessage {
+	return ErrorMessage{
+		Type:          string(MessageTypeError),
+		Text:          err.Error(),
+		Code:          string(CodeOf(err)),
+		Fatal:         fatal,
+		Timestamp:     Timestamp(),
+		CorrelationID: correlationID,
+	}
 }
 
 // ResultMessage represents the result of an operation.
 type ResultMessage struct {
-	Type    string `json:"type" toon:"type"`
-	Name    string `json:"name" toon:"name"`
-	Success bool   `json:"success" toon:"success"`
-	Output  string `json:"output,omitempty" toon:"output,omitempty"`
-	Error   string `json:"error,omitempty" toon:"error,omitempty"`
-	Skipped bool   `json:"skipped" toon:"skipped"`
-	Reason  string `json:"reason,omitempty" toon:"reason,omitempty"`
+	Type          string `json:"type" toon:"type"`
+	ID            string `json:"id,omitempty" toon:"id,omitempty"`
+	Name          string `json:"name" toon:"name"`
+	Success       bool   `json:"success" toon:"success"`
+	Severity      string `json:"severity" toon:"severity"` // "passed", "failed", or "skipped"
+	Output        string `json:"output,omitempty" toon:"output,omitempty"`
+	Error         string `json:"error,omitempty" toon:"error,omitempty"`
+	Code          string `json:"code,omitempty" toon:"code,omitempty"`
+	Skipped       bool   `json:"skipped" toon:"skipped"`
+	Reason        string `json:"reason,omitempty" toon:"reason,omitempty"`
+	DurationMs    int64  `json:"duration_ms,omitempty" toon:"duration_ms,omitempty"`
+	Timestamp     string `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
+}
+
+// NewResultMessage converts an action result to a result message.
+func NewResultMessage(r actions.Result, correlationID string) ResultMessage {
+	errStr := ""
+	if r.Error != nil {
+		errStr = r.Error.Error()
+	}
+	return ResultMessage{
+		Type:          string(MessageTypeResult),
+		ID:            r.ID,
+		Name:          r.Name,
+		Success:       r.Success,
+		Severity:      string(r.Severity()),
+		Output:        r.Output,
+		Error:         errStr,
+		Code:          string(CodeOf(r.Error)),
+		Skipped:       r.Skipped,
+		Reason:        r.Reason,
+		DurationMs:    r.Duration.Milliseconds(),
+		Timestamp:     Timestamp(),
+		CorrelationID: correlationID,
+	}
 }
 
 // ProgressMessage represents a progress update.
 type ProgressMessage struct {
-	Type       string `json:"type" toon:"type"`
-	Step       int    `json:"step" toon:"step"`
-	TotalSteps int    `json:"total_steps" toon:"total_steps"`
-	StepName   string `json:"step_name" toon:"step_name"`
-	Status     string `json:"status" toon:"status"` // "running", "completed", "failed", "skipped"
+	Type          string `json:"type" toon:"type"`
+	Step          int    `json:"step" toon:"step"`
+	TotalSteps    int    `json:"total_steps" toon:"total_steps"`
+	StepName      string `json:"step_name" toon:"step_name"`
+	Status        string `json:"status" toon:"status"` // "running", "completed", "failed", "skipped"
+	Timestamp     string `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // WorkflowResultMessage represents the final result of a workflow.
 type WorkflowResultMessage struct {
-	Type         string           `json:"type" toon:"type"`
-	WorkflowName string           `json:"workflow_name" toon:"workflow_name"`
-	Success      bool             `json:"success" toon:"success"`
-	Steps        []StepResultJSON `json:"steps" toon:"steps"`
-	Summary      string           `json:"summary,omitempty" toon:"summary,omitempty"`
+	Type          string           `json:"type" toon:"type"`
+	WorkflowName  string           `json:"workflow_name" toon:"workflow_name"`
+	Success       bool             `json:"success" toon:"success"`
+	Steps         []StepResultJSON `json:"steps" toon:"steps"`
+	Summary       string           `json:"summary,omitempty" toon:"summary,omitempty"`
+	Timestamp     string           `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string           `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // StepResultJSON represents a step result.
@@ -136,22 +197,50 @@ type StepResultJSON struct {
 	Error    string `json:"error,omitempty" toon:"error,omitempty"`
 }
 
+// NewCorrelationID returns a random identifier for correlating all messages
+// emitted during a single run.
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to a time-based ID; uniqueness per run is all we need.
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Timestamp returns the current time in RFC3339 format (UTC).
+func Timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
 // JSONWriter writes JSON messages to an output stream.
 type JSONWriter struct {
-	writer  io.Writer
-	encoder *json.Encoder
+	writer        io.Writer
+	encoder       *json.Encoder
+	correlationID string
 }
 
-// NewJSONWriter creates a new JSONWriter.
+// NewJSONWriter creates a new JSONWriter with a fresh correlation ID.
 func NewJSONWriter(w io.Writer) *JSONWriter {
 	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return &JSONWriter{
-		writer:  w,
-		encoder: encoder,
+		writer:        w,
+		encoder:       encoder,
+		correlationID: NewCorrelationID(),
 	}
 }
 
+// CorrelationID returns the correlation ID stamped on every message.
+func (jw *JSONWriter) CorrelationID() string {
+	return jw.correlationID
+}
+
+// SetCorrelationID overrides the correlation ID, e.g. to match a workflow run.
+func (jw *JSONWriter) SetCorrelationID(id string) {
+	jw.correlationID = id
+}
+
 // DefaultJSONWriter returns a JSONWriter writing to stdout.
 func DefaultJSONWriter() *JSONWriter {
 	return NewJSONWriter(os.Stdout)
@@ -174,15 +263,17 @@ func (jw *JSONWriter) WriteQuestion(q interactive.Question) error {
 	}
 
 	msg := QuestionMessage{
-		Type:       string(MessageTypeQuestion),
-		ID:         q.ID,
-		Question:   q.Text,
-		InputType:  q.Type.String(),
-		Options:    options,
-		Default:    q.Default,
-		Context:    q.Context,
-		Required:   true,
-		WaitingFor: "user_input",
+		Type:          string(MessageTypeQuestion),
+		ID:            q.ID,
+		Question:      q.Text,
+		InputType:     q.Type.String(),
+		Options:       options,
+		Default:       q.Default,
+		Context:       q.Context,
+		Required:      true,
+		WaitingFor:    "user_input",
+		Timestamp:     Timestamp(),
+		CorrelationID: jw.correlationID,
 	}
 	return jw.Write(msg)
 }
@@ -190,14 +281,16 @@ func (jw *JSONWriter) WriteQuestion(q interactive.Question) error {
 // WriteProposal writes a proposal as JSON.
 func (jw *JSONWriter) WriteProposal(p actions.Proposal) error {
 	msg := ProposalMessage{
-		Type:        string(MessageTypeProposal),
-		Description: p.Description,
-		FilePath:    p.FilePath,
-		OldContent:  p.OldContent,
-		NewContent:  p.NewContent,
-		Metadata:    p.Metadata,
-		WaitingFor:  "user_approval",
-		Actions:     []string{"apply", "skip", "abort"},
+		Type:          string(MessageTypeProposal),
+		Description:   p.Description,
+		FilePath:      p.FilePath,
+		OldContent:    p.OldContent,
+		NewContent:    p.NewContent,
+		Metadata:      p.Metadata,
+		WaitingFor:    "user_approval",
+		Actions:       []string{"apply", "skip", "abort"},
+		Timestamp:     Timestamp(),
+		CorrelationID: jw.correlationID,
 	}
 	return jw.Write(msg)
 }
@@ -205,8 +298,10 @@ func (jw *JSONWriter) WriteProposal(p actions.Proposal) error {
 // WriteInfo writes an informational message as JSON.
 func (jw *JSONWriter) WriteInfo(text string) error {
 	msg := InfoMessage{
-		Type: string(MessageTypeInfo),
-		Text: text,
+		Type:          string(MessageTypeInfo),
+		Text:          text,
+		Timestamp:     Timestamp(),
+		CorrelationID: jw.correlationID,
 	}
 	return jw.Write(msg)
 }
@@ -214,48 +309,53 @@ func (jw *JSONWriter) WriteInfo(text string) error {
 // WriteWarning writes a warning message as JSON.
 func (jw *JSONWriter) WriteWarning(text string) error {
 	msg := WarningMessage{
-		Type: string(MessageTypeWarning),
-		Text: text,
+		Type:          string(MessageTypeWarning),
+		Text:          text,
+		Timestamp:     Timestamp(),
+		CorrelationID: jw.correlationID,
 	}
 	return jw.Write(msg)
 }
 
 // WriteError writes an error message as JSON.
 func (jw *JSONWriter) WriteError(text string, fatal bool) error {
+	return jw.WriteCodedError("", text, fatal)
+}
+
+// WriteCodedError writes an error message with a stable error code as JSON.
+func (jw *JSONWriter) WriteCodedError(code ErrorCode, text string, fatal bool) error {
 	msg := ErrorMessage{
-		Type:  string(MessageTypeError),
-		Text:  text,
-		Fatal: fatal,
+		Type:          string(MessageTypeError),
+		Text:          text,
+		Code:          string(code),
+		Fatal:         fatal,
+		Timestamp:     Timestamp(),
+		CorrelationID: jw.correlationID,
 	}
 	return jw.Write(msg)
 }
 
+// WriteErr writes err as an error message as JSON, with the code CodeOf
+// finds for it.
+func (jw *JSONWriter) WriteErr(err error, fatal bool) error {
+	return jw.Write(NewErrorMessage(err, fatal, jw.correlationID))
+}
+
 // WriteResult writes an action result as JSON.
 func (jw *JSONWriter) WriteResult(r actions.Result) error {
-	errStr := ""
-	if r.Error != nil {
-		errStr = r.Error.Error()
-	}
-	msg := ResultMessage{
-		Type:    string(MessageTypeResult),
-		Name:    r.Name,
-		Success: r.Success,
-		Output:  r.Output,
-		Error:   errStr,
-		Skipped: r.Skipped,
-		Reason:  r.Reason,
-	}
-	return jw.Write(msg)
+	return jw.Write(NewResultMessage(r, jw.correlationID))
 }
 
 // WriteProgress writes a progress update as JSON.
 func (jw *JSONWriter) WriteProgress(step, totalSteps int, stepName, status string) error {
 	msg := ProgressMessage{
-		Type:       string(MessageTypeProgress),
-		Step:       step,
-		TotalSteps: totalSteps,
-		StepName:   stepName,
-		Status:     status,
+		Type:          string(MessageTypeProgress),
+		Step:          step,
+		TotalSteps:    totalSteps,
+		StepName:      stepName,
+		Status:        status,
+		Timestamp:     Timestamp(),
+		CorrelationID: jw.correlationID,
 	}
 	return jw.Write(msg)
 }
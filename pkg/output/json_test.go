@@ -3,11 +3,18 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/plexusone/agent-team-release/pkg/actions"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
 	"github.com/plexusone/agent-team-release/pkg/interactive"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 func TestJSONWriter_WriteQuestion(t *testing.T) {
@@ -170,10 +177,12 @@ func TestJSONWriter_WriteResult(t *testing.T) {
 	writer := NewJSONWriter(&buf)
 
 	r := actions.Result{
-		Name:    "test-action",
-		Success: true,
-		Output:  "Action completed",
-		Skipped: false,
+		ID:       "action.test",
+		Name:     "test-action",
+		Success:  true,
+		Output:   "Action completed",
+		Skipped:  false,
+		Duration: 1500 * time.Millisecond,
 	}
 
 	err := writer.WriteResult(r)
@@ -186,6 +195,10 @@ func TestJSONWriter_WriteResult(t *testing.T) {
 		t.Fatalf("Failed to parse JSON: %v", err)
 	}
 
+	if msg.ID != "action.test" || msg.Severity != "passed" || msg.DurationMs != 1500 {
+		t.Errorf("ID, Severity, DurationMs = %s, %s, %d, want action.test, passed, 1500", msg.ID, msg.Severity, msg.DurationMs)
+	}
+
 	if msg.Type != string(MessageTypeResult) {
 		t.Errorf("Type = %s, want %s", msg.Type, MessageTypeResult)
 	}
@@ -313,3 +326,115 @@ func TestWorkflowResultMessage(t *testing.T) {
 		t.Error("JSON should contain 'Release'")
 	}
 }
+
+func TestJSONWriter_StampsMessages(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONWriter(&buf)
+
+	if writer.CorrelationID() == "" {
+		t.Fatal("CorrelationID() is empty, want generated ID")
+	}
+	writer.SetCorrelationID("run-123")
+
+	if err := writer.WriteInfo("first"); err != nil {
+		t.Fatalf("WriteInfo() error = %v", err)
+	}
+	if err := writer.WriteWarning("second"); err != nil {
+		t.Fatalf("WriteWarning() error = %v", err)
+	}
+
+	decoder := json.NewDecoder(&buf)
+	for i := 0; i < 2; i++ {
+		var msg Message
+		if err := decoder.Decode(&msg); err != nil {
+			t.Fatalf("Failed to parse message %d: %v", i, err)
+		}
+		if msg.CorrelationID != "run-123" {
+			t.Errorf("message %d CorrelationID = %s, want run-123", i, msg.CorrelationID)
+		}
+		if _, err := time.Parse(time.RFC3339, msg.Timestamp); err != nil {
+			t.Errorf("message %d Timestamp = %q, not RFC3339: %v", i, msg.Timestamp, err)
+		}
+	}
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	a := NewCorrelationID()
+	b := NewCorrelationID()
+
+	if a == "" || b == "" {
+		t.Fatal("NewCorrelationID() returned empty ID")
+	}
+	if a == b {
+		t.Errorf("NewCorrelationID() returned duplicate IDs: %s", a)
+	}
+}
+
+func TestJSONWriter_WriteCodedError(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONWriter(&buf)
+
+	if err := writer.WriteCodedError(ErrCodeTagExists, "tag v1.0.0 already exists", true); err != nil {
+		t.Fatalf("WriteCodedError() error = %v", err)
+	}
+
+	var msg ErrorMessage
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+
+	if msg.Code != "TAG_EXISTS" {
+		t.Errorf("Code = %s, want TAG_EXISTS", msg.Code)
+	}
+	if !msg.Fatal {
+		t.Error("Fatal should be true")
+	}
+}
+
+func TestJSONWriter_WriteErr(t *testing.T) {
+	var buf bytes.Buffer
+	writer := NewJSONWriter(&buf)
+	if err := writer.WriteErr(fmt.Errorf("%w: .releaseagent.yaml: bad yaml", config.ErrInvalid), true); err != nil {
+		t.Fatalf("WriteErr() error = %v", err)
+	}
+
+	var msg ErrorMessage
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if msg.Code != "CONFIG_INVALID" || msg.CorrelationID != writer.CorrelationID() || !msg.Fatal {
+		t.Errorf("message = %+v", msg)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"plain error", errors.New("boom"), ""},
+		{"coded", WithCode(ErrCodeGitDirty, errors.New("dirty")), ErrCodeGitDirty},
+		{"wrapped coded", fmt.Errorf("step: %w", WithCode(ErrCodeCheckFailed, errors.New("2 checks failed"))), ErrCodeCheckFailed},
+		{"missing executable", &exec.Error{Name: "releasekit", Err: exec.ErrNotFound}, ErrCodeToolMissing},
+		{"tool not found", fmt.Errorf("listing issues: %w", proc.NotFound("gh CLI")), ErrCodeToolMissing},
+		{"invalid config", fmt.Errorf("%w: bad yaml", config.ErrInvalid), ErrCodeConfigInvalid},
+		{"unavailable policy", fmt.Errorf("%w: extends github.com/org/policy@v1", config.ErrPolicy), ErrCodeConfigInvalid},
+		{"CI timeout", fmt.Errorf("%w after 10m", git.ErrCITimeout), ErrCodeCITimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCode_Nil(t *testing.T) {
+	if err := WithCode(ErrCodeCheckFailed, nil); err != nil {
+		t.Errorf("WithCode(nil) = %v, want nil", err)
+	}
+}
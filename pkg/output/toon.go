@@ -12,18 +12,30 @@ import (
 
 // TOONWriter writes TOON-formatted messages to an output stream.
 type TOONWriter struct {
-	writer  io.Writer
-	encoder *toon.Encoder
+	writer        io.Writer
+	encoder       *toon.Encoder
+	correlationID string
 }
 
-// NewTOONWriter creates a new TOONWriter.
+// NewTOONWriter creates a new TOONWriter with a fresh correlation ID.
 func NewTOONWriter(w io.Writer) *TOONWriter {
 	return &TOONWriter{
-		writer:  w,
-		encoder: toon.NewEncoder(toon.WithIndent(2)),
+		writer:        w,
+		encoder:       toon.NewEncoder(toon.WithIndent(2)),
+		correlationID: NewCorrelationID(),
 	}
 }
 
+// CorrelationID returns the correlation ID stamped on every message.
+func (tw *TOONWriter) CorrelationID() string {
+	return tw.correlationID
+}
+
+// SetCorrelationID overrides the correlation ID, e.g. to match a workflow run.
+func (tw *TOONWriter) SetCorrelationID(id string) {
+	tw.correlationID = id
+}
+
 // DefaultTOONWriter returns a TOONWriter writing to stdout.
 func DefaultTOONWriter() *TOONWriter {
 	return NewTOONWriter(os.Stdout)
@@ -56,15 +68,17 @@ func (tw *TOONWriter) WriteQuestion(q interactive.Question) error {
 	}
 
 	msg := QuestionMessage{
-		Type:       string(MessageTypeQuestion),
-		ID:         q.ID,
-		Question:   q.Text,
-		InputType:  q.Type.String(),
-		Options:    options,
-		Default:    q.Default,
-		Context:    q.Context,
-		Required:   true,
-		WaitingFor: "user_input",
+		Type:          string(MessageTypeQuestion),
+		ID:            q.ID,
+		Question:      q.Text,
+		InputType:     q.Type.String(),
+		Options:       options,
+		Default:       q.Default,
+		Context:       q.Context,
+		Required:      true,
+		WaitingFor:    "user_input",
+		Timestamp:     Timestamp(),
+		CorrelationID: tw.correlationID,
 	}
 	return tw.Write(msg)
 }
@@ -72,14 +86,16 @@ func (tw *TOONWriter) WriteQuestion(q interactive.Question) error {
 // WriteProposal writes a proposal as TOON.
 func (tw *TOONWriter) WriteProposal(p actions.Proposal) error {
 	msg := ProposalMessage{
-		Type:        string(MessageTypeProposal),
-		Description: p.Description,
-		FilePath:    p.FilePath,
-		OldContent:  p.OldContent,
-		NewContent:  p.NewContent,
-		Metadata:    p.Metadata,
-		WaitingFor:  "user_approval",
-		Actions:     []string{"apply", "skip", "abort"},
+		Type:          string(MessageTypeProposal),
+		Description:   p.Description,
+		FilePath:      p.FilePath,
+		OldContent:    p.OldContent,
+		NewContent:    p.NewContent,
+		Metadata:      p.Metadata,
+		WaitingFor:    "user_approval",
+		Actions:       []string{"apply", "skip", "abort"},
+		Timestamp:     Timestamp(),
+		CorrelationID: tw.correlationID,
 	}
 	return tw.Write(msg)
 }
@@ -87,8 +103,10 @@ func (tw *TOONWriter) WriteProposal(p actions.Proposal) error {
 // WriteInfo writes an informational message as TOON.
 func (tw *TOONWriter) WriteInfo(text string) error {
 	msg := InfoMessage{
-		Type: string(MessageTypeInfo),
-		Text: text,
+		Type:          string(MessageTypeInfo),
+		Text:          text,
+		Timestamp:     Timestamp(),
+		CorrelationID: tw.correlationID,
 	}
 	return tw.Write(msg)
 }
@@ -96,48 +114,53 @@ func (tw *TOONWriter) WriteInfo(text string) error {
 // WriteWarning writes a warning message as TOON.
 func (tw *TOONWriter) WriteWarning(text string) error {
 	msg := WarningMessage{
-		Type: string(MessageTypeWarning),
-		Text: text,
+		Type:          string(MessageTypeWarning),
+		Text:          text,
+		Timestamp:     Timestamp(),
+		CorrelationID: tw.correlationID,
 	}
 	return tw.Write(msg)
 }
 
 // WriteError writes an error message as TOON.
 func (tw *TOONWriter) WriteError(text string, fatal bool) error {
+	return tw.WriteCodedError("", text, fatal)
+}
+
+// WriteCodedError writes an error message with a stable error code as TOON.
+func (tw *TOONWriter) WriteCodedError(code ErrorCode, text string, fatal bool) error {
 	msg := ErrorMessage{
-		Type:  string(MessageTypeError),
-		Text:  text,
-		Fatal: fatal,
+		Type:          string(MessageTypeError),
+		Text:          text,
+		Code:          string(code),
+		Fatal:         fatal,
+		Timestamp:     Timestamp(),
+		CorrelationID: tw.correlationID,
 	}
 	return tw.Write(msg)
 }
 
+// WriteErr writes err as an error message as TOON, with the code CodeOf
+// finds for it.
+func (tw *TOONWriter) WriteErr(err error, fatal bool) error {
+	return tw.Write(NewErrorMessage(err, fatal, tw.correlationID))
+}
+
 // WriteResult writes an action result as TOON.
 func (tw *TOONWriter) WriteResult(r actions.Result) error {
-	errStr := ""
-	if r.Error != nil {
-		errStr = r.Error.Error()
-	}
-	msg := ResultMessage{
-		Type:    string(MessageTypeResult),
-		Name:    r.Name,
-		Success: r.Success,
-		Output:  r.Output,
-		Error:   errStr,
-		Skipped: r.Skipped,
-		Reason:  r.Reason,
-	}
-	return tw.Write(msg)
+	return tw.Write(NewResultMessage(r, tw.correlationID))
 }
 
 // WriteProgress writes a progress update as TOON.
 func (tw *TOONWriter) WriteProgress(step, totalSteps int, stepName, status string) error {
 	msg := ProgressMessage{
-		Type:       string(MessageTypeProgress),
-		Step:       step,
-		TotalSteps: totalSteps,
-		StepName:   stepName,
-		Status:     status,
+		Type:          string(MessageTypeProgress),
+		Step:          step,
+		TotalSteps:    totalSteps,
+		StepName:      stepName,
+		Status:        status,
+		Timestamp:     Timestamp(),
+		CorrelationID: tw.correlationID,
 	}
 	return tw.Write(msg)
 }
@@ -0,0 +1,167 @@
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDryRun is returned by commands that were not run because they would
+// write to the repository or call a remote service during a dry run.
+var ErrDryRun = errors.New("dry run")
+
+var (
+	dryRun  bool
+	blocked []string
+)
+
+// SetDryRun makes commands that write or call a remote service fail with
+// ErrDryRun instead of running, until the returned function restores the
+// previous setting. Read-only commands, such as git status or go test,
+// still run. Steps should skip such commands themselves in a dry run; this
+// guarantees they can't slip through.
+func SetDryRun(b bool) (restore func()) {
+	mu.Lock()
+	prev := dryRun
+	dryRun = b
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		dryRun = prev
+		mu.Unlock()
+	}
+}
+
+// IsDryRun reports whether commands run in dry-run mode.
+func IsDryRun() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return dryRun
+}
+
+// Blocked returns the command lines that were not run because of dry-run
+// mode, in order.
+func Blocked() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), blocked...)
+}
+
+// blockDryRun records a blocked command and returns the error it fails
+// with.
+func blockDryRun(name string, args []string) error {
+	line := strings.Join(append([]string{name}, args...), " ")
+	mu.Lock()
+	blocked = append(blocked, line)
+	mu.Unlock()
+	return fmt.Errorf("%w: would run %s", ErrDryRun, line)
+}
+
+// alwaysMutating are tools that publish, sync, or write files on every
+// invocation.
+var alwaysMutating = map[string]bool{
+	"gh":           true, // every gh command calls the GitHub API
+	"cosign":       true,
+	"gocoverbadge": true,
+	"goreleaser":   true,
+	"rsync":        true,
+	"ssh":          true,
+}
+
+// readOnlySubcommands are the subcommands of tools that otherwise write.
+var readOnlySubcommands = map[string]map[string]bool{
+	"schangelog": {"parse-commits": true, "validate": true},
+	"sroadmap":   {"validate": true, "stats": true},
+}
+
+// gitReadOnly are git subcommands that neither write to the repository nor
+// contact a remote.
+var gitReadOnly = map[string]bool{
+	"blame": true, "cat-file": true, "check-ignore": true, "cherry": true,
+	"describe": true, "diff": true, "for-each-ref": true, "grep": true,
+	"log": true, "ls-files": true, "ls-tree": true, "merge-base": true,
+	"name-rev": true, "rev-list": true, "rev-parse": true, "shortlog": true,
+	"show": true, "status": true, "var": true, "version": true,
+}
+
+// Mutates reports whether running name with args may write to the
+// repository or call a remote service, and so is blocked in a dry run.
+func Mutates(name string, args []string) bool {
+	if alwaysMutating[name] {
+		return true
+	}
+	if sub, ok := readOnlySubcommands[name]; ok {
+		return len(args) == 0 || !sub[args[0]]
+	}
+	if name == "git" {
+		return gitMutates(args)
+	}
+	return false
+}
+
+// gitMutates reports whether a git command may write or contact a remote.
+// Subcommands that both read and write are read-only only in their listing
+// forms.
+func gitMutates(args []string) bool {
+	// Skip global options such as -C dir and -c key=value
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if args[0] == "-C" || args[0] == "-c" {
+			args = args[1:]
+		}
+		if len(args) > 0 {
+			args = args[1:]
+		}
+	}
+	if len(args) == 0 {
+		return false
+	}
+	sub, rest := args[0], args[1:]
+	if gitReadOnly[sub] {
+		return false
+	}
+	pos := positional(rest)
+	switch sub {
+	case "branch", "tag":
+		// Listing, unless creating, deleting, or renaming
+		return len(pos) > 0 && !hasFlag(rest, "-l", "--list", "--contains", "--merged", "--no-merged", "--points-at") ||
+			hasFlag(rest, "-d", "-D", "--delete", "-m", "-M", "--move", "-c", "-C", "--copy", "-f", "--force", "-a", "-s", "-u")
+	case "remote":
+		return !(len(pos) == 0 || pos[0] == "get-url")
+	case "config":
+		return len(pos) > 1 || hasFlag(rest, "--unset", "--unset-all", "--add", "--replace-all", "--rename-section", "--remove-section", "-e", "--edit")
+	case "symbolic-ref":
+		return len(pos) > 1 || hasFlag(rest, "-d", "--delete")
+	case "stash":
+		return !(len(pos) > 0 && (pos[0] == "list" || pos[0] == "show"))
+	case "worktree":
+		// Checks build and test other revisions in temporary worktrees,
+		// which only leave metadata in .git until they are removed.
+		return !(len(pos) > 0 && (pos[0] == "list" || pos[0] == "add" || pos[0] == "remove" || pos[0] == "prune"))
+	}
+	return true
+}
+
+// positional returns the arguments of args that aren't options.
+func positional(args []string) []string {
+	var pos []string
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			pos = append(pos, a)
+		}
+	}
+	return pos
+}
+
+// hasFlag reports whether args contains one of flags, with or without a
+// "=value".
+func hasFlag(args []string, flags ...string) bool {
+	for _, a := range args {
+		name, _, _ := strings.Cut(a, "=")
+		for _, f := range flags {
+			if name == f {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package proc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMutates(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{"git status --porcelain", false},
+		{"git -C repo log -1 --format=%s", false},
+		{"git rev-parse --abbrev-ref HEAD", false},
+		{"git tag --sort=-version:refname", false},
+		{"git tag -l v1.*", false},
+		{"git branch --contains abc123", false},
+		{"git config user.name", false},
+		{"git remote get-url origin", false},
+		{"git symbolic-ref --short refs/remotes/origin/HEAD", false},
+		{"git worktree add --detach /tmp/wt HEAD", false},
+		{"git tag -a v1.0.0 -m release", true},
+		{"git tag v1.0.0", true},
+		{"git tag -d v1.0.0", true},
+		{"git branch -D release/v1.0.0", true},
+		{"git config user.name someone", true},
+		{"git commit -m msg", true},
+		{"git push origin v1.0.0", true},
+		{"git fetch origin", true},
+		{"git ls-remote --tags origin", true},
+		{"git stash push -m checks", true},
+		{"gh pr view 1", true},
+		{"gocoverbadge -dir . -badge-only", true},
+		{"schangelog parse-commits --since=v1.0.0", false},
+		{"schangelog generate CHANGELOG.json -o CHANGELOG.md", true},
+		{"sroadmap stats ROADMAP.json", false},
+		{"go test ./...", false},
+	}
+	for _, tt := range tests {
+		fields := strings.Fields(tt.cmd)
+		if got := Mutates(fields[0], fields[1:]); got != tt.want {
+			t.Errorf("Mutates(%q) = %v, want %v", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_DryRun(t *testing.T) {
+	defer SetDryRun(true)()
+
+	err := Command("git", "push", "origin", "main").Run()
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("Run() = %v, want ErrDryRun", err)
+	}
+	if blocked := Blocked(); len(blocked) == 0 || blocked[len(blocked)-1] != "git push origin main" {
+		t.Errorf("Blocked() = %v", blocked)
+	}
+
+	if cmd := Command("git", "status"); cmd.Err != nil {
+		t.Errorf("read-only command blocked: %v", cmd.Err)
+	}
+}
@@ -0,0 +1,115 @@
+// Package proc starts external processes under a shared cancellation
+// context, so an aborted workflow doesn't leave git, go, or npm processes
+// running behind it, and with a shared extra environment. In a dry run it
+// refuses to start commands that would write or call a remote service.
+package proc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// WaitDelay is how long a cancelled process may take to exit, and its
+// output pipes to close, before it is abandoned.
+const WaitDelay = 5 * time.Second
+
+var (
+	mu      sync.RWMutex
+	current = context.Background()
+	env     []string
+)
+
+// SetContext makes ctx the context new processes run under until the
+// returned function restores the previous one. When ctx is cancelled,
+// processes started under it are killed.
+func SetContext(ctx context.Context) (restore func()) {
+	mu.Lock()
+	prev := current
+	current = ctx
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		current = prev
+		mu.Unlock()
+	}
+}
+
+// Context returns the context new processes run under.
+func Context() context.Context {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetEnv adds env, a list of "KEY=value" entries, to the environment of
+// new processes until the returned function restores the previous one.
+func SetEnv(e []string) (restore func()) {
+	mu.Lock()
+	prev := env
+	env = e
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		env = prev
+		mu.Unlock()
+	}
+}
+
+// Command returns an exec.Cmd for name that is killed when the current
+// context is cancelled and has the environment set with SetEnv. In a dry
+// run (see SetDryRun), a command that Mutates fails with ErrDryRun without
+// starting. Use it instead of exec.Command.
+func Command(name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(Context(), name, args...)
+	cmd.WaitDelay = WaitDelay
+	mu.RLock()
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	block := dryRun && Mutates(filepath.Base(name), args)
+	mu.RUnlock()
+	if block {
+		cmd.Err = blockDryRun(name, args)
+	}
+	return cmd
+}
+
+// Sleep pauses for d, returning early with the context's error if the
+// current context is cancelled. Use it for polling loops.
+func Sleep(d time.Duration) error {
+	ctx := Context()
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notFoundError is the error of a tool that isn't installed.
+type notFoundError struct {
+	tool string
+}
+
+func (e *notFoundError) Error() string {
+	return e.tool + " not found in PATH"
+}
+
+// Unwrap returns exec.ErrNotFound, so the error matches errors.Is(err,
+// exec.ErrNotFound) like a command that failed to start.
+func (e *notFoundError) Unwrap() error {
+	return exec.ErrNotFound
+}
+
+// NotFound returns the error for tool not being installed, e.g.
+// "gh CLI not found in PATH". It wraps exec.ErrNotFound, which
+// output.CodeOf reports as TOOL_MISSING.
+func NotFound(tool string) error {
+	return &notFoundError{tool: tool}
+}
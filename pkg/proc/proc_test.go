@@ -0,0 +1,61 @@
+package proc
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCommand_Cancelled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer SetContext(ctx)()
+
+	cmd := Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Error("expected the cancelled process to fail")
+	}
+	if elapsed := time.Since(start); elapsed > WaitDelay {
+		t.Errorf("process took %s to stop", elapsed)
+	}
+}
+
+func TestSetContext_Restore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	restore := SetContext(ctx)
+	if Context() != ctx {
+		t.Error("SetContext didn't set the context")
+	}
+	if err := Sleep(time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("Sleep() = %v, want context.Canceled", err)
+	}
+	restore()
+	if Context() != context.Background() {
+		t.Error("restore didn't restore the previous context")
+	}
+	if err := Sleep(time.Millisecond); err != nil {
+		t.Errorf("Sleep() = %v, want nil", err)
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	restore := SetEnv([]string{"GOFLAGS=-tags=integration"})
+	cmd := Command("go", "env", "GOFLAGS")
+	if got := cmd.Env[len(cmd.Env)-1]; got != "GOFLAGS=-tags=integration" {
+		t.Errorf("last environment entry = %q, want the one set", got)
+	}
+	restore()
+	if cmd := Command("go", "env"); cmd.Env != nil {
+		t.Errorf("expected the inherited environment after restore, got %d entries", len(cmd.Env))
+	}
+}
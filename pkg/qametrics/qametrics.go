@@ -0,0 +1,140 @@
+// Package qametrics records the QA metrics of each release, such as the
+// number of tests and the coverage, in a file committed with the release.
+// Comparing a release candidate with the previous release gates on trends
+// rather than only on the checks passing at one point in time.
+package qametrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/semver"
+)
+
+// Dir is the directory, relative to the project, holding the metrics
+// file. Unlike the local run history, it is committed.
+const Dir = ".release-agent"
+
+// FileName is the metrics file name within Dir.
+const FileName = "metrics.json"
+
+// Metrics are the QA metrics of one release.
+type Metrics struct {
+	Version    string    `json:"version"`
+	Recorded   time.Time `json:"recorded"`
+	Tests      int       `json:"tests"`              // Test functions and cases
+	Coverage   *float64  `json:"coverage,omitempty"` // Percent of statements; absent if not measured
+	LintIssues int       `json:"lint_issues"`        // Findings reported by the linters
+	BuildMs    int64     `json:"build_ms"`           // Time the build checks took; 0 if none ran
+}
+
+// History is the contents of the metrics file: the metrics of each
+// recorded release, oldest first.
+type History struct {
+	Releases []Metrics `json:"releases"`
+}
+
+// Path returns the metrics file of dir.
+func Path(dir string) string {
+	return filepath.Join(dir, Dir, FileName)
+}
+
+// Load reads the metrics file of dir. It returns an empty history if
+// there is none.
+func Load(dir string) (History, error) {
+	var h History
+	data, err := os.ReadFile(Path(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("%s: %w", filepath.Join(Dir, FileName), err)
+	}
+	return h, nil
+}
+
+// Save writes h to the metrics file of dir, creating Dir if needed.
+func Save(dir string, h History) error {
+	if err := os.MkdirAll(filepath.Join(dir, Dir), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(dir), append(data, '\n'), 0644)
+}
+
+// Record adds the metrics of a release to h, replacing any recorded for
+// the same version, and keeps the releases in version order.
+func (h *History) Record(m Metrics) {
+	h.Releases = slices.DeleteFunc(h.Releases, func(r Metrics) bool { return r.Version == m.Version })
+	h.Releases = append(h.Releases, m)
+	slices.SortStableFunc(h.Releases, func(a, b Metrics) int {
+		if !semver.IsValid(a.Version) || !semver.IsValid(b.Version) {
+			return 0
+		}
+		return semver.Compare(a.Version, b.Version)
+	})
+}
+
+// Previous returns the metrics of the latest release before version, or
+// of the latest release if version isn't a valid version.
+func (h History) Previous(version string) (Metrics, bool) {
+	for i := len(h.Releases) - 1; i >= 0; i-- {
+		r := h.Releases[i]
+		if r.Version == version {
+			continue
+		}
+		if !semver.IsValid(version) || !semver.IsValid(r.Version) || semver.Compare(r.Version, version) < 0 {
+			return r, true
+		}
+	}
+	return Metrics{}, false
+}
+
+// Limits are how far metrics may move against the previous release
+// before it counts as a regression. Fewer tests and more lint issues
+// always count.
+type Limits struct {
+	MaxCoverageDrop  float64 // Percentage points
+	MaxBuildIncrease float64 // Percent
+}
+
+// minBuildIncrease is the build time increase below which a slower build
+// is noise, whatever the percentage.
+const minBuildIncrease = time.Second
+
+// Compare returns the regressions of cur against prev, one line each,
+// e.g. "tests: 120 -> 112 (-8)".
+func Compare(prev, cur Metrics, limits Limits) []string {
+	var regressions []string
+	if cur.Tests < prev.Tests {
+		regressions = append(regressions, fmt.Sprintf("tests: %d -> %d (%+d)", prev.Tests, cur.Tests, cur.Tests-prev.Tests))
+	}
+	if prev.Coverage != nil && cur.Coverage != nil && *prev.Coverage-*cur.Coverage > limits.MaxCoverageDrop {
+		regressions = append(regressions, fmt.Sprintf("coverage: %.1f%% -> %.1f%% (%+.1f)",
+			*prev.Coverage, *cur.Coverage, *cur.Coverage-*prev.Coverage))
+	}
+	if cur.LintIssues > prev.LintIssues {
+		regressions = append(regressions, fmt.Sprintf("lint issues: %d -> %d (%+d)",
+			prev.LintIssues, cur.LintIssues, cur.LintIssues-prev.LintIssues))
+	}
+	if prev.BuildMs > 0 && cur.BuildMs > 0 {
+		increase := time.Duration(cur.BuildMs-prev.BuildMs) * time.Millisecond
+		percent := float64(cur.BuildMs-prev.BuildMs) / float64(prev.BuildMs) * 100
+		if increase >= minBuildIncrease && percent > limits.MaxBuildIncrease {
+			regressions = append(regressions, fmt.Sprintf("build time: %s -> %s (%+.0f%%)",
+				time.Duration(prev.BuildMs)*time.Millisecond, time.Duration(cur.BuildMs)*time.Millisecond, percent))
+		}
+	}
+	return regressions
+}
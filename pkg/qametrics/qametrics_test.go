@@ -0,0 +1,112 @@
+package qametrics
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ptr(f float64) *float64 { return &f }
+
+func TestHistory_RecordAndPrevious(t *testing.T) {
+	dir := t.TempDir()
+
+	h, err := Load(dir)
+	if err != nil || len(h.Releases) != 0 {
+		t.Fatalf("Load() = %+v, %v, want an empty history", h, err)
+	}
+	if _, ok := h.Previous("v1.0.0"); ok {
+		t.Error("Previous() of an empty history found a release")
+	}
+
+	h.Record(Metrics{Version: "v1.10.0", Tests: 30})
+	h.Record(Metrics{Version: "v1.2.0", Tests: 10})
+	h.Record(Metrics{Version: "v1.9.0", Tests: 20})
+	h.Record(Metrics{Version: "v1.9.0", Tests: 25})
+	if err := Save(dir, h); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, r := range h.Releases {
+		versions = append(versions, r.Version)
+	}
+	if want := []string{"v1.2.0", "v1.9.0", "v1.10.0"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("versions = %v, want %v", versions, want)
+	}
+
+	for version, want := range map[string]string{
+		"v1.10.0": "v1.9.0",  // re-validating a recorded release
+		"v1.9.5":  "v1.9.0",  // between releases
+		"v2.0.0":  "v1.10.0", // next release
+		"":        "v1.10.0", // version unknown
+	} {
+		if got, ok := h.Previous(version); !ok || got.Version != want {
+			t.Errorf("Previous(%q) = %s, %v, want %s", version, got.Version, ok, want)
+		}
+	}
+	if got, ok := h.Previous("v1.2.0"); ok {
+		t.Errorf("Previous(v1.2.0) = %s, want none", got.Version)
+	}
+	if got, _ := h.Previous("v2.0.0"); got.Tests != 30 {
+		t.Errorf("Previous(v2.0.0).Tests = %d", got.Tests)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	limits := Limits{MaxCoverageDrop: 1, MaxBuildIncrease: 25}
+	prev := Metrics{Version: "v1.0.0", Tests: 120, Coverage: ptr(80), LintIssues: 2, BuildMs: 10000}
+
+	tests := []struct {
+		name string
+		cur  Metrics
+		want []string
+	}{
+		{"unchanged", prev, nil},
+		{"within limits", Metrics{Tests: 130, Coverage: ptr(79.5), LintIssues: 1, BuildMs: 12000}, nil},
+		{"coverage not measured", Metrics{Tests: 120, LintIssues: 2, BuildMs: 10000}, nil},
+		{
+			"regressed",
+			Metrics{Tests: 112, Coverage: ptr(77.5), LintIssues: 5, BuildMs: 15000},
+			[]string{
+				"tests: 120 -> 112 (-8)",
+				"coverage: 80.0% -> 77.5% (-2.5)",
+				"lint issues: 2 -> 5 (+3)",
+				"build time: 10s -> 15s (+50%)",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(prev, tt.cur, limits)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compare() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// A build that is far slower in percent but less than a second slower
+	// is noise
+	fast := Metrics{Tests: 1, BuildMs: 400}
+	if got := Compare(fast, Metrics{Tests: 1, BuildMs: 900}, limits); len(got) != 0 {
+		t.Errorf("Compare() = %q, want none", got)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := Save(dir, History{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(Path(dir), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), FileName) {
+		t.Errorf("Load() error = %v, want one naming %s", err, FileName)
+	}
+}
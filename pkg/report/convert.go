@@ -6,6 +6,7 @@ package report
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -44,57 +45,42 @@ func FromValidationReport(vr *checks.ValidationReport, project, target, phase st
 		configMap[c.Area] = c
 	}
 
-	var teams []multiagentspec.TeamSection
+	// Areas beyond the defaults come after PM, or the areas they depend
+	// on, and before Release
 	for _, ar := range vr.Areas {
-		config, ok := configMap[ar.Area]
-		if !ok {
-			config = TeamConfig{
-				ID:   strings.ToLower(string(ar.Area)) + "-validation",
-				Name: strings.ToLower(string(ar.Area)),
+		if _, ok := configMap[ar.Area]; ok {
+			continue
+		}
+		deps := ar.DependsOn
+		if len(deps) == 0 {
+			deps = []checks.ValidationArea{checks.AreaPM}
+		}
+		config := TeamConfig{
+			Area: ar.Area,
+			ID:   customTeamID(ar.Area),
+			Name: ar.Area.Key(),
+		}
+		for _, d := range deps {
+			if c, ok := configMap[d]; ok {
+				config.DependsOn = append(config.DependsOn, c.ID)
+			} else {
+				config.DependsOn = append(config.DependsOn, customTeamID(d))
 			}
 		}
+		configMap[ar.Area] = config
 
-		var teamTasks []multiagentspec.TaskResult
-		for _, r := range ar.Results {
-			status := multiagentspec.StatusGo
-			if r.Skipped {
-				status = multiagentspec.StatusSkip
-			} else if r.Warning && !r.Passed {
-				status = multiagentspec.StatusWarn
-			} else if !r.Passed {
-				status = multiagentspec.StatusNoGo
-			}
+		release := configMap[checks.AreaRelease]
+		release.DependsOn = append(slices.Clone(release.DependsOn), config.ID)
+		configMap[checks.AreaRelease] = release
+	}
 
-			// Extract check ID from name (e.g., "Go: build" -> "build")
-			id := r.Name
-			if idx := strings.Index(id, ": "); idx >= 0 {
-				id = id[idx+2:]
-			}
-			// Convert to kebab-case
-			id = strings.ToLower(strings.ReplaceAll(id, " ", "-"))
-
-			// Use output as detail, truncate if needed
-			detail := ""
-			if r.Output != "" {
-				detail = r.Output
-				// Take first line only
-				if idx := strings.Index(detail, "\n"); idx >= 0 {
-					detail = detail[:idx]
-				}
-				// Truncate
-				if len(detail) > 40 {
-					detail = detail[:37] + "..."
-				}
-			}
-			if r.Reason != "" && detail == "" {
-				detail = r.Reason
-			}
+	var teams []multiagentspec.TeamSection
+	for _, ar := range vr.Areas {
+		config := configMap[ar.Area]
 
-			teamTasks = append(teamTasks, multiagentspec.TaskResult{
-				ID:     id,
-				Status: status,
-				Detail: detail,
-			})
+		var teamTasks []multiagentspec.TaskResult
+		for _, r := range ar.Results {
+			teamTasks = append(teamTasks, checks.ToTaskResult(r))
 		}
 
 		team := multiagentspec.TeamSection{
@@ -123,6 +109,12 @@ func FromValidationReport(vr *checks.ValidationReport, project, target, phase st
 	return report
 }
 
+// customTeamID returns the team ID of an area beyond the defaults, e.g.
+// "legal-review-validation" for "Legal Review".
+func customTeamID(area checks.ValidationArea) string {
+	return strings.ReplaceAll(area.Key(), "_", "-") + "-validation"
+}
+
 // PMTeam creates a Product Management validation team section.
 func PMTeam(version string, roadmapTotal, roadmapCompleted int, hasHighlights, hasBreaking, hasDeprecations bool) multiagentspec.TeamSection {
 	teamTasks := []multiagentspec.TaskResult{
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	multiagentspec "github.com/plexusone/multi-agent-spec/sdk/go"
+	"github.com/plexusone/agent-team-release/pkg/checks"
 )
 
 func TestStatusIcon(t *testing.T) {
@@ -219,3 +220,34 @@ func TestRendererNoGo(t *testing.T) {
 		t.Error("Output should contain NO-GO message")
 	}
 }
+
+func TestFromValidationReport_CustomAreas(t *testing.T) {
+	vr := &checks.ValidationReport{Areas: []checks.AreaResult{
+		{Area: checks.AreaPM, Status: checks.StatusGo},
+		{Area: checks.AreaRelease, Status: checks.StatusGo},
+		{Area: "Legal Review", Status: checks.StatusGo},
+		{Area: "Localization", Status: checks.StatusNoGo, DependsOn: []checks.ValidationArea{"Legal Review", checks.AreaQA},
+			Results: []checks.Result{{Name: "Localization: translations"}}},
+	}}
+
+	tr := FromValidationReport(vr, "project", "v1.0.0", "review")
+	deps := make(map[string][]string)
+	for _, team := range tr.Teams {
+		deps[team.ID] = team.DependsOn
+	}
+	if got := deps["legal-review-validation"]; strings.Join(got, ",") != "pm-validation" {
+		t.Errorf("legal-review-validation depends on %v, want pm-validation", got)
+	}
+	if got := deps["localization-validation"]; strings.Join(got, ",") != "legal-review-validation,qa-validation" {
+		t.Errorf("localization-validation depends on %v", got)
+	}
+	if got := strings.Join(deps["release-validation"], ","); !strings.HasSuffix(got, ",legal-review-validation,localization-validation") {
+		t.Errorf("release-validation depends on %s, want the custom areas last", got)
+	}
+	if tr.Status != multiagentspec.StatusNoGo {
+		t.Errorf("Status = %s, want NO-GO from the custom area", tr.Status)
+	}
+	if got := strings.Join(DefaultTeamConfigs()[4].DependsOn, ","); strings.Contains(got, "legal") {
+		t.Errorf("DefaultTeamConfigs() modified: %s", got)
+	}
+}
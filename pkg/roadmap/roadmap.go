@@ -0,0 +1,342 @@
+// Package roadmap reads and updates ROADMAP.json files in the structured
+// roadmap IR format used by sroadmap.
+package roadmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// DefaultFile is the conventional roadmap file name.
+const DefaultFile = "ROADMAP.json"
+
+// Status values used by roadmap items and phases.
+const (
+	StatusCompleted  = "completed"
+	StatusInProgress = "in_progress"
+	StatusPlanned    = "planned"
+	StatusFuture     = "future"
+)
+
+// Roadmap is a parsed ROADMAP.json. Top-level fields other than phases and
+// items are preserved verbatim, in their original order, by Save.
+type Roadmap struct {
+	Project string  `json:"project,omitempty"`
+	Phases  []Phase `json:"phases,omitempty"`
+	Items   []Item  `json:"items"`
+
+	keys []string
+	raw  map[string]json.RawMessage
+}
+
+// Phase is a release phase, typically one per minor version. Fields other
+// than the ones below are preserved, in their original order, by Save.
+type Phase struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Order       int    `json:"order"`
+	Description string `json:"description,omitempty"`
+
+	keys []string
+	raw  map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes a phase, keeping its unknown fields for Save.
+func (p *Phase) UnmarshalJSON(data []byte) error {
+	type plain Phase
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	var err error
+	p.keys, p.raw, err = orderedFields(data)
+	return err
+}
+
+// MarshalJSON encodes a phase along with the unknown fields it was loaded
+// with.
+func (p Phase) MarshalJSON() ([]byte, error) {
+	type plain Phase
+	return marshalPreserved(plain(p), p.keys, p.raw)
+}
+
+// Item is a single roadmap entry. Fields other than the ones below are
+// preserved, in their original order, by Save.
+type Item struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status"`
+	Version     string `json:"version,omitempty"`
+	Phase       string `json:"phase,omitempty"`
+	Area        string `json:"area,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	Issue       int    `json:"issue,omitempty"` // linked GitHub issue number
+
+	keys []string
+	raw  map[string]json.RawMessage
+}
+
+// UnmarshalJSON decodes an item, keeping its unknown fields for Save.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type plain Item
+	if err := json.Unmarshal(data, (*plain)(i)); err != nil {
+		return err
+	}
+	var err error
+	i.keys, i.raw, err = orderedFields(data)
+	return err
+}
+
+// MarshalJSON encodes an item along with the unknown fields it was loaded
+// with.
+func (i Item) MarshalJSON() ([]byte, error) {
+	type plain Item
+	return marshalPreserved(plain(i), i.keys, i.raw)
+}
+
+// New returns an empty roadmap for project.
+func New(project string) *Roadmap {
+	return &Roadmap{
+		Project: project,
+		keys:    []string{"ir_version", "project", "phases", "items"},
+		raw:     map[string]json.RawMessage{"ir_version": json.RawMessage(`"1.0"`)},
+	}
+}
+
+// Load reads and parses a ROADMAP.json file.
+func Load(path string) (*Roadmap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Roadmap
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if r.keys, r.raw, err = orderedFields(data); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Save writes the roadmap to path, preserving unknown top-level fields.
+func (r *Roadmap) Save(path string) error {
+	keys := r.keys
+	raw := r.raw
+	if raw == nil {
+		n := New(r.Project)
+		keys, raw = n.keys, n.raw
+	}
+
+	values := make(map[string]any, len(keys))
+	for k, v := range raw {
+		values[k] = v
+	}
+	values["project"] = r.Project
+	values["phases"] = r.Phases
+	values["items"] = r.Items
+	if r.Phases == nil {
+		values["phases"] = []Phase{}
+	}
+	if r.Items == nil {
+		values["items"] = []Item{}
+	}
+	for _, k := range []string{"project", "phases", "items"} {
+		if !contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, k := range keys {
+		data, err := json.Marshal(values[k])
+		if err != nil {
+			return fmt.Errorf("encoding %s: %w", k, err)
+		}
+		name, _ := json.Marshal(k)
+		buf.WriteString("  ")
+		buf.Write(name)
+		buf.WriteString(": ")
+		if err := json.Indent(&buf, data, "  ", "  "); err != nil {
+			return fmt.Errorf("encoding %s: %w", k, err)
+		}
+		if i < len(keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Outstanding returns the items that are not completed.
+func (r *Roadmap) Outstanding() []Item {
+	var items []Item
+	for _, item := range r.Items {
+		if item.Status != StatusCompleted {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ItemsFor returns the items targeting version, ignoring a "v" prefix,
+// which sroadmap omits.
+func (r *Roadmap) ItemsFor(version string) []Item {
+	var items []Item
+	for _, item := range r.Items {
+		if item.Version != "" && strings.TrimPrefix(item.Version, "v") == strings.TrimPrefix(version, "v") {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Phase returns the phase with the given ID, or nil.
+func (r *Roadmap) Phase(id string) *Phase {
+	for i := range r.Phases {
+		if r.Phases[i].ID == id {
+			return &r.Phases[i]
+		}
+	}
+	return nil
+}
+
+// Item returns the item with the given ID, or nil.
+func (r *Roadmap) Item(id string) *Item {
+	for i := range r.Items {
+		if r.Items[i].ID == id {
+			return &r.Items[i]
+		}
+	}
+	return nil
+}
+
+// UpsertItem replaces the item with the same ID, or appends it. It reports
+// whether the item was added. A replaced item keeps its unknown fields.
+func (r *Roadmap) UpsertItem(item Item) bool {
+	for i := range r.Items {
+		if r.Items[i].ID == item.ID {
+			if item.raw == nil {
+				item.keys, item.raw = r.Items[i].keys, r.Items[i].raw
+			}
+			r.Items[i] = item
+			return false
+		}
+	}
+	r.Items = append(r.Items, item)
+	return true
+}
+
+// orderedFields returns the top-level keys of a JSON object in document order
+// along with their raw values.
+func orderedFields(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	var keys []string
+	raw := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("unexpected token %v", tok)
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, seen := raw[key]; !seen {
+			keys = append(keys, key)
+		}
+		raw[key] = value
+	}
+	return keys, raw, nil
+}
+
+// marshalPreserved encodes v, a struct, as a JSON object whose keys follow
+// the document order in keys. Raw values are kept for keys that v does not
+// declare; declared fields that are now omitted are dropped, and new ones
+// are appended.
+func marshalPreserved(v any, keys []string, raw map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || raw == nil {
+		return data, err
+	}
+	known, values, err := orderedFields(data)
+	if err != nil {
+		return nil, err
+	}
+	declared := jsonFields(reflect.TypeOf(v))
+
+	var out []string
+	for _, k := range keys {
+		if _, ok := values[k]; ok || !declared[k] {
+			out = append(out, k)
+		}
+	}
+	for _, k := range known {
+		if !contains(out, k) {
+			out = append(out, k)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	for i, k := range out {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteString(":")
+		if value, ok := values[k]; ok {
+			buf.Write(value)
+		} else {
+			buf.Write(raw[k])
+		}
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// jsonFields returns the JSON names of the exported fields of struct type t.
+func jsonFields(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
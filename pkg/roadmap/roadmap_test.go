@@ -0,0 +1,177 @@
+package roadmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRoadmap = `{
+  "ir_version": "1.0",
+  "project": "demo",
+  "items": [
+    {"id": "a", "title": "Done", "status": "completed", "version": "0.1.0"},
+    {"id": "b", "title": "Doing", "status": "in_progress", "version": "0.2.0"},
+    {"id": "c", "title": "Later", "status": "planned"}
+  ]
+}`
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(testRoadmap), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if r.Project != "demo" {
+		t.Errorf("Project = %q, want %q", r.Project, "demo")
+	}
+	if len(r.Items) != 3 {
+		t.Fatalf("len(Items) = %d, want 3", len(r.Items))
+	}
+
+	outstanding := r.Outstanding()
+	if len(outstanding) != 2 {
+		t.Fatalf("len(Outstanding()) = %d, want 2", len(outstanding))
+	}
+	if outstanding[0].ID != "b" || outstanding[1].ID != "c" {
+		t.Errorf("Outstanding() IDs = %s, %s, want b, c", outstanding[0].ID, outstanding[1].ID)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() error = nil, want error")
+	}
+}
+
+func TestSave_PreservesFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte(testRoadmap), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if added := r.UpsertItem(Item{ID: "b", Title: "Doing", Status: StatusCompleted}); added {
+		t.Error("UpsertItem(existing) = true, want false")
+	}
+	if added := r.UpsertItem(Item{ID: "d", Title: "New", Status: StatusPlanned}); !added {
+		t.Error("UpsertItem(new) = false, want true")
+	}
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ir_version must survive and stay ahead of project
+	irIdx := strings.Index(string(data), `"ir_version"`)
+	projIdx := strings.Index(string(data), `"project"`)
+	if irIdx < 0 || projIdx < 0 || irIdx > projIdx {
+		t.Errorf("Save() did not preserve field order:\n%s", data)
+	}
+
+	r2, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() after Save error: %v", err)
+	}
+	if len(r2.Items) != 4 {
+		t.Fatalf("len(Items) = %d, want 4", len(r2.Items))
+	}
+	if r2.Items[1].Status != StatusCompleted {
+		t.Errorf("Items[1].Status = %q, want %q", r2.Items[1].Status, StatusCompleted)
+	}
+}
+
+func TestNew_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	r := New("demo")
+	r.Phases = append(r.Phases, Phase{ID: "v0.1", Name: "v0.1.0", Status: StatusPlanned, Order: 1})
+	r.UpsertItem(Item{ID: "a", Title: "First", Status: StatusPlanned, Phase: "v0.1"})
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	r2, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if r2.Project != "demo" || len(r2.Items) != 1 || r2.Phase("v0.1") == nil {
+		t.Errorf("round trip = %+v, want project demo with one item and phase v0.1", r2)
+	}
+}
+
+func TestItemsFor(t *testing.T) {
+	r := &Roadmap{Items: []Item{
+		{ID: "a", Version: "1.1.0"},
+		{ID: "b", Version: "v1.1.0"},
+		{ID: "c", Version: "1.2.0"},
+		{ID: "d"},
+	}}
+	items := r.ItemsFor("v1.1.0")
+	if len(items) != 2 || items[0].ID != "a" || items[1].ID != "b" {
+		t.Errorf("ItemsFor(v1.1.0) = %+v, want a and b", items)
+	}
+}
+
+func TestSave_PreservesItemFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	const doc = `{
+  "project": "demo",
+  "phases": [
+    {"id": "v0.1", "name": "v0.1.0", "status": "planned", "order": 1, "target_date": "2026-12-01"}
+  ],
+  "items": [
+    {"id": "a", "labels": ["cli"], "title": "First", "status": "planned", "priority": "high"}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	item := *r.Item("a")
+	item.Status = StatusCompleted
+	item.Priority = ""
+	r.UpsertItem(item)
+	r.UpsertItem(Item{ID: "b", Title: "Second", Status: StatusPlanned})
+	if err := r.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`{"id":"v0.1","name":"v0.1.0","status":"planned","order":1,"target_date":"2026-12-01"}`,
+		`{"id":"a","labels":["cli"],"title":"First","status":"completed"}`,
+		`{"id":"b","title":"Second","status":"planned"}`,
+	} {
+		if !strings.Contains(compact.String(), want) {
+			t.Errorf("Save() output missing %s:\n%s", want, data)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package roadmap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sync actions planned by PlanIssueSync.
+const (
+	SyncCreate   = "create"   // open an issue for the item
+	SyncLink     = "link"     // record an existing issue with the same title
+	SyncComplete = "complete" // mark the item completed because its issue closed
+)
+
+// IssueRef is the state of a GitHub issue as seen by PlanIssueSync.
+type IssueRef struct {
+	Number int
+	Title  string
+	Closed bool
+}
+
+// SyncChange is a single change needed to bring ROADMAP.json and GitHub
+// issues in line.
+type SyncChange struct {
+	Action string // SyncCreate, SyncLink, or SyncComplete
+	ItemID string
+	Title  string
+	Issue  int // linked issue number; zero for SyncCreate until the issue exists
+}
+
+// String describes the change for output and proposals.
+func (c SyncChange) String() string {
+	switch c.Action {
+	case SyncCreate:
+		return fmt.Sprintf("create issue for %s: %s", c.ItemID, c.Title)
+	case SyncLink:
+		return fmt.Sprintf("link %s to #%d", c.ItemID, c.Issue)
+	case SyncComplete:
+		return fmt.Sprintf("mark %s completed (#%d closed)", c.ItemID, c.Issue)
+	}
+	return c.Action + " " + c.ItemID
+}
+
+// LinkedIssue returns the GitHub issue number linked to the item: Issue if
+// set, otherwise N from an "issue-N" ID as created by atrelease plan.
+func (i Item) LinkedIssue() int {
+	if i.Issue > 0 {
+		return i.Issue
+	}
+	if rest, ok := strings.CutPrefix(i.ID, "issue-"); ok {
+		if n, err := strconv.Atoi(rest); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// PlanIssueSync compares roadmap items with GitHub issues. Outstanding items
+// without a linked issue are linked to an issue with the same title, or
+// planned for creation; items whose linked issue is closed are marked
+// completed. Issues not in issues are left alone.
+func (r *Roadmap) PlanIssueSync(issues []IssueRef) []SyncChange {
+	byNumber := make(map[int]IssueRef, len(issues))
+	byTitle := make(map[string]IssueRef, len(issues))
+	for _, issue := range issues {
+		byNumber[issue.Number] = issue
+		key := strings.ToLower(strings.TrimSpace(issue.Title))
+		if _, ok := byTitle[key]; !ok {
+			byTitle[key] = issue
+		}
+	}
+
+	var changes []SyncChange
+	for _, item := range r.Items {
+		n := item.LinkedIssue()
+		if n == 0 {
+			if item.Status == StatusCompleted {
+				continue
+			}
+			issue, ok := byTitle[strings.ToLower(strings.TrimSpace(item.Title))]
+			if !ok {
+				changes = append(changes, SyncChange{Action: SyncCreate, ItemID: item.ID, Title: item.Title})
+				continue
+			}
+			n = issue.Number
+			changes = append(changes, SyncChange{Action: SyncLink, ItemID: item.ID, Title: item.Title, Issue: n})
+		}
+
+		if issue, ok := byNumber[n]; ok && issue.Closed && item.Status != StatusCompleted {
+			changes = append(changes, SyncChange{Action: SyncComplete, ItemID: item.ID, Title: item.Title, Issue: n})
+		}
+	}
+	return changes
+}
+
+// ApplySync records a sync change on the matching item. SyncCreate and
+// SyncLink set the item's Issue; SyncComplete sets its status to completed.
+func (r *Roadmap) ApplySync(c SyncChange) error {
+	item := r.Item(c.ItemID)
+	if item == nil {
+		return fmt.Errorf("roadmap item %s not found", c.ItemID)
+	}
+	switch c.Action {
+	case SyncCreate, SyncLink:
+		if c.Issue == 0 {
+			return fmt.Errorf("%s: no issue number", c.ItemID)
+		}
+		item.Issue = c.Issue
+	case SyncComplete:
+		item.Status = StatusCompleted
+	default:
+		return fmt.Errorf("%s: unknown sync action %q", c.ItemID, c.Action)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package roadmap
+
+import (
+	"testing"
+)
+
+func TestItem_LinkedIssue(t *testing.T) {
+	tests := []struct {
+		item Item
+		want int
+	}{
+		{Item{ID: "issue-12"}, 12},
+		{Item{ID: "x", Issue: 7}, 7},
+		{Item{ID: "issue-12", Issue: 7}, 7},
+		{Item{ID: "issue-abc"}, 0},
+		{Item{ID: "feature-3"}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.item.LinkedIssue(); got != tt.want {
+			t.Errorf("LinkedIssue(%+v) = %d, want %d", tt.item, got, tt.want)
+		}
+	}
+}
+
+func TestPlanIssueSync(t *testing.T) {
+	r := New("demo")
+	r.Items = []Item{
+		{ID: "done", Title: "Shipped", Status: StatusCompleted},
+		{ID: "issue-1", Title: "Planned from issue", Status: StatusPlanned},
+		{ID: "linked", Title: "Linked", Status: StatusInProgress, Issue: 2},
+		{ID: "same-title", Title: "Dark mode", Status: StatusPlanned},
+		{ID: "new", Title: "Brand new", Status: StatusFuture},
+		{ID: "issue-9", Title: "Unknown issue", Status: StatusPlanned},
+	}
+	issues := []IssueRef{
+		{Number: 1, Title: "Planned from issue", Closed: true},
+		{Number: 2, Title: "Linked"},
+		{Number: 3, Title: "dark mode ", Closed: true},
+	}
+
+	got := r.PlanIssueSync(issues)
+	want := []SyncChange{
+		{Action: SyncComplete, ItemID: "issue-1", Title: "Planned from issue", Issue: 1},
+		{Action: SyncLink, ItemID: "same-title", Title: "Dark mode", Issue: 3},
+		{Action: SyncComplete, ItemID: "same-title", Title: "Dark mode", Issue: 3},
+		{Action: SyncCreate, ItemID: "new", Title: "Brand new"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("PlanIssueSync() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestApplySync(t *testing.T) {
+	r := New("demo")
+	r.Items = []Item{{ID: "a", Title: "A", Status: StatusPlanned}}
+
+	if err := r.ApplySync(SyncChange{Action: SyncCreate, ItemID: "a"}); err == nil {
+		t.Error("ApplySync(create without issue) error = nil, want error")
+	}
+	if err := r.ApplySync(SyncChange{Action: SyncCreate, ItemID: "a", Issue: 5}); err != nil {
+		t.Fatalf("ApplySync(create) error: %v", err)
+	}
+	if err := r.ApplySync(SyncChange{Action: SyncComplete, ItemID: "a", Issue: 5}); err != nil {
+		t.Fatalf("ApplySync(complete) error: %v", err)
+	}
+	if r.Items[0].Issue != 5 || r.Items[0].Status != StatusCompleted {
+		t.Errorf("item = %+v, want issue 5 and completed", r.Items[0])
+	}
+	if err := r.ApplySync(SyncChange{Action: SyncComplete, ItemID: "missing"}); err == nil {
+		t.Error("ApplySync(missing item) error = nil, want error")
+	}
+}
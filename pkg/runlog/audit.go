@@ -0,0 +1,49 @@
+package runlog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// AuditFileName is the audit log file name within Dir.
+const AuditFileName = "audit.jsonl"
+
+// AuditEntry records a safeguard that was overridden, such as a release
+// forced during a freeze window.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`            // What was done, e.g. "release"
+	Version  string    `json:"version,omitempty"` // Release version, if any
+	User     string    `json:"user,omitempty"`    // git user.name of whoever overrode it
+	Override string    `json:"override"`          // Safeguard overridden, e.g. "freeze"
+	Reason   string    `json:"reason"`            // Why it applied, e.g. the freeze window
+}
+
+// AuditPath returns the audit log file for dir.
+func AuditPath(dir string) string {
+	return filepath.Join(dir, Dir, AuditFileName)
+}
+
+// AppendAudit records entry in the audit log of dir, creating the history
+// directory on first use.
+func AppendAudit(dir string, entry AuditEntry) error {
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(AuditPath(dir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
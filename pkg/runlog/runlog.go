@@ -0,0 +1,299 @@
+// Package runlog records check runs locally and summarizes them. Nothing
+// leaves the machine: runs are appended to .atrelease/history.jsonl in the
+// checked directory, the full output of the last run's checks is kept in
+// .atrelease/logs, and overridden safeguards are logged to
+// .atrelease/audit.jsonl.
+package runlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dir is the directory, relative to the checked directory, holding the
+// history file and check logs. It ignores itself in git.
+const Dir = ".atrelease"
+
+// FileName is the history file name within Dir.
+const FileName = "history.jsonl"
+
+// LogsDir is the directory within Dir holding the full output of the
+// checks of the last run.
+const LogsDir = "logs"
+
+// Status values for recorded checks.
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+	StatusWarning = "warning"
+)
+
+// Run is one recorded check run.
+type Run struct {
+	Time         time.Time `json:"time"`
+	Command      string    `json:"command"`
+	DurationMs   int64     `json:"duration_ms"`
+	Success      bool      `json:"success"`
+	DetectCached bool      `json:"detect_cached"` // Detection results came from the cache
+	Checks       []Check   `json:"checks"`
+}
+
+// Check is one check in a recorded run.
+type Check struct {
+	ID         string `json:"id,omitempty"` // Stable check ID; absent in older history
+	Name       string `json:"name"`
+	Path       string `json:"path,omitempty"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// Path returns the history file for dir.
+func Path(dir string) string {
+	return filepath.Join(dir, Dir, FileName)
+}
+
+// Append records run in the history of dir, creating the history
+// directory on first use.
+func Append(dir string, run Run) error {
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(Path(dir), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ensureDir creates the history directory of dir, with a .gitignore that
+// ignores it.
+func ensureDir(dir string) error {
+	histDir := filepath.Join(dir, Dir)
+	if err := os.MkdirAll(histDir, 0755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(histDir, ".gitignore")
+	if _, err := os.Stat(ignore); errors.Is(err, os.ErrNotExist) {
+		return os.WriteFile(ignore, []byte("*\n"), 0644)
+	}
+	return nil
+}
+
+// WriteFile saves data as the file name in the history directory of dir,
+// e.g. a coverage profile.
+func WriteFile(dir, name string, data []byte) error {
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, Dir, name), data, 0644)
+}
+
+// unsafeLogChars are the characters of a detection path replaced in the
+// name of its log directory.
+var unsafeLogChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// LogName returns the name, within LogsDir, of the log of check id run in
+// the detection path p, relative to the checked directory: "<id>.log" for
+// the directory itself, or "<path-slug>/<id>.log", such as
+// "services-web/go.test.log" for services/web.
+func LogName(p, id string) string {
+	slug := strings.Trim(unsafeLogChars.ReplaceAllString(filepath.ToSlash(p), "-"), ".-")
+	if slug == "" {
+		return id + ".log"
+	}
+	return slug + "/" + id + ".log"
+}
+
+// LogPath returns the file the full output of check id run in the
+// detection path p is saved to in dir.
+func LogPath(dir, p, id string) string {
+	return filepath.Join(dir, Dir, LogsDir, filepath.FromSlash(LogName(p, id)))
+}
+
+// WriteLogs saves the full output of each check in logs, keyed by
+// LogName, replacing the logs of the previous run of dir.
+func WriteLogs(dir string, logs map[string]string) error {
+	if err := ensureDir(dir); err != nil {
+		return err
+	}
+	logsDir := filepath.Join(dir, Dir, LogsDir)
+	if err := os.RemoveAll(logsDir); err != nil {
+		return err
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+	if err := os.Mkdir(logsDir, 0755); err != nil {
+		return err
+	}
+	for name, output := range logs {
+		path := filepath.Join(logsDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(output+"\n"), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load returns the recorded runs of dir, oldest first. A missing history
+// is empty; lines that can't be parsed are skipped.
+func Load(dir string) ([]Run, error) {
+	f, err := os.Open(Path(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []Run
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var run Run
+		if json.Unmarshal(scanner.Bytes(), &run) == nil {
+			runs = append(runs, run)
+		}
+	}
+	return runs, scanner.Err()
+}
+
+// Stats summarizes recorded runs.
+type Stats struct {
+	Runs          int          `json:"runs" toon:"runs"`
+	Passed        int          `json:"passed" toon:"passed"`
+	Since         time.Time    `json:"since" toon:"since"`
+	AvgDurationMs int64        `json:"avg_duration_ms" toon:"avg_duration_ms"`
+	DetectCached  int          `json:"detect_cached" toon:"detect_cached"` // Runs whose detection results were cached
+	Slowest       []CheckStats `json:"slowest" toon:"slowest"`
+	Flakiest      []CheckStats `json:"flakiest" toon:"flakiest"`
+}
+
+// CheckStats summarizes one check across runs.
+type CheckStats struct {
+	ID            string  `json:"id,omitempty" toon:"id,omitempty"`
+	Name          string  `json:"name" toon:"name"`
+	Path          string  `json:"path,omitempty" toon:"path,omitempty"`
+	Runs          int     `json:"runs" toon:"runs"`
+	Failures      int     `json:"failures" toon:"failures"`
+	Flips         int     `json:"flips" toon:"flips"` // Changes between passing and failing in consecutive runs
+	AvgDurationMs int64   `json:"avg_duration_ms" toon:"avg_duration_ms"`
+	Flakiness     float64 `json:"flakiness" toon:"flakiness"` // Flips per consecutive pair of runs
+}
+
+// Summarize computes statistics for runs, oldest first, listing up to
+// limit of the slowest and flakiest checks. Skipped checks are ignored;
+// warnings count as passing. Only checks that both passed and failed are
+// considered flaky.
+func Summarize(runs []Run, limit int) Stats {
+	stats := Stats{Runs: len(runs)}
+	if len(runs) == 0 {
+		return stats
+	}
+	stats.Since = runs[0].Time
+
+	type key struct{ name, path string }
+	type acc struct {
+		CheckStats
+		totalMs int64
+		timed   int
+		last    string
+	}
+	byCheck := make(map[key]*acc)
+	var order []key
+	var totalMs int64
+	for _, run := range runs {
+		totalMs += run.DurationMs
+		if run.Success {
+			stats.Passed++
+		}
+		if run.DetectCached {
+			stats.DetectCached++
+		}
+		for _, c := range run.Checks {
+			if c.Status == StatusSkipped {
+				continue
+			}
+			// Key by ID so renamed checks keep their history
+			k := key{c.ID, c.Path}
+			if c.ID == "" {
+				k.name = c.Name
+			}
+			a, ok := byCheck[k]
+			if !ok {
+				a = &acc{CheckStats: CheckStats{ID: c.ID, Path: c.Path}}
+				byCheck[k] = a
+				order = append(order, k)
+			}
+			a.Name = c.Name
+			status := StatusPassed
+			if c.Status == StatusFailed {
+				status = StatusFailed
+				a.Failures++
+			}
+			if a.last != "" && a.last != status {
+				a.Flips++
+			}
+			a.last = status
+			a.Runs++
+			if c.DurationMs > 0 {
+				a.totalMs += c.DurationMs
+				a.timed++
+			}
+		}
+	}
+	stats.AvgDurationMs = totalMs / int64(len(runs))
+
+	var all []CheckStats
+	for _, k := range order {
+		a := byCheck[k]
+		if a.timed > 0 {
+			a.AvgDurationMs = a.totalMs / int64(a.timed)
+		}
+		if a.Runs > 1 {
+			a.Flakiness = float64(a.Flips) / float64(a.Runs-1)
+		}
+		all = append(all, a.CheckStats)
+	}
+
+	for _, c := range all {
+		if c.AvgDurationMs > 0 {
+			stats.Slowest = append(stats.Slowest, c)
+		}
+		if c.Flips > 0 {
+			stats.Flakiest = append(stats.Flakiest, c)
+		}
+	}
+	sort.SliceStable(stats.Slowest, func(i, j int) bool {
+		return stats.Slowest[i].AvgDurationMs > stats.Slowest[j].AvgDurationMs
+	})
+	sort.SliceStable(stats.Flakiest, func(i, j int) bool {
+		return stats.Flakiest[i].Flakiness > stats.Flakiest[j].Flakiness
+	})
+	if limit > 0 {
+		stats.Slowest = stats.Slowest[:min(limit, len(stats.Slowest))]
+		stats.Flakiest = stats.Flakiest[:min(limit, len(stats.Flakiest))]
+	}
+	return stats
+}
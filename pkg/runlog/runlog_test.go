@@ -0,0 +1,104 @@
+package runlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	runs, err := Load(dir)
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("expected no runs before recording, got %v (%v)", runs, err)
+	}
+
+	for i := range 2 {
+		run := Run{Time: time.Unix(int64(i), 0).UTC(), Command: "check", DurationMs: 1000, Success: i == 0,
+			Checks: []Check{{Name: "Go: tests", Path: ".", Status: StatusPassed, DurationMs: 800}}}
+		if err := Append(dir, run); err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+	}
+
+	runs, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(runs) != 2 || !runs[0].Success || runs[1].Success || runs[1].Checks[0].DurationMs != 800 {
+		t.Errorf("unexpected runs: %+v", runs)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, Dir, ".gitignore"))
+	if err != nil || string(data) != "*\n" {
+		t.Errorf("expected history directory to ignore itself, got %q (%v)", data, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	statuses := []string{StatusPassed, StatusFailed, StatusPassed, StatusPassed}
+	var runs []Run
+	for i, status := range statuses {
+		runs = append(runs, Run{
+			Time:         time.Unix(int64(i), 0),
+			DurationMs:   int64(1000 * (i + 1)),
+			Success:      status == StatusPassed,
+			DetectCached: i > 0,
+			Checks: []Check{
+				{Name: "Go: tests", Status: status, DurationMs: 3000},
+				{Name: "Go: build", Status: StatusPassed, DurationMs: 500},
+				{Name: "Go: lint", Status: StatusWarning},
+				{Name: "Docs: site build", Status: StatusSkipped},
+			},
+		})
+	}
+
+	s := Summarize(runs, 5)
+	if s.Runs != 4 || s.Passed != 3 || s.DetectCached != 3 || s.AvgDurationMs != 2500 {
+		t.Errorf("unexpected totals: %+v", s)
+	}
+	if !s.Since.Equal(time.Unix(0, 0)) {
+		t.Errorf("Since = %v", s.Since)
+	}
+	if len(s.Slowest) != 2 || s.Slowest[0].Name != "Go: tests" || s.Slowest[0].AvgDurationMs != 3000 {
+		t.Errorf("unexpected slowest checks: %+v", s.Slowest)
+	}
+	if len(s.Flakiest) != 1 {
+		t.Fatalf("unexpected flakiest checks: %+v", s.Flakiest)
+	}
+	if f := s.Flakiest[0]; f.Name != "Go: tests" || f.Flips != 2 || f.Failures != 1 || f.Flakiness != 2.0/3 {
+		t.Errorf("unexpected flaky check: %+v", f)
+	}
+
+	if s := Summarize(runs, 1); len(s.Slowest) != 1 {
+		t.Errorf("expected limit to apply, got %+v", s.Slowest)
+	}
+	if s := Summarize(nil, 5); s.Runs != 0 || s.Slowest != nil {
+		t.Errorf("expected empty stats, got %+v", s)
+	}
+}
+
+func TestWriteLogs(t *testing.T) {
+	dir := t.TempDir()
+	logs := map[string]string{
+		LogName(".", "go.test"):            "FAIL root",
+		LogName("services/web", "go.test"): "FAIL web",
+	}
+	if err := WriteLogs(dir, logs); err != nil {
+		t.Fatal(err)
+	}
+	for p, want := range map[string]string{".": "FAIL root\n", "services/web": "FAIL web\n"} {
+		data, err := os.ReadFile(LogPath(dir, p, "go.test"))
+		if err != nil || string(data) != want {
+			t.Errorf("log of %s = %q (%v), want %q", p, data, err, want)
+		}
+	}
+	if got := LogName("../x", "go.test"); got != "x/go.test.log" {
+		t.Errorf("LogName(../x) = %q, want x/go.test.log", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, Dir, ".gitignore")); err != nil {
+		t.Errorf("expected the directory to ignore itself in git: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Package semver handles the semantic versions of releases and tags, such
+// as "v1.2.3" or "1.2.3-rc.1", using golang.org/x/mod/semver. Unlike that
+// package, versions may omit the "v" prefix, and shorthands such as "v1.2"
+// are not valid release versions.
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Part is a component of a version to bump.
+type Part int
+
+// Parts of a version.
+const (
+	Patch Part = iota
+	Minor
+	Major
+)
+
+// String returns "patch", "minor", or "major".
+func (p Part) String() string {
+	switch p {
+	case Major:
+		return "major"
+	case Minor:
+		return "minor"
+	}
+	return "patch"
+}
+
+// withV adds the "v" prefix golang.org/x/mod/semver requires.
+func withV(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
+
+// IsValid reports whether v is a full MAJOR.MINOR.PATCH version, with
+// optional "v" prefix, prerelease, and build metadata.
+func IsValid(v string) bool {
+	w := withV(v)
+	return semver.IsValid(w) && strings.TrimSuffix(w, semver.Build(w)) == semver.Canonical(w)
+}
+
+// IsRelease reports whether v is a valid version without prerelease or
+// build metadata, such as "v1.2.3".
+func IsRelease(v string) bool {
+	return IsValid(v) && !strings.ContainsAny(v, "-+")
+}
+
+// Compare returns -1, 0, or 1 as a is lower than, equal to, or higher than
+// b by semver precedence. Build metadata is ignored. Invalid versions are
+// lower than valid ones and equal to each other.
+func Compare(a, b string) int {
+	if !IsValid(a) {
+		a = ""
+	}
+	if !IsValid(b) {
+		b = ""
+	}
+	return semver.Compare(withV(a), withV(b))
+}
+
+// Prerelease returns the prerelease of v without its "-", or "" if it
+// has none.
+func Prerelease(v string) string {
+	if !IsValid(v) {
+		return ""
+	}
+	return strings.TrimPrefix(semver.Prerelease(withV(v)), "-")
+}
+
+// Split returns the major, minor, and patch numbers of v.
+func Split(v string) (major, minor, patch int, err error) {
+	if !IsValid(v) {
+		return 0, 0, 0, fmt.Errorf("invalid version %q (expected vMAJOR.MINOR.PATCH)", v)
+	}
+	core := strings.TrimPrefix(semver.Canonical(withV(v)), "v")
+	core = strings.TrimSuffix(core, semver.Prerelease(withV(v)))
+	nums := strings.Split(core, ".")
+	major, _ = strconv.Atoi(nums[0])
+	minor, _ = strconv.Atoi(nums[1])
+	patch, _ = strconv.Atoi(nums[2])
+	return major, minor, patch, nil
+}
+
+// Bump returns the next version after v that changes part, keeping v's
+// "v" prefix or lack of it. The next version of a prerelease is its
+// release when that already changes part, so v1.1.0-rc.1 bumps to
+// v1.1.0 for a minor or patch change and to v2.0.0 for a major one.
+func Bump(v string, part Part) (string, error) {
+	major, minor, patch, err := Split(v)
+	if err != nil {
+		return "", err
+	}
+	pre := Prerelease(v) != ""
+	switch {
+	case part == Major && !(pre && minor == 0 && patch == 0):
+		major, minor, patch = major+1, 0, 0
+	case part == Minor && !(pre && patch == 0):
+		minor, patch = minor+1, 0
+	case part == Patch && !pre:
+		patch++
+	}
+	prefix := ""
+	if strings.HasPrefix(v, "v") {
+		prefix = "v"
+	}
+	return fmt.Sprintf("%s%d.%d.%d", prefix, major, minor, patch), nil
+}
+
+// SortNewestFirst sorts versions from highest to lowest, with invalid
+// versions last in their original order.
+func SortNewestFirst(versions []string) {
+	slices.SortStableFunc(versions, func(a, b string) int {
+		return Compare(b, a)
+	})
+}
@@ -0,0 +1,108 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		v    string
+		want bool
+	}{
+		{"v1.2.3", true},
+		{"1.2.3", true},
+		{"v1.2.3-rc.1", true},
+		{"v1.2.3+build.5", true},
+		{"v1.2.3-rc.1+build.5", true},
+		{"v1.2", false},
+		{"v1", false},
+		{"v01.2.3", false},
+		{"v1.2.3-", false},
+		{"1.2.3.4", false},
+		{"", false},
+		{"latest", false},
+	}
+	for _, tt := range tests {
+		if got := IsValid(tt.v); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+	if IsRelease("v1.2.3-rc.1") || !IsRelease("1.2.3") {
+		t.Error("IsRelease accepted a prerelease or rejected a release")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.0.0", "v1.0.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"v0.10.0", "v0.9.0", 1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-rc.2", "v1.0.0-rc.10", -1},
+		{"v1.0.0-alpha", "v1.0.0-1", 1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0+build", "v1.0.0", 0},
+		{"v0.0.1", "dev", 1},
+		{"v1.2", "v1.1.0", -1},
+		{"dev", "(devel)", 0},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	major, minor, patch, err := Split("1.20.3-rc.1+build")
+	if err != nil || major != 1 || minor != 20 || patch != 3 {
+		t.Errorf("Split() = %d, %d, %d, %v", major, minor, patch, err)
+	}
+	if _, _, _, err := Split("v1.2"); err == nil {
+		t.Error("Split(v1.2) error = nil, want error")
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		v    string
+		part Part
+		want string
+	}{
+		{"v1.2.3", Patch, "v1.2.4"},
+		{"v1.2.3", Minor, "v1.3.0"},
+		{"v1.2.3", Major, "v2.0.0"},
+		{"1.2.3", Patch, "1.2.4"},
+		{"v1.2.3+build", Patch, "v1.2.4"},
+		{"v1.2.3-rc.1", Patch, "v1.2.3"},
+		{"v1.2.3-rc.1", Minor, "v1.3.0"},
+		{"v1.3.0-rc.1", Minor, "v1.3.0"},
+		{"v1.3.0-rc.1", Major, "v2.0.0"},
+		{"v2.0.0-beta", Major, "v2.0.0"},
+	}
+	for _, tt := range tests {
+		got, err := Bump(tt.v, tt.part)
+		if err != nil {
+			t.Fatalf("Bump(%q, %s) error: %v", tt.v, tt.part, err)
+		}
+		if got != tt.want {
+			t.Errorf("Bump(%q, %s) = %q, want %q", tt.v, tt.part, got, tt.want)
+		}
+	}
+	if _, err := Bump("next", Patch); err == nil {
+		t.Error("Bump(next) error = nil, want error")
+	}
+}
+
+func TestSortNewestFirst(t *testing.T) {
+	tags := []string{"v1.0.0-rc.1", "nightly", "v0.10.0", "v1.0.0", "v0.9.0", "latest", "v1.0.0-rc.10"}
+	SortNewestFirst(tags)
+	want := []string{"v1.0.0", "v1.0.0-rc.10", "v1.0.0-rc.1", "v0.10.0", "v0.9.0", "nightly", "latest"}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("SortNewestFirst() = %v, want %v", tags, want)
+	}
+}
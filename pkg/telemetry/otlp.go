@@ -0,0 +1,278 @@
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// The OTLP/HTTP JSON encoding of the OpenTelemetry protocol, limited to
+// what the exporter sends. 64-bit integers are strings and IDs are hex, as
+// the protocol requires.
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"` // 1 for OK, 2 for ERROR
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"` // 1 for internal
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	AsDouble     float64        `json:"asDouble"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpMetric struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Unit        string    `json:"unit,omitempty"`
+	Gauge       otlpGauge `json:"gauge"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetrics struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// scope is the instrumentation scope of everything exported.
+var scope = otlpScope{Name: "github.com/plexusone/agent-team-release"}
+
+func keyValues(attrs ...attr) []otlpKeyValue {
+	var kvs []otlpKeyValue
+	for _, a := range attrs {
+		if a.value != "" {
+			kvs = append(kvs, otlpKeyValue{Key: a.key, Value: otlpValue{StringValue: a.value}})
+		}
+	}
+	return kvs
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// resource describes the validated repository.
+func resource(run Run) otlpResource {
+	return otlpResource{Attributes: keyValues(
+		attr{"service.name", "atrelease"},
+		attr{"vcs.repository.name", run.Repository},
+		attr{"vcs.ref.head.revision", run.Commit},
+		attr{"atrelease.release.version", run.Report.Version},
+	)}
+}
+
+// newID returns a random trace or span ID of n bytes in hex.
+func newID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// spans returns a trace of the run: a root span for the validation, a
+// child span for each area, and an event on the area's span for each of
+// its checks.
+func spans(run Run) []otlpSpan {
+	report := run.Report
+	traceID, rootID := newID(16), newID(8)
+	root := otlpSpan{
+		TraceID:           traceID,
+		SpanID:            rootID,
+		Name:              "atrelease validate",
+		Kind:              1,
+		StartTimeUnixNano: unixNano(run.Start),
+		EndTimeUnixNano:   unixNano(run.End),
+		Attributes:        keyValues(attr{"atrelease.verdict", report.Verdict()}),
+		Status:            spanStatus(report.IsGo(), report.Verdict()),
+	}
+	out := []otlpSpan{root}
+
+	for _, area := range report.Areas {
+		start, end := area.Started, area.Started.Add(area.Duration)
+		if start.IsZero() {
+			// A skipped area didn't run
+			start, end = run.Start, run.Start
+		}
+		span := otlpSpan{
+			TraceID:           traceID,
+			SpanID:            newID(8),
+			ParentSpanID:      rootID,
+			Name:              string(area.Area) + " validation",
+			Kind:              1,
+			StartTimeUnixNano: unixNano(start),
+			EndTimeUnixNano:   unixNano(end),
+			Attributes:        keyValues(attr{"atrelease.area", string(area.Area)}, attr{"atrelease.status", string(area.Status)}),
+			Status:            spanStatus(area.Status != checks.StatusNoGo, string(area.Status)),
+		}
+		for _, r := range area.Results {
+			span.Events = append(span.Events, otlpEvent{
+				TimeUnixNano: unixNano(end),
+				Name:         r.Name,
+				Attributes: keyValues(
+					attr{"atrelease.check.id", checks.ResultID(r)},
+					attr{"atrelease.check.path", checks.RelResultPath(run.Dir, r)},
+					attr{"atrelease.check.status", string(r.Severity())},
+					attr{"atrelease.check.duration_ms", strconv.FormatInt(r.Duration.Milliseconds(), 10)},
+					attr{"atrelease.check.reason", r.Reason},
+				),
+			})
+		}
+		out = append(out, span)
+	}
+	return out
+}
+
+func spanStatus(ok bool, message string) otlpStatus {
+	if ok {
+		return otlpStatus{Code: 1}
+	}
+	return otlpStatus{Code: 2, Message: message}
+}
+
+// tracesPayload returns the OTLP traces request for run.
+func tracesPayload(run Run) otlpTraces {
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource:   resource(run),
+		ScopeSpans: []otlpScopeSpans{{Scope: scope, Spans: spans(run)}},
+	}}}
+}
+
+// metricsPayload returns the OTLP metrics request for run, with a gauge
+// for each metric of samples.
+func metricsPayload(run Run) otlpMetrics {
+	var metrics []otlpMetric
+	index := make(map[string]int)
+	for _, s := range samples(run) {
+		i, ok := index[s.name]
+		if !ok {
+			i = len(metrics)
+			index[s.name] = i
+			metrics = append(metrics, otlpMetric{Name: s.name, Description: s.help, Unit: s.unit})
+		}
+		metrics[i].Gauge.DataPoints = append(metrics[i].Gauge.DataPoints, otlpDataPoint{
+			Attributes:   keyValues(s.attrs...),
+			TimeUnixNano: unixNano(run.End),
+			AsDouble:     s.value,
+		})
+	}
+
+	return otlpMetrics{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     resource(run),
+		ScopeMetrics: []otlpScopeMetrics{{Scope: scope, Metrics: metrics}},
+	}}}
+}
+
+// ExportOTLP sends run to an OpenTelemetry collector as a trace and
+// metrics.
+func ExportOTLP(client *http.Client, cfg config.OTLPConfig, run Run) error {
+	base := strings.TrimSuffix(cfg.Endpoint, "/")
+	headers := cfg.ExpandedHeaders()
+	if err := postJSON(client, base+"/v1/traces", headers, tracesPayload(run)); err != nil {
+		return fmt.Errorf("OTLP traces: %w", err)
+	}
+	if err := postJSON(client, base+"/v1/metrics", headers, metricsPayload(run)); err != nil {
+		return fmt.Errorf("OTLP metrics: %w", err)
+	}
+	return nil
+}
+
+func postJSON(client *http.Client, url string, headers map[string]string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return send(client, req)
+}
+
+// send sends req and fails unless the response status is 2xx.
+func send(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s %s", req.Method, req.URL.Redacted(), resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
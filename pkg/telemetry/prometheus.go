@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// DefaultJob is the Pushgateway job label when none is configured.
+const DefaultJob = "atrelease"
+
+// promName returns the Prometheus name of a sample, e.g.
+// "atrelease_check_duration_seconds" for atrelease.check.duration in s.
+func promName(s sample) string {
+	name := strings.ReplaceAll(s.name, ".", "_")
+	if s.unit == "s" {
+		name += "_seconds"
+	}
+	return name
+}
+
+// labelEscaper escapes a label value of the text exposition format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Exposition returns the metrics of run in the Prometheus text exposition
+// format. Every sample is labeled with the release version; the
+// repository is left to the Pushgateway grouping key.
+func Exposition(run Run) []byte {
+	var b bytes.Buffer
+	seen := make(map[string]bool)
+	for _, s := range samples(run) {
+		name := promName(s)
+		if !seen[name] {
+			seen[name] = true
+			fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, s.help, name)
+		}
+
+		var labels []string
+		for _, a := range append([]attr{{"version", run.Report.Version}}, s.attrs...) {
+			if a.value != "" {
+				labels = append(labels, fmt.Sprintf(`%s="%s"`, a.key, labelEscaper.Replace(a.value)))
+			}
+		}
+		b.WriteString(name)
+		if len(labels) > 0 {
+			b.WriteString("{" + strings.Join(labels, ",") + "}")
+		}
+		b.WriteString(" " + strconv.FormatFloat(s.value, 'g', -1, 64) + "\n")
+	}
+	return b.Bytes()
+}
+
+// pushURL returns the Pushgateway URL of the group of run's repository.
+// The repository is base64-encoded since it may contain slashes.
+func pushURL(cfg config.PushgatewayConfig, run Run) string {
+	job := cfg.Job
+	if job == "" {
+		job = DefaultJob
+	}
+	u := strings.TrimSuffix(cfg.URL, "/") + "/metrics/job/" + url.PathEscape(job)
+	if run.Repository != "" {
+		u += "/repository@base64/" + base64.RawURLEncoding.EncodeToString([]byte(run.Repository))
+	}
+	return u
+}
+
+// Push replaces the metrics of run's repository on a Prometheus
+// Pushgateway.
+func Push(client *http.Client, cfg config.PushgatewayConfig, run Run) error {
+	req, err := http.NewRequest(http.MethodPut, pushURL(cfg, run), bytes.NewReader(Exposition(run)))
+	if err != nil {
+		return fmt.Errorf("Pushgateway: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	if err := send(client, req); err != nil {
+		return fmt.Errorf("Pushgateway: %w", err)
+	}
+	return nil
+}
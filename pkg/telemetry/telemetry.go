@@ -0,0 +1,111 @@
+// Package telemetry exports validation reports to monitoring systems: as
+// OpenTelemetry traces and metrics over OTLP/HTTP, or as Prometheus
+// metrics pushed to a Pushgateway. Graphing them over time shows the
+// release readiness of many repositories at a glance.
+package telemetry
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+// Run is a validation run to export.
+type Run struct {
+	Repository string // e.g. "github.com/org/repo", or the directory name without a remote
+	Dir        string // Validated directory; check paths are reported relative to it
+	Commit     string // Validated commit, if known
+	Report     *checks.ValidationReport
+	Start, End time.Time
+}
+
+// attr is a metric label or span attribute.
+type attr struct {
+	key, value string
+}
+
+// sample is a gauge reading, exported as an OTLP data point or a
+// Prometheus sample.
+type sample struct {
+	name  string // OpenTelemetry name, e.g. "atrelease.area.go"
+	unit  string // UCUM unit, e.g. "s"; "" for none
+	help  string
+	attrs []attr
+	value float64
+}
+
+// severities are the check statuses counted by the atrelease.checks metric.
+var severities = []checks.Severity{checks.SeverityPassed, checks.SeverityFailed, checks.SeverityWarning, checks.SeveritySkipped}
+
+// samples returns the metrics of a run:
+//
+//   - atrelease.validation.go: 1 if the release is GO, 0 if not
+//   - atrelease.validation.duration: seconds the validation took
+//   - atrelease.area.go: per area, 1 unless it is NO-GO
+//   - atrelease.checks: per area, the number of checks with each status
+//   - atrelease.check.duration: per check and detection path, seconds it
+//     took
+func samples(run Run) []sample {
+	report := run.Report
+	out := []sample{
+		{name: "atrelease.validation.go", help: "Whether the release is GO (1) or NO-GO (0).", value: boolValue(report.IsGo())},
+		{name: "atrelease.validation.duration", unit: "s", help: "Duration of the validation.", value: run.End.Sub(run.Start).Seconds()},
+	}
+	for _, area := range report.Areas {
+		name := string(area.Area)
+		out = append(out, sample{
+			name:  "atrelease.area.go",
+			help:  "Whether a validation area is GO, WARN, or SKIP (1) or NO-GO (0).",
+			attrs: []attr{{"area", name}, {"status", string(area.Status)}},
+			value: boolValue(area.Status != checks.StatusNoGo),
+		})
+
+		counts := make(map[checks.Severity]int)
+		for _, r := range area.Results {
+			counts[r.Severity()]++
+		}
+		for _, s := range severities {
+			out = append(out, sample{
+				name:  "atrelease.checks",
+				help:  "Number of checks of a validation area by status.",
+				attrs: []attr{{"area", name}, {"status", string(s)}},
+				value: float64(counts[s]),
+			})
+		}
+	}
+	for _, area := range report.Areas {
+		for _, r := range area.Results {
+			out = append(out, sample{
+				name:  "atrelease.check.duration",
+				unit:  "s",
+				help:  "Duration of a check.",
+				attrs: []attr{{"area", string(area.Area)}, {"check", checks.ResultID(r)}, {"path", checks.RelResultPath(run.Dir, r)}, {"status", string(r.Severity())}},
+				value: r.Duration.Seconds(),
+			})
+		}
+	}
+	return out
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Export sends run to the exporters configured in cfg with client. It
+// tries every exporter and returns their errors joined.
+func Export(client *http.Client, cfg config.TelemetryConfig, run Run) error {
+	var errs []error
+	if cfg.OTLP.Endpoint != "" {
+		errs = append(errs, ExportOTLP(client, cfg.OTLP, run))
+	}
+	if cfg.Pushgateway.URL != "" {
+		errs = append(errs, Push(client, cfg.Pushgateway, run))
+	}
+	return errors.Join(errs...)
+}
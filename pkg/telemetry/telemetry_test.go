@@ -0,0 +1,185 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+)
+
+func testRun() Run {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	qa := []checks.Result{
+		{Name: "Go: tests", Error: errors.New("exit status 1"), Duration: 2 * time.Second},
+		{Name: "Go: build", Passed: true, Duration: time.Second},
+	}
+	return Run{
+		Repository: "github.com/org/repo",
+		Commit:     "abc123",
+		Start:      start,
+		End:        start.Add(5 * time.Second),
+		Report: &checks.ValidationReport{
+			Version: "v1.2.0",
+			Areas: []checks.AreaResult{
+				{Area: checks.AreaQA, Status: checks.StatusNoGo, Results: qa, Started: start, Duration: 3 * time.Second},
+				checks.SkippedArea(checks.AreaSecurity, "--skip-security"),
+			},
+		},
+	}
+}
+
+func TestExposition(t *testing.T) {
+	out := string(Exposition(testRun()))
+	for _, want := range []string{
+		"# TYPE atrelease_validation_go gauge\n",
+		"atrelease_validation_go{version=\"v1.2.0\"} 0\n",
+		"atrelease_validation_duration_seconds{version=\"v1.2.0\"} 5\n",
+		"atrelease_area_go{version=\"v1.2.0\",area=\"QA\",status=\"NO-GO\"} 0\n",
+		"atrelease_area_go{version=\"v1.2.0\",area=\"Security\",status=\"SKIP\"} 1\n",
+		"atrelease_checks{version=\"v1.2.0\",area=\"QA\",status=\"failed\"} 1\n",
+		"atrelease_checks{version=\"v1.2.0\",area=\"QA\",status=\"warning\"} 0\n",
+		"atrelease_check_duration_seconds{version=\"v1.2.0\",area=\"QA\",check=\"go.test\",path=\".\",status=\"failed\"} 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("exposition missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "# TYPE atrelease_checks gauge"); n != 1 {
+		t.Errorf("atrelease_checks declared %d times", n)
+	}
+}
+
+func TestExposition_Monorepo(t *testing.T) {
+	dir := "repo"
+	run := testRun()
+	run.Dir = dir
+	run.Report.Areas[0].Results = []checks.Result{
+		{Name: "Go: tests", Path: filepath.Join(dir, "api"), Passed: true, Duration: time.Second},
+		{Name: "Go: tests", Path: filepath.Join(dir, "services", "web"), Error: errors.New("exit status 1"), Duration: 2 * time.Second},
+	}
+
+	out := string(Exposition(run))
+	series := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, _, _ := strings.Cut(line, " ")
+		if series[key] {
+			t.Errorf("duplicate series %s", key)
+		}
+		series[key] = true
+	}
+	for _, want := range []string{
+		`atrelease_check_duration_seconds{version="v1.2.0",area="QA",check="go.test",path="api",status="passed"}`,
+		`atrelease_check_duration_seconds{version="v1.2.0",area="QA",check="go.test",path="services/web",status="failed"}`,
+	} {
+		if !series[want] {
+			t.Errorf("exposition missing %s:\n%s", want, out)
+		}
+	}
+
+	for _, m := range metricsPayload(run).ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		points := make(map[string]bool)
+		for _, dp := range m.Gauge.DataPoints {
+			key := fmt.Sprint(dp.Attributes)
+			if points[key] {
+				t.Errorf("duplicate OTLP data point %s %s", m.Name, key)
+			}
+			points[key] = true
+		}
+	}
+}
+
+func TestPush(t *testing.T) {
+	var method, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method, path, body = r.Method, r.URL.Path, string(data)
+	}))
+	defer srv.Close()
+
+	if err := Push(srv.Client(), config.PushgatewayConfig{URL: srv.URL + "/"}, testRun()); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	if method != http.MethodPut || path != "/metrics/job/atrelease/repository@base64/Z2l0aHViLmNvbS9vcmcvcmVwbw" {
+		t.Errorf("request = %s %s", method, path)
+	}
+	if !strings.Contains(body, "atrelease_validation_go") {
+		t.Errorf("body = %q", body)
+	}
+}
+
+func TestExportOTLP(t *testing.T) {
+	t.Setenv("OTLP_TOKEN", "secret")
+	var mu sync.Mutex
+	bodies := make(map[string][]byte)
+	auth := ""
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		bodies[r.URL.Path], _ = io.ReadAll(r.Body)
+		auth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	cfg := config.OTLPConfig{Endpoint: srv.URL, Headers: map[string]string{"Authorization": "Bearer ${OTLP_TOKEN}"}}
+	if err := ExportOTLP(srv.Client(), cfg, testRun()); err != nil {
+		t.Fatalf("ExportOTLP() error = %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	var traces otlpTraces
+	if err := json.Unmarshal(bodies["/v1/traces"], &traces); err != nil {
+		t.Fatalf("traces: %v", err)
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 || spans[0].Status.Code != 2 || len(spans[0].TraceID) != 32 {
+		t.Fatalf("spans = %+v", spans)
+	}
+	qa := spans[1]
+	if qa.ParentSpanID != spans[0].SpanID || qa.Name != "QA validation" || len(qa.Events) != 2 ||
+		qa.EndTimeUnixNano != unixNano(testRun().Start.Add(3*time.Second)) {
+		t.Errorf("QA span = %+v", qa)
+	}
+	if attrs := traces.ResourceSpans[0].Resource.Attributes; len(attrs) != 4 || attrs[1].Value.StringValue != "github.com/org/repo" {
+		t.Errorf("resource = %+v", attrs)
+	}
+
+	var metrics otlpMetrics
+	if err := json.Unmarshal(bodies["/v1/metrics"], &metrics); err != nil {
+		t.Fatalf("metrics: %v", err)
+	}
+	ms := metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(ms) != 5 || ms[0].Name != "atrelease.validation.go" || ms[3].Name != "atrelease.checks" || len(ms[3].Gauge.DataPoints) != 8 {
+		t.Errorf("metrics = %+v", ms)
+	}
+}
+
+func TestExport_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cfg := config.TelemetryConfig{
+		OTLP:        config.OTLPConfig{Endpoint: srv.URL},
+		Pushgateway: config.PushgatewayConfig{URL: srv.URL},
+	}
+	err := Export(srv.Client(), cfg, testRun())
+	if err == nil || !strings.Contains(err.Error(), "OTLP traces") || !strings.Contains(err.Error(), "Pushgateway") {
+		t.Errorf("Export() error = %v, want both exporters' errors", err)
+	}
+}
@@ -0,0 +1,115 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/output"
+)
+
+// approvalStep returns the step that, when release.approvals is set, holds
+// the tag until other maintainers approve the release.
+func approvalStep() Step {
+	return Step{
+		Name:        "Require approval",
+		Description: "Require approval by other maintainers before tagging",
+		Type:        StepTypeFunc,
+		Required:    true,
+		Func:        requireApproval,
+	}
+}
+
+// requireApproval checks that release.approvals maintainers other than the
+// releaser approved the release: through reviews of the release PR, or,
+// without one, by confirming interactively while gh is authenticated as
+// them. A config that can't be loaded fails the step, since the number of
+// approvals it requires is unknown.
+func requireApproval(ctx *Context) error {
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return fmt.Errorf("loading release.approvals: %w", err)
+	}
+	need := cfg.Release.Approvals
+	if need <= 0 {
+		ctx.Log("  No approvals required")
+		return nil
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would require %d approval(s) before tagging", need)
+		return nil
+	}
+
+	g := git.New(ctx.Dir)
+	if pr := ctx.Data[dataPullRequest]; pr != "" {
+		return requirePRApproval(ctx, g, pr, need)
+	}
+	return confirmApproval(ctx, g, need)
+}
+
+// requirePRApproval checks that the release PR has need approvals.
+func requirePRApproval(ctx *Context, g *git.Git, pr string, need int) error {
+	approvers, err := g.PRApprovers(pr)
+	if err != nil {
+		return fmt.Errorf("checking approvals of %s: %w", pr, err)
+	}
+	if len(approvers) < need {
+		return output.WithCode(output.ErrCodeApprovalRequired, fmt.Errorf("%s has %d of %d required approval(s)", pr, len(approvers), need))
+	}
+	ctx.Log("  Approved by %s", strings.Join(approvers, ", "))
+	return nil
+}
+
+// confirmApproval asks need other maintainers in turn to switch gh to
+// their account and confirm the release, verifying each is a different
+// user with push access.
+func confirmApproval(ctx *Context, g *git.Git, need int) error {
+	if !ctx.Interactive || ctx.Prompter == nil {
+		return output.WithCode(output.ErrCodeApprovalRequired, fmt.Errorf("releases need %d approval(s); run with --interactive so another maintainer can confirm, or release with --pr", need))
+	}
+
+	releaser, err := g.CurrentUser()
+	if err != nil {
+		return fmt.Errorf("identifying the releaser: %w", err)
+	}
+
+	approvers := []string{releaser}
+	for len(approvers) <= need {
+		msg := fmt.Sprintf("Approval %d of %d for %s: approver, switch gh to your account ('gh auth switch' or 'gh auth login'). Approve the release?", len(approvers), need, ctx.Version)
+		ok, err := ctx.Prompter.Confirm(msg)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return output.WithCode(output.ErrCodeApprovalRequired, fmt.Errorf("release %s was not approved", ctx.Version))
+		}
+
+		approver, err := g.CurrentUser()
+		if err != nil {
+			return fmt.Errorf("identifying the approver: %w", err)
+		}
+		if slices.Contains(approvers, approver) {
+			ctx.Log("  %s already took part in this release; another maintainer must approve", approver)
+			continue
+		}
+		permission, err := g.Permission(approver)
+		if err != nil {
+			return fmt.Errorf("checking permission of %s: %w", approver, err)
+		}
+		if !git.CanMaintain(permission) {
+			ctx.Log("  %s has %s permission; approvers need write access", approver, permission)
+			continue
+		}
+		approvers = append(approvers, approver)
+		ctx.Log("  Approved by %s", approver)
+	}
+
+	// The tag is pushed as the releaser, not the last approver
+	if err := g.SwitchUser(releaser); err != nil {
+		ctx.Log("  Warning: could not switch gh back to %s: %v", releaser, err)
+	}
+	return nil
+}
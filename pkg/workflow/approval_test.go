@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/output"
+)
+
+func TestRequireApproval(t *testing.T) {
+	dir := t.TempDir()
+	ctx := NewContext(dir, "v1.2.0")
+	if err := requireApproval(ctx); err != nil {
+		t.Fatalf("requireApproval without release.approvals: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("release:\n  approvals: 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := requireApproval(ctx)
+	if output.CodeOf(err) != output.ErrCodeApprovalRequired {
+		t.Errorf("expected %s without --interactive or a PR, got %v", output.ErrCodeApprovalRequired, err)
+	}
+
+	ctx.DryRun = true
+	if err := requireApproval(ctx); err != nil {
+		t.Errorf("requireApproval in dry run: %v", err)
+	}
+}
+
+func TestRequireApproval_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("release:\n  approvals: two\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	err := requireApproval(NewContext(dir, "v1.2.0"))
+	if output.CodeOf(err) != output.ErrCodeConfigInvalid {
+		t.Errorf("expected %s for a broken config, got %v", output.ErrCodeConfigInvalid, err)
+	}
+}
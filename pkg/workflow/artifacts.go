@@ -0,0 +1,224 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/artifacts"
+	"github.com/plexusone/agent-team-release/pkg/build"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
+)
+
+// builderID identifies atrelease as the producer of provenance attestations.
+const builderID = "https://github.com/plexusone/agent-team-release"
+
+// buildStep returns the optional step that cross-compiles release archives.
+func buildStep() Step {
+	return Step{
+		Name:        "Build binaries",
+		Description: "Cross-compile release archives for the configured platforms",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        buildBinaries,
+	}
+}
+
+// buildBinaries cross-compiles build.main for each target in build.targets
+// and writes versioned archives, recording them for publishArtifacts.
+func buildBinaries(ctx *Context) error {
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	bc := cfg.Build
+	if bc.Main == "" {
+		ctx.Log("  No binary build configured (build.main)")
+		return nil
+	}
+
+	specs := bc.Targets
+	if len(specs) == 0 {
+		specs = build.DefaultTargets
+	}
+	targets, err := build.ParseTargets(specs)
+	if err != nil {
+		return err
+	}
+
+	opts := build.Options{
+		Main:      bc.Main,
+		Binary:    bc.Binary,
+		Project:   bc.Project,
+		Version:   ctx.Version,
+		LDFlags:   bc.LDFlags,
+		OutputDir: bc.Output,
+		Files:     bc.Files,
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would build %s for %d platforms:", bc.Main, len(targets))
+		for _, t := range targets {
+			ctx.Log("    %-15s -> %s", t, build.ArchivePath(opts, t))
+		}
+		return nil
+	}
+
+	g := git.New(ctx.Dir)
+	if opts.Commit, err = g.ResolveCommit(ctx.Version); err != nil {
+		opts.Commit, _ = g.CurrentCommit()
+	}
+
+	var archives []string
+	for _, t := range targets {
+		archive, err := build.Build(ctx.Dir, opts, t)
+		if err != nil {
+			return err
+		}
+		ctx.Log("    %-15s -> %s", t, archive)
+		archives = append(archives, archive)
+	}
+	ctx.Data[dataBuiltArchives] = strings.Join(archives, "\n")
+
+	ctx.Log("  Built %d archives", len(archives))
+	return nil
+}
+
+// artifactsStep returns the optional step that checksums built artifacts,
+// attests their provenance, and uploads them to the GitHub release.
+func artifactsStep() Step {
+	return Step{
+		Name:        "Publish artifacts",
+		Description: "Write checksums and provenance for built artifacts and upload them to the GitHub release",
+		Type:        StepTypeFunc,
+		Required:    false,
+		Func:        publishArtifacts,
+	}
+}
+
+// publishArtifacts computes SHA256 checksums for the artifacts matching
+// artifacts.paths, or the archives written by buildBinaries, writes the
+// checksums file, attests SLSA provenance with cosign when it is installed,
+// and uploads everything to the release.
+func publishArtifacts(ctx *Context) error {
+	started := time.Now()
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	ac := cfg.Artifacts
+	if len(ac.Paths) == 0 && ctx.Data[dataBuiltArchives] != "" {
+		ac.Paths = strings.Split(ctx.Data[dataBuiltArchives], "\n")
+	}
+	if len(ac.Paths) == 0 {
+		ctx.Log("  No artifacts configured (artifacts.paths)")
+		return nil
+	}
+
+	paths, err := artifacts.Collect(ctx.Dir, ac.Paths)
+	if err != nil {
+		return err
+	}
+	checksumsFile := checksumsPath(ctx.Dir, ac.ChecksumsFile, paths)
+	paths = removePath(paths, checksumsFile)
+	if len(paths) == 0 {
+		ctx.Log("  No artifacts match %s; build them before releasing", strings.Join(ac.Paths, ", "))
+		return nil
+	}
+
+	sums, err := artifacts.Checksums(paths)
+	if err != nil {
+		return err
+	}
+	for _, c := range sums {
+		ctx.Log("    %s  %s", c.SHA256, c.Name())
+	}
+
+	attest := ac.Provenance && artifacts.CosignAvailable()
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would write %s", relativeTo(ctx.Dir, checksumsFile))
+		if attest {
+			ctx.Log("  [Dry run] Would attest provenance with cosign")
+		}
+		if ac.Upload {
+			ctx.Log("  [Dry run] Would upload %d artifacts to release %s", len(sums), ctx.Version)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(checksumsFile, artifacts.FormatChecksums(sums), 0644); err != nil {
+		return fmt.Errorf("failed to write checksums: %w", err)
+	}
+	ctx.Log("  Wrote %s", relativeTo(ctx.Dir, checksumsFile))
+	uploads := append(paths, checksumsFile)
+
+	g := git.New(ctx.Dir)
+	switch {
+	case !ac.Provenance:
+	case !attest:
+		ctx.Log("  cosign not found, skipping provenance attestation")
+	default:
+		info := artifacts.ProvenanceInfo{
+			Tag:       ctx.Version,
+			BuilderID: builderID,
+			StartedOn: started,
+		}
+		info.Repository, _ = g.RepositoryURL()
+		info.Commit, _ = g.ResolveCommit(ctx.Version)
+		predicate, err := artifacts.Provenance(info, sums)
+		if err != nil {
+			return err
+		}
+		attestation, err := artifacts.Attest(checksumsFile, predicate, ac.CosignKey)
+		if err != nil {
+			return err
+		}
+		ctx.Log("  Wrote %s", relativeTo(ctx.Dir, attestation))
+		uploads = append(uploads, attestation)
+	}
+
+	if !ac.Upload {
+		return nil
+	}
+	if !commandExists("gh") {
+		ctx.Log("  gh CLI not found, skipping upload")
+		return nil
+	}
+	if err := g.UploadReleaseAssets(ctx.Version, uploads...); err != nil {
+		return err
+	}
+	ctx.Log("  Uploaded %d files to release %s", len(uploads), ctx.Version)
+	return nil
+}
+
+// checksumsPath returns the configured checksums file, resolved against
+// dir, or checksums.txt in the directory of the first artifact.
+func checksumsPath(dir, configured string, paths []string) string {
+	switch {
+	case configured != "":
+		return filepath.Join(dir, configured)
+	case len(paths) > 0:
+		return filepath.Join(filepath.Dir(paths[0]), artifacts.DefaultChecksumsFile)
+	}
+	return filepath.Join(dir, artifacts.DefaultChecksumsFile)
+}
+
+func removePath(paths []string, path string) []string {
+	var out []string
+	for _, p := range paths {
+		if filepath.Clean(p) != filepath.Clean(path) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func relativeTo(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/output"
+)
+
+func TestBuildBinaries_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	config := "build:\n  main: ./cmd/tool\n  targets: [linux/arm64, windows/amd64]\n"
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContext(dir, "v1.2.0")
+	ctx.DryRun = true
+	if err := buildBinaries(ctx); err != nil {
+		t.Fatalf("buildBinaries() error: %v", err)
+	}
+	out := ctx.Output.String()
+	for _, want := range []string{
+		"Would build ./cmd/tool for 2 platforms",
+		"linux/arm64     -> " + filepath.Join("dist", "tool_1.2.0_linux_arm64.tar.gz"),
+		"windows/amd64   -> " + filepath.Join("dist", "tool_1.2.0_windows_x86_64.zip"),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dist")); err == nil {
+		t.Error("dry run created dist/")
+	}
+}
+
+func TestPublishArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := NewContext(dir, "v1.2.0")
+	if err := publishArtifacts(ctx); err != nil || !strings.Contains(ctx.Output.String(), "No artifacts configured") {
+		t.Fatalf("no config: err = %v, output:\n%s", err, ctx.Output)
+	}
+
+	write(".releaseagent.yaml", "artifacts:\n  paths: [\"dist/*\"]\n  provenance: false\n  upload: false\n")
+	write("dist/app.tar.gz", "hello\n")
+	write("dist/checksums.txt", "stale")
+
+	ctx = NewContext(dir, "v1.2.0")
+	ctx.DryRun = true
+	if err := publishArtifacts(ctx); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if out := ctx.Output.String(); !strings.Contains(out, "Would write "+filepath.Join("dist", "checksums.txt")) {
+		t.Errorf("dry run output:\n%s", out)
+	}
+
+	ctx = NewContext(dir, "v1.2.0")
+	if err := publishArtifacts(ctx); err != nil {
+		t.Fatalf("publishArtifacts() error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "dist", "checksums.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  app.tar.gz\n"
+	if string(data) != want {
+		t.Errorf("checksums.txt = %q, want %q", data, want)
+	}
+}
+
+func TestBuildBinaries_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("build: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := buildBinaries(NewContext(dir, "v1.0.0")); output.CodeOf(err) != output.ErrCodeConfigInvalid {
+		t.Errorf("expected %s for a broken config, got %v", output.ErrCodeConfigInvalid, err)
+	}
+}
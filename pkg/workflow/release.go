@@ -1,14 +1,21 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/plexusone/agent-team-release/pkg/actions"
 	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
 	"github.com/plexusone/agent-team-release/pkg/detect"
 	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/output"
+	"github.com/plexusone/agent-team-release/pkg/qametrics"
+	"github.com/plexusone/agent-team-release/pkg/runlog"
+	"github.com/plexusone/agent-team-release/pkg/semver"
 	"github.com/plexusone/assistantkit/requirements"
 )
 
@@ -17,74 +24,141 @@ func ReleaseWorkflow(version string) *Workflow {
 	return &Workflow{
 		Name:        "Release " + version,
 		Description: "Prepare and create release " + version,
-		Steps: []Step{
-			{
-				Name:        "Validate version",
-				Description: "Check version format and ensure it doesn't exist",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        validateVersion,
-			},
-			{
-				Name:        "Check working directory",
-				Description: "Ensure no uncommitted changes",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        checkWorkingDirectory,
-			},
-			{
-				Name:        "Run validation checks",
-				Description: "Run build, test, lint, format checks",
-				Type:        StepTypeFunc,
-				Required:    true,
-				Func:        runValidationChecks,
-			},
-			{
-				Name:        "Generate changelog",
-				Description: "Update CHANGELOG.md with new entries",
-				Type:        StepTypeFunc,
-				Required:    false,
-				Func:        generateChangelog,
-			},
-			{
-				Name:        "Update roadmap",
-				Description: "Regenerate ROADMAP.md",
-				Type:        StepTypeFunc,
-				Required:    false,
-				Func:        updateRoadmap,
-			},
-			{
+		Steps: append(prepareSteps(),
+			Step{
 				Name:        "Create release commit",
 				Description: "Commit all changes with release message",
 				Type:        StepTypeFunc,
 				Required:    true,
 				Func:        createReleaseCommit,
 			},
-			{
+			Step{
 				Name:        "Push to remote",
 				Description: "Push commits to origin",
 				Type:        StepTypeFunc,
 				Required:    true,
 				Func:        pushToRemote,
 			},
-			{
+			Step{
 				Name:        "Wait for CI",
 				Description: "Wait for CI checks to pass",
 				Type:        StepTypeFunc,
 				Required:    false,
 				Func:        waitForCI,
 			},
-			{
+			approvalStep(),
+			Step{
 				Name:        "Create tag",
 				Description: "Create and push release tag",
 				Type:        StepTypeFunc,
 				Required:    true,
 				Func:        createTag,
 			},
+			buildStep(),
+			artifactsStep(),
+		),
+	}
+}
+
+// prepareSteps returns the steps shared by the release workflows that
+// validate the release and update the changelog and roadmap.
+func prepareSteps() []Step {
+	return []Step{
+		{
+			Name:        "Check freeze window",
+			Description: "Ensure releases aren't frozen, unless forced",
+			Type:        StepTypeFunc,
+			Required:    true,
+			Func:        checkFreezeWindow,
+		},
+		{
+			Name:        "Validate version",
+			Description: "Check version format and ensure it doesn't exist",
+			Type:        StepTypeFunc,
+			Required:    true,
+			Func:        validateVersion,
+		},
+		{
+			Name:        "Check credentials",
+			Description: "Ensure git can push and the GitHub credential has the permissions the release needs",
+			Type:        StepTypeFunc,
+			Required:    true,
+			Func:        checkCredentials,
+		},
+		{
+			Name:        "Check working directory",
+			Description: "Ensure no uncommitted changes and the branch is up to date",
+			Type:        StepTypeFunc,
+			Required:    true,
+			Func:        checkWorkingDirectory,
+		},
+		{
+			Name:        "Run validation checks",
+			Description: "Run build, test, lint, format checks",
+			Type:        StepTypeFunc,
+			Required:    true,
+			Func:        runValidationChecks,
+		},
+		{
+			Name:        "Generate changelog",
+			Description: "Update CHANGELOG.md with new entries",
+			Type:        StepTypeFunc,
+			Required:    false,
+			Func:        generateChangelog,
+		},
+		{
+			Name:        "Update roadmap",
+			Description: "Regenerate ROADMAP.md",
+			Type:        StepTypeFunc,
+			Required:    false,
+			Func:        updateRoadmap,
 		},
 	}
 }
 
+// checkFreezeWindow blocks the release during a configured freeze window.
+// With --force the release proceeds and the override is recorded in the
+// audit log. A config that can't be loaded fails the step, even with
+// --force, since its freeze windows are unknown.
+func checkFreezeWindow(ctx *Context) error {
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return fmt.Errorf("loading freeze windows: %w", err)
+	}
+	w, err := checks.ActiveFreeze(cfg.Release.Freeze, time.Now())
+	if err != nil {
+		return err
+	}
+	if w == nil {
+		ctx.Log("  Not in a freeze window")
+		return nil
+	}
+
+	label := checks.FreezeLabel(*w)
+	if !ctx.Force {
+		return output.WithCode(output.ErrCodeReleaseFrozen, fmt.Errorf("releases are frozen (%s); use --force to override", label))
+	}
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would override freeze window %s and record it in the audit log", label)
+		return nil
+	}
+
+	user, _ := git.New(ctx.Dir).UserName()
+	entry := runlog.AuditEntry{
+		Time:     time.Now(),
+		Action:   "release",
+		Version:  ctx.Version,
+		User:     user,
+		Override: "freeze",
+		Reason:   label,
+	}
+	if err := runlog.AppendAudit(ctx.Dir, entry); err != nil {
+		return fmt.Errorf("recording freeze override: %w", err)
+	}
+	ctx.Log("  Warning: releasing during freeze window %s (--force); recorded in %s", label, runlog.AuditPath(ctx.Dir))
+	return nil
+}
+
 // validateVersion checks that the version is valid and doesn't already exist.
 func validateVersion(ctx *Context) error {
 	if ctx.Version == "" {
@@ -95,6 +169,9 @@ func validateVersion(ctx *Context) error {
 	if ctx.Version[0] != 'v' {
 		ctx.Version = "v" + ctx.Version
 	}
+	if !semver.IsValid(ctx.Version) {
+		return fmt.Errorf("invalid version %s: expected vMAJOR.MINOR.PATCH", ctx.Version)
+	}
 
 	// Check if tag already exists
 	g := git.New(ctx.Dir)
@@ -102,7 +179,7 @@ func validateVersion(ctx *Context) error {
 	if err == nil {
 		for _, tag := range tags {
 			if tag == ctx.Version {
-				return fmt.Errorf("tag %s already exists", ctx.Version)
+				return output.WithCode(output.ErrCodeTagExists, fmt.Errorf("tag %s already exists", ctx.Version))
 			}
 		}
 	}
@@ -111,6 +188,55 @@ func validateVersion(ctx *Context) error {
 	return nil
 }
 
+// checkCredentials fails before anything is committed or pushed if the
+// release lacks the access it needs, rather than partway through. git must
+// be able to push to the remote with its own credential, such as an SSH
+// key; on GitHub, gh's credential, which the GitHub API steps use, must
+// also have the permissions the release needs. The gh check is skipped,
+// with a note, when the remote isn't on GitHub or gh isn't logged in.
+func checkCredentials(ctx *Context) error {
+	if ctx.Offline {
+		return Skip(checks.OfflineReason)
+	}
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would verify that git can push and the GitHub credential's permissions")
+		return nil
+	}
+
+	g := git.New(ctx.Dir)
+	if err := g.CheckPush(); err != nil {
+		return output.WithCode(output.ErrCodeAuthScope, fmt.Errorf("git can't push to %s: %w", g.Remote, err))
+	}
+	ctx.Log("  git can push to %s", g.Remote)
+
+	switch {
+	case !g.IsGitHub():
+		ctx.Log("  Remote isn't on GitHub, skipping the GitHub credential check")
+		return nil
+	case !commandExists("gh"):
+		ctx.Log("  gh CLI not found, skipping the GitHub credential check")
+		return nil
+	case !g.GHAuthenticated():
+		ctx.Log("  Warning: gh isn't logged in to %s; GitHub API steps may fail (run 'gh auth login')", g.Host())
+		return nil
+	}
+
+	cred, err := g.Credential()
+	if err != nil {
+		return fmt.Errorf("checking GitHub credential: %w", err)
+	}
+	if missing := cred.Missing(); len(missing) > 0 {
+		return output.WithCode(output.ErrCodeAuthScope, fmt.Errorf("gh's GitHub credential lacks required permissions:\n    %s", strings.Join(missing, "\n    ")))
+	}
+
+	if cred.Classic {
+		ctx.Log("  Token scopes: %s", strings.Join(cred.Scopes, ", "))
+	} else {
+		ctx.Log("  Push access to %s (workflow permission can't be verified for fine-grained tokens)", cred.Repository)
+	}
+	return nil
+}
+
 // checkWorkingDirectory ensures there are no uncommitted changes.
 func checkWorkingDirectory(ctx *Context) error {
 	g := git.New(ctx.Dir)
@@ -126,10 +252,71 @@ func checkWorkingDirectory(ctx *Context) error {
 			ctx.Log("  Warning: working directory has uncommitted changes")
 			return nil
 		}
-		return fmt.Errorf("working directory has uncommitted changes; commit or stash them first")
+		return output.WithCode(output.ErrCodeGitDirty, fmt.Errorf("working directory has uncommitted changes; commit or stash them first"))
 	}
 
 	ctx.Log("  Working directory is clean")
+
+	return checkBranchDivergence(ctx, g)
+}
+
+// checkBranchDivergence fetches the remote and ensures the branch is not
+// behind its upstream and shares history with the default branch, so the
+// release doesn't fail later at push time.
+func checkBranchDivergence(ctx *Context, g *git.Git) error {
+	if ctx.Offline {
+		ctx.Log("  Skipping divergence check (offline)")
+		return nil
+	}
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Not fetching %s; comparing with its last fetched state", g.Remote)
+	} else if err := g.Fetch(); err != nil {
+		ctx.Log("  Warning: could not fetch %s, skipping divergence check: %v", g.Remote, err)
+		return nil
+	}
+
+	status, err := g.Status()
+	if err != nil {
+		return fmt.Errorf("failed to check git status: %w", err)
+	}
+
+	if status.HasRemote && status.Behind > 0 {
+		msg := fmt.Sprintf("branch %s is %d commit(s) behind %s", status.Branch, status.Behind, status.RemoteBranch)
+		switch {
+		case ctx.DryRun:
+			ctx.Log("  Warning: %s", msg)
+		case ctx.Interactive && ctx.Prompter != nil:
+			pull, err := ctx.Prompter.Confirm(msg + "; pull and rebase now?")
+			if err != nil {
+				return err
+			}
+			if !pull {
+				return output.WithCode(output.ErrCodeGitDiverged, fmt.Errorf("%s; pull or rebase first", msg))
+			}
+			if err := g.PullRebase(); err != nil {
+				return output.WithCode(output.ErrCodeGitDiverged, fmt.Errorf("pull --rebase failed: %w", err))
+			}
+			ctx.Log("  Rebased onto %s", status.RemoteBranch)
+		default:
+			return output.WithCode(output.ErrCodeGitDiverged, fmt.Errorf("%s; run 'git pull --rebase' first", msg))
+		}
+	} else if status.HasRemote {
+		ctx.Log("  Branch is up to date with %s", status.RemoteBranch)
+	}
+
+	defaultRef, err := g.DefaultBranchRef()
+	if err != nil {
+		return nil
+	}
+	if _, err := g.MergeBase("HEAD", defaultRef); err != nil {
+		msg := fmt.Sprintf("branch %s shares no history with %s", status.Branch, defaultRef)
+		if ctx.DryRun {
+			ctx.Log("  Warning: %s", msg)
+			return nil
+		}
+		return output.WithCode(output.ErrCodeGitDiverged, errors.New(msg))
+	}
+
 	return nil
 }
 
@@ -137,60 +324,118 @@ func checkWorkingDirectory(ctx *Context) error {
 func runValidationChecks(ctx *Context) error {
 	if ctx.SkipChecks {
 		ctx.Log("  Skipping validation checks (--skip-checks)")
+		ctx.Data[dataValidationReport] = "Validation checks were skipped (`--skip-checks`)."
 		return nil
 	}
 
 	// Check if releasekit is available, prompt for installation if not
 	if !checks.ReleasekitAvailable() {
+		// Installing runs outside proc's dry-run guard, so a dry run only
+		// reports the missing tool
+		if ctx.DryRun {
+			ctx.Log("  [Dry run] releasekit CLI not installed; would offer to install it, skipping validation")
+			ctx.Data[dataValidationReport] = "Validation checks were skipped (releasekit not installed)."
+			return nil
+		}
 		prompter := requirements.NewCLIPrompter()
 		reqResult := requirements.EnsureRequirements([]string{"releasekit"}, prompter)
 		if !reqResult.AllSatisfied() {
 			ctx.Log("  Warning: releasekit CLI not installed, skipping validation")
+			ctx.Data[dataValidationReport] = "Validation checks were skipped (releasekit not installed)."
 			return nil
 		}
 	}
 
 	// Detect languages to see if there's anything to check
-	detections, err := detect.Detect(ctx.Dir)
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	detectOpts := detect.Options{
+		Exclude:  cfg.Detect.Exclude,
+		MaxDepth: cfg.Detect.MaxDepth,
+	}
+	if cfg.Detect.Cache {
+		detectOpts.CacheDir = detect.DefaultCacheDir()
+	}
+	detections, err := detect.DetectWith(ctx.Dir, detectOpts)
 	if err != nil {
 		return fmt.Errorf("failed to detect languages: %w", err)
 	}
 
 	if len(detections) == 0 {
 		ctx.Log("  No supported languages detected, skipping checks")
+		ctx.Data[dataValidationReport] = "No supported languages detected; validation checks were skipped."
 		return nil
 	}
 
-	ctx.Log("  Running releasekit validate...")
-
-	// Build options
-	opts := checks.Options{
-		Test:    true,
-		Lint:    true,
-		Format:  true,
-		Verbose: ctx.Verbose,
+	// A dry run makes no remote calls, so checks that need the network are
+	// skipped as with --offline
+	if ctx.DryRun && !checks.Offline() {
+		checks.SetOffline(true)
+		defer checks.SetOffline(false)
 	}
 
-	// Run releasekit validate (it auto-detects languages)
-	results, err := checks.RunReleasekit(ctx.Dir, opts)
+	// Run the language checks with the settings in the config
+	var flags checks.OptionFlags
+	if ctx.Verbose {
+		flags.Verbose = &ctx.Verbose
+	}
+	engine, err := checks.NewEngine(cfg, flags)
 	if err != nil {
-		return fmt.Errorf("releasekit failed: %w", err)
+		return err
+	}
+	engine.Log = func(format string, args ...any) {
+		ctx.Log("  "+format, args...)
+	}
+	results, err := engine.Run(ctx.Dir, detections)
+	if err != nil {
+		return err
 	}
 
 	// Count results
 	failed := 0
 	for _, r := range results {
-		if !r.Passed && !r.Skipped && !r.Warning {
+		if r.Severity() == checks.SeverityFailed {
 			failed++
 			ctx.Log("    ✗ %s: %s", r.Name, r.Output)
 		}
 	}
 
 	if failed > 0 {
-		return fmt.Errorf("%d checks failed", failed)
+		return output.WithCode(output.ErrCodeCheckFailed, fmt.Errorf("%d checks failed", failed))
 	}
 
+	ctx.Data[dataValidationReport] = validationReport(results)
 	ctx.Log("  All checks passed")
+
+	if cfg.QABaseline.Enabled {
+		return recordQAMetrics(ctx, results)
+	}
+	return nil
+}
+
+// recordQAMetrics records the QA metrics of the release in
+// .release-agent/metrics.json, which the release commit then includes, so
+// validate can compare the next release with this one.
+func recordQAMetrics(ctx *Context, results []checks.Result) error {
+	m := checks.QAMetrics(ctx.Dir, ctx.Version, results)
+	m.Recorded = time.Now().UTC()
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would record QA metrics for %s (%d tests)", ctx.Version, m.Tests)
+		return nil
+	}
+
+	history, err := qametrics.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	history.Record(m)
+	if err := qametrics.Save(ctx.Dir, history); err != nil {
+		return fmt.Errorf("failed to record QA metrics: %w", err)
+	}
+	ctx.Log("  Recorded QA metrics for %s", ctx.Version)
 	return nil
 }
 
@@ -209,7 +454,7 @@ func generateChangelog(ctx *Context) error {
 		Verbose: ctx.Verbose,
 	}
 
-	result := action.Run(ctx.Dir, opts)
+	result := actions.Run(action, ctx.Dir, opts)
 	if !result.Success {
 		if result.Error != nil {
 			ctx.Log("  Warning: %v", result.Error)
@@ -224,14 +469,21 @@ func generateChangelog(ctx *Context) error {
 
 // updateRoadmap regenerates the roadmap.
 func updateRoadmap(ctx *Context) error {
-	action := &actions.RoadmapAction{}
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	action := &actions.RoadmapAction{
+		SyncIssues: cfg.Roadmap.SyncIssues,
+		IssueLabel: cfg.Roadmap.IssueLabel,
+	}
 
 	opts := actions.Options{
 		DryRun:  ctx.DryRun,
 		Verbose: ctx.Verbose,
 	}
 
-	result := action.Run(ctx.Dir, opts)
+	result := actions.Run(action, ctx.Dir, opts)
 	if !result.Success {
 		if result.Error != nil {
 			ctx.Log("  Warning: %v", result.Error)
@@ -259,17 +511,26 @@ func createReleaseCommit(ctx *Context) error {
 		return nil
 	}
 
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	message, err := actions.RenderCommitMessage(cfg.Release.CommitTemplate, actions.LoadMessageData(ctx.Dir, ctx.Version))
+	if err != nil {
+		return err
+	}
+	subject, _, _ := strings.Cut(message, "\n")
+
 	if ctx.DryRun {
-		ctx.Log("  [Dry run] Would create commit: chore(release): %s", ctx.Version)
+		ctx.Log("  [Dry run] Would create commit: %s", subject)
 		return nil
 	}
 
-	message := fmt.Sprintf("chore(release): %s", ctx.Version)
 	if err := g.CommitAll(message, false); err != nil {
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
 
-	ctx.Log("  Created commit: %s", message)
+	ctx.Log("  Created commit: %s", subject)
 	return nil
 }
 
@@ -307,6 +568,9 @@ func waitForCI(ctx *Context) error {
 		ctx.Log("  Skipping CI wait (--skip-ci)")
 		return nil
 	}
+	if ctx.Offline {
+		return Skip(checks.OfflineReason)
+	}
 
 	g := git.New(ctx.Dir)
 
@@ -325,7 +589,11 @@ func waitForCI(ctx *Context) error {
 
 	timeout := 10 * time.Minute
 	if err := g.WaitForCI(timeout); err != nil {
-		return fmt.Errorf("CI failed: %w", err)
+		if errors.Is(err, git.ErrCITimeout) {
+			// Mapped to CI_TIMEOUT by output.CodeOf
+			return fmt.Errorf("CI failed: %w", err)
+		}
+		return output.WithCode(output.ErrCodeCheckFailed, fmt.Errorf("CI failed: %w", err))
 	}
 
 	ctx.Log("  CI passed")
@@ -336,13 +604,21 @@ func waitForCI(ctx *Context) error {
 func createTag(ctx *Context) error {
 	g := git.New(ctx.Dir)
 
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	message, err := actions.RenderTagMessage(cfg.Tag.Template, actions.LoadMessageData(ctx.Dir, ctx.Version))
+	if err != nil {
+		return err
+	}
+
 	if ctx.DryRun {
 		ctx.Log("  [Dry run] Would create tag: %s", ctx.Version)
 		return nil
 	}
 
 	// Create the tag
-	message := fmt.Sprintf("Release %s", ctx.Version)
 	if err := g.CreateTag(ctx.Version, message, false); err != nil {
 		return fmt.Errorf("failed to create tag: %w", err)
 	}
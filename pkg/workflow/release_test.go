@@ -0,0 +1,234 @@
+package workflow
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/actions"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/interactive"
+	"github.com/plexusone/agent-team-release/pkg/output"
+	"github.com/plexusone/agent-team-release/pkg/runlog"
+)
+
+// confirmPrompter answers every confirmation with answer.
+type confirmPrompter struct {
+	answer bool
+	asked  []string
+}
+
+func (p *confirmPrompter) Ask(q interactive.Question) (interactive.Answer, error) {
+	return interactive.Answer{}, nil
+}
+func (p *confirmPrompter) ShowProposal(actions.Proposal) error { return nil }
+func (p *confirmPrompter) Confirm(message string) (bool, error) {
+	p.asked = append(p.asked, message)
+	return p.answer, nil
+}
+func (p *confirmPrompter) Info(string)  {}
+func (p *confirmPrompter) Warn(string)  {}
+func (p *confirmPrompter) Error(string) {}
+
+// newTestRepo initializes a git repository on branch main in a temporary
+// directory, skipping the test if git isn't installed. run runs git in the
+// repository with a committer identity and returns its trimmed output,
+// failing the test on error; pass -C to run it elsewhere.
+func newTestRepo(t *testing.T) (dir string, run func(args ...string) string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir = t.TempDir()
+	run = func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "-b", "main")
+	return dir, run
+}
+
+func TestCheckBranchDivergence(t *testing.T) {
+	local, run := newTestRepo(t)
+	commit := func(dir, file string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+		run("-C", dir, "add", file)
+		run("-C", dir, "commit", "-m", "add "+file)
+	}
+
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	other := filepath.Join(root, "other")
+	run("init", "--bare", "-b", "main", remote)
+	run("remote", "add", "origin", remote)
+	commit(local, "a.txt")
+	run("push", "-u", "origin", "main")
+	run("clone", remote, other)
+
+	g := git.New(local)
+
+	t.Run("up to date", func(t *testing.T) {
+		ctx := NewContext(local, "v1.0.0")
+		if err := checkBranchDivergence(ctx, g); err != nil {
+			t.Fatalf("checkBranchDivergence() error: %v", err)
+		}
+		if !strings.Contains(ctx.Output.String(), "up to date with origin/main") {
+			t.Errorf("output = %q", ctx.Output.String())
+		}
+	})
+
+	commit(other, "b.txt")
+	run("-C", other, "push", "origin", "main")
+
+	t.Run("behind", func(t *testing.T) {
+		ctx := NewContext(local, "v1.0.0")
+		err := checkBranchDivergence(ctx, g)
+		if output.CodeOf(err) != output.ErrCodeGitDiverged {
+			t.Fatalf("expected %s, got %v", output.ErrCodeGitDiverged, err)
+		}
+		if !strings.Contains(err.Error(), "1 commit(s) behind origin/main") {
+			t.Errorf("error = %q", err)
+		}
+	})
+
+	t.Run("behind dry run", func(t *testing.T) {
+		ctx := NewContext(local, "v1.0.0")
+		ctx.DryRun = true
+		if err := checkBranchDivergence(ctx, g); err != nil {
+			t.Fatalf("dry run should only warn, got %v", err)
+		}
+		if !strings.Contains(ctx.Output.String(), "Warning: branch main is 1 commit(s) behind") {
+			t.Errorf("output = %q", ctx.Output.String())
+		}
+	})
+
+	t.Run("interactive declined", func(t *testing.T) {
+		p := &confirmPrompter{answer: false}
+		ctx := NewContext(local, "v1.0.0")
+		ctx.Interactive = true
+		ctx.Prompter = p
+		if err := checkBranchDivergence(ctx, g); output.CodeOf(err) != output.ErrCodeGitDiverged {
+			t.Fatalf("expected %s, got %v", output.ErrCodeGitDiverged, err)
+		}
+		if len(p.asked) != 1 {
+			t.Errorf("expected one confirmation, got %v", p.asked)
+		}
+	})
+
+	t.Run("interactive pull", func(t *testing.T) {
+		ctx := NewContext(local, "v1.0.0")
+		ctx.Interactive = true
+		ctx.Prompter = &confirmPrompter{answer: true}
+		if err := checkBranchDivergence(ctx, g); err != nil {
+			t.Fatalf("checkBranchDivergence() error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(local, "b.txt")); err != nil {
+			t.Error("expected b.txt after pull --rebase")
+		}
+	})
+
+	t.Run("no remote", func(t *testing.T) {
+		dir, _ := newTestRepo(t)
+		commit(dir, "c.txt")
+		ctx := NewContext(dir, "v1.0.0")
+		if err := checkBranchDivergence(ctx, git.New(dir)); err != nil {
+			t.Fatalf("expected fetch failure to be a warning, got %v", err)
+		}
+	})
+}
+
+func TestCheckFreezeWindow(t *testing.T) {
+	dir := t.TempDir()
+	cfg := "release:\n  freeze:\n    - name: always\n      start: 2000-01-01\n      end: 2999-12-31\n"
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContext(dir, "v1.2.0")
+	err := checkFreezeWindow(ctx)
+	if output.CodeOf(err) != output.ErrCodeReleaseFrozen {
+		t.Fatalf("expected %s, got %v", output.ErrCodeReleaseFrozen, err)
+	}
+	if _, err := os.Stat(runlog.AuditPath(dir)); !os.IsNotExist(err) {
+		t.Error("expected no audit entry without --force")
+	}
+
+	ctx.Force = true
+	if err := checkFreezeWindow(ctx); err != nil {
+		t.Fatalf("checkFreezeWindow with Force: %v", err)
+	}
+	data, err := os.ReadFile(runlog.AuditPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"override":"freeze"`) || !strings.Contains(string(data), `"version":"v1.2.0"`) {
+		t.Errorf("unexpected audit log: %s", data)
+	}
+}
+
+func TestCheckFreezeWindow_InvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".releaseagent.yaml"), []byte("release:\n  freeze: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContext(dir, "v1.2.0")
+	ctx.Force = true
+	err := checkFreezeWindow(ctx)
+	if output.CodeOf(err) != output.ErrCodeConfigInvalid {
+		t.Errorf("expected %s for a broken config, got %v", output.ErrCodeConfigInvalid, err)
+	}
+	if _, err := os.Stat(runlog.AuditPath(dir)); !os.IsNotExist(err) {
+		t.Error("expected no audit entry when the config can't be loaded")
+	}
+}
+
+func TestCheckCredentials(t *testing.T) {
+	local, run := newTestRepo(t)
+	root := t.TempDir()
+	remote := filepath.Join(root, "remote.git")
+	run("init", "--bare", "-b", "main", remote)
+	run("commit", "--allow-empty", "-m", "initial")
+	run("remote", "add", "origin", remote)
+
+	// A remote that isn't on GitHub only needs git to be able to push
+	ctx := NewContext(local, "v1.0.0")
+	if err := checkCredentials(ctx); err != nil {
+		t.Fatalf("checkCredentials() error: %v", err)
+	}
+	if out := ctx.Output.String(); !strings.Contains(out, "git can push to origin") || !strings.Contains(out, "isn't on GitHub") {
+		t.Errorf("output = %q", out)
+	}
+
+	run("remote", "set-url", "origin", filepath.Join(root, "missing.git"))
+	if err := checkCredentials(NewContext(local, "v1.0.0")); output.CodeOf(err) != output.ErrCodeAuthScope {
+		t.Errorf("expected %s for an unreachable remote, got %v", output.ErrCodeAuthScope, err)
+	}
+}
+
+func TestRunValidationChecks_DryRunMissingReleasekit(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	ctx := NewContext(t.TempDir(), "v1.0.0")
+	ctx.DryRun = true
+	if err := runValidationChecks(ctx); err != nil {
+		t.Fatalf("runValidationChecks() error: %v", err)
+	}
+	if out := ctx.Output.String(); !strings.Contains(out, "[Dry run] releasekit CLI not installed") {
+		t.Errorf("output = %q", out)
+	}
+}
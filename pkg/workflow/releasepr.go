@@ -0,0 +1,388 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/actions"
+	"github.com/plexusone/agent-team-release/pkg/checks"
+	"github.com/plexusone/agent-team-release/pkg/config"
+	"github.com/plexusone/agent-team-release/pkg/git"
+	"github.com/plexusone/agent-team-release/pkg/output"
+	"github.com/plexusone/agent-team-release/pkg/proc"
+)
+
+// DefaultMergeTimeout is how long the release PR workflow waits for the
+// pull request to be merged when Context.MergeTimeout is not set.
+const DefaultMergeTimeout = time.Hour
+
+// Keys in Context.Data shared between release steps.
+const (
+	dataValidationReport = "validation_report" // Markdown summary of the validation checks
+	dataBaseBranch       = "base_branch"       // Branch the release PR targets
+	dataReleaseBranch    = "release_branch"    // Branch holding the release commit
+	dataPullRequest      = "pull_request"      // URL of the release PR
+	dataMergeCommit      = "merge_commit"      // SHA of the merged release PR
+	dataBuiltArchives    = "built_archives"    // Newline-separated archives written by the build step
+)
+
+// ReleasePRWorkflow creates a workflow that releases through a pull request
+// instead of pushing to the current branch. The release commit goes to a
+// release/<version> branch, a PR is opened with the validation report as its
+// description, and the tag is created on the merge commit once the PR is
+// merged, optionally by auto-merge or a merge queue.
+func ReleasePRWorkflow(version string) *Workflow {
+	return &Workflow{
+		Name:        "Release " + version,
+		Description: "Prepare release " + version + " and open a release pull request",
+		Steps: append(prepareSteps(),
+			Step{
+				Name:        "Create release branch",
+				Description: "Create " + ReleaseBranch(version) + " from the current branch",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        createReleaseBranch,
+			},
+			Step{
+				Name:        "Create release commit",
+				Description: "Commit all changes with release message",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        createReleaseCommit,
+			},
+			Step{
+				Name:        "Push release branch",
+				Description: "Push the release branch to origin",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        pushReleaseBranch,
+			},
+			Step{
+				Name:        "Open pull request",
+				Description: "Open a release PR with the validation report",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        openReleasePR,
+			},
+			Step{
+				Name:        "Enable auto-merge",
+				Description: "Enable auto-merge or add the PR to the merge queue",
+				Type:        StepTypeFunc,
+				Required:    false,
+				Func:        enableAutoMerge,
+			},
+			Step{
+				Name:        "Wait for CI",
+				Description: "Wait for PR and merge queue checks to pass",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        waitForPRCI,
+			},
+			Step{
+				Name:        "Wait for merge",
+				Description: "Wait for the release PR to be merged",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        waitForMerge,
+			},
+			approvalStep(),
+			Step{
+				Name:        "Tag merge commit",
+				Description: "Create and push the release tag on the merge commit",
+				Type:        StepTypeFunc,
+				Required:    true,
+				Func:        tagMergeCommit,
+			},
+			buildStep(),
+			artifactsStep(),
+		),
+	}
+}
+
+// ReleaseBranch returns the branch name used for a release PR.
+func ReleaseBranch(version string) string {
+	return "release/v" + strings.TrimPrefix(version, "v")
+}
+
+// createReleaseBranch creates the release branch from the current branch,
+// carrying over the changelog and roadmap updates.
+func createReleaseBranch(ctx *Context) error {
+	if !commandExists("gh") {
+		return fmt.Errorf("%w; it is required to open the release PR", proc.NotFound("gh CLI"))
+	}
+	g := git.New(ctx.Dir)
+
+	base, err := g.CurrentBranch()
+	if err != nil {
+		return err
+	}
+	branch := ReleaseBranch(ctx.Version)
+	if base == branch {
+		return fmt.Errorf("already on %s; run the release from the branch the PR should target", branch)
+	}
+	if g.RefExists("refs/heads/" + branch) {
+		return fmt.Errorf("branch %s already exists; delete it or finish that release first", branch)
+	}
+	ctx.Data[dataBaseBranch] = base
+	ctx.Data[dataReleaseBranch] = branch
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would create branch %s from %s", branch, base)
+		return nil
+	}
+
+	if err := g.CreateBranch(branch, ""); err != nil {
+		return err
+	}
+
+	ctx.Log("  Created branch %s from %s", branch, base)
+	return nil
+}
+
+// pushReleaseBranch pushes the release branch and sets its upstream.
+func pushReleaseBranch(ctx *Context) error {
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would push %s to origin", ctx.Data[dataReleaseBranch])
+		return nil
+	}
+
+	if err := git.New(ctx.Dir).PushWithUpstream(); err != nil {
+		return err
+	}
+
+	ctx.Log("  Pushed %s", ctx.Data[dataReleaseBranch])
+	return nil
+}
+
+// openReleasePR opens the release pull request against the base branch.
+func openReleasePR(ctx *Context) error {
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	data := actions.LoadMessageData(ctx.Dir, ctx.Version)
+	title, err := actions.RenderPRTitle(cfg.Release.PRTitleTemplate, data)
+	if err != nil {
+		return err
+	}
+	body := releasePRBody(ctx.Version, data.Highlights, ctx.Data[dataValidationReport])
+
+	base, branch := ctx.Data[dataBaseBranch], ctx.Data[dataReleaseBranch]
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would open PR %s -> %s: %s", branch, base, title)
+		return nil
+	}
+
+	url, err := git.New(ctx.Dir).CreatePR(base, branch, title, body)
+	if err != nil {
+		return err
+	}
+	ctx.Data[dataPullRequest] = url
+
+	ctx.Log("  Opened %s", url)
+	return nil
+}
+
+// enableAutoMerge enables auto-merge on the release pull request, which adds
+// it to the merge queue when the base branch requires one.
+func enableAutoMerge(ctx *Context) error {
+	if !ctx.AutoMerge {
+		ctx.Log("  Auto-merge not requested (--auto-merge)")
+		return nil
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would enable auto-merge")
+		return nil
+	}
+
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	if err := git.New(ctx.Dir).EnableAutoMerge(ctx.Data[dataPullRequest], cfg.Release.MergeMethod); err != nil {
+		return err
+	}
+
+	ctx.Log("  Auto-merge enabled")
+	return nil
+}
+
+// waitForPRCI waits for the release pull request's checks, following it into
+// the merge queue when there is one.
+func waitForPRCI(ctx *Context) error {
+	if ctx.SkipCI {
+		ctx.Log("  Skipping CI wait (--skip-ci)")
+		return nil
+	}
+	if ctx.Offline {
+		return Skip(checks.OfflineReason)
+	}
+
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would wait for PR and merge queue checks")
+		return nil
+	}
+
+	timeout := ctx.MergeTimeout
+	if timeout == 0 {
+		timeout = DefaultMergeTimeout
+	}
+	ctx.Log("  Waiting for CI on %s (timeout: %v)...", ctx.Data[dataPullRequest], timeout)
+
+	pr, err := git.New(ctx.Dir).WaitForPRCI(ctx.Data[dataPullRequest], timeout)
+	if err != nil {
+		if errors.Is(err, git.ErrCITimeout) {
+			return fmt.Errorf("CI failed: %w", err)
+		}
+		return output.WithCode(output.ErrCodeCheckFailed, fmt.Errorf("CI failed: %w", err))
+	}
+
+	switch {
+	case pr.IsMerged():
+		ctx.Log("  CI passed and the PR was merged")
+	case pr.MergeQueue != nil:
+		ctx.Log("  Merge queue checks passed")
+	default:
+		ctx.Log("  CI passed")
+	}
+	return nil
+}
+
+// waitForMerge waits until the release pull request is merged.
+func waitForMerge(ctx *Context) error {
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would wait for the PR to be merged")
+		return nil
+	}
+
+	timeout := ctx.MergeTimeout
+	if timeout == 0 {
+		timeout = DefaultMergeTimeout
+	}
+	ctx.Log("  Waiting for %s to be merged (timeout: %v)...", ctx.Data[dataPullRequest], timeout)
+
+	pr, err := git.New(ctx.Dir).WaitForMerge(ctx.Data[dataPullRequest], timeout)
+	if err != nil {
+		if errors.Is(err, git.ErrMergeTimeout) {
+			return fmt.Errorf("%w; once it is merged, run 'atrelease tag %s' on the updated %s", err, ctx.Version, ctx.Data[dataBaseBranch])
+		}
+		return err
+	}
+	ctx.Data[dataMergeCommit] = pr.MergeCommit
+
+	ctx.Log("  Merged as %s", shortSHA(pr.MergeCommit))
+	return nil
+}
+
+// tagMergeCommit switches back to the base branch, updates it, and tags the
+// merge commit.
+func tagMergeCommit(ctx *Context) error {
+	if ctx.DryRun {
+		ctx.Log("  [Dry run] Would create tag %s on the merge commit", ctx.Version)
+		return nil
+	}
+
+	g := git.New(ctx.Dir)
+	merge := ctx.Data[dataMergeCommit]
+	if merge == "" {
+		return fmt.Errorf("merge commit of %s is unknown", ctx.Data[dataPullRequest])
+	}
+
+	if err := g.Fetch(); err != nil {
+		return fmt.Errorf("failed to fetch merge commit: %w", err)
+	}
+	if base := ctx.Data[dataBaseBranch]; base != "" {
+		if err := g.Checkout(base); err != nil {
+			ctx.Log("  Warning: %v", err)
+		} else if err := g.PullRebase(); err != nil {
+			ctx.Log("  Warning: could not update %s: %v", base, err)
+		}
+	}
+
+	cfg, err := config.Load(ctx.Dir)
+	if err != nil {
+		return err
+	}
+	message, err := actions.RenderTagMessage(cfg.Tag.Template, actions.LoadMessageData(ctx.Dir, ctx.Version))
+	if err != nil {
+		return err
+	}
+
+	if err := g.CreateTagAt(ctx.Version, merge, message, false); err != nil {
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+	ctx.Log("  Created tag %s on %s", ctx.Version, shortSHA(merge))
+
+	if err := g.PushTag(ctx.Version); err != nil {
+		_ = g.DeleteTag(ctx.Version)
+		return fmt.Errorf("failed to push tag: %w", err)
+	}
+
+	ctx.Log("  Pushed tag: %s", ctx.Version)
+	return nil
+}
+
+// releasePRBody returns the description of the release pull request.
+func releasePRBody(version string, highlights []string, report string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Release %s.\n", version)
+
+	if len(highlights) > 0 {
+		b.WriteString("\n## Highlights\n\n")
+		for _, h := range highlights {
+			fmt.Fprintf(&b, "- %s\n", h)
+		}
+	}
+
+	b.WriteString("\n## Validation\n\n")
+	if report == "" {
+		report = "No validation report available."
+	}
+	b.WriteString(report)
+	b.WriteString("\n\n---\n")
+	fmt.Fprintf(&b, "The release tag %s is created on the merge commit once this pull request is merged.\n", version)
+	return b.String()
+}
+
+// validationReport summarizes check results as a Markdown table.
+func validationReport(results []checks.Result) string {
+	if len(results) == 0 {
+		return "No checks were run."
+	}
+
+	var b strings.Builder
+	b.WriteString("| Check | Result |\n")
+	b.WriteString("|-------|--------|\n")
+	for _, r := range results {
+		status := "✅ passed"
+		switch r.Severity() {
+		case checks.SeveritySkipped:
+			status = "⏭️ skipped"
+			if r.Reason != "" {
+				status += ": " + r.Reason
+			}
+		case checks.SeverityWarning:
+			status = "⚠️ warning"
+		case checks.SeverityFailed:
+			status = "❌ failed"
+		}
+		fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(r.Name), markdownCell(status))
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// markdownCell escapes text for a single Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
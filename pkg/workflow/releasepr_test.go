@@ -0,0 +1,51 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plexusone/agent-team-release/pkg/checks"
+)
+
+func TestReleaseBranch(t *testing.T) {
+	for _, v := range []string{"v1.2.0", "1.2.0"} {
+		if got := ReleaseBranch(v); got != "release/v1.2.0" {
+			t.Errorf("ReleaseBranch(%q) = %q, want release/v1.2.0", v, got)
+		}
+	}
+}
+
+func TestValidationReport(t *testing.T) {
+	if got := validationReport(nil); got != "No checks were run." {
+		t.Errorf("validationReport(nil) = %q", got)
+	}
+
+	got := validationReport([]checks.Result{
+		{Name: "Go: tests", Passed: true},
+		{Name: "Go: lint", Warning: true},
+		{Name: "Go: a|b", Passed: false},
+		{Name: "Docs: PRD", Skipped: true, Reason: "PRD.md\nnot found"},
+	})
+	want := "| Check | Result |\n" +
+		"|-------|--------|\n" +
+		"| Go: tests | ✅ passed |\n" +
+		"| Go: lint | ⚠️ warning |\n" +
+		"| Go: a\\|b | ❌ failed |\n" +
+		"| Docs: PRD | ⏭️ skipped: PRD.md not found |"
+	if got != want {
+		t.Errorf("validationReport() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestReleasePRBody(t *testing.T) {
+	body := releasePRBody("v1.2.0", []string{"Faster builds"}, "| Check | Result |")
+	for _, want := range []string{"Release v1.2.0.", "## Highlights\n\n- Faster builds\n", "## Validation\n\n| Check | Result |", "tag v1.2.0 is created on the merge commit"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("releasePRBody() missing %q:\n%s", want, body)
+		}
+	}
+
+	if body := releasePRBody("v1.2.0", nil, ""); strings.Contains(body, "Highlights") || !strings.Contains(body, "No validation report available.") {
+		t.Errorf("releasePRBody() without highlights or report:\n%s", body)
+	}
+}
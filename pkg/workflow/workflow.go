@@ -2,9 +2,18 @@
 package workflow
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/interactive"
+	"github.com/plexusone/agent-team-release/pkg/output"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 // StepType defines the type of workflow step.
@@ -40,25 +49,32 @@ type Workflow struct {
 
 // Context provides context for step execution.
 type Context struct {
-	Dir         string            // Working directory
-	Version     string            // Target version
-	DryRun      bool              // If true, don't make changes
-	Verbose     bool              // Show detailed output
-	Interactive bool              // Enable interactive mode
-	JSONOutput  bool              // Output JSON for Claude Code
-	SkipChecks  bool              // Skip validation checks
-	SkipCI      bool              // Skip CI wait
-	Data        map[string]string // Arbitrary data passed between steps
-	Output      *strings.Builder  // Captured output
+	Dir           string               // Working directory
+	Version       string               // Target version
+	DryRun        bool                 // If true, don't make changes
+	Verbose       bool                 // Show detailed output
+	Interactive   bool                 // Enable interactive mode
+	JSONOutput    bool                 // Output JSON for Claude Code
+	SkipChecks    bool                 // Skip validation checks
+	SkipCI        bool                 // Skip CI wait
+	Offline       bool                 // Skip steps that only query the network
+	Force         bool                 // Release during a freeze window, recording it in the audit log
+	MergeTimeout  time.Duration        // How long to wait for a release PR to merge
+	AutoMerge     bool                 // Enable auto-merge on the release PR
+	CorrelationID string               // Run-scoped ID stamped on structured output
+	Prompter      interactive.Prompter // Asks for confirmation in interactive mode
+	Data          map[string]string    // Arbitrary data passed between steps
+	Output        *strings.Builder     // Captured output
 }
 
 // NewContext creates a new workflow context.
 func NewContext(dir string, version string) *Context {
 	return &Context{
-		Dir:     dir,
-		Version: version,
-		Data:    make(map[string]string),
-		Output:  &strings.Builder{},
+		Dir:           dir,
+		Version:       version,
+		CorrelationID: output.NewCorrelationID(),
+		Data:          make(map[string]string),
+		Output:        &strings.Builder{},
 	}
 }
 
@@ -73,22 +89,25 @@ func (c *Context) Log(format string, args ...interface{}) {
 
 // StepResult represents the result of a step execution.
 type StepResult struct {
-	Name     string
-	Success  bool
-	Skipped  bool
-	Error    error
-	Output   string
-	Duration time.Duration
-	SubSteps []StepResult // Results of sub-steps (for composite)
+	Name      string
+	Success   bool
+	Skipped   bool
+	Cancelled bool // Interrupted, or not started because the workflow was cancelled
+	Error     error
+	Output    string
+	Duration  time.Duration
+	SubSteps  []StepResult // Results of sub-steps (for composite)
 }
 
 // WorkflowResult represents the result of a workflow execution.
 type WorkflowResult struct {
-	Name     string
-	Success  bool
-	Steps    []StepResult
-	Duration time.Duration
-	Output   string
+	Name          string
+	Success       bool
+	Cancelled     bool // Interrupted by a signal; remaining steps weren't run
+	Steps         []StepResult
+	Duration      time.Duration
+	Output        string
+	CorrelationID string
 }
 
 // Runner executes workflows.
@@ -104,19 +123,35 @@ func NewRunner() *Runner {
 	return &Runner{}
 }
 
-// Run executes a workflow and returns the results.
+// Run executes a workflow and returns the results. Processes steps start
+// through package proc are killed when a required step fails or the
+// workflow is interrupted with SIGINT or SIGTERM; an interrupted step and
+// the steps after it are reported as cancelled.
 func (r *Runner) Run(w *Workflow, ctx *Context) *WorkflowResult {
 	start := time.Now()
 
+	runCtx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	defer proc.SetContext(runCtx)()
+	if r.DryRun {
+		defer proc.SetDryRun(true)()
+	}
+	blockedBefore := len(proc.Blocked())
+
 	// Apply runner settings to context
 	ctx.DryRun = r.DryRun
 	ctx.Verbose = r.Verbose
 	ctx.Interactive = r.Interactive
 	ctx.JSONOutput = r.JSONOutput
+	if p, ok := ctx.Prompter.(interface{ SetCorrelationID(string) }); ok {
+		// Questions asked during the run carry the run's ID
+		p.SetCorrelationID(ctx.CorrelationID)
+	}
 
 	result := &WorkflowResult{
-		Name:    w.Name,
-		Success: true,
+		Name:          w.Name,
+		Success:       true,
+		CorrelationID: ctx.CorrelationID,
 	}
 
 	ctx.Log("=== %s ===\n", w.Name)
@@ -126,19 +161,40 @@ func (r *Runner) Run(w *Workflow, ctx *Context) *WorkflowResult {
 	ctx.Log("")
 
 	for _, step := range w.Steps {
+		if runCtx.Err() != nil {
+			result.Steps = append(result.Steps, StepResult{Name: step.Name, Cancelled: true})
+			continue
+		}
 		stepResult := r.runStep(&step, ctx)
 		result.Steps = append(result.Steps, stepResult)
 
+		if stepResult.Cancelled {
+			result.Success = false
+			result.Cancelled = true
+			ctx.Log("\n❌ Workflow cancelled at step: %s\n", step.Name)
+			continue
+		}
 		if !stepResult.Success && !stepResult.Skipped {
 			if step.Required {
 				result.Success = false
 				ctx.Log("\n❌ Workflow failed at step: %s\n", step.Name)
+				// Stop anything the failed step left running
+				cancel()
 				break
 			}
 			ctx.Log("⚠ Step %s failed but is not required, continuing...\n", step.Name)
 		}
 	}
 
+	// A step that reached for a write or remote call in a dry run has a
+	// dry-run gap; show what was stopped
+	if blocked := proc.Blocked()[blockedBefore:]; len(blocked) > 0 {
+		ctx.Log("\n⚠ Dry run blocked %d command(s):", len(blocked))
+		for _, line := range blocked {
+			ctx.Log("  %s", line)
+		}
+	}
+
 	result.Duration = time.Since(start)
 	result.Output = ctx.Output.String()
 
@@ -149,6 +205,21 @@ func (r *Runner) Run(w *Workflow, ctx *Context) *WorkflowResult {
 	return result
 }
 
+// skipError is returned by a step that didn't run.
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return "skipped: " + e.reason
+}
+
+// Skip returns an error a step function returns to be reported as skipped
+// with reason, e.g. "offline", rather than failed or done.
+func Skip(reason string) error {
+	return &skipError{reason: reason}
+}
+
 // runStep executes a single step.
 func (r *Runner) runStep(step *Step, ctx *Context) StepResult {
 	start := time.Now()
@@ -172,7 +243,17 @@ func (r *Runner) runStep(step *Step, ctx *Context) StepResult {
 		}
 
 		err := step.Func(ctx)
-		if err != nil {
+		var skip *skipError
+		if errors.As(err, &skip) {
+			result.Skipped = true
+			result.Output = skip.reason
+			ctx.Log(" [skipped: %s]\n", skip.reason)
+		} else if err != nil && proc.Context().Err() != nil {
+			result.Cancelled = true
+			result.Error = output.WithCode(output.ErrCodeCancelled, err)
+			result.Output = err.Error()
+			ctx.Log(" [cancelled]\n")
+		} else if err != nil {
 			result.Success = false
 			result.Error = err
 			result.Output = err.Error()
@@ -186,8 +267,19 @@ func (r *Runner) runStep(step *Step, ctx *Context) StepResult {
 		ctx.Log("\n")
 		allSuccess := true
 		for _, subStep := range step.SubSteps {
+			if proc.Context().Err() != nil {
+				result.SubSteps = append(result.SubSteps, StepResult{Name: subStep.Name, Cancelled: true})
+				result.Cancelled = true
+				allSuccess = false
+				continue
+			}
 			subResult := r.runStep(&subStep, ctx)
 			result.SubSteps = append(result.SubSteps, subResult)
+			if subResult.Cancelled {
+				result.Cancelled = true
+				allSuccess = false
+				continue
+			}
 			if !subResult.Success && !subResult.Skipped && subStep.Required {
 				allSuccess = false
 				break
@@ -210,28 +302,29 @@ func (wr *WorkflowResult) Summary() string {
 	sb.WriteString("\nSteps:\n")
 
 	for _, step := range wr.Steps {
-		status := "✓"
-		if step.Skipped {
-			status = "⊘"
-		} else if !step.Success {
-			status = "✗"
-		}
-		fmt.Fprintf(&sb, "  %s %s (%s)\n", status, step.Name, step.Duration.Round(time.Millisecond))
+		fmt.Fprintf(&sb, "  %s (%s)\n", stepLine(step), step.Duration.Round(time.Millisecond))
 
 		for _, sub := range step.SubSteps {
-			subStatus := "✓"
-			if sub.Skipped {
-				subStatus = "⊘"
-			} else if !sub.Success {
-				subStatus = "✗"
-			}
-			fmt.Fprintf(&sb, "    %s %s\n", subStatus, sub.Name)
+			fmt.Fprintf(&sb, "    %s\n", stepLine(sub))
 		}
 	}
 
 	return sb.String()
 }
 
+// stepLine returns the status icon and name of a step for the summary.
+func stepLine(step StepResult) string {
+	switch {
+	case step.Cancelled:
+		return "⊘ " + step.Name + " [cancelled]"
+	case step.Skipped:
+		return "⊘ " + step.Name
+	case !step.Success:
+		return "✗ " + step.Name
+	}
+	return "✓ " + step.Name
+}
+
 func statusEmoji(success bool) string {
 	if success {
 		return "✅ Success"
@@ -241,21 +334,26 @@ func statusEmoji(success bool) string {
 
 // JSONResult represents a workflow result in structured format.
 type JSONResult struct {
-	Type         string           `json:"type" toon:"type"`
-	WorkflowName string           `json:"workflow_name" toon:"workflow_name"`
-	Success      bool             `json:"success" toon:"success"`
-	Duration     string           `json:"duration" toon:"duration"`
-	Steps        []JSONStepResult `json:"steps" toon:"steps"`
+	Type          string           `json:"type" toon:"type"`
+	WorkflowName  string           `json:"workflow_name" toon:"workflow_name"`
+	Success       bool             `json:"success" toon:"success"`
+	Cancelled     bool             `json:"cancelled,omitempty" toon:"cancelled,omitempty"`
+	Duration      string           `json:"duration" toon:"duration"`
+	Steps         []JSONStepResult `json:"steps" toon:"steps"`
+	Timestamp     string           `json:"timestamp,omitempty" toon:"timestamp,omitempty"`
+	CorrelationID string           `json:"correlation_id,omitempty" toon:"correlation_id,omitempty"`
 }
 
 // JSONStepResult represents a step result in structured format.
 type JSONStepResult struct {
-	Name     string           `json:"name" toon:"name"`
-	Success  bool             `json:"success" toon:"success"`
-	Skipped  bool             `json:"skipped,omitempty" toon:"skipped,omitempty"`
-	Error    string           `json:"error,omitempty" toon:"error,omitempty"`
-	Duration string           `json:"duration" toon:"duration"`
-	SubSteps []JSONStepResult `json:"sub_steps,omitempty" toon:"sub_steps,omitempty"`
+	Name      string           `json:"name" toon:"name"`
+	Success   bool             `json:"success" toon:"success"`
+	Skipped   bool             `json:"skipped,omitempty" toon:"skipped,omitempty"`
+	Cancelled bool             `json:"cancelled,omitempty" toon:"cancelled,omitempty"`
+	Error     string           `json:"error,omitempty" toon:"error,omitempty"`
+	ErrorCode string           `json:"error_code,omitempty" toon:"error_code,omitempty"`
+	Duration  string           `json:"duration" toon:"duration"`
+	SubSteps  []JSONStepResult `json:"sub_steps,omitempty" toon:"sub_steps,omitempty"`
 }
 
 // ToJSON converts the workflow result to a JSON-serializable structure.
@@ -266,23 +364,28 @@ func (wr *WorkflowResult) ToJSON() JSONResult {
 	}
 
 	return JSONResult{
-		Type:         "workflow_result",
-		WorkflowName: wr.Name,
-		Success:      wr.Success,
-		Duration:     wr.Duration.Round(time.Millisecond).String(),
-		Steps:        steps,
+		Type:          "workflow_result",
+		WorkflowName:  wr.Name,
+		Success:       wr.Success,
+		Cancelled:     wr.Cancelled,
+		Duration:      wr.Duration.Round(time.Millisecond).String(),
+		Steps:         steps,
+		Timestamp:     output.Timestamp(),
+		CorrelationID: wr.CorrelationID,
 	}
 }
 
 func stepToJSON(step StepResult) JSONStepResult {
 	result := JSONStepResult{
-		Name:     step.Name,
-		Success:  step.Success,
-		Skipped:  step.Skipped,
-		Duration: step.Duration.Round(time.Millisecond).String(),
+		Name:      step.Name,
+		Success:   step.Success,
+		Skipped:   step.Skipped,
+		Cancelled: step.Cancelled,
+		Duration:  step.Duration.Round(time.Millisecond).String(),
 	}
 	if step.Error != nil {
 		result.Error = step.Error.Error()
+		result.ErrorCode = string(output.CodeOf(step.Error))
 	}
 	if len(step.SubSteps) > 0 {
 		result.SubSteps = make([]JSONStepResult, len(step.SubSteps))
@@ -1,9 +1,17 @@
 package workflow
 
 import (
+	"bytes"
 	"errors"
+	"os"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/plexusone/agent-team-release/pkg/interactive"
+	"github.com/plexusone/agent-team-release/pkg/output"
+	"github.com/plexusone/agent-team-release/pkg/proc"
 )
 
 func TestNewContext(t *testing.T) {
@@ -131,6 +139,59 @@ func TestRunnerRun_RequiredStepFailure(t *testing.T) {
 	}
 }
 
+func TestRunnerRun_Interrupted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sends SIGINT")
+	}
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{
+				Name:     "Long Step",
+				Type:     StepTypeFunc,
+				Required: true,
+				Func: func(ctx *Context) error {
+					go func() {
+						time.Sleep(100 * time.Millisecond)
+						if p, err := os.FindProcess(os.Getpid()); err == nil {
+							_ = p.Signal(os.Interrupt)
+						}
+					}()
+					return proc.Command("sleep", "30").Run()
+				},
+			},
+			{
+				Name:     "Never Reached",
+				Type:     StepTypeFunc,
+				Required: true,
+				Func: func(ctx *Context) error {
+					t.Error("This step should not be executed")
+					return nil
+				},
+			},
+		},
+	}
+
+	start := time.Now()
+	result := NewRunner().Run(wf, NewContext("/tmp", "v1.0.0"))
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("interrupted step ran for %s", elapsed)
+	}
+
+	if result.Success || !result.Cancelled {
+		t.Errorf("expected a cancelled workflow, got success=%v cancelled=%v", result.Success, result.Cancelled)
+	}
+	if len(result.Steps) != 2 || !result.Steps[0].Cancelled || !result.Steps[1].Cancelled {
+		t.Fatalf("expected both steps to be cancelled, got %+v", result.Steps)
+	}
+	if output.CodeOf(result.Steps[0].Error) != output.ErrCodeCancelled {
+		t.Errorf("error code = %q, want %q", output.CodeOf(result.Steps[0].Error), output.ErrCodeCancelled)
+	}
+	if !strings.Contains(result.Summary(), "Never Reached [cancelled]") {
+		t.Errorf("summary doesn't report the cancelled step:\n%s", result.Summary())
+	}
+}
+
 func TestRunnerRun_OptionalStepFailure(t *testing.T) {
 	step2Executed := false
 
@@ -197,6 +258,68 @@ func TestRunnerRun_SkippedStep(t *testing.T) {
 	}
 }
 
+func TestRunnerRun_SkipError(t *testing.T) {
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{
+				Name:     "Offline",
+				Type:     StepTypeFunc,
+				Required: true,
+				Func: func(ctx *Context) error {
+					return Skip("offline")
+				},
+			},
+		},
+	}
+
+	runner := NewRunner()
+	ctx := NewContext("/tmp", "v1.0.0")
+	result := runner.Run(wf, ctx)
+
+	if !result.Success {
+		t.Error("Workflow should succeed with skipped step")
+	}
+	step := result.Steps[0]
+	if !step.Skipped || step.Error != nil {
+		t.Errorf("Step = %+v, want skipped without error", step)
+	}
+	if step.Output != "offline" {
+		t.Errorf("Output = %q, want %q", step.Output, "offline")
+	}
+}
+
+func TestRunnerRun_DryRunBlocksWrites(t *testing.T) {
+	var pushErr error
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{
+				Name: "Leaky step",
+				Type: StepTypeFunc,
+				Func: func(ctx *Context) error {
+					pushErr = proc.Command("git", "push", "origin", "v1.0.0").Run()
+					return nil
+				},
+			},
+		},
+	}
+
+	runner := NewRunner()
+	runner.DryRun = true
+	result := runner.Run(wf, NewContext(t.TempDir(), "v1.0.0"))
+
+	if !errors.Is(pushErr, proc.ErrDryRun) {
+		t.Fatalf("push in dry run = %v, want ErrDryRun", pushErr)
+	}
+	if !strings.Contains(result.Output, "Dry run blocked 1 command(s)") || !strings.Contains(result.Output, "git push origin v1.0.0") {
+		t.Errorf("expected the blocked command in the output:\n%s", result.Output)
+	}
+	if proc.IsDryRun() {
+		t.Error("dry-run mode outlived the workflow")
+	}
+}
+
 func TestRunnerRun_CompositeStep(t *testing.T) {
 	wf := &Workflow{
 		Name: "Test Workflow",
@@ -293,3 +416,50 @@ func TestWorkflowResultSummary(t *testing.T) {
 		t.Error("Summary should contain step names")
 	}
 }
+
+func TestWorkflowResultToJSON_CorrelationID(t *testing.T) {
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{Name: "Step 1", Type: StepTypeFunc, Func: func(ctx *Context) error { return nil }},
+		},
+	}
+
+	ctx := NewContext("/tmp", "v1.0.0")
+	if ctx.CorrelationID == "" {
+		t.Fatal("CorrelationID is empty, want generated ID")
+	}
+
+	result := NewRunner().Run(wf, ctx)
+	jsonResult := result.ToJSON()
+
+	if jsonResult.CorrelationID != ctx.CorrelationID {
+		t.Errorf("CorrelationID = %s, want %s", jsonResult.CorrelationID, ctx.CorrelationID)
+	}
+	if jsonResult.Timestamp == "" {
+		t.Error("Timestamp is empty, want RFC3339 time")
+	}
+}
+
+func TestRunnerRun_PrompterCorrelationID(t *testing.T) {
+	wf := &Workflow{
+		Name: "Test Workflow",
+		Steps: []Step{
+			{Name: "Confirm", Type: StepTypeFunc, Func: func(ctx *Context) error {
+				_, err := ctx.Prompter.Confirm("Continue?")
+				return err
+			}},
+		},
+	}
+
+	var out bytes.Buffer
+	ctx := NewContext("/tmp", "v1.0.0")
+	ctx.Prompter = interactive.NewJSONPrompter(&out, strings.NewReader(`{"question_id": "confirm", "confirmed": true}`+"\n"))
+
+	if result := NewRunner().Run(wf, ctx); !result.Success {
+		t.Fatalf("Run() failed: %+v", result.Steps)
+	}
+	if want := `"correlation_id": "` + ctx.CorrelationID + `"`; !strings.Contains(out.String(), want) {
+		t.Errorf("prompt = %s, want %s", out.String(), want)
+	}
+}
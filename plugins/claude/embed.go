@@ -0,0 +1,19 @@
+// Package claude provides embedded Claude Code agent, command, and skill files.
+package claude
+
+import "embed"
+
+// AgentFiles contains embedded Claude Code subagent markdown files.
+//
+//go:embed agents/*.md
+var AgentFiles embed.FS
+
+// CommandFiles contains embedded Claude Code slash command markdown files.
+//
+//go:embed commands/*.md
+var CommandFiles embed.FS
+
+// SkillFiles contains embedded Claude Code skill directories.
+//
+//go:embed skills/*/SKILL.md
+var SkillFiles embed.FS
@@ -0,0 +1,10 @@
+// Package gemini provides the embedded Gemini CLI extension.
+package gemini
+
+import "embed"
+
+// ExtensionFiles contains the embedded Gemini CLI extension: manifest,
+// context file, agent definitions, and commands.
+//
+//go:embed gemini-extension.json GEMINI.md *.toml commands/*.toml
+var ExtensionFiles embed.FS
@@ -5,10 +5,19 @@
 # It replaces the old plugins/generate/main.go approach.
 #
 # Usage:
-#   ./scripts/generate-plugins.sh
+#   ./scripts/generate-plugins.sh           # regenerate plugins/ in place
+#   ./scripts/generate-plugins.sh --check   # fail if plugins/ is out of date
+#
+# In --check mode the JSON specs must parse, the agent, command, and skill
+# specs must match assistantkit's schemas, the generated plugin manifests
+# must match assistantkit's plugin manifest schema, and the plugins generated
+# into a temporary directory must match the committed plugins/ tree. The script exits non-zero on any failure so CI can enforce
+# regeneration.
 #
 # Requirements:
 #   - assistantkit CLI (or go run from assistantkit source)
+#   - go, for --check: the files are validated by scripts/plugincheck against
+#     the schemas in the assistantkit module version pinned in go.mod
 
 set -euo pipefail
 
@@ -16,17 +25,82 @@ SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 PROJECT_ROOT="$(dirname "$SCRIPT_DIR")"
 ASSISTANTKIT_SRC="${PROJECT_ROOT}/../assistantkit"
 
-if command -v assistantkit &> /dev/null; then
-    assistantkit generate all \
-        --specs="${PROJECT_ROOT}/specs" \
-        --target=local \
-        --output="${PROJECT_ROOT}"
-elif [[ -d "$ASSISTANTKIT_SRC" ]]; then
-    (cd "$ASSISTANTKIT_SRC" && go run ./cmd/assistantkit generate all \
-        --specs="${PROJECT_ROOT}/specs" \
-        --target=local \
-        --output="${PROJECT_ROOT}")
-else
-    echo "Error: assistantkit not found. Install it or clone to ../assistantkit"
+CHECK=false
+case "${1:-}" in
+    "") ;;
+    --check) CHECK=true ;;
+    *)
+        echo "Usage: $0 [--check]"
+        exit 2
+        ;;
+esac
+
+generate() {
+    local output="$1"
+    if command -v assistantkit &> /dev/null; then
+        assistantkit generate all \
+            --specs="${PROJECT_ROOT}/specs" \
+            --target=local \
+            --output="${output}"
+    elif [[ -d "$ASSISTANTKIT_SRC" ]]; then
+        (cd "$ASSISTANTKIT_SRC" && go run ./cmd/assistantkit generate all \
+            --specs="${PROJECT_ROOT}/specs" \
+            --target=local \
+            --output="${output}")
+    else
+        echo "Error: assistantkit not found. Install it or clone to ../assistantkit"
+        exit 1
+    fi
+}
+
+if [[ "$CHECK" == false ]]; then
+    generate "${PROJECT_ROOT}"
+    exit 0
+fi
+
+TMP_DIR="$(mktemp -d)"
+trap 'rm -rf "$TMP_DIR"' EXIT
+
+plugincheck() {
+    (cd "$PROJECT_ROOT" && go run ./scripts/plugincheck "$@")
+}
+
+(cd "$PROJECT_ROOT" && go mod download github.com/plexusone/assistantkit)
+SCHEMA_DIR="$(cd "$PROJECT_ROOT" && go list -m -f '{{.Dir}}' github.com/plexusone/assistantkit)"
+
+# Validate the specs before generating: all JSON specs must parse, and the
+# agent, command, and skill specs must match their schemas.
+SPECS=()
+while IFS= read -r -d '' spec; do
+    SPECS+=("${spec#"${PROJECT_ROOT}/"}")
+done < <(find "${PROJECT_ROOT}/specs" -name '*.json' -print0)
+plugincheck "${SPECS[@]}"
+
+for kind in agent command skill; do
+    if ! (cd "$PROJECT_ROOT" && go run ./scripts/plugincheck \
+        -schema "${SCHEMA_DIR}/${kind}s/schema/${kind}.schema.json" specs/"${kind}"s/*.md); then
+        echo ""
+        echo "Error: specs/${kind}s/ doesn't match the ${kind} schema"
+        exit 1
+    fi
+done
+
+generate "${TMP_DIR}"
+
+# Validate the generated manifests against the plugin manifest schema.
+if ! plugincheck -schema "${SCHEMA_DIR}/plugins/schema/plugin.schema.json" \
+    "${TMP_DIR}/plugins/claude/.claude-plugin/plugin.json" \
+    "${TMP_DIR}/plugins/gemini/gemini-extension.json"; then
+    echo ""
+    echo "Error: generated plugin manifests don't match the plugin manifest schema. Fix specs/plugin.json"
     exit 1
 fi
+
+# embed.go files are hand-written Go packages, not generator output.
+if ! diff -ru -x embed.go "${PROJECT_ROOT}/plugins" "${TMP_DIR}/plugins"; then
+    echo ""
+    echo "Error: plugins/ is out of date with specs/. Run ./scripts/generate-plugins.sh"
+    exit 1
+fi
+
+echo "plugins/ is up to date with specs/"
@@ -0,0 +1,122 @@
+// Command plugincheck validates spec and plugin files for
+// scripts/generate-plugins.sh. Each file must parse and, with -schema, match
+// the JSON Schema, such as assistantkit's plugin manifest schema. Markdown
+// specs are checked as their YAML frontmatter, with the body as the
+// "instructions" field and command arguments expanded from the name
+// shorthand, the way assistantkit loads them.
+//
+// Usage:
+//
+//	go run ./scripts/plugincheck [-schema plugin.schema.json] file...
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plexusone/agent-team-release/pkg/jsonschema"
+)
+
+func main() {
+	schemaFile := flag.String("schema", "", "JSON Schema the files must match")
+	flag.Parse()
+
+	var schema *jsonschema.Schema
+	if *schemaFile != "" {
+		data, err := os.ReadFile(*schemaFile)
+		if err == nil {
+			schema, err = jsonschema.Compile(data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: schema %s: %v\n", *schemaFile, err)
+			os.Exit(2)
+		}
+	}
+
+	failed := false
+	for _, file := range flag.Args() {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+			continue
+		}
+		doc, err := decode(file, data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: can't parse %s: %v\n", file, err)
+			failed = true
+			continue
+		}
+		if schema == nil {
+			continue
+		}
+		for _, v := range schema.Validate(doc) {
+			fmt.Fprintf(os.Stderr, "Error: %s: %s\n", file, v)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// decode parses file as a JSON document. A Markdown file becomes its YAML
+// frontmatter fields plus the trimmed body as "instructions".
+func decode(file string, data []byte) (any, error) {
+	if filepath.Ext(file) != ".md" {
+		return jsonschema.Decode(data)
+	}
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return nil, errors.New("missing YAML frontmatter")
+	}
+	front, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return nil, errors.New("unterminated YAML frontmatter")
+	}
+	fields := map[string]any{}
+	if err := yaml.Unmarshal([]byte(front), &fields); err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+	if args, ok := fields["arguments"].([]any); ok {
+		fields["arguments"] = expandArguments(args)
+	}
+	if body = strings.TrimSpace(body); body != "" {
+		if _, ok := fields["instructions"]; !ok {
+			fields["instructions"] = body
+		}
+	}
+
+	// Round-trip through JSON so values have the types the schema expects
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("frontmatter: %w", err)
+	}
+	return jsonschema.Decode(data)
+}
+
+// expandArguments expands the command argument shorthand, a list of names
+// with a "?" suffix for optional ones, into argument objects as assistantkit
+// does.
+func expandArguments(args []any) []any {
+	expanded := make([]any, len(args))
+	for i, arg := range args {
+		name, ok := arg.(string)
+		if !ok {
+			expanded[i] = arg
+			continue
+		}
+		trimmed, optional := strings.CutSuffix(name, "?")
+		expanded[i] = map[string]any{"name": trimmed, "type": "string", "required": !optional}
+	}
+	return expanded
+}